@@ -10,20 +10,39 @@ import (
 	"time"
 
 	deliveryHTTP "github.com/frontandrew/gate/internal/delivery/http"
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/infrastructure/events"
 	"github.com/frontandrew/gate/internal/infrastructure/ml"
+	"github.com/frontandrew/gate/internal/infrastructure/occupancy"
+	"github.com/frontandrew/gate/internal/infrastructure/webhook"
 	"github.com/frontandrew/gate/internal/pkg/config"
 	"github.com/frontandrew/gate/internal/pkg/database"
+	"github.com/frontandrew/gate/internal/pkg/idempotency"
 	"github.com/frontandrew/gate/internal/pkg/jwt"
 	"github.com/frontandrew/gate/internal/pkg/logger"
-	"github.com/frontandrew/gate/internal/pkg/redis"
+	"github.com/frontandrew/gate/internal/pkg/metrics"
+	"github.com/frontandrew/gate/internal/pkg/ratelimit"
+	"github.com/frontandrew/gate/internal/pkg/server"
+	"github.com/frontandrew/gate/internal/pkg/worker"
 	"github.com/frontandrew/gate/internal/repository/cached"
 	"github.com/frontandrew/gate/internal/repository/postgres"
 	"github.com/frontandrew/gate/internal/usecase/access"
+	"github.com/frontandrew/gate/internal/usecase/audit"
 	"github.com/frontandrew/gate/internal/usecase/auth"
+	"github.com/frontandrew/gate/internal/usecase/blacklist"
+	"github.com/frontandrew/gate/internal/usecase/lists"
 	"github.com/frontandrew/gate/internal/usecase/pass"
+	"github.com/frontandrew/gate/internal/usecase/user"
 	"github.com/frontandrew/gate/internal/usecase/vehicle"
+	"github.com/frontandrew/gate/internal/usecase/whitelist"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 )
 
+// workerShutdownTimeout - сколько фоновые задачи могут доводить начатую работу после сигнала остановки
+const workerShutdownTimeout = 15 * time.Second
+
 func main() {
 	// =========================================================================
 	// Загрузка конфигурации
@@ -35,14 +54,26 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
 	// =========================================================================
 	// Инициализация logger
 	// =========================================================================
 
-	log := logger.New(cfg.Logger.Level, cfg.Logger.Format, cfg.Logger.Output)
+	log := logger.New(logger.Config{
+		Level:      cfg.Logger.Level,
+		Format:     cfg.Logger.Format,
+		Output:     cfg.Logger.Output,
+		MaxSizeMB:  cfg.Logger.MaxSizeMB,
+		MaxBackups: cfg.Logger.MaxBackups,
+		MaxAgeDays: cfg.Logger.MaxAgeDays,
+	})
 	log.Info("Starting GATE API server", map[string]interface{}{
 		"version": "1.0.0",
-		"env":     "development",
+		"env":     cfg.Env,
 	})
 
 	// =========================================================================
@@ -50,7 +81,11 @@ func main() {
 	// =========================================================================
 
 	ctx := context.Background()
-	db, err := database.Connect(ctx, &cfg.Database)
+
+	// Сигнал остановки прерывает ожидание БД при старте
+	connectCtx, stopConnect := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	db, err := database.Connect(connectCtx, log, &cfg.Database)
+	stopConnect()
 	if err != nil {
 		log.Fatal("Failed to connect to database", map[string]interface{}{
 			"error": err.Error(),
@@ -68,24 +103,33 @@ func main() {
 	// Подключение к Redis
 	// =========================================================================
 
-	redisClient, err := redis.NewClient(redis.Config{
-		Host:     cfg.Redis.Host,
-		Port:     cfg.Redis.Port,
-		Password: cfg.Redis.Password,
-		DB:       cfg.Redis.DB,
-	})
+	redisClient, err := connectRedis(cfg.Redis, log)
 	if err != nil {
 		log.Fatal("Failed to connect to Redis", map[string]interface{}{
 			"error": err.Error(),
 		})
 	}
-	defer redisClient.Close()
+	if redisClient != nil {
+		defer redisClient.Close()
 
-	log.Info("Connected to Redis", map[string]interface{}{
-		"host": cfg.Redis.Host,
-		"port": cfg.Redis.Port,
-		"db":   cfg.Redis.DB,
-	})
+		log.Info("Connected to Redis", map[string]interface{}{
+			"host": cfg.Redis.Host,
+			"port": cfg.Redis.Port,
+			"db":   cfg.Redis.DB,
+		})
+	}
+
+	// =========================================================================
+	// Создание Prometheus метрик
+	// =========================================================================
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	appMetrics := metrics.New(registry)
+	appMetrics.RegisterDBPool(func() database.PoolStats { return database.Stats(db) })
 
 	// =========================================================================
 	// Создание repositories
@@ -97,33 +141,108 @@ func main() {
 	passVehicleRepo := postgres.NewPassVehicleRepository(db)
 	accessLogRepo := postgres.NewAccessLogRepository(db)
 	refreshTokenRepo := postgres.NewRefreshTokenRepository(db)
+	auditLogRepo := postgres.NewAuditLogRepository(db)
+	unitOfWork := postgres.NewUnitOfWork(db)
+
+	// Кэшируемые репозитории (без Redis - напрямую в БД)
+	whitelistRepo, blacklistRepo := newListRepositories(
+		postgres.NewWhitelistRepository(db),
+		postgres.NewBlacklistRepository(db),
+		redisClient,
+		appMetrics,
+		log,
+	)
+
+	// Кэши ниже хранятся в Redis и без него отключаются
+	var unregisteredPlates access.UnregisteredPlateCache
+	var unregisteredInvalidator pass.UnregisteredPlateInvalidator
+	var recognitions access.RecognitionCache
+	var accessEvents access.EventPublishers
+	var occupancyCounter access.OccupancyCounter
+	var eventsHandler *deliveryHTTP.EventsHandler
+	if redisClient != nil {
+		// Троттлинг записи last_login_at для частых входов (сервисные аккаунты)
+		if cfg.Cache.LastLoginInterval > 0 {
+			userRepo = cached.NewUserRepository(userRepo, redisClient, log, cfg.Cache.LastLoginInterval)
+		}
 
-	// Кэшируемые репозитории
-	whitelistBaseRepo := postgres.NewWhitelistRepository(db)
-	blacklistBaseRepo := postgres.NewBlacklistRepository(db)
-	whitelistRepo := cached.NewWhitelistRepository(whitelistBaseRepo, redisClient)
-	blacklistRepo := cached.NewBlacklistRepository(blacklistBaseRepo, redisClient)
+		// Кэш отказов по незарегистрированным номерам; сбрасывается при создании автомобиля
+		// и после выдачи гостевого пропуска (автомобиль гостя создается в транзакции)
+		if cfg.Cache.UnregisteredPlateTTL > 0 {
+			unregisteredCache := cached.NewUnregisteredPlateCache(redisClient, appMetrics, log, cfg.Cache.UnregisteredPlateTTL)
+			vehicleRepo = cached.NewVehicleRepository(vehicleRepo, unregisteredCache)
+			unregisteredPlates = unregisteredCache
+			unregisteredInvalidator = unregisteredCache
+		}
+
+		// Кэш распознавания по хешу кадра: камера может присылать один и тот же кадр подряд
+		if cfg.Cache.RecognitionTTL > 0 {
+			recognitions = cached.NewRecognitionCache(redisClient, appMetrics, log, cfg.Cache.RecognitionTTL)
+		}
+
+		// Лента решений о доступе (SSE) через Redis pub/sub - события видны на всех экземплярах API
+		eventBroker := events.NewRedisBroker(redisClient, log)
+		accessEvents = append(accessEvents, eventBroker)
+		eventsHandler = deliveryHTTP.NewEventsHandler(eventBroker, log)
+
+		// Заполненность территории общая для всех экземпляров API
+		occupancyCounter = occupancy.NewRedisCounter(redisClient)
+	}
 
 	log.Info("Repositories initialized", map[string]interface{}{
-		"cached": "whitelist, blacklist",
+		"redis":               redisClient != nil,
+		"last_login_interval": cfg.Cache.LastLoginInterval.String(),
+		"unregistered_ttl":    cfg.Cache.UnregisteredPlateTTL.String(),
+		"recognition_ttl":     cfg.Cache.RecognitionTTL.String(),
 	})
 
+	if cfg.Cache.WarmOnStartup {
+		warmListCaches(ctx, whitelistRepo, blacklistRepo, cfg.Cache.WarmMaxEntries, log)
+	}
+
 	// =========================================================================
 	// Создание ML клиента
 	// =========================================================================
 
-	mlClient := ml.NewHTTPClient(cfg.ML.ServiceURL, cfg.ML.Timeout)
+	mlRetry := ml.RetryConfig{
+		MaxRetries:  cfg.ML.MaxRetries,
+		BaseBackoff: cfg.ML.RetryBackoff,
+	}
+
+	var mlClient ml.Client
+	mlAddress := cfg.ML.ServiceURL
+	if cfg.ML.Protocol == "grpc" {
+		mlConn, err := ml.DialGRPC(cfg.ML.GRPCAddress)
+		if err != nil {
+			log.Fatal("Failed to create ML gRPC client", map[string]interface{}{
+				"error":   err.Error(),
+				"address": cfg.ML.GRPCAddress,
+			})
+		}
+		defer mlConn.Close()
+
+		mlClient = ml.NewGRPCClient(mlConn, cfg.ML.Timeout, mlRetry)
+		mlAddress = cfg.ML.GRPCAddress
+	} else {
+		mlClient = ml.NewHTTPClient(cfg.ML.ServiceURL, cfg.ML.Timeout, mlRetry)
+	}
+	mlClient = ml.NewInstrumentedClient(mlClient, appMetrics)
 
 	// Проверяем доступность ML сервиса
-	if err := mlClient.Health(ctx); err != nil {
+	mlHealth, err := mlClient.Health(ctx)
+	if err != nil {
 		log.Warn("ML service is not available", map[string]interface{}{
-			"error": err.Error(),
-			"url":   cfg.ML.ServiceURL,
+			"error":    err.Error(),
+			"url":      mlAddress,
+			"protocol": cfg.ML.Protocol,
 		})
 		log.Warn("Access checks will fail until ML service is running")
 	} else {
 		log.Info("ML service is healthy", map[string]interface{}{
-			"url": cfg.ML.ServiceURL,
+			"url":           mlAddress,
+			"protocol":      cfg.ML.Protocol,
+			"version":       mlHealth.Version,
+			"model_version": mlHealth.ModelVersion,
 		})
 	}
 
@@ -131,33 +250,202 @@ func main() {
 	// Создание JWT token service
 	// =========================================================================
 
-	tokenService := jwt.NewTokenService(
-		cfg.JWT.SecretKey,
-		cfg.JWT.AccessExpiry,
-		cfg.JWT.RefreshExpiry,
-	)
+	tokenService, err := jwt.NewTokenServiceFromConfig(jwt.Config{
+		SigningMethod:  cfg.JWT.SigningMethod,
+		SecretKey:      cfg.JWT.SecretKey,
+		KeyID:          cfg.JWT.KeyID,
+		PrivateKeyFile: cfg.JWT.PrivateKeyFile,
+		PublicKeyFiles: cfg.JWT.PublicKeyFiles,
+		AccessExpiry:   cfg.JWT.AccessExpiry,
+		RefreshExpiry:  cfg.JWT.RefreshExpiry,
+	})
+	if err != nil {
+		log.Fatal("Failed to initialize JWT token service", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
 
-	log.Info("JWT token service initialized")
+	log.Info("JWT token service initialized", map[string]interface{}{
+		"signing_method": cfg.JWT.SigningMethod,
+	})
 
 	// =========================================================================
 	// Создание use case services
 	// =========================================================================
 
-	authService := auth.NewService(userRepo, refreshTokenRepo, tokenService, log)
-	vehicleService := vehicle.NewService(vehicleRepo, userRepo, log)
-	passService := pass.NewService(passRepo, passVehicleRepo, userRepo, vehicleRepo, log)
-	accessService := access.NewService(vehicleRepo, userRepo, passRepo, accessLogRepo, whitelistRepo, blacklistRepo, mlClient, log, cfg.ML.MinConfidence)
+	if !domain.IsPhoneRegionSupported(cfg.Auth.PhoneDefaultRegion) {
+		log.Fatal("Unsupported AUTH_PHONE_DEFAULT_REGION", map[string]interface{}{
+			"region": cfg.Auth.PhoneDefaultRegion,
+		})
+	}
+	authService := auth.NewService(userRepo, refreshTokenRepo, tokenService, redisClient, auth.NewLogNotifier(log), log, auth.Config{
+		PhoneDefaultRegion:  cfg.Auth.PhoneDefaultRegion,
+		SMSEnabled:          cfg.Auth.SMSEnabled,
+		DisableRegistration: !cfg.Auth.RegistrationOpen,
+		MaxLoginAttempts:    cfg.Auth.MaxLoginAttempts,
+		LockoutDuration:     cfg.Auth.LockoutDuration,
+		PasswordResetTTL:    cfg.Auth.PasswordResetTTL,
+	})
+	if cfg.Vehicle.PlateCountry != "" && !domain.IsPlateCountrySupported(cfg.Vehicle.PlateCountry) {
+		log.Fatal("Unsupported VEHICLE_PLATE_COUNTRY", map[string]interface{}{
+			"country": cfg.Vehicle.PlateCountry,
+		})
+	}
+	vehicleService := vehicle.NewService(vehicleRepo, userRepo, log, vehicle.Config{
+		PlateCountry: cfg.Vehicle.PlateCountry,
+	})
+	passService := pass.NewService(passRepo, passVehicleRepo, userRepo, vehicleRepo, unitOfWork, unregisteredInvalidator, log, pass.Config{
+		GuestDailyLimit:   cfg.Pass.GuestDailyLimit,
+		GuestPassDuration: cfg.Pass.GuestPassDuration,
+
+		RejectDuplicateVehicleLinks: cfg.Pass.RejectDuplicateVehicleLinks,
+		RejectOverlappingPasses:     cfg.Pass.RejectOverlappingPasses,
+
+		ExpiryInterval: cfg.Pass.ExpiryInterval,
+
+		PlateCountry: cfg.Vehicle.PlateCountry,
+	})
+	// Webhook'и о решениях доступа: доставка идет в фоне, проверка доступа ее не ждет
+	var webhooks *webhook.Dispatcher
+	if len(cfg.Webhook.URLs) > 0 {
+		webhooks = webhook.NewDispatcher(log, webhook.Config{
+			URLs:         cfg.Webhook.URLs,
+			Secret:       cfg.Webhook.Secret,
+			Timeout:      cfg.Webhook.Timeout,
+			MaxRetries:   cfg.Webhook.MaxRetries,
+			RetryBackoff: cfg.Webhook.RetryBackoff,
+			QueueSize:    cfg.Webhook.QueueSize,
+		})
+		accessEvents = append(accessEvents, webhooks)
+	}
+	accessService := access.NewService(vehicleRepo, userRepo, passRepo, accessLogRepo, whitelistRepo, blacklistRepo, mlClient, recognitions, unregisteredPlates, accessEvents, occupancyCounter, appMetrics, log, access.Config{
+		MinConfidence:         cfg.ML.MinConfidence,
+		ReviewThreshold:       cfg.Access.ReviewThreshold,
+		StrictDirection:       cfg.Access.StrictDirection,
+		DeniedSummaryInterval: cfg.Access.DeniedSummaryInterval,
+		Gates:                 cfg.Access.Gates,
+		StrictGates:           cfg.Access.StrictGates,
+		ObserveOnlyGates:      cfg.Access.ObserveOnlyGates,
+		GateZones:             cfg.Access.GateZones,
+		MaxFrameAge:           cfg.Access.MaxFrameAge,
+		RejectStaleFrames:     cfg.Access.RejectStaleFrames,
+		AntiPassback:          cfg.Access.AntiPassback,
+		InferDirectionGates:   cfg.Access.InferDirectionGates,
+		MaxOccupancy:          cfg.Access.MaxOccupancy,
+		WhitelistFailClosed:   cfg.Access.WhitelistFailClosed,
+		BlacklistFailClosed:   cfg.Access.BlacklistFailClosed,
+	})
+
+	// Владелец автомобилей-заглушек для белого списка (пустое значение - только owner_id из запроса)
+	var placeholderOwnerID uuid.UUID
+	if cfg.Whitelist.PlaceholderOwnerID != "" {
+		placeholderOwnerID, err = uuid.Parse(cfg.Whitelist.PlaceholderOwnerID)
+		if err != nil {
+			log.Fatal("Invalid WHITELIST_PLACEHOLDER_OWNER_ID", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	}
+	auditService := audit.NewService(auditLogRepo, log)
+	blacklistService := blacklist.NewService(blacklistRepo, auditService, log, blacklist.Config{
+		PlateCountry: cfg.Vehicle.PlateCountry,
+	})
+	listsService := lists.NewService(whitelistRepo, blacklistRepo, log, lists.Config{
+		PlateCountry: cfg.Vehicle.PlateCountry,
+	})
+	userService := user.NewService(userRepo, unitOfWork, authService, log)
+	whitelistService := whitelist.NewService(whitelistRepo, vehicleRepo, log, whitelist.Config{
+		AutoCreateVehicle:  cfg.Whitelist.AutoCreateVehicle,
+		PlaceholderOwnerID: placeholderOwnerID,
+		PlateCountry:       cfg.Vehicle.PlateCountry,
+	})
+
+	// Начальная версия модели, чтобы первые логи доступа уже содержали ее
+	if mlHealth != nil {
+		accessService.ObserveMLVersion(mlHealth.ModelVersion)
+	}
 
 	log.Info("Use case services initialized")
 
+	// Фоновые задачи: сводка причин отказов (если включена), деактивация истекших пропусков, доставка webhook'ов
+	// При остановке они завершаются после HTTP сервера и до закрытия БД и Redis
+	workers := worker.NewGroup(ctx)
+	workers.Go("denied-reason-summary", accessService.RunDeniedReasonSummary)
+	workers.Go("pass-expiry", passService.RunExpiryWorker)
+	if webhooks != nil {
+		workers.Go("webhooks", webhooks.Run)
+	}
+
+	// =========================================================================
+	// Создание rate limiter'ов
+	// =========================================================================
+
+	// Счетчики limiter'ов хранятся в Redis: без него ограничение частоты отключается
+	var rateLimiters deliveryHTTP.RateLimiters
+	if cfg.RateLimit.Enabled && redisClient != nil {
+		rateLimiters = deliveryHTTP.RateLimiters{
+			AccessCheck: ratelimit.NewLimiter(redisClient, "ratelimit:access:", ratelimit.Config{
+				Requests: cfg.RateLimit.AccessCheckRate,
+				Burst:    cfg.RateLimit.AccessCheckBurst,
+				Window:   cfg.RateLimit.AccessCheckWindow,
+			}),
+			Login: ratelimit.NewLimiter(redisClient, "ratelimit:login:", ratelimit.Config{
+				Requests: cfg.RateLimit.LoginRate,
+				Window:   cfg.RateLimit.LoginWindow,
+			}),
+			Register: ratelimit.NewLimiter(redisClient, "ratelimit:register:", ratelimit.Config{
+				Requests: cfg.RateLimit.RegisterRate,
+				Window:   cfg.RateLimit.RegisterWindow,
+			}),
+			ForgotPassword: ratelimit.NewLimiter(redisClient, "ratelimit:forgot-password:", ratelimit.Config{
+				Requests: cfg.RateLimit.ForgotPasswordRate,
+				Window:   cfg.RateLimit.ForgotPasswordWindow,
+			}),
+			ResetPassword: ratelimit.NewLimiter(redisClient, "ratelimit:reset-password:", ratelimit.Config{
+				Requests: cfg.RateLimit.ResetPasswordRate,
+				Window:   cfg.RateLimit.ResetPasswordWindow,
+			}),
+		}
+	}
+
+	// Ответы для повторов по Idempotency-Key тоже хранятся в Redis
+	var idempotencyStore *idempotency.Store
+	if cfg.Idempotency.TTL > 0 && redisClient != nil {
+		idempotencyStore = idempotency.NewStore(redisClient, "idempotency:", idempotency.Config{
+			TTL: cfg.Idempotency.TTL,
+		})
+	}
+
 	// =========================================================================
 	// Создание HTTP handlers
 	// =========================================================================
 
 	authHandler := deliveryHTTP.NewAuthHandler(authService, log)
 	vehicleHandler := deliveryHTTP.NewVehicleHandler(vehicleService, log)
-	passHandler := deliveryHTTP.NewPassHandler(passService, log)
+	passHandler := deliveryHTTP.NewPassHandler(passService, log, deliveryHTTP.PassHandlerConfig{
+		RequireValidFrom: cfg.Pass.RequireValidFrom,
+	})
 	accessHandler := deliveryHTTP.NewAccessHandler(accessService, log)
+	whitelistHandler := deliveryHTTP.NewWhitelistHandler(whitelistService, log)
+	blacklistHandler := deliveryHTTP.NewBlacklistHandler(blacklistService, log)
+	auditHandler := deliveryHTTP.NewAuditHandler(auditService, log)
+	listsHandler := deliveryHTTP.NewListsHandler(listsService, log)
+	userHandler := deliveryHTTP.NewUserHandler(userService, log)
+
+	// Зависимости для /health/ready; Redis проверяется, только если он подключен
+	healthChecks := []deliveryHTTP.HealthCheck{
+		{Name: "postgres", Check: db.Ping, Stats: func() interface{} { return database.Stats(db) }},
+		{Name: "ml", Check: func(ctx context.Context) error {
+			_, err := mlClient.Health(ctx)
+			return err
+		}},
+	}
+	if redisClient != nil {
+		healthChecks = append(healthChecks, deliveryHTTP.HealthCheck{Name: "redis", Check: redisClient.Ping})
+	}
+	healthHandler := deliveryHTTP.NewHealthHandler(healthChecks, log, deliveryHTTP.HealthHandlerConfig{
+		Timeout: cfg.Server.HealthCheckTimeout,
+	})
 
 	log.Info("HTTP handlers initialized")
 
@@ -170,7 +458,18 @@ func main() {
 		authHandler,
 		vehicleHandler,
 		passHandler,
+		whitelistHandler,
+		blacklistHandler,
+		auditHandler,
+		listsHandler,
+		userHandler,
+		eventsHandler,
+		healthHandler,
 		tokenService,
+		authService,
+		rateLimiters,
+		idempotencyStore,
+		appMetrics,
 		cfg,
 		log,
 	)
@@ -191,6 +490,28 @@ func main() {
 		IdleTimeout:  cfg.Server.IdleTimeout,
 	}
 
+	if cfg.Server.TLSEnabled() {
+		srv.TLSConfig = server.NewTLSConfig(&cfg.Server)
+	}
+
+	// Shutdown не отменяет контекст запросов: открытые SSE потоки закрываются отдельно,
+	// иначе каждая остановка ждала бы их до таймаута
+	if eventsHandler != nil {
+		srv.RegisterOnShutdown(eventsHandler.Shutdown)
+	}
+
+	// Дополнительный HTTP listener, перенаправляющий на HTTPS
+	var redirectSrv *http.Server
+	if cfg.Server.TLSEnabled() && cfg.Server.HTTPRedirectPort != "" {
+		redirectSrv = &http.Server{
+			Addr:         cfg.Server.RedirectAddress(),
+			Handler:      server.RedirectToHTTPS(cfg.Server.Port),
+			ReadTimeout:  cfg.Server.ReadTimeout,
+			WriteTimeout: cfg.Server.WriteTimeout,
+			IdleTimeout:  cfg.Server.IdleTimeout,
+		}
+	}
+
 	// =========================================================================
 	// Запуск сервера в goroutine
 	// =========================================================================
@@ -200,10 +521,26 @@ func main() {
 	go func() {
 		log.Info("API server listening", map[string]interface{}{
 			"address": srv.Addr,
+			"tls":     cfg.Server.TLSEnabled(),
 		})
+		if cfg.Server.TLSEnabled() {
+			serverErrors <- srv.ListenAndServeTLS(cfg.Server.TLSCertFile, cfg.Server.TLSKeyFile)
+			return
+		}
 		serverErrors <- srv.ListenAndServe()
 	}()
 
+	if redirectSrv != nil {
+		go func() {
+			log.Info("HTTP to HTTPS redirect listening", map[string]interface{}{
+				"address": redirectSrv.Addr,
+			})
+			if err := redirectSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				serverErrors <- err
+			}
+		}()
+	}
+
 	// =========================================================================
 	// Graceful shutdown
 	// =========================================================================
@@ -228,6 +565,10 @@ func main() {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
+		if redirectSrv != nil {
+			_ = redirectSrv.Shutdown(ctx)
+		}
+
 		if err := srv.Shutdown(ctx); err != nil {
 			log.Error("Graceful shutdown failed", map[string]interface{}{
 				"error": err.Error(),
@@ -241,6 +582,20 @@ func main() {
 			}
 		}
 
+		// Задачи останавливаются после сервера: запросы, завершенные при shutdown, еще могли поставить webhook'и -
+		// диспетчер доставляет очередь до конца, но не дольше workerShutdownTimeout.
+		// Отдельный таймаут - чтобы долгий shutdown сервера (например, медленные запросы) не съел время задач
+		workersCtx, cancelWorkers := context.WithTimeout(context.Background(), workerShutdownTimeout)
+		defer cancelWorkers()
+
+		if err := workers.Shutdown(workersCtx); err != nil {
+			log.Error("Background workers did not stop", map[string]interface{}{
+				"error": err.Error(),
+			})
+		} else {
+			log.Info("Background workers stopped")
+		}
+
 		log.Info("Server stopped gracefully")
 	}
 }
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/frontandrew/gate/internal/pkg/config"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/pkg/metrics"
+	"github.com/frontandrew/gate/internal/pkg/redis"
+	"github.com/frontandrew/gate/internal/repository"
+	"github.com/frontandrew/gate/internal/repository/cached"
+)
+
+// connectRedis подключается к Redis
+// Если Redis недоступен и не обязателен (REDIS_REQUIRED=false), возвращает nil без ошибки:
+// сервис стартует без кэшей, rate limiting, блокировки входа и отзыва access токенов
+func connectRedis(cfg config.RedisConfig, log logger.Logger) (*redis.Client, error) {
+	client, err := redis.NewClient(redis.Config{
+		Host:     cfg.Host,
+		Port:     cfg.Port,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+	if err == nil {
+		return client, nil
+	}
+
+	if cfg.Required {
+		return nil, fmt.Errorf("redis is required: %w", err)
+	}
+
+	log.Warn("Redis is unavailable, starting without caches and rate limiting", map[string]interface{}{
+		"host":  cfg.Host,
+		"port":  cfg.Port,
+		"error": err.Error(),
+	})
+	return nil, nil
+}
+
+// newListRepositories оборачивает репозитории белого и черного списков кэшем Redis
+// Без Redis (redisClient == nil) проверки списков идут напрямую в БД
+func newListRepositories(
+	whitelist repository.WhitelistRepository,
+	blacklist repository.BlacklistRepository,
+	redisClient *redis.Client,
+	appMetrics *metrics.Metrics,
+	log logger.Logger,
+) (repository.WhitelistRepository, repository.BlacklistRepository) {
+	if redisClient == nil {
+		return whitelist, blacklist
+	}
+
+	return cached.NewWhitelistRepository(whitelist, redisClient, appMetrics, log),
+		cached.NewBlacklistRepository(blacklist, redisClient, appMetrics, log)
+}
+
+// cacheWarmer - кэшируемый репозиторий, который можно прогреть при старте
+type cacheWarmer interface {
+	Warm(ctx context.Context, maxEntries int) (int, error)
+}
+
+// warmListCaches прогревает кэш списков, чтобы первые проверки после рестарта не шли в БД
+// Без Redis репозитории не кэшируются и прогрев пропускается
+func warmListCaches(
+	ctx context.Context,
+	whitelist repository.WhitelistRepository,
+	blacklist repository.BlacklistRepository,
+	maxEntries int,
+	log logger.Logger,
+) {
+	whitelistWarmer, ok := whitelist.(cacheWarmer)
+	if !ok {
+		return
+	}
+	blacklistWarmer, ok := blacklist.(cacheWarmer)
+	if !ok {
+		return
+	}
+
+	warmedWhitelist, err := whitelistWarmer.Warm(ctx, maxEntries)
+	if err != nil {
+		log.Warn("Failed to warm whitelist cache", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	warmedBlacklist, err := blacklistWarmer.Warm(ctx, maxEntries)
+	if err != nil {
+		log.Warn("Failed to warm blacklist cache", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	log.Info("List caches warmed", map[string]interface{}{
+		"whitelist": warmedWhitelist,
+		"blacklist": warmedBlacklist,
+	})
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/frontandrew/gate/internal/pkg/config"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/repository/cached"
+	"github.com/frontandrew/gate/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// redisConfig возвращает настройки подключения к адресу host:port
+func redisConfig(t *testing.T, addr string, required bool) config.RedisConfig {
+	t.Helper()
+
+	host, port, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+	return config.RedisConfig{Host: host, Port: port, Required: required}
+}
+
+// unavailableAddr возвращает адрес, на котором никто не слушает
+func unavailableAddr(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	return addr
+}
+
+func TestListRepositories_WithRedis(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	redisClient, err := connectRedis(redisConfig(t, mr.Addr(), true), logger.NewNoop())
+	require.NoError(t, err)
+	require.NotNil(t, redisClient)
+	t.Cleanup(func() { _ = redisClient.Close() })
+
+	whitelistBase := new(mocks.MockWhitelistRepository)
+	whitelistBase.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(true, "Скорая помощь", nil).Once()
+	blacklistBase := new(mocks.MockBlacklistRepository)
+	blacklistBase.On("IsBlacklisted", mock.Anything, "A123BC777", "").Return(false, "", nil).Once()
+
+	whitelist, blacklist := newListRepositories(whitelistBase, blacklistBase, redisClient, nil, logger.NewNoop())
+	assert.IsType(t, &cached.WhitelistRepository{}, whitelist)
+	assert.IsType(t, &cached.BlacklistRepository{}, blacklist)
+
+	// Повторные проверки обслуживаются из Redis - в БД только первый запрос
+	for i := 0; i < 3; i++ {
+		inWhitelist, _, err := whitelist.IsWhitelisted(context.Background(), "A123BC777", "")
+		require.NoError(t, err)
+		assert.True(t, inWhitelist)
+
+		inBlacklist, _, err := blacklist.IsBlacklisted(context.Background(), "A123BC777", "")
+		require.NoError(t, err)
+		assert.False(t, inBlacklist)
+	}
+
+	assert.True(t, mr.Exists("whitelist:A123BC777"))
+	whitelistBase.AssertExpectations(t)
+	blacklistBase.AssertExpectations(t)
+}
+
+func TestListRepositories_RedisUnavailable(t *testing.T) {
+	redisClient, err := connectRedis(redisConfig(t, unavailableAddr(t), false), logger.NewNoop())
+	require.NoError(t, err, "необязательный Redis не мешает старту")
+	require.Nil(t, redisClient)
+
+	whitelistBase := new(mocks.MockWhitelistRepository)
+	whitelistBase.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(true, "Скорая помощь", nil).Times(2)
+	blacklistBase := new(mocks.MockBlacklistRepository)
+
+	whitelist, blacklist := newListRepositories(whitelistBase, blacklistBase, redisClient, nil, logger.NewNoop())
+	assert.Same(t, whitelistBase, whitelist)
+	assert.Same(t, blacklistBase, blacklist)
+
+	// Без кэша каждая проверка идет в БД
+	for i := 0; i < 2; i++ {
+		_, _, err := whitelist.IsWhitelisted(context.Background(), "A123BC777", "")
+		require.NoError(t, err)
+	}
+
+	// Прогрев без кэша пропускается, а не падает на отсутствии Warm
+	warmListCaches(context.Background(), whitelist, blacklist, 100, logger.NewNoop())
+
+	whitelistBase.AssertExpectations(t)
+}
+
+func TestConnectRedis_Required(t *testing.T) {
+	redisClient, err := connectRedis(redisConfig(t, unavailableAddr(t), true), logger.NewNoop())
+
+	assert.Error(t, err)
+	assert.Nil(t, redisClient)
+}
@@ -1,24 +1,47 @@
 package http
 
 import (
-	"encoding/json"
+	"context"
+	"encoding/csv"
+	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/frontandrew/gate/internal/delivery/http/middleware"
+	"github.com/frontandrew/gate/internal/domain"
 	"github.com/frontandrew/gate/internal/pkg/logger"
 	"github.com/frontandrew/gate/internal/usecase/access"
 	"github.com/google/uuid"
 )
 
+// AccessService определяет интерфейс для сервиса проверки доступа
+type AccessService interface {
+	CheckAccess(ctx context.Context, req *access.CheckAccessRequest) (*access.CheckAccessResponse, error)
+	GetAccessLogs(ctx context.Context, userID *uuid.UUID, limit, offset int) ([]*domain.AccessLog, error)
+	GetAccessLog(ctx context.Context, id uuid.UUID) (*domain.AccessLog, error)
+	LoadRelations(ctx context.Context, logs []*domain.AccessLog, include access.LogInclude) error
+	GetAccessLogsByVehicle(ctx context.Context, vehicleID uuid.UUID, limit, offset int) ([]*domain.AccessLog, error)
+	SearchAccessLogs(ctx context.Context, filter domain.AccessLogFilter, limit, offset int) ([]*domain.AccessLog, error)
+	CountAccessLogs(ctx context.Context, filter domain.AccessLogFilter) (int, error)
+	ExportAccessLogs(ctx context.Context, filter domain.AccessLogFilter, fn func(*domain.AccessLog) error) error
+	GetStats(ctx context.Context, from, to time.Time) (*domain.AccessStats, error)
+	DeniedReasonCounts() map[string]int64
+	SimulateAccess(ctx context.Context, req *access.SimulateAccessRequest) (*access.SimulateAccessResponse, error)
+	ManualAccess(ctx context.Context, req *access.ManualAccessRequest, operatorID uuid.UUID) (*access.CheckAccessResponse, error)
+	Occupancy(ctx context.Context) (*access.OccupancyResponse, error)
+}
+
 // AccessHandler обрабатывает запросы связанные с проверкой доступа
 type AccessHandler struct {
-	accessService *access.Service
+	accessService AccessService
 	logger        logger.Logger
 }
 
 // NewAccessHandler создает новый handler
-func NewAccessHandler(accessService *access.Service, logger logger.Logger) *AccessHandler {
+func NewAccessHandler(accessService AccessService, logger logger.Logger) *AccessHandler {
 	return &AccessHandler{
 		accessService: accessService,
 		logger:        logger,
@@ -29,21 +52,54 @@ func NewAccessHandler(accessService *access.Service, logger logger.Logger) *Acce
 // POST /api/v1/access/check
 func (h *AccessHandler) CheckAccess(w http.ResponseWriter, r *http.Request) {
 	var req access.CheckAccessRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Error("Failed to decode request", map[string]interface{}{
-			"error": err.Error(),
-		})
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if !validateRequest(w, &req) {
 		return
 	}
 
 	// Проверяем доступ
 	response, err := h.accessService.CheckAccess(r.Context(), &req)
 	if err != nil {
-		h.logger.Error("Failed to check access", map[string]interface{}{
-			"error": err.Error(),
-		})
-		respondError(w, http.StatusInternalServerError, "Failed to check access")
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to check access", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to check access")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// ManualAccess принимает решение по номеру, подтвержденному охранником, когда распознавание не сработало
+// POST /api/v1/access/manual
+func (h *AccessHandler) ManualAccess(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserClaims(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req access.ManualAccessRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	response, err := h.accessService.ManualAccess(r.Context(), &req, claims.UserID)
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to check access manually", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to check access")
+		}
 		return
 	}
 
@@ -53,30 +109,83 @@ func (h *AccessHandler) CheckAccess(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// SimulateAccess прогоняет решение о доступе для заданного номера без распознавания и логирования
+// POST /api/v1/admin/access/simulate
+func (h *AccessHandler) SimulateAccess(w http.ResponseWriter, r *http.Request) {
+	var req access.SimulateAccessRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	result, err := h.accessService.SimulateAccess(r.Context(), &req)
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to simulate access check", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to simulate access check")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    result,
+	})
+}
+
 // GetAccessLogs возвращает историю проездов
-// GET /api/v1/access/logs
+// GET /api/v1/access/logs?user_id=&vehicle_id=&gate_id=&direction=&access_granted=&reason_code=&from=&to=[&include=user,vehicle]
 func (h *AccessHandler) GetAccessLogs(w http.ResponseWriter, r *http.Request) {
 	// Получаем параметры пагинации
 	limit, offset := getPaginationParams(r)
+	query := r.URL.Query()
 
-	// Получаем user_id из query params (опционально)
-	var userID *uuid.UUID
-	if userIDStr := r.URL.Query().Get("user_id"); userIDStr != "" {
-		parsedID, err := uuid.Parse(userIDStr)
-		if err != nil {
-			respondError(w, http.StatusBadRequest, "Invalid user_id")
+	filter, errMessage := parseAccessLogFilter(query)
+	if errMessage != "" {
+		respondError(w, http.StatusBadRequest, errMessage)
+		return
+	}
+
+	include, ok := parseAccessLogInclude(w, r)
+	if !ok {
+		return
+	}
+
+	// Получаем логи
+	var logs []*domain.AccessLog
+	var err error
+	// Без фильтров или только по пользователю - простая выборка по индексу
+	if filter != (domain.AccessLogFilter{UserID: filter.UserID}) {
+		logs, err = h.accessService.SearchAccessLogs(r.Context(), filter, limit, offset)
+	} else {
+		logs, err = h.accessService.GetAccessLogs(r.Context(), filter.UserID, limit, offset)
+	}
+	if err == nil && include != (access.LogInclude{}) {
+		err = h.accessService.LoadRelations(r.Context(), logs, include)
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidDateRange) {
+			respondDomainErrorMessage(w, err, "Invalid date range: from must be before to")
 			return
 		}
-		userID = &parsedID
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to get access logs", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to get access logs")
+		}
+		return
 	}
 
-	// Получаем логи
-	logs, err := h.accessService.GetAccessLogs(r.Context(), userID, limit, offset)
+	total, err := h.accessService.CountAccessLogs(r.Context(), filter)
 	if err != nil {
-		h.logger.Error("Failed to get access logs", map[string]interface{}{
-			"error": err.Error(),
-		})
-		respondError(w, http.StatusInternalServerError, "Failed to get access logs")
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to count access logs", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to get access logs")
+		}
 		return
 	}
 
@@ -86,12 +195,39 @@ func (h *AccessHandler) GetAccessLogs(w http.ResponseWriter, r *http.Request) {
 		"pagination": map[string]int{
 			"limit":  limit,
 			"offset": offset,
+			"total":  total,
 		},
 	})
 }
 
+// GetAccessLog возвращает запись журнала с изображением, пользователем и автомобилем
+// GET /api/v1/access/logs/:id
+func (h *AccessHandler) GetAccessLog(w http.ResponseWriter, r *http.Request) {
+	logID, err := uuid.Parse(getPathParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid access log ID")
+		return
+	}
+
+	accessLog, err := h.accessService.GetAccessLog(r.Context(), logID)
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to get access log", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to get access log")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    accessLog,
+	})
+}
+
 // GetVehicleAccessLogs возвращает историю проездов автомобиля
-// GET /api/v1/access/logs/vehicle/:id
+// GET /api/v1/access/logs/vehicle/:id[?include=user,vehicle]
 func (h *AccessHandler) GetVehicleAccessLogs(w http.ResponseWriter, r *http.Request) {
 	// Извлекаем vehicle_id из URL
 	vehicleIDStr := getPathParam(r, "id")
@@ -103,12 +239,22 @@ func (h *AccessHandler) GetVehicleAccessLogs(w http.ResponseWriter, r *http.Requ
 
 	limit, offset := getPaginationParams(r)
 
+	include, ok := parseAccessLogInclude(w, r)
+	if !ok {
+		return
+	}
+
 	logs, err := h.accessService.GetAccessLogsByVehicle(r.Context(), vehicleID, limit, offset)
+	if err == nil && include != (access.LogInclude{}) {
+		err = h.accessService.LoadRelations(r.Context(), logs, include)
+	}
 	if err != nil {
-		h.logger.Error("Failed to get vehicle access logs", map[string]interface{}{
-			"error": err.Error(),
-		})
-		respondError(w, http.StatusInternalServerError, "Failed to get vehicle access logs")
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to get vehicle access logs", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to get vehicle access logs")
+		}
 		return
 	}
 
@@ -119,7 +265,7 @@ func (h *AccessHandler) GetVehicleAccessLogs(w http.ResponseWriter, r *http.Requ
 }
 
 // GetMyAccessLogs возвращает историю проездов текущего пользователя
-// GET /api/v1/access/me/logs
+// GET /api/v1/access/me/logs[?include=user,vehicle]
 func (h *AccessHandler) GetMyAccessLogs(w http.ResponseWriter, r *http.Request) {
 	// Получаем пользователя из контекста
 	claims, ok := middleware.GetUserClaims(r.Context())
@@ -130,31 +276,269 @@ func (h *AccessHandler) GetMyAccessLogs(w http.ResponseWriter, r *http.Request)
 
 	limit, offset := getPaginationParams(r)
 
+	include, ok := parseAccessLogInclude(w, r)
+	if !ok {
+		return
+	}
+
 	logs, err := h.accessService.GetAccessLogs(r.Context(), &claims.UserID, limit, offset)
+	if err == nil && include != (access.LogInclude{}) {
+		err = h.accessService.LoadRelations(r.Context(), logs, include)
+	}
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to get user access logs", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to get access logs")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    logs,
+	})
+}
+
+// parseAccessLogInclude разбирает параметр include (через запятую: user, vehicle): связанные данные
+// загружаются только по запросу. При неизвестном значении отвечает 400 и возвращает false
+func parseAccessLogInclude(w http.ResponseWriter, r *http.Request) (access.LogInclude, bool) {
+	var include access.LogInclude
+
+	value := r.URL.Query().Get("include")
+	if value == "" {
+		return include, true
+	}
+
+	for _, part := range strings.Split(value, ",") {
+		switch strings.TrimSpace(part) {
+		case "user":
+			include.User = true
+		case "vehicle":
+			include.Vehicle = true
+		default:
+			respondFieldError(w, http.StatusBadRequest, "include", "Invalid include: expected user, vehicle")
+			return include, false
+		}
+	}
+	return include, true
+}
+
+// maxGateIDLength - длина колонки gate_id в access_logs
+const maxGateIDLength = 50
+
+// parseAccessLogFilter разбирает фильтры user_id, vehicle_id, gate_id, direction, access_granted, reason_code, from, to
+// Возвращает текст ошибки для ответа 400 (пусто - фильтр корректен)
+func parseAccessLogFilter(query url.Values) (domain.AccessLogFilter, string) {
+	var filter domain.AccessLogFilter
+
+	if userIDStr := query.Get("user_id"); userIDStr != "" {
+		parsedID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return filter, "Invalid user_id"
+		}
+		filter.UserID = &parsedID
+	}
+
+	if vehicleIDStr := query.Get("vehicle_id"); vehicleIDStr != "" {
+		parsedID, err := uuid.Parse(vehicleIDStr)
+		if err != nil {
+			return filter, "Invalid vehicle_id"
+		}
+		filter.VehicleID = &parsedID
+	}
+
+	filter.GateID = strings.TrimSpace(query.Get("gate_id"))
+	if len(filter.GateID) > maxGateIDLength {
+		return filter, "Invalid gate_id"
+	}
+
+	if directionStr := query.Get("direction"); directionStr != "" {
+		direction, err := domain.ParseDirection(directionStr)
+		if err != nil {
+			return filter, "Invalid direction: expected IN or OUT"
+		}
+		filter.Direction = direction
+	}
+
+	if grantedStr := query.Get("access_granted"); grantedStr != "" {
+		granted, err := strconv.ParseBool(grantedStr)
+		if err != nil {
+			return filter, "Invalid access_granted: expected true or false"
+		}
+		filter.AccessGranted = &granted
+	}
+
+	if reasonCodeStr := query.Get("reason_code"); reasonCodeStr != "" {
+		code, err := access.ParseReasonCode(reasonCodeStr)
+		if err != nil {
+			return filter, "Invalid reason_code"
+		}
+		filter.ReasonCode = string(code)
+	}
+
+	var err error
+	if filter.From, err = parseTimeParam(query.Get("from"), false); err != nil {
+		return filter, "Invalid from: expected RFC3339 or YYYY-MM-DD"
+	}
+	if filter.To, err = parseTimeParam(query.Get("to"), true); err != nil {
+		return filter, "Invalid to: expected RFC3339 or YYYY-MM-DD"
+	}
+
+	return filter, ""
+}
+
+// exportFlushEvery - через сколько строк CSV сбрасывать буфер клиенту
+const exportFlushEvery = 100
+
+// ExportAccessLogs выгружает историю проездов в CSV потоком, без загрузки выборки в память
+// GET /api/v1/access/logs/export?format=csv (фильтры как у GET /api/v1/access/logs)
+func (h *AccessHandler) ExportAccessLogs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	if format := query.Get("format"); format != "" && format != "csv" {
+		respondError(w, http.StatusBadRequest, "Invalid format: expected csv")
+		return
+	}
+
+	filter, errMessage := parseAccessLogFilter(query)
+	if errMessage != "" {
+		respondError(w, http.StatusBadRequest, errMessage)
+		return
+	}
+
+	cw := csv.NewWriter(w)
+	flusher, _ := w.(http.Flusher)
+	started := false
+	rows := 0
+
+	// Заголовки отправляются при первой строке, чтобы ошибки до начала выборки вернулись обычным ответом
+	start := func() {
+		if started {
+			return
+		}
+		started = true
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="access-logs-`+time.Now().UTC().Format("20060102-150405")+`.csv"`)
+		w.WriteHeader(http.StatusOK)
+		_ = cw.Write([]string{"timestamp", "license_plate", "access_granted", "reason", "gate_id", "direction", "confidence"})
+	}
+
+	err := h.accessService.ExportAccessLogs(r.Context(), filter, func(log *domain.AccessLog) error {
+		start()
+		if err := cw.Write([]string{
+			log.Timestamp.UTC().Format(time.RFC3339),
+			log.LicensePlate,
+			strconv.FormatBool(log.AccessGranted),
+			log.AccessReason,
+			log.GateID,
+			string(log.Direction),
+			strconv.FormatFloat(log.RecognitionConfidence, 'f', 2, 64),
+		}); err != nil {
+			return err
+		}
+
+		rows++
+		if rows%exportFlushEvery == 0 {
+			cw.Flush()
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		return cw.Error()
+	})
+	if err != nil && !started {
+		if errors.Is(err, domain.ErrInvalidDateRange) {
+			respondDomainErrorMessage(w, err, "Invalid date range: from must be before to")
+			return
+		}
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to export access logs", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to export access logs")
+		}
+		return
+	}
 	if err != nil {
-		h.logger.Error("Failed to get user access logs", map[string]interface{}{
+		// Ответ уже начат - статус не изменить, клиент получит обрезанный файл
+		requestLogger(r, h.logger).Error("Access logs export interrupted", map[string]interface{}{
 			"error": err.Error(),
+			"rows":  rows,
 		})
-		respondError(w, http.StatusInternalServerError, "Failed to get access logs")
+		return
+	}
+
+	start()
+	cw.Flush()
+}
+
+// defaultStatsPeriod - период статистики, если from/to не заданы
+const defaultStatsPeriod = 24 * time.Hour
+
+// GetStats возвращает статистику проездов за период (по умолчанию - последние сутки)
+// GET /api/v1/access/stats?from=&to=
+func (h *AccessHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	from, err := parseTimeParam(query.Get("from"), false)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid from: expected RFC3339 or YYYY-MM-DD")
+		return
+	}
+	to, err := parseTimeParam(query.Get("to"), true)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid to: expected RFC3339 or YYYY-MM-DD")
+		return
+	}
+
+	// Незаданная граница отсчитывается от заданной (или от текущего момента)
+	if to == nil {
+		now := time.Now()
+		to = &now
+	}
+	if from == nil {
+		start := to.Add(-defaultStatsPeriod)
+		from = &start
+	}
+
+	stats, err := h.accessService.GetStats(r.Context(), *from, *to)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidDateRange) {
+			respondDomainErrorMessage(w, err, "Invalid date range: from must be before to")
+			return
+		}
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to get access stats", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to get access stats")
+		}
 		return
 	}
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
 		"success": true,
-		"data":    logs,
+		"data":    stats,
 	})
 }
 
+const (
+	defaultPageSize = 50  // limit по умолчанию
+	maxPageSize     = 100 // максимальный limit
+)
+
 // getPaginationParams извлекает параметры пагинации из query string
 func getPaginationParams(r *http.Request) (limit, offset int) {
-	limit = 50 // по умолчанию
+	limit = defaultPageSize
 	offset = 0
 
 	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
 		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
 			limit = parsedLimit
-			if limit > 100 {
-				limit = 100 // максимум 100
+			if limit > maxPageSize {
+				limit = maxPageSize
 			}
 		}
 	}
@@ -167,3 +551,53 @@ func getPaginationParams(r *http.Request) (limit, offset int) {
 
 	return limit, offset
 }
+
+// parseTimeParam разбирает время из query string в формате RFC3339 или YYYY-MM-DD
+// Для конца периода дата без времени включает весь день
+func parseTimeParam(value string, endOfPeriod bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	if endOfPeriod {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+// GetOccupancy возвращает текущее число автомобилей на территории по зонам
+// GET /api/v1/access/occupancy
+func (h *AccessHandler) GetOccupancy(w http.ResponseWriter, r *http.Request) {
+	occupancy, err := h.accessService.Occupancy(r.Context())
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to get occupancy", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to get occupancy")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    occupancy,
+	})
+}
+
+// GetDeniedReasonStats возвращает распределение отказов по кодам причин
+// GET /api/v1/access/stats/denied-reasons
+func (h *AccessHandler) GetDeniedReasonStats(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    h.accessService.DeniedReasonCounts(),
+	})
+}
@@ -0,0 +1,883 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/frontandrew/gate/internal/delivery/http/middleware"
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/usecase/access"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessHandler_CheckAccess(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		mockSetup      func(*MockAccessService)
+		expectedStatus int
+		checkResponse  func(*testing.T, map[string]interface{})
+	}{
+		{
+			name: "успешная проверка доступа",
+			requestBody: access.CheckAccessRequest{
+				ImageBase64: "image",
+				GateID:      "gate-1",
+				Direction:   "IN",
+			},
+			mockSetup: func(m *MockAccessService) {
+				m.On("CheckAccess", mock.Anything, mock.AnythingOfType("*access.CheckAccessRequest")).
+					Return(&access.CheckAccessResponse{AccessGranted: true, LicensePlate: "A123BC777"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, resp map[string]interface{}) {
+				if success, ok := resp["success"].(bool); ok {
+					assert.True(t, success)
+				}
+				assert.NotNil(t, resp["data"])
+			},
+		},
+		{
+			name: "направление в нижнем регистре передается сервису",
+			requestBody: access.CheckAccessRequest{
+				ImageBase64: "image",
+				GateID:      "gate-1",
+				Direction:   "in",
+			},
+			mockSetup: func(m *MockAccessService) {
+				m.On("CheckAccess", mock.Anything, mock.MatchedBy(func(req *access.CheckAccessRequest) bool {
+					return req.Direction == "in"
+				})).Return(&access.CheckAccessResponse{AccessGranted: true, LicensePlate: "A123BC777"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, resp map[string]interface{}) {
+				assert.NotNil(t, resp["data"])
+			},
+		},
+		{
+			name: "неизвестное направление",
+			requestBody: access.CheckAccessRequest{
+				ImageBase64: "image",
+				GateID:      "gate-1",
+				Direction:   "SIDEWAYS",
+			},
+			mockSetup: func(m *MockAccessService) {
+				m.On("CheckAccess", mock.Anything, mock.AnythingOfType("*access.CheckAccessRequest")).
+					Return(nil, domain.ErrInvalidDirection)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			checkResponse: func(t *testing.T, resp map[string]interface{}) {
+				AssertErrorCode(t, resp, "INVALID_DIRECTION")
+			},
+		},
+		{
+			name: "неизвестные ворота",
+			requestBody: access.CheckAccessRequest{
+				ImageBase64: "image",
+				GateID:      "nort-1",
+				Direction:   "IN",
+			},
+			mockSetup: func(m *MockAccessService) {
+				m.On("CheckAccess", mock.Anything, mock.AnythingOfType("*access.CheckAccessRequest")).
+					Return(nil, domain.ErrUnknownGate)
+			},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp map[string]interface{}) {
+				AssertErrorCode(t, resp, "UNKNOWN_GATE")
+			},
+		},
+		{
+			name: "изображение отклонено распознаванием",
+			requestBody: access.CheckAccessRequest{
+				ImageBase64: "image",
+				GateID:      "gate-1",
+				Direction:   "IN",
+			},
+			mockSetup: func(m *MockAccessService) {
+				m.On("CheckAccess", mock.Anything, mock.AnythingOfType("*access.CheckAccessRequest")).
+					Return(nil, fmt.Errorf("recognize: %w", domain.ErrMLImageRejected))
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			checkResponse: func(t *testing.T, resp map[string]interface{}) {
+				AssertErrorCode(t, resp, "ML_IMAGE_REJECTED")
+			},
+		},
+		{
+			name:        "невалидный JSON",
+			requestBody: "invalid json",
+			mockSetup: func(m *MockAccessService) {
+				// Mock не будет вызван
+			},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp map[string]interface{}) {
+				AssertErrorCode(t, resp, "BAD_REQUEST")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAccessService)
+			tt.mockSetup(mockService)
+
+			log := logger.NewNoop()
+			handler := NewAccessHandler(mockService, log)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/access/check", bytes.NewReader(body))
+			w := httptest.NewRecorder()
+
+			handler.CheckAccess(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			_ = json.Unmarshal(w.Body.Bytes(), &response)
+			tt.checkResponse(t, response)
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAccessHandler_CheckAccess_BodyLimit(t *testing.T) {
+	const limit = 1 << 10
+
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func(*MockAccessService)
+		expectedStatus int
+	}{
+		{
+			name:           "изображение больше лимита",
+			body:           `{"image_base64":"` + strings.Repeat("A", 2*limit) + `","gate_id":"gate-1","direction":"IN"}`,
+			mockSetup:      func(m *MockAccessService) {},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "изображение в пределах лимита",
+			body: `{"image_base64":"` + strings.Repeat("A", limit/2) + `","gate_id":"gate-1","direction":"IN"}`,
+			mockSetup: func(m *MockAccessService) {
+				m.On("CheckAccess", mock.Anything, mock.AnythingOfType("*access.CheckAccessRequest")).
+					Return(&access.CheckAccessResponse{AccessGranted: true}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAccessService)
+			tt.mockSetup(mockService)
+
+			handler := NewAccessHandler(mockService, logger.NewNoop())
+			limited := middleware.MaxBodySize(limit)(http.HandlerFunc(handler.CheckAccess))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/access/check", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			limited.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAccessHandler_GetAccessLogs_ReasonCodeFilter(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		mockSetup      func(*MockAccessService)
+		expectedStatus int
+	}{
+		{
+			name:  "фильтр по коду причины и периоду",
+			query: "?reason_code=blacklisted&from=2026-10-05&to=2026-10-11",
+			mockSetup: func(m *MockAccessService) {
+				m.On("SearchAccessLogs", mock.Anything, mock.MatchedBy(func(f domain.AccessLogFilter) bool {
+					return f.ReasonCode == string(access.ReasonBlacklisted) &&
+						f.From != nil && f.From.Equal(time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)) &&
+						f.To != nil && f.To.Equal(time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC))
+				}), 50, 0).Return([]*domain.AccessLog{{LicensePlate: "A123BC777", ReasonCode: "BLACKLISTED"}}, nil)
+				m.On("CountAccessLogs", mock.Anything, mock.AnythingOfType("domain.AccessLogFilter")).Return(1, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "неизвестный код причины",
+			query:          "?reason_code=STOLEN",
+			mockSetup:      func(m *MockAccessService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAccessService)
+			tt.mockSetup(mockService)
+
+			handler := NewAccessHandler(mockService, logger.NewNoop())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/access/logs"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.GetAccessLogs(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAccessHandler_GetAccessLogs_Total(t *testing.T) {
+	userID := uuid.New()
+	vehicleID := uuid.New()
+	logs := []*domain.AccessLog{{LicensePlate: "A123BC777"}, {LicensePlate: "A123BC777"}}
+
+	tests := []struct {
+		name           string
+		query          string
+		mockSetup      func(*MockAccessService)
+		expectedStatus int
+		expectedTotal  int
+	}{
+		{
+			name:  "без фильтров - total по всем логам",
+			query: "?limit=2",
+			mockSetup: func(m *MockAccessService) {
+				m.On("GetAccessLogs", mock.Anything, (*uuid.UUID)(nil), 2, 0).Return(logs, nil)
+				m.On("CountAccessLogs", mock.Anything, domain.AccessLogFilter{}).Return(7, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedTotal:  7,
+		},
+		{
+			name:  "фильтр по пользователю и автомобилю",
+			query: "?user_id=" + userID.String() + "&vehicle_id=" + vehicleID.String() + "&limit=2&offset=2",
+			mockSetup: func(m *MockAccessService) {
+				filter := domain.AccessLogFilter{UserID: &userID, VehicleID: &vehicleID}
+				m.On("SearchAccessLogs", mock.Anything, filter, 2, 2).Return(logs, nil)
+				m.On("CountAccessLogs", mock.Anything, filter).Return(4, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedTotal:  4,
+		},
+		{
+			name:           "невалидный vehicle_id",
+			query:          "?vehicle_id=not-a-uuid",
+			mockSetup:      func(m *MockAccessService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "ошибка подсчета",
+			query: "",
+			mockSetup: func(m *MockAccessService) {
+				m.On("GetAccessLogs", mock.Anything, (*uuid.UUID)(nil), 50, 0).Return(logs, nil)
+				m.On("CountAccessLogs", mock.Anything, mock.Anything).Return(0, assert.AnError)
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAccessService)
+			tt.mockSetup(mockService)
+
+			handler := NewAccessHandler(mockService, logger.NewNoop())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/access/logs"+tt.query, nil)
+			w := httptest.NewRecorder()
+			handler.GetAccessLogs(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response struct {
+					Pagination map[string]int `json:"pagination"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedTotal, response.Pagination["total"])
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAccessHandler_GetAccessLog(t *testing.T) {
+	logID := uuid.New()
+	userID := uuid.New()
+	vehicleID := uuid.New()
+	accessLog := &domain.AccessLog{
+		ID:            logID,
+		UserID:        &userID,
+		VehicleID:     &vehicleID,
+		LicensePlate:  "A123BC777",
+		ImageURL:      "https://storage.example.com/frames/1.jpg",
+		AccessGranted: true,
+		Direction:     domain.DirectionIn,
+		User:          &domain.User{ID: userID, FullName: "Иван Иванов"},
+		Vehicle:       &domain.Vehicle{ID: vehicleID, LicensePlate: "A123BC777"},
+	}
+
+	tests := []struct {
+		name           string
+		logID          string
+		mockSetup      func(*MockAccessService)
+		expectedStatus int
+	}{
+		{
+			name:  "запись с пользователем и автомобилем",
+			logID: logID.String(),
+			mockSetup: func(m *MockAccessService) {
+				m.On("GetAccessLog", mock.Anything, logID).Return(accessLog, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "запись не найдена",
+			logID: logID.String(),
+			mockSetup: func(m *MockAccessService) {
+				m.On("GetAccessLog", mock.Anything, logID).Return(nil, domain.ErrAccessLogNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "невалидный ID",
+			logID:          "not-a-uuid",
+			mockSetup:      func(m *MockAccessService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "ошибка сервиса",
+			logID: logID.String(),
+			mockSetup: func(m *MockAccessService) {
+				m.On("GetAccessLog", mock.Anything, logID).Return(nil, assert.AnError)
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAccessService)
+			tt.mockSetup(mockService)
+
+			handler := NewAccessHandler(mockService, logger.NewNoop())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/access/logs/"+tt.logID, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.logID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			w := httptest.NewRecorder()
+			handler.GetAccessLog(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response struct {
+					Data domain.AccessLog `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, accessLog.ImageURL, response.Data.ImageURL)
+				require.NotNil(t, response.Data.User)
+				assert.Equal(t, "Иван Иванов", response.Data.User.FullName)
+				require.NotNil(t, response.Data.Vehicle)
+				assert.Equal(t, vehicleID, response.Data.Vehicle.ID)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAccessHandler_GetAccessLogs_Include(t *testing.T) {
+	logs := []*domain.AccessLog{{LicensePlate: "A123BC777"}}
+
+	tests := []struct {
+		name           string
+		query          string
+		include        *access.LogInclude // nil - LoadRelations не вызывается
+		expectedStatus int
+	}{
+		{name: "без include", query: "", expectedStatus: http.StatusOK},
+		{name: "пользователь и автомобиль", query: "?include=user,vehicle", include: &access.LogInclude{User: true, Vehicle: true}, expectedStatus: http.StatusOK},
+		{name: "только автомобиль", query: "?include=vehicle", include: &access.LogInclude{Vehicle: true}, expectedStatus: http.StatusOK},
+		{name: "неизвестное значение", query: "?include=user,pass", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAccessService)
+			if tt.expectedStatus == http.StatusOK {
+				mockService.On("GetAccessLogs", mock.Anything, (*uuid.UUID)(nil), 50, 0).Return(logs, nil)
+				mockService.On("CountAccessLogs", mock.Anything, domain.AccessLogFilter{}).Return(1, nil)
+			}
+			if tt.include != nil {
+				mockService.On("LoadRelations", mock.Anything, logs, *tt.include).Return(nil)
+			}
+
+			handler := NewAccessHandler(mockService, logger.NewNoop())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/access/logs"+tt.query, nil)
+			w := httptest.NewRecorder()
+			handler.GetAccessLogs(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.include == nil {
+				mockService.AssertNotCalled(t, "LoadRelations", mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestParseAccessLogFilter(t *testing.T) {
+	userID := uuid.New()
+	vehicleID := uuid.New()
+	granted := true
+	denied := false
+	from := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedFilter domain.AccessLogFilter
+		expectedError  string
+	}{
+		{
+			name: "без фильтров",
+		},
+		{
+			name:           "user_id",
+			query:          "user_id=" + userID.String(),
+			expectedFilter: domain.AccessLogFilter{UserID: &userID},
+		},
+		{
+			name:           "vehicle_id",
+			query:          "vehicle_id=" + vehicleID.String(),
+			expectedFilter: domain.AccessLogFilter{VehicleID: &vehicleID},
+		},
+		{
+			name:           "gate_id",
+			query:          "gate_id=gate_003",
+			expectedFilter: domain.AccessLogFilter{GateID: "gate_003"},
+		},
+		{
+			name:           "direction нормализуется",
+			query:          "direction=out",
+			expectedFilter: domain.AccessLogFilter{Direction: domain.DirectionOut},
+		},
+		{
+			name:           "access_granted",
+			query:          "access_granted=true",
+			expectedFilter: domain.AccessLogFilter{AccessGranted: &granted},
+		},
+		{
+			name:           "период датами",
+			query:          "from=2026-10-14&to=2026-10-14",
+			expectedFilter: domain.AccessLogFilter{From: &from, To: &to},
+		},
+		{
+			name:  "отказы на воротах за вчера",
+			query: "gate_id=gate_003&access_granted=false&direction=IN&from=2026-10-14&to=2026-10-14",
+			expectedFilter: domain.AccessLogFilter{
+				GateID:        "gate_003",
+				Direction:     domain.DirectionIn,
+				AccessGranted: &denied,
+				From:          &from,
+				To:            &to,
+			},
+		},
+		{
+			name:           "все фильтры вместе",
+			query:          "user_id=" + userID.String() + "&vehicle_id=" + vehicleID.String() + "&gate_id=north-1&direction=OUT&access_granted=1&reason_code=blacklisted",
+			expectedFilter: domain.AccessLogFilter{UserID: &userID, VehicleID: &vehicleID, GateID: "north-1", Direction: domain.DirectionOut, AccessGranted: &granted, ReasonCode: string(access.ReasonBlacklisted)},
+		},
+		{
+			name:          "невалидный user_id",
+			query:         "user_id=42",
+			expectedError: "Invalid user_id",
+		},
+		{
+			name:          "невалидный vehicle_id",
+			query:         "vehicle_id=42",
+			expectedError: "Invalid vehicle_id",
+		},
+		{
+			name:          "слишком длинный gate_id",
+			query:         "gate_id=" + strings.Repeat("g", maxGateIDLength+1),
+			expectedError: "Invalid gate_id",
+		},
+		{
+			name:          "неизвестное направление",
+			query:         "direction=sideways",
+			expectedError: "Invalid direction: expected IN or OUT",
+		},
+		{
+			name:          "невалидный access_granted",
+			query:         "access_granted=maybe",
+			expectedError: "Invalid access_granted: expected true or false",
+		},
+		{
+			name:          "невалидная дата",
+			query:         "from=14.10.2026",
+			expectedError: "Invalid from: expected RFC3339 or YYYY-MM-DD",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+
+			filter, errMessage := parseAccessLogFilter(query)
+
+			assert.Equal(t, tt.expectedError, errMessage)
+			if tt.expectedError == "" {
+				assert.Equal(t, tt.expectedFilter, filter)
+			}
+		})
+	}
+}
+
+func TestAccessHandler_GetAccessLogs_GateFilter(t *testing.T) {
+	denied := false
+	filter := domain.AccessLogFilter{GateID: "gate_003", AccessGranted: &denied}
+
+	mockService := new(MockAccessService)
+	mockService.On("SearchAccessLogs", mock.Anything, filter, 50, 0).
+		Return([]*domain.AccessLog{{LicensePlate: "A123BC777", GateID: "gate_003"}}, nil)
+	mockService.On("CountAccessLogs", mock.Anything, filter).Return(1, nil)
+
+	handler := NewAccessHandler(mockService, logger.NewNoop())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/access/logs?gate_id=gate_003&access_granted=false", nil)
+	w := httptest.NewRecorder()
+	handler.GetAccessLogs(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "GetAccessLogs", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAccessHandler_GetStats(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		mockSetup      func(*MockAccessService)
+		expectedStatus int
+	}{
+		{
+			name:  "без параметров - последние сутки",
+			query: "",
+			mockSetup: func(m *MockAccessService) {
+				m.On("GetStats", mock.Anything, mock.AnythingOfType("time.Time"), mock.MatchedBy(func(to time.Time) bool {
+					return time.Since(to) < time.Minute
+				})).Run(func(args mock.Arguments) {
+					from, to := args.Get(1).(time.Time), args.Get(2).(time.Time)
+					assert.Equal(t, 24*time.Hour, to.Sub(from))
+				}).Return(&domain.AccessStats{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "пустой период",
+			query: "?from=2026-10-05T00:00:00Z&to=2026-10-06T00:00:00Z",
+			mockSetup: func(m *MockAccessService) {
+				from := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+				to := time.Date(2026, 10, 6, 0, 0, 0, 0, time.UTC)
+				m.On("GetStats", mock.Anything, from, to).Return(&domain.AccessStats{From: from, To: to}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "from позже to",
+			query: "?from=2026-10-06T00:00:00Z&to=2026-10-05T00:00:00Z",
+			mockSetup: func(m *MockAccessService) {
+				m.On("GetStats", mock.Anything, mock.Anything, mock.Anything).Return(nil, domain.ErrInvalidDateRange)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "некорректный формат from",
+			query:          "?from=yesterday",
+			mockSetup:      func(m *MockAccessService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAccessService)
+			tt.mockSetup(mockService)
+
+			handler := NewAccessHandler(mockService, logger.NewNoop())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/access/stats"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.GetStats(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAccessHandler_GetOccupancy(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockSetup      func(*MockAccessService)
+		expectedStatus int
+	}{
+		{
+			name: "заполненность по зонам",
+			mockSetup: func(m *MockAccessService) {
+				m.On("Occupancy", mock.Anything).Return(&access.OccupancyResponse{
+					Total:    12,
+					Capacity: 10,
+					Scopes: []access.ScopeOccupancy{
+						{Scope: "gate-3", Count: 2},
+						{Scope: "parking", Count: 10, Full: true},
+					},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "учет отключен без Redis",
+			mockSetup: func(m *MockAccessService) {
+				m.On("Occupancy", mock.Anything).Return(nil, domain.ErrOccupancyUnavailable)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name: "ошибка Redis",
+			mockSetup: func(m *MockAccessService) {
+				m.On("Occupancy", mock.Anything).Return(nil, errors.New("connection refused"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAccessService)
+			tt.mockSetup(mockService)
+
+			handler := NewAccessHandler(mockService, logger.NewNoop())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/access/occupancy", nil)
+			w := httptest.NewRecorder()
+
+			handler.GetOccupancy(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var body struct {
+					Data access.OccupancyResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, int64(12), body.Data.Total)
+				require.Len(t, body.Data.Scopes, 2)
+				assert.True(t, body.Data.Scopes[1].Full)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAccessHandler_ExportAccessLogs(t *testing.T) {
+	userID := uuid.New()
+	timestamp := time.Date(2026, 10, 5, 8, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		query          string
+		mockSetup      func(*MockAccessService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "строки выгружаются в CSV с фильтрами",
+			query: "?format=csv&user_id=" + userID.String() + "&from=2026-10-05",
+			mockSetup: func(m *MockAccessService) {
+				m.On("ExportAccessLogs", mock.Anything, mock.MatchedBy(func(f domain.AccessLogFilter) bool {
+					return f.UserID != nil && *f.UserID == userID && f.From != nil && f.To == nil
+				}), mock.Anything).Run(func(args mock.Arguments) {
+					fn := args.Get(2).(func(*domain.AccessLog) error)
+					_ = fn(&domain.AccessLog{Timestamp: timestamp, LicensePlate: "A123BC777", AccessGranted: true,
+						AccessReason: "Valid pass", GateID: "gate-1", Direction: domain.DirectionIn, RecognitionConfidence: 95.5})
+					_ = fn(&domain.AccessLog{Timestamp: timestamp, LicensePlate: "B456CD777", AccessReason: "Blacklisted: stolen, reported",
+						GateID: "gate-1", Direction: domain.DirectionOut, RecognitionConfidence: 88})
+				}).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: "timestamp,license_plate,access_granted,reason,gate_id,direction,confidence\n" +
+				"2026-10-05T08:30:00Z,A123BC777,true,Valid pass,gate-1,IN,95.50\n" +
+				"2026-10-05T08:30:00Z,B456CD777,false,\"Blacklisted: stolen, reported\",gate-1,OUT,88.00\n",
+		},
+		{
+			name:  "пустая выборка - только заголовок",
+			query: "",
+			mockSetup: func(m *MockAccessService) {
+				m.On("ExportAccessLogs", mock.Anything, domain.AccessLogFilter{}, mock.Anything).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "timestamp,license_plate,access_granted,reason,gate_id,direction,confidence\n",
+		},
+		{
+			name:  "некорректный период",
+			query: "?from=2026-10-06&to=2026-10-05",
+			mockSetup: func(m *MockAccessService) {
+				m.On("ExportAccessLogs", mock.Anything, mock.Anything, mock.Anything).Return(domain.ErrInvalidDateRange)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "неподдерживаемый формат",
+			query:          "?format=xlsx",
+			mockSetup:      func(m *MockAccessService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAccessService)
+			tt.mockSetup(mockService)
+
+			handler := NewAccessHandler(mockService, logger.NewNoop())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/access/logs/export"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.ExportAccessLogs(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+				assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+				assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAccessHandler_ManualAccess(t *testing.T) {
+	guardID := uuid.New()
+	validBody := access.ManualAccessRequest{
+		LicensePlate: "A123BC777",
+		GateID:       "gate-1",
+		Direction:    "IN",
+		Reason:       "Камера залеплена снегом",
+	}
+
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		authenticated  bool
+		mockSetup      func(*MockAccessService)
+		expectedStatus int
+		checkResponse  func(*testing.T, map[string]interface{})
+	}{
+		{
+			name:          "ручное решение от имени охранника",
+			requestBody:   validBody,
+			authenticated: true,
+			mockSetup: func(m *MockAccessService) {
+				m.On("ManualAccess", mock.Anything, mock.MatchedBy(func(req *access.ManualAccessRequest) bool {
+					return req.LicensePlate == "A123BC777" && req.Reason == "Камера залеплена снегом"
+				}), guardID).Return(&access.CheckAccessResponse{
+					AccessGranted: true,
+					LicensePlate:  "A123BC777",
+					ReasonCode:    access.ReasonValidPass,
+					Manual:        true,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, resp map[string]interface{}) {
+				data, ok := resp["data"].(map[string]interface{})
+				require.True(t, ok)
+				assert.Equal(t, true, data["access_granted"])
+				assert.Equal(t, true, data["manual"])
+			},
+		},
+		{
+			name: "причина обязательна",
+			requestBody: access.ManualAccessRequest{
+				LicensePlate: "A123BC777",
+				GateID:       "gate-1",
+				Direction:    "IN",
+			},
+			authenticated:  true,
+			mockSetup:      func(m *MockAccessService) {},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp map[string]interface{}) {
+				AssertFieldErrors(t, resp, "reason")
+			},
+		},
+		{
+			name:          "неизвестные ворота",
+			requestBody:   validBody,
+			authenticated: true,
+			mockSetup: func(m *MockAccessService) {
+				m.On("ManualAccess", mock.Anything, mock.Anything, guardID).Return(nil, domain.ErrUnknownGate)
+			},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp map[string]interface{}) {
+				AssertErrorCode(t, resp, "UNKNOWN_GATE")
+			},
+		},
+		{
+			name:           "без аутентификации",
+			requestBody:    validBody,
+			mockSetup:      func(m *MockAccessService) {},
+			expectedStatus: http.StatusUnauthorized,
+			checkResponse: func(t *testing.T, resp map[string]interface{}) {
+				AssertErrorCode(t, resp, "UNAUTHORIZED")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAccessService)
+			tt.mockSetup(mockService)
+			handler := NewAccessHandler(mockService, logger.NewNoop())
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/access/manual", bytes.NewReader(body))
+			if tt.authenticated {
+				req = req.WithContext(CreateAuthContext(t, guardID, "guard@example.com", domain.RoleGuard))
+			}
+			w := httptest.NewRecorder()
+
+			handler.ManualAccess(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			_ = json.Unmarshal(w.Body.Bytes(), &response)
+			tt.checkResponse(t, response)
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
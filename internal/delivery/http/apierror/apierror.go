@@ -0,0 +1,198 @@
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/frontandrew/gate/internal/domain"
+)
+
+// Code - стабильный машиночитаемый код ошибки API
+// Клиенты сравнивают код, а не текст сообщения: сообщение может меняться, код - нет
+type Code string
+
+// Общие коды - для ошибок, не связанных с конкретной доменной ошибкой
+const (
+	CodeBadRequest         Code = "BAD_REQUEST"
+	CodeValidationFailed   Code = "VALIDATION_FAILED"
+	CodeUnauthorized       Code = "UNAUTHORIZED"
+	CodeTokenRevoked       Code = "TOKEN_REVOKED"
+	CodeForbidden          Code = "FORBIDDEN"
+	CodeNotFound           Code = "NOT_FOUND"
+	CodeConflict           Code = "CONFLICT"
+	CodePayloadTooLarge    Code = "PAYLOAD_TOO_LARGE"
+	CodeUnprocessable      Code = "UNPROCESSABLE_ENTITY"
+	CodeRateLimited        Code = "RATE_LIMITED"
+	CodeInternal           Code = "INTERNAL_ERROR"
+	CodeServiceUnavailable Code = "SERVICE_UNAVAILABLE"
+)
+
+// statusCodes - код ошибки по умолчанию для HTTP статуса
+var statusCodes = map[int]Code{
+	http.StatusBadRequest:            CodeBadRequest,
+	http.StatusUnauthorized:          CodeUnauthorized,
+	http.StatusForbidden:             CodeForbidden,
+	http.StatusNotFound:              CodeNotFound,
+	http.StatusConflict:              CodeConflict,
+	http.StatusRequestEntityTooLarge: CodePayloadTooLarge,
+	http.StatusUnprocessableEntity:   CodeUnprocessable,
+	http.StatusTooManyRequests:       CodeRateLimited,
+	http.StatusInternalServerError:   CodeInternal,
+	http.StatusServiceUnavailable:    CodeServiceUnavailable,
+}
+
+// domainCodes - коды доменных ошибок; коды входят в контракт API и не переименовываются
+var domainCodes = map[error]Code{
+	// User errors
+	domain.ErrUserNotFound:       "USER_NOT_FOUND",
+	domain.ErrUserAlreadyExists:  "USER_ALREADY_EXISTS",
+	domain.ErrInvalidEmail:       "INVALID_EMAIL",
+	domain.ErrInvalidPassword:    "INVALID_PASSWORD",
+	domain.ErrWeakPassword:       "WEAK_PASSWORD",
+	domain.ErrInvalidUserData:    "INVALID_USER_DATA",
+	domain.ErrInvalidRole:        "INVALID_ROLE",
+	domain.ErrUserInactive:       "USER_INACTIVE",
+	domain.ErrInvalidCredentials: "INVALID_CREDENTIALS",
+	domain.ErrInvalidPhone:       "INVALID_PHONE",
+	domain.ErrAccountLocked:      "ACCOUNT_LOCKED",
+	domain.ErrRegistrationClosed: "REGISTRATION_CLOSED",
+	domain.ErrLastAdmin:          "LAST_ADMIN",
+
+	// Vehicle errors
+	domain.ErrVehicleNotFound:      "VEHICLE_NOT_FOUND",
+	domain.ErrVehicleAlreadyExists: "VEHICLE_ALREADY_EXISTS",
+	domain.ErrInvalidLicensePlate:  "INVALID_LICENSE_PLATE",
+	domain.ErrInvalidVehicleData:   "INVALID_VEHICLE_DATA",
+	domain.ErrVehicleInactive:      "VEHICLE_INACTIVE",
+	domain.ErrVehicleOwnerMismatch: "VEHICLE_OWNER_MISMATCH",
+	domain.ErrPlateQueryTooShort:   "PLATE_QUERY_TOO_SHORT",
+
+	// Pass errors
+	domain.ErrPassNotFound:           "PASS_NOT_FOUND",
+	domain.ErrInvalidPassData:        "INVALID_PASS_DATA",
+	domain.ErrInvalidPassType:        "INVALID_PASS_TYPE",
+	domain.ErrInvalidDateRange:       "INVALID_DATE_RANGE",
+	domain.ErrInvalidPassSchedule:    "INVALID_PASS_SCHEDULE",
+	domain.ErrPassExpired:            "PASS_EXPIRED",
+	domain.ErrPassNotActive:          "PASS_NOT_ACTIVE",
+	domain.ErrPassAlreadyRevoked:     "PASS_ALREADY_REVOKED",
+	domain.ErrPassNotExtendable:      "PASS_NOT_EXTENDABLE",
+	domain.ErrPassUsageLimitReached:  "PASS_USAGE_LIMIT_REACHED",
+	domain.ErrOverlappingPass:        "OVERLAPPING_PASS",
+	domain.ErrNoValidPass:            "NO_VALID_PASS",
+	domain.ErrGuestPassLimitExceeded: "GUEST_PASS_LIMIT_EXCEEDED",
+
+	// PassVehicle errors
+	domain.ErrPassVehicleNotFound:      "PASS_VEHICLE_NOT_FOUND",
+	domain.ErrPassVehicleAlreadyExists: "PASS_VEHICLE_ALREADY_EXISTS",
+	domain.ErrPassVehicleLinkFailed:    "PASS_VEHICLE_LINK_FAILED",
+	domain.ErrPassVehicleOwnerMismatch: "PASS_VEHICLE_OWNER_MISMATCH",
+	domain.ErrInvalidPassVehicleData:   "INVALID_PASS_VEHICLE_DATA",
+
+	// AccessLog errors
+	domain.ErrAccessLogNotFound:    "ACCESS_LOG_NOT_FOUND",
+	domain.ErrInvalidAccessLogData: "INVALID_ACCESS_LOG_DATA",
+	domain.ErrInvalidDirection:     "INVALID_DIRECTION",
+	domain.ErrInvalidConfidence:    "INVALID_CONFIDENCE",
+	domain.ErrInvalidReasonCode:    "INVALID_REASON_CODE",
+	domain.ErrUnknownGate:          "UNKNOWN_GATE",
+	domain.ErrStaleFrame:           "STALE_FRAME",
+	domain.ErrOccupancyUnavailable: "OCCUPANCY_UNAVAILABLE",
+
+	// ML errors
+	domain.ErrMLImageRejected: "ML_IMAGE_REJECTED",
+
+	// Audit errors
+	domain.ErrInvalidAuditLogData:    "INVALID_AUDIT_LOG_DATA",
+	domain.ErrInvalidAuditTargetType: "INVALID_AUDIT_TARGET_TYPE",
+
+	// Authorization errors
+	domain.ErrUnauthorized:             CodeUnauthorized,
+	domain.ErrForbidden:                CodeForbidden,
+	domain.ErrTokenExpired:             "TOKEN_EXPIRED",
+	domain.ErrInvalidToken:             "INVALID_TOKEN",
+	domain.ErrRefreshTokenNotFound:     "REFRESH_TOKEN_NOT_FOUND",
+	domain.ErrInvalidResetToken:        "INVALID_RESET_TOKEN",
+	domain.ErrPasswordResetUnavailable: "PASSWORD_RESET_UNAVAILABLE",
+
+	// Blacklist/Whitelist errors
+	domain.ErrBlacklistEntryNotFound:      "BLACKLIST_ENTRY_NOT_FOUND",
+	domain.ErrBlacklistEntryAlreadyExists: "BLACKLIST_ENTRY_ALREADY_EXISTS",
+	domain.ErrInvalidBlacklistData:        "INVALID_BLACKLIST_DATA",
+	domain.ErrWhitelistEntryNotFound:      "WHITELIST_ENTRY_NOT_FOUND",
+	domain.ErrWhitelistEntryAlreadyExists: "WHITELIST_ENTRY_ALREADY_EXISTS",
+	domain.ErrInvalidWhitelistData:        "INVALID_WHITELIST_DATA",
+	domain.ErrExpiryInPast:                "EXPIRY_IN_PAST",
+	domain.ErrInvalidZone:                 "INVALID_ZONE",
+
+	// General errors
+	domain.ErrInternal:   CodeInternal,
+	domain.ErrNotFound:   CodeNotFound,
+	domain.ErrBadRequest: CodeBadRequest,
+	domain.ErrConflict:   CodeConflict,
+}
+
+// CodeForStatus возвращает код ошибки по умолчанию для HTTP статуса
+func CodeForStatus(status int) Code {
+	if code, ok := statusCodes[status]; ok {
+		return code
+	}
+	return Code(strings.ToUpper(strings.ReplaceAll(http.StatusText(status), " ", "_")))
+}
+
+// CodeForError возвращает код доменной ошибки err (в том числе обернутой);
+// для остальных ошибок - код по умолчанию для status
+func CodeForError(err error, status int) Code {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if code, ok := domainCodes[e]; ok {
+			return code
+		}
+	}
+	return CodeForStatus(status)
+}
+
+// FieldError - ошибка валидации одного поля запроса
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Detail - описание ошибки в ответе
+// Field заполняется для ошибки одного поля, Fields - для ошибок валидации нескольких полей
+type Detail struct {
+	Code    Code         `json:"code"`
+	Message string       `json:"message"`
+	Field   string       `json:"field,omitempty"`
+	Fields  []FieldError `json:"fields,omitempty"`
+}
+
+// Response - тело ответа с ошибкой: {"success":false,"error":{"code":"...","message":"..."}}
+type Response struct {
+	Success bool   `json:"success"`
+	Error   Detail `json:"error"`
+}
+
+// Write отправляет JSON ответ с ошибкой detail и HTTP статусом status
+func Write(w http.ResponseWriter, status int, detail Detail) {
+	body, err := json.Marshal(Response{Error: detail})
+	if err != nil {
+		status = http.StatusInternalServerError
+		body = []byte(`{"success":false,"error":{"code":"INTERNAL_ERROR","message":"Failed to marshal response"}}`)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// Error отправляет ответ с ошибкой; код определяется по HTTP статусу
+func Error(w http.ResponseWriter, status int, message string) {
+	Write(w, status, Detail{Code: CodeForStatus(status), Message: message})
+}
+
+// DomainError отправляет ответ с доменной ошибкой err; код берется из err, сообщение - message
+func DomainError(w http.ResponseWriter, status int, err error, message string) {
+	Write(w, status, Detail{Code: CodeForError(err, status), Message: message})
+}
@@ -0,0 +1,71 @@
+package apierror
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodeForError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		status   int
+		expected Code
+	}{
+		{name: "пользователь уже существует", err: domain.ErrUserAlreadyExists, status: http.StatusConflict, expected: "USER_ALREADY_EXISTS"},
+		{name: "пользователь не найден", err: domain.ErrUserNotFound, status: http.StatusNotFound, expected: "USER_NOT_FOUND"},
+		{name: "неверные учетные данные", err: domain.ErrInvalidCredentials, status: http.StatusUnauthorized, expected: "INVALID_CREDENTIALS"},
+		{name: "некорректный номер", err: domain.ErrInvalidLicensePlate, status: http.StatusBadRequest, expected: "INVALID_LICENSE_PLATE"},
+		{name: "пропуск не найден", err: domain.ErrPassNotFound, status: http.StatusNotFound, expected: "PASS_NOT_FOUND"},
+		{name: "общая ошибка домена", err: domain.ErrForbidden, status: http.StatusForbidden, expected: CodeForbidden},
+		{name: "обернутая доменная ошибка", err: fmt.Errorf("create vehicle: %w", domain.ErrVehicleAlreadyExists), status: http.StatusConflict, expected: "VEHICLE_ALREADY_EXISTS"},
+		{name: "ошибка вне домена - код по статусу", err: fmt.Errorf("connection refused"), status: http.StatusInternalServerError, expected: CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CodeForError(tt.err, tt.status))
+		})
+	}
+}
+
+func TestCodeForStatus(t *testing.T) {
+	assert.Equal(t, CodeBadRequest, CodeForStatus(http.StatusBadRequest))
+	assert.Equal(t, CodeRateLimited, CodeForStatus(http.StatusTooManyRequests))
+	assert.Equal(t, CodePayloadTooLarge, CodeForStatus(http.StatusRequestEntityTooLarge))
+	assert.Equal(t, Code("METHOD_NOT_ALLOWED"), CodeForStatus(http.StatusMethodNotAllowed), "статус без явного кода")
+}
+
+func TestWrite(t *testing.T) {
+	t.Run("доменная ошибка", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		DomainError(w, http.StatusConflict, domain.ErrUserAlreadyExists, "User already exists")
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"success":false,"error":{"code":"USER_ALREADY_EXISTS","message":"User already exists"}}`, w.Body.String())
+	})
+
+	t.Run("сообщение экранируется", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		Error(w, http.StatusBadRequest, `bad "quote"`)
+
+		assert.JSONEq(t, `{"success":false,"error":{"code":"BAD_REQUEST","message":"bad \"quote\""}}`, w.Body.String())
+	})
+
+	t.Run("ошибки полей", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		Write(w, http.StatusBadRequest, Detail{
+			Code:    CodeValidationFailed,
+			Message: "Validation failed",
+			Fields:  []FieldError{{Field: "email", Message: "email is required"}},
+		})
+
+		assert.JSONEq(t, `{"success":false,"error":{"code":"VALIDATION_FAILED","message":"Validation failed","fields":[{"field":"email","message":"email is required"}]}}`, w.Body.String())
+	})
+}
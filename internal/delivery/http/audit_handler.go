@@ -0,0 +1,99 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// AuditService определяет интерфейс для сервиса журнала аудита
+type AuditService interface {
+	SearchAuditLogs(ctx context.Context, filter domain.AuditLogFilter, limit, offset int) ([]*domain.AuditLog, error)
+}
+
+// AuditHandler обрабатывает запросы к журналу аудита
+type AuditHandler struct {
+	auditService AuditService
+	logger       logger.Logger
+}
+
+// NewAuditHandler создает новый handler
+func NewAuditHandler(auditService AuditService, logger logger.Logger) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+		logger:       logger,
+	}
+}
+
+// SearchAuditLogs возвращает записи журнала аудита с фильтрацией и пагинацией
+// GET /api/v1/audit?target_type=&target_id=&actor=&from=&to=
+func (h *AuditHandler) SearchAuditLogs(w http.ResponseWriter, r *http.Request) {
+	limit, offset := getPaginationParams(r)
+	query := r.URL.Query()
+
+	var filter domain.AuditLogFilter
+	var err error
+
+	if targetType := query.Get("target_type"); targetType != "" {
+		if filter.TargetType, err = domain.ParseAuditTargetType(targetType); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid target_type: expected user, vehicle, pass, whitelist or blacklist")
+			return
+		}
+	}
+
+	if targetID := query.Get("target_id"); targetID != "" {
+		parsedID, err := uuid.Parse(targetID)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid target_id")
+			return
+		}
+		filter.TargetID = &parsedID
+	}
+
+	if actor := query.Get("actor"); actor != "" {
+		parsedID, err := uuid.Parse(actor)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid actor")
+			return
+		}
+		filter.ActorID = &parsedID
+	}
+
+	if filter.From, err = parseTimeParam(query.Get("from"), false); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid from: expected RFC3339 or YYYY-MM-DD")
+		return
+	}
+	if filter.To, err = parseTimeParam(query.Get("to"), true); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid to: expected RFC3339 or YYYY-MM-DD")
+		return
+	}
+
+	entries, err := h.auditService.SearchAuditLogs(r.Context(), filter, limit, offset)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidDateRange):
+			respondDomainErrorMessage(w, err, "Invalid date range: from must be before to")
+		default:
+			if !respondDomainError(w, err) {
+				requestLogger(r, h.logger).Error("Failed to search audit logs", map[string]interface{}{
+					"error": err.Error(),
+				})
+				respondError(w, http.StatusInternalServerError, "Failed to search audit logs")
+			}
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    entries,
+		"pagination": map[string]int{
+			"limit":  limit,
+			"offset": offset,
+		},
+	})
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuditHandler_SearchAuditLogs(t *testing.T) {
+	targetID := uuid.New()
+	actorID := uuid.New()
+
+	tests := []struct {
+		name           string
+		query          string
+		mockSetup      func(*MockAuditService)
+		expectedStatus int
+		checkResponse  func(*testing.T, map[string]interface{})
+	}{
+		{
+			name:  "фильтр по объекту",
+			query: "?target_type=blacklist&target_id=" + targetID.String(),
+			mockSetup: func(m *MockAuditService) {
+				m.On("SearchAuditLogs", mock.Anything, mock.MatchedBy(func(f domain.AuditLogFilter) bool {
+					return f.TargetType == domain.AuditTargetBlacklist &&
+						f.TargetID != nil && *f.TargetID == targetID &&
+						f.ActorID == nil
+				}), 50, 0).Return([]*domain.AuditLog{
+					{ID: uuid.New(), Action: "create", TargetType: domain.AuditTargetBlacklist, TargetID: &targetID},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, resp map[string]interface{}) {
+				data, ok := resp["data"].([]interface{})
+				if assert.True(t, ok) {
+					assert.Len(t, data, 1)
+				}
+			},
+		},
+		{
+			name:  "фильтр по автору действия",
+			query: "?actor=" + actorID.String() + "&limit=10&offset=20",
+			mockSetup: func(m *MockAuditService) {
+				m.On("SearchAuditLogs", mock.Anything, mock.MatchedBy(func(f domain.AuditLogFilter) bool {
+					return f.ActorID != nil && *f.ActorID == actorID && f.TargetID == nil && f.TargetType == ""
+				}), 10, 20).Return([]*domain.AuditLog{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "неизвестный тип объекта",
+			query:          "?target_type=gate",
+			mockSetup:      func(m *MockAuditService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "невалидный actor",
+			query:          "?actor=admin",
+			mockSetup:      func(m *MockAuditService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "from позже to",
+			query: "?from=2026-10-10&to=2026-10-01",
+			mockSetup: func(m *MockAuditService) {
+				m.On("SearchAuditLogs", mock.Anything, mock.AnythingOfType("domain.AuditLogFilter"), 50, 0).
+					Return(nil, domain.ErrInvalidDateRange)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAuditService)
+			tt.mockSetup(mockService)
+
+			handler := NewAuditHandler(mockService, logger.NewNoop())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/audit"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.SearchAuditLogs(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.checkResponse != nil {
+				var response map[string]interface{}
+				_ = json.Unmarshal(w.Body.Bytes(), &response)
+				tt.checkResponse(t, response)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
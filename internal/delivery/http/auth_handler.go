@@ -2,7 +2,8 @@ package http
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/frontandrew/gate/internal/delivery/http/middleware"
@@ -19,6 +20,12 @@ type AuthService interface {
 	Logout(ctx context.Context, req *auth.LogoutRequest) error
 	RefreshToken(ctx context.Context, req *auth.RefreshTokenRequest) (*auth.LoginResponse, error)
 	GetUserByID(ctx context.Context, userID uuid.UUID) (*domain.User, error)
+	ListSessions(ctx context.Context, userID uuid.UUID) ([]*auth.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error
+	RevokeAllSessions(ctx context.Context, userID uuid.UUID) error
+	ChangePassword(ctx context.Context, userID uuid.UUID, req *auth.ChangePasswordRequest) error
+	ForgotPassword(ctx context.Context, req *auth.ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, req *auth.ResetPasswordRequest) error
 }
 
 // AuthHandler обрабатывает запросы аутентификации
@@ -39,21 +46,22 @@ func NewAuthHandler(authService AuthService, logger logger.Logger) *AuthHandler
 // POST /api/v1/auth/register
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	var req auth.RegisterRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
 	user, err := h.authService.Register(r.Context(), &req)
 	if err != nil {
-		if err == domain.ErrUserAlreadyExists {
-			respondError(w, http.StatusConflict, "User already exists")
+		if errors.Is(err, domain.ErrInvalidPhone) {
+			respondDomainFieldError(w, err, "phone", "Invalid phone number: expected E.164, e.g. +79991234567")
 			return
 		}
-		h.logger.Error("Failed to register user", map[string]interface{}{
-			"error": err.Error(),
-		})
-		respondError(w, http.StatusInternalServerError, "Failed to register user")
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to register user", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to register user")
+		}
 		return
 	}
 
@@ -67,25 +75,18 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 // POST /api/v1/auth/login
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var req auth.LoginRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
 	response, err := h.authService.Login(r.Context(), &req)
 	if err != nil {
-		if err == domain.ErrInvalidCredentials {
-			respondError(w, http.StatusUnauthorized, "Invalid credentials")
-			return
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to login user", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to login")
 		}
-		if err == domain.ErrUserInactive {
-			respondError(w, http.StatusForbidden, "User account is inactive")
-			return
-		}
-		h.logger.Error("Failed to login user", map[string]interface{}{
-			"error": err.Error(),
-		})
-		respondError(w, http.StatusInternalServerError, "Failed to login")
 		return
 	}
 
@@ -107,14 +108,12 @@ func (h *AuthHandler) GetMe(w http.ResponseWriter, r *http.Request) {
 
 	user, err := h.authService.GetUserByID(r.Context(), claims.UserID)
 	if err != nil {
-		if err == domain.ErrUserNotFound {
-			respondError(w, http.StatusNotFound, "User not found")
-			return
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to get user", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to get user")
 		}
-		h.logger.Error("Failed to get user", map[string]interface{}{
-			"error": err.Error(),
-		})
-		respondError(w, http.StatusInternalServerError, "Failed to get user")
 		return
 	}
 
@@ -128,29 +127,31 @@ func (h *AuthHandler) GetMe(w http.ResponseWriter, r *http.Request) {
 // POST /api/v1/auth/refresh
 func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	var req auth.RefreshTokenRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
 	response, err := h.authService.RefreshToken(r.Context(), &req)
 	if err != nil {
-		if err == domain.ErrInvalidToken {
-			respondError(w, http.StatusUnauthorized, "Invalid refresh token")
+		if errors.Is(err, domain.ErrInvalidToken) {
+			respondDomainErrorMessage(w, err, "Invalid refresh token")
 			return
 		}
-		if err == domain.ErrUserNotFound {
-			respondError(w, http.StatusUnauthorized, "User not found")
+		if errors.Is(err, domain.ErrTokenExpired) {
+			respondDomainErrorMessage(w, err, "Refresh token expired")
 			return
 		}
-		if err == domain.ErrUserInactive {
-			respondError(w, http.StatusForbidden, "User account is inactive")
+		if errors.Is(err, domain.ErrUserNotFound) {
+			// Владелец токена удален - для клиента это недействительная сессия, а не 404
+			respondError(w, http.StatusUnauthorized, "User not found")
 			return
 		}
-		h.logger.Error("Failed to refresh token", map[string]interface{}{
-			"error": err.Error(),
-		})
-		respondError(w, http.StatusInternalServerError, "Failed to refresh token")
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to refresh token", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to refresh token")
+		}
 		return
 	}
 
@@ -164,26 +165,204 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 // POST /api/v1/auth/logout
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	var req auth.LogoutRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
+	// Access token необязателен: если передан, он отзывается вместе с refresh token
+	req.AccessToken, _ = middleware.BearerToken(r)
+
 	err := h.authService.Logout(r.Context(), &req)
 	if err != nil {
-		if err == domain.ErrInvalidToken {
-			respondError(w, http.StatusUnauthorized, "Invalid refresh token")
+		if errors.Is(err, domain.ErrInvalidToken) {
+			respondDomainErrorMessage(w, err, "Invalid refresh token")
+			return
+		}
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to logout", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to logout")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "Logged out successfully",
+	})
+}
+
+// ListSessions возвращает активные сессии текущего пользователя
+// GET /api/v1/auth/sessions
+func (h *AuthHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserClaims(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	sessions, err := h.authService.ListSessions(r.Context(), claims.UserID)
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to list sessions", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to list sessions")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    sessions,
+	})
+}
+
+// RevokeSession завершает одну сессию текущего пользователя
+// DELETE /api/v1/auth/sessions/{id}
+func (h *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserClaims(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	sessionID, err := uuid.Parse(getPathParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	if err := h.authService.RevokeSession(r.Context(), claims.UserID, sessionID); err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to revoke session", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to revoke session")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "Session revoked successfully",
+	})
+}
+
+// LogoutAll завершает все сессии текущего пользователя
+// DELETE /api/v1/auth/sessions
+func (h *AuthHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserClaims(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.authService.RevokeAllSessions(r.Context(), claims.UserID); err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to revoke all sessions", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to logout")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "Logged out from all sessions successfully",
+	})
+}
+
+// ChangePassword меняет пароль текущего пользователя
+// POST /api/v1/auth/change-password
+func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserClaims(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req auth.ChangePasswordRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	if err := h.authService.ChangePassword(r.Context(), claims.UserID, &req); err != nil {
+		if errors.Is(err, domain.ErrInvalidPassword) {
+			respondDomainFieldError(w, err, "old_password", "Current password is incorrect")
 			return
 		}
-		h.logger.Error("Failed to logout", map[string]interface{}{
+		if errors.Is(err, domain.ErrWeakPassword) {
+			respondDomainFieldError(w, err, "new_password", fmt.Sprintf("Password must be at least %d characters", domain.MinPasswordLength))
+			return
+		}
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to change password", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to change password")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "Password changed successfully",
+	})
+}
+
+// ForgotPassword запрашивает токен сброса пароля
+// Ответ не зависит от того, зарегистрирован ли email (защита от перебора адресов)
+// POST /api/v1/auth/forgot-password
+func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req auth.ForgotPasswordRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	if err := h.authService.ForgotPassword(r.Context(), &req); err != nil {
+		if errors.Is(err, domain.ErrPasswordResetUnavailable) {
+			respondDomainError(w, err)
+			return
+		}
+		// Ошибка только логируется: иной ответ выдал бы существование пользователя
+		requestLogger(r, h.logger).Error("Failed to process password reset request", map[string]interface{}{
 			"error": err.Error(),
 		})
-		respondError(w, http.StatusInternalServerError, "Failed to logout")
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "If the email is registered, password reset instructions have been sent",
+	})
+}
+
+// ResetPassword устанавливает новый пароль по токену сброса
+// POST /api/v1/auth/reset-password
+func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req auth.ResetPasswordRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	if err := h.authService.ResetPassword(r.Context(), &req); err != nil {
+		if errors.Is(err, domain.ErrWeakPassword) {
+			respondDomainFieldError(w, err, "new_password", fmt.Sprintf("Password must be at least %d characters", domain.MinPasswordLength))
+			return
+		}
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to reset password", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to reset password")
+		}
 		return
 	}
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
 		"success": true,
-		"message": "Logged out successfully",
+		"message": "Password reset successfully",
 	})
 }
@@ -2,17 +2,23 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/frontandrew/gate/internal/domain"
 	"github.com/frontandrew/gate/internal/pkg/logger"
 	"github.com/frontandrew/gate/internal/usecase/auth"
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // TestAuthHandler_Register тестирует регистрацию пользователя
@@ -66,12 +72,26 @@ func TestAuthHandler_Register(t *testing.T) {
 			},
 			expectedStatus: http.StatusConflict,
 			checkResponse: func(t *testing.T, resp map[string]interface{}) {
-				if success, ok := resp["success"].(bool); ok {
-					assert.False(t, success)
-				}
-				if err, ok := resp["error"].(string); ok {
-					assert.Contains(t, err, "already exists")
-				}
+				AssertErrorCode(t, resp, "USER_ALREADY_EXISTS")
+				assert.Contains(t, ErrorDetail(t, resp)["message"], "already exists")
+			},
+		},
+		{
+			name: "некорректный телефон",
+			requestBody: auth.RegisterRequest{
+				Email:    "test@example.com",
+				Password: "password123",
+				FullName: "Test User",
+				Phone:    "12-34",
+			},
+			mockSetup: func(m *MockAuthService) {
+				m.On("Register", mock.Anything, mock.AnythingOfType("*auth.RegisterRequest")).
+					Return(nil, domain.ErrInvalidPhone)
+			},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp map[string]interface{}) {
+				AssertErrorCode(t, resp, "INVALID_PHONE")
+				assert.Equal(t, "phone", ErrorDetail(t, resp)["field"])
 			},
 		},
 		{
@@ -80,9 +100,42 @@ func TestAuthHandler_Register(t *testing.T) {
 			mockSetup:      func(m *MockAuthService) {},
 			expectedStatus: http.StatusBadRequest,
 			checkResponse: func(t *testing.T, resp map[string]interface{}) {
-				if success, ok := resp["success"].(bool); ok {
-					assert.False(t, success)
-				}
+				AssertErrorCode(t, resp, "BAD_REQUEST")
+			},
+		},
+		{
+			name:           "неизвестное поле",
+			requestBody:    `{"email":"test@example.com","password":"password123","full_name":"Test User","is_admin":true}`,
+			mockSetup:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp map[string]interface{}) {
+				AssertErrorCode(t, resp, "VALIDATION_FAILED")
+				assert.Equal(t, "is_admin", ErrorDetail(t, resp)["field"])
+			},
+		},
+		{
+			name: "не указан email",
+			requestBody: auth.RegisterRequest{
+				Password: "password123",
+				FullName: "Test User",
+			},
+			mockSetup:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp map[string]interface{}) {
+				AssertFieldErrors(t, resp, "email")
+			},
+		},
+		{
+			name: "короткий пароль и некорректный email",
+			requestBody: auth.RegisterRequest{
+				Email:    "not-an-email",
+				Password: "short",
+				FullName: "Test User",
+			},
+			mockSetup:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp map[string]interface{}) {
+				AssertFieldErrors(t, resp, "email", "password")
 			},
 		},
 	}
@@ -370,6 +423,21 @@ func TestAuthHandler_RefreshToken(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "обернутая ошибка - пользователь не найден",
+			requestBody: auth.RefreshTokenRequest{
+				RefreshToken: "orphaned_token",
+			},
+			mockSetup: func(m *MockAuthService) {
+				m.On("RefreshToken", mock.Anything, mock.AnythingOfType("*auth.RefreshTokenRequest")).
+					Return(nil, fmt.Errorf("failed to get user: %w", domain.ErrUserNotFound))
+			},
+			expectedStatus: http.StatusUnauthorized,
+			checkResponse: func(t *testing.T, resp map[string]interface{}) {
+				errBody := resp["error"].(map[string]interface{})
+				assert.Equal(t, "User not found", errBody["message"])
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -397,3 +465,236 @@ func TestAuthHandler_RefreshToken(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthHandler_ListSessions(t *testing.T) {
+	userID := uuid.New()
+	mockService := new(MockAuthService)
+	mockService.On("ListSessions", mock.Anything, userID).Return([]*auth.Session{
+		{ID: uuid.New(), Identifier: "a1b2c3d4", CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour)},
+	}, nil)
+
+	handler := NewAuthHandler(mockService, logger.NewNoop())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/sessions", nil)
+	req = req.WithContext(CreateAuthContext(t, userID, "user@test.com", domain.RoleUser))
+	w := httptest.NewRecorder()
+	handler.ListSessions(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "token_hash")
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	data, ok := response["data"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, data, 1)
+	assert.Equal(t, "a1b2c3d4", data[0].(map[string]interface{})["identifier"])
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthHandler_RevokeSession(t *testing.T) {
+	userID := uuid.New()
+	sessionID := uuid.New()
+
+	tests := []struct {
+		name           string
+		sessionParam   string
+		mockSetup      func(*MockAuthService)
+		expectedStatus int
+	}{
+		{
+			name:         "сессия отозвана",
+			sessionParam: sessionID.String(),
+			mockSetup: func(m *MockAuthService) {
+				m.On("RevokeSession", mock.Anything, userID, sessionID).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "невалидный ID",
+			sessionParam:   "not-a-uuid",
+			mockSetup:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "сессия не найдена",
+			sessionParam: sessionID.String(),
+			mockSetup: func(m *MockAuthService) {
+				m.On("RevokeSession", mock.Anything, userID, sessionID).Return(domain.ErrRefreshTokenNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAuthService)
+			tt.mockSetup(mockService)
+
+			handler := NewAuthHandler(mockService, logger.NewNoop())
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.sessionParam)
+			ctx := CreateAuthContext(t, userID, "user@test.com", domain.RoleUser)
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/auth/sessions/"+tt.sessionParam, nil)
+			req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+			handler.RevokeSession(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthHandler_LogoutAll(t *testing.T) {
+	userID := uuid.New()
+	mockService := new(MockAuthService)
+	mockService.On("RevokeAllSessions", mock.Anything, userID).Return(nil)
+
+	handler := NewAuthHandler(mockService, logger.NewNoop())
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/auth/sessions", nil)
+	req = req.WithContext(CreateAuthContext(t, userID, "user@test.com", domain.RoleUser))
+	w := httptest.NewRecorder()
+	handler.LogoutAll(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthHandler_ChangePassword(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name           string
+		body           string
+		mockErr        error
+		skipService    bool // Запрос отклоняется валидацией до вызова сервиса
+		expectedStatus int
+		expectedField  string
+	}{
+		{
+			name:           "пароль изменен",
+			body:           `{"old_password":"old-password","new_password":"new-password"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "неверный текущий пароль",
+			body:           `{"old_password":"wrong","new_password":"new-password"}`,
+			mockErr:        domain.ErrInvalidPassword,
+			expectedStatus: http.StatusBadRequest,
+			expectedField:  "old_password",
+		},
+		{
+			name:           "слабый новый пароль",
+			body:           `{"old_password":"old-password","new_password":"short"}`,
+			skipService:    true,
+			expectedStatus: http.StatusBadRequest,
+			expectedField:  "new_password",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAuthService)
+			if !tt.skipService {
+				mockService.On("ChangePassword", mock.Anything, userID, mock.AnythingOfType("*auth.ChangePasswordRequest")).Return(tt.mockErr)
+			}
+
+			handler := NewAuthHandler(mockService, logger.NewNoop())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/change-password", bytes.NewBufferString(tt.body))
+			req = req.WithContext(CreateAuthContext(t, userID, "user@test.com", domain.RoleUser))
+			w := httptest.NewRecorder()
+			handler.ChangePassword(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedField != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedField)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthHandler_ForgotPassword(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "токен отправлен",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "внутренняя ошибка не раскрывается",
+			mockErr:        errors.New("smtp unavailable"),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "сброс пароля без Redis недоступен",
+			mockErr:        domain.ErrPasswordResetUnavailable,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAuthService)
+			mockService.On("ForgotPassword", mock.Anything, mock.MatchedBy(func(req *auth.ForgotPasswordRequest) bool {
+				return req.Email == "user@test.com"
+			})).Return(tt.mockErr)
+
+			handler := NewAuthHandler(mockService, logger.NewNoop())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/forgot-password", bytes.NewBufferString(`{"email":"user@test.com"}`))
+			w := httptest.NewRecorder()
+			handler.ForgotPassword(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthHandler_ResetPassword(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "пароль сброшен",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "истекший или использованный токен",
+			mockErr:        domain.ErrInvalidResetToken,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "слабый пароль",
+			mockErr:        domain.ErrWeakPassword,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAuthService)
+			mockService.On("ResetPassword", mock.Anything, mock.AnythingOfType("*auth.ResetPasswordRequest")).Return(tt.mockErr)
+
+			handler := NewAuthHandler(mockService, logger.NewNoop())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/reset-password",
+				bytes.NewBufferString(`{"token":"reset-token","new_password":"new-password"}`))
+			w := httptest.NewRecorder()
+			handler.ResetPassword(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
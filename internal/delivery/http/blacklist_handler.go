@@ -0,0 +1,231 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/frontandrew/gate/internal/delivery/http/middleware"
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/usecase/blacklist"
+	"github.com/google/uuid"
+)
+
+// BlacklistService определяет интерфейс для сервиса черного списка
+type BlacklistService interface {
+	CreateEntry(ctx context.Context, req *blacklist.CreateEntryRequest) (*domain.BlacklistEntry, error)
+	CreateEntries(ctx context.Context, reqs []blacklist.CreateEntryRequest, addedBy uuid.UUID) (*blacklist.BulkResult, error)
+	ListEntries(ctx context.Context, limit, offset int) ([]*domain.BlacklistEntry, error)
+	GetEntry(ctx context.Context, id uuid.UUID) (*domain.BlacklistEntry, error)
+	UpdateEntry(ctx context.Context, id uuid.UUID, req *blacklist.UpdateEntryRequest) (*domain.BlacklistEntry, error)
+	DeleteEntry(ctx context.Context, id, deletedBy uuid.UUID) error
+}
+
+// BlacklistHandler обрабатывает запросы управления черным списком
+type BlacklistHandler struct {
+	blacklistService BlacklistService
+	logger           logger.Logger
+}
+
+// NewBlacklistHandler создает новый handler
+func NewBlacklistHandler(blacklistService BlacklistService, logger logger.Logger) *BlacklistHandler {
+	return &BlacklistHandler{
+		blacklistService: blacklistService,
+		logger:           logger,
+	}
+}
+
+// CreateEntry добавляет номер в черный список (админы и охрана)
+// POST /api/v1/blacklist
+func (h *BlacklistHandler) CreateEntry(w http.ResponseWriter, r *http.Request) {
+	var req blacklist.CreateEntryRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	claims, ok := middleware.GetUserClaims(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	req.AddedBy = claims.UserID
+
+	entry, err := h.blacklistService.CreateEntry(r.Context(), &req)
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to create blacklist entry", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to create blacklist entry")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"success": true,
+		"data":    entry,
+	})
+}
+
+// BulkCreate добавляет пакет номеров в черный список (админы и охрана)
+// Тело - JSON-массив записей или CSV (license_plate,reason,expires_at); итог возвращается по каждой строке
+// POST /api/v1/blacklist/bulk
+func (h *BlacklistHandler) BulkCreate(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserClaims(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	reqs, ok := readBulkListRequest(w, r, blacklist.MaxBulkEntries, func(row bulkListRow) blacklist.CreateEntryRequest {
+		return blacklist.CreateEntryRequest{
+			LicensePlate: row.LicensePlate,
+			Reason:       row.Reason,
+			ExpiresAt:    row.ExpiresAt,
+			Zone:         row.Zone,
+		}
+	})
+	if !ok {
+		return
+	}
+
+	result, err := h.blacklistService.CreateEntries(r.Context(), reqs, claims.UserID)
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to bulk create blacklist entries", map[string]interface{}{
+				"entries": len(reqs),
+				"error":   err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to create blacklist entries")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// ListEntries возвращает записи черного списка (админы и охрана)
+// GET /api/v1/blacklist?limit=&offset=
+func (h *BlacklistHandler) ListEntries(w http.ResponseWriter, r *http.Request) {
+	limit, offset := getPaginationParams(r)
+
+	entries, err := h.blacklistService.ListEntries(r.Context(), limit, offset)
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to list blacklist entries", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to list blacklist entries")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    entries,
+		"pagination": map[string]int{
+			"limit":  limit,
+			"offset": offset,
+		},
+	})
+}
+
+// GetEntry возвращает запись черного списка по ID (админы и охрана)
+// GET /api/v1/blacklist/{id}
+func (h *BlacklistHandler) GetEntry(w http.ResponseWriter, r *http.Request) {
+	entryID, err := uuid.Parse(getPathParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid blacklist entry ID")
+		return
+	}
+
+	entry, err := h.blacklistService.GetEntry(r.Context(), entryID)
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to get blacklist entry", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to get blacklist entry")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    entry,
+	})
+}
+
+// UpdateEntry изменяет запись черного списка (админы и охрана)
+// PUT /api/v1/blacklist/{id}
+func (h *BlacklistHandler) UpdateEntry(w http.ResponseWriter, r *http.Request) {
+	entryID, err := uuid.Parse(getPathParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid blacklist entry ID")
+		return
+	}
+
+	var req blacklist.UpdateEntryRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	claims, ok := middleware.GetUserClaims(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	req.UpdatedBy = claims.UserID
+
+	entry, err := h.blacklistService.UpdateEntry(r.Context(), entryID, &req)
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to update blacklist entry", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to update blacklist entry")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    entry,
+	})
+}
+
+// DeleteEntry удаляет запись из черного списка (админы и охрана)
+// DELETE /api/v1/blacklist/{id}
+func (h *BlacklistHandler) DeleteEntry(w http.ResponseWriter, r *http.Request) {
+	entryID, err := uuid.Parse(getPathParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid blacklist entry ID")
+		return
+	}
+
+	claims, ok := middleware.GetUserClaims(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.blacklistService.DeleteEntry(r.Context(), entryID, claims.UserID); err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to delete blacklist entry", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to delete blacklist entry")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "Blacklist entry deleted successfully",
+	})
+}
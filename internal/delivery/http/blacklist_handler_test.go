@@ -0,0 +1,271 @@
+package http
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/usecase/blacklist"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBlacklistHandler_CreateEntry(t *testing.T) {
+	guardID := uuid.New()
+	expiresAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func(*MockBlacklistService)
+		expectedStatus int
+	}{
+		{
+			name: "запись создана, added_by берется из claims",
+			body: `{"license_plate":"A001AA777","reason":"Угнан","expires_at":"` + expiresAt.Format(time.RFC3339) + `"}`,
+			mockSetup: func(m *MockBlacklistService) {
+				m.On("CreateEntry", mock.Anything, mock.MatchedBy(func(req *blacklist.CreateEntryRequest) bool {
+					return req.AddedBy == guardID && req.LicensePlate == "A001AA777" &&
+						req.ExpiresAt != nil && req.ExpiresAt.Equal(expiresAt)
+				})).Return(&domain.BlacklistEntry{ID: uuid.New(), LicensePlate: "A001AA777", IsActive: true}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "невалидное тело запроса",
+			body:           `{invalid`,
+			mockSetup:      func(m *MockBlacklistService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "срок действия в прошлом",
+			body: `{"license_plate":"A001AA777","reason":"Угнан","expires_at":"2020-01-01T00:00:00Z"}`,
+			mockSetup: func(m *MockBlacklistService) {
+				m.On("CreateEntry", mock.Anything, mock.Anything).Return(nil, domain.ErrExpiryInPast)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "номер уже в черном списке",
+			body: `{"license_plate":"A001AA777","reason":"Угнан"}`,
+			mockSetup: func(m *MockBlacklistService) {
+				m.On("CreateEntry", mock.Anything, mock.Anything).Return(nil, domain.ErrBlacklistEntryAlreadyExists)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockBlacklistService)
+			tt.mockSetup(mockService)
+
+			handler := NewBlacklistHandler(mockService, logger.NewNoop())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/blacklist", bytes.NewBufferString(tt.body))
+			req = req.WithContext(CreateAuthContext(t, guardID, "guard@test.com", domain.RoleGuard))
+			w := httptest.NewRecorder()
+			handler.CreateEntry(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBlacklistHandler_UpdateEntry(t *testing.T) {
+	guardID := uuid.New()
+	entryID := uuid.New()
+
+	mockService := new(MockBlacklistService)
+	mockService.On("UpdateEntry", mock.Anything, entryID, mock.MatchedBy(func(req *blacklist.UpdateEntryRequest) bool {
+		return req.UpdatedBy == guardID && req.ClearExpiry
+	})).Return(&domain.BlacklistEntry{ID: entryID, LicensePlate: "A001AA777", IsActive: true}, nil)
+
+	handler := NewBlacklistHandler(mockService, logger.NewNoop())
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/blacklist/"+entryID.String(), bytes.NewBufferString(`{"clear_expiry":true}`))
+	req = withEntryID(t, req, guardID, entryID.String())
+	w := httptest.NewRecorder()
+	handler.UpdateEntry(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestBlacklistHandler_DeleteEntry(t *testing.T) {
+	adminID := uuid.New()
+	entryID := uuid.New()
+
+	tests := []struct {
+		name           string
+		entryParam     string
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "запись удалена",
+			entryParam:     entryID.String(),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "запись не найдена",
+			entryParam:     entryID.String(),
+			mockErr:        domain.ErrBlacklistEntryNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "невалидный ID",
+			entryParam:     "not-a-uuid",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockBlacklistService)
+			if tt.expectedStatus != http.StatusBadRequest {
+				mockService.On("DeleteEntry", mock.Anything, entryID, adminID).Return(tt.mockErr)
+			}
+
+			handler := NewBlacklistHandler(mockService, logger.NewNoop())
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/blacklist/"+tt.entryParam, nil)
+			req = withEntryID(t, req, adminID, tt.entryParam)
+			w := httptest.NewRecorder()
+			handler.DeleteEntry(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBlacklistHandler_BulkCreate(t *testing.T) {
+	guardID := uuid.New()
+	expiresAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	result := &blacklist.BulkResult{Created: 1, Failed: 1, Results: []blacklist.BulkEntryResult{
+		{Row: 1, LicensePlate: "A001AA777", Success: true},
+		{Row: 2, LicensePlate: "B002BB777", Error: domain.ErrBlacklistEntryAlreadyExists.Error()},
+	}}
+	csvBody := "license_plate,reason,expires_at\n" +
+		"A001AA777,Угнан," + expiresAt.Format(time.RFC3339) + "\n" +
+		"B002BB777,\"Долг, парковка\",\n"
+
+	multipartBody := func() (string, string) {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		part, _ := mw.CreateFormFile("file", "plates.csv")
+		_, _ = part.Write([]byte(csvBody))
+		_ = mw.Close()
+		return buf.String(), mw.FormDataContentType()
+	}
+	uploadBody, uploadContentType := multipartBody()
+
+	// csvRows проверяет, что строки CSV разобраны по колонкам заголовка
+	csvRows := mock.MatchedBy(func(reqs []blacklist.CreateEntryRequest) bool {
+		return len(reqs) == 2 &&
+			reqs[0].LicensePlate == "A001AA777" && reqs[0].ExpiresAt != nil && reqs[0].ExpiresAt.Equal(expiresAt) &&
+			reqs[1].Reason == "Долг, парковка" && reqs[1].ExpiresAt == nil
+	})
+
+	tests := []struct {
+		name           string
+		contentType    string
+		body           string
+		mockSetup      func(*MockBlacklistService)
+		expectedStatus int
+	}{
+		{
+			name:        "JSON-массив",
+			contentType: "application/json",
+			body:        `[{"license_plate":"A001AA777","reason":"Угнан"},{"license_plate":"B002BB777","reason":"Долг"}]`,
+			mockSetup: func(m *MockBlacklistService) {
+				m.On("CreateEntries", mock.Anything, mock.MatchedBy(func(reqs []blacklist.CreateEntryRequest) bool {
+					return len(reqs) == 2 && reqs[1].LicensePlate == "B002BB777"
+				}), guardID).Return(result, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "CSV в теле запроса",
+			contentType: "text/csv; charset=utf-8",
+			body:        csvBody,
+			mockSetup: func(m *MockBlacklistService) {
+				m.On("CreateEntries", mock.Anything, csvRows, guardID).Return(result, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "CSV-файл в multipart",
+			contentType: uploadContentType,
+			body:        uploadBody,
+			mockSetup: func(m *MockBlacklistService) {
+				m.On("CreateEntries", mock.Anything, csvRows, guardID).Return(result, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "CSV без обязательной колонки",
+			contentType:    "text/csv",
+			body:           "license_plate\nA001AA777\n",
+			mockSetup:      func(m *MockBlacklistService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "CSV с некорректной датой",
+			contentType:    "text/csv",
+			body:           "license_plate,reason,expires_at\nA001AA777,Угнан,завтра\n",
+			mockSetup:      func(m *MockBlacklistService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "пустой массив",
+			contentType:    "application/json",
+			body:           `[]`,
+			mockSetup:      func(m *MockBlacklistService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "слишком много строк",
+			contentType:    "text/csv",
+			body:           "license_plate,reason\n" + strings.Repeat("A001AA777,Угнан\n", blacklist.MaxBulkEntries+1),
+			mockSetup:      func(m *MockBlacklistService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "сбой транзакции",
+			contentType: "application/json",
+			body:        `[{"license_plate":"A001AA777","reason":"Угнан"}]`,
+			mockSetup: func(m *MockBlacklistService) {
+				m.On("CreateEntries", mock.Anything, mock.Anything, guardID).Return(nil, assert.AnError)
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockBlacklistService)
+			tt.mockSetup(mockService)
+
+			handler := NewBlacklistHandler(mockService, logger.NewNoop())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/blacklist/bulk", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			req = req.WithContext(CreateAuthContext(t, guardID, "guard@test.com", domain.RoleGuard))
+			w := httptest.NewRecorder()
+			handler.BulkCreate(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
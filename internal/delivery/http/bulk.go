@@ -0,0 +1,144 @@
+package http
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// bulkListRow - строка CSV для пакетного добавления в белый или черный список
+type bulkListRow struct {
+	LicensePlate string
+	Reason       string
+	ExpiresAt    *time.Time
+	Zone         *string
+}
+
+// readBulkListRequest разбирает тело пакетного добавления в список
+// Принимается JSON-массив строк, CSV в теле (text/csv) или CSV-файл в поле file (multipart/form-data).
+// Строки CSV преобразуются в T через fromCSV. При ошибке сам отправляет ответ и возвращает false
+func readBulkListRequest[T any](w http.ResponseWriter, r *http.Request, maxEntries int, fromCSV func(bulkListRow) T) ([]T, bool) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	var reqs []T
+	switch mediaType {
+	case "text/csv", "multipart/form-data":
+		body := io.Reader(r.Body)
+		if mediaType == "multipart/form-data" {
+			file, _, err := r.FormFile("file")
+			if err != nil {
+				if !respondBodyTooLarge(w, err) {
+					respondFieldError(w, http.StatusBadRequest, "file", "CSV file is required")
+				}
+				return nil, false
+			}
+			defer file.Close()
+			body = file
+		}
+
+		rows, err := readBulkListCSV(body)
+		if err != nil {
+			if !respondBodyTooLarge(w, err) {
+				respondError(w, http.StatusBadRequest, err.Error())
+			}
+			return nil, false
+		}
+		for _, row := range rows {
+			reqs = append(reqs, fromCSV(row))
+		}
+	default:
+		if !decodeJSON(w, r, &reqs) {
+			return nil, false
+		}
+	}
+
+	if len(reqs) == 0 {
+		respondError(w, http.StatusBadRequest, "At least one entry is required")
+		return nil, false
+	}
+	if len(reqs) > maxEntries {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("Too many entries: at most %d per request", maxEntries))
+		return nil, false
+	}
+
+	return reqs, true
+}
+
+// readBulkListCSV читает CSV с заголовком: license_plate, reason и необязательные expires_at (RFC 3339) и zone
+// Порядок колонок произвольный, лишние колонки игнорируются
+func readBulkListCSV(body io.Reader) ([]bulkListRow, error) {
+	cr := csv.NewReader(body)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("CSV is empty")
+		}
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"license_plate", "reason"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV header must contain %s column", required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []bulkListRow
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		row := bulkListRow{
+			LicensePlate: field(record, "license_plate"),
+			Reason:       field(record, "reason"),
+		}
+		if value := field(record, "expires_at"); value != "" {
+			expiresAt, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				line, _ := cr.FieldPos(columns["expires_at"])
+				return nil, fmt.Errorf("invalid expires_at on CSV line %d: expected RFC 3339", line)
+			}
+			row.ExpiresAt = &expiresAt
+		}
+		if value := field(record, "zone"); value != "" {
+			row.Zone = &value
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// respondBodyTooLarge отправляет 413, если err вызван превышением лимита тела запроса
+func respondBodyTooLarge(w http.ResponseWriter, err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	if !errors.As(err, &maxBytesErr) {
+		return false
+	}
+	respondError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body exceeds %d bytes", maxBytesErr.Limit))
+	return true
+}
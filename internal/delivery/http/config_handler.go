@@ -0,0 +1,112 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/config"
+)
+
+// PublicConfig - несекретная часть конфигурации для frontend
+// Поля добавляются сюда явно: секреты (JWT, пароли БД/Redis, SMTP) не должны попадать в ответ
+type PublicConfig struct {
+	Pagination   PublicPaginationConfig   `json:"pagination"`
+	Registration PublicRegistrationConfig `json:"registration"`
+	CORS         PublicCORSConfig         `json:"cors"`
+	Access       PublicAccessConfig       `json:"access"`
+	Passes       PublicPassConfig         `json:"passes"`
+	Vehicles     PublicVehicleConfig      `json:"vehicles"`
+}
+
+// PublicPaginationConfig - ограничения пагинации списков
+type PublicPaginationConfig struct {
+	DefaultLimit int `json:"default_limit"`
+	MaxLimit     int `json:"max_limit"`
+}
+
+// PublicRegistrationConfig - настройки самостоятельной регистрации
+type PublicRegistrationConfig struct {
+	Open               bool   `json:"open"`
+	PhoneDefaultRegion string `json:"phone_default_region"`
+	PhoneRequiredE164  bool   `json:"phone_required_e164"`
+}
+
+// PublicCORSConfig - политика CORS
+type PublicCORSConfig struct {
+	AllowedOrigins []string `json:"allowed_origins"`
+	AllowedMethods []string `json:"allowed_methods"`
+	AllowedHeaders []string `json:"allowed_headers"`
+}
+
+// PublicAccessConfig - параметры проверки доступа
+type PublicAccessConfig struct {
+	Directions    []domain.Direction `json:"directions"`
+	Gates         []string           `json:"gates"`
+	MinConfidence float64            `json:"min_confidence"`
+}
+
+// PublicPassConfig - ограничения выдачи пропусков
+type PublicPassConfig struct {
+	GuestDailyLimit          int  `json:"guest_daily_limit"`
+	GuestPassDurationSeconds int  `json:"guest_pass_duration_seconds"`
+	RequireValidFrom         bool `json:"require_valid_from"`
+}
+
+// PublicVehicleConfig - формат номеров автомобилей (для проверки ввода на клиенте)
+type PublicVehicleConfig struct {
+	PlateCountry string `json:"plate_country"`
+}
+
+// ConfigHandler отдает публичную конфигурацию
+type ConfigHandler struct {
+	public PublicConfig
+}
+
+// NewConfigHandler создает handler; публичная конфигурация собирается один раз
+func NewConfigHandler(cfg *config.Config) *ConfigHandler {
+	gates := cfg.Access.Gates
+	if gates == nil {
+		gates = []string{}
+	}
+
+	return &ConfigHandler{
+		public: PublicConfig{
+			Pagination: PublicPaginationConfig{
+				DefaultLimit: defaultPageSize,
+				MaxLimit:     maxPageSize,
+			},
+			Registration: PublicRegistrationConfig{
+				Open:               cfg.Auth.RegistrationOpen,
+				PhoneDefaultRegion: cfg.Auth.PhoneDefaultRegion,
+				PhoneRequiredE164:  cfg.Auth.SMSEnabled,
+			},
+			CORS: PublicCORSConfig{
+				AllowedOrigins: cfg.CORS.AllowedOrigins,
+				AllowedMethods: cfg.CORS.AllowedMethods,
+				AllowedHeaders: cfg.CORS.AllowedHeaders,
+			},
+			Access: PublicAccessConfig{
+				Directions:    []domain.Direction{domain.DirectionIn, domain.DirectionOut},
+				Gates:         gates,
+				MinConfidence: cfg.ML.MinConfidence,
+			},
+			Passes: PublicPassConfig{
+				GuestDailyLimit:          cfg.Pass.GuestDailyLimit,
+				GuestPassDurationSeconds: int(cfg.Pass.GuestPassDuration.Seconds()),
+				RequireValidFrom:         cfg.Pass.RequireValidFrom,
+			},
+			Vehicles: PublicVehicleConfig{
+				PlateCountry: cfg.Vehicle.PlateCountry,
+			},
+		},
+	}
+}
+
+// GetConfig возвращает публичную конфигурацию
+// GET /api/v1/config
+func (h *ConfigHandler) GetConfig(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    h.public,
+	})
+}
@@ -0,0 +1,58 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/frontandrew/gate/internal/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigHandler_GetConfig(t *testing.T) {
+	cfg := &config.Config{
+		Database: config.DatabaseConfig{Password: "db-secret-password"},
+		Redis:    config.RedisConfig{Password: "redis-secret-password"},
+		JWT:      config.JWTConfig{SecretKey: "jwt-secret-key"},
+		Auth:     config.AuthConfig{RegistrationOpen: true, PhoneDefaultRegion: "RU", SMSEnabled: true},
+		ML:       config.MLConfig{ServiceURL: "http://ml.internal:8001", MinConfidence: 0.7},
+		CORS: config.CORSConfig{
+			AllowedOrigins: []string{"https://gate.example.com"},
+			AllowedMethods: []string{"GET", "POST"},
+			AllowedHeaders: []string{"Authorization"},
+		},
+		Pass:    config.PassConfig{GuestDailyLimit: 3, GuestPassDuration: 24 * time.Hour},
+		Access:  config.AccessConfig{Gates: []string{"gate-1", "gate-2"}},
+		Vehicle: config.VehicleConfig{PlateCountry: "RU"},
+	}
+
+	handler := NewConfigHandler(cfg)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/config", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetConfig(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	body := w.Body.String()
+	for _, secret := range []string{"db-secret-password", "redis-secret-password", "jwt-secret-key", "ml.internal"} {
+		assert.NotContains(t, body, secret)
+	}
+
+	var resp struct {
+		Data PublicConfig `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	assert.Equal(t, PublicPaginationConfig{DefaultLimit: 50, MaxLimit: 100}, resp.Data.Pagination)
+	assert.True(t, resp.Data.Registration.Open)
+	assert.Equal(t, "RU", resp.Data.Registration.PhoneDefaultRegion)
+	assert.Equal(t, []string{"https://gate.example.com"}, resp.Data.CORS.AllowedOrigins)
+	assert.Len(t, resp.Data.Access.Directions, 2)
+	assert.Equal(t, []string{"gate-1", "gate-2"}, resp.Data.Access.Gates)
+	assert.Equal(t, 86400, resp.Data.Passes.GuestPassDurationSeconds)
+	assert.Equal(t, "RU", resp.Data.Vehicles.PlateCountry)
+}
@@ -0,0 +1,183 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/frontandrew/gate/internal/delivery/http/apierror"
+	"github.com/frontandrew/gate/internal/domain"
+)
+
+// domainErrorStatus - HTTP статус доменной ошибки
+type domainErrorStatus struct {
+	err    error
+	status int
+}
+
+// domainErrorStatuses - единое соответствие доменных ошибок HTTP статусам для всех обработчиков
+// Ошибки, которых нет в списке (в том числе domain.ErrInternal), считаются внутренними - 500
+var domainErrorStatuses = []domainErrorStatus{
+	// 400 - некорректные данные запроса
+	{domain.ErrBadRequest, http.StatusBadRequest},
+	{domain.ErrInvalidEmail, http.StatusBadRequest},
+	{domain.ErrInvalidPassword, http.StatusBadRequest},
+	{domain.ErrWeakPassword, http.StatusBadRequest},
+	{domain.ErrInvalidUserData, http.StatusBadRequest},
+	{domain.ErrInvalidRole, http.StatusBadRequest},
+	{domain.ErrInvalidPhone, http.StatusBadRequest},
+	{domain.ErrInvalidLicensePlate, http.StatusBadRequest},
+	{domain.ErrInvalidVehicleData, http.StatusBadRequest},
+	{domain.ErrVehicleInactive, http.StatusBadRequest},
+	{domain.ErrPlateQueryTooShort, http.StatusBadRequest},
+	{domain.ErrInvalidPassData, http.StatusBadRequest},
+	{domain.ErrInvalidPassType, http.StatusBadRequest},
+	{domain.ErrInvalidDateRange, http.StatusBadRequest},
+	{domain.ErrInvalidPassSchedule, http.StatusBadRequest},
+	{domain.ErrPassVehicleOwnerMismatch, http.StatusBadRequest},
+	{domain.ErrInvalidPassVehicleData, http.StatusBadRequest},
+	{domain.ErrInvalidAccessLogData, http.StatusBadRequest},
+	{domain.ErrInvalidConfidence, http.StatusBadRequest},
+	{domain.ErrInvalidReasonCode, http.StatusBadRequest},
+	{domain.ErrUnknownGate, http.StatusBadRequest},
+	{domain.ErrInvalidAuditLogData, http.StatusBadRequest},
+	{domain.ErrInvalidAuditTargetType, http.StatusBadRequest},
+	{domain.ErrInvalidResetToken, http.StatusBadRequest},
+	{domain.ErrInvalidBlacklistData, http.StatusBadRequest},
+	{domain.ErrInvalidWhitelistData, http.StatusBadRequest},
+	{domain.ErrExpiryInPast, http.StatusBadRequest},
+	{domain.ErrInvalidZone, http.StatusBadRequest},
+
+	// 401 - не удалось подтвердить личность
+	{domain.ErrUnauthorized, http.StatusUnauthorized},
+	{domain.ErrInvalidCredentials, http.StatusUnauthorized},
+	{domain.ErrTokenExpired, http.StatusUnauthorized},
+	{domain.ErrInvalidToken, http.StatusUnauthorized},
+
+	// 403 - действие запрещено
+	{domain.ErrForbidden, http.StatusForbidden},
+	{domain.ErrUserInactive, http.StatusForbidden},
+	{domain.ErrRegistrationClosed, http.StatusForbidden},
+	{domain.ErrPassExpired, http.StatusForbidden},
+	{domain.ErrPassNotActive, http.StatusForbidden},
+	{domain.ErrPassUsageLimitReached, http.StatusForbidden},
+	{domain.ErrNoValidPass, http.StatusForbidden},
+
+	// 404 - объект не найден
+	{domain.ErrNotFound, http.StatusNotFound},
+	{domain.ErrUserNotFound, http.StatusNotFound},
+	{domain.ErrVehicleNotFound, http.StatusNotFound},
+	{domain.ErrPassNotFound, http.StatusNotFound},
+	{domain.ErrPassVehicleNotFound, http.StatusNotFound},
+	{domain.ErrAccessLogNotFound, http.StatusNotFound},
+	{domain.ErrRefreshTokenNotFound, http.StatusNotFound},
+	{domain.ErrBlacklistEntryNotFound, http.StatusNotFound},
+	{domain.ErrWhitelistEntryNotFound, http.StatusNotFound},
+
+	// 409 - конфликт с текущим состоянием
+	{domain.ErrConflict, http.StatusConflict},
+	{domain.ErrUserAlreadyExists, http.StatusConflict},
+	{domain.ErrLastAdmin, http.StatusConflict},
+	{domain.ErrVehicleAlreadyExists, http.StatusConflict},
+	{domain.ErrVehicleOwnerMismatch, http.StatusConflict},
+	{domain.ErrPassAlreadyRevoked, http.StatusConflict},
+	{domain.ErrPassNotExtendable, http.StatusConflict},
+	{domain.ErrOverlappingPass, http.StatusConflict},
+	{domain.ErrPassVehicleAlreadyExists, http.StatusConflict},
+	{domain.ErrBlacklistEntryAlreadyExists, http.StatusConflict},
+	{domain.ErrWhitelistEntryAlreadyExists, http.StatusConflict},
+
+	// 422 - запрос корректен, но не может быть обработан
+	{domain.ErrInvalidDirection, http.StatusUnprocessableEntity},
+	{domain.ErrStaleFrame, http.StatusUnprocessableEntity},
+	{domain.ErrMLImageRejected, http.StatusUnprocessableEntity},
+
+	// 429 - превышен лимит
+	{domain.ErrAccountLocked, http.StatusTooManyRequests},
+	{domain.ErrGuestPassLimitExceeded, http.StatusTooManyRequests},
+
+	// 503 - функция временно недоступна
+	{domain.ErrOccupancyUnavailable, http.StatusServiceUnavailable},
+	{domain.ErrPasswordResetUnavailable, http.StatusServiceUnavailable},
+}
+
+// domainErrorMessages - сообщения клиенту, более подробные, чем текст доменной ошибки
+// Для остальных ошибок в ответ попадает текст самой ошибки
+var domainErrorMessages = map[error]string{
+	domain.ErrInvalidPassSchedule:         "Invalid schedule: allowed_time_start and allowed_time_end must both be HH:MM and differ, allowed_weekdays must be 0-6",
+	domain.ErrUnknownGate:                 "Unknown gate_id",
+	domain.ErrInvalidAuditTargetType:      "Invalid target_type",
+	domain.ErrInvalidResetToken:           "Invalid or expired reset token",
+	domain.ErrUserInactive:                "User account is inactive",
+	domain.ErrPassVehicleNotFound:         "Vehicle is not linked to the pass",
+	domain.ErrRefreshTokenNotFound:        "Session not found",
+	domain.ErrOverlappingPass:             "An active pass of this type already covers these vehicles for the requested period",
+	domain.ErrPassVehicleAlreadyExists:    "Vehicle is already linked to the pass",
+	domain.ErrBlacklistEntryAlreadyExists: "Plate is already blacklisted",
+	domain.ErrWhitelistEntryAlreadyExists: "Plate is already whitelisted",
+	domain.ErrInvalidDirection:            "Invalid direction: expected IN or OUT",
+	domain.ErrStaleFrame:                  "Frame is too old: captured_at is outside the allowed window",
+	domain.ErrAccountLocked:               "Too many failed login attempts, try again later",
+}
+
+// findDomainError ищет доменную ошибку в цепочке err (через errors.Is)
+func findDomainError(err error) (domainErrorStatus, bool) {
+	for _, mapping := range domainErrorStatuses {
+		if errors.Is(err, mapping.err) {
+			return mapping, true
+		}
+	}
+	return domainErrorStatus{}, false
+}
+
+// httpStatusForError возвращает HTTP статус для ошибки сервиса; неизвестные ошибки - 500
+func httpStatusForError(err error) int {
+	mapping, ok := findDomainError(err)
+	if !ok {
+		return http.StatusInternalServerError
+	}
+	return mapping.status
+}
+
+// respondDomainError отправляет ответ для доменной ошибки err: статус из httpStatusForError,
+// стабильный код и сообщение из domainErrorMessages (или текст ошибки).
+// Для внутренних ошибок ответ не отправляется и возвращается false - вызывающий логирует err
+// и сам отвечает 500 (так текст внутренней ошибки не попадает клиенту)
+func respondDomainError(w http.ResponseWriter, err error) bool {
+	mapping, ok := findDomainError(err)
+	if !ok {
+		return false
+	}
+
+	message, ok := domainErrorMessages[mapping.err]
+	if !ok {
+		message = domainErrorMessage(mapping.err)
+	}
+	apierror.DomainError(w, mapping.status, err, message)
+	return true
+}
+
+// respondDomainErrorMessage отправляет ответ для доменной ошибки err с сообщением message,
+// когда обработчик может подсказать клиенту больше общего сообщения (например, допустимые значения)
+func respondDomainErrorMessage(w http.ResponseWriter, err error, message string) {
+	apierror.DomainError(w, httpStatusForError(err), err, message)
+}
+
+// respondDomainFieldError отправляет ответ для доменной ошибки err, относящейся к полю запроса field
+func respondDomainFieldError(w http.ResponseWriter, err error, field, message string) {
+	status := httpStatusForError(err)
+	apierror.Write(w, status, apierror.Detail{
+		Code:    apierror.CodeForError(err, status),
+		Message: message,
+		Field:   field,
+	})
+}
+
+// domainErrorMessage - текст доменной ошибки с заглавной буквы для ответа клиенту
+func domainErrorMessage(err error) string {
+	message := err.Error()
+	if message == "" {
+		return message
+	}
+	return strings.ToUpper(message[:1]) + message[1:]
+}
@@ -0,0 +1,179 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/frontandrew/gate/internal/delivery/http/apierror"
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPStatusForError(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected int
+	}{
+		// User errors
+		{domain.ErrUserNotFound, http.StatusNotFound},
+		{domain.ErrUserAlreadyExists, http.StatusConflict},
+		{domain.ErrInvalidEmail, http.StatusBadRequest},
+		{domain.ErrInvalidPassword, http.StatusBadRequest},
+		{domain.ErrWeakPassword, http.StatusBadRequest},
+		{domain.ErrInvalidUserData, http.StatusBadRequest},
+		{domain.ErrInvalidRole, http.StatusBadRequest},
+		{domain.ErrUserInactive, http.StatusForbidden},
+		{domain.ErrInvalidCredentials, http.StatusUnauthorized},
+		{domain.ErrInvalidPhone, http.StatusBadRequest},
+		{domain.ErrAccountLocked, http.StatusTooManyRequests},
+		{domain.ErrRegistrationClosed, http.StatusForbidden},
+		{domain.ErrLastAdmin, http.StatusConflict},
+
+		// Vehicle errors
+		{domain.ErrVehicleNotFound, http.StatusNotFound},
+		{domain.ErrVehicleAlreadyExists, http.StatusConflict},
+		{domain.ErrInvalidLicensePlate, http.StatusBadRequest},
+		{domain.ErrInvalidVehicleData, http.StatusBadRequest},
+		{domain.ErrVehicleInactive, http.StatusBadRequest},
+		{domain.ErrVehicleOwnerMismatch, http.StatusConflict},
+		{domain.ErrPlateQueryTooShort, http.StatusBadRequest},
+
+		// Pass errors
+		{domain.ErrPassNotFound, http.StatusNotFound},
+		{domain.ErrInvalidPassData, http.StatusBadRequest},
+		{domain.ErrInvalidPassType, http.StatusBadRequest},
+		{domain.ErrInvalidDateRange, http.StatusBadRequest},
+		{domain.ErrInvalidPassSchedule, http.StatusBadRequest},
+		{domain.ErrPassExpired, http.StatusForbidden},
+		{domain.ErrPassNotActive, http.StatusForbidden},
+		{domain.ErrPassAlreadyRevoked, http.StatusConflict},
+		{domain.ErrPassNotExtendable, http.StatusConflict},
+		{domain.ErrPassUsageLimitReached, http.StatusForbidden},
+		{domain.ErrOverlappingPass, http.StatusConflict},
+		{domain.ErrNoValidPass, http.StatusForbidden},
+		{domain.ErrGuestPassLimitExceeded, http.StatusTooManyRequests},
+
+		// PassVehicle errors
+		{domain.ErrPassVehicleNotFound, http.StatusNotFound},
+		{domain.ErrPassVehicleAlreadyExists, http.StatusConflict},
+		{domain.ErrPassVehicleLinkFailed, http.StatusInternalServerError},
+		{domain.ErrPassVehicleOwnerMismatch, http.StatusBadRequest},
+		{domain.ErrInvalidPassVehicleData, http.StatusBadRequest},
+
+		// AccessLog errors
+		{domain.ErrAccessLogNotFound, http.StatusNotFound},
+		{domain.ErrInvalidAccessLogData, http.StatusBadRequest},
+		{domain.ErrInvalidDirection, http.StatusUnprocessableEntity},
+		{domain.ErrInvalidConfidence, http.StatusBadRequest},
+		{domain.ErrInvalidReasonCode, http.StatusBadRequest},
+		{domain.ErrUnknownGate, http.StatusBadRequest},
+		{domain.ErrStaleFrame, http.StatusUnprocessableEntity},
+		{domain.ErrOccupancyUnavailable, http.StatusServiceUnavailable},
+
+		// ML errors
+		{domain.ErrMLImageRejected, http.StatusUnprocessableEntity},
+
+		// Audit errors
+		{domain.ErrInvalidAuditLogData, http.StatusBadRequest},
+		{domain.ErrInvalidAuditTargetType, http.StatusBadRequest},
+
+		// Authorization errors
+		{domain.ErrUnauthorized, http.StatusUnauthorized},
+		{domain.ErrForbidden, http.StatusForbidden},
+		{domain.ErrTokenExpired, http.StatusUnauthorized},
+		{domain.ErrInvalidToken, http.StatusUnauthorized},
+		{domain.ErrRefreshTokenNotFound, http.StatusNotFound},
+		{domain.ErrInvalidResetToken, http.StatusBadRequest},
+		{domain.ErrPasswordResetUnavailable, http.StatusServiceUnavailable},
+
+		// Blacklist/Whitelist errors
+		{domain.ErrBlacklistEntryNotFound, http.StatusNotFound},
+		{domain.ErrBlacklistEntryAlreadyExists, http.StatusConflict},
+		{domain.ErrInvalidBlacklistData, http.StatusBadRequest},
+		{domain.ErrWhitelistEntryNotFound, http.StatusNotFound},
+		{domain.ErrWhitelistEntryAlreadyExists, http.StatusConflict},
+		{domain.ErrInvalidWhitelistData, http.StatusBadRequest},
+		{domain.ErrExpiryInPast, http.StatusBadRequest},
+		{domain.ErrInvalidZone, http.StatusBadRequest},
+
+		// General errors
+		{domain.ErrInternal, http.StatusInternalServerError},
+		{domain.ErrNotFound, http.StatusNotFound},
+		{domain.ErrBadRequest, http.StatusBadRequest},
+		{domain.ErrConflict, http.StatusConflict},
+
+		// Обернутые и недоменные ошибки
+		{fmt.Errorf("whitelist[3]: %w", domain.ErrInvalidLicensePlate), http.StatusBadRequest},
+		{fmt.Errorf("failed to get user: %w", errors.New("connection refused")), http.StatusInternalServerError},
+		{errors.New("unexpected"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			assert.Equal(t, tt.expected, httpStatusForError(tt.err))
+		})
+	}
+}
+
+func TestDomainErrorStatuses_HaveCodes(t *testing.T) {
+	// Каждая ошибка с собственным статусом должна иметь и собственный стабильный код
+	generic := map[error]bool{
+		domain.ErrBadRequest: true, domain.ErrUnauthorized: true, domain.ErrForbidden: true,
+		domain.ErrNotFound: true, domain.ErrConflict: true,
+	}
+	for _, mapping := range domainErrorStatuses {
+		if generic[mapping.err] {
+			continue
+		}
+		assert.NotEqual(t, apierror.CodeForStatus(mapping.status), apierror.CodeForError(mapping.err, mapping.status),
+			"нет кода для %q", mapping.err)
+	}
+}
+
+func TestRespondDomainError(t *testing.T) {
+	decode := func(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return ErrorDetail(t, response)
+	}
+
+	t.Run("текст доменной ошибки", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		assert.True(t, respondDomainError(w, domain.ErrPassNotFound))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		detail := decode(t, w)
+		assert.Equal(t, "PASS_NOT_FOUND", detail["code"])
+		assert.Equal(t, "Pass not found", detail["message"])
+	})
+
+	t.Run("подробное сообщение", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		assert.True(t, respondDomainError(w, domain.ErrInvalidDirection))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, "Invalid direction: expected IN or OUT", decode(t, w)["message"])
+	})
+
+	t.Run("обернутая ошибка: текст обертки не раскрывается", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		assert.True(t, respondDomainError(w, fmt.Errorf("failed to import entry A123BC777: %w", domain.ErrWhitelistEntryAlreadyExists)))
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		detail := decode(t, w)
+		assert.Equal(t, "WHITELIST_ENTRY_ALREADY_EXISTS", detail["code"])
+		assert.Equal(t, "Plate is already whitelisted", detail["message"])
+	})
+
+	t.Run("внутренняя ошибка остается вызывающему", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		assert.False(t, respondDomainError(w, errors.New("connection refused")))
+		assert.False(t, respondDomainError(w, domain.ErrInternal))
+		assert.Zero(t, w.Body.Len(), "ответ не отправлен")
+	})
+}
@@ -0,0 +1,108 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+)
+
+// eventsHeartbeatInterval - период комментариев-пингов, чтобы прокси не закрывали простаивающее соединение
+const eventsHeartbeatInterval = 25 * time.Second
+
+// AccessEventSubscriber определяет интерфейс подписки на решения о доступе
+type AccessEventSubscriber interface {
+	// Subscribe возвращает канал событий; канал закрывается после отмены ctx
+	Subscribe(ctx context.Context) (<-chan *domain.AccessEvent, error)
+}
+
+// EventsHandler отдает ленту решений о доступе в реальном времени (Server-Sent Events)
+type EventsHandler struct {
+	subscriber AccessEventSubscriber
+	logger     logger.Logger
+
+	shutdown     chan struct{} // Закрывается при остановке сервера и завершает открытые потоки
+	shutdownOnce sync.Once
+}
+
+// NewEventsHandler создает новый handler
+func NewEventsHandler(subscriber AccessEventSubscriber, logger logger.Logger) *EventsHandler {
+	return &EventsHandler{
+		subscriber: subscriber,
+		logger:     logger,
+		shutdown:   make(chan struct{}),
+	}
+}
+
+// Shutdown завершает открытые потоки событий; регистрируется через http.Server.RegisterOnShutdown.
+// Server.Shutdown не отменяет контекст запросов и без этого ждал бы SSE клиентов до своего таймаута.
+// Клиенты переподключатся к другому экземпляру API
+func (h *EventsHandler) Shutdown() {
+	h.shutdownOnce.Do(func() { close(h.shutdown) })
+}
+
+// StreamAccessEvents транслирует решения о доступе по мере их принятия
+// GET /api/v1/access/events
+func (h *EventsHandler) StreamAccessEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondError(w, http.StatusInternalServerError, "Streaming is not supported")
+		return
+	}
+
+	events, err := h.subscriber.Subscribe(r.Context())
+	if err != nil {
+		requestLogger(r, h.logger).Error("Failed to subscribe to access events", map[string]interface{}{
+			"error": err.Error(),
+		})
+		respondError(w, http.StatusServiceUnavailable, "Access events are unavailable")
+		return
+	}
+
+	// Соединение живет дольше WriteTimeout сервера; если снять дедлайн нельзя, клиент переподключится
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(eventsHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-h.shutdown:
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				requestLogger(r, h.logger).Warn("Failed to encode access event", map[string]interface{}{
+					"error": err.Error(),
+				})
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: access\ndata: %s\n\n", data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
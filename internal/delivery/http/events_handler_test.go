@@ -0,0 +1,152 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEventSubscriber отдает заранее подготовленный канал событий
+type fakeEventSubscriber struct {
+	events chan *domain.AccessEvent
+	err    error
+}
+
+func (f *fakeEventSubscriber) Subscribe(ctx context.Context) (<-chan *domain.AccessEvent, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.events, nil
+}
+
+// readSSEEvents разбирает тело ответа на пары (event, data)
+func readSSEEvents(t *testing.T, body string) (names []string, payloads []string) {
+	t.Helper()
+
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			names = append(names, strings.TrimPrefix(line, "event: "))
+		case strings.HasPrefix(line, "data: "):
+			payloads = append(payloads, strings.TrimPrefix(line, "data: "))
+		}
+	}
+	require.NoError(t, scanner.Err())
+	return names, payloads
+}
+
+func TestEventsHandler_StreamAccessEvents(t *testing.T) {
+	subscriber := &fakeEventSubscriber{events: make(chan *domain.AccessEvent, 2)}
+	subscriber.events <- &domain.AccessEvent{
+		LicensePlate:  "A123BC777",
+		AccessGranted: true,
+		Reason:        "Valid pass",
+		GateID:        "north-1",
+		Direction:     domain.DirectionIn,
+		Timestamp:     time.Now(),
+	}
+	subscriber.events <- &domain.AccessEvent{
+		LicensePlate:  "X999XX99",
+		AccessGranted: false,
+		Reason:        "Vehicle is blacklisted",
+		Timestamp:     time.Now(),
+	}
+	// Закрытый канал завершает поток - как при отключении подписки
+	close(subscriber.events)
+
+	handler := NewEventsHandler(subscriber, logger.NewNoop())
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/access/events", nil)
+	w := httptest.NewRecorder()
+
+	handler.StreamAccessEvents(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+	assert.True(t, w.Flushed)
+
+	names, payloads := readSSEEvents(t, w.Body.String())
+	assert.Equal(t, []string{"access", "access"}, names)
+	require.Len(t, payloads, 2)
+
+	var first, second domain.AccessEvent
+	require.NoError(t, json.Unmarshal([]byte(payloads[0]), &first))
+	require.NoError(t, json.Unmarshal([]byte(payloads[1]), &second))
+	assert.Equal(t, "A123BC777", first.LicensePlate)
+	assert.True(t, first.AccessGranted)
+	assert.Equal(t, "north-1", first.GateID)
+	assert.Equal(t, "X999XX99", second.LicensePlate)
+	assert.False(t, second.AccessGranted)
+}
+
+func TestEventsHandler_StreamAccessEvents_ClientDisconnect(t *testing.T) {
+	subscriber := &fakeEventSubscriber{events: make(chan *domain.AccessEvent)}
+	handler := NewEventsHandler(subscriber, logger.NewNoop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/access/events", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		handler.StreamAccessEvents(w, req)
+		close(done)
+	}()
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("поток не завершился после отключения клиента")
+	}
+}
+
+func TestEventsHandler_StreamAccessEvents_ServerShutdown(t *testing.T) {
+	subscriber := &fakeEventSubscriber{events: make(chan *domain.AccessEvent)}
+	handler := NewEventsHandler(subscriber, logger.NewNoop())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/access/events", nil)
+	w := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		handler.StreamAccessEvents(w, req)
+		close(done)
+	}()
+
+	// Контекст запроса не отменяется - поток завершает только остановка сервера
+	handler.Shutdown()
+	handler.Shutdown()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("поток не завершился при остановке сервера")
+	}
+}
+
+func TestEventsHandler_StreamAccessEvents_SubscribeError(t *testing.T) {
+	subscriber := &fakeEventSubscriber{err: errors.New("redis unavailable")}
+	handler := NewEventsHandler(subscriber, logger.NewNoop())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/access/events", nil)
+	w := httptest.NewRecorder()
+
+	handler.StreamAccessEvents(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,113 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/frontandrew/gate/internal/pkg/logger"
+)
+
+// Статусы зависимости в ответе /health/ready
+const (
+	healthStatusUp   = "up"
+	healthStatusDown = "down"
+)
+
+// HealthCheck - проверка одной зависимости (PostgreSQL, Redis, ML сервис)
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+	Stats func() interface{} // Необязательно: состояние зависимости для поля stats (например, пул подключений)
+}
+
+// HealthHandlerConfig содержит параметры проверок готовности
+type HealthHandlerConfig struct {
+	Timeout time.Duration // Общий таймаут проверки всех зависимостей
+}
+
+// HealthHandler обрабатывает проверки живости и готовности (для оркестратора и балансировщика)
+type HealthHandler struct {
+	checks []HealthCheck
+	logger logger.Logger
+	config HealthHandlerConfig
+}
+
+// NewHealthHandler создает новый health handler
+func NewHealthHandler(checks []HealthCheck, logger logger.Logger, config HealthHandlerConfig) *HealthHandler {
+	return &HealthHandler{
+		checks: checks,
+		logger: logger,
+		config: config,
+	}
+}
+
+// Live сообщает, что процесс запущен и обслуживает запросы; зависимости не проверяются,
+// чтобы сбой БД не приводил к перезапуску всех экземпляров API
+// GET /health/live
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, map[string]string{
+		"status": "healthy",
+	})
+}
+
+// Ready проверяет все зависимости параллельно с общим таймаутом
+// Если хотя бы одна недоступна - 503, чтобы балансировщик не направлял запросы на экземпляр
+// GET /health/ready
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.config.Timeout)
+	defer cancel()
+
+	statuses := make(map[string]string, len(h.checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range h.checks {
+		wg.Add(1)
+		go func(check HealthCheck) {
+			defer wg.Done()
+
+			status := healthStatusUp
+			if err := check.Check(ctx); err != nil {
+				status = healthStatusDown
+				// Причина только в логе: endpoint публичный, а ошибка может раскрыть адреса внутренних сервисов
+				requestLogger(r, h.logger).Warn("Health check failed", map[string]interface{}{
+					"dependency": check.Name,
+					"error":      err.Error(),
+				})
+			}
+
+			mu.Lock()
+			statuses[check.Name] = status
+			mu.Unlock()
+		}(check)
+	}
+	wg.Wait()
+
+	code := http.StatusOK
+	overall := "ready"
+	for _, status := range statuses {
+		if status != healthStatusUp {
+			code = http.StatusServiceUnavailable
+			overall = "not_ready"
+			break
+		}
+	}
+
+	response := map[string]interface{}{
+		"status": overall,
+		"checks": statuses,
+	}
+
+	stats := make(map[string]interface{})
+	for _, check := range h.checks {
+		if check.Stats != nil {
+			stats[check.Name] = check.Stats()
+		}
+	}
+	if len(stats) > 0 {
+		response["stats"] = stats
+	}
+
+	respondJSON(w, code, response)
+}
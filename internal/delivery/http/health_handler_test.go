@@ -0,0 +1,114 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func healthyCheck(ctx context.Context) error { return nil }
+
+func failingCheck(ctx context.Context) error { return errors.New("connection refused") }
+
+// hangingCheck отвечает только по истечении таймаута проверки
+func hangingCheck(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestHealthHandler_Ready(t *testing.T) {
+	tests := []struct {
+		name           string
+		checks         []HealthCheck
+		expectedStatus int
+		expectedChecks map[string]interface{}
+	}{
+		{
+			name: "все зависимости доступны",
+			checks: []HealthCheck{
+				{Name: "postgres", Check: healthyCheck},
+				{Name: "redis", Check: healthyCheck},
+				{Name: "ml", Check: healthyCheck},
+			},
+			expectedStatus: http.StatusOK,
+			expectedChecks: map[string]interface{}{"postgres": "up", "redis": "up", "ml": "up"},
+		},
+		{
+			name: "ML сервис недоступен",
+			checks: []HealthCheck{
+				{Name: "postgres", Check: healthyCheck},
+				{Name: "redis", Check: healthyCheck},
+				{Name: "ml", Check: failingCheck},
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedChecks: map[string]interface{}{"postgres": "up", "redis": "up", "ml": "down"},
+		},
+		{
+			name: "зависимость не ответила за таймаут",
+			checks: []HealthCheck{
+				{Name: "postgres", Check: hangingCheck},
+				{Name: "ml", Check: healthyCheck},
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedChecks: map[string]interface{}{"postgres": "down", "ml": "up"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHealthHandler(tt.checks, logger.NewNoop(), HealthHandlerConfig{Timeout: 50 * time.Millisecond})
+
+			req := httptest.NewRequest(http.MethodGet, "/health/ready", nil)
+			w := httptest.NewRecorder()
+			handler.Ready(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedChecks, response["checks"])
+			assert.NotContains(t, w.Body.String(), "connection refused", "причина сбоя не раскрывается")
+		})
+	}
+}
+
+func TestHealthHandler_Ready_Stats(t *testing.T) {
+	checks := []HealthCheck{
+		{Name: "postgres", Check: healthyCheck, Stats: func() interface{} {
+			return map[string]int{"total_conns": 3, "idle_conns": 2}
+		}},
+		{Name: "ml", Check: healthyCheck},
+	}
+	handler := NewHealthHandler(checks, logger.NewNoop(), HealthHandlerConfig{Timeout: time.Second})
+
+	req := httptest.NewRequest(http.MethodGet, "/health/ready", nil)
+	w := httptest.NewRecorder()
+	handler.Ready(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"status": "ready",
+		"checks": {"postgres": "up", "ml": "up"},
+		"stats": {"postgres": {"total_conns": 3, "idle_conns": 2}}
+	}`, w.Body.String())
+}
+
+func TestHealthHandler_Live(t *testing.T) {
+	// Живость не зависит от состояния зависимостей
+	handler := NewHealthHandler([]HealthCheck{{Name: "postgres", Check: failingCheck}}, logger.NewNoop(), HealthHandlerConfig{Timeout: time.Second})
+
+	req := httptest.NewRequest(http.MethodGet, "/health/live", nil)
+	w := httptest.NewRecorder()
+	handler.Live(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"healthy"}`, w.Body.String())
+}
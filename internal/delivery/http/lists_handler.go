@@ -0,0 +1,123 @@
+package http
+
+import (
+	"context"
+	"encoding/csv"
+	"net/http"
+	"time"
+
+	"github.com/frontandrew/gate/internal/delivery/http/middleware"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/usecase/lists"
+	"github.com/google/uuid"
+)
+
+// ListsService определяет интерфейс для выгрузки и восстановления белого/черного списков
+type ListsService interface {
+	Export(ctx context.Context) (*lists.Snapshot, error)
+	Import(ctx context.Context, snapshot *lists.Snapshot, addedBy uuid.UUID) (*lists.ImportResult, error)
+}
+
+// ListsHandler обрабатывает запросы резервного копирования списков
+type ListsHandler struct {
+	listsService ListsService
+	logger       logger.Logger
+}
+
+// NewListsHandler создает новый handler
+func NewListsHandler(listsService ListsService, logger logger.Logger) *ListsHandler {
+	return &ListsHandler{
+		listsService: listsService,
+		logger:       logger,
+	}
+}
+
+// Export выгружает действующие записи белого и черного списков
+// GET /api/v1/admin/lists/export?format=json|csv
+func (h *ListsHandler) Export(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		respondError(w, http.StatusBadRequest, "Invalid format: expected json or csv")
+		return
+	}
+
+	snapshot, err := h.listsService.Export(r.Context())
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to export lists", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to export lists")
+		}
+		return
+	}
+
+	filename := "lists-" + snapshot.ExportedAt.UTC().Format("20060102-150405")
+
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`.csv"`)
+		w.WriteHeader(http.StatusOK)
+		writeListsCSV(w, snapshot)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`.json"`)
+	respondJSON(w, http.StatusOK, snapshot)
+}
+
+// Import восстанавливает записи из JSON-выгрузки (повторный импорт ничего не меняет)
+// POST /api/v1/admin/lists/import
+func (h *ListsHandler) Import(w http.ResponseWriter, r *http.Request) {
+	var snapshot lists.Snapshot
+	if !decodeJSON(w, r, &snapshot) {
+		return
+	}
+
+	claims, ok := middleware.GetUserClaims(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	result, err := h.listsService.Import(r.Context(), &snapshot, claims.UserID)
+	if err != nil {
+		if httpStatusForError(err) == http.StatusBadRequest {
+			// Ошибка валидации содержит индекс записи, клиенту нужен полный текст
+			respondDomainErrorMessage(w, err, err.Error())
+			return
+		}
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to import lists", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to import lists")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// writeListsCSV пишет выгрузку в CSV: list,license_plate,reason,expires_at
+func writeListsCSV(w http.ResponseWriter, snapshot *lists.Snapshot) {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"list", "license_plate", "reason", "expires_at"})
+
+	write := func(list string, entries []lists.Entry) {
+		for _, entry := range entries {
+			expiresAt := ""
+			if entry.ExpiresAt != nil {
+				expiresAt = entry.ExpiresAt.UTC().Format(time.RFC3339)
+			}
+			_ = cw.Write([]string{list, entry.LicensePlate, entry.Reason, expiresAt})
+		}
+	}
+	write("whitelist", snapshot.Whitelist)
+	write("blacklist", snapshot.Blacklist)
+
+	cw.Flush()
+}
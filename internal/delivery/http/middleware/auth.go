@@ -2,11 +2,14 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strings"
 
+	"github.com/frontandrew/gate/internal/delivery/http/apierror"
 	"github.com/frontandrew/gate/internal/domain"
 	"github.com/frontandrew/gate/internal/pkg/jwt"
+	"github.com/frontandrew/gate/internal/pkg/logger"
 )
 
 // contextKey - тип для ключей контекста
@@ -17,39 +20,61 @@ const (
 	UserClaimsKey contextKey = "user_claims"
 )
 
+// TokenDenylist проверяет, отозван ли access токен до истечения срока (logout, отключение пользователя)
+type TokenDenylist interface {
+	IsAccessTokenRevoked(ctx context.Context, claims *jwt.Claims) bool
+}
+
+// BearerToken извлекает токен из заголовка "Authorization: Bearer <token>"
+func BearerToken(r *http.Request) (string, bool) {
+	parts := strings.Split(r.Header.Get("Authorization"), " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return "", false
+	}
+	return parts[1], true
+}
+
 // AuthMiddleware проверяет наличие и валидность JWT токена
-func AuthMiddleware(tokenService *jwt.TokenService) func(http.Handler) http.Handler {
+// denylist может быть nil - тогда отозванные токены действуют до истечения срока
+func AuthMiddleware(tokenService *jwt.TokenService, denylist TokenDenylist) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Извлекаем токен из заголовка Authorization
-			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" {
+			if r.Header.Get("Authorization") == "" {
 				respondError(w, http.StatusUnauthorized, "Authorization header required")
 				return
 			}
 
 			// Проверяем формат: "Bearer <token>"
-			parts := strings.Split(authHeader, " ")
-			if len(parts) != 2 || parts[0] != "Bearer" {
+			tokenString, ok := BearerToken(r)
+			if !ok {
 				respondError(w, http.StatusUnauthorized, "Invalid authorization header format")
 				return
 			}
 
-			tokenString := parts[1]
-
 			// Валидируем токен
 			claims, err := tokenService.ValidateToken(tokenString)
 			if err != nil {
-				if err == domain.ErrTokenExpired {
-					respondError(w, http.StatusUnauthorized, "Token expired")
+				if errors.Is(err, domain.ErrTokenExpired) {
+					apierror.DomainError(w, http.StatusUnauthorized, err, "Token expired")
 					return
 				}
-				respondError(w, http.StatusUnauthorized, "Invalid token")
+				apierror.DomainError(w, http.StatusUnauthorized, domain.ErrInvalidToken, "Invalid token")
+				return
+			}
+
+			if denylist != nil && denylist.IsAccessTokenRevoked(r.Context(), claims) {
+				apierror.Write(w, http.StatusUnauthorized, apierror.Detail{Code: apierror.CodeTokenRevoked, Message: "Token revoked"})
 				return
 			}
 
 			// Добавляем claims в контекст
 			ctx := context.WithValue(r.Context(), UserClaimsKey, claims)
+
+			// Записи logger'а запроса дополняются пользователем
+			if requestLog := logger.FromContextOr(ctx, nil); requestLog != nil {
+				ctx = logger.WithContext(ctx, requestLog.With("user_id", claims.UserID))
+			}
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
@@ -91,9 +116,7 @@ func GetUserClaims(ctx context.Context) (*jwt.Claims, bool) {
 	return claims, ok
 }
 
-// respondError отправляет JSON ответ с ошибкой
+// respondError отправляет JSON ответ с ошибкой; код ошибки определяется по HTTP статусу
 func respondError(w http.ResponseWriter, code int, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	_, _ = w.Write([]byte(`{"error":"` + message + `"}`))
+	apierror.Error(w, code, message)
 }
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/frontandrew/gate/internal/delivery/http/apierror"
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/jwt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubDenylist считает отозванными токены с указанными jti
+type stubDenylist map[string]bool
+
+func (d stubDenylist) IsAccessTokenRevoked(ctx context.Context, claims *jwt.Claims) bool {
+	return d[claims.ID]
+}
+
+func TestAuthMiddleware_Denylist(t *testing.T) {
+	tokenService := jwt.NewTokenService("test-secret", time.Hour, 24*time.Hour)
+	pair, err := tokenService.GenerateTokenPair(&domain.User{ID: uuid.New(), Email: "user@test.com", Role: domain.RoleUser})
+	require.NoError(t, err)
+	claims, err := tokenService.ValidateToken(pair.AccessToken)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		denylist       TokenDenylist
+		expectedStatus int
+	}{
+		{
+			name:           "токен не отозван",
+			denylist:       stubDenylist{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "токен в denylist",
+			denylist:       stubDenylist{claims.ID: true},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "denylist не настроен",
+			denylist:       nil,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := AuthMiddleware(tokenService, tt.denylist)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/me", nil)
+			req.Header.Set("Authorization", "Bearer "+pair.AccessToken)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestAuthMiddleware_ErrorCodes(t *testing.T) {
+	tokenService := jwt.NewTokenService("test-secret", time.Hour, 24*time.Hour)
+	expiredService := jwt.NewTokenService("test-secret", -time.Minute, 24*time.Hour)
+	user := &domain.User{ID: uuid.New(), Email: "user@test.com", Role: domain.RoleUser}
+
+	expired, err := expiredService.GenerateTokenPair(user)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		authorization string
+		expectedCode  apierror.Code
+	}{
+		{name: "без заголовка", authorization: "", expectedCode: apierror.CodeUnauthorized},
+		{name: "неверный формат заголовка", authorization: "Token abc", expectedCode: apierror.CodeUnauthorized},
+		{name: "истекший токен", authorization: "Bearer " + expired.AccessToken, expectedCode: "TOKEN_EXPIRED"},
+		{name: "поддельный токен", authorization: "Bearer not-a-jwt", expectedCode: "INVALID_TOKEN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := AuthMiddleware(tokenService, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				t.Fatal("запрос не должен дойти до обработчика")
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/me", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+			var resp apierror.Response
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.False(t, resp.Success)
+			assert.Equal(t, tt.expectedCode, resp.Error.Code)
+			assert.NotEmpty(t, resp.Error.Message)
+		})
+	}
+}
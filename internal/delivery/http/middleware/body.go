@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"net/http"
+)
+
+// MaxBodySize ограничивает размер тела запроса
+// Чтение сверх лимита завершается ошибкой *http.MaxBytesError, которую обработчик превращает в 413
+func MaxBodySize(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Body != nil {
+				r.Body = http.MaxBytesReader(w, r.Body, limit)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// CacheControl выставляет заголовок Cache-Control для всех ответов
+func CacheControl(value string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", value)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// NoStore запрещает кэширование ответа (auth endpoints, токены)
+func NoStore() func(http.Handler) http.Handler {
+	return CacheControl("no-store")
+}
+
+// PrivateCache разрешает кратковременное кэширование только в браузере пользователя
+// Используется для списков, привязанных к текущему пользователю
+func PrivateCache(maxAge time.Duration) func(http.Handler) http.Handler {
+	return CacheControl(fmt.Sprintf("private, max-age=%d", int(maxAge.Seconds())))
+}
+
+// Revalidate разрешает хранить ответ, но требует проверки через ETag перед использованием
+func Revalidate() func(http.Handler) http.Handler {
+	return CacheControl("private, no-cache")
+}
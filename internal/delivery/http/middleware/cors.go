@@ -13,21 +13,17 @@ type CORSConfig struct {
 }
 
 // CORSMiddleware добавляет CORS заголовки
+// Origin разрешается при точном совпадении с одним из AllowedOrigins, по "*" (любой origin)
+// или по шаблону поддоменов вида "https://*.example.com"
 func CORSMiddleware(config CORSConfig) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			origin := r.Header.Get("Origin")
 
-			// Проверяем, разрешен ли origin
-			allowed := false
-			for _, allowedOrigin := range config.AllowedOrigins {
-				if allowedOrigin == "*" || allowedOrigin == origin {
-					allowed = true
-					break
-				}
-			}
+			// Ответ зависит от Origin - промежуточные кэши не должны отдавать его другим origin'ам
+			w.Header().Add("Vary", "Origin")
 
-			if allowed {
+			if origin != "" && originAllowed(origin, config.AllowedOrigins) {
 				w.Header().Set("Access-Control-Allow-Origin", origin)
 			}
 
@@ -46,3 +42,30 @@ func CORSMiddleware(config CORSConfig) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// originAllowed проверяет origin по списку разрешенных; схема и хост сравниваются без учета регистра
+func originAllowed(origin string, allowedOrigins []string) bool {
+	for _, allowed := range allowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) || matchWildcardOrigin(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchWildcardOrigin сопоставляет origin с шаблоном "scheme://*.domain[:port]"
+// Сам domain шаблону не соответствует - только его поддомены
+func matchWildcardOrigin(pattern, origin string) bool {
+	scheme, domain, ok := strings.Cut(strings.ToLower(pattern), "://*.")
+	if !ok || domain == "" {
+		return false
+	}
+
+	host, ok := strings.CutPrefix(strings.ToLower(origin), scheme+"://")
+	if !ok {
+		return false
+	}
+
+	subdomain, ok := strings.CutSuffix(host, "."+domain)
+	return ok && subdomain != ""
+}
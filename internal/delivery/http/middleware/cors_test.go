@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORSMiddleware_AllowedOrigins(t *testing.T) {
+	config := CORSConfig{
+		AllowedOrigins: []string{"https://gate.example.com", "http://localhost:5173", "https://*.partner.com"},
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Content-Type", "Authorization"},
+	}
+	handler := CORSMiddleware(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name    string
+		origin  string
+		allowed bool
+	}{
+		{name: "первый origin из списка", origin: "https://gate.example.com", allowed: true},
+		{name: "второй origin из списка", origin: "http://localhost:5173", allowed: true},
+		{name: "хост без учета регистра", origin: "https://Gate.Example.com", allowed: true},
+		{name: "поддомен по шаблону", origin: "https://cams.partner.com", allowed: true},
+		{name: "вложенный поддомен по шаблону", origin: "https://a.b.partner.com", allowed: true},
+		{name: "склеенная строка из окружения", origin: "https://gate.example.com,http://localhost:5173", allowed: false},
+		{name: "другой порт", origin: "http://localhost:3000", allowed: false},
+		{name: "другая схема", origin: "http://gate.example.com", allowed: false},
+		{name: "сам домен шаблона", origin: "https://partner.com", allowed: false},
+		{name: "домен с тем же окончанием", origin: "https://evilpartner.com", allowed: false},
+		{name: "другая схема для шаблона", origin: "http://cams.partner.com", allowed: false},
+		{name: "неизвестный origin", origin: "https://evil.com", allowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, method := range []string{http.MethodGet, http.MethodOptions} {
+				req := httptest.NewRequest(method, "/api/v1/config", nil)
+				req.Header.Set("Origin", tt.origin)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, req)
+
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Contains(t, w.Header().Values("Vary"), "Origin")
+				if tt.allowed {
+					assert.Equal(t, tt.origin, w.Header().Get("Access-Control-Allow-Origin"), method)
+				} else {
+					assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"), method)
+				}
+			}
+		})
+	}
+}
+
+func TestCORSMiddleware_AnyOrigin(t *testing.T) {
+	handler := CORSMiddleware(CORSConfig{AllowedOrigins: []string{"*"}})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/config", nil)
+	req.Header.Set("Origin", "https://any.example.org")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, "https://any.example.org", w.Header().Get("Access-Control-Allow-Origin"))
+
+	// Запрос без Origin (не из браузера) заголовок не получает
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/config", nil))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/frontandrew/gate/internal/pkg/idempotency"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+)
+
+// IdempotencyKeyHeader - заголовок, которым клиент помечает повторы одного и того же запроса
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader выставляется в ответе, взятом из сохраненного
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// maxIdempotencyKeyLength ограничивает длину ключа (UUID клиента с запасом)
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyMiddleware возвращает сохраненный ответ на повтор запроса с тем же Idempotency-Key
+// Без заголовка запрос выполняется как обычно. Ключ действует в пределах пользователя, метода и пути:
+// повтор с другим телом отклоняется 422, повтор во время обработки исходного запроса - 409.
+// Ответы 5xx не сохраняются, чтобы клиент мог повторить запрос.
+// При недоступности Redis запрос выполняется без защиты от повторов (fail-open)
+func IdempotencyMiddleware(store *idempotency.Store, log logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				respondError(w, http.StatusBadRequest, "Idempotency-Key is too long")
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					respondError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+					return
+				}
+				respondError(w, http.StatusBadRequest, "Failed to read request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			reqLog := logger.FromContextOr(r.Context(), log)
+			scopedKey := idempotencyScope(r) + ":" + key
+			fingerprint := bodyFingerprint(body)
+
+			existing, started, err := store.Begin(r.Context(), scopedKey, fingerprint)
+			if err != nil {
+				reqLog.Error("Idempotency check failed", map[string]interface{}{
+					"error": err.Error(),
+				})
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !started {
+				switch {
+				case existing.Fingerprint != fingerprint:
+					respondError(w, http.StatusUnprocessableEntity, "Idempotency-Key was already used with a different request")
+				case !existing.Completed:
+					respondError(w, http.StatusConflict, "Request with this Idempotency-Key is still in progress")
+				default:
+					replayResponse(w, existing)
+				}
+				return
+			}
+
+			// Ответ сохраняется и после отмены запроса клиентом - иначе его повтор выполнится заново
+			ctx := context.WithoutCancel(r.Context())
+			completed := false
+			defer func() {
+				if !completed {
+					if err := store.Release(ctx, scopedKey); err != nil {
+						reqLog.Error("Failed to release idempotency key", map[string]interface{}{
+							"error": err.Error(),
+						})
+					}
+				}
+			}()
+
+			rec := &recordingWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			if rec.statusCode >= http.StatusInternalServerError {
+				return
+			}
+
+			err = store.Complete(ctx, scopedKey, &idempotency.Record{
+				Fingerprint: fingerprint,
+				StatusCode:  rec.statusCode,
+				ContentType: rec.Header().Get("Content-Type"),
+				Body:        rec.body.Bytes(),
+			})
+			if err != nil {
+				reqLog.Error("Failed to save idempotent response", map[string]interface{}{
+					"error": err.Error(),
+				})
+				return
+			}
+			completed = true
+		})
+	}
+}
+
+// idempotencyScope отделяет ключи разных пользователей и endpoint'ов:
+// одинаковый ключ от двух клиентов не должен возвращать одному из них чужой ответ
+func idempotencyScope(r *http.Request) string {
+	client := ClientIPRateLimitKey(r)
+	if claims, ok := GetUserClaims(r.Context()); ok {
+		client = "user:" + claims.UserID.String()
+	}
+	return client + ":" + r.Method + ":" + r.URL.Path
+}
+
+// bodyFingerprint - хеш тела запроса для обнаружения повторного использования ключа с другими данными
+func bodyFingerprint(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// replayResponse отправляет сохраненный ответ исходного запроса
+func replayResponse(w http.ResponseWriter, record *idempotency.Record) {
+	if record.ContentType != "" {
+		w.Header().Set("Content-Type", record.ContentType)
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(record.StatusCode)
+	_, _ = w.Write(record.Body)
+}
+
+// recordingWriter пропускает ответ клиенту и запоминает его для повторов
+type recordingWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rw *recordingWriter) WriteHeader(code int) {
+	rw.statusCode = code
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recordingWriter) Write(b []byte) (int, error) {
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
+
+// Unwrap нужен http.ResponseController для доступа к исходному writer'у
+func (rw *recordingWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/idempotency"
+	"github.com/frontandrew/gate/internal/pkg/jwt"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/pkg/redis"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestIdempotencyStore(t *testing.T) *idempotency.Store {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	host, port, err := net.SplitHostPort(mr.Addr())
+	require.NoError(t, err)
+
+	client, err := redis.NewClient(redis.Config{Host: host, Port: port})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	return idempotency.NewStore(client, "idempotency:test:", idempotency.Config{TTL: time.Hour})
+}
+
+// creatingHandler имитирует создание ресурса: каждый выполненный запрос получает новый номер
+type creatingHandler struct {
+	created int
+	status  int
+}
+
+func (h *creatingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.created++
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(h.status)
+	_, _ = fmt.Fprintf(w, `{"id":%d}`, h.created)
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	userID := uuid.New()
+
+	post := func(handler http.Handler, user uuid.UUID, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/passes", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		claims := &jwt.Claims{UserID: user, Email: "user@example.com", Role: domain.RoleAdmin}
+		req = req.WithContext(context.WithValue(req.Context(), UserClaimsKey, claims))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("тот же ключ возвращает исходный ответ", func(t *testing.T) {
+		next := &creatingHandler{status: http.StatusCreated}
+		handler := IdempotencyMiddleware(newTestIdempotencyStore(t), logger.NewNoop())(next)
+
+		first := post(handler, userID, "key-1", `{"user_id":"1"}`)
+		second := post(handler, userID, "key-1", `{"user_id":"1"}`)
+
+		assert.Equal(t, 1, next.created, "повтор не должен создавать ресурс заново")
+		assert.Equal(t, http.StatusCreated, second.Code)
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, "application/json", second.Header().Get("Content-Type"))
+		assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+		assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+	})
+
+	t.Run("другой ключ создает новый ресурс", func(t *testing.T) {
+		next := &creatingHandler{status: http.StatusCreated}
+		handler := IdempotencyMiddleware(newTestIdempotencyStore(t), logger.NewNoop())(next)
+
+		first := post(handler, userID, "key-1", `{"user_id":"1"}`)
+		second := post(handler, userID, "key-2", `{"user_id":"1"}`)
+
+		assert.Equal(t, 2, next.created)
+		assert.NotEqual(t, first.Body.String(), second.Body.String())
+	})
+
+	t.Run("ошибка клиента повторяется так же", func(t *testing.T) {
+		next := &creatingHandler{status: http.StatusConflict}
+		handler := IdempotencyMiddleware(newTestIdempotencyStore(t), logger.NewNoop())(next)
+
+		post(handler, userID, "key-1", `{}`)
+		second := post(handler, userID, "key-1", `{}`)
+
+		assert.Equal(t, 1, next.created)
+		assert.Equal(t, http.StatusConflict, second.Code)
+	})
+
+	t.Run("ответ 5xx не сохраняется", func(t *testing.T) {
+		next := &creatingHandler{status: http.StatusInternalServerError}
+		handler := IdempotencyMiddleware(newTestIdempotencyStore(t), logger.NewNoop())(next)
+
+		post(handler, userID, "key-1", `{}`)
+		next.status = http.StatusCreated
+		second := post(handler, userID, "key-1", `{}`)
+
+		assert.Equal(t, 2, next.created, "после 5xx повтор выполняется заново")
+		assert.Equal(t, http.StatusCreated, second.Code)
+	})
+
+	t.Run("тот же ключ с другим телом отклоняется", func(t *testing.T) {
+		next := &creatingHandler{status: http.StatusCreated}
+		handler := IdempotencyMiddleware(newTestIdempotencyStore(t), logger.NewNoop())(next)
+
+		post(handler, userID, "key-1", `{"user_id":"1"}`)
+		second := post(handler, userID, "key-1", `{"user_id":"2"}`)
+
+		assert.Equal(t, 1, next.created)
+		assert.Equal(t, http.StatusUnprocessableEntity, second.Code)
+	})
+
+	t.Run("ключи разных пользователей не пересекаются", func(t *testing.T) {
+		next := &creatingHandler{status: http.StatusCreated}
+		handler := IdempotencyMiddleware(newTestIdempotencyStore(t), logger.NewNoop())(next)
+
+		post(handler, userID, "key-1", `{}`)
+		second := post(handler, uuid.New(), "key-1", `{}`)
+
+		assert.Equal(t, 2, next.created)
+		assert.Empty(t, second.Header().Get(IdempotentReplayedHeader))
+	})
+
+	t.Run("повтор во время обработки исходного запроса", func(t *testing.T) {
+		store := newTestIdempotencyStore(t)
+		next := &creatingHandler{status: http.StatusCreated}
+		handler := IdempotencyMiddleware(store, logger.NewNoop())(next)
+
+		// Исходный запрос занял ключ и еще не завершился
+		scope := "user:" + userID.String() + ":" + http.MethodPost + ":/api/v1/passes"
+		_, started, err := store.Begin(context.Background(), scope+":key-1", bodyFingerprint([]byte(`{}`)))
+		require.NoError(t, err)
+		require.True(t, started)
+
+		w := post(handler, userID, "key-1", `{}`)
+
+		assert.Equal(t, 0, next.created)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("без заголовка каждый запрос выполняется", func(t *testing.T) {
+		next := &creatingHandler{status: http.StatusCreated}
+		handler := IdempotencyMiddleware(newTestIdempotencyStore(t), logger.NewNoop())(next)
+
+		post(handler, userID, "", `{}`)
+		post(handler, userID, "", `{}`)
+
+		assert.Equal(t, 2, next.created)
+	})
+
+	t.Run("слишком длинный ключ", func(t *testing.T) {
+		next := &creatingHandler{status: http.StatusCreated}
+		handler := IdempotencyMiddleware(newTestIdempotencyStore(t), logger.NewNoop())(next)
+
+		w := post(handler, userID, strings.Repeat("k", maxIdempotencyKeyLength+1), `{}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, 0, next.created)
+	})
+}
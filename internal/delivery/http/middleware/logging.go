@@ -25,6 +25,18 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Flush пробрасывает сброс буфера (SSE, потоковый экспорт)
+func (rw *responseWriter) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap нужен http.ResponseController для доступа к исходному writer'у
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // LoggingMiddleware логирует все HTTP запросы
 func LoggingMiddleware(log logger.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -42,7 +54,8 @@ func LoggingMiddleware(log logger.Logger) func(http.Handler) http.Handler {
 
 			// Логируем
 			duration := time.Since(start)
-			log.Info("HTTP request", map[string]interface{}{
+			// Logger запроса (с request_id), если RequestLoggerMiddleware стоит раньше
+			logger.FromContextOr(r.Context(), log).Info("HTTP request", map[string]interface{}{
 				"method":      r.Method,
 				"path":        r.URL.Path,
 				"status":      rw.statusCode,
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/frontandrew/gate/internal/pkg/metrics"
+	"github.com/go-chi/chi/v5"
+)
+
+// unmatchedRoute - метка запросов, не совпавших ни с одним маршрутом
+const unmatchedRoute = "unmatched"
+
+// MetricsMiddleware учитывает количество и длительность HTTP запросов по шаблону маршрута
+// Должен стоять на корневом роутере: шаблон известен только после маршрутизации
+func MetricsMiddleware(m *metrics.Metrics) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			rw := &responseWriter{
+				ResponseWriter: w,
+				statusCode:     http.StatusOK,
+			}
+
+			next.ServeHTTP(rw, r)
+
+			route := unmatchedRoute
+			if rctx := chi.RouteContext(r.Context()); rctx != nil {
+				if pattern := rctx.RoutePattern(); pattern != "" {
+					route = pattern
+				}
+			}
+
+			m.ObserveHTTPRequest(r.Method, route, rw.statusCode, time.Since(start))
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/frontandrew/gate/internal/pkg/metrics"
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	r := chi.NewRouter()
+	r.Use(MetricsMiddleware(metrics.New(registry)))
+	r.Get("/api/v1/vehicles/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if chi.URLParam(r, "id") == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for _, path := range []string{"/api/v1/vehicles/1", "/api/v1/vehicles/2", "/api/v1/vehicles/missing", "/unknown"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	// Запросы группируются по шаблону маршрута, а не по пути
+	expected := `
+# HELP gate_http_requests_total Количество HTTP запросов по маршруту и статусу ответа
+# TYPE gate_http_requests_total counter
+gate_http_requests_total{method="GET",route="/api/v1/vehicles/{id}",status="200"} 2
+gate_http_requests_total{method="GET",route="/api/v1/vehicles/{id}",status="404"} 1
+gate_http_requests_total{method="GET",route="unmatched",status="404"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "gate_http_requests_total"))
+
+	series, err := testutil.GatherAndCount(registry, "gate_http_request_duration_seconds")
+	require.NoError(t, err)
+	require.Equal(t, 2, series, "гистограмма длительности по маршрутам без учета статуса")
+}
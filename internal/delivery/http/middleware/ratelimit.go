@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/pkg/ratelimit"
+)
+
+// maxKeyBodySize ограничивает объем тела, читаемого для извлечения ключа лимита
+const maxKeyBodySize = 10 << 20
+
+// RateLimitKeyFunc извлекает из запроса ключ, по которому считается лимит
+type RateLimitKeyFunc func(r *http.Request) string
+
+// RateLimitMiddleware ограничивает частоту запросов по ключу и возвращает 429 при превышении
+// При недоступности Redis запрос пропускается (fail-open), чтобы не блокировать шлагбаумы
+func RateLimitMiddleware(limiter *ratelimit.Limiter, keyFunc RateLimitKeyFunc, log logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := keyFunc(r)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			result, err := limiter.Allow(r.Context(), key)
+			if err != nil {
+				log.Error("Rate limit check failed", map[string]interface{}{
+					"key":   key,
+					"error": err.Error(),
+				})
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+
+			if !result.Allowed {
+				log.Warn("Rate limit exceeded", map[string]interface{}{
+					"key": key,
+				})
+				w.Header().Set("Retry-After", strconv.Itoa(int(result.RetryAfter.Seconds())+1))
+				respondError(w, http.StatusTooManyRequests, "Too many requests")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// AccessCheckRateLimitKey возвращает ключ лимита для проверки доступа:
+// пользователь (если запрос аутентифицирован), иначе ворота из тела запроса, иначе IP
+func AccessCheckRateLimitKey(r *http.Request) string {
+	if claims, ok := GetUserClaims(r.Context()); ok {
+		return "user:" + claims.UserID.String()
+	}
+
+	if gateID := peekJSONField(r, "gate_id"); gateID != "" {
+		return "gate:" + gateID
+	}
+
+	return ClientIPRateLimitKey(r)
+}
+
+// ClientIPRateLimitKey возвращает ключ лимита по IP клиента
+func ClientIPRateLimitKey(r *http.Request) string {
+	return "ip:" + clientIP(r)
+}
+
+// EmailRateLimitKey возвращает ключ лимита по email из тела запроса (вход, запрос сброса пароля)
+// Ограничивает подбор пароля к одному аккаунту и рассылку писем на один адрес с разных IP;
+// без email лимит не применяется
+func EmailRateLimitKey(r *http.Request) string {
+	email := strings.ToLower(strings.TrimSpace(peekJSONField(r, "email")))
+	if email == "" {
+		return ""
+	}
+	return "email:" + email
+}
+
+// peekJSONField читает строковое поле верхнего уровня из JSON тела и восстанавливает тело для handler'а
+func peekJSONField(r *http.Request, field string) string {
+	if r.Body == nil {
+		return ""
+	}
+
+	// Непрочитанный остаток (если тело больше лимита) склеивается обратно
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxKeyBodySize))
+	r.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), r.Body), Closer: r.Body}
+	if err != nil {
+		return ""
+	}
+
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return ""
+	}
+
+	var value string
+	if err := json.Unmarshal(payload[field], &value); err != nil {
+		return ""
+	}
+	return value
+}
+
+// readCloser объединяет восстановленное тело с Close исходного
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// clientIP возвращает IP клиента без порта
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
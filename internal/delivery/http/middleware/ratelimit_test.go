@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/pkg/ratelimit"
+	"github.com/frontandrew/gate/internal/pkg/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLimiter(t *testing.T, config ratelimit.Config) *ratelimit.Limiter {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	host, port, err := net.SplitHostPort(mr.Addr())
+	require.NoError(t, err)
+
+	client, err := redis.NewClient(redis.Config{Host: host, Port: port})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	return ratelimit.NewLimiter(client, "ratelimit:test:", config)
+}
+
+func TestRateLimitMiddleware_AccessCheck(t *testing.T) {
+	limiter := newTestLimiter(t, ratelimit.Config{Requests: 3, Burst: 2, Window: time.Minute})
+
+	// Handler проверяет, что тело запроса доступно после извлечения gate_id
+	handler := RateLimitMiddleware(limiter, AccessCheckRateLimitKey, logger.NewNoop())(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			assert.Contains(t, string(body), "gate_id")
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+
+	check := func(gateID string) *httptest.ResponseRecorder {
+		body := `{"image_base64":"image","gate_id":"` + gateID + `","direction":"IN"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/access/check", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	// Rate + burst запросов проходят
+	for i := 0; i < 5; i++ {
+		w := check("gate-1")
+		require.Equal(t, http.StatusOK, w.Code, "request %d", i+1)
+	}
+
+	// Следующий запрос от тех же ворот отклоняется
+	w := check("gate-1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+
+	// Другие ворота считаются отдельно
+	w = check("gate-2")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRateLimitMiddleware_Login(t *testing.T) {
+	limiter := newTestLimiter(t, ratelimit.Config{Requests: 2, Window: time.Minute})
+	log := logger.NewNoop()
+
+	// Как в роутере: отдельные лимиты на IP и на email
+	handler := RateLimitMiddleware(limiter, ClientIPRateLimitKey, log)(
+		RateLimitMiddleware(limiter, EmailRateLimitKey, log)(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				assert.Contains(t, string(body), "password")
+				w.WriteHeader(http.StatusOK)
+			}),
+		),
+	)
+
+	login := func(ip, email string) *httptest.ResponseRecorder {
+		body := `{"email":"` + email + `","password":"password123"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader(body))
+		req.RemoteAddr = ip + ":12345"
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("лимит по IP", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, login("10.0.0.1", "a@example.com").Code)
+		require.Equal(t, http.StatusOK, login("10.0.0.1", "b@example.com").Code)
+
+		w := login("10.0.0.1", "c@example.com")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+		assert.Equal(t, http.StatusOK, login("10.0.0.2", "d@example.com").Code)
+	})
+
+	t.Run("лимит по email с разных IP", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, login("10.0.1.1", "victim@example.com").Code)
+		require.Equal(t, http.StatusOK, login("10.0.1.2", "Victim@Example.com").Code)
+
+		w := login("10.0.1.3", "victim@example.com")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	})
+}
+
+func TestRateLimitMiddleware_Register(t *testing.T) {
+	limiter := newTestLimiter(t, ratelimit.Config{Requests: 3, Window: time.Hour})
+
+	handler := RateLimitMiddleware(limiter, ClientIPRateLimitKey, logger.NewNoop())(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+		}),
+	)
+
+	register := func(ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/register", strings.NewReader(`{}`))
+		req.RemoteAddr = ip + ":12345"
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := 0; i < 3; i++ {
+		require.Equal(t, http.StatusCreated, register("10.0.0.1").Code, "request %d", i+1)
+	}
+
+	w := register("10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusCreated, register("10.0.0.2").Code)
+}
@@ -13,7 +13,7 @@ func RecoveryMiddleware(log logger.Logger) func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
 				if err := recover(); err != nil {
-					log.Error("Panic recovered", map[string]interface{}{
+					logger.FromContextOr(r.Context(), log).Error("Panic recovered", map[string]interface{}{
 						"error":       err,
 						"stack":       string(debug.Stack()),
 						"method":      r.Method,
@@ -21,9 +21,7 @@ func RecoveryMiddleware(log logger.Logger) func(http.Handler) http.Handler {
 						"remote_addr": r.RemoteAddr,
 					})
 
-					w.Header().Set("Content-Type", "application/json")
-					w.WriteHeader(http.StatusInternalServerError)
-					_, _ = w.Write([]byte(`{"error":"Internal server error"}`))
+					respondError(w, http.StatusInternalServerError, "Internal server error")
 				}
 			}()
 
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	chiMiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// RequestLoggerMiddleware сохраняет в контексте logger с request_id запроса
+// Должен стоять после chiMiddleware.RequestID; handler'ы и сервисы получают его через logger.FromContext
+func RequestLoggerMiddleware(log logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestLog := log
+			if requestID := chiMiddleware.GetReqID(r.Context()); requestID != "" {
+				requestLog = log.With("request_id", requestID)
+			}
+
+			next.ServeHTTP(w, r.WithContext(logger.WithContext(r.Context(), requestLog)))
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/jwt"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	chiMiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// logEntry - запись recordingLogger вместе с полями, добавленными через With
+type logEntry struct {
+	msg    string
+	fields map[string]interface{}
+}
+
+// recordingLogger запоминает записи всех уровней; logger'ы, полученные через With, пишут в общий список
+type recordingLogger struct {
+	logger.Logger
+	mu      *sync.Mutex
+	entries *[]logEntry
+	with    map[string]interface{}
+}
+
+func newRecordingLogger() *recordingLogger {
+	return &recordingLogger{
+		Logger:  logger.NewNoop(),
+		mu:      &sync.Mutex{},
+		entries: &[]logEntry{},
+		with:    map[string]interface{}{},
+	}
+}
+
+func (l *recordingLogger) record(msg string, fields []map[string]interface{}) {
+	entry := logEntry{msg: msg, fields: map[string]interface{}{}}
+	for key, value := range l.with {
+		entry.fields[key] = value
+	}
+	for _, fieldMap := range fields {
+		for key, value := range fieldMap {
+			entry.fields[key] = value
+		}
+	}
+
+	l.mu.Lock()
+	*l.entries = append(*l.entries, entry)
+	l.mu.Unlock()
+}
+
+func (l *recordingLogger) Info(msg string, fields ...map[string]interface{})  { l.record(msg, fields) }
+func (l *recordingLogger) Warn(msg string, fields ...map[string]interface{})  { l.record(msg, fields) }
+func (l *recordingLogger) Error(msg string, fields ...map[string]interface{}) { l.record(msg, fields) }
+
+func (l *recordingLogger) With(key string, value interface{}) logger.Logger {
+	with := make(map[string]interface{}, len(l.with)+1)
+	for k, v := range l.with {
+		with[k] = v
+	}
+	with[key] = value
+	return &recordingLogger{Logger: l.Logger, mu: l.mu, entries: l.entries, with: with}
+}
+
+func TestRequestLoggerMiddleware(t *testing.T) {
+	log := newRecordingLogger()
+
+	handler := chiMiddleware.RequestID(
+		RequestLoggerMiddleware(log)(
+			LoggingMiddleware(log)(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					logger.FromContext(r.Context()).Warn("Handler message")
+					w.WriteHeader(http.StatusNoContent)
+				}),
+			),
+		),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/vehicles", nil)
+	req.Header.Set("X-Request-Id", "req-123")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// И запись handler'а, и итоговая запись о запросе коррелируются по request_id
+	require.Len(t, *log.entries, 2)
+	for _, entry := range *log.entries {
+		assert.Equal(t, "req-123", entry.fields["request_id"], entry.msg)
+	}
+	assert.Equal(t, "Handler message", (*log.entries)[0].msg)
+	assert.Equal(t, "HTTP request", (*log.entries)[1].msg)
+}
+
+func TestFromContext_WithoutMiddleware(t *testing.T) {
+	log := newRecordingLogger()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	// Без middleware сервисы пишут в собственный logger
+	logger.FromContextOr(req.Context(), log).Warn("Fallback message")
+
+	require.Len(t, *log.entries, 1)
+	assert.NotContains(t, (*log.entries)[0].fields, "request_id")
+}
+
+func TestRequestLoggerMiddleware_AuthenticatedUser(t *testing.T) {
+	tokenService := jwt.NewTokenService("test-secret", time.Hour, 24*time.Hour)
+	user := &domain.User{ID: uuid.New(), Email: "user@test.com", Role: domain.RoleUser}
+	pair, err := tokenService.GenerateTokenPair(user)
+	require.NoError(t, err)
+
+	log := newRecordingLogger()
+	handler := chiMiddleware.RequestID(
+		RequestLoggerMiddleware(log)(
+			AuthMiddleware(tokenService, nil)(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					logger.FromContext(r.Context()).Info("Handler message")
+				}),
+			),
+		),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/me", nil)
+	req.Header.Set("X-Request-Id", "req-456")
+	req.Header.Set("Authorization", "Bearer "+pair.AccessToken)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.Len(t, *log.entries, 1)
+	assert.Equal(t, "req-456", (*log.entries)[0].fields["request_id"])
+	assert.Equal(t, user.ID, (*log.entries)[0].fields["user_id"])
+}
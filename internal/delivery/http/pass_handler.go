@@ -2,8 +2,11 @@ package http
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/frontandrew/gate/internal/delivery/http/middleware"
 	"github.com/frontandrew/gate/internal/domain"
@@ -16,21 +19,38 @@ import (
 type PassService interface {
 	CreatePass(ctx context.Context, req *pass.CreatePassRequest) (*domain.Pass, error)
 	GetPassesByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Pass, error)
+	ListPasses(ctx context.Context, filter domain.PassFilter, limit, offset int) ([]*domain.Pass, error)
+	CountPasses(ctx context.Context, filter domain.PassFilter) (int, error)
 	GetPassByID(ctx context.Context, passID uuid.UUID) (*domain.Pass, error)
 	RevokePass(ctx context.Context, passID, revokedBy uuid.UUID, reason string) error
+	ExtendPass(ctx context.Context, passID, extendedBy uuid.UUID, validUntil time.Time) (*domain.Pass, error)
+	GetPassWithVehicles(ctx context.Context, passID uuid.UUID) (*domain.Pass, error)
+	LoadVehicles(ctx context.Context, passes []*domain.Pass) error
+	AddVehicleToPass(ctx context.Context, passID, vehicleID, addedBy uuid.UUID) error
+	RemoveVehicleFromPass(ctx context.Context, passID, vehicleID uuid.UUID) error
+	CreateGuestPass(ctx context.Context, req *pass.CreateGuestPassRequest) (*domain.Pass, error)
+}
+
+// PassHandlerConfig содержит настройки разбора запросов на выдачу пропусков
+type PassHandlerConfig struct {
+	// RequireValidFrom отклоняет запросы без valid_from;
+	// иначе отсутствующее начало действия означает "с текущего момента"
+	RequireValidFrom bool
 }
 
 // PassHandler обрабатывает запросы связанные с пропусками
 type PassHandler struct {
 	passService PassService
 	logger      logger.Logger
+	config      PassHandlerConfig
 }
 
 // NewPassHandler создает новый handler
-func NewPassHandler(passService PassService, logger logger.Logger) *PassHandler {
+func NewPassHandler(passService PassService, logger logger.Logger, config PassHandlerConfig) *PassHandler {
 	return &PassHandler{
 		passService: passService,
 		logger:      logger,
+		config:      config,
 	}
 }
 
@@ -38,8 +58,7 @@ func NewPassHandler(passService PassService, logger logger.Logger) *PassHandler
 // POST /api/v1/passes
 func (h *PassHandler) CreatePass(w http.ResponseWriter, r *http.Request) {
 	var req pass.CreatePassRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
@@ -50,15 +69,43 @@ func (h *PassHandler) CreatePass(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.ValidFrom.IsZero() {
+		if h.config.RequireValidFrom {
+			respondFieldError(w, http.StatusBadRequest, "valid_from", "valid_from is required")
+			return
+		}
+		req.ValidFrom = time.Now()
+	}
+
 	// Устанавливаем created_by
 	req.CreatedBy = claims.UserID
 
+	// created_by заполняется из claims, поэтому проверяем запрос только после этого
+	if !validateRequest(w, &req) {
+		return
+	}
+
 	p, err := h.passService.CreatePass(r.Context(), &req)
 	if err != nil {
-		h.logger.Error("Failed to create pass", map[string]interface{}{
-			"error": err.Error(),
-		})
-		respondError(w, http.StatusInternalServerError, "Failed to create pass")
+		switch {
+		case errors.Is(err, domain.ErrInvalidDateRange):
+			respondDomainFieldError(w, err, "valid_until", "valid_until must be after valid_from")
+		case errors.Is(err, domain.ErrInvalidZone):
+			respondDomainFieldError(w, err, "zone", "zone must contain only letters, digits, '.', '_' and '-' (at most 64 characters)")
+		case errors.Is(err, domain.ErrVehicleNotFound):
+			respondDomainFieldError(w, err, "vehicle_ids", "Vehicle not found")
+		case errors.Is(err, domain.ErrPassVehicleOwnerMismatch):
+			respondDomainFieldError(w, err, "vehicle_ids", "Vehicle does not belong to the pass owner")
+		case errors.Is(err, domain.ErrVehicleInactive):
+			respondDomainFieldError(w, err, "vehicle_ids", "Vehicle is inactive")
+		default:
+			if !respondDomainError(w, err) {
+				requestLogger(r, h.logger).Error("Failed to create pass", map[string]interface{}{
+					"error": err.Error(),
+				})
+				respondError(w, http.StatusInternalServerError, "Failed to create pass")
+			}
+		}
 		return
 	}
 
@@ -68,8 +115,141 @@ func (h *PassHandler) CreatePass(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// CreateGuestPass выдает гостевой пропуск от имени текущего пользователя
+// POST /api/v1/passes/guest
+func (h *PassHandler) CreateGuestPass(w http.ResponseWriter, r *http.Request) {
+	var req pass.CreateGuestPassRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	claims, ok := middleware.GetUserClaims(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Гостевой пропуск всегда выдается текущему пользователю
+	req.UserID = claims.UserID
+
+	p, err := h.passService.CreateGuestPass(r.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrForbidden):
+			respondDomainErrorMessage(w, err, "Vehicle belongs to another user")
+		default:
+			if !respondDomainError(w, err) {
+				requestLogger(r, h.logger).Error("Failed to create guest pass", map[string]interface{}{
+					"error": err.Error(),
+				})
+				respondError(w, http.StatusInternalServerError, "Failed to create guest pass")
+			}
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"success": true,
+		"data":    p,
+	})
+}
+
+// ListPasses возвращает пропуска с фильтрами и пагинацией (только для админов и охранников)
+// GET /api/v1/passes?user_id=&pass_type=&is_active=&from=&to=&limit=&offset=[&include=vehicles]
+func (h *PassHandler) ListPasses(w http.ResponseWriter, r *http.Request) {
+	limit, offset := getPaginationParams(r)
+
+	filter, errMsg := parsePassFilter(r.URL.Query())
+	if errMsg != "" {
+		respondError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	withVehicles, ok := parseIncludeVehicles(w, r)
+	if !ok {
+		return
+	}
+
+	passes, err := h.passService.ListPasses(r.Context(), filter, limit, offset)
+	if err == nil && withVehicles {
+		err = h.passService.LoadVehicles(r.Context(), passes)
+	}
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to list passes", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to list passes")
+		}
+		return
+	}
+
+	total, err := h.passService.CountPasses(r.Context(), filter)
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to count passes", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to list passes")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    passes,
+		"pagination": map[string]int{
+			"limit":  limit,
+			"offset": offset,
+			"total":  total,
+		},
+	})
+}
+
+// parsePassFilter разбирает фильтры user_id, pass_type, is_active и период действия from, to
+// Возвращает текст ошибки для ответа 400 (пусто - фильтр корректен)
+func parsePassFilter(query url.Values) (domain.PassFilter, string) {
+	var filter domain.PassFilter
+
+	if userIDStr := query.Get("user_id"); userIDStr != "" {
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return filter, "Invalid user_id"
+		}
+		filter.UserID = &userID
+	}
+
+	if passType := domain.PassType(query.Get("pass_type")); passType != "" {
+		if passType != domain.PassTypePermanent && passType != domain.PassTypeTemporary {
+			return filter, "Invalid pass_type: expected permanent or temporary"
+		}
+		filter.PassType = passType
+	}
+
+	if isActiveStr := query.Get("is_active"); isActiveStr != "" {
+		isActive, err := strconv.ParseBool(isActiveStr)
+		if err != nil {
+			return filter, "Invalid is_active: expected true or false"
+		}
+		filter.IsActive = &isActive
+	}
+
+	var err error
+	if filter.ValidFrom, err = parseTimeParam(query.Get("from"), false); err != nil {
+		return filter, "Invalid from: expected RFC3339 or YYYY-MM-DD"
+	}
+	if filter.ValidTo, err = parseTimeParam(query.Get("to"), true); err != nil {
+		return filter, "Invalid to: expected RFC3339 or YYYY-MM-DD"
+	}
+	if filter.ValidFrom != nil && filter.ValidTo != nil && !filter.ValidTo.After(*filter.ValidFrom) {
+		return filter, "Invalid period: to must be after from"
+	}
+
+	return filter, ""
+}
+
 // GetMyPasses возвращает все пропуска текущего пользователя
-// GET /api/v1/passes/me
+// GET /api/v1/passes/me[?include=vehicles]
 func (h *PassHandler) GetMyPasses(w http.ResponseWriter, r *http.Request) {
 	claims, ok := middleware.GetUserClaims(r.Context())
 	if !ok {
@@ -77,12 +257,22 @@ func (h *PassHandler) GetMyPasses(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	withVehicles, ok := parseIncludeVehicles(w, r)
+	if !ok {
+		return
+	}
+
 	passes, err := h.passService.GetPassesByUser(r.Context(), claims.UserID)
+	if err == nil && withVehicles {
+		err = h.passService.LoadVehicles(r.Context(), passes)
+	}
 	if err != nil {
-		h.logger.Error("Failed to get user passes", map[string]interface{}{
-			"error": err.Error(),
-		})
-		respondError(w, http.StatusInternalServerError, "Failed to get passes")
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to get user passes", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to get passes")
+		}
 		return
 	}
 
@@ -92,8 +282,22 @@ func (h *PassHandler) GetMyPasses(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// parseIncludeVehicles разбирает параметр include: автомобили пропусков загружаются только по запросу,
+// чтобы списки не делали лишних запросов. При неизвестном значении отвечает 400 и возвращает false
+func parseIncludeVehicles(w http.ResponseWriter, r *http.Request) (bool, bool) {
+	include := r.URL.Query().Get("include")
+	switch include {
+	case "":
+		return false, true
+	case "vehicles":
+		return true, true
+	}
+	respondFieldError(w, http.StatusBadRequest, "include", "Invalid include: expected vehicles")
+	return false, false
+}
+
 // GetPassByID возвращает пропуск по ID
-// GET /api/v1/passes/:id
+// GET /api/v1/passes/:id[?include=vehicles]
 func (h *PassHandler) GetPassByID(w http.ResponseWriter, r *http.Request) {
 	passIDStr := getPathParam(r, "id")
 	passID, err := uuid.Parse(passIDStr)
@@ -102,20 +306,28 @@ func (h *PassHandler) GetPassByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	p, err := h.passService.GetPassByID(r.Context(), passID)
+	withVehicles, ok := parseIncludeVehicles(w, r)
+	if !ok {
+		return
+	}
+
+	var p *domain.Pass
+	if withVehicles {
+		p, err = h.passService.GetPassWithVehicles(r.Context(), passID)
+	} else {
+		p, err = h.passService.GetPassByID(r.Context(), passID)
+	}
 	if err != nil {
-		if err == domain.ErrPassNotFound {
-			respondError(w, http.StatusNotFound, "Pass not found")
-			return
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to get pass", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to get pass")
 		}
-		h.logger.Error("Failed to get pass", map[string]interface{}{
-			"error": err.Error(),
-		})
-		respondError(w, http.StatusInternalServerError, "Failed to get pass")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]interface{}{
+	respondJSONWithETag(w, r, http.StatusOK, map[string]interface{}{
 		"success": true,
 		"data":    p,
 	})
@@ -140,20 +352,17 @@ func (h *PassHandler) RevokePass(w http.ResponseWriter, r *http.Request) {
 	var body struct {
 		Reason string `json:"reason"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeJSON(w, r, &body) {
 		return
 	}
 
 	if err := h.passService.RevokePass(r.Context(), passID, claims.UserID, body.Reason); err != nil {
-		if err == domain.ErrPassNotFound {
-			respondError(w, http.StatusNotFound, "Pass not found")
-			return
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to revoke pass", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to revoke pass")
 		}
-		h.logger.Error("Failed to revoke pass", map[string]interface{}{
-			"error": err.Error(),
-		})
-		respondError(w, http.StatusInternalServerError, "Failed to revoke pass")
 		return
 	}
 
@@ -162,3 +371,140 @@ func (h *PassHandler) RevokePass(w http.ResponseWriter, r *http.Request) {
 		"message": "Pass revoked successfully",
 	})
 }
+
+// ExtendPass продлевает временный пропуск (только для админов и охранников)
+// POST /api/v1/passes/:id/extend
+func (h *PassHandler) ExtendPass(w http.ResponseWriter, r *http.Request) {
+	passID, err := uuid.Parse(getPathParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid pass ID")
+		return
+	}
+
+	claims, ok := middleware.GetUserClaims(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var body struct {
+		ValidUntil time.Time `json:"valid_until" validate:"required"`
+	}
+	if !decodeAndValidate(w, r, &body) {
+		return
+	}
+
+	p, err := h.passService.ExtendPass(r.Context(), passID, claims.UserID, body.ValidUntil)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrPassAlreadyRevoked):
+			respondDomainErrorMessage(w, err, "Revoked pass cannot be extended")
+		case errors.Is(err, domain.ErrPassNotExtendable):
+			respondDomainErrorMessage(w, err, "Permanent pass cannot be extended")
+		case errors.Is(err, domain.ErrInvalidDateRange):
+			respondDomainFieldError(w, err, "valid_until", "valid_until must be in the future and after the current valid_until")
+		default:
+			if !respondDomainError(w, err) {
+				requestLogger(r, h.logger).Error("Failed to extend pass", map[string]interface{}{
+					"error": err.Error(),
+				})
+				respondError(w, http.StatusInternalServerError, "Failed to extend pass")
+			}
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    p,
+	})
+}
+
+// AddVehicleToPass привязывает автомобиль владельца пропуска к пропуску (только для админов и охранников)
+// POST /api/v1/passes/:id/vehicles
+func (h *PassHandler) AddVehicleToPass(w http.ResponseWriter, r *http.Request) {
+	passID, err := uuid.Parse(getPathParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid pass ID")
+		return
+	}
+
+	claims, ok := middleware.GetUserClaims(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var body struct {
+		VehicleID uuid.UUID `json:"vehicle_id" validate:"required"`
+	}
+	if !decodeAndValidate(w, r, &body) {
+		return
+	}
+
+	if err := h.passService.AddVehicleToPass(r.Context(), passID, body.VehicleID, claims.UserID); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrPassVehicleOwnerMismatch):
+			respondDomainFieldError(w, err, "vehicle_id", "Vehicle does not belong to the pass owner")
+		case errors.Is(err, domain.ErrVehicleInactive):
+			respondDomainFieldError(w, err, "vehicle_id", "Vehicle is inactive")
+		default:
+			if !respondDomainError(w, err) {
+				requestLogger(r, h.logger).Error("Failed to add vehicle to pass", map[string]interface{}{
+					"error": err.Error(),
+				})
+				respondError(w, http.StatusInternalServerError, "Failed to add vehicle to pass")
+			}
+		}
+		return
+	}
+
+	h.respondPassWithVehicles(w, r, passID)
+}
+
+// RemoveVehicleFromPass отвязывает автомобиль от пропуска (только для админов и охранников)
+// DELETE /api/v1/passes/:id/vehicles/:vehicleId
+func (h *PassHandler) RemoveVehicleFromPass(w http.ResponseWriter, r *http.Request) {
+	passID, err := uuid.Parse(getPathParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid pass ID")
+		return
+	}
+
+	vehicleID, err := uuid.Parse(getPathParam(r, "vehicleId"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid vehicle ID")
+		return
+	}
+
+	if err := h.passService.RemoveVehicleFromPass(r.Context(), passID, vehicleID); err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to remove vehicle from pass", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to remove vehicle from pass")
+		}
+		return
+	}
+
+	h.respondPassWithVehicles(w, r, passID)
+}
+
+// respondPassWithVehicles отвечает пропуском с привязанными автомобилями после изменения их состава
+func (h *PassHandler) respondPassWithVehicles(w http.ResponseWriter, r *http.Request, passID uuid.UUID) {
+	p, err := h.passService.GetPassWithVehicles(r.Context(), passID)
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to get pass vehicles", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to get pass")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    p,
+	})
+}
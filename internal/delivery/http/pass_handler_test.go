@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/frontandrew/gate/internal/domain"
 	"github.com/frontandrew/gate/internal/pkg/logger"
@@ -15,6 +17,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPassHandler_CreatePass(t *testing.T) {
@@ -68,6 +71,69 @@ func TestPassHandler_CreatePass(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "автомобиль не найден",
+			requestBody: pass.CreatePassRequest{
+				UserID:     uuid.New(),
+				VehicleIDs: []uuid.UUID{uuid.New()},
+				PassType:   domain.PassTypePermanent,
+			},
+			setupContext: func() context.Context {
+				return CreateAuthContext(t, uuid.New(), "admin@test.com", domain.RoleAdmin)
+			},
+			mockSetup: func(m *MockPassService) {
+				m.On("CreatePass", mock.Anything, mock.AnythingOfType("*pass.CreatePassRequest")).
+					Return(nil, fmt.Errorf("vehicle %s: %w", uuid.New(), domain.ErrVehicleNotFound))
+			},
+			expectedStatus: http.StatusNotFound,
+			checkResponse: func(t *testing.T, resp map[string]interface{}) {
+				errBody := resp["error"].(map[string]interface{})
+				assert.Equal(t, "VEHICLE_NOT_FOUND", errBody["code"])
+				assert.Equal(t, "vehicle_ids", errBody["field"])
+			},
+		},
+		{
+			name: "автомобиль другого владельца",
+			requestBody: pass.CreatePassRequest{
+				UserID:     uuid.New(),
+				VehicleIDs: []uuid.UUID{uuid.New()},
+				PassType:   domain.PassTypePermanent,
+			},
+			setupContext: func() context.Context {
+				return CreateAuthContext(t, uuid.New(), "admin@test.com", domain.RoleAdmin)
+			},
+			mockSetup: func(m *MockPassService) {
+				m.On("CreatePass", mock.Anything, mock.AnythingOfType("*pass.CreatePassRequest")).
+					Return(nil, fmt.Errorf("vehicle %s: %w", uuid.New(), domain.ErrPassVehicleOwnerMismatch))
+			},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp map[string]interface{}) {
+				errBody := resp["error"].(map[string]interface{})
+				assert.Equal(t, "PASS_VEHICLE_OWNER_MISMATCH", errBody["code"])
+				assert.Equal(t, "vehicle_ids", errBody["field"])
+			},
+		},
+		{
+			name: "автомобиль деактивирован",
+			requestBody: pass.CreatePassRequest{
+				UserID:     uuid.New(),
+				VehicleIDs: []uuid.UUID{uuid.New()},
+				PassType:   domain.PassTypePermanent,
+			},
+			setupContext: func() context.Context {
+				return CreateAuthContext(t, uuid.New(), "admin@test.com", domain.RoleAdmin)
+			},
+			mockSetup: func(m *MockPassService) {
+				m.On("CreatePass", mock.Anything, mock.AnythingOfType("*pass.CreatePassRequest")).
+					Return(nil, fmt.Errorf("vehicle %s: %w", uuid.New(), domain.ErrVehicleInactive))
+			},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp map[string]interface{}) {
+				errBody := resp["error"].(map[string]interface{})
+				assert.Equal(t, "VEHICLE_INACTIVE", errBody["code"])
+				assert.Equal(t, "vehicle_ids", errBody["field"])
+			},
+		},
 		{
 			name:        "невалидный JSON",
 			requestBody: "invalid json",
@@ -92,7 +158,7 @@ func TestPassHandler_CreatePass(t *testing.T) {
 			tt.mockSetup(mockService)
 
 			log := logger.NewNoop()
-			handler := NewPassHandler(mockService, log)
+			handler := NewPassHandler(mockService, log, PassHandlerConfig{})
 
 			body, _ := json.Marshal(tt.requestBody)
 			req := httptest.NewRequest(http.MethodPost, "/api/v1/passes", bytes.NewReader(body))
@@ -112,6 +178,92 @@ func TestPassHandler_CreatePass(t *testing.T) {
 	}
 }
 
+func TestPassHandler_CreatePass_ValidFrom(t *testing.T) {
+	explicitFrom := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		config         PassHandlerConfig
+		requestBody    map[string]interface{}
+		mockSetup      func(*MockPassService)
+		expectedStatus int
+	}{
+		{
+			name:        "без valid_from пропуск действует с текущего момента",
+			requestBody: map[string]interface{}{"pass_type": domain.PassTypePermanent},
+			mockSetup: func(m *MockPassService) {
+				m.On("CreatePass", mock.Anything, mock.MatchedBy(func(req *pass.CreatePassRequest) bool {
+					return time.Since(req.ValidFrom) >= 0 && time.Since(req.ValidFrom) < time.Minute
+				})).Return(CreateTestPass(uuid.New(), uuid.New(), uuid.New(), domain.PassTypePermanent), nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:        "явный valid_from передается без изменений",
+			requestBody: map[string]interface{}{"pass_type": domain.PassTypePermanent, "valid_from": explicitFrom},
+			mockSetup: func(m *MockPassService) {
+				m.On("CreatePass", mock.Anything, mock.MatchedBy(func(req *pass.CreatePassRequest) bool {
+					return req.ValidFrom.Equal(explicitFrom)
+				})).Return(CreateTestPass(uuid.New(), uuid.New(), uuid.New(), domain.PassTypePermanent), nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:        "строгий режим отклоняет запрос без valid_from",
+			config:      PassHandlerConfig{RequireValidFrom: true},
+			requestBody: map[string]interface{}{"pass_type": domain.PassTypePermanent},
+			mockSetup: func(m *MockPassService) {
+				// Mock не будет вызван
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "строгий режим принимает явный valid_from",
+			config:      PassHandlerConfig{RequireValidFrom: true},
+			requestBody: map[string]interface{}{"pass_type": domain.PassTypePermanent, "valid_from": explicitFrom},
+			mockSetup: func(m *MockPassService) {
+				m.On("CreatePass", mock.Anything, mock.MatchedBy(func(req *pass.CreatePassRequest) bool {
+					return req.ValidFrom.Equal(explicitFrom)
+				})).Return(CreateTestPass(uuid.New(), uuid.New(), uuid.New(), domain.PassTypePermanent), nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "valid_until не позже valid_from",
+			requestBody: map[string]interface{}{
+				"pass_type":   domain.PassTypeTemporary,
+				"valid_until": time.Now().Add(-time.Hour),
+			},
+			mockSetup: func(m *MockPassService) {
+				m.On("CreatePass", mock.Anything, mock.AnythingOfType("*pass.CreatePassRequest")).
+					Return(nil, domain.ErrInvalidDateRange)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockPassService)
+			tt.mockSetup(mockService)
+
+			handler := NewPassHandler(mockService, logger.NewNoop(), tt.config)
+
+			tt.requestBody["user_id"] = uuid.New()
+			tt.requestBody["vehicle_ids"] = []uuid.UUID{uuid.New()}
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/passes", bytes.NewReader(body))
+			req = req.WithContext(CreateAuthContext(t, uuid.New(), "admin@test.com", domain.RoleAdmin))
+			w := httptest.NewRecorder()
+
+			handler.CreatePass(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
 func TestPassHandler_GetMyPasses(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -183,7 +335,7 @@ func TestPassHandler_GetMyPasses(t *testing.T) {
 			tt.mockSetup(mockService)
 
 			log := logger.NewNoop()
-			handler := NewPassHandler(mockService, log)
+			handler := NewPassHandler(mockService, log, PassHandlerConfig{})
 
 			req := httptest.NewRequest(http.MethodGet, "/api/v1/passes/me", nil)
 			req = req.WithContext(tt.setupContext())
@@ -261,7 +413,7 @@ func TestPassHandler_GetPassByID(t *testing.T) {
 			tt.mockSetup(mockService)
 
 			log := logger.NewNoop()
-			handler := NewPassHandler(mockService, log)
+			handler := NewPassHandler(mockService, log, PassHandlerConfig{})
 
 			req := httptest.NewRequest(http.MethodGet, "/api/v1/passes/"+tt.passID, nil)
 
@@ -285,6 +437,76 @@ func TestPassHandler_GetPassByID(t *testing.T) {
 	}
 }
 
+func TestPassHandler_GetPassByID_ETag(t *testing.T) {
+	passID := uuid.New()
+	p := CreateTestPass(passID, uuid.New(), uuid.New(), domain.PassTypePermanent)
+
+	getPass := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		mockService := new(MockPassService)
+		mockService.On("GetPassByID", mock.Anything, passID).Return(p, nil)
+
+		handler := NewPassHandler(mockService, logger.NewNoop(), PassHandlerConfig{})
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/passes/"+passID.String(), nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", passID.String())
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+		w := httptest.NewRecorder()
+		handler.GetPassByID(w, req)
+
+		mockService.AssertExpectations(t)
+		return w
+	}
+
+	// Первый запрос без If-None-Match для получения актуального ETag
+	fresh := getPass("")
+	assert.Equal(t, http.StatusOK, fresh.Code)
+	etag := fresh.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	tests := []struct {
+		name           string
+		ifNoneMatch    string
+		expectedStatus int
+		expectBody     bool
+	}{
+		{
+			name:           "совпадающий If-None-Match возвращает 304",
+			ifNoneMatch:    etag,
+			expectedStatus: http.StatusNotModified,
+		},
+		{
+			name:           "совпадение в списке слабых ETag",
+			ifNoneMatch:    `"stale", W/` + etag,
+			expectedStatus: http.StatusNotModified,
+		},
+		{
+			name:           "устаревший ETag возвращает 200",
+			ifNoneMatch:    `"stale"`,
+			expectedStatus: http.StatusOK,
+			expectBody:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := getPass(tt.ifNoneMatch)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, etag, w.Header().Get("ETag"))
+			if tt.expectBody {
+				assert.Equal(t, fresh.Body.String(), w.Body.String())
+			} else {
+				assert.Empty(t, w.Body.Bytes())
+			}
+		})
+	}
+}
+
 func TestPassHandler_RevokePass(t *testing.T) {
 	validID := uuid.New()
 	adminID := uuid.New()
@@ -384,7 +606,7 @@ func TestPassHandler_RevokePass(t *testing.T) {
 			tt.mockSetup(mockService)
 
 			log := logger.NewNoop()
-			handler := NewPassHandler(mockService, log)
+			handler := NewPassHandler(mockService, log, PassHandlerConfig{})
 
 			body, _ := json.Marshal(tt.requestBody)
 			req := httptest.NewRequest(http.MethodDelete, "/api/v1/passes/"+tt.passID+"/revoke", bytes.NewReader(body))
@@ -409,3 +631,432 @@ func TestPassHandler_RevokePass(t *testing.T) {
 		})
 	}
 }
+
+func TestPassHandler_ExtendPass(t *testing.T) {
+	passID := uuid.New()
+	adminID := uuid.New()
+	validUntil := time.Date(2030, 1, 2, 15, 0, 0, 0, time.UTC)
+	sameTime := mock.MatchedBy(func(v time.Time) bool { return v.Equal(validUntil) })
+
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		mockSetup      func(*MockPassService)
+		expectedStatus int
+		expectedField  string
+	}{
+		{
+			name:        "успешное продление",
+			requestBody: map[string]string{"valid_until": "2030-01-02T15:00:00Z"},
+			mockSetup: func(m *MockPassService) {
+				m.On("ExtendPass", mock.Anything, passID, adminID, sameTime).
+					Return(&domain.Pass{ID: passID, PassType: domain.PassTypeTemporary, ValidUntil: &validUntil, ExtendedBy: &adminID}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "без valid_until",
+			requestBody:    map[string]string{},
+			mockSetup:      func(m *MockPassService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedField:  "valid_until",
+		},
+		{
+			name:        "срок в прошлом",
+			requestBody: map[string]string{"valid_until": "2030-01-02T15:00:00Z"},
+			mockSetup: func(m *MockPassService) {
+				m.On("ExtendPass", mock.Anything, passID, adminID, sameTime).Return(nil, domain.ErrInvalidDateRange)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedField:  "valid_until",
+		},
+		{
+			name:        "отозванный пропуск",
+			requestBody: map[string]string{"valid_until": "2030-01-02T15:00:00Z"},
+			mockSetup: func(m *MockPassService) {
+				m.On("ExtendPass", mock.Anything, passID, adminID, sameTime).Return(nil, domain.ErrPassAlreadyRevoked)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:        "постоянный пропуск",
+			requestBody: map[string]string{"valid_until": "2030-01-02T15:00:00Z"},
+			mockSetup: func(m *MockPassService) {
+				m.On("ExtendPass", mock.Anything, passID, adminID, sameTime).Return(nil, domain.ErrPassNotExtendable)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:        "пропуск не найден",
+			requestBody: map[string]string{"valid_until": "2030-01-02T15:00:00Z"},
+			mockSetup: func(m *MockPassService) {
+				m.On("ExtendPass", mock.Anything, passID, adminID, sameTime).Return(nil, domain.ErrPassNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockPassService)
+			tt.mockSetup(mockService)
+
+			handler := NewPassHandler(mockService, logger.NewNoop(), PassHandlerConfig{})
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/passes/"+passID.String()+"/extend", bytes.NewReader(body))
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", passID.String())
+			ctx := context.WithValue(CreateAuthContext(t, adminID, "admin@test.com", domain.RoleAdmin), chi.RouteCtxKey, rctx)
+			req = req.WithContext(ctx)
+
+			w := httptest.NewRecorder()
+			handler.ExtendPass(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, true, response["success"])
+			}
+			if tt.expectedField != "" {
+				assert.Contains(t, w.Body.String(), `"field":"`+tt.expectedField+`"`)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPassHandler_AddVehicleToPass(t *testing.T) {
+	passID := uuid.New()
+	vehicleID := uuid.New()
+	guardID := uuid.New()
+	updated := &domain.Pass{ID: passID, Vehicles: []*domain.Vehicle{{ID: vehicleID, LicensePlate: "A123BC777"}}}
+
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		mockSetup      func(*MockPassService)
+		expectedStatus int
+	}{
+		{
+			name:        "привязка автомобиля",
+			requestBody: map[string]string{"vehicle_id": vehicleID.String()},
+			mockSetup: func(m *MockPassService) {
+				m.On("AddVehicleToPass", mock.Anything, passID, vehicleID, guardID).Return(nil)
+				m.On("GetPassWithVehicles", mock.Anything, passID).Return(updated, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "без vehicle_id",
+			requestBody:    map[string]string{},
+			mockSetup:      func(m *MockPassService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "автомобиль другого владельца",
+			requestBody: map[string]string{"vehicle_id": vehicleID.String()},
+			mockSetup: func(m *MockPassService) {
+				m.On("AddVehicleToPass", mock.Anything, passID, vehicleID, guardID).Return(domain.ErrPassVehicleOwnerMismatch)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "автомобиль уже привязан",
+			requestBody: map[string]string{"vehicle_id": vehicleID.String()},
+			mockSetup: func(m *MockPassService) {
+				m.On("AddVehicleToPass", mock.Anything, passID, vehicleID, guardID).Return(domain.ErrPassVehicleAlreadyExists)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:        "пропуск не найден",
+			requestBody: map[string]string{"vehicle_id": vehicleID.String()},
+			mockSetup: func(m *MockPassService) {
+				m.On("AddVehicleToPass", mock.Anything, passID, vehicleID, guardID).Return(domain.ErrPassNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockPassService)
+			tt.mockSetup(mockService)
+
+			handler := NewPassHandler(mockService, logger.NewNoop(), PassHandlerConfig{})
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/passes/"+passID.String()+"/vehicles", bytes.NewReader(body))
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", passID.String())
+			ctx := context.WithValue(CreateAuthContext(t, guardID, "guard@test.com", domain.RoleGuard), chi.RouteCtxKey, rctx)
+			req = req.WithContext(ctx)
+
+			w := httptest.NewRecorder()
+			handler.AddVehicleToPass(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response struct {
+					Data domain.Pass `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				require.Len(t, response.Data.Vehicles, 1)
+				assert.Equal(t, vehicleID, response.Data.Vehicles[0].ID)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPassHandler_RemoveVehicleFromPass(t *testing.T) {
+	passID := uuid.New()
+	vehicleID := uuid.New()
+	guardID := uuid.New()
+
+	tests := []struct {
+		name           string
+		vehicleID      string
+		mockSetup      func(*MockPassService)
+		expectedStatus int
+	}{
+		{
+			name:      "отвязка автомобиля",
+			vehicleID: vehicleID.String(),
+			mockSetup: func(m *MockPassService) {
+				m.On("RemoveVehicleFromPass", mock.Anything, passID, vehicleID).Return(nil)
+				m.On("GetPassWithVehicles", mock.Anything, passID).Return(&domain.Pass{ID: passID}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "автомобиль не привязан",
+			vehicleID: vehicleID.String(),
+			mockSetup: func(m *MockPassService) {
+				m.On("RemoveVehicleFromPass", mock.Anything, passID, vehicleID).Return(domain.ErrPassVehicleNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "невалидный ID автомобиля",
+			vehicleID:      "invalid-uuid",
+			mockSetup:      func(m *MockPassService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockPassService)
+			tt.mockSetup(mockService)
+
+			handler := NewPassHandler(mockService, logger.NewNoop(), PassHandlerConfig{})
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/passes/"+passID.String()+"/vehicles/"+tt.vehicleID, nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", passID.String())
+			rctx.URLParams.Add("vehicleId", tt.vehicleID)
+			ctx := context.WithValue(CreateAuthContext(t, guardID, "guard@test.com", domain.RoleGuard), chi.RouteCtxKey, rctx)
+			req = req.WithContext(ctx)
+
+			w := httptest.NewRecorder()
+			handler.RemoveVehicleFromPass(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPassHandler_IncludeVehicles(t *testing.T) {
+	userID := uuid.New()
+	passID := uuid.New()
+	vehicle := &domain.Vehicle{ID: uuid.New(), OwnerID: userID, LicensePlate: "A123BC777"}
+
+	t.Run("пропуска пользователя с автомобилями", func(t *testing.T) {
+		mockService := new(MockPassService)
+		passes := []*domain.Pass{{ID: passID, UserID: userID}}
+		mockService.On("GetPassesByUser", mock.Anything, userID).Return(passes, nil)
+		mockService.On("LoadVehicles", mock.Anything, passes).
+			Run(func(args mock.Arguments) {
+				args.Get(1).([]*domain.Pass)[0].Vehicles = []*domain.Vehicle{vehicle}
+			}).
+			Return(nil)
+
+		handler := NewPassHandler(mockService, logger.NewNoop(), PassHandlerConfig{})
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/passes/me?include=vehicles", nil)
+		req = req.WithContext(CreateAuthContext(t, userID, "user@test.com", domain.RoleUser))
+		w := httptest.NewRecorder()
+
+		handler.GetMyPasses(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Data []domain.Pass `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Data, 1)
+		require.Len(t, response.Data[0].Vehicles, 1)
+		assert.Equal(t, "A123BC777", response.Data[0].Vehicles[0].LicensePlate)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("без include автомобили не загружаются", func(t *testing.T) {
+		mockService := new(MockPassService)
+		mockService.On("GetPassesByUser", mock.Anything, userID).Return([]*domain.Pass{{ID: passID, UserID: userID}}, nil)
+
+		handler := NewPassHandler(mockService, logger.NewNoop(), PassHandlerConfig{})
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/passes/me", nil)
+		req = req.WithContext(CreateAuthContext(t, userID, "user@test.com", domain.RoleUser))
+		w := httptest.NewRecorder()
+
+		handler.GetMyPasses(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertNotCalled(t, "LoadVehicles", mock.Anything, mock.Anything)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("пропуск по ID с автомобилями", func(t *testing.T) {
+		mockService := new(MockPassService)
+		mockService.On("GetPassWithVehicles", mock.Anything, passID).
+			Return(&domain.Pass{ID: passID, UserID: userID, Vehicles: []*domain.Vehicle{vehicle}}, nil)
+
+		handler := NewPassHandler(mockService, logger.NewNoop(), PassHandlerConfig{})
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/passes/"+passID.String()+"?include=vehicles", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", passID.String())
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+
+		handler.GetPassByID(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Data domain.Pass `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Data.Vehicles, 1)
+		assert.Equal(t, vehicle.ID, response.Data.Vehicles[0].ID)
+		mockService.AssertNotCalled(t, "GetPassByID", mock.Anything, mock.Anything)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("неизвестное значение include", func(t *testing.T) {
+		mockService := new(MockPassService)
+
+		handler := NewPassHandler(mockService, logger.NewNoop(), PassHandlerConfig{})
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/passes/me?include=owner", nil)
+		req = req.WithContext(CreateAuthContext(t, userID, "user@test.com", domain.RoleUser))
+		w := httptest.NewRecorder()
+
+		handler.GetMyPasses(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"include"`)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestPassHandler_ListPasses(t *testing.T) {
+	userID := uuid.New()
+	active := true
+	from := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2030, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedFilter domain.PassFilter
+		expectedLimit  int
+		expectedOffset int
+		expectedStatus int
+	}{
+		{
+			name:           "без фильтров",
+			expectedLimit:  defaultPageSize,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "только активные",
+			query:          "?is_active=true",
+			expectedFilter: domain.PassFilter{IsActive: &active},
+			expectedLimit:  defaultPageSize,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "пропуска пользователя",
+			query:          "?user_id=" + userID.String(),
+			expectedFilter: domain.PassFilter{UserID: &userID},
+			expectedLimit:  defaultPageSize,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "тип, период действия и пагинация",
+			query:          "?pass_type=temporary&from=2030-01-01&to=2030-01-31&limit=10&offset=20",
+			expectedFilter: domain.PassFilter{PassType: domain.PassTypeTemporary, ValidFrom: &from, ValidTo: &to},
+			expectedLimit:  10,
+			expectedOffset: 20,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "невалидный user_id",
+			query:          "?user_id=not-a-uuid",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "невалидный pass_type",
+			query:          "?pass_type=guest",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "невалидный is_active",
+			query:          "?is_active=maybe",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "конец периода раньше начала",
+			query:          "?from=2030-02-01&to=2030-01-01",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockPassService)
+			if tt.expectedStatus == http.StatusOK {
+				mockService.On("ListPasses", mock.Anything, tt.expectedFilter, tt.expectedLimit, tt.expectedOffset).
+					Return([]*domain.Pass{CreateTestPass(uuid.New(), userID, uuid.New(), domain.PassTypePermanent)}, nil)
+				mockService.On("CountPasses", mock.Anything, tt.expectedFilter).Return(42, nil)
+			}
+
+			handler := NewPassHandler(mockService, logger.NewNoop(), PassHandlerConfig{})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/passes"+tt.query, nil)
+			w := httptest.NewRecorder()
+			handler.ListPasses(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response map[string]interface{}
+				_ = json.Unmarshal(w.Body.Bytes(), &response)
+				assert.Len(t, response["data"], 1)
+				pagination, ok := response["pagination"].(map[string]interface{})
+				if assert.True(t, ok) {
+					assert.Equal(t, float64(tt.expectedLimit), pagination["limit"])
+					assert.Equal(t, float64(tt.expectedOffset), pagination["offset"])
+					assert.Equal(t, float64(42), pagination["total"], "total считается по тому же фильтру")
+				}
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -6,21 +6,45 @@ import (
 	"github.com/frontandrew/gate/internal/delivery/http/middleware"
 	"github.com/frontandrew/gate/internal/domain"
 	"github.com/frontandrew/gate/internal/pkg/config"
+	"github.com/frontandrew/gate/internal/pkg/idempotency"
 	"github.com/frontandrew/gate/internal/pkg/jwt"
 	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/pkg/metrics"
+	"github.com/frontandrew/gate/internal/pkg/ratelimit"
 	"github.com/go-chi/chi/v5"
 	chiMiddleware "github.com/go-chi/chi/v5/middleware"
 )
 
+// RateLimiters - limiter'ы публичных endpoint'ов; nil - ограничение для группы отключено
+type RateLimiters struct {
+	AccessCheck *ratelimit.Limiter // По воротам, пользователю или IP
+	Login       *ratelimit.Limiter // По IP и по email
+	Register    *ratelimit.Limiter // По IP
+
+	ForgotPassword *ratelimit.Limiter // По IP и по email
+	ResetPassword  *ratelimit.Limiter // По IP
+}
+
 // Router содержит все зависимости для HTTP роутера
 type Router struct {
-	accessHandler  *AccessHandler
-	authHandler    *AuthHandler
-	vehicleHandler *VehicleHandler
-	passHandler    *PassHandler
-	tokenService   *jwt.TokenService
-	config         *config.Config
-	logger         logger.Logger
+	accessHandler    *AccessHandler
+	authHandler      *AuthHandler
+	vehicleHandler   *VehicleHandler
+	passHandler      *PassHandler
+	whitelistHandler *WhitelistHandler
+	blacklistHandler *BlacklistHandler
+	auditHandler     *AuditHandler
+	listsHandler     *ListsHandler
+	userHandler      *UserHandler
+	eventsHandler    *EventsHandler // nil - лента событий доступа отключена (нет Redis)
+	healthHandler    *HealthHandler
+	tokenService     *jwt.TokenService
+	tokenDenylist    middleware.TokenDenylist // nil - отзыв access токенов отключен
+	rateLimiters     RateLimiters
+	idempotency      *idempotency.Store // nil - заголовок Idempotency-Key игнорируется
+	metrics          *metrics.Metrics   // nil - метрики и endpoint /metrics отключены
+	config           *config.Config
+	logger           logger.Logger
 }
 
 // NewRouter создает новый HTTP router
@@ -29,18 +53,40 @@ func NewRouter(
 	authHandler *AuthHandler,
 	vehicleHandler *VehicleHandler,
 	passHandler *PassHandler,
+	whitelistHandler *WhitelistHandler,
+	blacklistHandler *BlacklistHandler,
+	auditHandler *AuditHandler,
+	listsHandler *ListsHandler,
+	userHandler *UserHandler,
+	eventsHandler *EventsHandler,
+	healthHandler *HealthHandler,
 	tokenService *jwt.TokenService,
+	tokenDenylist middleware.TokenDenylist,
+	rateLimiters RateLimiters,
+	idempotencyStore *idempotency.Store,
+	metrics *metrics.Metrics,
 	config *config.Config,
 	logger logger.Logger,
 ) *Router {
 	return &Router{
-		accessHandler:  accessHandler,
-		authHandler:    authHandler,
-		vehicleHandler: vehicleHandler,
-		passHandler:    passHandler,
-		tokenService:   tokenService,
-		config:         config,
-		logger:         logger,
+		accessHandler:    accessHandler,
+		authHandler:      authHandler,
+		vehicleHandler:   vehicleHandler,
+		passHandler:      passHandler,
+		whitelistHandler: whitelistHandler,
+		blacklistHandler: blacklistHandler,
+		auditHandler:     auditHandler,
+		listsHandler:     listsHandler,
+		userHandler:      userHandler,
+		eventsHandler:    eventsHandler,
+		healthHandler:    healthHandler,
+		tokenService:     tokenService,
+		tokenDenylist:    tokenDenylist,
+		rateLimiters:     rateLimiters,
+		idempotency:      idempotencyStore,
+		metrics:          metrics,
+		config:           config,
+		logger:           logger,
 	}
 }
 
@@ -50,76 +96,210 @@ func (rt *Router) Setup() http.Handler {
 
 	// Глобальные middleware
 	r.Use(chiMiddleware.RequestID)
+	r.Use(middleware.RequestLoggerMiddleware(rt.logger))
 	r.Use(middleware.RecoveryMiddleware(rt.logger))
 	r.Use(middleware.LoggingMiddleware(rt.logger))
+	if rt.metrics != nil {
+		r.Use(middleware.MetricsMiddleware(rt.metrics))
+	}
 	r.Use(middleware.CORSMiddleware(middleware.CORSConfig{
 		AllowedOrigins: rt.config.CORS.AllowedOrigins,
 		AllowedMethods: rt.config.CORS.AllowedMethods,
 		AllowedHeaders: rt.config.CORS.AllowedHeaders,
 	}))
 
-	// Health check endpoint (публичный)
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		respondJSON(w, http.StatusOK, map[string]string{
-			"status": "healthy",
-		})
-	})
+	// Health check endpoints (публичные): /health оставлен для совместимости и равен /health/live
+	r.Get("/health", rt.healthHandler.Live)
+	r.Get("/health/live", rt.healthHandler.Live)
+	r.Get("/health/ready", rt.healthHandler.Ready)
+
+	// Метрики Prometheus (публичный, как и health check)
+	if rt.metrics != nil {
+		r.Handle("/metrics", rt.metrics.Handler())
+	}
+
+	configHandler := NewConfigHandler(rt.config)
+
+	maxBody := middleware.MaxBodySize(int64(rt.config.Server.MaxBodyBytes))
 
 	// API v1 routes
 	r.Route("/api/v1", func(r chi.Router) {
+		// Публичная конфигурация для frontend (без секретов)
+		r.Get("/config", configHandler.GetConfig)
+
 		// Public routes (без аутентификации)
 		r.Route("/auth", func(r chi.Router) {
-			r.Post("/register", rt.authHandler.Register)
-			r.Post("/login", rt.authHandler.Login)
+			r.Use(middleware.NoStore())
+			r.Use(maxBody)
+			r.With(rt.rateLimit(rt.rateLimiters.Register, middleware.ClientIPRateLimitKey)).
+				Post("/register", rt.authHandler.Register)
+			r.With(
+				rt.rateLimit(rt.rateLimiters.Login, middleware.ClientIPRateLimitKey),
+				rt.rateLimit(rt.rateLimiters.Login, middleware.EmailRateLimitKey),
+			).Post("/login", rt.authHandler.Login)
 			r.Post("/refresh", rt.authHandler.RefreshToken)
 			r.Post("/logout", rt.authHandler.Logout)
+			// Лимит по email не дает завалить письмами один ящик с разных IP
+			r.With(
+				rt.rateLimit(rt.rateLimiters.ForgotPassword, middleware.ClientIPRateLimitKey),
+				rt.rateLimit(rt.rateLimiters.ForgotPassword, middleware.EmailRateLimitKey),
+			).Post("/forgot-password", rt.authHandler.ForgotPassword)
+			r.With(rt.rateLimit(rt.rateLimiters.ResetPassword, middleware.ClientIPRateLimitKey)).
+				Post("/reset-password", rt.authHandler.ResetPassword)
 		})
 
 		// Access check endpoint (публичный - используется камерами/шлагбаумами)
-		r.Post("/access/check", rt.accessHandler.CheckAccess)
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.MaxBodySize(int64(rt.config.Server.MaxImageBodyBytes)))
+			r.Use(rt.rateLimit(rt.rateLimiters.AccessCheck, middleware.AccessCheckRateLimitKey))
+			r.Post("/access/check", rt.accessHandler.CheckAccess)
+		})
 
 		// Protected routes (требуют аутентификации)
 		r.Group(func(r chi.Router) {
-			r.Use(middleware.AuthMiddleware(rt.tokenService))
+			r.Use(middleware.AuthMiddleware(rt.tokenService, rt.tokenDenylist))
+			r.Use(maxBody)
+
+			privateCache := middleware.PrivateCache(rt.config.Server.PrivateCacheMaxAge)
+			idempotent := rt.idempotent()
 
 			// Current user endpoints
 			r.Route("/auth/me", func(r chi.Router) {
+				r.Use(middleware.NoStore())
 				r.Get("/", rt.authHandler.GetMe)
 			})
 
+			r.With(middleware.NoStore()).Post("/auth/change-password", rt.authHandler.ChangePassword)
+
+			// Сессии (устройства) текущего пользователя
+			r.Route("/auth/sessions", func(r chi.Router) {
+				r.Use(middleware.NoStore())
+				r.Get("/", rt.authHandler.ListSessions)
+				r.Delete("/", rt.authHandler.LogoutAll)
+				r.Delete("/{id}", rt.authHandler.RevokeSession)
+			})
+
 			// Vehicle endpoints
 			r.Route("/vehicles", func(r chi.Router) {
-				r.Get("/me", rt.vehicleHandler.GetMyVehicles)
-				r.Post("/", rt.vehicleHandler.CreateVehicle)
-				r.Get("/{id}", rt.vehicleHandler.GetVehicleByID)
+				r.With(privateCache).Get("/me", rt.vehicleHandler.GetMyVehicles)
+				r.With(idempotent).Post("/", rt.vehicleHandler.CreateVehicle)
+				r.With(middleware.Revalidate()).Get("/{id}", rt.vehicleHandler.GetVehicleByID)
+
+				// Поиск по части номера - для охраны и админов
+				r.With(middleware.RequireRole(domain.RoleAdmin, domain.RoleGuard)).Get("/search", rt.vehicleHandler.SearchVehicles)
+
+				// Admin only endpoints
+				r.Group(func(r chi.Router) {
+					r.Use(middleware.RequireRole(domain.RoleAdmin))
+					r.Get("/", rt.vehicleHandler.ListVehicles)
+					r.Post("/merge", rt.vehicleHandler.MergeVehicles)
+					r.Delete("/{id}", rt.vehicleHandler.DeleteVehicle)
+				})
 			})
 
 			// Pass endpoints
 			r.Route("/passes", func(r chi.Router) {
-				r.Get("/me", rt.passHandler.GetMyPasses)
-				r.Get("/{id}", rt.passHandler.GetPassByID)
+				r.With(privateCache).Get("/me", rt.passHandler.GetMyPasses)
+				r.With(idempotent).Post("/guest", rt.passHandler.CreateGuestPass)
+				r.With(middleware.Revalidate()).Get("/{id}", rt.passHandler.GetPassByID)
 
 				// Admin/Guard only endpoints
 				r.Group(func(r chi.Router) {
 					r.Use(middleware.RequireRole(domain.RoleAdmin, domain.RoleGuard))
-					r.Post("/", rt.passHandler.CreatePass)
+					r.Get("/", rt.passHandler.ListPasses)
+					r.With(idempotent).Post("/", rt.passHandler.CreatePass)
 					r.Delete("/{id}/revoke", rt.passHandler.RevokePass)
+					r.Post("/{id}/extend", rt.passHandler.ExtendPass)
+					r.Post("/{id}/vehicles", rt.passHandler.AddVehicleToPass)
+					r.Delete("/{id}/vehicles/{vehicleId}", rt.passHandler.RemoveVehicleFromPass)
 				})
 			})
 
 			// Access log endpoints
 			r.Route("/access", func(r chi.Router) {
-				r.Get("/me/logs", rt.accessHandler.GetMyAccessLogs)
+				r.With(privateCache).Get("/me/logs", rt.accessHandler.GetMyAccessLogs)
 				r.Get("/logs/vehicle/{id}", rt.accessHandler.GetVehicleAccessLogs)
 
 				// Admin/Guard only endpoints
 				r.Group(func(r chi.Router) {
 					r.Use(middleware.RequireRole(domain.RoleAdmin, domain.RoleGuard))
 					r.Get("/logs", rt.accessHandler.GetAccessLogs)
+					r.Post("/manual", rt.accessHandler.ManualAccess)
+					r.Get("/logs/export", rt.accessHandler.ExportAccessLogs)
+					r.Get("/logs/{id}", rt.accessHandler.GetAccessLog)
+					r.Get("/stats", rt.accessHandler.GetStats)
+					r.Get("/stats/denied-reasons", rt.accessHandler.GetDeniedReasonStats)
+					r.Get("/occupancy", rt.accessHandler.GetOccupancy)
+					if rt.eventsHandler != nil {
+						r.Get("/events", rt.eventsHandler.StreamAccessEvents)
+					}
 				})
 			})
+
+			// Whitelist endpoints (только для админов)
+			r.Route("/whitelist", func(r chi.Router) {
+				r.Use(middleware.RequireRole(domain.RoleAdmin))
+				r.Get("/", rt.whitelistHandler.ListEntries)
+				r.Post("/", rt.whitelistHandler.CreateEntry)
+				r.With(idempotent).Post("/bulk", rt.whitelistHandler.BulkCreate)
+				r.Get("/{id}", rt.whitelistHandler.GetEntry)
+				r.Put("/{id}", rt.whitelistHandler.UpdateEntry)
+				r.Delete("/{id}", rt.whitelistHandler.DeleteEntry)
+			})
+
+			// Blacklist endpoints (админы и охрана)
+			r.Route("/blacklist", func(r chi.Router) {
+				r.Use(middleware.RequireRole(domain.RoleAdmin, domain.RoleGuard))
+				r.Get("/", rt.blacklistHandler.ListEntries)
+				r.Post("/", rt.blacklistHandler.CreateEntry)
+				r.With(idempotent).Post("/bulk", rt.blacklistHandler.BulkCreate)
+				r.Get("/{id}", rt.blacklistHandler.GetEntry)
+				r.Put("/{id}", rt.blacklistHandler.UpdateEntry)
+				r.Delete("/{id}", rt.blacklistHandler.DeleteEntry)
+			})
+
+			// User administration endpoints (только для админов)
+			r.Route("/users", func(r chi.Router) {
+				r.Use(middleware.RequireRole(domain.RoleAdmin))
+				r.Get("/", rt.userHandler.ListUsers)
+				r.Get("/{id}", rt.userHandler.GetUser)
+				r.Patch("/{id}", rt.userHandler.UpdateUser)
+				r.Post("/{id}/disable-access", rt.userHandler.DisableAccess)
+			})
+
+			// Audit log endpoints (только для админов)
+			r.Route("/audit", func(r chi.Router) {
+				r.Use(middleware.RequireRole(domain.RoleAdmin))
+				r.Get("/", rt.auditHandler.SearchAuditLogs)
+			})
+
+			// Admin endpoints
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(middleware.RequireRole(domain.RoleAdmin))
+				r.Post("/access/simulate", rt.accessHandler.SimulateAccess)
+				r.Get("/lists/export", rt.listsHandler.Export)
+				r.With(idempotent).Post("/lists/import", rt.listsHandler.Import)
+			})
 		})
 	})
 
 	return r
 }
+
+// rateLimit возвращает middleware ограничения частоты запросов
+// Для отключенного limiter'а (nil) запросы пропускаются без проверки
+func (rt *Router) rateLimit(limiter *ratelimit.Limiter, keyFunc middleware.RateLimitKeyFunc) func(http.Handler) http.Handler {
+	if limiter == nil {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return middleware.RateLimitMiddleware(limiter, keyFunc, rt.logger)
+}
+
+// idempotent возвращает middleware повторов по Idempotency-Key для создающих запросов
+// Без хранилища (нет Redis или IDEMPOTENCY_TTL=0) заголовок игнорируется
+func (rt *Router) idempotent() func(http.Handler) http.Handler {
+	if rt.idempotency == nil {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return middleware.IdempotencyMiddleware(rt.idempotency, rt.logger)
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/config"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/pkg/ratelimit"
+	"github.com/frontandrew/gate/internal/pkg/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRouter собирает роутер с auth handler'ом и указанными limiter'ами; остальные handler'ы не нужны
+func newTestRouter(t *testing.T, authService *MockAuthService, limiters RateLimiters) http.Handler {
+	t.Helper()
+
+	cfg := &config.Config{Server: config.ServerConfig{MaxBodyBytes: 1 << 20, MaxImageBodyBytes: 10 << 20}}
+	rt := NewRouter(nil, NewAuthHandler(authService, logger.NewNoop()), nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, limiters, nil, nil, cfg, logger.NewNoop())
+	return rt.Setup()
+}
+
+func newTestRedisClient(t *testing.T) *redis.Client {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	host, port, err := net.SplitHostPort(mr.Addr())
+	require.NoError(t, err)
+
+	client, err := redis.NewClient(redis.Config{Host: host, Port: port})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func postJSON(handler http.Handler, path, ip, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = ip + ":12345"
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestRouter_ForgotPasswordRateLimit(t *testing.T) {
+	client := newTestRedisClient(t)
+	authService := new(MockAuthService)
+	authService.On("ForgotPassword", mock.Anything, mock.Anything).Return(nil)
+
+	handler := newTestRouter(t, authService, RateLimiters{
+		ForgotPassword: ratelimit.NewLimiter(client, "ratelimit:forgot-password:", ratelimit.Config{Requests: 2, Window: time.Hour}),
+	})
+
+	forgot := func(ip, email string) *httptest.ResponseRecorder {
+		return postJSON(handler, "/api/v1/auth/forgot-password", ip, `{"email":"`+email+`"}`)
+	}
+
+	t.Run("лимит по IP", func(t *testing.T) {
+		for _, email := range []string{"a@example.com", "b@example.com"} {
+			w := forgot("10.0.0.1", email)
+			require.Equal(t, http.StatusOK, w.Code)
+			// Ответ до лимита не зависит от существования пользователя
+			assert.Contains(t, w.Body.String(), "If the email is registered")
+		}
+
+		w := forgot("10.0.0.1", "c@example.com")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.NotEmpty(t, w.Header().Get("Retry-After"))
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		AssertErrorCode(t, response, "RATE_LIMITED")
+	})
+
+	t.Run("лимит по email с разных IP", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, forgot("10.0.1.1", "victim@example.com").Code)
+		require.Equal(t, http.StatusOK, forgot("10.0.1.2", "Victim@Example.com").Code)
+
+		assert.Equal(t, http.StatusTooManyRequests, forgot("10.0.1.3", "victim@example.com").Code)
+	})
+
+	// Письма отправлялись только до срабатывания лимита
+	authService.AssertNumberOfCalls(t, "ForgotPassword", 4)
+}
+
+func TestRouter_ResetPasswordRateLimit(t *testing.T) {
+	client := newTestRedisClient(t)
+	authService := new(MockAuthService)
+	authService.On("ResetPassword", mock.Anything, mock.Anything).Return(domain.ErrInvalidResetToken)
+
+	handler := newTestRouter(t, authService, RateLimiters{
+		ResetPassword: ratelimit.NewLimiter(client, "ratelimit:reset-password:", ratelimit.Config{Requests: 3, Window: time.Hour}),
+	})
+
+	reset := func(ip string) *httptest.ResponseRecorder {
+		return postJSON(handler, "/api/v1/auth/reset-password", ip, `{"token":"guess","new_password":"newpassword123"}`)
+	}
+
+	// Перебор токенов с одного IP останавливается лимитом
+	for i := 0; i < 3; i++ {
+		require.Equal(t, http.StatusBadRequest, reset("10.0.0.1").Code, "request %d", i+1)
+	}
+	w := reset("10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusBadRequest, reset("10.0.0.2").Code, "другой IP считается отдельно")
+	authService.AssertNumberOfCalls(t, "ResetPassword", 4)
+}
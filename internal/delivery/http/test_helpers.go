@@ -10,9 +10,14 @@ import (
 	"github.com/frontandrew/gate/internal/pkg/jwt"
 	"github.com/frontandrew/gate/internal/usecase/access"
 	"github.com/frontandrew/gate/internal/usecase/auth"
+	"github.com/frontandrew/gate/internal/usecase/blacklist"
+	"github.com/frontandrew/gate/internal/usecase/lists"
 	"github.com/frontandrew/gate/internal/usecase/pass"
+	"github.com/frontandrew/gate/internal/usecase/user"
 	"github.com/frontandrew/gate/internal/usecase/vehicle"
+	"github.com/frontandrew/gate/internal/usecase/whitelist"
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -64,6 +69,39 @@ func (m *MockAuthService) GetUserByID(ctx context.Context, userID uuid.UUID) (*d
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
+func (m *MockAuthService) ListSessions(ctx context.Context, userID uuid.UUID) ([]*auth.Session, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*auth.Session), args.Error(1)
+}
+
+func (m *MockAuthService) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	args := m.Called(ctx, userID, sessionID)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) RevokeAllSessions(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ChangePassword(ctx context.Context, userID uuid.UUID, req *auth.ChangePasswordRequest) error {
+	args := m.Called(ctx, userID, req)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ForgotPassword(ctx context.Context, req *auth.ForgotPasswordRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ResetPassword(ctx context.Context, req *auth.ResetPasswordRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
 // MockVehicleService мок для vehicle.Service
 type MockVehicleService struct {
 	mock.Mock
@@ -77,22 +115,59 @@ func (m *MockVehicleService) CreateVehicle(ctx context.Context, req *vehicle.Cre
 	return args.Get(0).(*domain.Vehicle), args.Error(1)
 }
 
-func (m *MockVehicleService) GetVehiclesByOwner(ctx context.Context, ownerID uuid.UUID) ([]*domain.Vehicle, error) {
-	args := m.Called(ctx, ownerID)
+func (m *MockVehicleService) GetVehiclesByOwner(ctx context.Context, ownerID uuid.UUID, includeInactive bool) ([]*domain.Vehicle, error) {
+	args := m.Called(ctx, ownerID, includeInactive)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*domain.Vehicle), args.Error(1)
 }
 
-func (m *MockVehicleService) GetVehicleByID(ctx context.Context, vehicleID uuid.UUID) (*domain.Vehicle, error) {
-	args := m.Called(ctx, vehicleID)
+func (m *MockVehicleService) GetVehicleByID(ctx context.Context, vehicleID uuid.UUID, includeInactive bool) (*domain.Vehicle, error) {
+	args := m.Called(ctx, vehicleID, includeInactive)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.Vehicle), args.Error(1)
 }
 
+func (m *MockVehicleService) DeleteVehicle(ctx context.Context, id uuid.UUID, hard bool) (*domain.VehicleDeleteResult, error) {
+	args := m.Called(ctx, id, hard)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.VehicleDeleteResult), args.Error(1)
+}
+
+func (m *MockVehicleService) ListVehicles(ctx context.Context, filter domain.VehicleFilter, limit, offset int) ([]*domain.Vehicle, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Vehicle), args.Error(1)
+}
+
+func (m *MockVehicleService) CountVehicles(ctx context.Context, filter domain.VehicleFilter) (int, error) {
+	args := m.Called(ctx, filter)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockVehicleService) SearchVehiclesByPlate(ctx context.Context, plate string, limit int, includeInactive bool) ([]*domain.Vehicle, error) {
+	args := m.Called(ctx, plate, limit, includeInactive)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Vehicle), args.Error(1)
+}
+
+func (m *MockVehicleService) MergeVehicles(ctx context.Context, req *vehicle.MergeVehiclesRequest) (*domain.VehicleMergeResult, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.VehicleMergeResult), args.Error(1)
+}
+
 // MockPassService мок для pass.Service
 type MockPassService struct {
 	mock.Mock
@@ -114,6 +189,19 @@ func (m *MockPassService) GetPassesByUser(ctx context.Context, userID uuid.UUID)
 	return args.Get(0).([]*domain.Pass), args.Error(1)
 }
 
+func (m *MockPassService) ListPasses(ctx context.Context, filter domain.PassFilter, limit, offset int) ([]*domain.Pass, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Pass), args.Error(1)
+}
+
+func (m *MockPassService) CountPasses(ctx context.Context, filter domain.PassFilter) (int, error) {
+	args := m.Called(ctx, filter)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockPassService) GetPassByID(ctx context.Context, passID uuid.UUID) (*domain.Pass, error) {
 	args := m.Called(ctx, passID)
 	if args.Get(0) == nil {
@@ -127,6 +215,45 @@ func (m *MockPassService) RevokePass(ctx context.Context, passID, revokedBy uuid
 	return args.Error(0)
 }
 
+func (m *MockPassService) ExtendPass(ctx context.Context, passID, extendedBy uuid.UUID, validUntil time.Time) (*domain.Pass, error) {
+	args := m.Called(ctx, passID, extendedBy, validUntil)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Pass), args.Error(1)
+}
+
+func (m *MockPassService) GetPassWithVehicles(ctx context.Context, passID uuid.UUID) (*domain.Pass, error) {
+	args := m.Called(ctx, passID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Pass), args.Error(1)
+}
+
+func (m *MockPassService) LoadVehicles(ctx context.Context, passes []*domain.Pass) error {
+	args := m.Called(ctx, passes)
+	return args.Error(0)
+}
+
+func (m *MockPassService) AddVehicleToPass(ctx context.Context, passID, vehicleID, addedBy uuid.UUID) error {
+	args := m.Called(ctx, passID, vehicleID, addedBy)
+	return args.Error(0)
+}
+
+func (m *MockPassService) RemoveVehicleFromPass(ctx context.Context, passID, vehicleID uuid.UUID) error {
+	args := m.Called(ctx, passID, vehicleID)
+	return args.Error(0)
+}
+
+func (m *MockPassService) CreateGuestPass(ctx context.Context, req *pass.CreateGuestPassRequest) (*domain.Pass, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Pass), args.Error(1)
+}
+
 // MockAccessService мок для access.Service
 type MockAccessService struct {
 	mock.Mock
@@ -148,6 +275,19 @@ func (m *MockAccessService) GetAccessLogs(ctx context.Context, userID *uuid.UUID
 	return args.Get(0).([]*domain.AccessLog), args.Error(1)
 }
 
+func (m *MockAccessService) GetAccessLog(ctx context.Context, id uuid.UUID) (*domain.AccessLog, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AccessLog), args.Error(1)
+}
+
+func (m *MockAccessService) LoadRelations(ctx context.Context, logs []*domain.AccessLog, include access.LogInclude) error {
+	args := m.Called(ctx, logs, include)
+	return args.Error(0)
+}
+
 func (m *MockAccessService) GetAccessLogsByVehicle(ctx context.Context, vehicleID uuid.UUID, limit, offset int) ([]*domain.AccessLog, error) {
 	args := m.Called(ctx, vehicleID, limit, offset)
 	if args.Get(0) == nil {
@@ -156,6 +296,232 @@ func (m *MockAccessService) GetAccessLogsByVehicle(ctx context.Context, vehicleI
 	return args.Get(0).([]*domain.AccessLog), args.Error(1)
 }
 
+func (m *MockAccessService) SearchAccessLogs(ctx context.Context, filter domain.AccessLogFilter, limit, offset int) ([]*domain.AccessLog, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.AccessLog), args.Error(1)
+}
+
+func (m *MockAccessService) CountAccessLogs(ctx context.Context, filter domain.AccessLogFilter) (int, error) {
+	args := m.Called(ctx, filter)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAccessService) ExportAccessLogs(ctx context.Context, filter domain.AccessLogFilter, fn func(*domain.AccessLog) error) error {
+	args := m.Called(ctx, filter, fn)
+	return args.Error(0)
+}
+
+func (m *MockAccessService) GetStats(ctx context.Context, from, to time.Time) (*domain.AccessStats, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AccessStats), args.Error(1)
+}
+
+func (m *MockAccessService) DeniedReasonCounts() map[string]int64 {
+	args := m.Called()
+	return args.Get(0).(map[string]int64)
+}
+
+func (m *MockAccessService) ManualAccess(ctx context.Context, req *access.ManualAccessRequest, operatorID uuid.UUID) (*access.CheckAccessResponse, error) {
+	args := m.Called(ctx, req, operatorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*access.CheckAccessResponse), args.Error(1)
+}
+
+func (m *MockAccessService) Occupancy(ctx context.Context) (*access.OccupancyResponse, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*access.OccupancyResponse), args.Error(1)
+}
+
+func (m *MockAccessService) SimulateAccess(ctx context.Context, req *access.SimulateAccessRequest) (*access.SimulateAccessResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*access.SimulateAccessResponse), args.Error(1)
+}
+
+// MockAuditService мок для audit.Service
+type MockAuditService struct {
+	mock.Mock
+}
+
+func (m *MockAuditService) SearchAuditLogs(ctx context.Context, filter domain.AuditLogFilter, limit, offset int) ([]*domain.AuditLog, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.AuditLog), args.Error(1)
+}
+
+// MockListsService мок для lists.Service
+type MockListsService struct {
+	mock.Mock
+}
+
+func (m *MockListsService) Export(ctx context.Context) (*lists.Snapshot, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*lists.Snapshot), args.Error(1)
+}
+
+func (m *MockListsService) Import(ctx context.Context, snapshot *lists.Snapshot, addedBy uuid.UUID) (*lists.ImportResult, error) {
+	args := m.Called(ctx, snapshot, addedBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*lists.ImportResult), args.Error(1)
+}
+
+// MockBlacklistService мок для blacklist.Service
+type MockBlacklistService struct {
+	mock.Mock
+}
+
+func (m *MockBlacklistService) CreateEntry(ctx context.Context, req *blacklist.CreateEntryRequest) (*domain.BlacklistEntry, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BlacklistEntry), args.Error(1)
+}
+
+func (m *MockBlacklistService) CreateEntries(ctx context.Context, reqs []blacklist.CreateEntryRequest, addedBy uuid.UUID) (*blacklist.BulkResult, error) {
+	args := m.Called(ctx, reqs, addedBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*blacklist.BulkResult), args.Error(1)
+}
+
+func (m *MockBlacklistService) ListEntries(ctx context.Context, limit, offset int) ([]*domain.BlacklistEntry, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.BlacklistEntry), args.Error(1)
+}
+
+func (m *MockBlacklistService) GetEntry(ctx context.Context, id uuid.UUID) (*domain.BlacklistEntry, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BlacklistEntry), args.Error(1)
+}
+
+func (m *MockBlacklistService) UpdateEntry(ctx context.Context, id uuid.UUID, req *blacklist.UpdateEntryRequest) (*domain.BlacklistEntry, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BlacklistEntry), args.Error(1)
+}
+
+func (m *MockBlacklistService) DeleteEntry(ctx context.Context, id, deletedBy uuid.UUID) error {
+	args := m.Called(ctx, id, deletedBy)
+	return args.Error(0)
+}
+
+// MockWhitelistService мок для whitelist.Service
+type MockWhitelistService struct {
+	mock.Mock
+}
+
+func (m *MockWhitelistService) CreateEntry(ctx context.Context, req *whitelist.CreateEntryRequest) (*domain.WhitelistEntry, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WhitelistEntry), args.Error(1)
+}
+
+func (m *MockWhitelistService) CreateEntries(ctx context.Context, reqs []whitelist.CreateEntryRequest, addedBy uuid.UUID) (*whitelist.BulkResult, error) {
+	args := m.Called(ctx, reqs, addedBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*whitelist.BulkResult), args.Error(1)
+}
+
+func (m *MockWhitelistService) ListEntries(ctx context.Context, limit, offset int) ([]*domain.WhitelistEntry, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.WhitelistEntry), args.Error(1)
+}
+
+func (m *MockWhitelistService) GetEntry(ctx context.Context, id uuid.UUID) (*domain.WhitelistEntry, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WhitelistEntry), args.Error(1)
+}
+
+func (m *MockWhitelistService) UpdateEntry(ctx context.Context, id uuid.UUID, req *whitelist.UpdateEntryRequest) (*domain.WhitelistEntry, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WhitelistEntry), args.Error(1)
+}
+
+func (m *MockWhitelistService) DeleteEntry(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockUserService мок для user.Service
+type MockUserService struct {
+	mock.Mock
+}
+
+func (m *MockUserService) DisableAccess(ctx context.Context, userID, disabledBy uuid.UUID) (*domain.UserDisableResult, error) {
+	args := m.Called(ctx, userID, disabledBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.UserDisableResult), args.Error(1)
+}
+
+func (m *MockUserService) ListUsers(ctx context.Context, limit, offset int, includeInactive bool) ([]*domain.User, error) {
+	args := m.Called(ctx, limit, offset, includeInactive)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.User), args.Error(1)
+}
+
+func (m *MockUserService) GetUser(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockUserService) UpdateUser(ctx context.Context, id uuid.UUID, req *user.UpdateUserRequest) (*domain.User, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
 // ============================================================================
 // Test Data Factories
 // ============================================================================
@@ -251,3 +617,43 @@ func AssertError(t *testing.T, response map[string]interface{}) {
 		t.Errorf("Expected success=false, got %v", response)
 	}
 }
+
+// ErrorDetail возвращает объект error ошибочного ответа API
+func ErrorDetail(t *testing.T, response map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	detail, ok := response["error"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected error object, got %v", response)
+	}
+	return detail
+}
+
+// AssertErrorCode проверяет ошибочный ответ API и код ошибки
+func AssertErrorCode(t *testing.T, response map[string]interface{}, code string) {
+	t.Helper()
+	AssertError(t, response)
+	detail := ErrorDetail(t, response)
+	assert.Equal(t, code, detail["code"])
+	assert.NotEmpty(t, detail["message"])
+}
+
+// AssertFieldErrors проверяет ответ валидации: ошибка и перечень невалидных полей
+func AssertFieldErrors(t *testing.T, response map[string]interface{}, fields ...string) {
+	t.Helper()
+	AssertErrorCode(t, response, "VALIDATION_FAILED")
+	detail := ErrorDetail(t, response)
+	assert.Equal(t, "Validation failed", detail["message"])
+
+	items, ok := detail["fields"].([]interface{})
+	if !ok {
+		t.Fatalf("Expected fields list, got %v", response)
+	}
+
+	var got []string
+	for _, item := range items {
+		fieldErr := item.(map[string]interface{})
+		assert.NotEmpty(t, fieldErr["message"])
+		got = append(got, fieldErr["field"].(string))
+	}
+	assert.ElementsMatch(t, fields, got)
+}
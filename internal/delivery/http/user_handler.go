@@ -0,0 +1,162 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/frontandrew/gate/internal/delivery/http/middleware"
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/usecase/user"
+	"github.com/google/uuid"
+)
+
+// UserService определяет интерфейс для сервиса администрирования пользователей
+type UserService interface {
+	DisableAccess(ctx context.Context, userID, disabledBy uuid.UUID) (*domain.UserDisableResult, error)
+	ListUsers(ctx context.Context, limit, offset int, includeInactive bool) ([]*domain.User, error)
+	GetUser(ctx context.Context, id uuid.UUID) (*domain.User, error)
+	UpdateUser(ctx context.Context, id uuid.UUID, req *user.UpdateUserRequest) (*domain.User, error)
+}
+
+// UserHandler обрабатывает запросы администрирования пользователей
+type UserHandler struct {
+	userService UserService
+	logger      logger.Logger
+}
+
+// NewUserHandler создает новый handler
+func NewUserHandler(userService UserService, logger logger.Logger) *UserHandler {
+	return &UserHandler{
+		userService: userService,
+		logger:      logger,
+	}
+}
+
+// DisableAccess отключает пользователю весь доступ (только для админов)
+// POST /api/v1/users/{id}/disable-access
+func (h *UserHandler) DisableAccess(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(getPathParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	claims, ok := middleware.GetUserClaims(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	result, err := h.userService.DisableAccess(r.Context(), userID, claims.UserID)
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to disable user access", map[string]interface{}{
+				"user_id": userID,
+				"error":   err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to disable user access")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// ListUsers возвращает список активных пользователей (только для админов)
+// GET /api/v1/users[?include_inactive=true]
+func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	limit, offset := getPaginationParams(r)
+
+	includeInactive, ok := parseIncludeInactive(w, r)
+	if !ok {
+		return
+	}
+
+	users, err := h.userService.ListUsers(r.Context(), limit, offset, includeInactive)
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to list users", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to list users")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    users,
+		"pagination": map[string]interface{}{
+			"limit":  limit,
+			"offset": offset,
+		},
+	})
+}
+
+// GetUser возвращает пользователя по ID (только для админов)
+// GET /api/v1/users/{id}
+func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(getPathParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	u, err := h.userService.GetUser(r.Context(), userID)
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to get user", map[string]interface{}{
+				"user_id": userID,
+				"error":   err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to get user")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    u,
+	})
+}
+
+// UpdateUser меняет роль и активность пользователя (только для админов)
+// PATCH /api/v1/users/{id}
+func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(getPathParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req user.UpdateUserRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	u, err := h.userService.UpdateUser(r.Context(), userID, &req)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidRole) {
+			respondDomainFieldError(w, err, "role", "Invalid role: expected admin, guard or user")
+			return
+		}
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to update user", map[string]interface{}{
+				"user_id": userID,
+				"error":   err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to update user")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    u,
+	})
+}
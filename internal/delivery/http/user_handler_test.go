@@ -0,0 +1,222 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/usecase/user"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserHandler_DisableAccess(t *testing.T) {
+	userID := uuid.New()
+	adminID := uuid.New()
+
+	tests := []struct {
+		name           string
+		userParam      string
+		mockSetup      func(*MockUserService)
+		expectedStatus int
+		checkResponse  func(*testing.T, map[string]interface{})
+	}{
+		{
+			name:      "доступ отключен, возвращаются счетчики",
+			userParam: userID.String(),
+			mockSetup: func(m *MockUserService) {
+				m.On("DisableAccess", mock.Anything, userID, adminID).Return(&domain.UserDisableResult{
+					UserID:               userID,
+					VehiclesDeactivated:  2,
+					PassesRevoked:        3,
+					RefreshTokensRevoked: 4,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, resp map[string]interface{}) {
+				data, ok := resp["data"].(map[string]interface{})
+				require.True(t, ok)
+				assert.Equal(t, userID.String(), data["user_id"])
+				assert.Equal(t, float64(2), data["vehicles_deactivated"])
+				assert.Equal(t, float64(3), data["passes_revoked"])
+				assert.Equal(t, float64(4), data["refresh_tokens_revoked"])
+			},
+		},
+		{
+			name:           "невалидный ID",
+			userParam:      "not-a-uuid",
+			mockSetup:      func(m *MockUserService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:      "пользователь не найден",
+			userParam: userID.String(),
+			mockSetup: func(m *MockUserService) {
+				m.On("DisableAccess", mock.Anything, userID, adminID).Return(nil, domain.ErrUserNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:      "последний администратор",
+			userParam: userID.String(),
+			mockSetup: func(m *MockUserService) {
+				m.On("DisableAccess", mock.Anything, userID, adminID).Return(nil, domain.ErrLastAdmin)
+			},
+			expectedStatus: http.StatusConflict,
+			checkResponse: func(t *testing.T, resp map[string]interface{}) {
+				AssertErrorCode(t, resp, "LAST_ADMIN")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			tt.mockSetup(mockService)
+
+			handler := NewUserHandler(mockService, logger.NewNoop())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/users/"+tt.userParam+"/disable-access", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.userParam)
+			ctx := CreateAuthContext(t, adminID, "admin@test.com", domain.RoleAdmin)
+			req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+
+			w := httptest.NewRecorder()
+			handler.DisableAccess(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.checkResponse != nil {
+				var response map[string]interface{}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				tt.checkResponse(t, response)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_ListUsers(t *testing.T) {
+	tests := []struct {
+		name            string
+		query           string
+		includeInactive bool
+		expectedStatus  int
+	}{
+		{
+			name:           "по умолчанию только активные",
+			query:          "?limit=10",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:            "include_inactive возвращает деактивированных",
+			query:           "?limit=10&include_inactive=true",
+			includeInactive: true,
+			expectedStatus:  http.StatusOK,
+		},
+		{
+			name:           "невалидный include_inactive",
+			query:          "?limit=10&include_inactive=maybe",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			if tt.expectedStatus == http.StatusOK {
+				mockService.On("ListUsers", mock.Anything, 10, 0, tt.includeInactive).
+					Return([]*domain.User{{ID: uuid.New(), Email: "user@test.com", Role: domain.RoleUser, PasswordHash: "secret-hash"}}, nil)
+			}
+
+			handler := NewUserHandler(mockService, logger.NewNoop())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/users"+tt.query, nil)
+			req = req.WithContext(CreateAuthContext(t, uuid.New(), "admin@test.com", domain.RoleAdmin))
+			w := httptest.NewRecorder()
+			handler.ListUsers(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.NotContains(t, w.Body.String(), "secret-hash")
+
+				var response map[string]interface{}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				data, ok := response["data"].([]interface{})
+				require.True(t, ok)
+				assert.Len(t, data, 1)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_UpdateUser(t *testing.T) {
+	userID := uuid.New()
+	adminID := uuid.New()
+
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func(*MockUserService)
+		expectedStatus int
+	}{
+		{
+			name: "роль изменена",
+			body: `{"role":"guard"}`,
+			mockSetup: func(m *MockUserService) {
+				m.On("UpdateUser", mock.Anything, userID, mock.MatchedBy(func(req *user.UpdateUserRequest) bool {
+					return req.Role != nil && *req.Role == domain.RoleGuard && req.IsActive == nil
+				})).Return(&domain.User{ID: userID, Role: domain.RoleGuard, IsActive: true}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "неизвестная роль",
+			body: `{"role":"superuser"}`,
+			mockSetup: func(m *MockUserService) {
+				m.On("UpdateUser", mock.Anything, userID, mock.Anything).Return(nil, domain.ErrInvalidRole)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "последний админ",
+			body: `{"is_active":false}`,
+			mockSetup: func(m *MockUserService) {
+				m.On("UpdateUser", mock.Anything, userID, mock.Anything).Return(nil, domain.ErrLastAdmin)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "невалидное тело запроса",
+			body:           `{invalid`,
+			mockSetup:      func(m *MockUserService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			tt.mockSetup(mockService)
+
+			handler := NewUserHandler(mockService, logger.NewNoop())
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/v1/users/"+userID.String(), bytes.NewBufferString(tt.body))
+			req = withEntryID(t, req, adminID, userID.String())
+			w := httptest.NewRecorder()
+			handler.UpdateUser(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
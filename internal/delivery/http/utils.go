@@ -1,10 +1,17 @@
 package http
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"github.com/frontandrew/gate/internal/delivery/http/apierror"
+	"github.com/frontandrew/gate/internal/delivery/http/middleware"
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
 	"github.com/go-chi/chi/v5"
 )
 
@@ -13,7 +20,7 @@ func respondJSON(w http.ResponseWriter, code int, payload interface{}) {
 	response, err := json.Marshal(payload)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
-		_, _ = w.Write([]byte(`{"error":"Failed to marshal response"}`))
+		_, _ = w.Write([]byte(`{"success":false,"error":{"code":"INTERNAL_ERROR","message":"Failed to marshal response"}}`))
 		return
 	}
 
@@ -22,10 +29,61 @@ func respondJSON(w http.ResponseWriter, code int, payload interface{}) {
 	_, _ = w.Write(response)
 }
 
-// respondError отправляет JSON ответ с ошибкой
+// respondJSONWithETag отправляет JSON ответ с ETag
+// Если клиент прислал совпадающий If-None-Match, возвращается 304 без тела
+func respondJSONWithETag(w http.ResponseWriter, r *http.Request, code int, payload interface{}) {
+	response, err := json.Marshal(payload)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"success":false,"error":{"code":"INTERNAL_ERROR","message":"Failed to marshal response"}}`))
+		return
+	}
+
+	etag := computeETag(response)
+	w.Header().Set("ETag", etag)
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(response)
+}
+
+// computeETag вычисляет сильный ETag по сериализованному телу ответа
+func computeETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches проверяет значение If-None-Match (может содержать список или *)
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// respondError отправляет JSON ответ с ошибкой; код ошибки определяется по HTTP статусу
 func respondError(w http.ResponseWriter, code int, message string) {
-	respondJSON(w, code, map[string]string{
-		"error": message,
+	apierror.Error(w, code, message)
+}
+
+// respondFieldError отправляет JSON ответ с ошибкой, относящейся к конкретному полю запроса
+func respondFieldError(w http.ResponseWriter, code int, field, message string) {
+	apierror.Write(w, code, apierror.Detail{
+		Code:    apierror.CodeValidationFailed,
+		Message: message,
+		Field:   field,
 	})
 }
 
@@ -44,3 +102,34 @@ func getPathParam(r *http.Request, param string) string {
 	}
 	return ""
 }
+
+// requestLogger возвращает logger запроса (с request_id и user_id) или fallback, если middleware не подключен
+func requestLogger(r *http.Request, fallback logger.Logger) logger.Logger {
+	return logger.FromContextOr(r.Context(), fallback)
+}
+
+// parseIncludeInactive разбирает ?include_inactive=: деактивированные пользователи и автомобили
+// по умолчанию скрыты, показать их может только администратор.
+// При ошибке сам отправляет ответ и возвращает ok=false
+func parseIncludeInactive(w http.ResponseWriter, r *http.Request) (include bool, ok bool) {
+	value := r.URL.Query().Get("include_inactive")
+	if value == "" {
+		return false, true
+	}
+
+	include, err := strconv.ParseBool(value)
+	if err != nil {
+		respondFieldError(w, http.StatusBadRequest, "include_inactive", "Invalid include_inactive: expected true or false")
+		return false, false
+	}
+	if !include {
+		return false, true
+	}
+
+	claims, authenticated := middleware.GetUserClaims(r.Context())
+	if !authenticated || claims.Role != domain.RoleAdmin {
+		respondError(w, http.StatusForbidden, "Only admins can include inactive records")
+		return false, false
+	}
+	return true, true
+}
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/frontandrew/gate/internal/delivery/http/apierror"
+	"github.com/go-playground/validator/v10"
+)
+
+// requestValidator проверяет теги validate в структурах запросов
+// В ошибках используются имена полей из тегов json, чтобы они совпадали с телом запроса
+var requestValidator = newRequestValidator()
+
+func newRequestValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+	return v
+}
+
+// decodeJSON строго декодирует JSON тело запроса в dst: неизвестные поля отклоняются
+// При ошибке сам отправляет ответ (413 для слишком большого тела, иначе 400) и возвращает false
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(dst)
+	if err == nil {
+		return true
+	}
+
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		respondError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body exceeds %d bytes", maxBytesErr.Limit))
+		return false
+	}
+
+	// encoding/json не экспортирует тип этой ошибки, поле извлекается из текста
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		respondFieldError(w, http.StatusBadRequest, strings.Trim(field, `"`), "Unknown field")
+		return false
+	}
+
+	respondError(w, http.StatusBadRequest, "Invalid request body")
+	return false
+}
+
+// decodeAndValidate декодирует JSON тело запроса в dst и проверяет его теги validate
+// При ошибке сам отправляет ответ и возвращает false
+func decodeAndValidate(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	if !decodeJSON(w, r, dst) {
+		return false
+	}
+	return validateRequest(w, dst)
+}
+
+// validateRequest проверяет теги validate уже заполненного запроса
+// Используется, когда часть полей (например, из claims) заполняется после декодирования
+func validateRequest(w http.ResponseWriter, req interface{}) bool {
+	err := requestValidator.Struct(req)
+	if err == nil {
+		return true
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+
+	fields := make([]apierror.FieldError, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		fields = append(fields, apierror.FieldError{
+			Field:   fe.Field(),
+			Message: fieldErrorMessage(fe),
+		})
+	}
+
+	apierror.Write(w, http.StatusBadRequest, apierror.Detail{
+		Code:    apierror.CodeValidationFailed,
+		Message: "Validation failed",
+		Fields:  fields,
+	})
+	return false
+}
+
+// fieldErrorMessage формирует понятное клиенту описание нарушенного правила
+func fieldErrorMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", fe.Field())
+	case "email":
+		return fmt.Sprintf("%s must be a valid email address", fe.Field())
+	case "min":
+		if fe.Kind() == reflect.Slice || fe.Kind() == reflect.Array || fe.Kind() == reflect.Map {
+			return fmt.Sprintf("%s must contain at least %s items", fe.Field(), fe.Param())
+		}
+		return fmt.Sprintf("%s must be at least %s characters", fe.Field(), fe.Param())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", fe.Field(), strings.ReplaceAll(fe.Param(), " ", ", "))
+	default:
+		return fmt.Sprintf("%s failed %s validation", fe.Field(), fe.Tag())
+	}
+}
@@ -2,8 +2,10 @@ package http
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/frontandrew/gate/internal/delivery/http/middleware"
 	"github.com/frontandrew/gate/internal/domain"
@@ -15,8 +17,13 @@ import (
 // VehicleService определяет интерфейс для сервиса автомобилей
 type VehicleService interface {
 	CreateVehicle(ctx context.Context, req *vehicle.CreateVehicleRequest) (*domain.Vehicle, error)
-	GetVehiclesByOwner(ctx context.Context, ownerID uuid.UUID) ([]*domain.Vehicle, error)
-	GetVehicleByID(ctx context.Context, vehicleID uuid.UUID) (*domain.Vehicle, error)
+	GetVehiclesByOwner(ctx context.Context, ownerID uuid.UUID, includeInactive bool) ([]*domain.Vehicle, error)
+	GetVehicleByID(ctx context.Context, vehicleID uuid.UUID, includeInactive bool) (*domain.Vehicle, error)
+	MergeVehicles(ctx context.Context, req *vehicle.MergeVehiclesRequest) (*domain.VehicleMergeResult, error)
+	DeleteVehicle(ctx context.Context, id uuid.UUID, hard bool) (*domain.VehicleDeleteResult, error)
+	ListVehicles(ctx context.Context, filter domain.VehicleFilter, limit, offset int) ([]*domain.Vehicle, error)
+	CountVehicles(ctx context.Context, filter domain.VehicleFilter) (int, error)
+	SearchVehiclesByPlate(ctx context.Context, plate string, limit int, includeInactive bool) ([]*domain.Vehicle, error)
 }
 
 // VehicleHandler обрабатывает запросы связанные с автомобилями
@@ -37,8 +44,7 @@ func NewVehicleHandler(vehicleService VehicleService, logger logger.Logger) *Veh
 // POST /api/v1/vehicles
 func (h *VehicleHandler) CreateVehicle(w http.ResponseWriter, r *http.Request) {
 	var req vehicle.CreateVehicleRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -57,14 +63,12 @@ func (h *VehicleHandler) CreateVehicle(w http.ResponseWriter, r *http.Request) {
 
 	v, err := h.vehicleService.CreateVehicle(r.Context(), &req)
 	if err != nil {
-		if err == domain.ErrVehicleAlreadyExists {
-			respondError(w, http.StatusConflict, "Vehicle already exists")
-			return
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to create vehicle", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to create vehicle")
 		}
-		h.logger.Error("Failed to create vehicle", map[string]interface{}{
-			"error": err.Error(),
-		})
-		respondError(w, http.StatusInternalServerError, "Failed to create vehicle")
 		return
 	}
 
@@ -74,8 +78,8 @@ func (h *VehicleHandler) CreateVehicle(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// GetMyVehicles возвращает все автомобили текущего пользователя
-// GET /api/v1/vehicles/me
+// GetMyVehicles возвращает активные автомобили текущего пользователя
+// GET /api/v1/vehicles/me[?include_inactive=true]
 func (h *VehicleHandler) GetMyVehicles(w http.ResponseWriter, r *http.Request) {
 	claims, ok := middleware.GetUserClaims(r.Context())
 	if !ok {
@@ -83,12 +87,19 @@ func (h *VehicleHandler) GetMyVehicles(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	vehicles, err := h.vehicleService.GetVehiclesByOwner(r.Context(), claims.UserID)
+	includeInactive, ok := parseIncludeInactive(w, r)
+	if !ok {
+		return
+	}
+
+	vehicles, err := h.vehicleService.GetVehiclesByOwner(r.Context(), claims.UserID, includeInactive)
 	if err != nil {
-		h.logger.Error("Failed to get user vehicles", map[string]interface{}{
-			"error": err.Error(),
-		})
-		respondError(w, http.StatusInternalServerError, "Failed to get vehicles")
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to get user vehicles", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to get vehicles")
+		}
 		return
 	}
 
@@ -99,7 +110,8 @@ func (h *VehicleHandler) GetMyVehicles(w http.ResponseWriter, r *http.Request) {
 }
 
 // GetVehicleByID возвращает автомобиль по ID
-// GET /api/v1/vehicles/:id
+// Деактивированный автомобиль виден только администратору с include_inactive=true
+// GET /api/v1/vehicles/:id[?include_inactive=true]
 func (h *VehicleHandler) GetVehicleByID(w http.ResponseWriter, r *http.Request) {
 	vehicleIDStr := getPathParam(r, "id")
 	vehicleID, err := uuid.Parse(vehicleIDStr)
@@ -108,21 +120,192 @@ func (h *VehicleHandler) GetVehicleByID(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	v, err := h.vehicleService.GetVehicleByID(r.Context(), vehicleID)
+	includeInactive, ok := parseIncludeInactive(w, r)
+	if !ok {
+		return
+	}
+
+	v, err := h.vehicleService.GetVehicleByID(r.Context(), vehicleID, includeInactive)
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to get vehicle", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to get vehicle")
+		}
+		return
+	}
+
+	respondJSONWithETag(w, r, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    v,
+	})
+}
+
+// MergeVehicles объединяет дубликаты автомобилей (только для админов)
+// POST /api/v1/vehicles/merge
+func (h *VehicleHandler) MergeVehicles(w http.ResponseWriter, r *http.Request) {
+	var req vehicle.MergeVehiclesRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	result, err := h.vehicleService.MergeVehicles(r.Context(), &req)
 	if err != nil {
-		if err == domain.ErrVehicleNotFound {
-			respondError(w, http.StatusNotFound, "Vehicle not found")
+		switch {
+		case errors.Is(err, domain.ErrInvalidVehicleData):
+			respondDomainErrorMessage(w, err, "Source and target must be different vehicles")
+		default:
+			if !respondDomainError(w, err) {
+				requestLogger(r, h.logger).Error("Failed to merge vehicles", map[string]interface{}{
+					"error": err.Error(),
+				})
+				respondError(w, http.StatusInternalServerError, "Failed to merge vehicles")
+			}
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// DeleteVehicle удаляет автомобиль; ?hard=true - физическое удаление (например, по запросу GDPR)
+// DELETE /api/v1/vehicles/{id}
+func (h *VehicleHandler) DeleteVehicle(w http.ResponseWriter, r *http.Request) {
+	vehicleID, err := uuid.Parse(getPathParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid vehicle ID")
+		return
+	}
+
+	hard := false
+	if hardStr := r.URL.Query().Get("hard"); hardStr != "" {
+		hard, err = strconv.ParseBool(hardStr)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid hard flag")
 			return
 		}
-		h.logger.Error("Failed to get vehicle", map[string]interface{}{
-			"error": err.Error(),
-		})
-		respondError(w, http.StatusInternalServerError, "Failed to get vehicle")
+	}
+
+	result, err := h.vehicleService.DeleteVehicle(r.Context(), vehicleID, hard)
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to delete vehicle", map[string]interface{}{
+				"vehicle_id": vehicleID,
+				"hard":       hard,
+				"error":      err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to delete vehicle")
+		}
 		return
 	}
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
 		"success": true,
-		"data":    v,
+		"data":    result,
+	})
+}
+
+// ListVehicles возвращает список автомобилей с фильтрами (только для админов)
+// Без is_active и include_inactive=true возвращаются только активные автомобили
+// GET /api/v1/vehicles?owner_id=&is_active=&include_inactive=&limit=&offset=
+func (h *VehicleHandler) ListVehicles(w http.ResponseWriter, r *http.Request) {
+	limit, offset := getPaginationParams(r)
+	query := r.URL.Query()
+
+	var filter domain.VehicleFilter
+	if ownerIDStr := query.Get("owner_id"); ownerIDStr != "" {
+		ownerID, err := uuid.Parse(ownerIDStr)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid owner_id")
+			return
+		}
+		filter.OwnerID = &ownerID
+	}
+	if isActiveStr := query.Get("is_active"); isActiveStr != "" {
+		isActive, err := strconv.ParseBool(isActiveStr)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid is_active")
+			return
+		}
+		filter.IsActive = &isActive
+	}
+
+	includeInactive, ok := parseIncludeInactive(w, r)
+	if !ok {
+		return
+	}
+	if filter.IsActive == nil && !includeInactive {
+		active := true
+		filter.IsActive = &active
+	}
+
+	vehicles, err := h.vehicleService.ListVehicles(r.Context(), filter, limit, offset)
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to list vehicles", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to list vehicles")
+		}
+		return
+	}
+
+	total, err := h.vehicleService.CountVehicles(r.Context(), filter)
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to count vehicles", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to list vehicles")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    vehicles,
+		"pagination": map[string]int{
+			"limit":  limit,
+			"offset": offset,
+			"total":  total,
+		},
+	})
+}
+
+// SearchVehicles ищет автомобили по части номера (для охраны и админов)
+// Деактивированные автомобили ищутся только администратором с include_inactive=true
+// GET /api/v1/vehicles/search?plate=&limit=[&include_inactive=true]
+func (h *VehicleHandler) SearchVehicles(w http.ResponseWriter, r *http.Request) {
+	limit, _ := getPaginationParams(r)
+	plate := r.URL.Query().Get("plate")
+
+	includeInactive, ok := parseIncludeInactive(w, r)
+	if !ok {
+		return
+	}
+
+	vehicles, err := h.vehicleService.SearchVehiclesByPlate(r.Context(), plate, limit, includeInactive)
+	if err != nil {
+		if errors.Is(err, domain.ErrPlateQueryTooShort) {
+			respondDomainErrorMessage(w, err, fmt.Sprintf("Plate query must be at least %d characters", vehicle.MinPlateSearchLength))
+			return
+		}
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to search vehicles", map[string]interface{}{
+				"plate": plate,
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to search vehicles")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    vehicles,
 	})
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // TestVehicleHandler_CreateVehicle тестирует создание автомобиля
@@ -157,7 +158,7 @@ func TestVehicleHandler_GetMyVehicles(t *testing.T) {
 			name:   "успешное получение",
 			userID: userID,
 			mockSetup: func(m *MockVehicleService) {
-				m.On("GetVehiclesByOwner", mock.Anything, userID).Return(vehicles, nil)
+				m.On("GetVehiclesByOwner", mock.Anything, userID, false).Return(vehicles, nil)
 			},
 			expectedStatus: http.StatusOK,
 			checkResponse: func(t *testing.T, resp map[string]interface{}) {
@@ -173,7 +174,7 @@ func TestVehicleHandler_GetMyVehicles(t *testing.T) {
 			name:   "нет автомобилей",
 			userID: userID,
 			mockSetup: func(m *MockVehicleService) {
-				m.On("GetVehiclesByOwner", mock.Anything, userID).Return([]*domain.Vehicle{}, nil)
+				m.On("GetVehiclesByOwner", mock.Anything, userID, false).Return([]*domain.Vehicle{}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			checkResponse: func(t *testing.T, resp map[string]interface{}) {
@@ -220,6 +221,8 @@ func TestVehicleHandler_GetVehicleByID(t *testing.T) {
 	tests := []struct {
 		name           string
 		vehicleID      string
+		query          string
+		role           domain.UserRole
 		mockSetup      func(*MockVehicleService)
 		expectedStatus int
 		checkResponse  func(*testing.T, map[string]interface{})
@@ -228,7 +231,7 @@ func TestVehicleHandler_GetVehicleByID(t *testing.T) {
 			name:      "успешное получение",
 			vehicleID: vehicleID.String(),
 			mockSetup: func(m *MockVehicleService) {
-				m.On("GetVehicleByID", mock.Anything, vehicleID).Return(&domain.Vehicle{
+				m.On("GetVehicleByID", mock.Anything, vehicleID, false).Return(&domain.Vehicle{
 					ID:           vehicleID,
 					OwnerID:      userID,
 					LicensePlate: "А123ВС777",
@@ -251,7 +254,7 @@ func TestVehicleHandler_GetVehicleByID(t *testing.T) {
 			name:      "автомобиль не найден",
 			vehicleID: vehicleID.String(),
 			mockSetup: func(m *MockVehicleService) {
-				m.On("GetVehicleByID", mock.Anything, vehicleID).
+				m.On("GetVehicleByID", mock.Anything, vehicleID, false).
 					Return(nil, domain.ErrVehicleNotFound)
 			},
 			expectedStatus: http.StatusNotFound,
@@ -261,6 +264,45 @@ func TestVehicleHandler_GetVehicleByID(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:      "администратор видит деактивированный автомобиль",
+			vehicleID: vehicleID.String(),
+			query:     "?include_inactive=true",
+			role:      domain.RoleAdmin,
+			mockSetup: func(m *MockVehicleService) {
+				m.On("GetVehicleByID", mock.Anything, vehicleID, true).Return(&domain.Vehicle{
+					ID:           vehicleID,
+					OwnerID:      userID,
+					LicensePlate: "А123ВС777",
+					VehicleType:  "car",
+					IsActive:     false,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, resp map[string]interface{}) {
+				if data, ok := resp["data"].(map[string]interface{}); ok {
+					assert.Equal(t, false, data["is_active"])
+				}
+			},
+		},
+		{
+			name:           "include_inactive недоступен не администратору",
+			vehicleID:      vehicleID.String(),
+			query:          "?include_inactive=true",
+			role:           domain.RoleUser,
+			mockSetup:      func(m *MockVehicleService) {},
+			expectedStatus: http.StatusForbidden,
+			checkResponse:  func(t *testing.T, resp map[string]interface{}) {},
+		},
+		{
+			name:           "невалидный include_inactive",
+			vehicleID:      vehicleID.String(),
+			query:          "?include_inactive=maybe",
+			role:           domain.RoleAdmin,
+			mockSetup:      func(m *MockVehicleService) {},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse:  func(t *testing.T, resp map[string]interface{}) {},
+		},
 		{
 			name:           "невалидный UUID",
 			vehicleID:      "invalid-uuid",
@@ -282,12 +324,16 @@ func TestVehicleHandler_GetVehicleByID(t *testing.T) {
 			log := logger.NewDevelopment()
 			handler := NewVehicleHandler(mockService, log)
 
-			req := httptest.NewRequest(http.MethodGet, "/api/v1/vehicles/"+tt.vehicleID, nil)
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/vehicles/"+tt.vehicleID+tt.query, nil)
+			ctx := req.Context()
+			if tt.role != "" {
+				ctx = CreateAuthContext(t, userID, "test@example.com", tt.role)
+			}
 
 			// Настраиваем chi router для передачи параметра id
 			rctx := chi.NewRouteContext()
 			rctx.URLParams.Add("id", tt.vehicleID)
-			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
 
 			w := httptest.NewRecorder()
 			handler.GetVehicleByID(w, req)
@@ -302,3 +348,251 @@ func TestVehicleHandler_GetVehicleByID(t *testing.T) {
 		})
 	}
 }
+
+func TestVehicleHandler_DeleteVehicle(t *testing.T) {
+	vehicleID := uuid.New()
+
+	tests := []struct {
+		name           string
+		query          string
+		mockSetup      func(*MockVehicleService)
+		expectedStatus int
+	}{
+		{
+			name: "мягкое удаление по умолчанию",
+			mockSetup: func(m *MockVehicleService) {
+				m.On("DeleteVehicle", mock.Anything, vehicleID, false).
+					Return(&domain.VehicleDeleteResult{VehicleID: vehicleID}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "физическое удаление",
+			query: "?hard=true",
+			mockSetup: func(m *MockVehicleService) {
+				m.On("DeleteVehicle", mock.Anything, vehicleID, true).
+					Return(&domain.VehicleDeleteResult{VehicleID: vehicleID, Hard: true, PassLinksRemoved: 1}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "невалидный флаг hard",
+			query:          "?hard=maybe",
+			mockSetup:      func(m *MockVehicleService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "автомобиль не найден",
+			query: "?hard=true",
+			mockSetup: func(m *MockVehicleService) {
+				m.On("DeleteVehicle", mock.Anything, vehicleID, true).Return(nil, domain.ErrVehicleNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockVehicleService)
+			tt.mockSetup(mockService)
+
+			handler := NewVehicleHandler(mockService, logger.NewNoop())
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/vehicles/"+vehicleID.String()+tt.query, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", vehicleID.String())
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			w := httptest.NewRecorder()
+			handler.DeleteVehicle(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestVehicleHandler_ListVehicles(t *testing.T) {
+	ownerID := uuid.New()
+	active := true
+	inactive := false
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedFilter domain.VehicleFilter
+		expectedLimit  int
+		expectedOffset int
+		expectedStatus int
+	}{
+		{
+			name:           "без фильтров - только активные",
+			expectedFilter: domain.VehicleFilter{IsActive: &active},
+			expectedLimit:  defaultPageSize,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "include_inactive снимает фильтр активности",
+			query:          "?include_inactive=true",
+			expectedLimit:  defaultPageSize,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "только владелец",
+			query:          "?owner_id=" + ownerID.String(),
+			expectedFilter: domain.VehicleFilter{OwnerID: &ownerID, IsActive: &active},
+			expectedLimit:  defaultPageSize,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "только неактивные",
+			query:          "?is_active=false",
+			expectedFilter: domain.VehicleFilter{IsActive: &inactive},
+			expectedLimit:  defaultPageSize,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "владелец, активность и пагинация",
+			query:          "?owner_id=" + ownerID.String() + "&is_active=true&limit=10&offset=20",
+			expectedFilter: domain.VehicleFilter{OwnerID: &ownerID, IsActive: &active},
+			expectedLimit:  10,
+			expectedOffset: 20,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "невалидный owner_id",
+			query:          "?owner_id=not-a-uuid",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "невалидный is_active",
+			query:          "?is_active=maybe",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "невалидный include_inactive",
+			query:          "?include_inactive=maybe",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockVehicleService)
+			if tt.expectedStatus == http.StatusOK {
+				mockService.On("ListVehicles", mock.Anything, tt.expectedFilter, tt.expectedLimit, tt.expectedOffset).
+					Return([]*domain.Vehicle{CreateTestVehicle(uuid.New(), ownerID, "A123BC777")}, nil)
+				mockService.On("CountVehicles", mock.Anything, tt.expectedFilter).Return(42, nil)
+			}
+
+			handler := NewVehicleHandler(mockService, logger.NewNoop())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/vehicles"+tt.query, nil)
+			req = req.WithContext(CreateAuthContext(t, uuid.New(), "admin@example.com", domain.RoleAdmin))
+			w := httptest.NewRecorder()
+			handler.ListVehicles(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response map[string]interface{}
+				_ = json.Unmarshal(w.Body.Bytes(), &response)
+				pagination, ok := response["pagination"].(map[string]interface{})
+				if assert.True(t, ok) {
+					assert.Equal(t, float64(tt.expectedLimit), pagination["limit"])
+					assert.Equal(t, float64(tt.expectedOffset), pagination["offset"])
+					assert.Equal(t, float64(42), pagination["total"], "total считается по тому же фильтру")
+				}
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestVehicleHandler_SearchVehicles(t *testing.T) {
+	ownerID := uuid.New()
+
+	tests := []struct {
+		name           string
+		query          string
+		role           domain.UserRole
+		mockSetup      func(*MockVehicleService)
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name:  "частичное совпадение",
+			query: "?plate=123",
+			role:  domain.RoleGuard,
+			mockSetup: func(m *MockVehicleService) {
+				m.On("SearchVehiclesByPlate", mock.Anything, "123", defaultPageSize, false).
+					Return([]*domain.Vehicle{
+						CreateTestVehicle(uuid.New(), ownerID, "A123BC777"),
+						CreateTestVehicle(uuid.New(), ownerID, "B123KM750"),
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCount:  2,
+		},
+		{
+			name:  "нет совпадений",
+			query: "?plate=XYZ&limit=5",
+			role:  domain.RoleGuard,
+			mockSetup: func(m *MockVehicleService) {
+				m.On("SearchVehiclesByPlate", mock.Anything, "XYZ", 5, false).Return([]*domain.Vehicle{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCount:  0,
+		},
+		{
+			name:  "администратор ищет с деактивированными",
+			query: "?plate=123&include_inactive=true",
+			role:  domain.RoleAdmin,
+			mockSetup: func(m *MockVehicleService) {
+				m.On("SearchVehiclesByPlate", mock.Anything, "123", defaultPageSize, true).
+					Return([]*domain.Vehicle{CreateTestVehicle(uuid.New(), ownerID, "A123BC777")}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCount:  1,
+		},
+		{
+			name:           "охрана не может включить деактивированные",
+			query:          "?plate=123&include_inactive=true",
+			role:           domain.RoleGuard,
+			mockSetup:      func(m *MockVehicleService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:  "слишком короткий запрос",
+			query: "?plate=A1",
+			role:  domain.RoleGuard,
+			mockSetup: func(m *MockVehicleService) {
+				m.On("SearchVehiclesByPlate", mock.Anything, "A1", defaultPageSize, false).Return(nil, domain.ErrPlateQueryTooShort)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockVehicleService)
+			tt.mockSetup(mockService)
+
+			handler := NewVehicleHandler(mockService, logger.NewNoop())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/vehicles/search"+tt.query, nil)
+			req = req.WithContext(CreateAuthContext(t, uuid.New(), "staff@example.com", tt.role))
+			w := httptest.NewRecorder()
+			handler.SearchVehicles(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response map[string]interface{}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				data, ok := response["data"].([]interface{})
+				require.True(t, ok)
+				assert.Len(t, data, tt.expectedCount)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
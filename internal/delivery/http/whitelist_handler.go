@@ -0,0 +1,217 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/frontandrew/gate/internal/delivery/http/middleware"
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/usecase/whitelist"
+	"github.com/google/uuid"
+)
+
+// WhitelistService определяет интерфейс для сервиса белого списка
+type WhitelistService interface {
+	CreateEntry(ctx context.Context, req *whitelist.CreateEntryRequest) (*domain.WhitelistEntry, error)
+	CreateEntries(ctx context.Context, reqs []whitelist.CreateEntryRequest, addedBy uuid.UUID) (*whitelist.BulkResult, error)
+	ListEntries(ctx context.Context, limit, offset int) ([]*domain.WhitelistEntry, error)
+	GetEntry(ctx context.Context, id uuid.UUID) (*domain.WhitelistEntry, error)
+	UpdateEntry(ctx context.Context, id uuid.UUID, req *whitelist.UpdateEntryRequest) (*domain.WhitelistEntry, error)
+	DeleteEntry(ctx context.Context, id uuid.UUID) error
+}
+
+// WhitelistHandler обрабатывает запросы управления белым списком
+type WhitelistHandler struct {
+	whitelistService WhitelistService
+	logger           logger.Logger
+}
+
+// NewWhitelistHandler создает новый handler
+func NewWhitelistHandler(whitelistService WhitelistService, logger logger.Logger) *WhitelistHandler {
+	return &WhitelistHandler{
+		whitelistService: whitelistService,
+		logger:           logger,
+	}
+}
+
+// CreateEntry добавляет номер в белый список (только для админов)
+// POST /api/v1/whitelist
+func (h *WhitelistHandler) CreateEntry(w http.ResponseWriter, r *http.Request) {
+	var req whitelist.CreateEntryRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	claims, ok := middleware.GetUserClaims(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	req.AddedBy = claims.UserID
+
+	entry, err := h.whitelistService.CreateEntry(r.Context(), &req)
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to create whitelist entry", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to create whitelist entry")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"success": true,
+		"data":    entry,
+	})
+}
+
+// BulkCreate добавляет пакет номеров в белый список (только для админов)
+// Тело - JSON-массив записей или CSV (license_plate,reason,expires_at); итог возвращается по каждой строке
+// POST /api/v1/whitelist/bulk
+func (h *WhitelistHandler) BulkCreate(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserClaims(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	reqs, ok := readBulkListRequest(w, r, whitelist.MaxBulkEntries, func(row bulkListRow) whitelist.CreateEntryRequest {
+		return whitelist.CreateEntryRequest{
+			LicensePlate: row.LicensePlate,
+			Reason:       row.Reason,
+			ExpiresAt:    row.ExpiresAt,
+			Zone:         row.Zone,
+		}
+	})
+	if !ok {
+		return
+	}
+
+	result, err := h.whitelistService.CreateEntries(r.Context(), reqs, claims.UserID)
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to bulk create whitelist entries", map[string]interface{}{
+				"entries": len(reqs),
+				"error":   err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to create whitelist entries")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// ListEntries возвращает записи белого списка (только для админов)
+// GET /api/v1/whitelist?limit=&offset=
+func (h *WhitelistHandler) ListEntries(w http.ResponseWriter, r *http.Request) {
+	limit, offset := getPaginationParams(r)
+
+	entries, err := h.whitelistService.ListEntries(r.Context(), limit, offset)
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to list whitelist entries", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to list whitelist entries")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    entries,
+		"pagination": map[string]int{
+			"limit":  limit,
+			"offset": offset,
+		},
+	})
+}
+
+// GetEntry возвращает запись белого списка по ID (только для админов)
+// GET /api/v1/whitelist/{id}
+func (h *WhitelistHandler) GetEntry(w http.ResponseWriter, r *http.Request) {
+	entryID, err := uuid.Parse(getPathParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid whitelist entry ID")
+		return
+	}
+
+	entry, err := h.whitelistService.GetEntry(r.Context(), entryID)
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to get whitelist entry", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to get whitelist entry")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    entry,
+	})
+}
+
+// UpdateEntry изменяет запись белого списка (только для админов)
+// PUT /api/v1/whitelist/{id}
+func (h *WhitelistHandler) UpdateEntry(w http.ResponseWriter, r *http.Request) {
+	entryID, err := uuid.Parse(getPathParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid whitelist entry ID")
+		return
+	}
+
+	var req whitelist.UpdateEntryRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	entry, err := h.whitelistService.UpdateEntry(r.Context(), entryID, &req)
+	if err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to update whitelist entry", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to update whitelist entry")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    entry,
+	})
+}
+
+// DeleteEntry удаляет запись из белого списка (только для админов)
+// DELETE /api/v1/whitelist/{id}
+func (h *WhitelistHandler) DeleteEntry(w http.ResponseWriter, r *http.Request) {
+	entryID, err := uuid.Parse(getPathParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid whitelist entry ID")
+		return
+	}
+
+	if err := h.whitelistService.DeleteEntry(r.Context(), entryID); err != nil {
+		if !respondDomainError(w, err) {
+			requestLogger(r, h.logger).Error("Failed to delete whitelist entry", map[string]interface{}{
+				"error": err.Error(),
+			})
+			respondError(w, http.StatusInternalServerError, "Failed to delete whitelist entry")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "Whitelist entry deleted successfully",
+	})
+}
@@ -0,0 +1,311 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/usecase/whitelist"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestWhitelistEntry(id uuid.UUID, licensePlate string) *domain.WhitelistEntry {
+	return &domain.WhitelistEntry{
+		ID:           id,
+		LicensePlate: licensePlate,
+		Reason:       "Скорая помощь",
+		AddedBy:      uuid.New(),
+		AddedAt:      time.Now(),
+		IsActive:     true,
+	}
+}
+
+// withEntryID добавляет в запрос claims админа и параметр маршрута {id}
+func withEntryID(t *testing.T, req *http.Request, adminID uuid.UUID, id string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	ctx := CreateAuthContext(t, adminID, "admin@test.com", domain.RoleAdmin)
+	return req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+}
+
+func TestWhitelistHandler_CreateEntry(t *testing.T) {
+	adminID := uuid.New()
+	expiresAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func(*MockWhitelistService)
+		expectedStatus int
+	}{
+		{
+			name: "запись создана, added_by берется из claims",
+			body: `{"license_plate":"A001AA777","reason":"Скорая помощь","expires_at":"` + expiresAt.Format(time.RFC3339) + `"}`,
+			mockSetup: func(m *MockWhitelistService) {
+				m.On("CreateEntry", mock.Anything, mock.MatchedBy(func(req *whitelist.CreateEntryRequest) bool {
+					return req.AddedBy == adminID && req.LicensePlate == "A001AA777" &&
+						req.ExpiresAt != nil && req.ExpiresAt.Equal(expiresAt)
+				})).Return(newTestWhitelistEntry(uuid.New(), "A001AA777"), nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "невалидное тело запроса",
+			body:           `{invalid`,
+			mockSetup:      func(m *MockWhitelistService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "срок действия в прошлом",
+			body: `{"license_plate":"A001AA777","reason":"Скорая помощь","expires_at":"2020-01-01T00:00:00Z"}`,
+			mockSetup: func(m *MockWhitelistService) {
+				m.On("CreateEntry", mock.Anything, mock.Anything).Return(nil, domain.ErrExpiryInPast)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "номер уже в белом списке",
+			body: `{"license_plate":"A001AA777","reason":"Скорая помощь"}`,
+			mockSetup: func(m *MockWhitelistService) {
+				m.On("CreateEntry", mock.Anything, mock.Anything).Return(nil, domain.ErrWhitelistEntryAlreadyExists)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockWhitelistService)
+			tt.mockSetup(mockService)
+
+			handler := NewWhitelistHandler(mockService, logger.NewNoop())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/whitelist", bytes.NewBufferString(tt.body))
+			req = req.WithContext(CreateAuthContext(t, adminID, "admin@test.com", domain.RoleAdmin))
+			w := httptest.NewRecorder()
+			handler.CreateEntry(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWhitelistHandler_ListEntries(t *testing.T) {
+	mockService := new(MockWhitelistService)
+	mockService.On("ListEntries", mock.Anything, 10, 20).
+		Return([]*domain.WhitelistEntry{newTestWhitelistEntry(uuid.New(), "A001AA777")}, nil)
+
+	handler := NewWhitelistHandler(mockService, logger.NewNoop())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/whitelist?limit=10&offset=20", nil)
+	w := httptest.NewRecorder()
+	handler.ListEntries(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	data, ok := response["data"].([]interface{})
+	require.True(t, ok)
+	assert.Len(t, data, 1)
+	pagination, ok := response["pagination"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, float64(10), pagination["limit"])
+	assert.Equal(t, float64(20), pagination["offset"])
+	mockService.AssertExpectations(t)
+}
+
+func TestWhitelistHandler_GetEntry(t *testing.T) {
+	adminID := uuid.New()
+	entryID := uuid.New()
+
+	tests := []struct {
+		name           string
+		entryParam     string
+		mockSetup      func(*MockWhitelistService)
+		expectedStatus int
+	}{
+		{
+			name:       "запись найдена",
+			entryParam: entryID.String(),
+			mockSetup: func(m *MockWhitelistService) {
+				m.On("GetEntry", mock.Anything, entryID).Return(newTestWhitelistEntry(entryID, "A001AA777"), nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "невалидный ID",
+			entryParam:     "not-a-uuid",
+			mockSetup:      func(m *MockWhitelistService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "запись не найдена",
+			entryParam: entryID.String(),
+			mockSetup: func(m *MockWhitelistService) {
+				m.On("GetEntry", mock.Anything, entryID).Return(nil, domain.ErrWhitelistEntryNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockWhitelistService)
+			tt.mockSetup(mockService)
+
+			handler := NewWhitelistHandler(mockService, logger.NewNoop())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/whitelist/"+tt.entryParam, nil)
+			req = withEntryID(t, req, adminID, tt.entryParam)
+			w := httptest.NewRecorder()
+			handler.GetEntry(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWhitelistHandler_UpdateEntry(t *testing.T) {
+	adminID := uuid.New()
+	entryID := uuid.New()
+
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func(*MockWhitelistService)
+		expectedStatus int
+	}{
+		{
+			name: "запись отключена",
+			body: `{"is_active":false}`,
+			mockSetup: func(m *MockWhitelistService) {
+				m.On("UpdateEntry", mock.Anything, entryID, mock.MatchedBy(func(req *whitelist.UpdateEntryRequest) bool {
+					return req.IsActive != nil && !*req.IsActive && req.Reason == nil
+				})).Return(newTestWhitelistEntry(entryID, "A001AA777"), nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "невалидное тело запроса",
+			body:           `{invalid`,
+			mockSetup:      func(m *MockWhitelistService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "пустая причина",
+			body: `{"reason":""}`,
+			mockSetup: func(m *MockWhitelistService) {
+				m.On("UpdateEntry", mock.Anything, entryID, mock.Anything).Return(nil, domain.ErrInvalidWhitelistData)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "запись не найдена",
+			body: `{"clear_expiry":true}`,
+			mockSetup: func(m *MockWhitelistService) {
+				m.On("UpdateEntry", mock.Anything, entryID, mock.Anything).Return(nil, domain.ErrWhitelistEntryNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockWhitelistService)
+			tt.mockSetup(mockService)
+
+			handler := NewWhitelistHandler(mockService, logger.NewNoop())
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/whitelist/"+entryID.String(), bytes.NewBufferString(tt.body))
+			req = withEntryID(t, req, adminID, entryID.String())
+			w := httptest.NewRecorder()
+			handler.UpdateEntry(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWhitelistHandler_DeleteEntry(t *testing.T) {
+	adminID := uuid.New()
+	entryID := uuid.New()
+
+	tests := []struct {
+		name           string
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "запись удалена",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "запись не найдена",
+			mockErr:        domain.ErrWhitelistEntryNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockWhitelistService)
+			mockService.On("DeleteEntry", mock.Anything, entryID).Return(tt.mockErr)
+
+			handler := NewWhitelistHandler(mockService, logger.NewNoop())
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/whitelist/"+entryID.String(), nil)
+			req = withEntryID(t, req, adminID, entryID.String())
+			w := httptest.NewRecorder()
+			handler.DeleteEntry(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWhitelistHandler_BulkCreate(t *testing.T) {
+	adminID := uuid.New()
+	ownerID := uuid.New()
+
+	mockService := new(MockWhitelistService)
+	mockService.On("CreateEntries", mock.Anything, mock.MatchedBy(func(reqs []whitelist.CreateEntryRequest) bool {
+		return len(reqs) == 2 && reqs[0].OwnerID != nil && *reqs[0].OwnerID == ownerID && reqs[1].LicensePlate == ""
+	}), adminID).Return(&whitelist.BulkResult{Created: 1, Failed: 1, Results: []whitelist.BulkEntryResult{
+		{Row: 1, LicensePlate: "A001AA777", Success: true},
+		{Row: 2, Error: domain.ErrInvalidLicensePlate.Error()},
+	}}, nil)
+
+	handler := NewWhitelistHandler(mockService, logger.NewNoop())
+
+	body := `[{"license_plate":"A001AA777","reason":"Подрядчик","owner_id":"` + ownerID.String() + `"},{"license_plate":"","reason":"Без номера"}]`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/whitelist/bulk", bytes.NewBufferString(body))
+	req = req.WithContext(CreateAuthContext(t, adminID, "admin@test.com", domain.RoleAdmin))
+	w := httptest.NewRecorder()
+	handler.BulkCreate(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data whitelist.BulkResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.Data.Created)
+	require.Len(t, response.Data.Results, 2)
+	assert.Equal(t, "invalid license plate", response.Data.Results[1].Error)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// AccessEvent - решение о доступе для ленты событий в реальном времени
+type AccessEvent struct {
+	LicensePlate  string    `json:"license_plate"`
+	AccessGranted bool      `json:"access_granted"`
+	Reason        string    `json:"reason"`
+	ReasonCode    string    `json:"reason_code,omitempty"`
+	GateID        string    `json:"gate_id,omitempty"`
+	Direction     Direction `json:"direction,omitempty"`
+	Observed      bool      `json:"observed,omitempty"` // Ворота в режиме наблюдения: шлагбаум решение не применял
+	Manual        bool      `json:"manual,omitempty"`   // Номер введен охранником, распознавание не выполнялось
+	Timestamp     time.Time `json:"timestamp"`
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,6 +15,15 @@ const (
 	DirectionOut Direction = "OUT" // Выезд с территории
 )
 
+// ParseDirection нормализует направление (регистр, пробелы) и проверяет его по допустимым значениям
+func ParseDirection(value string) (Direction, error) {
+	direction := Direction(strings.ToUpper(strings.TrimSpace(value)))
+	if direction != DirectionIn && direction != DirectionOut {
+		return "", ErrInvalidDirection
+	}
+	return direction, nil
+}
+
 // AccessLog - запись о проезде
 // ВАЖНО: Главная информация - КТО (User) получил доступ, ЧЕРЕЗ ЧТО (Vehicle) - вспомогательная
 type AccessLog struct {
@@ -25,15 +35,44 @@ type AccessLog struct {
 	RecognitionConfidence float64    `json:"recognition_confidence"` // Уверенность распознавания (0-100)
 	AccessGranted         bool       `json:"access_granted"`         // Разрешен ли доступ
 	AccessReason          string     `json:"access_reason"`          // Причина решения
+	ReasonCode            string     `json:"reason_code,omitempty"`  // Машиночитаемый код причины
 	GateID                string     `json:"gate_id,omitempty"`      // ID ворот
 	Direction             Direction  `json:"direction"`
 	Timestamp             time.Time  `json:"timestamp"`
+	MLModelVersion        string     `json:"ml_model_version,omitempty"`   // Версия ML модели, распознавшей номер
+	Observed              bool       `json:"observed,omitempty"`           // Режим наблюдения: решение не передавалось шлагбауму
+	DirectionInferred     bool       `json:"direction_inferred,omitempty"` // Направление выведено из предыдущего проезда (ворота без датчика)
+	Manual                bool       `json:"manual,omitempty"`             // Номер введен охранником вручную, без распознавания
+	OperatorID            *uuid.UUID `json:"operator_id,omitempty"`        // Кто выполнил ручную проверку
+	ManualReason          string     `json:"manual_reason,omitempty"`      // Причина ручной проверки
 
 	// Связанные данные (не хранятся в БД, заполняются при необходимости)
 	User    *User    `json:"user,omitempty"`
 	Vehicle *Vehicle `json:"vehicle,omitempty"`
 }
 
+// AccessLogFilter - условия выборки логов; пустые поля не ограничивают выборку
+type AccessLogFilter struct {
+	UserID        *uuid.UUID
+	VehicleID     *uuid.UUID
+	ReasonCode    string
+	GateID        string
+	Direction     Direction
+	AccessGranted *bool
+	From          *time.Time // Включительно
+	To            *time.Time // Не включительно
+}
+
+// AccessStats - сводная статистика проездов за период
+type AccessStats struct {
+	From          time.Time `json:"from"`
+	To            time.Time `json:"to"`
+	TotalCount    int       `json:"total_count"`
+	GrantedCount  int       `json:"granted_count"`
+	DeniedCount   int       `json:"denied_count"`
+	AvgConfidence float64   `json:"avg_confidence"` // 0, если за период нет проездов
+}
+
 // Validate проверяет корректность данных лога
 func (al *AccessLog) Validate() error {
 	if al.LicensePlate == "" {
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditTargetType - тип объекта, над которым выполнено действие
+type AuditTargetType string
+
+const (
+	AuditTargetUser      AuditTargetType = "user"
+	AuditTargetVehicle   AuditTargetType = "vehicle"
+	AuditTargetPass      AuditTargetType = "pass"
+	AuditTargetWhitelist AuditTargetType = "whitelist"
+	AuditTargetBlacklist AuditTargetType = "blacklist"
+)
+
+// ParseAuditTargetType проверяет тип объекта аудита по допустимым значениям
+func ParseAuditTargetType(value string) (AuditTargetType, error) {
+	switch t := AuditTargetType(value); t {
+	case AuditTargetUser, AuditTargetVehicle, AuditTargetPass, AuditTargetWhitelist, AuditTargetBlacklist:
+		return t, nil
+	default:
+		return "", ErrInvalidAuditTargetType
+	}
+}
+
+// AuditLog - запись журнала аудита
+type AuditLog struct {
+	ID         uuid.UUID              `json:"id"`
+	ActorID    *uuid.UUID             `json:"actor_id,omitempty"` // Кто выполнил действие (nil - система)
+	Action     string                 `json:"action"`             // Например: create, update, delete
+	TargetType AuditTargetType        `json:"target_type"`
+	TargetID   *uuid.UUID             `json:"target_id,omitempty"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+// Validate проверяет корректность записи аудита
+func (a *AuditLog) Validate() error {
+	if a.Action == "" {
+		return ErrInvalidAuditLogData
+	}
+	if _, err := ParseAuditTargetType(string(a.TargetType)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// AuditLogFilter - условия выборки журнала аудита; пустые поля не ограничивают выборку
+type AuditLogFilter struct {
+	TargetType AuditTargetType
+	TargetID   *uuid.UUID
+	ActorID    *uuid.UUID
+	From       *time.Time // Включительно
+	To         *time.Time // Не включительно
+}
@@ -16,6 +16,7 @@ type BlacklistEntry struct {
 	AddedAt      time.Time  `json:"added_at"`
 	ExpiresAt    *time.Time `json:"expires_at,omitempty"` // NULL = бессрочно
 	IsActive     bool       `json:"is_active"`
+	Zone         *string    `json:"zone,omitempty"` // NULL = действует на всех воротах
 }
 
 // IsExpired проверяет, истекла ли запись в черном списке
@@ -35,7 +36,8 @@ func (b *BlacklistEntry) IsValid() bool {
 }
 
 // Validate проверяет корректность данных
-func (b *BlacklistEntry) Validate() error {
+// plateCountry - страна, по формату которой проверяется номер (пустая строка - без проверки формата)
+func (b *BlacklistEntry) Validate(plateCountry string) error {
 	if b.LicensePlate == "" {
 		return ErrInvalidLicensePlate
 	}
@@ -46,8 +48,20 @@ func (b *BlacklistEntry) Validate() error {
 		return ErrInvalidBlacklistData
 	}
 
+	expiresAt, err := normalizeExpiry(b.ExpiresAt)
+	if err != nil {
+		return err
+	}
+	b.ExpiresAt = expiresAt
+
+	zone, err := NormalizeZone(b.Zone)
+	if err != nil {
+		return err
+	}
+	b.Zone = zone
+
 	// Нормализуем номер
 	b.LicensePlate = NormalizeLicensePlate(b.LicensePlate)
 
-	return nil
+	return ValidateLicensePlate(b.LicensePlate, plateCountry)
 }
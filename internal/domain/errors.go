@@ -10,10 +10,15 @@ var (
 	ErrUserAlreadyExists  = errors.New("user already exists")
 	ErrInvalidEmail       = errors.New("invalid email")
 	ErrInvalidPassword    = errors.New("invalid password")
+	ErrWeakPassword       = errors.New("password is too short")
 	ErrInvalidUserData    = errors.New("invalid user data")
 	ErrInvalidRole        = errors.New("invalid user role")
 	ErrUserInactive       = errors.New("user is inactive")
 	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrInvalidPhone       = errors.New("invalid phone number")
+	ErrAccountLocked      = errors.New("account temporarily locked")
+	ErrRegistrationClosed = errors.New("registration is closed")
+	ErrLastAdmin          = errors.New("cannot demote or deactivate the last active admin")
 )
 
 // Vehicle errors
@@ -22,24 +27,35 @@ var (
 	ErrVehicleAlreadyExists = errors.New("vehicle already exists")
 	ErrInvalidLicensePlate  = errors.New("invalid license plate")
 	ErrInvalidVehicleData   = errors.New("invalid vehicle data")
+	ErrVehicleInactive      = errors.New("vehicle is inactive")
+	ErrVehicleOwnerMismatch = errors.New("vehicles belong to different owners")
+	ErrPlateQueryTooShort   = errors.New("license plate query is too short")
 )
 
 // Pass errors
 var (
-	ErrPassNotFound       = errors.New("pass not found")
-	ErrInvalidPassData    = errors.New("invalid pass data")
-	ErrInvalidPassType    = errors.New("invalid pass type")
-	ErrInvalidDateRange   = errors.New("invalid date range")
-	ErrPassExpired        = errors.New("pass expired")
-	ErrPassNotActive      = errors.New("pass is not active")
-	ErrPassAlreadyRevoked = errors.New("pass already revoked")
-	ErrNoValidPass        = errors.New("no valid pass found")
+	ErrPassNotFound          = errors.New("pass not found")
+	ErrInvalidPassData       = errors.New("invalid pass data")
+	ErrInvalidPassType       = errors.New("invalid pass type")
+	ErrInvalidDateRange      = errors.New("invalid date range")
+	ErrInvalidPassSchedule   = errors.New("invalid pass schedule")
+	ErrPassExpired           = errors.New("pass expired")
+	ErrPassNotActive         = errors.New("pass is not active")
+	ErrPassAlreadyRevoked    = errors.New("pass already revoked")
+	ErrPassNotExtendable     = errors.New("only temporary passes can be extended")
+	ErrPassUsageLimitReached = errors.New("pass usage limit reached")
+	ErrOverlappingPass       = errors.New("overlapping active pass already exists")
+	ErrNoValidPass           = errors.New("no valid pass found")
+
+	ErrGuestPassLimitExceeded = errors.New("guest pass daily limit exceeded")
 )
 
 // PassVehicle errors
 var (
 	ErrPassVehicleNotFound      = errors.New("pass-vehicle relation not found")
 	ErrPassVehicleAlreadyExists = errors.New("pass-vehicle relation already exists")
+	ErrPassVehicleLinkFailed    = errors.New("failed to link vehicle to pass")
+	ErrPassVehicleOwnerMismatch = errors.New("vehicle does not belong to pass owner")
 	ErrInvalidPassVehicleData   = errors.New("invalid pass-vehicle data")
 )
 
@@ -49,6 +65,21 @@ var (
 	ErrInvalidAccessLogData = errors.New("invalid access log data")
 	ErrInvalidDirection     = errors.New("invalid direction")
 	ErrInvalidConfidence    = errors.New("invalid recognition confidence")
+	ErrInvalidReasonCode    = errors.New("invalid reason code")
+	ErrUnknownGate          = errors.New("unknown gate")
+	ErrStaleFrame           = errors.New("frame is too old")
+	ErrOccupancyUnavailable = errors.New("occupancy tracking is unavailable")
+)
+
+// ML errors
+var (
+	ErrMLImageRejected = errors.New("image rejected by recognizer")
+)
+
+// Audit errors
+var (
+	ErrInvalidAuditLogData    = errors.New("invalid audit log data")
+	ErrInvalidAuditTargetType = errors.New("invalid audit target type")
 )
 
 // Authorization errors
@@ -57,6 +88,11 @@ var (
 	ErrForbidden    = errors.New("forbidden")
 	ErrTokenExpired = errors.New("token expired")
 	ErrInvalidToken = errors.New("invalid token")
+
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+
+	ErrInvalidResetToken        = errors.New("invalid or expired password reset token")
+	ErrPasswordResetUnavailable = errors.New("password reset is unavailable")
 )
 
 // Blacklist/Whitelist errors
@@ -67,6 +103,8 @@ var (
 	ErrWhitelistEntryNotFound      = errors.New("whitelist entry not found")
 	ErrWhitelistEntryAlreadyExists = errors.New("whitelist entry already exists")
 	ErrInvalidWhitelistData        = errors.New("invalid whitelist data")
+	ErrExpiryInPast                = errors.New("expires_at must be in the future")
+	ErrInvalidZone                 = errors.New("invalid zone")
 )
 
 // General errors
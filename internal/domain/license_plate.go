@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxPlateLength        = 20 // Длина колонки license_plate
+	minVehiclePlateLength = 5  // Короче не бывает номеров зарегистрированных автомобилей
+)
+
+// plateLookalikes - кириллические буквы, совпадающие по начертанию с латинскими
+// Номер хранится латиницей: ML сервис и операторы могут вводить любую раскладку,
+// а сравнение номеров идет по строке
+var plateLookalikes = strings.NewReplacer(
+	"А", "A", "В", "B", "Е", "E", "К", "K", "М", "M", "Н", "H",
+	"О", "O", "Р", "P", "С", "C", "Т", "T", "У", "Y", "Х", "X",
+)
+
+// NormalizeLicensePlate нормализует номер автомобиля: убирает пробелы, приводит к верхнему регистру
+// и заменяет кириллические буквы латинскими двойниками
+func NormalizeLicensePlate(plate string) string {
+	normalized := strings.ToUpper(strings.ReplaceAll(plate, " ", ""))
+	return plateLookalikes.Replace(normalized)
+}
+
+// listPlatePattern повторяет CHECK-ограничение номера в таблицах vehicles, whitelist и blacklist
+var listPlatePattern = regexp.MustCompile(`^[A-ZА-Я0-9]+$`)
+
+// IsValidListPlate проверяет, что нормализованный номер пройдет ограничения таблиц списков
+// (только буквы и цифры, не длиннее 20 символов)
+func IsValidListPlate(plate string) bool {
+	return utf8.RuneCountInString(plate) <= maxPlateLength && listPlatePattern.MatchString(plate)
+}
+
+// PlateValidator проверяет формат нормализованного номера одной страны
+type PlateValidator func(plate string) bool
+
+// plateValidators - поддерживаемые форматы номеров (ISO 3166-1 alpha-2)
+var plateValidators = map[string]PlateValidator{
+	"RU": isRussianPlate,
+}
+
+// russianPlatePattern - номера РФ по ГОСТ Р 50577 после нормализации (12 букв, общих с латиницей):
+// легковые А123ВС777, такси АВ12377, прицепы АВ123477, мотоциклы 1234АВ77, полиция А123477, транзитные АВ123С77
+var russianPlatePattern = regexp.MustCompile(
+	`^(?:[ABEKMHOPCTYX]\d{3}[ABEKMHOPCTYX]{2}` +
+		`|[ABEKMHOPCTYX]{2}\d{3,4}` +
+		`|\d{4}[ABEKMHOPCTYX]{2}` +
+		`|[ABEKMHOPCTYX]\d{4}` +
+		`|[ABEKMHOPCTYX]{2}\d{3}[ABEKMHOPCTYX])` +
+		`\d{2,3}$`,
+)
+
+func isRussianPlate(plate string) bool {
+	return russianPlatePattern.MatchString(plate)
+}
+
+// IsPlateCountrySupported проверяет, известен ли формат номеров страны
+func IsPlateCountrySupported(country string) bool {
+	_, ok := plateValidators[strings.ToUpper(country)]
+	return ok
+}
+
+// ValidateLicensePlate проверяет нормализованный номер: ограничения таблиц и формат страны country
+// Пустая country отключает проверку формата (например, для площадок с иностранными номерами)
+func ValidateLicensePlate(plate, country string) error {
+	if !IsValidListPlate(plate) {
+		return ErrInvalidLicensePlate
+	}
+	if country == "" {
+		return nil
+	}
+
+	validate, ok := plateValidators[strings.ToUpper(country)]
+	if !ok || !validate(plate) {
+		return ErrInvalidLicensePlate
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeLicensePlate(t *testing.T) {
+	tests := []struct {
+		name     string
+		plate    string
+		expected string
+	}{
+		{name: "латиница", plate: "A123BC777", expected: "A123BC777"},
+		{name: "кириллица", plate: "А123ВС777", expected: "A123BC777"},
+		{name: "смешанная раскладка", plate: "А123BС777", expected: "A123BC777"},
+		{name: "нижний регистр и пробелы", plate: " а123 вс 777 ", expected: "A123BC777"},
+		{name: "все буквы-двойники", plate: "авекмнорстух", expected: "ABEKMHOPCTYX"},
+		{name: "буквы без двойника не меняются", plate: "Д123ЖЗ77", expected: "Д123ЖЗ77"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeLicensePlate(tt.plate))
+		})
+	}
+
+	// Распознанный и сохраненный номер совпадают независимо от раскладки
+	assert.Equal(t, NormalizeLicensePlate("А001АА777"), NormalizeLicensePlate("a001aa777"))
+}
+
+func TestValidateLicensePlate(t *testing.T) {
+	tests := []struct {
+		name    string
+		plate   string
+		country string
+		valid   bool
+	}{
+		{name: "RU: легковой, регион из трех цифр", plate: "A123BC777", country: "RU", valid: true},
+		{name: "RU: легковой, регион из двух цифр", plate: "X999XX99", country: "RU", valid: true},
+		{name: "RU: такси", plate: "AB12377", country: "RU", valid: true},
+		{name: "RU: прицеп", plate: "AB123477", country: "RU", valid: true},
+		{name: "RU: мотоцикл", plate: "1234AB77", country: "RU", valid: true},
+		{name: "RU: полиция", plate: "A123477", country: "RU", valid: true},
+		{name: "RU: транзитный", plate: "AB123C77", country: "RU", valid: true},
+		{name: "RU: код страны в нижнем регистре", plate: "A123BC777", country: "ru", valid: true},
+		{name: "RU: буква без латинского двойника", plate: "B456CD777", country: "RU", valid: false},
+		{name: "RU: кириллица без двойника", plate: "Д123ЖЗ77", country: "RU", valid: false},
+		{name: "RU: без региона", plate: "A123BC", country: "RU", valid: false},
+		{name: "RU: регион из четырех цифр", plate: "A123BC7777", country: "RU", valid: false},
+		{name: "RU: лишняя буква", plate: "AA123BC777", country: "RU", valid: false},
+		{name: "без страны: любой буквенно-цифровой номер", plate: "B456CD777", country: "", valid: true},
+		{name: "без страны: недопустимые символы", plate: "C789-KM", country: "", valid: false},
+		{name: "без страны: длиннее колонки", plate: "ABCDEFGHIJ1234567890X", country: "", valid: false},
+		{name: "неизвестная страна", plate: "A123BC777", country: "XX", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLicensePlate(tt.plate, tt.country)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, ErrInvalidLicensePlate, err)
+			}
+		})
+	}
+}
+
+func TestVehicle_Validate_PlateCountry(t *testing.T) {
+	// Номер, введенный кириллицей, нормализуется до проверки формата
+	vehicle := &Vehicle{OwnerID: uuid.New(), LicensePlate: "а123вс 777"}
+	assert.NoError(t, vehicle.Validate("RU"))
+	assert.Equal(t, "A123BC777", vehicle.LicensePlate)
+
+	vehicle = &Vehicle{OwnerID: uuid.New(), LicensePlate: "B456CD777"}
+	assert.Equal(t, ErrInvalidLicensePlate, vehicle.Validate("RU"))
+	assert.NoError(t, vehicle.Validate(""))
+
+	vehicle = &Vehicle{OwnerID: uuid.New(), LicensePlate: "A12"}
+	assert.Equal(t, ErrInvalidLicensePlate, vehicle.Validate(""), "короче минимальной длины")
+}
+
+func TestListEntry_Validate_PlateCountry(t *testing.T) {
+	whitelistEntry := &WhitelistEntry{LicensePlate: "Е001КХ777", Reason: "Сотрудник", AddedBy: uuid.New()}
+	assert.NoError(t, whitelistEntry.Validate("RU"))
+	assert.Equal(t, "E001KX777", whitelistEntry.LicensePlate)
+
+	blacklistEntry := &BlacklistEntry{LicensePlate: "В456ДЕ777", Reason: "Угон", AddedBy: uuid.New()}
+	assert.Equal(t, ErrInvalidLicensePlate, blacklistEntry.Validate("RU"))
+}
+
+func TestIsPlateCountrySupported(t *testing.T) {
+	assert.True(t, IsPlateCountrySupported("RU"))
+	assert.True(t, IsPlateCountrySupported("ru"))
+	assert.False(t, IsPlateCountrySupported("XX"))
+}
@@ -32,19 +32,113 @@ type Pass struct {
 	CreatedBy    *uuid.UUID `json:"created_by,omitempty"`
 	UpdatedAt    time.Time  `json:"updated_at"`
 
+	// Последнее продление срока действия (см. Extend); nil - пропуск не продлевался
+	ExtendedAt *time.Time `json:"extended_at,omitempty"`
+	ExtendedBy *uuid.UUID `json:"extended_by,omitempty"`
+
+	// Расписание действия (например, только рабочие часы); пусто - без ограничений
+	// Окно задается временем суток "HH:MM" по местному времени сервера: начало включительно,
+	// конец - нет; начало позже конца - окно через полночь (22:00-06:00)
+	AllowedTimeStart *string        `json:"allowed_time_start,omitempty"`
+	AllowedTimeEnd   *string        `json:"allowed_time_end,omitempty"`
+	AllowedWeekdays  []time.Weekday `json:"allowed_weekdays,omitempty"` // 0 - воскресенье, 6 - суббота
+
+	// Ограничение числа проездов (1 - разовый пропуск); nil - без ограничения
+	// UsesCount увеличивается только вместе с записью разрешенного проезда
+	MaxUses   *int `json:"max_uses,omitempty"`
+	UsesCount int  `json:"uses_count"`
+
+	// Зона действия пропуска (см. zone.go); nil - пропуск действует на всех воротах
+	Zone *string `json:"zone,omitempty"`
+
+	// Гостевой пропуск, выданный жителем посетителю (см. CreateGuestPass): учитывается в суточном лимите
+	// жителя и действует для автомобиля гостя независимо от того, за кем этот автомобиль записан
+	IsGuest bool `json:"is_guest"`
+
 	// Связанные данные (не хранятся в БД, заполняются при необходимости)
 	User     *User      `json:"user,omitempty"`
 	Vehicles []*Vehicle `json:"vehicles,omitempty"` // Автомобили, связанные с пропуском
 }
 
+// PassFilter - условия выборки пропусков; пустые поля не ограничивают выборку
+// ValidFrom/ValidTo отбирают пропуска, действующие хотя бы часть периода [ValidFrom, ValidTo)
+type PassFilter struct {
+	UserID    *uuid.UUID
+	PassType  PassType
+	IsActive  *bool
+	ValidFrom *time.Time
+	ValidTo   *time.Time
+}
+
+// timeOfDayLayout - формат времени суток в расписании пропуска
+const timeOfDayLayout = "15:04"
+
 // IsValid проверяет, действителен ли пропуск в данный момент времени
 func (p *Pass) IsValid() bool {
+	return p.IsValidAt(time.Now())
+}
+
+// IsValidAt проверяет, действителен ли пропуск в момент now (включая расписание и лимит проездов)
+func (p *Pass) IsValidAt(now time.Time) bool {
+	return p.isActiveAt(now) && !p.usageLimitReached() && p.IsWithinScheduleAt(now)
+}
+
+// IsUsageExhausted сообщает, что пропуск действует, но все разрешенные проезды использованы
+func (p *Pass) IsUsageExhausted(now time.Time) bool {
+	return p.isActiveAt(now) && p.usageLimitReached()
+}
+
+// usageLimitReached проверяет счетчик проездов по лимиту
+func (p *Pass) usageLimitReached() bool {
+	return p.MaxUses != nil && p.UsesCount >= *p.MaxUses
+}
+
+// IsOutsideSchedule сообщает, что пропуск действует, но сейчас вне разрешенных часов или дней
+// Позволяет отличить отказ по расписанию от истекшего пропуска
+func (p *Pass) IsOutsideSchedule(now time.Time) bool {
+	return p.isActiveAt(now) && !p.IsWithinScheduleAt(now)
+}
+
+// IsWithinScheduleAt проверяет момент now по разрешенным дням недели и окну времени суток
+// Для окна через полночь день недели берется по моменту проезда
+func (p *Pass) IsWithinScheduleAt(now time.Time) bool {
+	if len(p.AllowedWeekdays) > 0 {
+		allowed := false
+		for _, day := range p.AllowedWeekdays {
+			if day == now.Weekday() {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+
+	if p.AllowedTimeStart == nil || p.AllowedTimeEnd == nil {
+		return true
+	}
+
+	start, errStart := parseTimeOfDay(*p.AllowedTimeStart)
+	end, errEnd := parseTimeOfDay(*p.AllowedTimeEnd)
+	if errStart != nil || errEnd != nil {
+		return false // Некорректное расписание не должно открывать доступ
+	}
+
+	current := time.Duration(now.Hour())*time.Hour + time.Duration(now.Minute())*time.Minute +
+		time.Duration(now.Second())*time.Second
+	if start < end {
+		return current >= start && current < end
+	}
+	return current >= start || current < end
+}
+
+// isActiveAt проверяет активность и срок действия пропуска без учета расписания
+func (p *Pass) isActiveAt(now time.Time) bool {
 	if !p.IsActive {
 		return false
 	}
 
-	now := time.Now()
-
 	// Проверяем, что пропуск уже вступил в силу
 	if now.Before(p.ValidFrom) {
 		return false
@@ -68,6 +162,13 @@ func (p *Pass) IsExpired() bool {
 	return time.Now().After(*p.ValidUntil)
 }
 
+// Overlaps сообщает, что сроки действия пропусков пересекаются
+// Пропуск без ValidUntil (постоянный) действует бессрочно; конец срока не включается
+func (p *Pass) Overlaps(other *Pass) bool {
+	return (p.ValidUntil == nil || p.ValidUntil.After(other.ValidFrom)) &&
+		(other.ValidUntil == nil || other.ValidUntil.After(p.ValidFrom))
+}
+
 // Revoke отзывает пропуск
 func (p *Pass) Revoke(revokedBy uuid.UUID, reason string) {
 	now := time.Now()
@@ -78,6 +179,28 @@ func (p *Pass) Revoke(revokedBy uuid.UUID, reason string) {
 	p.UpdatedAt = now
 }
 
+// Extend продлевает временный пропуск до validUntil от имени extendedBy
+// Новый срок должен быть позже текущего и позже now; пропуск, деактивированный
+// по истечении срока, снова становится активным. Отозванный пропуск не продлевается
+func (p *Pass) Extend(validUntil time.Time, extendedBy uuid.UUID, now time.Time) error {
+	if p.RevokedAt != nil {
+		return ErrPassAlreadyRevoked
+	}
+	if p.PassType != PassTypeTemporary {
+		return ErrPassNotExtendable
+	}
+	if !validUntil.After(now) || (p.ValidUntil != nil && !validUntil.After(*p.ValidUntil)) {
+		return ErrInvalidDateRange
+	}
+
+	p.ValidUntil = &validUntil
+	p.IsActive = true
+	p.ExtendedAt = &now
+	p.ExtendedBy = &extendedBy
+	p.UpdatedAt = now
+	return nil
+}
+
 // Validate проверяет корректность данных пропуска
 func (p *Pass) Validate() error {
 	if p.UserID == uuid.Nil {
@@ -93,10 +216,60 @@ func (p *Pass) Validate() error {
 		if p.ValidUntil == nil {
 			return ErrInvalidPassData
 		}
-		if p.ValidUntil.Before(p.ValidFrom) {
+		if !p.ValidUntil.After(p.ValidFrom) {
 			return ErrInvalidDateRange
 		}
 	}
 
+	if p.MaxUses != nil && *p.MaxUses < 1 {
+		return ErrInvalidPassData
+	}
+
+	zone, err := NormalizeZone(p.Zone)
+	if err != nil {
+		return err
+	}
+	p.Zone = zone
+
+	return p.validateSchedule()
+}
+
+// validateSchedule проверяет расписание: окно задается обеими границами "HH:MM",
+// границы различны, дни недели в диапазоне 0-6 без повторов
+func (p *Pass) validateSchedule() error {
+	if (p.AllowedTimeStart == nil) != (p.AllowedTimeEnd == nil) {
+		return ErrInvalidPassSchedule
+	}
+	if p.AllowedTimeStart != nil {
+		start, err := parseTimeOfDay(*p.AllowedTimeStart)
+		if err != nil {
+			return ErrInvalidPassSchedule
+		}
+		end, err := parseTimeOfDay(*p.AllowedTimeEnd)
+		if err != nil {
+			return ErrInvalidPassSchedule
+		}
+		if start == end {
+			return ErrInvalidPassSchedule
+		}
+	}
+
+	seen := make(map[time.Weekday]bool, len(p.AllowedWeekdays))
+	for _, day := range p.AllowedWeekdays {
+		if day < time.Sunday || day > time.Saturday || seen[day] {
+			return ErrInvalidPassSchedule
+		}
+		seen[day] = true
+	}
+
 	return nil
 }
+
+// parseTimeOfDay переводит "HH:MM" в смещение от начала суток
+func parseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse(timeOfDayLayout, value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
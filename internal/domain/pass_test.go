@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestPass_IsWithinScheduleAt(t *testing.T) {
+	// 2024-01-15 - понедельник
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 15, hour, minute, 0, 0, time.Local)
+	}
+
+	businessHours := &Pass{AllowedTimeStart: strPtr("09:00"), AllowedTimeEnd: strPtr("18:00")}
+	overnight := &Pass{AllowedTimeStart: strPtr("22:00"), AllowedTimeEnd: strPtr("06:00")}
+	weekdaysOnly := &Pass{AllowedWeekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}}
+
+	tests := []struct {
+		name     string
+		pass     *Pass
+		now      time.Time
+		expected bool
+	}{
+		{name: "без расписания", pass: &Pass{}, now: at(3, 0), expected: true},
+		{name: "начало окна включительно", pass: businessHours, now: at(9, 0), expected: true},
+		{name: "до начала окна", pass: businessHours, now: at(8, 59), expected: false},
+		{name: "последняя минута окна", pass: businessHours, now: at(17, 59), expected: true},
+		{name: "конец окна не включительно", pass: businessHours, now: at(18, 0), expected: false},
+		{name: "окно через полночь: вечер", pass: overnight, now: at(23, 30), expected: true},
+		{name: "окно через полночь: утро", pass: overnight, now: at(5, 59), expected: true},
+		{name: "окно через полночь: день", pass: overnight, now: at(12, 0), expected: false},
+		{name: "разрешенный день недели", pass: weekdaysOnly, now: at(12, 0), expected: true},
+		{name: "выходной день", pass: weekdaysOnly, now: at(12, 0).AddDate(0, 0, 5), expected: false},
+		{
+			name: "окно и дни недели вместе",
+			pass: &Pass{
+				AllowedTimeStart: strPtr("09:00"),
+				AllowedTimeEnd:   strPtr("18:00"),
+				AllowedWeekdays:  []time.Weekday{time.Saturday},
+			},
+			now:      at(10, 0),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.pass.IsWithinScheduleAt(tt.now))
+		})
+	}
+}
+
+func TestPass_IsOutsideSchedule(t *testing.T) {
+	now := time.Date(2024, 1, 15, 20, 0, 0, 0, time.Local)
+	past := now.Add(-time.Hour)
+
+	active := &Pass{
+		PassType:         PassTypePermanent,
+		ValidFrom:        now.Add(-24 * time.Hour),
+		IsActive:         true,
+		AllowedTimeStart: strPtr("09:00"),
+		AllowedTimeEnd:   strPtr("18:00"),
+	}
+	assert.False(t, active.IsValidAt(now))
+	assert.True(t, active.IsOutsideSchedule(now))
+
+	// Истекший пропуск - это истечение, а не расписание
+	expired := *active
+	expired.PassType = PassTypeTemporary
+	expired.ValidUntil = &past
+	assert.False(t, expired.IsOutsideSchedule(now))
+}
+
+func TestPass_Validate_Schedule(t *testing.T) {
+	tests := []struct {
+		name        string
+		start, end  *string
+		weekdays    []time.Weekday
+		expectedErr error
+	}{
+		{name: "без расписания"},
+		{name: "рабочие часы", start: strPtr("09:00"), end: strPtr("18:00")},
+		{name: "окно через полночь", start: strPtr("22:00"), end: strPtr("06:00")},
+		{name: "только начало", start: strPtr("09:00"), expectedErr: ErrInvalidPassSchedule},
+		{name: "неверный формат", start: strPtr("9am"), end: strPtr("18:00"), expectedErr: ErrInvalidPassSchedule},
+		{name: "пустое окно", start: strPtr("09:00"), end: strPtr("09:00"), expectedErr: ErrInvalidPassSchedule},
+		{name: "дни недели", weekdays: []time.Weekday{time.Sunday, time.Saturday}},
+		{name: "день вне диапазона", weekdays: []time.Weekday{7}, expectedErr: ErrInvalidPassSchedule},
+		{name: "повтор дня", weekdays: []time.Weekday{time.Monday, time.Monday}, expectedErr: ErrInvalidPassSchedule},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pass := &Pass{
+				UserID:           uuid.New(),
+				PassType:         PassTypePermanent,
+				ValidFrom:        time.Now(),
+				AllowedTimeStart: tt.start,
+				AllowedTimeEnd:   tt.end,
+				AllowedWeekdays:  tt.weekdays,
+			}
+			assert.Equal(t, tt.expectedErr, pass.Validate())
+		})
+	}
+}
+
+func TestPass_Overlaps(t *testing.T) {
+	day := func(n int) time.Time { return time.Date(2030, 1, n, 0, 0, 0, 0, time.UTC) }
+	temporary := func(from, until time.Time) *Pass {
+		return &Pass{PassType: PassTypeTemporary, ValidFrom: from, ValidUntil: &until}
+	}
+	permanent := func(from time.Time) *Pass {
+		return &Pass{PassType: PassTypePermanent, ValidFrom: from}
+	}
+
+	tests := []struct {
+		name     string
+		a, b     *Pass
+		expected bool
+	}{
+		{name: "частичное пересечение", a: temporary(day(1), day(10)), b: temporary(day(5), day(15)), expected: true},
+		{name: "вложенный срок", a: temporary(day(1), day(20)), b: temporary(day(5), day(10)), expected: true},
+		{name: "смежные сроки", a: temporary(day(1), day(10)), b: temporary(day(10), day(20)), expected: false},
+		{name: "разнесенные сроки", a: temporary(day(1), day(5)), b: temporary(day(10), day(20)), expected: false},
+		{name: "постоянный после временного", a: temporary(day(1), day(5)), b: permanent(day(10)), expected: false},
+		{name: "постоянный до конца временного", a: temporary(day(1), day(15)), b: permanent(day(10)), expected: true},
+		{name: "два постоянных", a: permanent(day(1)), b: permanent(day(20)), expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.a.Overlaps(tt.b))
+			assert.Equal(t, tt.expected, tt.b.Overlaps(tt.a), "пересечение симметрично")
+		})
+	}
+}
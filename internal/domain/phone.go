@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"strings"
+)
+
+// phoneRegion описывает правила перевода локального номера в E.164
+type phoneRegion struct {
+	countryCode string // Код страны без "+"
+	trunkPrefix string // Префикс междугородней связи, который отбрасывается ("8" в РФ, "0" в Европе)
+	localDigits int    // Длина национального номера без префикса
+}
+
+// phoneRegions - поддерживаемые регионы по умолчанию (ISO 3166-1 alpha-2)
+var phoneRegions = map[string]phoneRegion{
+	"RU": {countryCode: "7", trunkPrefix: "8", localDigits: 10},
+	"KZ": {countryCode: "7", trunkPrefix: "8", localDigits: 10},
+	"BY": {countryCode: "375", trunkPrefix: "80", localDigits: 9},
+	"UA": {countryCode: "380", trunkPrefix: "0", localDigits: 9},
+	"DE": {countryCode: "49", trunkPrefix: "0"},
+	"GB": {countryCode: "44", trunkPrefix: "0", localDigits: 10},
+	"US": {countryCode: "1", trunkPrefix: "1", localDigits: 10},
+}
+
+// IsPhoneRegionSupported проверяет, известен ли регион для нормализации номеров
+func IsPhoneRegionSupported(region string) bool {
+	_, ok := phoneRegions[strings.ToUpper(region)]
+	return ok
+}
+
+// IsE164 проверяет, что номер записан в формате E.164: "+" и от 8 до 15 цифр, первая не 0
+func IsE164(phone string) bool {
+	if len(phone) < 9 || len(phone) > 16 || phone[0] != '+' || phone[1] == '0' {
+		return false
+	}
+	for _, r := range phone[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizePhone приводит номер к E.164
+// Допускаются пробелы, дефисы, точки и скобки; международный номер - с "+" или "00",
+// номер без кода страны дополняется кодом региона defaultRegion (с отбрасыванием префикса)
+func NormalizePhone(phone, defaultRegion string) (string, error) {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(phone) {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", ErrInvalidPhone
+		}
+	}
+	digits := b.String()
+
+	switch {
+	case strings.HasPrefix(digits, "+"):
+		// уже международный формат
+	case strings.HasPrefix(digits, "00"):
+		digits = "+" + strings.TrimPrefix(digits, "00")
+	default:
+		region, ok := phoneRegions[strings.ToUpper(defaultRegion)]
+		if !ok {
+			return "", ErrInvalidPhone
+		}
+		national := digits
+		if region.localDigits == 0 || len(national) != region.localDigits {
+			national = strings.TrimPrefix(national, region.trunkPrefix)
+		}
+		if region.localDigits > 0 && len(national) != region.localDigits {
+			return "", ErrInvalidPhone
+		}
+		digits = "+" + region.countryCode + national
+	}
+
+	if !IsE164(digits) {
+		return "", ErrInvalidPhone
+	}
+	return digits, nil
+}
@@ -15,6 +15,14 @@ const (
 	RoleGuard UserRole = "guard" // Охранник
 )
 
+// MinPasswordLength - минимальная длина пароля
+const MinPasswordLength = 8
+
+// IsValid проверяет, что роль - одна из известных
+func (r UserRole) IsValid() bool {
+	return r == RoleAdmin || r == RoleUser || r == RoleGuard
+}
+
 // User - центральная сущность системы
 // Пользователь владеет автомобилями и получает пропуска
 type User struct {
@@ -30,6 +38,14 @@ type User struct {
 	LastLoginAt  *time.Time `json:"last_login_at,omitempty"`
 }
 
+// UserDisableResult - итог отключения доступа пользователя (offboarding)
+type UserDisableResult struct {
+	UserID               uuid.UUID `json:"user_id"`
+	VehiclesDeactivated  int       `json:"vehicles_deactivated"`   // Деактивированные автомобили пользователя
+	PassesRevoked        int       `json:"passes_revoked"`         // Отозванные активные пропуска
+	RefreshTokensRevoked int       `json:"refresh_tokens_revoked"` // Отозванные refresh токены (сессии)
+}
+
 // IsAdmin проверяет, является ли пользователь администратором
 func (u *User) IsAdmin() bool {
 	return u.Role == RoleAdmin
@@ -53,8 +69,27 @@ func (u *User) Validate() error {
 	if u.FullName == "" {
 		return ErrInvalidUserData
 	}
-	if u.Role != RoleAdmin && u.Role != RoleUser && u.Role != RoleGuard {
+	if !u.Role.IsValid() {
 		return ErrInvalidRole
 	}
 	return nil
 }
+
+// NormalizePhone приводит телефон к E.164 с учетом региона по умолчанию
+// В строгом режиме некорректный номер - ошибка, иначе номер остается как был введен
+func (u *User) NormalizePhone(defaultRegion string, strict bool) error {
+	if u.Phone == "" {
+		return nil
+	}
+
+	normalized, err := NormalizePhone(u.Phone, defaultRegion)
+	if err != nil {
+		if strict {
+			return err
+		}
+		return nil
+	}
+
+	u.Phone = normalized
+	return nil
+}
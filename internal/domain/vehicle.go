@@ -1,8 +1,8 @@
 package domain
 
 import (
-	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
@@ -35,15 +35,38 @@ type Vehicle struct {
 	Owner *User `json:"owner,omitempty"`
 }
 
-// NormalizeLicensePlate нормализует номер автомобиля (убирает пробелы, приводит к верхнему регистру)
-func NormalizeLicensePlate(plate string) string {
-	// Убираем пробелы и приводим к верхнему регистру
-	normalized := strings.ToUpper(strings.ReplaceAll(plate, " ", ""))
-	return normalized
+// VehicleFilter - условия выборки автомобилей; пустые поля не ограничивают выборку
+type VehicleFilter struct {
+	OwnerID  *uuid.UUID
+	IsActive *bool
+}
+
+// IsEmpty сообщает, что фильтр не задает ни одного условия
+func (f VehicleFilter) IsEmpty() bool {
+	return f.OwnerID == nil && f.IsActive == nil
+}
+
+// VehicleMergeResult - итог объединения дубликатов автомобилей
+type VehicleMergeResult struct {
+	SourceID         uuid.UUID `json:"source_id"`
+	TargetID         uuid.UUID `json:"target_id"`
+	PassLinksMoved   int       `json:"pass_links_moved"`   // Перенесенные связи с пропусками
+	PassLinksDropped int       `json:"pass_links_dropped"` // Связи, уже существовавшие у target
+	PassLinksRevoked int       `json:"pass_links_revoked"` // Связи с обычными пропусками прежнего владельца (если владельцы разные)
+	AccessLogsMoved  int       `json:"access_logs_moved"`  // Перенесенные записи журнала проездов
+}
+
+// VehicleDeleteResult - итог удаления автомобиля
+type VehicleDeleteResult struct {
+	VehicleID          uuid.UUID `json:"vehicle_id"`
+	Hard               bool      `json:"hard"`                 // Строка удалена из БД (иначе - деактивирована)
+	PassLinksRemoved   int       `json:"pass_links_removed"`   // Удаленные связи с пропусками
+	AccessLogsDetached int       `json:"access_logs_detached"` // Записи журнала, у которых обнулен vehicle_id
 }
 
 // Validate проверяет корректность данных автомобиля
-func (v *Vehicle) Validate() error {
+// plateCountry - страна, по формату которой проверяется номер (пустая строка - без проверки формата)
+func (v *Vehicle) Validate(plateCountry string) error {
 	if v.OwnerID == uuid.Nil {
 		return ErrInvalidVehicleData
 	}
@@ -53,8 +76,8 @@ func (v *Vehicle) Validate() error {
 	// Нормализуем номер
 	v.LicensePlate = NormalizeLicensePlate(v.LicensePlate)
 
-	if len(v.LicensePlate) < 5 || len(v.LicensePlate) > 20 {
+	if utf8.RuneCountInString(v.LicensePlate) < minVehiclePlateLength {
 		return ErrInvalidLicensePlate
 	}
-	return nil
+	return ValidateLicensePlate(v.LicensePlate, plateCountry)
 }
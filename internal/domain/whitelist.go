@@ -16,6 +16,7 @@ type WhitelistEntry struct {
 	AddedAt      time.Time  `json:"added_at"`
 	ExpiresAt    *time.Time `json:"expires_at,omitempty"` // NULL = бессрочно
 	IsActive     bool       `json:"is_active"`
+	Zone         *string    `json:"zone,omitempty"` // NULL = действует на всех воротах
 }
 
 // IsExpired проверяет, истекла ли запись в белом списке
@@ -34,8 +35,22 @@ func (w *WhitelistEntry) IsValid() bool {
 	return !w.IsExpired()
 }
 
+// normalizeExpiry проверяет срок действия записи списка и приводит его к UTC
+// Уже истекшая запись бесполезна, поэтому прошедшее время - ошибка; nil - бессрочно
+func normalizeExpiry(expiresAt *time.Time) (*time.Time, error) {
+	if expiresAt == nil {
+		return nil, nil
+	}
+	if !expiresAt.After(time.Now()) {
+		return nil, ErrExpiryInPast
+	}
+	utc := expiresAt.UTC()
+	return &utc, nil
+}
+
 // Validate проверяет корректность данных
-func (w *WhitelistEntry) Validate() error {
+// plateCountry - страна, по формату которой проверяется номер (пустая строка - без проверки формата)
+func (w *WhitelistEntry) Validate(plateCountry string) error {
 	if w.LicensePlate == "" {
 		return ErrInvalidLicensePlate
 	}
@@ -46,8 +61,20 @@ func (w *WhitelistEntry) Validate() error {
 		return ErrInvalidWhitelistData
 	}
 
+	expiresAt, err := normalizeExpiry(w.ExpiresAt)
+	if err != nil {
+		return err
+	}
+	w.ExpiresAt = expiresAt
+
+	zone, err := NormalizeZone(w.Zone)
+	if err != nil {
+		return err
+	}
+	w.Zone = zone
+
 	// Нормализуем номер
 	w.LicensePlate = NormalizeLicensePlate(w.LicensePlate)
 
-	return nil
+	return ValidateLicensePlate(w.LicensePlate, plateCountry)
 }
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Зона - группа ворот площадки с общими правилами доступа (например, "parking").
+// Ворота относятся к зонам через настройки, а записи белого и черного списков и пропуска
+// могут быть ограничены одной зоной. Запись без зоны действует на всех воротах
+
+// maxZoneLength - длина колонки zone в таблицах списков и пропусков
+const maxZoneLength = 64
+
+var zonePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// NormalizeZone проверяет имя зоны записи и убирает пробелы по краям
+// nil и пустая строка означают запись без зоны (действует везде) и возвращают nil
+func NormalizeZone(zone *string) (*string, error) {
+	if zone == nil {
+		return nil, nil
+	}
+
+	name := strings.TrimSpace(*zone)
+	if name == "" {
+		return nil, nil
+	}
+	if len(name) > maxZoneLength || !zonePattern.MatchString(name) {
+		return nil, ErrInvalidZone
+	}
+	return &name, nil
+}
+
+// ZoneApplies сообщает, действует ли запись с зоной scope на воротах зоны gateZone
+// Запись без зоны действует везде; запись с зоной - только на воротах этой зоны
+func ZoneApplies(scope *string, gateZone string) bool {
+	return scope == nil || *scope == gateZone
+}
@@ -0,0 +1,96 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/pkg/redis"
+)
+
+// AccessEventsChannel - канал Redis pub/sub с решениями о доступе всех экземпляров API
+const AccessEventsChannel = "gate:access_events"
+
+// subscriberBuffer - сколько событий ждут медленного подписчика; следующие отбрасываются
+const subscriberBuffer = 64
+
+// RedisBroker публикует решения о доступе в Redis pub/sub и раздает их подписчикам
+// Через Redis событие получают клиенты, подключенные к любому экземпляру API
+type RedisBroker struct {
+	client *redis.Client
+	logger logger.Logger
+}
+
+// NewRedisBroker создает брокер событий доступа поверх Redis
+func NewRedisBroker(client *redis.Client, logger logger.Logger) *RedisBroker {
+	return &RedisBroker{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Publish отправляет событие всем подписчикам
+// Ошибка только логируется: лента событий не должна влиять на проверку доступа
+func (b *RedisBroker) Publish(ctx context.Context, event *domain.AccessEvent) {
+	payload, err := json.Marshal(event)
+	if err == nil {
+		err = b.client.GetClient().Publish(ctx, AccessEventsChannel, payload).Err()
+	}
+	if err != nil {
+		logger.FromContextOr(ctx, b.logger).Warn("Failed to publish access event", map[string]interface{}{
+			"license_plate": event.LicensePlate,
+			"error":         err.Error(),
+		})
+	}
+}
+
+// Subscribe подписывается на события доступа
+// Канал закрывается после отмены ctx (например, при отключении клиента SSE)
+func (b *RedisBroker) Subscribe(ctx context.Context) (<-chan *domain.AccessEvent, error) {
+	pubsub := b.client.GetClient().Subscribe(ctx, AccessEventsChannel)
+
+	// Дожидаемся подтверждения подписки, чтобы не потерять события, опубликованные сразу после возврата
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, fmt.Errorf("failed to subscribe to access events: %w", err)
+	}
+
+	out := make(chan *domain.AccessEvent, subscriberBuffer)
+	go func() {
+		defer close(out)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				var event domain.AccessEvent
+				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+					b.logger.Warn("Skipped malformed access event", map[string]interface{}{
+						"error": err.Error(),
+					})
+					continue
+				}
+
+				// Медленный клиент не должен задерживать чтение из Redis
+				select {
+				case out <- &event:
+				default:
+					b.logger.Warn("Dropped access event for slow subscriber", map[string]interface{}{
+						"license_plate": event.LicensePlate,
+					})
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
@@ -0,0 +1,101 @@
+package events
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/pkg/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBroker(t *testing.T) (*RedisBroker, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	host, port, err := net.SplitHostPort(mr.Addr())
+	require.NoError(t, err)
+
+	client, err := redis.NewClient(redis.Config{Host: host, Port: port})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	return NewRedisBroker(client, logger.NewNoop()), mr
+}
+
+// receive читает одно событие или проваливает тест по таймауту
+func receive(t *testing.T, events <-chan *domain.AccessEvent) *domain.AccessEvent {
+	t.Helper()
+
+	select {
+	case event, ok := <-events:
+		require.True(t, ok, "канал событий закрыт")
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("событие не получено")
+		return nil
+	}
+}
+
+func TestRedisBroker_PublishSubscribe(t *testing.T) {
+	broker, _ := newTestBroker(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Два подписчика - как два экземпляра API
+	first, err := broker.Subscribe(ctx)
+	require.NoError(t, err)
+	second, err := broker.Subscribe(ctx)
+	require.NoError(t, err)
+
+	timestamp := time.Date(2026, 10, 15, 8, 30, 0, 0, time.UTC)
+	broker.Publish(context.Background(), &domain.AccessEvent{
+		LicensePlate:  "A123BC777",
+		AccessGranted: true,
+		Reason:        "Valid pass",
+		ReasonCode:    "PASS_VALID",
+		GateID:        "north-1",
+		Direction:     domain.DirectionIn,
+		Timestamp:     timestamp,
+	})
+
+	for _, events := range []<-chan *domain.AccessEvent{first, second} {
+		event := receive(t, events)
+		assert.Equal(t, "A123BC777", event.LicensePlate)
+		assert.True(t, event.AccessGranted)
+		assert.Equal(t, "north-1", event.GateID)
+		assert.Equal(t, domain.DirectionIn, event.Direction)
+		assert.True(t, timestamp.Equal(event.Timestamp))
+	}
+}
+
+func TestRedisBroker_Subscribe_ClosesOnCancel(t *testing.T) {
+	broker, _ := newTestBroker(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	events, err := broker.Subscribe(ctx)
+	require.NoError(t, err)
+
+	cancel()
+
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok)
+	case <-time.After(2 * time.Second):
+		t.Fatal("канал не закрыт после отмены контекста")
+	}
+}
+
+func TestRedisBroker_Subscribe_RedisUnavailable(t *testing.T) {
+	broker, mr := newTestBroker(t)
+	mr.Close()
+
+	_, err := broker.Subscribe(context.Background())
+
+	assert.Error(t, err)
+}
@@ -4,10 +4,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
+	"net"
 	"net/http"
+	"syscall"
 	"time"
+
+	"github.com/frontandrew/gate/internal/domain"
 )
 
 // RecognitionResult содержит результат распознавания номера
@@ -17,9 +23,17 @@ type RecognitionResult struct {
 	Confidence     float64      `json:"confidence"`
 	BoundingBox    *BoundingBox `json:"bounding_box,omitempty"`
 	ProcessingTime float64      `json:"processing_time_ms"`
+	ModelVersion   string       `json:"model_version,omitempty"`
 	Error          string       `json:"error,omitempty"`
 }
 
+// HealthStatus содержит ответ health check ML сервиса
+type HealthStatus struct {
+	Status       string `json:"status"`
+	Version      string `json:"version,omitempty"`       // Версия ML сервиса
+	ModelVersion string `json:"model_version,omitempty"` // Версия модели распознавания
+}
+
 // BoundingBox содержит координаты распознанного номера на изображении
 type BoundingBox struct {
 	X      int `json:"x"`
@@ -39,8 +53,14 @@ type Client interface {
 	// RecognizePlate распознает номер автомобиля на изображении
 	RecognizePlate(ctx context.Context, imageBase64 string, minConfidence float64) (*RecognitionResult, error)
 
-	// Health проверяет доступность ML сервиса
-	Health(ctx context.Context) error
+	// Health проверяет доступность ML сервиса и возвращает его версию
+	Health(ctx context.Context) (*HealthStatus, error)
+}
+
+// RetryConfig содержит параметры повтора запросов распознавания
+type RetryConfig struct {
+	MaxRetries  int           // Повторов после первой попытки; 0 - без повторов
+	BaseBackoff time.Duration // Задержка перед первым повтором, далее удваивается
 }
 
 // httpClient - HTTP реализация ML клиента
@@ -48,13 +68,19 @@ type httpClient struct {
 	baseURL    string
 	httpClient *http.Client
 	timeout    time.Duration
+	retry      RetryConfig
 }
 
 // NewHTTPClient создает новый HTTP клиент для ML сервиса
-func NewHTTPClient(baseURL string, timeout time.Duration) Client {
+func NewHTTPClient(baseURL string, timeout time.Duration, retry RetryConfig) Client {
+	if retry.MaxRetries < 0 {
+		retry.MaxRetries = 0
+	}
+
 	return &httpClient{
 		baseURL: baseURL,
 		timeout: timeout,
+		retry:   retry,
 		httpClient: &http.Client{
 			Timeout: timeout,
 			Transport: &http.Transport{
@@ -79,47 +105,29 @@ func (c *httpClient) RecognizePlate(ctx context.Context, imageBase64 string, min
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	// Создаем HTTP запрос
-	url := fmt.Sprintf("%s/api/v1/recognize", c.baseURL)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
 	// Отправляем запрос с retry логикой
 	var result *RecognitionResult
-	var lastErr error
-
-	maxRetries := 3
-	for attempt := 0; attempt < maxRetries; attempt++ {
-		if attempt > 0 {
-			// Экспоненциальная задержка между попытками
-			backoff := time.Duration(attempt) * time.Second
-			select {
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(backoff):
-			}
-		}
-
-		result, lastErr = c.doRequest(req)
-		if lastErr == nil {
-			return result, nil
-		}
-
-		// Если это не временная ошибка, не повторяем
-		if !isRetryable(lastErr) {
-			break
-		}
+	err = withRetry(ctx, c.retry, isRetryable, func() error {
+		var err error
+		result, err = c.doRequest(ctx, jsonData)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
-
-	return nil, fmt.Errorf("recognition failed after %d attempts: %w", maxRetries, lastErr)
+	return result, nil
 }
 
 // doRequest выполняет HTTP запрос и обрабатывает ответ
-func (c *httpClient) doRequest(req *http.Request) (*RecognitionResult, error) {
+// Тело создается заново для каждой попытки: прочитанный body повторно не отправить
+func (c *httpClient) doRequest(ctx context.Context, jsonData []byte) (*RecognitionResult, error) {
+	url := fmt.Sprintf("%s/api/v1/recognize", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
@@ -132,9 +140,14 @@ func (c *httpClient) doRequest(req *http.Request) (*RecognitionResult, error) {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
+	// Изображение слишком большое или в неподдерживаемом формате - повтор не поможет
+	if resp.StatusCode == http.StatusRequestEntityTooLarge || resp.StatusCode == http.StatusUnsupportedMediaType {
+		return nil, fmt.Errorf("%w: ML service returned status %d: %s", domain.ErrMLImageRejected, resp.StatusCode, string(body))
+	}
+
 	// Проверяем статус код
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("ML service returned status %d: %s", resp.StatusCode, string(body))
+		return nil, &statusError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	// Парсим ответ
@@ -147,30 +160,104 @@ func (c *httpClient) doRequest(req *http.Request) (*RecognitionResult, error) {
 }
 
 // Health проверяет доступность ML сервиса
-func (c *httpClient) Health(ctx context.Context) error {
+func (c *httpClient) Health(ctx context.Context) (*HealthStatus, error) {
 	url := fmt.Sprintf("%s/health", c.baseURL)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create health check request: %w", err)
+		return nil, fmt.Errorf("failed to create health check request: %w", err)
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("health check failed: %w", err)
+		return nil, fmt.Errorf("health check failed: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	body, _ := io.ReadAll(resp.Body)
+
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("health check returned status %d: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("health check returned status %d: %s", resp.StatusCode, string(body))
 	}
 
-	return nil
+	// Старые версии ML сервиса могут не возвращать JSON - это не ошибка доступности
+	status := &HealthStatus{Status: "ok"}
+	_ = json.Unmarshal(body, status)
+
+	return status, nil
+}
+
+// withRetry выполняет attempt, повторяя его при временных ошибках
+// Отмененный вызывающим запрос и ошибки, для которых retryable возвращает false, не повторяются
+func withRetry(ctx context.Context, retry RetryConfig, retryable func(error) bool, attempt func() error) error {
+	var lastErr error
+
+	attempts := retry.MaxRetries + 1
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(retry.backoff(i)):
+			}
+		}
+
+		lastErr = attempt()
+		if lastErr == nil {
+			return nil
+		}
+
+		if ctx.Err() != nil || !retryable(lastErr) {
+			return lastErr
+		}
+	}
+
+	return fmt.Errorf("recognition failed after %d attempts: %w", attempts, lastErr)
+}
+
+// backoff возвращает задержку перед повтором: экспоненциальный рост от BaseBackoff
+// со случайным разбросом в верхней половине интервала, чтобы клиенты не повторяли синхронно
+func (r RetryConfig) backoff(attempt int) time.Duration {
+	if r.BaseBackoff <= 0 {
+		return 0
+	}
+
+	delay := r.BaseBackoff << (attempt - 1)
+	half := int64(delay / 2)
+	return time.Duration(half + rand.Int64N(half+1))
+}
+
+// statusError - неуспешный HTTP статус ответа ML сервиса
+type statusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("ML service returned status %d: %s", e.StatusCode, e.Body)
 }
 
 // isRetryable определяет, можно ли повторить запрос при данной ошибке
+// Повторяются только временные сбои: сетевые ошибки, таймауты и 5xx.
+// 4xx и некорректный ответ при повторе не изменятся
 func isRetryable(err error) bool {
-	// Можно добавить более сложную логику определения
-	// временных ошибок (network timeout, connection refused и т.д.)
-	return true
+	// Отклоненное изображение будет отклонено и при повторе
+	if errors.Is(err, domain.ErrMLImageRejected) {
+		return false
+	}
+
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	// Таймауты, отказ в соединении, обрыв соединения при чтении ответа
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET)
 }
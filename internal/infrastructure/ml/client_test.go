@@ -0,0 +1,133 @@
+package ml
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPClient_RecognizePlate_ImageRejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+	}{
+		{name: "слишком большое изображение", status: http.StatusRequestEntityTooLarge},
+		{name: "неподдерживаемый формат", status: http.StatusUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			client := NewHTTPClient(server.URL, 5*time.Second, RetryConfig{MaxRetries: 2, BaseBackoff: time.Second})
+
+			start := time.Now()
+			result, err := client.RecognizePlate(context.Background(), "image", 0.7)
+
+			require.Error(t, err)
+			assert.ErrorIs(t, err, domain.ErrMLImageRejected)
+			assert.Nil(t, result)
+			assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "отклоненное изображение не должно повторяться")
+			assert.Less(t, time.Since(start), time.Second, "без задержек между попытками")
+		})
+	}
+}
+
+func TestHTTPClient_RecognizePlate_Retry(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		body          string
+		succeedOn     int32 // Номер вызова, на котором сервис отвечает 200 (0 - никогда)
+		expectedCalls int32
+		expectError   bool
+	}{
+		{name: "400 не повторяется", status: http.StatusBadRequest, expectedCalls: 1, expectError: true},
+		{name: "некорректный JSON не повторяется", status: http.StatusOK, body: "not json", expectedCalls: 1, expectError: true},
+		{name: "503 повторяется до успеха", status: http.StatusServiceUnavailable, succeedOn: 3, expectedCalls: 3},
+		{name: "503 повторяется не больше MaxRetries", status: http.StatusServiceUnavailable, expectedCalls: 3, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				call := atomic.AddInt32(&calls, 1)
+				if call == tt.succeedOn {
+					_, _ = w.Write([]byte(`{"success":true,"license_plate":"A123BC777","confidence":0.95}`))
+					return
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewHTTPClient(server.URL, 5*time.Second, RetryConfig{MaxRetries: 2, BaseBackoff: time.Millisecond})
+
+			result, err := client.RecognizePlate(context.Background(), "image", 0.7)
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "A123BC777", result.LicensePlate)
+			}
+			assert.Equal(t, tt.expectedCalls, atomic.LoadInt32(&calls))
+		})
+	}
+}
+
+func TestRetryConfig_Backoff(t *testing.T) {
+	retry := RetryConfig{MaxRetries: 3, BaseBackoff: 100 * time.Millisecond}
+
+	for attempt, limit := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond} {
+		for i := 0; i < 20; i++ {
+			delay := retry.backoff(attempt)
+			assert.GreaterOrEqual(t, delay, limit/2, "attempt %d", attempt)
+			assert.LessOrEqual(t, delay, limit, "attempt %d", attempt)
+		}
+	}
+}
+
+func TestHTTPClient_RecognizePlate_RetrySendsFullBody(t *testing.T) {
+	image := strings.Repeat("iVBORw0KGgoAAAANSUhEUgAA", 1000)
+
+	var calls int32
+	var retryImage string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req recognitionRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		retryImage = req.ImageBase64
+		_, _ = w.Write([]byte(`{"success":true,"license_plate":"A123BC777","confidence":0.95}`))
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(server.URL, 5*time.Second, RetryConfig{MaxRetries: 1, BaseBackoff: time.Millisecond})
+
+	result, err := client.RecognizePlate(context.Background(), image, 0.7)
+
+	require.NoError(t, err)
+	assert.Equal(t, "A123BC777", result.LicensePlate)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.Equal(t, image, retryImage, "повторная попытка должна отправить изображение целиком")
+}
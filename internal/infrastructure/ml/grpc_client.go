@@ -0,0 +1,124 @@
+package ml
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/infrastructure/ml/mlpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// grpcClient - gRPC реализация ML клиента
+// Изображение передается сырыми байтами, что легче base64 в JSON при высокой нагрузке
+type grpcClient struct {
+	client  mlpb.PlateRecognitionClient
+	timeout time.Duration
+	retry   RetryConfig
+}
+
+// DialGRPC создает соединение с gRPC сервером ML сервиса
+// Соединение устанавливается лениво при первом вызове; закрывает его вызывающий
+func DialGRPC(address string) (*grpc.ClientConn, error) {
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ML gRPC connection: %w", err)
+	}
+	return conn, nil
+}
+
+// NewGRPCClient создает новый gRPC клиент для ML сервиса
+// timeout ограничивает каждую попытку отдельно
+func NewGRPCClient(conn grpc.ClientConnInterface, timeout time.Duration, retry RetryConfig) Client {
+	if retry.MaxRetries < 0 {
+		retry.MaxRetries = 0
+	}
+
+	return &grpcClient{
+		client:  mlpb.NewPlateRecognitionClient(conn),
+		timeout: timeout,
+		retry:   retry,
+	}
+}
+
+// RecognizePlate отправляет запрос на распознавание номера
+func (c *grpcClient) RecognizePlate(ctx context.Context, imageBase64 string, minConfidence float64) (*RecognitionResult, error) {
+	image, err := base64.StdEncoding.DecodeString(imageBase64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid base64 image: %v", domain.ErrMLImageRejected, err)
+	}
+
+	req := &mlpb.RecognizePlateRequest{
+		Image:         image,
+		MinConfidence: minConfidence,
+	}
+
+	var resp *mlpb.RecognizePlateResponse
+	err = withRetry(ctx, c.retry, isRetryableGRPC, func() error {
+		attemptCtx, cancel := context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+
+		var err error
+		resp, err = c.client.RecognizePlate(attemptCtx, req)
+		return err
+	})
+	if err != nil {
+		// Изображение слишком большое или в неподдерживаемом формате
+		if code := status.Code(err); code == codes.InvalidArgument || code == codes.ResourceExhausted {
+			return nil, fmt.Errorf("%w: %v", domain.ErrMLImageRejected, err)
+		}
+		return nil, fmt.Errorf("ML gRPC request failed: %w", err)
+	}
+
+	result := &RecognitionResult{
+		Success:        resp.GetSuccess(),
+		LicensePlate:   resp.GetLicensePlate(),
+		Confidence:     resp.GetConfidence(),
+		ProcessingTime: resp.GetProcessingTimeMs(),
+		ModelVersion:   resp.GetModelVersion(),
+		Error:          resp.GetError(),
+	}
+	if box := resp.GetBoundingBox(); box != nil {
+		result.BoundingBox = &BoundingBox{
+			X:      int(box.GetX()),
+			Y:      int(box.GetY()),
+			Width:  int(box.GetWidth()),
+			Height: int(box.GetHeight()),
+		}
+	}
+
+	return result, nil
+}
+
+// Health проверяет доступность ML сервиса
+func (c *grpcClient) Health(ctx context.Context) (*HealthStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	resp, err := c.client.Health(ctx, &mlpb.HealthRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("health check failed: %w", err)
+	}
+
+	return &HealthStatus{
+		Status:       resp.GetStatus(),
+		Version:      resp.GetVersion(),
+		ModelVersion: resp.GetModelVersion(),
+	}, nil
+}
+
+// isRetryableGRPC определяет, можно ли повторить вызов при данной ошибке
+// Повторяются недоступность сервиса и таймаут попытки; ошибки запроса при повторе не изменятся
+func isRetryableGRPC(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,159 @@
+package ml
+
+import (
+	"context"
+	"encoding/base64"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/infrastructure/ml/mlpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeRecognitionServer - in-memory ML сервис; первые failures вызовов завершаются ошибкой failCode
+type fakeRecognitionServer struct {
+	mlpb.UnimplementedPlateRecognitionServer
+	failures  int32
+	failCode  codes.Code
+	calls     int32
+	lastImage []byte
+}
+
+func (s *fakeRecognitionServer) RecognizePlate(ctx context.Context, req *mlpb.RecognizePlateRequest) (*mlpb.RecognizePlateResponse, error) {
+	if atomic.AddInt32(&s.calls, 1) <= s.failures {
+		return nil, status.Error(s.failCode, "simulated failure")
+	}
+	s.lastImage = req.GetImage()
+	return &mlpb.RecognizePlateResponse{
+		Success:          true,
+		LicensePlate:     "A123BC777",
+		Confidence:       0.95,
+		BoundingBox:      &mlpb.BoundingBox{X: 10, Y: 20, Width: 120, Height: 40},
+		ProcessingTimeMs: 12.5,
+		ModelVersion:     "easyocr-1.7",
+	}, nil
+}
+
+func (s *fakeRecognitionServer) Health(ctx context.Context, req *mlpb.HealthRequest) (*mlpb.HealthResponse, error) {
+	return &mlpb.HealthResponse{Status: "ok", Version: "1.2.0", ModelVersion: "easyocr-1.7"}, nil
+}
+
+// newTestGRPCClient поднимает fake сервер на bufconn и возвращает подключенный к нему клиент
+func newTestGRPCClient(t *testing.T, server *fakeRecognitionServer) Client {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	mlpb.RegisterPlateRecognitionServer(grpcServer, server)
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return NewGRPCClient(conn, 5*time.Second, RetryConfig{MaxRetries: 2, BaseBackoff: time.Millisecond})
+}
+
+func TestGRPCClient_RecognizePlate(t *testing.T) {
+	server := &fakeRecognitionServer{}
+	client := newTestGRPCClient(t, server)
+
+	image := []byte("\x89PNG raw image bytes")
+	result, err := client.RecognizePlate(context.Background(), base64.StdEncoding.EncodeToString(image), 0.7)
+
+	require.NoError(t, err)
+	assert.Equal(t, image, server.lastImage, "сервер получает сырые байты изображения")
+	assert.True(t, result.Success)
+	assert.Equal(t, "A123BC777", result.LicensePlate)
+	assert.Equal(t, 0.95, result.Confidence)
+	assert.Equal(t, &BoundingBox{X: 10, Y: 20, Width: 120, Height: 40}, result.BoundingBox)
+	assert.Equal(t, 12.5, result.ProcessingTime)
+	assert.Equal(t, "easyocr-1.7", result.ModelVersion)
+}
+
+func TestGRPCClient_RecognizePlate_Errors(t *testing.T) {
+	image := base64.StdEncoding.EncodeToString([]byte("image"))
+
+	tests := []struct {
+		name          string
+		server        *fakeRecognitionServer
+		image         string
+		expectedCalls int32
+		expectError   bool
+		imageRejected bool
+	}{
+		{
+			name:          "Unavailable повторяется до успеха",
+			server:        &fakeRecognitionServer{failures: 2, failCode: codes.Unavailable},
+			image:         image,
+			expectedCalls: 3,
+		},
+		{
+			name:          "Unavailable повторяется не больше MaxRetries",
+			server:        &fakeRecognitionServer{failures: 10, failCode: codes.Unavailable},
+			image:         image,
+			expectedCalls: 3,
+			expectError:   true,
+		},
+		{
+			name:          "InvalidArgument не повторяется",
+			server:        &fakeRecognitionServer{failures: 10, failCode: codes.InvalidArgument},
+			image:         image,
+			expectedCalls: 1,
+			expectError:   true,
+			imageRejected: true,
+		},
+		{
+			name:          "невалидный base64 не отправляется",
+			server:        &fakeRecognitionServer{},
+			image:         "not base64!",
+			expectedCalls: 0,
+			expectError:   true,
+			imageRejected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestGRPCClient(t, tt.server)
+
+			result, err := client.RecognizePlate(context.Background(), tt.image, 0.7)
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "A123BC777", result.LicensePlate)
+			}
+			if tt.imageRejected {
+				assert.ErrorIs(t, err, domain.ErrMLImageRejected)
+			}
+			assert.Equal(t, tt.expectedCalls, atomic.LoadInt32(&tt.server.calls))
+		})
+	}
+}
+
+func TestGRPCClient_Health(t *testing.T) {
+	client := newTestGRPCClient(t, &fakeRecognitionServer{})
+
+	health, err := client.Health(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, &HealthStatus{Status: "ok", Version: "1.2.0", ModelVersion: "easyocr-1.7"}, health)
+}
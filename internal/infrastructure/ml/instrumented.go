@@ -0,0 +1,38 @@
+package ml
+
+import (
+	"context"
+	"time"
+
+	"github.com/frontandrew/gate/internal/pkg/metrics"
+)
+
+// instrumentedClient - декоратор ML клиента, учитывающий длительность и ошибки вызовов
+type instrumentedClient struct {
+	client  Client
+	metrics *metrics.Metrics
+}
+
+// NewInstrumentedClient оборачивает клиент метриками; работает с любым транспортом
+func NewInstrumentedClient(client Client, metrics *metrics.Metrics) Client {
+	return &instrumentedClient{
+		client:  client,
+		metrics: metrics,
+	}
+}
+
+// RecognizePlate распознает номер и учитывает вызов в метриках
+func (c *instrumentedClient) RecognizePlate(ctx context.Context, imageBase64 string, minConfidence float64) (*RecognitionResult, error) {
+	start := time.Now()
+	result, err := c.client.RecognizePlate(ctx, imageBase64, minConfidence)
+	c.metrics.ObserveMLRequest("recognize", time.Since(start), err)
+	return result, err
+}
+
+// Health проверяет доступность ML сервиса и учитывает вызов в метриках
+func (c *instrumentedClient) Health(ctx context.Context) (*HealthStatus, error) {
+	start := time.Now()
+	health, err := c.client.Health(ctx)
+	c.metrics.ObserveMLRequest("health", time.Since(start), err)
+	return health, err
+}
@@ -0,0 +1,62 @@
+package ml
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/frontandrew/gate/internal/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// stubClient возвращает заданную ошибку или успешный результат
+type stubClient struct {
+	err error
+}
+
+func (c *stubClient) RecognizePlate(ctx context.Context, imageBase64 string, minConfidence float64) (*RecognitionResult, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &RecognitionResult{Success: true, LicensePlate: "A123BC777"}, nil
+}
+
+func (c *stubClient) Health(ctx context.Context) (*HealthStatus, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &HealthStatus{Status: "ok"}, nil
+}
+
+func TestInstrumentedClient(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	appMetrics := metrics.New(registry)
+
+	healthy := NewInstrumentedClient(&stubClient{}, appMetrics)
+	failing := NewInstrumentedClient(&stubClient{err: errors.New("connection refused")}, appMetrics)
+
+	for i := 0; i < 2; i++ {
+		_, err := healthy.RecognizePlate(context.Background(), "image", 0.7)
+		require.NoError(t, err)
+	}
+	_, err := failing.RecognizePlate(context.Background(), "image", 0.7)
+	require.Error(t, err)
+	_, err = healthy.Health(context.Background())
+	require.NoError(t, err)
+
+	expected := `
+# HELP gate_ml_requests_total Вызовы ML сервиса по операции и результату
+# TYPE gate_ml_requests_total counter
+gate_ml_requests_total{operation="health",result="success"} 1
+gate_ml_requests_total{operation="recognize",result="error"} 1
+gate_ml_requests_total{operation="recognize",result="success"} 2
+`
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "gate_ml_requests_total"))
+
+	series, err := testutil.GatherAndCount(registry, "gate_ml_request_duration_seconds")
+	require.NoError(t, err)
+	require.Equal(t, 2, series)
+}
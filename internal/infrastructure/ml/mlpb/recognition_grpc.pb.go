@@ -0,0 +1,166 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: recognition.proto
+
+// gRPC API сервиса распознавания номеров (альтернатива HTTP+JSON)
+// Изображение передается сырыми байтами, без base64
+
+package mlpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PlateRecognition_RecognizePlate_FullMethodName = "/gate.ml.v1.PlateRecognition/RecognizePlate"
+	PlateRecognition_Health_FullMethodName         = "/gate.ml.v1.PlateRecognition/Health"
+)
+
+// PlateRecognitionClient is the client API for PlateRecognition service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PlateRecognitionClient interface {
+	// RecognizePlate распознает номер автомобиля на изображении
+	RecognizePlate(ctx context.Context, in *RecognizePlateRequest, opts ...grpc.CallOption) (*RecognizePlateResponse, error)
+	// Health возвращает состояние сервиса и версию модели
+	Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error)
+}
+
+type plateRecognitionClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPlateRecognitionClient(cc grpc.ClientConnInterface) PlateRecognitionClient {
+	return &plateRecognitionClient{cc}
+}
+
+func (c *plateRecognitionClient) RecognizePlate(ctx context.Context, in *RecognizePlateRequest, opts ...grpc.CallOption) (*RecognizePlateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecognizePlateResponse)
+	err := c.cc.Invoke(ctx, PlateRecognition_RecognizePlate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plateRecognitionClient) Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HealthResponse)
+	err := c.cc.Invoke(ctx, PlateRecognition_Health_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PlateRecognitionServer is the server API for PlateRecognition service.
+// All implementations must embed UnimplementedPlateRecognitionServer
+// for forward compatibility.
+type PlateRecognitionServer interface {
+	// RecognizePlate распознает номер автомобиля на изображении
+	RecognizePlate(context.Context, *RecognizePlateRequest) (*RecognizePlateResponse, error)
+	// Health возвращает состояние сервиса и версию модели
+	Health(context.Context, *HealthRequest) (*HealthResponse, error)
+	mustEmbedUnimplementedPlateRecognitionServer()
+}
+
+// UnimplementedPlateRecognitionServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPlateRecognitionServer struct{}
+
+func (UnimplementedPlateRecognitionServer) RecognizePlate(context.Context, *RecognizePlateRequest) (*RecognizePlateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecognizePlate not implemented")
+}
+func (UnimplementedPlateRecognitionServer) Health(context.Context, *HealthRequest) (*HealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Health not implemented")
+}
+func (UnimplementedPlateRecognitionServer) mustEmbedUnimplementedPlateRecognitionServer() {}
+func (UnimplementedPlateRecognitionServer) testEmbeddedByValue()                          {}
+
+// UnsafePlateRecognitionServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PlateRecognitionServer will
+// result in compilation errors.
+type UnsafePlateRecognitionServer interface {
+	mustEmbedUnimplementedPlateRecognitionServer()
+}
+
+func RegisterPlateRecognitionServer(s grpc.ServiceRegistrar, srv PlateRecognitionServer) {
+	// If the following call pancis, it indicates UnimplementedPlateRecognitionServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PlateRecognition_ServiceDesc, srv)
+}
+
+func _PlateRecognition_RecognizePlate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecognizePlateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlateRecognitionServer).RecognizePlate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlateRecognition_RecognizePlate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlateRecognitionServer).RecognizePlate(ctx, req.(*RecognizePlateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlateRecognition_Health_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlateRecognitionServer).Health(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlateRecognition_Health_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlateRecognitionServer).Health(ctx, req.(*HealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PlateRecognition_ServiceDesc is the grpc.ServiceDesc for PlateRecognition service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PlateRecognition_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gate.ml.v1.PlateRecognition",
+	HandlerType: (*PlateRecognitionServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RecognizePlate",
+			Handler:    _PlateRecognition_RecognizePlate_Handler,
+		},
+		{
+			MethodName: "Health",
+			Handler:    _PlateRecognition_Health_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "recognition.proto",
+}
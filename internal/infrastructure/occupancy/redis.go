@@ -0,0 +1,84 @@
+package occupancy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/frontandrew/gate/internal/pkg/redis"
+	redisv9 "github.com/redis/go-redis/v9"
+)
+
+// Key - хеш Redis со счетчиками автомобилей на территории: поле - зона или ворота без зоны
+// Счетчики общие для всех экземпляров API
+const Key = "gate:occupancy"
+
+// decrementScript уменьшает счетчик, не опуская его ниже нуля
+// Выезд без учтенного въезда (счетчик сброшен, проезд до включения учета) не дает отрицательных значений
+var decrementScript = redisv9.NewScript(`
+local count = redis.call('HINCRBY', KEYS[1], ARGV[1], -1)
+if count < 0 then
+	redis.call('HSET', KEYS[1], ARGV[1], 0)
+	return 0
+end
+return count
+`)
+
+// RedisCounter хранит заполненность территории в Redis
+type RedisCounter struct {
+	client *redis.Client
+}
+
+// NewRedisCounter создает счетчик заполненности поверх Redis
+func NewRedisCounter(client *redis.Client) *RedisCounter {
+	return &RedisCounter{client: client}
+}
+
+// Increment учитывает въезд и возвращает новое значение счетчика
+func (c *RedisCounter) Increment(ctx context.Context, scope string) (int64, error) {
+	return c.client.GetClient().HIncrBy(ctx, Key, scope, 1).Result()
+}
+
+// Decrement учитывает выезд и возвращает новое значение счетчика (не меньше нуля)
+func (c *RedisCounter) Decrement(ctx context.Context, scope string) (int64, error) {
+	return decrementScript.Run(ctx, c.client.GetClient(), []string{Key}, scope).Int64()
+}
+
+// Get возвращает число автомобилей в зоне; незнакомая зона - 0
+func (c *RedisCounter) Get(ctx context.Context, scope string) (int64, error) {
+	value, err := c.client.HGet(ctx, Key, scope)
+	if errors.Is(err, redisv9.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return parseCount(scope, value)
+}
+
+// All возвращает счетчики всех зон, где был хотя бы один проезд
+func (c *RedisCounter) All(ctx context.Context) (map[string]int64, error) {
+	values, err := c.client.GetClient().HGetAll(ctx, Key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(values))
+	for scope, value := range values {
+		count, err := parseCount(scope, value)
+		if err != nil {
+			return nil, err
+		}
+		counts[scope] = count
+	}
+	return counts, nil
+}
+
+func parseCount(scope, value string) (int64, error) {
+	count, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid occupancy counter for %s: %w", scope, err)
+	}
+	return count, nil
+}
@@ -0,0 +1,80 @@
+package occupancy
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/frontandrew/gate/internal/pkg/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCounter(t *testing.T) (*RedisCounter, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	host, port, err := net.SplitHostPort(mr.Addr())
+	require.NoError(t, err)
+
+	client, err := redis.NewClient(redis.Config{Host: host, Port: port})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	return NewRedisCounter(client), mr
+}
+
+func TestRedisCounter_IncrementDecrement(t *testing.T) {
+	counter, _ := newTestCounter(t)
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		count, err := counter.Increment(ctx, "parking")
+		require.NoError(t, err)
+		assert.Equal(t, int64(i), count)
+	}
+
+	count, err := counter.Decrement(ctx, "parking")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	count, err = counter.Get(ctx, "parking")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestRedisCounter_DecrementFlooredAtZero(t *testing.T) {
+	counter, mr := newTestCounter(t)
+	ctx := context.Background()
+
+	// Выезд без учтенного въезда
+	count, err := counter.Decrement(ctx, "gate-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+	assert.Equal(t, "0", mr.HGet(Key, "gate-1"))
+
+	count, err = counter.Increment(ctx, "gate-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestRedisCounter_GetAndAll(t *testing.T) {
+	counter, _ := newTestCounter(t)
+	ctx := context.Background()
+
+	count, err := counter.Get(ctx, "unknown")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	_, err = counter.Increment(ctx, "parking")
+	require.NoError(t, err)
+	_, err = counter.Increment(ctx, "gate-3")
+	require.NoError(t, err)
+	_, err = counter.Increment(ctx, "gate-3")
+	require.NoError(t, err)
+
+	counts, err := counter.All(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"parking": 1, "gate-3": 2}, counts)
+}
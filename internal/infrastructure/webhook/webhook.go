@@ -0,0 +1,246 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+)
+
+// Заголовки запроса доставки
+const (
+	// SignatureHeader содержит "sha256=<hex HMAC-SHA256 тела запроса>" на общем секрете
+	SignatureHeader = "X-Gate-Signature"
+	// EventHeader дублирует тип события из тела, чтобы получатель мог маршрутизировать без разбора JSON
+	EventHeader = "X-Gate-Event"
+)
+
+// Типы событий
+const (
+	EventAccessGranted = "access.granted"
+	EventAccessDenied  = "access.denied"
+)
+
+// Payload - тело запроса к получателю
+type Payload struct {
+	Event string              `json:"event"`
+	Data  *domain.AccessEvent `json:"data"`
+}
+
+// Config содержит настройки доставки webhook'ов
+type Config struct {
+	URLs    []string      // Адреса получателей; каждое событие отправляется на все
+	Secret  string        // Общий секрет подписи HMAC
+	Timeout time.Duration // Таймаут одного запроса
+
+	MaxRetries   int           // Повторов после первой попытки (сетевые ошибки, 429 и 5xx)
+	RetryBackoff time.Duration // Базовая задержка; удваивается с каждым повтором, плюс jitter
+
+	QueueSize int // Размер очереди событий; при переполнении новые события отбрасываются
+}
+
+// Dispatcher принимает решения о доступе и доставляет их получателям в фоне
+// Publish не блокирует проверку доступа: события складываются в очередь, которую разбирает Run
+type Dispatcher struct {
+	queue      chan *domain.AccessEvent
+	httpClient *http.Client
+	logger     logger.Logger
+	config     Config
+}
+
+// NewDispatcher создает диспетчер webhook'ов
+func NewDispatcher(logger logger.Logger, config Config) *Dispatcher {
+	if config.QueueSize <= 0 {
+		config.QueueSize = 1000
+	}
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	}
+
+	return &Dispatcher{
+		queue:      make(chan *domain.AccessEvent, config.QueueSize),
+		httpClient: &http.Client{Timeout: config.Timeout},
+		logger:     logger,
+		config:     config,
+	}
+}
+
+// Publish ставит событие в очередь доставки; при переполненной очереди событие отбрасывается
+func (d *Dispatcher) Publish(ctx context.Context, event *domain.AccessEvent) {
+	select {
+	case d.queue <- event:
+	default:
+		logger.FromContextOr(ctx, d.logger).Warn("Webhook queue is full, event dropped", map[string]interface{}{
+			"license_plate": event.LicensePlate,
+		})
+	}
+}
+
+// Run разбирает очередь до отмены ctx
+// Получатели обслуживаются по очереди: медленный получатель задерживает доставку остальным не дольше Timeout на попытку.
+// После отмены ctx доставляются события, уже стоящие в очереди (их поставили запросы, завершенные при остановке сервера);
+// время на это ограничено таймаутом остановки группы задач, после него недоставленные события теряются
+func (d *Dispatcher) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			d.drain(context.WithoutCancel(ctx))
+			return
+		case event := <-d.queue:
+			d.dispatch(context.WithoutCancel(ctx), event)
+		}
+	}
+}
+
+// drain доставляет события, оставшиеся в очереди, не дожидаясь новых
+func (d *Dispatcher) drain(ctx context.Context) {
+	pending := len(d.queue)
+	if pending == 0 {
+		return
+	}
+
+	d.logger.Info("Delivering queued webhooks before shutdown", map[string]interface{}{
+		"pending": pending,
+	})
+
+	for {
+		select {
+		case event := <-d.queue:
+			d.dispatch(ctx, event)
+		default:
+			return
+		}
+	}
+}
+
+// dispatch отправляет событие всем получателям
+func (d *Dispatcher) dispatch(ctx context.Context, event *domain.AccessEvent) {
+	payload := newPayload(event)
+	body, err := json.Marshal(payload)
+	if err != nil {
+		d.logger.Error("Failed to encode webhook payload", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	for _, url := range d.config.URLs {
+		if err := d.deliver(ctx, url, payload.Event, body); err != nil {
+			d.logger.Warn("Webhook delivery failed", map[string]interface{}{
+				"url":           url,
+				"license_plate": event.LicensePlate,
+				"error":         err.Error(),
+			})
+		}
+	}
+}
+
+// newPayload оборачивает решение о доступе в тело webhook'а
+func newPayload(event *domain.AccessEvent) Payload {
+	name := EventAccessDenied
+	if event.AccessGranted {
+		name = EventAccessGranted
+	}
+	return Payload{Event: name, Data: event}
+}
+
+// deliver отправляет тело получателю, повторяя при временных ошибках
+func (d *Dispatcher) deliver(ctx context.Context, url, event string, body []byte) error {
+	var lastErr error
+
+	attempts := d.config.MaxRetries + 1
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(d.backoff(i)):
+			}
+		}
+
+		lastErr = d.post(ctx, url, event, body)
+		if lastErr == nil {
+			return nil
+		}
+
+		if ctx.Err() != nil || !isRetryable(lastErr) {
+			return lastErr
+		}
+	}
+
+	return fmt.Errorf("delivery failed after %d attempts: %w", attempts, lastErr)
+}
+
+// post выполняет одну попытку доставки; успех - любой 2xx
+func (d *Dispatcher) post(ctx context.Context, url, event string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(SignatureHeader, Sign(d.config.Secret, body))
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Тело ответа не нужно, но дочитываем его, чтобы соединение вернулось в пул
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &statusError{StatusCode: resp.StatusCode}
+	}
+	return nil
+}
+
+// backoff возвращает задержку перед повтором: экспоненциальный рост от RetryBackoff с jitter
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	if d.config.RetryBackoff <= 0 {
+		return 0
+	}
+
+	delay := d.config.RetryBackoff << (attempt - 1)
+	half := int64(delay / 2)
+	return time.Duration(half + rand.Int64N(half+1))
+}
+
+// Sign возвращает значение заголовка SignatureHeader для тела запроса
+// Получатель считает HMAC-SHA256 от сырого тела тем же секретом и сравнивает через hmac.Equal
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// statusError - неуспешный HTTP статус ответа получателя
+type statusError struct {
+	StatusCode int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("receiver returned status %d", e.StatusCode)
+}
+
+// isRetryable определяет, можно ли повторить доставку
+// Остальные 4xx означают, что получатель отклоняет запрос, и повтор не поможет
+func isRetryable(err error) bool {
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= http.StatusInternalServerError
+	}
+	return !errors.Is(err, context.Canceled)
+}
@@ -0,0 +1,245 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// delivery - запрос, полученный тестовым получателем
+type delivery struct {
+	header http.Header
+	body   []byte
+}
+
+// newReceiver поднимает получателя, отвечающего statuses по очереди (дальше - 200)
+func newReceiver(t *testing.T, statuses ...int) (*httptest.Server, chan delivery, *int32) {
+	t.Helper()
+
+	deliveries := make(chan delivery, 10)
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := int(atomic.AddInt32(&calls, 1))
+		body, _ := io.ReadAll(r.Body)
+		if call <= len(statuses) {
+			w.WriteHeader(statuses[call-1])
+			return
+		}
+		deliveries <- delivery{header: r.Header.Clone(), body: body}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, deliveries, &calls
+}
+
+// runDispatcher запускает диспетчер до конца теста
+func runDispatcher(t *testing.T, config Config) *Dispatcher {
+	t.Helper()
+
+	d := NewDispatcher(logger.NewNoop(), config)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go d.Run(ctx)
+
+	return d
+}
+
+func waitDelivery(t *testing.T, deliveries chan delivery) delivery {
+	t.Helper()
+
+	select {
+	case d := <-deliveries:
+		return d
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook не доставлен")
+		return delivery{}
+	}
+}
+
+func TestDispatcher_PayloadAndSignature(t *testing.T) {
+	server, deliveries, _ := newReceiver(t)
+	d := runDispatcher(t, Config{URLs: []string{server.URL}, Secret: "webhook-secret", Timeout: time.Second})
+
+	timestamp := time.Date(2026, 10, 15, 8, 30, 0, 0, time.UTC)
+	d.Publish(context.Background(), &domain.AccessEvent{
+		LicensePlate:  "A123BC777",
+		AccessGranted: false,
+		Reason:        "Vehicle is blacklisted",
+		ReasonCode:    "BLACKLISTED",
+		GateID:        "north-1",
+		Direction:     domain.DirectionIn,
+		Timestamp:     timestamp,
+	})
+
+	got := waitDelivery(t, deliveries)
+
+	assert.Equal(t, "application/json", got.header.Get("Content-Type"))
+	assert.Equal(t, EventAccessDenied, got.header.Get(EventHeader))
+
+	// Подпись проверяется так же, как это сделает получатель
+	mac := hmac.New(sha256.New, []byte("webhook-secret"))
+	mac.Write(got.body)
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	assert.True(t, hmac.Equal([]byte(expected), []byte(got.header.Get(SignatureHeader))))
+	assert.Equal(t, Sign("webhook-secret", got.body), got.header.Get(SignatureHeader))
+	assert.NotEqual(t, Sign("other-secret", got.body), got.header.Get(SignatureHeader))
+
+	var payload map[string]interface{}
+	require.NoError(t, json.Unmarshal(got.body, &payload))
+	assert.Equal(t, EventAccessDenied, payload["event"])
+
+	data, ok := payload["data"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "A123BC777", data["license_plate"])
+	assert.Equal(t, false, data["access_granted"])
+	assert.Equal(t, "Vehicle is blacklisted", data["reason"])
+	assert.Equal(t, "BLACKLISTED", data["reason_code"])
+	assert.Equal(t, "north-1", data["gate_id"])
+	assert.Equal(t, "IN", data["direction"])
+	assert.Equal(t, "2026-10-15T08:30:00Z", data["timestamp"])
+}
+
+func TestDispatcher_GrantedEvent(t *testing.T) {
+	server, deliveries, _ := newReceiver(t)
+	d := runDispatcher(t, Config{URLs: []string{server.URL}, Secret: "s", Timeout: time.Second})
+
+	d.Publish(context.Background(), &domain.AccessEvent{LicensePlate: "A123BC777", AccessGranted: true})
+
+	got := waitDelivery(t, deliveries)
+	assert.Equal(t, EventAccessGranted, got.header.Get(EventHeader))
+	assert.True(t, strings.Contains(string(got.body), `"event":"access.granted"`))
+}
+
+func TestDispatcher_Retry(t *testing.T) {
+	tests := []struct {
+		name          string
+		statuses      []int
+		expectedCalls int32
+		delivered     bool
+	}{
+		{name: "503 повторяется до успеха", statuses: []int{503, 503}, expectedCalls: 3, delivered: true},
+		{name: "429 повторяется", statuses: []int{429}, expectedCalls: 2, delivered: true},
+		{name: "400 не повторяется", statuses: []int{400}, expectedCalls: 1},
+		{name: "не больше MaxRetries повторов", statuses: []int{500, 500, 500}, expectedCalls: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, deliveries, calls := newReceiver(t, tt.statuses...)
+			d := NewDispatcher(logger.NewNoop(), Config{
+				URLs:         []string{server.URL},
+				Secret:       "s",
+				Timeout:      time.Second,
+				MaxRetries:   2,
+				RetryBackoff: time.Millisecond,
+			})
+
+			// dispatch синхронен - после возврата все попытки сделаны
+			d.dispatch(context.Background(), &domain.AccessEvent{LicensePlate: "A123BC777"})
+
+			assert.Equal(t, tt.expectedCalls, atomic.LoadInt32(calls))
+			assert.Equal(t, tt.delivered, len(deliveries) == 1)
+		})
+	}
+}
+
+func TestDispatcher_Publish_DoesNotBlock(t *testing.T) {
+	// Run не запущен: очередь никто не разбирает
+	d := NewDispatcher(logger.NewNoop(), Config{URLs: []string{"http://127.0.0.1:0"}, QueueSize: 1})
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 3; i++ {
+			d.Publish(context.Background(), &domain.AccessEvent{LicensePlate: "A123BC777"})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish заблокирован переполненной очередью")
+	}
+	assert.Len(t, d.queue, 1)
+}
+
+func TestDispatcher_ShutdownFinishesInFlightDelivery(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	delivered := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		delivered <- struct{}{}
+	}))
+	t.Cleanup(server.Close)
+
+	d := NewDispatcher(logger.NewNoop(), Config{URLs: []string{server.URL}, Secret: "s", Timeout: time.Second})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		d.Run(ctx)
+		close(done)
+	}()
+
+	d.Publish(context.Background(), &domain.AccessEvent{LicensePlate: "A123BC777"})
+	<-started
+
+	// Сигнал остановки во время доставки: запрос не прерывается, после него Run завершается
+	cancel()
+	close(release)
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("диспетчер не остановился")
+	}
+	select {
+	case <-delivered:
+	default:
+		t.Fatal("начатая доставка прервана остановкой")
+	}
+}
+
+func TestDispatcher_ShutdownDrainsQueue(t *testing.T) {
+	server, deliveries, _ := newReceiver(t)
+	d := NewDispatcher(logger.NewNoop(), Config{URLs: []string{server.URL}, Secret: "s", Timeout: time.Second})
+
+	// События поставлены запросами, завершившимися уже после сигнала остановки
+	for _, plate := range []string{"A001AA777", "A002AA777", "A003AA777"} {
+		d.Publish(context.Background(), &domain.AccessEvent{LicensePlate: plate})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		d.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("диспетчер не остановился")
+	}
+
+	// Run вернулся только после доставки всей очереди
+	require.Len(t, deliveries, 3)
+	assert.Empty(t, d.queue)
+}
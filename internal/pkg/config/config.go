@@ -1,9 +1,12 @@
 package config
 
 import (
+	"crypto/tls"
 	"fmt"
+	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -11,13 +14,24 @@ import (
 
 // Config содержит всю конфигурацию приложения
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	JWT      JWTConfig
-	ML       MLConfig
-	CORS     CORSConfig
-	Logger   LoggerConfig
+	Env string // APP_ENV: development, staging или production; вне development проверки строже
+
+	Server      ServerConfig
+	Database    DatabaseConfig
+	Redis       RedisConfig
+	JWT         JWTConfig
+	Auth        AuthConfig
+	ML          MLConfig
+	CORS        CORSConfig
+	Logger      LoggerConfig
+	Cache       CacheConfig
+	Vehicle     VehicleConfig
+	Pass        PassConfig
+	Access      AccessConfig
+	RateLimit   RateLimitConfig
+	Idempotency IdempotencyConfig
+	Whitelist   WhitelistConfig
+	Webhook     WebhookConfig
 }
 
 // ServerConfig содержит настройки HTTP сервера
@@ -27,6 +41,20 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
 	IdleTimeout  time.Duration
+
+	PrivateCacheMaxAge time.Duration // max-age для пользовательских списков (Cache-Control: private)
+
+	HealthCheckTimeout time.Duration // Таймаут проверки зависимостей в /health/ready
+
+	// Ограничение размера тела запроса в байтах; для /access/check отдельный лимит из-за base64 изображений
+	MaxBodyBytes      int
+	MaxImageBodyBytes int
+
+	// TLS (пусто - обычный HTTP, например за reverse proxy)
+	TLSCertFile      string
+	TLSKeyFile       string
+	TLSMinVersionStr string // "1.2" или "1.3"
+	HTTPRedirectPort string // Порт HTTP listener'а с редиректом на HTTPS (пусто - отключен)
 }
 
 // DatabaseConfig содержит настройки подключения к PostgreSQL
@@ -40,6 +68,9 @@ type DatabaseConfig struct {
 	MaxOpenConns    int
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
+
+	ConnectMaxRetries   int           // Повторов подключения при старте после первой попытки
+	ConnectRetryBackoff time.Duration // Базовая задержка; удваивается с каждым повтором, плюс jitter
 }
 
 // RedisConfig содержит настройки подключения к Redis
@@ -48,20 +79,45 @@ type RedisConfig struct {
 	Port     string
 	Password string
 	DB       int
+
+	Required bool // Не стартовать без Redis (иначе - работа без кэшей и rate limiting)
 }
 
 // JWTConfig содержит настройки JWT аутентификации
 type JWTConfig struct {
+	SigningMethod string // HS256 (по умолчанию) или RS256
 	SecretKey     string
 	AccessExpiry  time.Duration
 	RefreshExpiry time.Duration
+
+	// RS256: kid и приватный ключ подписи, публичные ключи прежних kid для ротации
+	KeyID          string
+	PrivateKeyFile string
+	PublicKeyFiles map[string]string
+}
+
+// AuthConfig содержит настройки регистрации и входа пользователей
+type AuthConfig struct {
+	PhoneDefaultRegion string // Регион для телефонов без кода страны (ISO 3166-1 alpha-2)
+	SMSEnabled         bool   // Телефоны используются для SMS - некорректные номера отклоняются
+	RegistrationOpen   bool   // Разрешена самостоятельная регистрация через /auth/register
+
+	MaxLoginAttempts int           // Неудачных попыток входа до блокировки (0 - без ограничения)
+	LockoutDuration  time.Duration // Окно подсчета неудач и длительность блокировки
+
+	PasswordResetTTL time.Duration // Срок действия токена сброса пароля
 }
 
 // MLConfig содержит настройки ML сервиса
 type MLConfig struct {
+	Protocol      string // "http" (JSON) или "grpc"
 	ServiceURL    string
+	GRPCAddress   string // host:port gRPC сервера ML сервиса (для Protocol = "grpc")
 	MinConfidence float64
 	Timeout       time.Duration
+
+	MaxRetries   int           // Повторов после первой попытки (только сетевые ошибки, таймауты и 5xx)
+	RetryBackoff time.Duration // Базовая задержка; удваивается с каждым повтором, плюс jitter
 }
 
 // CORSConfig содержит настройки CORS
@@ -75,7 +131,107 @@ type CORSConfig struct {
 type LoggerConfig struct {
 	Level  string
 	Format string // json или console
-	Output string // stdout или путь к файлу
+	Output string // stdout, stderr или путь к файлу
+
+	// Ротация файла журнала (при Output - путь к файлу)
+	MaxSizeMB  int // Размер файла, после которого он ротируется
+	MaxBackups int // Сколько ротированных файлов хранить (0 - все)
+	MaxAgeDays int // Сколько дней хранить ротированные файлы (0 - не удалять по возрасту)
+}
+
+// CacheConfig содержит настройки кэширования списков
+type CacheConfig struct {
+	WarmOnStartup  bool // Прогревать кэш whitelist/blacklist при старте
+	WarmMaxEntries int  // Максимум записей каждого списка для прогрева
+
+	LastLoginInterval time.Duration // Обновлять last_login_at не чаще интервала (0 - при каждом входе)
+
+	UnregisteredPlateTTL time.Duration // Время жизни кэша отказов по незарегистрированным номерам (0 - отключен)
+	RecognitionTTL       time.Duration // Время жизни кэша распознавания по хешу кадра (0 - отключен)
+}
+
+// PassConfig содержит настройки выдачи пропусков
+type PassConfig struct {
+	GuestDailyLimit   int           // Сколько гостевых пропусков пользователь может выдать за сутки
+	GuestPassDuration time.Duration // Максимальный срок действия гостевого пропуска
+
+	RejectDuplicateVehicleLinks bool // Отклонять повторную привязку автомобиля (иначе - идемпотентно)
+	RejectOverlappingPasses     bool // Отклонять пропуск, дублирующий действующий (иначе - вернуть существующий)
+	RequireValidFrom            bool // Требовать valid_from при выдаче пропуска (иначе - с текущего момента)
+
+	ExpiryInterval time.Duration // Период деактивации истекших временных пропусков (0 - отключено)
+}
+
+// AccessConfig содержит настройки проверки доступа
+type AccessConfig struct {
+	StrictDirection       bool          // Отклонять запросы с направлением, отличным от IN/OUT
+	DeniedSummaryInterval time.Duration // Период сводки причин отказов в логе (0 - отключено)
+
+	Gates       []string // Известные gate_id площадки (пусто - не проверяются)
+	StrictGates bool     // Отклонять запросы с gate_id вне списка Gates
+
+	ObserveOnlyGates []string // Ворота в режиме наблюдения (решение только логируется)
+
+	GateZones map[string]string // Зоны ворот (gate_id -> зона) для списков и пропусков с ограничением по зоне
+
+	MaxFrameAge       time.Duration // Максимальный возраст кадра по captured_at (0 - не проверяется)
+	RejectStaleFrames bool          // Отклонять устаревшие кадры (иначе - помечать и не писать в лог)
+
+	AntiPassback bool // Запрещать повторный проезд в том же направлении
+
+	InferDirectionGates []string // Ворота без датчика направления (направление выводится из истории)
+
+	ReviewThreshold float64 // Уверенность, с которой номер ниже ML_MIN_CONFIDENCE отправляется на разбор (0 - отключено)
+
+	MaxOccupancy int // Вместимость каждой зоны: въезд при заполненной отклоняется (0 - без ограничения)
+
+	WhitelistFailClosed bool // Отказывать в доступе при ошибке проверки белого списка (иначе - проверка продолжается)
+	BlacklistFailClosed bool // Отказывать в доступе при ошибке проверки черного списка (иначе - проверка продолжается)
+}
+
+// RateLimitConfig содержит настройки ограничения частоты запросов
+type RateLimitConfig struct {
+	Enabled           bool
+	AccessCheckRate   int           // Запросов проверки доступа на ворота/пользователя за окно (устойчивая скорость)
+	AccessCheckBurst  int           // Допустимый всплеск сверх AccessCheckRate: столько запросов проходит сразу дополнительно
+	AccessCheckWindow time.Duration // Длительность окна
+
+	LoginRate      int           // Попыток входа за окно: отдельно на IP и на email
+	LoginWindow    time.Duration // Длительность окна для входа
+	RegisterRate   int           // Регистраций с одного IP за окно
+	RegisterWindow time.Duration // Длительность окна для регистрации
+
+	ForgotPasswordRate   int           // Запросов сброса пароля за окно: отдельно на IP и на email
+	ForgotPasswordWindow time.Duration // Длительность окна для запроса сброса
+	ResetPasswordRate    int           // Попыток установить пароль по токену с одного IP за окно
+	ResetPasswordWindow  time.Duration // Длительность окна для установки пароля
+}
+
+// IdempotencyConfig содержит настройки повторов запросов с заголовком Idempotency-Key
+type IdempotencyConfig struct {
+	TTL time.Duration // Сколько хранится ответ для повторов (0 - заголовок игнорируется)
+}
+
+// VehicleConfig содержит настройки номеров автомобилей
+type VehicleConfig struct {
+	PlateCountry string // Формат номеров (ISO 3166-1 alpha-2); пусто - проверяются только длина и символы
+}
+
+// WhitelistConfig содержит настройки управления белым списком
+type WhitelistConfig struct {
+	AutoCreateVehicle  bool   // Создавать автомобиль-заглушку для незарегистрированного номера
+	PlaceholderOwnerID string // UUID системного аккаунта-владельца заглушек
+}
+
+// WebhookConfig содержит настройки webhook'ов о решениях доступа
+type WebhookConfig struct {
+	URLs    []string      // Адреса получателей (пусто - webhook'и отключены)
+	Secret  string        // Секрет подписи HMAC-SHA256
+	Timeout time.Duration // Таймаут одного запроса
+
+	MaxRetries   int           // Повторов после первой попытки (сетевые ошибки, 429 и 5xx)
+	RetryBackoff time.Duration // Базовая задержка; удваивается с каждым повтором, плюс jitter
+	QueueSize    int           // Размер очереди событий; при переполнении события отбрасываются
 }
 
 // Load загружает конфигурацию из переменных окружения
@@ -84,12 +240,26 @@ func Load() (*Config, error) {
 	_ = godotenv.Load()
 
 	cfg := &Config{
+		Env: strings.ToLower(getEnv("APP_ENV", EnvDevelopment)),
+
 		Server: ServerConfig{
 			Host:         getEnv("SERVER_HOST", "0.0.0.0"),
 			Port:         getEnv("SERVER_PORT", "8080"),
 			ReadTimeout:  getDurationEnv("SERVER_READ_TIMEOUT", 15*time.Second),
 			WriteTimeout: getDurationEnv("SERVER_WRITE_TIMEOUT", 15*time.Second),
 			IdleTimeout:  getDurationEnv("SERVER_IDLE_TIMEOUT", 60*time.Second),
+
+			PrivateCacheMaxAge: getDurationEnv("SERVER_PRIVATE_CACHE_MAX_AGE", 30*time.Second),
+
+			HealthCheckTimeout: getDurationEnv("SERVER_HEALTH_CHECK_TIMEOUT", 2*time.Second),
+
+			MaxBodyBytes:      getIntEnv("SERVER_MAX_BODY_BYTES", 1<<20),
+			MaxImageBodyBytes: getIntEnv("SERVER_MAX_IMAGE_BODY_BYTES", 10<<20),
+
+			TLSCertFile:      getEnv("SERVER_TLS_CERT_FILE", ""),
+			TLSKeyFile:       getEnv("SERVER_TLS_KEY_FILE", ""),
+			TLSMinVersionStr: getEnv("SERVER_TLS_MIN_VERSION", "1.2"),
+			HTTPRedirectPort: getEnv("SERVER_HTTP_REDIRECT_PORT", ""),
 		},
 		Database: DatabaseConfig{
 			Host:            getEnv("DB_HOST", "localhost"),
@@ -101,40 +271,178 @@ func Load() (*Config, error) {
 			MaxOpenConns:    getIntEnv("DB_MAX_OPEN_CONNS", 25),
 			MaxIdleConns:    getIntEnv("DB_MAX_IDLE_CONNS", 5),
 			ConnMaxLifetime: getDurationEnv("DB_CONN_MAX_LIFETIME", 5*time.Minute),
+
+			ConnectMaxRetries:   getIntEnv("DB_CONNECT_MAX_RETRIES", 5),
+			ConnectRetryBackoff: getDurationEnv("DB_CONNECT_RETRY_BACKOFF", time.Second),
 		},
 		Redis: RedisConfig{
 			Host:     getEnv("REDIS_HOST", "localhost"),
 			Port:     getEnv("REDIS_PORT", "6379"),
 			Password: getEnv("REDIS_PASSWORD", ""),
 			DB:       getIntEnv("REDIS_DB", 0),
+
+			Required: getBoolEnv("REDIS_REQUIRED", false),
 		},
 		JWT: JWTConfig{
-			SecretKey:     getEnv("JWT_SECRET", "your-secret-key-change-this-in-production"),
+			SigningMethod: getEnv("JWT_SIGNING_METHOD", "HS256"),
+			SecretKey:     getEnv("JWT_SECRET", defaultJWTSecret),
 			AccessExpiry:  getDurationEnv("JWT_ACCESS_EXPIRY", 15*time.Minute),
 			RefreshExpiry: getDurationEnv("JWT_REFRESH_EXPIRY", 7*24*time.Hour),
+
+			KeyID:          getEnv("JWT_KEY_ID", ""),
+			PrivateKeyFile: getEnv("JWT_PRIVATE_KEY_FILE", ""),
+			PublicKeyFiles: getMapEnv("JWT_PUBLIC_KEY_FILES"),
+		},
+		Auth: AuthConfig{
+			PhoneDefaultRegion: getEnv("AUTH_PHONE_DEFAULT_REGION", "RU"),
+			SMSEnabled:         getBoolEnv("SMS_ENABLED", false),
+			RegistrationOpen:   getBoolEnv("AUTH_REGISTRATION_OPEN", true),
+			MaxLoginAttempts:   getIntEnv("AUTH_MAX_LOGIN_ATTEMPTS", 5),
+			LockoutDuration:    getDurationEnv("AUTH_LOCKOUT_DURATION", 15*time.Minute),
+			PasswordResetTTL:   getDurationEnv("AUTH_PASSWORD_RESET_TTL", 30*time.Minute),
 		},
 		ML: MLConfig{
+			Protocol:      strings.ToLower(getEnv("ML_PROTOCOL", "http")),
 			ServiceURL:    getEnv("ML_SERVICE_URL", "http://localhost:8001"),
+			GRPCAddress:   getEnv("ML_GRPC_ADDRESS", "localhost:50051"),
 			MinConfidence: getFloatEnv("ML_MIN_CONFIDENCE", 0.7),
 			Timeout:       getDurationEnv("ML_TIMEOUT", 30*time.Second),
+			MaxRetries:    getIntEnv("ML_MAX_RETRIES", 2),
+			RetryBackoff:  getDurationEnv("ML_RETRY_BACKOFF", 500*time.Millisecond),
 		},
 		CORS: CORSConfig{
-			AllowedOrigins: []string{
-				getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:5173,http://localhost:3000"),
-			},
-			AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-			AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+			AllowedOrigins: getSliceEnv("CORS_ALLOWED_ORIGINS", []string{"http://localhost:5173", "http://localhost:3000"}),
+			AllowedMethods: getSliceEnv("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}),
+			AllowedHeaders: getSliceEnv("CORS_ALLOWED_HEADERS", []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "Idempotency-Key"}),
 		},
 		Logger: LoggerConfig{
 			Level:  getEnv("LOG_LEVEL", "info"),
 			Format: getEnv("LOG_FORMAT", "json"),
 			Output: getEnv("LOG_OUTPUT", "stdout"),
+
+			MaxSizeMB:  getIntEnv("LOG_MAX_SIZE_MB", 100),
+			MaxBackups: getIntEnv("LOG_MAX_BACKUPS", 5),
+			MaxAgeDays: getIntEnv("LOG_MAX_AGE_DAYS", 30),
 		},
+		Cache: CacheConfig{
+			WarmOnStartup:  getBoolEnv("CACHE_WARM_ON_STARTUP", false),
+			WarmMaxEntries: getIntEnv("CACHE_WARM_MAX_ENTRIES", 1000),
+
+			LastLoginInterval: getDurationEnv("CACHE_LAST_LOGIN_INTERVAL", 0),
+
+			UnregisteredPlateTTL: getDurationEnv("CACHE_UNREGISTERED_PLATE_TTL", 10*time.Second),
+			RecognitionTTL:       getDurationEnv("CACHE_RECOGNITION_TTL", 5*time.Second),
+		},
+		Pass: PassConfig{
+			GuestDailyLimit:   getIntEnv("GUEST_PASS_DAILY_LIMIT", 3),
+			GuestPassDuration: getDurationEnv("GUEST_PASS_DURATION", 24*time.Hour),
+
+			RejectDuplicateVehicleLinks: getBoolEnv("PASS_REJECT_DUPLICATE_VEHICLE_LINKS", true),
+			RejectOverlappingPasses:     getBoolEnv("PASS_REJECT_OVERLAPPING", true),
+			RequireValidFrom:            getBoolEnv("PASS_REQUIRE_VALID_FROM", false),
+
+			ExpiryInterval: getDurationEnv("PASS_EXPIRY_INTERVAL", 5*time.Minute),
+		},
+		Access: AccessConfig{
+			StrictDirection:       getBoolEnv("ACCESS_STRICT_DIRECTION", true),
+			DeniedSummaryInterval: getDurationEnv("ACCESS_DENIED_SUMMARY_INTERVAL", 0),
+
+			Gates:       getSliceEnv("ACCESS_GATES", nil),
+			StrictGates: getBoolEnv("ACCESS_STRICT_GATES", true),
+
+			ObserveOnlyGates: getSliceEnv("ACCESS_OBSERVE_ONLY_GATES", nil),
+
+			GateZones: getMapEnv("ACCESS_GATE_ZONES"),
+
+			MaxFrameAge:       getDurationEnv("ACCESS_MAX_FRAME_AGE", 30*time.Second),
+			RejectStaleFrames: getBoolEnv("ACCESS_REJECT_STALE_FRAMES", true),
+
+			AntiPassback: getBoolEnv("ACCESS_ANTI_PASSBACK", false),
+
+			InferDirectionGates: getSliceEnv("ACCESS_INFER_DIRECTION_GATES", nil),
+
+			ReviewThreshold: getFloatEnv("ACCESS_REVIEW_THRESHOLD", 0),
+
+			MaxOccupancy: getIntEnv("ACCESS_MAX_OCCUPANCY", 0),
+
+			WhitelistFailClosed: getBoolEnv("ACCESS_WHITELIST_FAIL_CLOSED", false),
+			BlacklistFailClosed: getBoolEnv("ACCESS_BLACKLIST_FAIL_CLOSED", false),
+		},
+		Vehicle: VehicleConfig{
+			PlateCountry: getEnv("VEHICLE_PLATE_COUNTRY", "RU"),
+		},
+		Whitelist: WhitelistConfig{
+			AutoCreateVehicle:  getBoolEnv("WHITELIST_AUTO_CREATE_VEHICLE", false),
+			PlaceholderOwnerID: getEnv("WHITELIST_PLACEHOLDER_OWNER_ID", ""),
+		},
+		Webhook: WebhookConfig{
+			URLs:    getSliceEnv("WEBHOOK_URLS", nil),
+			Secret:  getEnv("WEBHOOK_SECRET", ""),
+			Timeout: getDurationEnv("WEBHOOK_TIMEOUT", 5*time.Second),
+
+			MaxRetries:   getIntEnv("WEBHOOK_MAX_RETRIES", 3),
+			RetryBackoff: getDurationEnv("WEBHOOK_RETRY_BACKOFF", time.Second),
+			QueueSize:    getIntEnv("WEBHOOK_QUEUE_SIZE", 1000),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:           getBoolEnv("RATE_LIMIT_ENABLED", true),
+			AccessCheckRate:   getIntEnv("RATE_LIMIT_ACCESS_CHECK_RATE", 60),
+			AccessCheckBurst:  getIntEnv("RATE_LIMIT_ACCESS_CHECK_BURST", 10),
+			AccessCheckWindow: getDurationEnv("RATE_LIMIT_ACCESS_CHECK_WINDOW", time.Minute),
+			LoginRate:         getIntEnv("RATE_LIMIT_LOGIN_RATE", 10),
+			LoginWindow:       getDurationEnv("RATE_LIMIT_LOGIN_WINDOW", time.Minute),
+			RegisterRate:      getIntEnv("RATE_LIMIT_REGISTER_RATE", 5),
+			RegisterWindow:    getDurationEnv("RATE_LIMIT_REGISTER_WINDOW", time.Hour),
+
+			ForgotPasswordRate:   getIntEnv("RATE_LIMIT_FORGOT_PASSWORD_RATE", 5),
+			ForgotPasswordWindow: getDurationEnv("RATE_LIMIT_FORGOT_PASSWORD_WINDOW", time.Hour),
+			ResetPasswordRate:    getIntEnv("RATE_LIMIT_RESET_PASSWORD_RATE", 10),
+			ResetPasswordWindow:  getDurationEnv("RATE_LIMIT_RESET_PASSWORD_WINDOW", time.Hour),
+		},
+		Idempotency: IdempotencyConfig{
+			TTL: getDurationEnv("IDEMPOTENCY_TTL", 24*time.Hour),
+		},
+	}
+
+	methods, err := normalizeMethods(cfg.CORS.AllowedMethods)
+	if err != nil {
+		return nil, err
+	}
+	cfg.CORS.AllowedMethods = methods
+
+	if cfg.ML.Protocol != "http" && cfg.ML.Protocol != "grpc" {
+		return nil, fmt.Errorf("invalid ML_PROTOCOL: %q (expected http or grpc)", cfg.ML.Protocol)
 	}
 
 	return cfg, nil
 }
 
+// validHTTPMethods содержит допустимые значения CORS_ALLOWED_METHODS
+var validHTTPMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodConnect: true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+}
+
+// normalizeMethods приводит методы к верхнему регистру и проверяет, что это HTTP глаголы
+func normalizeMethods(methods []string) ([]string, error) {
+	normalized := make([]string, 0, len(methods))
+	for _, method := range methods {
+		method = strings.ToUpper(method)
+		if !validHTTPMethods[method] {
+			return nil, fmt.Errorf("invalid HTTP method in CORS_ALLOWED_METHODS: %q", method)
+		}
+		normalized = append(normalized, method)
+	}
+	return normalized, nil
+}
+
 // DSN возвращает строку подключения к PostgreSQL
 func (c *DatabaseConfig) DSN() string {
 	return fmt.Sprintf(
@@ -148,6 +456,24 @@ func (c *ServerConfig) Address() string {
 	return fmt.Sprintf("%s:%s", c.Host, c.Port)
 }
 
+// TLSEnabled возвращает true, если заданы сертификат и ключ
+func (c *ServerConfig) TLSEnabled() bool {
+	return c.TLSCertFile != "" && c.TLSKeyFile != ""
+}
+
+// TLSMinVersion возвращает минимальную версию TLS; ниже 1.2 не опускается
+func (c *ServerConfig) TLSMinVersion() uint16 {
+	if c.TLSMinVersionStr == "1.3" {
+		return tls.VersionTLS13
+	}
+	return tls.VersionTLS12
+}
+
+// RedirectAddress возвращает адрес HTTP listener'а для редиректа на HTTPS
+func (c *ServerConfig) RedirectAddress() string {
+	return fmt.Sprintf("%s:%s", c.Host, c.HTTPRedirectPort)
+}
+
 // Address возвращает адрес Redis
 func (c *RedisConfig) Address() string {
 	return fmt.Sprintf("%s:%s", c.Host, c.Port)
@@ -162,6 +488,37 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getSliceEnv разбирает список через запятую, пропуская пустые элементы
+func getSliceEnv(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return defaultValue
+	}
+	return items
+}
+
+// getMapEnv разбирает список пар "ключ=значение" через запятую; элементы без "=" пропускаются
+func getMapEnv(key string) map[string]string {
+	result := make(map[string]string)
+	for _, item := range getSliceEnv(key, nil) {
+		k, v, ok := strings.Cut(item, "=")
+		if k, v = strings.TrimSpace(k), strings.TrimSpace(v); ok && k != "" && v != "" {
+			result[k] = v
+		}
+	}
+	return result
+}
+
 func getIntEnv(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
 		if intValue, err := strconv.Atoi(value); err == nil {
@@ -171,6 +528,15 @@ func getIntEnv(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getBoolEnv(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
 func getFloatEnv(key string, defaultValue float64) float64 {
 	if value := os.Getenv(key); value != "" {
 		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_CORS(t *testing.T) {
+	tests := []struct {
+		name            string
+		env             map[string]string
+		expectedOrigins []string
+		expectedMethods []string
+		expectedHeaders []string
+		expectErr       bool
+	}{
+		{
+			name:            "значения по умолчанию",
+			expectedOrigins: []string{"http://localhost:5173", "http://localhost:3000"},
+			expectedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+			expectedHeaders: []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "Idempotency-Key"},
+		},
+		{
+			name: "переопределение через окружение",
+			env: map[string]string{
+				"CORS_ALLOWED_ORIGINS": "https://gate.example.com",
+				"CORS_ALLOWED_METHODS": "get, post ,PATCH,",
+				"CORS_ALLOWED_HEADERS": "Content-Type, Authorization, X-Gate-ID",
+			},
+			expectedOrigins: []string{"https://gate.example.com"},
+			expectedMethods: []string{"GET", "POST", "PATCH"},
+			expectedHeaders: []string{"Content-Type", "Authorization", "X-Gate-ID"},
+		},
+		{
+			name:            "несколько origin через запятую",
+			env:             map[string]string{"CORS_ALLOWED_ORIGINS": "https://gate.example.com, http://localhost:5173 ,,https://*.partner.com"},
+			expectedOrigins: []string{"https://gate.example.com", "http://localhost:5173", "https://*.partner.com"},
+			expectedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+			expectedHeaders: []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "Idempotency-Key"},
+		},
+		{
+			name:      "недопустимый метод",
+			env:       map[string]string{"CORS_ALLOWED_METHODS": "GET,FETCH"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"CORS_ALLOWED_ORIGINS", "CORS_ALLOWED_METHODS", "CORS_ALLOWED_HEADERS"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			cfg, err := Load()
+
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedOrigins, cfg.CORS.AllowedOrigins)
+			assert.Equal(t, tt.expectedMethods, cfg.CORS.AllowedMethods)
+			assert.Equal(t, tt.expectedHeaders, cfg.CORS.AllowedHeaders)
+		})
+	}
+}
+
+func TestLoad_MLProtocol(t *testing.T) {
+	tests := []struct {
+		name      string
+		protocol  string
+		expected  string
+		expectErr bool
+	}{
+		{name: "по умолчанию http", expected: "http"},
+		{name: "grpc без учета регистра", protocol: "GRPC", expected: "grpc"},
+		{name: "неизвестный протокол", protocol: "websocket", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ML_PROTOCOL", tt.protocol)
+
+			cfg, err := Load()
+
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg.ML.Protocol)
+		})
+	}
+}
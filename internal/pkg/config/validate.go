@@ -0,0 +1,115 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Окружения приложения (APP_ENV)
+const (
+	EnvDevelopment = "development"
+	EnvStaging     = "staging"
+	EnvProduction  = "production"
+)
+
+// defaultJWTSecret - значение JWT_SECRET по умолчанию; допустимо только при разработке
+const defaultJWTSecret = "your-secret-key-change-this-in-production"
+
+// IsDevelopment возвращает true для локальной разработки, где допустимы небезопасные значения по умолчанию
+func (c *Config) IsDevelopment() bool {
+	return c.Env == EnvDevelopment
+}
+
+// Validate проверяет конфигурацию и возвращает все найденные ошибки сразу,
+// чтобы сервис не стартовал с настройками, которые сломаются на первом запросе
+func (c *Config) Validate() error {
+	var errs []error
+
+	switch c.Env {
+	case EnvDevelopment, EnvStaging, EnvProduction:
+	default:
+		errs = append(errs, fmt.Errorf("APP_ENV must be one of %s, %s, %s, got %q",
+			EnvDevelopment, EnvStaging, EnvProduction, c.Env))
+	}
+
+	errs = append(errs, c.validateJWT()...)
+	errs = append(errs, c.validateML()...)
+	errs = append(errs, c.validateWebhook()...)
+
+	if c.Database.MaxOpenConns <= 0 {
+		errs = append(errs, fmt.Errorf("DB_MAX_OPEN_CONNS must be positive, got %d", c.Database.MaxOpenConns))
+	}
+	if c.Database.MaxIdleConns <= 0 {
+		errs = append(errs, fmt.Errorf("DB_MAX_IDLE_CONNS must be positive, got %d", c.Database.MaxIdleConns))
+	}
+	if c.Access.MaxOccupancy < 0 {
+		errs = append(errs, fmt.Errorf("ACCESS_MAX_OCCUPANCY must be 0 (unlimited) or positive, got %d", c.Access.MaxOccupancy))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+}
+
+// validateJWT проверяет секрет HS256; ключи RS256 проверяются при создании TokenService
+func (c *Config) validateJWT() []error {
+	if strings.ToUpper(c.JWT.SigningMethod) != "HS256" {
+		return nil
+	}
+
+	switch {
+	case c.JWT.SecretKey == "":
+		return []error{errors.New("JWT_SECRET is required for HS256")}
+	case c.JWT.SecretKey == defaultJWTSecret && !c.IsDevelopment():
+		return []error{fmt.Errorf("JWT_SECRET must be changed from the default value when APP_ENV=%s", c.Env)}
+	}
+	return nil
+}
+
+// validateML проверяет адрес ML сервиса выбранного протокола и пороги уверенности
+func (c *Config) validateML() []error {
+	var errs []error
+
+	if c.ML.Protocol == "grpc" {
+		if c.ML.GRPCAddress == "" {
+			errs = append(errs, errors.New("ML_GRPC_ADDRESS is required when ML_PROTOCOL=grpc"))
+		}
+	} else if u, err := url.Parse(c.ML.ServiceURL); err != nil {
+		errs = append(errs, fmt.Errorf("ML_SERVICE_URL is not a valid URL: %v", err))
+	} else if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, fmt.Errorf("ML_SERVICE_URL must be an absolute http(s) URL, got %q", c.ML.ServiceURL))
+	}
+
+	if c.ML.MinConfidence < 0 || c.ML.MinConfidence > 1 {
+		errs = append(errs, fmt.Errorf("ML_MIN_CONFIDENCE must be between 0 and 1, got %g", c.ML.MinConfidence))
+	}
+
+	if c.Access.ReviewThreshold < 0 || (c.Access.ReviewThreshold > 0 && c.Access.ReviewThreshold >= c.ML.MinConfidence) {
+		errs = append(errs, fmt.Errorf("ACCESS_REVIEW_THRESHOLD must be 0 (disabled) or between 0 and ML_MIN_CONFIDENCE, got %g",
+			c.Access.ReviewThreshold))
+	}
+
+	return errs
+}
+
+// validateWebhook проверяет адреса получателей и наличие секрета подписи
+func (c *Config) validateWebhook() []error {
+	if len(c.Webhook.URLs) == 0 {
+		return nil
+	}
+
+	var errs []error
+	if c.Webhook.Secret == "" {
+		errs = append(errs, errors.New("WEBHOOK_SECRET is required when WEBHOOK_URLS is set"))
+	}
+	for _, raw := range c.Webhook.URLs {
+		if u, err := url.Parse(raw); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("WEBHOOK_URLS must contain absolute http(s) URLs, got %q", raw))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,196 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validConfig возвращает конфигурацию, проходящую все проверки
+func validConfig() *Config {
+	return &Config{
+		Env:      EnvProduction,
+		Database: DatabaseConfig{MaxOpenConns: 25, MaxIdleConns: 5},
+		JWT:      JWTConfig{SigningMethod: "HS256", SecretKey: "production-secret"},
+		ML: MLConfig{
+			Protocol:      "http",
+			ServiceURL:    "http://ml-service:8001",
+			GRPCAddress:   "ml-service:50051",
+			MinConfidence: 0.7,
+		},
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(cfg *Config)
+		expectedErr string // Пусто - конфигурация валидна
+	}{
+		{
+			name:   "валидная конфигурация",
+			modify: func(cfg *Config) {},
+		},
+		{
+			name:        "неизвестное окружение",
+			modify:      func(cfg *Config) { cfg.Env = "prod" },
+			expectedErr: "APP_ENV",
+		},
+		{
+			name:        "секрет JWT по умолчанию вне development",
+			modify:      func(cfg *Config) { cfg.JWT.SecretKey = defaultJWTSecret },
+			expectedErr: "JWT_SECRET must be changed",
+		},
+		{
+			name: "секрет JWT по умолчанию допустим в development",
+			modify: func(cfg *Config) {
+				cfg.Env = EnvDevelopment
+				cfg.JWT.SecretKey = defaultJWTSecret
+			},
+		},
+		{
+			name: "пустой секрет JWT недопустим даже в development",
+			modify: func(cfg *Config) {
+				cfg.Env = EnvDevelopment
+				cfg.JWT.SecretKey = ""
+			},
+			expectedErr: "JWT_SECRET is required",
+		},
+		{
+			name: "секрет не нужен для RS256",
+			modify: func(cfg *Config) {
+				cfg.JWT.SigningMethod = "RS256"
+				cfg.JWT.SecretKey = ""
+			},
+		},
+		{
+			name:        "URL ML сервиса не разбирается",
+			modify:      func(cfg *Config) { cfg.ML.ServiceURL = "http://ml service:8001\n" },
+			expectedErr: "ML_SERVICE_URL is not a valid URL",
+		},
+		{
+			name:        "URL ML сервиса без схемы",
+			modify:      func(cfg *Config) { cfg.ML.ServiceURL = "ml-service:8001" },
+			expectedErr: "ML_SERVICE_URL must be an absolute http(s) URL",
+		},
+		{
+			name: "URL не проверяется для gRPC",
+			modify: func(cfg *Config) {
+				cfg.ML.Protocol = "grpc"
+				cfg.ML.ServiceURL = ""
+			},
+		},
+		{
+			name: "gRPC без адреса",
+			modify: func(cfg *Config) {
+				cfg.ML.Protocol = "grpc"
+				cfg.ML.GRPCAddress = ""
+			},
+			expectedErr: "ML_GRPC_ADDRESS is required",
+		},
+		{
+			name:        "отрицательная уверенность",
+			modify:      func(cfg *Config) { cfg.ML.MinConfidence = -0.1 },
+			expectedErr: "ML_MIN_CONFIDENCE must be between 0 and 1",
+		},
+		{
+			name:        "уверенность в процентах",
+			modify:      func(cfg *Config) { cfg.ML.MinConfidence = 70 },
+			expectedErr: "ML_MIN_CONFIDENCE must be between 0 and 1",
+		},
+		{
+			name: "webhook с секретом",
+			modify: func(cfg *Config) {
+				cfg.Webhook.URLs = []string{"https://hooks.example.com/gate"}
+				cfg.Webhook.Secret = "webhook-secret"
+			},
+		},
+		{
+			name:        "webhook без секрета",
+			modify:      func(cfg *Config) { cfg.Webhook.URLs = []string{"https://hooks.example.com/gate"} },
+			expectedErr: "WEBHOOK_SECRET is required",
+		},
+		{
+			name: "webhook без схемы",
+			modify: func(cfg *Config) {
+				cfg.Webhook.URLs = []string{"hooks.example.com/gate"}
+				cfg.Webhook.Secret = "webhook-secret"
+			},
+			expectedErr: "WEBHOOK_URLS must contain absolute http(s) URLs",
+		},
+		{
+			name:   "порог разбора ниже минимальной уверенности",
+			modify: func(cfg *Config) { cfg.Access.ReviewThreshold = 0.4 },
+		},
+		{
+			name:        "порог разбора не ниже минимальной уверенности",
+			modify:      func(cfg *Config) { cfg.Access.ReviewThreshold = 0.7 },
+			expectedErr: "ACCESS_REVIEW_THRESHOLD",
+		},
+		{
+			name:        "отрицательный порог разбора",
+			modify:      func(cfg *Config) { cfg.Access.ReviewThreshold = -0.1 },
+			expectedErr: "ACCESS_REVIEW_THRESHOLD",
+		},
+		{
+			name:        "отрицательная вместимость",
+			modify:      func(cfg *Config) { cfg.Access.MaxOccupancy = -1 },
+			expectedErr: "ACCESS_MAX_OCCUPANCY",
+		},
+		{
+			name:        "нулевой пул соединений",
+			modify:      func(cfg *Config) { cfg.Database.MaxOpenConns = 0 },
+			expectedErr: "DB_MAX_OPEN_CONNS must be positive",
+		},
+		{
+			name:        "отрицательный пул простаивающих соединений",
+			modify:      func(cfg *Config) { cfg.Database.MaxIdleConns = -1 },
+			expectedErr: "DB_MAX_IDLE_CONNS must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
+
+func TestConfig_Validate_ReportsAllErrors(t *testing.T) {
+	cfg := validConfig()
+	cfg.JWT.SecretKey = defaultJWTSecret
+	cfg.ML.MinConfidence = 2
+	cfg.Database.MaxOpenConns = 0
+
+	err := cfg.Validate()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "JWT_SECRET")
+	assert.Contains(t, err.Error(), "ML_MIN_CONFIDENCE")
+	assert.Contains(t, err.Error(), "DB_MAX_OPEN_CONNS")
+}
+
+func TestLoad_AppEnv(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, EnvDevelopment, cfg.Env)
+	assert.NoError(t, cfg.Validate(), "значения по умолчанию валидны при разработке")
+
+	t.Setenv("APP_ENV", "Production")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, EnvProduction, cfg.Env)
+	assert.ErrorContains(t, cfg.Validate(), "JWT_SECRET must be changed")
+}
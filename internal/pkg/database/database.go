@@ -3,45 +3,24 @@ package database
 import (
 	"context"
 	"fmt"
+	"math/rand/v2"
 	"time"
 
 	"github.com/frontandrew/gate/internal/pkg/config"
+	"github.com/frontandrew/gate/internal/pkg/logger"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// Connect создает пул подключений к PostgreSQL
-func Connect(ctx context.Context, cfg *config.DatabaseConfig) (*pgxpool.Pool, error) {
-	// Формируем строку подключения
-	dsn := fmt.Sprintf(
-		"postgres://%s:%s@%s:%s/%s?sslmode=%s",
-		cfg.User,
-		cfg.Password,
-		cfg.Host,
-		cfg.Port,
-		cfg.Database,
-		cfg.SSLMode,
-	)
-
-	// Настраиваем конфигурацию пула
-	poolConfig, err := pgxpool.ParseConfig(dsn)
-	if err != nil {
-		return nil, fmt.Errorf("unable to parse database config: %w", err)
-	}
-
-	// Настройки пула соединений
-	poolConfig.MaxConns = int32(cfg.MaxOpenConns)
-	poolConfig.MinConns = int32(cfg.MaxIdleConns)
-	poolConfig.MaxConnLifetime = cfg.ConnMaxLifetime
-	poolConfig.MaxConnIdleTime = 5 * time.Minute
-	poolConfig.HealthCheckPeriod = 1 * time.Minute
+// maxRetryBackoff ограничивает рост задержки между попытками подключения
+const maxRetryBackoff = 30 * time.Second
 
-	// Создаем пул подключений
+// openPool создает пул и проверяет подключение; в тестах подменяется заглушкой
+var openPool = func(ctx context.Context, poolConfig *pgxpool.Config) (*pgxpool.Pool, error) {
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create connection pool: %w", err)
 	}
 
-	// Проверяем подключение
 	if err := pool.Ping(ctx); err != nil {
 		pool.Close()
 		return nil, fmt.Errorf("unable to ping database: %w", err)
@@ -50,6 +29,111 @@ func Connect(ctx context.Context, cfg *config.DatabaseConfig) (*pgxpool.Pool, er
 	return pool, nil
 }
 
+// Connect создает пул подключений к PostgreSQL
+// Если БД недоступна (например, контейнер еще стартует), подключение повторяется до cfg.ConnectMaxRetries раз
+// с экспоненциальной задержкой; отмена ctx прерывает ожидание. Неудачная попытка не оставляет открытых соединений
+func Connect(ctx context.Context, log logger.Logger, cfg *config.DatabaseConfig) (*pgxpool.Pool, error) {
+	poolConfig, err := newPoolConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts := cfg.ConnectMaxRetries + 1
+	var lastErr error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			delay := backoff(cfg.ConnectRetryBackoff, i)
+			log.Warn("Database connection failed, retrying", map[string]interface{}{
+				"attempt": i,
+				"delay":   delay.String(),
+				"error":   lastErr.Error(),
+			})
+
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("database connection canceled: %w", lastErr)
+			case <-time.After(delay):
+			}
+		}
+
+		pool, err := openPool(ctx, poolConfig)
+		if err == nil {
+			return pool, nil
+		}
+		lastErr = err
+
+		if ctx.Err() != nil {
+			return nil, lastErr
+		}
+	}
+
+	return nil, fmt.Errorf("database connection failed after %d attempts: %w", attempts, lastErr)
+}
+
+// newPoolConfig формирует конфигурацию пула из настроек приложения
+func newPoolConfig(cfg *config.DatabaseConfig) (*pgxpool.Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(cfg.DSN())
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse database config: %w", err)
+	}
+
+	// Настройки пула соединений; нулевые значения оставляют умолчания pgxpool
+	if cfg.MaxOpenConns > 0 {
+		poolConfig.MaxConns = int32(cfg.MaxOpenConns)
+	}
+	if cfg.MaxIdleConns > 0 {
+		poolConfig.MinConns = int32(min(cfg.MaxIdleConns, int(poolConfig.MaxConns)))
+	}
+	if cfg.ConnMaxLifetime > 0 {
+		poolConfig.MaxConnLifetime = cfg.ConnMaxLifetime
+	}
+	poolConfig.MaxConnIdleTime = 5 * time.Minute
+	poolConfig.HealthCheckPeriod = 1 * time.Minute
+
+	return poolConfig, nil
+}
+
+// backoff возвращает задержку перед повтором: экспоненциальный рост от base с jitter, не больше maxRetryBackoff
+func backoff(base time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+
+	delay := base << (attempt - 1)
+	if delay <= 0 || delay > maxRetryBackoff {
+		delay = maxRetryBackoff
+	}
+	half := int64(delay / 2)
+	return time.Duration(half + rand.Int64N(half+1))
+}
+
+// PoolStats - состояние пула подключений для метрик и /health/ready
+type PoolStats struct {
+	MaxConns             int32         `json:"max_conns"`
+	TotalConns           int32         `json:"total_conns"`
+	IdleConns            int32         `json:"idle_conns"`
+	AcquiredConns        int32         `json:"acquired_conns"`
+	AcquireCount         int64         `json:"acquire_count"`
+	EmptyAcquireCount    int64         `json:"empty_acquire_count"`    // Получений, которым пришлось ждать соединение
+	CanceledAcquireCount int64         `json:"canceled_acquire_count"` // Получений, прерванных отменой контекста
+	AcquireDuration      time.Duration `json:"-"`                      // Суммарное время ожидания соединения
+}
+
+// Stats возвращает текущее состояние пула
+func Stats(pool *pgxpool.Pool) PoolStats {
+	stat := pool.Stat()
+	return PoolStats{
+		MaxConns:             stat.MaxConns(),
+		TotalConns:           stat.TotalConns(),
+		IdleConns:            stat.IdleConns(),
+		AcquiredConns:        stat.AcquiredConns(),
+		AcquireCount:         stat.AcquireCount(),
+		EmptyAcquireCount:    stat.EmptyAcquireCount(),
+		CanceledAcquireCount: stat.CanceledAcquireCount(),
+		AcquireDuration:      stat.AcquireDuration(),
+	}
+}
+
 // Close закрывает пул подключений
 func Close(pool *pgxpool.Pool) {
 	if pool != nil {
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/frontandrew/gate/internal/pkg/config"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errDial = errors.New("dial tcp 127.0.0.1:5432: connect: connection refused")
+
+// stubDialer подменяет открытие пула: первые failures попыток завершаются ошибкой,
+// затем возвращается пул без реальных соединений (pgxpool подключается лениво)
+func stubDialer(t *testing.T, failures int) *int {
+	t.Helper()
+
+	attempts := 0
+	original := openPool
+	openPool = func(ctx context.Context, poolConfig *pgxpool.Config) (*pgxpool.Pool, error) {
+		attempts++
+		if attempts <= failures {
+			return nil, errDial
+		}
+		return pgxpool.NewWithConfig(ctx, poolConfig)
+	}
+	t.Cleanup(func() { openPool = original })
+
+	return &attempts
+}
+
+func testConfig() *config.DatabaseConfig {
+	return &config.DatabaseConfig{
+		Host:                "localhost",
+		Port:                "5432",
+		User:                "gate_user",
+		Password:            "gate_password",
+		Database:            "gate_db",
+		SSLMode:             "disable",
+		MaxOpenConns:        10,
+		ConnMaxLifetime:     time.Minute,
+		ConnectMaxRetries:   3,
+		ConnectRetryBackoff: time.Millisecond,
+	}
+}
+
+func TestConnect_RetriesUntilSuccess(t *testing.T) {
+	attempts := stubDialer(t, 2)
+
+	pool, err := Connect(context.Background(), logger.NewNoop(), testConfig())
+	require.NoError(t, err)
+	defer Close(pool)
+
+	assert.Equal(t, 3, *attempts, "две неудачные попытки и успешная")
+	assert.Equal(t, int32(10), Stats(pool).MaxConns)
+}
+
+func TestConnect_RetriesExhausted(t *testing.T) {
+	attempts := stubDialer(t, 10)
+
+	pool, err := Connect(context.Background(), logger.NewNoop(), testConfig())
+	assert.Nil(t, pool)
+	assert.ErrorIs(t, err, errDial)
+	assert.Equal(t, 4, *attempts, "первая попытка и три повтора")
+}
+
+func TestConnect_ContextCanceled(t *testing.T) {
+	attempts := stubDialer(t, 10)
+
+	cfg := testConfig()
+	cfg.ConnectRetryBackoff = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	started := time.Now()
+	pool, err := Connect(ctx, logger.NewNoop(), cfg)
+	assert.Nil(t, pool)
+	assert.ErrorIs(t, err, errDial)
+	assert.Less(t, time.Since(started), time.Second, "ожидание повтора прерывается отменой контекста")
+	assert.Equal(t, 1, *attempts)
+}
+
+func TestNewPoolConfig(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxIdleConns = 20
+
+	poolConfig, err := newPoolConfig(cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(10), poolConfig.MaxConns)
+	assert.Equal(t, int32(10), poolConfig.MinConns, "свободных соединений не больше размера пула")
+	assert.Equal(t, time.Minute, poolConfig.MaxConnLifetime)
+	assert.Equal(t, "gate_db", poolConfig.ConnConfig.Database)
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Zero(t, backoff(0, 1))
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		delay := backoff(time.Second, attempt)
+		expected := time.Second << (attempt - 1)
+		assert.GreaterOrEqual(t, delay, expected/2)
+		assert.LessOrEqual(t, delay, expected)
+	}
+
+	assert.LessOrEqual(t, backoff(time.Second, 40), maxRetryBackoff, "задержка ограничена сверху")
+}
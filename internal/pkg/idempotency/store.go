@@ -0,0 +1,101 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/frontandrew/gate/internal/pkg/redis"
+	redisv9 "github.com/redis/go-redis/v9"
+)
+
+// pendingTTL - сколько ключ занят еще не завершенным запросом
+// Если инстанс упал посреди обработки, ключ освобождается сам и клиент может повторить запрос
+const pendingTTL = time.Minute
+
+// Config содержит параметры хранения ответов
+type Config struct {
+	TTL time.Duration // Сколько хранится ответ: повтор с тем же ключом после TTL выполняется заново
+}
+
+// Record - запрос с ключом идемпотентности и (после завершения) его ответ
+type Record struct {
+	Fingerprint string `json:"fingerprint"` // Отпечаток тела исходного запроса
+	Completed   bool   `json:"completed"`   // false - исходный запрос еще обрабатывается
+	StatusCode  int    `json:"status_code,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// Store хранит ответы на запросы с ключом идемпотентности в Redis
+// Записи общие для всех инстансов API, поэтому повтор может прийти на любой из них
+type Store struct {
+	client *redis.Client
+	prefix string
+	config Config
+}
+
+// NewStore создает новое хранилище; prefix отделяет ключи от остальных данных в Redis
+func NewStore(client *redis.Client, prefix string, config Config) *Store {
+	return &Store{
+		client: client,
+		prefix: prefix,
+		config: config,
+	}
+}
+
+// Begin занимает ключ под новый запрос с отпечатком fingerprint
+// Если ключ уже занят, возвращает его запись (завершенную или еще обрабатываемую) и started=false
+func (s *Store) Begin(ctx context.Context, key, fingerprint string) (existing *Record, started bool, err error) {
+	pending, err := json.Marshal(Record{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Вторая попытка нужна, если занятый ключ истек между SetNX и Get
+	for attempt := 0; attempt < 2; attempt++ {
+		ok, err := s.client.SetNX(ctx, s.prefix+key, pending, pendingTTL)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+		}
+		if ok {
+			return nil, true, nil
+		}
+
+		raw, err := s.client.Get(ctx, s.prefix+key)
+		if errors.Is(err, redisv9.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get idempotency record: %w", err)
+		}
+
+		var record Record
+		if err := json.Unmarshal([]byte(raw), &record); err != nil {
+			return nil, false, fmt.Errorf("failed to decode idempotency record: %w", err)
+		}
+		return &record, false, nil
+	}
+
+	return nil, false, errors.New("idempotency key is changing concurrently")
+}
+
+// Complete сохраняет ответ на запрос, занявший ключ, на Config.TTL
+func (s *Store) Complete(ctx context.Context, key string, record *Record) error {
+	record.Completed = true
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if err := s.client.Set(ctx, s.prefix+key, data, s.config.TTL); err != nil {
+		return fmt.Errorf("failed to save idempotency record: %w", err)
+	}
+	return nil
+}
+
+// Release освобождает ключ без сохранения ответа (запрос не удался, повтор должен выполниться заново)
+func (s *Store) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, s.prefix+key)
+}
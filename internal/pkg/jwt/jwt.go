@@ -1,9 +1,12 @@
 package jwt
 
 import (
+	"crypto/rsa"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/frontandrew/gate/internal/domain"
@@ -12,6 +15,7 @@ import (
 )
 
 // Claims содержит payload JWT токена
+// Уникальный jti (RegisteredClaims.ID) позволяет отозвать отдельный access токен
 type Claims struct {
 	UserID uuid.UUID       `json:"user_id"`
 	Email  string          `json:"email"`
@@ -19,29 +23,106 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
+// Поддерживаемые алгоритмы подписи
+const (
+	SigningMethodHS256 = "HS256" // Общий секрет (по умолчанию)
+	SigningMethodRS256 = "RS256" // Пара ключей RSA с kid для ротации
+)
+
+// Config содержит настройки подписи токенов
+type Config struct {
+	SigningMethod string // HS256 (по умолчанию) или RS256
+	SecretKey     string // Секрет для HS256
+
+	// RS256: токены подписываются приватным ключом, в заголовок пишется KeyID
+	// Проверка выбирает публичный ключ по kid: текущий берется из приватного ключа,
+	// PublicKeyFiles (kid -> PEM файл) - прежние ключи, токены которых еще действуют
+	KeyID          string
+	PrivateKeyFile string
+	PublicKeyFiles map[string]string
+
+	AccessExpiry  time.Duration
+	RefreshExpiry time.Duration
+}
+
 // TokenService управляет созданием и валидацией JWT токенов
 type TokenService struct {
-	secretKey     string
+	method        jwt.SigningMethod
+	signKey       interface{}               // []byte для HS256, *rsa.PrivateKey для RS256
+	keyID         string                    // kid подписи; пусто для HS256
+	verifyKeys    map[string]*rsa.PublicKey // Ключи проверки RS256 по kid
 	accessExpiry  time.Duration
 	refreshExpiry time.Duration
 }
 
 // TokenPair содержит access и refresh токены
 type TokenPair struct {
-	AccessToken  string    `json:"access_token"`
-	RefreshToken string    `json:"refresh_token"`
-	ExpiresAt    time.Time `json:"expires_at"`
+	AccessToken      string    `json:"access_token"`
+	RefreshToken     string    `json:"refresh_token"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
 }
 
-// NewTokenService создает новый сервис для работы с токенами
+// NewTokenService создает новый сервис для работы с токенами (HS256)
 func NewTokenService(secretKey string, accessExpiry, refreshExpiry time.Duration) *TokenService {
 	return &TokenService{
-		secretKey:     secretKey,
+		method:        jwt.SigningMethodHS256,
+		signKey:       []byte(secretKey),
 		accessExpiry:  accessExpiry,
 		refreshExpiry: refreshExpiry,
 	}
 }
 
+// NewTokenServiceFromConfig создает сервис с выбранным алгоритмом подписи
+// Для RS256 ключи читаются из PEM файлов
+func NewTokenServiceFromConfig(cfg Config) (*TokenService, error) {
+	switch cfg.SigningMethod {
+	case "", SigningMethodHS256:
+		return NewTokenService(cfg.SecretKey, cfg.AccessExpiry, cfg.RefreshExpiry), nil
+	case SigningMethodRS256:
+	default:
+		return nil, fmt.Errorf("unsupported JWT signing method: %q", cfg.SigningMethod)
+	}
+
+	if cfg.KeyID == "" {
+		return nil, fmt.Errorf("JWT key id is required for %s", SigningMethodRS256)
+	}
+
+	privatePEM, err := os.ReadFile(cfg.PrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT private key: %w", err)
+	}
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JWT private key: %w", err)
+	}
+
+	verifyKeys := map[string]*rsa.PublicKey{cfg.KeyID: &privateKey.PublicKey}
+	for kid, path := range cfg.PublicKeyFiles {
+		if kid == cfg.KeyID {
+			return nil, fmt.Errorf("JWT public key %q duplicates the signing key id", kid)
+		}
+		publicPEM, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT public key %q: %w", kid, err)
+		}
+		publicKey, err := jwt.ParseRSAPublicKeyFromPEM(publicPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JWT public key %q: %w", kid, err)
+		}
+		verifyKeys[kid] = publicKey
+	}
+
+	return &TokenService{
+		method:        jwt.SigningMethodRS256,
+		signKey:       privateKey,
+		keyID:         cfg.KeyID,
+		verifyKeys:    verifyKeys,
+		accessExpiry:  cfg.AccessExpiry,
+		refreshExpiry: cfg.RefreshExpiry,
+	}, nil
+}
+
 // GenerateTokenPair генерирует пару access и refresh токенов
 func (ts *TokenService) GenerateTokenPair(user *domain.User) (*TokenPair, error) {
 	// Access Token
@@ -51,15 +132,16 @@ func (ts *TokenService) GenerateTokenPair(user *domain.User) (*TokenPair, error)
 	}
 
 	// Refresh Token
-	refreshToken, _, err := ts.generateToken(user, ts.refreshExpiry)
+	refreshToken, refreshExpiresAt, err := ts.generateToken(user, ts.refreshExpiry)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
 	return &TokenPair{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresAt:    expiresAt,
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		ExpiresAt:        expiresAt,
+		RefreshExpiresAt: refreshExpiresAt,
 	}, nil
 }
 
@@ -72,6 +154,8 @@ func (ts *TokenService) generateToken(user *domain.User, expiry time.Duration) (
 		Email:  user.Email,
 		Role:   user.Role,
 		RegisteredClaims: jwt.RegisteredClaims{
+			// jti делает токены уникальными даже при выдаче в одну секунду (нужно для ротации)
+			ID:        uuid.NewString(),
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
@@ -79,8 +163,11 @@ func (ts *TokenService) generateToken(user *domain.User, expiry time.Duration) (
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(ts.secretKey))
+	token := jwt.NewWithClaims(ts.method, claims)
+	if ts.keyID != "" {
+		token.Header["kid"] = ts.keyID
+	}
+	tokenString, err := token.SignedString(ts.signKey)
 	if err != nil {
 		return "", time.Time{}, err
 	}
@@ -88,17 +175,19 @@ func (ts *TokenService) generateToken(user *domain.User, expiry time.Duration) (
 	return tokenString, expiresAt, nil
 }
 
+// AccessExpiry возвращает срок жизни access токена
+func (ts *TokenService) AccessExpiry() time.Duration {
+	return ts.accessExpiry
+}
+
 // ValidateToken валидирует JWT токен и возвращает claims
 func (ts *TokenService) ValidateToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		// Проверяем алгоритм подписи
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(ts.secretKey), nil
-	})
-
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, ts.verificationKey)
 	if err != nil {
+		// Библиотека проверяет exp при разборе - истекший токен отличаем от поддельного
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, domain.ErrTokenExpired
+		}
 		return nil, fmt.Errorf("invalid token: %w", err)
 	}
 
@@ -118,10 +207,7 @@ func (ts *TokenService) ValidateToken(tokenString string) (*Claims, error) {
 // ExtractClaims извлекает claims из токена без валидации срока действия
 // Полезно для refresh token flow
 func (ts *TokenService) ExtractClaims(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(ts.secretKey), nil
-	})
-
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, ts.verificationKey)
 	if err != nil {
 		return nil, err
 	}
@@ -134,6 +220,25 @@ func (ts *TokenService) ExtractClaims(tokenString string) (*Claims, error) {
 	return claims, nil
 }
 
+// verificationKey возвращает ключ проверки подписи токена
+// Алгоритм должен совпадать с настроенным; для RS256 ключ выбирается по kid из заголовка
+func (ts *TokenService) verificationKey(token *jwt.Token) (interface{}, error) {
+	if token.Method.Alg() != ts.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	if ts.verifyKeys == nil {
+		return ts.signKey, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ts.verifyKeys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id: %q", kid)
+	}
+	return key, nil
+}
+
 // HashToken создает SHA-256 хеш токена для хранения в БД
 func HashToken(token string) string {
 	hash := sha256.Sum256([]byte(token))
@@ -0,0 +1,127 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeRSAKey генерирует пару ключей и сохраняет приватный и публичный ключи в PEM файлы
+func writeRSAKey(t *testing.T, name string) (privatePath, publicPath string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	privatePath = filepath.Join(dir, name+".pem")
+	publicPath = filepath.Join(dir, name+".pub.pem")
+
+	require.NoError(t, os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}), 0o600))
+	require.NoError(t, os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: publicDER,
+	}), 0o600))
+
+	return privatePath, publicPath
+}
+
+func newRS256Service(t *testing.T, kid, privatePath string, publicKeys map[string]string) *TokenService {
+	t.Helper()
+
+	ts, err := NewTokenServiceFromConfig(Config{
+		SigningMethod:  SigningMethodRS256,
+		KeyID:          kid,
+		PrivateKeyFile: privatePath,
+		PublicKeyFiles: publicKeys,
+		AccessExpiry:   time.Hour,
+		RefreshExpiry:  24 * time.Hour,
+	})
+	require.NoError(t, err)
+	return ts
+}
+
+func TestTokenService_RS256(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Email: "test@example.com", Role: domain.RoleUser}
+	oldPrivate, oldPublic := writeRSAKey(t, "old")
+	newPrivate, _ := writeRSAKey(t, "new")
+
+	oldService := newRS256Service(t, "key-1", oldPrivate, nil)
+	oldPair, err := oldService.GenerateTokenPair(user)
+	require.NoError(t, err)
+
+	t.Run("токен проверяется ключом, которым подписан", func(t *testing.T) {
+		claims, err := oldService.ValidateToken(oldPair.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, claims.UserID)
+	})
+
+	t.Run("после ротации токены прежнего kid принимаются", func(t *testing.T) {
+		rotated := newRS256Service(t, "key-2", newPrivate, map[string]string{"key-1": oldPublic})
+
+		claims, err := rotated.ValidateToken(oldPair.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, claims.UserID)
+
+		newPair, err := rotated.GenerateTokenPair(user)
+		require.NoError(t, err)
+		_, err = rotated.ValidateToken(newPair.AccessToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("неизвестный kid отклоняется", func(t *testing.T) {
+		rotated := newRS256Service(t, "key-2", newPrivate, nil)
+
+		_, err := rotated.ValidateToken(oldPair.AccessToken)
+		assert.ErrorContains(t, err, "unknown key id")
+	})
+
+	t.Run("HS256 токен не принимается сервисом RS256", func(t *testing.T) {
+		hsPair, err := NewTokenService("secret", time.Hour, 24*time.Hour).GenerateTokenPair(user)
+		require.NoError(t, err)
+
+		_, err = oldService.ValidateToken(hsPair.AccessToken)
+		assert.ErrorContains(t, err, "unexpected signing method")
+	})
+}
+
+func TestNewTokenServiceFromConfig(t *testing.T) {
+	t.Run("по умолчанию HS256", func(t *testing.T) {
+		ts, err := NewTokenServiceFromConfig(Config{SecretKey: "secret", AccessExpiry: time.Hour, RefreshExpiry: time.Hour})
+		require.NoError(t, err)
+
+		pair, err := ts.GenerateTokenPair(&domain.User{ID: uuid.New(), Role: domain.RoleUser})
+		require.NoError(t, err)
+
+		// Совместимо с токенами, выданными NewTokenService с тем же секретом
+		_, err = NewTokenService("secret", time.Hour, time.Hour).ValidateToken(pair.AccessToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("RS256 без kid", func(t *testing.T) {
+		privatePath, _ := writeRSAKey(t, "key")
+		_, err := NewTokenServiceFromConfig(Config{SigningMethod: SigningMethodRS256, PrivateKeyFile: privatePath})
+		assert.Error(t, err)
+	})
+
+	t.Run("неизвестный алгоритм", func(t *testing.T) {
+		_, err := NewTokenServiceFromConfig(Config{SigningMethod: "none"})
+		assert.Error(t, err)
+	})
+}
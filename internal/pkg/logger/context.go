@@ -0,0 +1,29 @@
+package logger
+
+import "context"
+
+// contextKey - тип ключа logger'а в контексте
+type contextKey struct{}
+
+// WithContext сохраняет logger в контексте (например, logger запроса с request_id)
+func WithContext(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext возвращает logger, сохраненный в контексте
+// Если его нет (фоновая задача, тест), возвращается noop logger
+func FromContext(ctx context.Context) Logger {
+	return FromContextOr(ctx, noop)
+}
+
+// FromContextOr возвращает logger из контекста или fallback, если его там нет
+// Сервисы передают свой logger, чтобы не терять записи вне HTTP запроса
+func FromContextOr(ctx context.Context, fallback Logger) Logger {
+	if logger, ok := ctx.Value(contextKey{}).(Logger); ok {
+		return logger
+	}
+	return fallback
+}
+
+// noop - logger по умолчанию для FromContext
+var noop = NewNoop()
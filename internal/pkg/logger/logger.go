@@ -3,11 +3,13 @@ package logger
 import (
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 // Logger - интерфейс для логирования
@@ -25,36 +27,91 @@ type zerologLogger struct {
 	logger zerolog.Logger
 }
 
-// New создает новый logger с заданным уровнем и форматом
-func New(level, format, output string) Logger {
+// Config содержит настройки logger'а
+type Config struct {
+	Level  string
+	Format string // json или console
+	Output string // stdout, stderr или путь к файлу
+
+	// Ротация файла журнала (только при записи в файл)
+	MaxSizeMB  int // Размер файла в мегабайтах, после которого он ротируется
+	MaxBackups int // Сколько ротированных файлов хранить (0 - все)
+	MaxAgeDays int // Сколько дней хранить ротированные файлы (0 - не удалять по возрасту)
+}
+
+// New создает новый logger с заданным уровнем, форматом и выводом
+// Если файл журнала открыть не удалось, logger пишет в stdout и предупреждает об этом
+func New(config Config) Logger {
 	// Парсим уровень логирования
-	logLevel := parseLevel(level)
+	logLevel := parseLevel(config.Level)
 	zerolog.SetGlobalLevel(logLevel)
 
 	// Настраиваем вывод
-	var writer io.Writer
-	if output == "stdout" || output == "" {
-		writer = os.Stdout
-	} else {
-		// Можно добавить запись в файл
+	writer, outputErr := openOutput(config)
+	if outputErr != nil {
 		writer = os.Stdout
 	}
 
 	// Настраиваем формат
-	if format == "console" {
+	if config.Format == "console" {
 		writer = zerolog.ConsoleWriter{
 			Out:        writer,
 			TimeFormat: time.RFC3339,
+			NoColor:    isFileOutput(config.Output) && outputErr == nil,
 		}
 	}
 
-	logger := zerolog.New(writer).
-		With().
-		Timestamp().
-		Caller().
-		Logger()
+	logger := &zerologLogger{
+		logger: zerolog.New(writer).
+			With().
+			Timestamp().
+			Caller().
+			Logger(),
+	}
 
-	return &zerologLogger{logger: logger}
+	if outputErr != nil {
+		logger.Warn("Failed to open log file, falling back to stdout", map[string]interface{}{
+			"output": config.Output,
+			"error":  outputErr.Error(),
+		})
+	}
+
+	return logger
+}
+
+// isFileOutput проверяет, что вывод задан путем к файлу
+func isFileOutput(output string) bool {
+	return output != "" && output != "stdout" && output != "stderr"
+}
+
+// openOutput возвращает writer для вывода журнала
+// Для файла создаются родительские директории; файл ротируется по размеру и возрасту
+func openOutput(config Config) (io.Writer, error) {
+	switch config.Output {
+	case "", "stdout":
+		return os.Stdout, nil
+	case "stderr":
+		return os.Stderr, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(config.Output), 0o755); err != nil {
+		return nil, err
+	}
+
+	// lumberjack открывает файл лениво, при первой записи - проверяем доступ заранее,
+	// чтобы ошибка не терялась молча
+	file, err := os.OpenFile(config.Output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	_ = file.Close()
+
+	return &lumberjack.Logger{
+		Filename:   config.Output,
+		MaxSize:    config.MaxSizeMB,
+		MaxBackups: config.MaxBackups,
+		MaxAge:     config.MaxAgeDays,
+	}, nil
 }
 
 func (l *zerologLogger) Debug(msg string, fields ...map[string]interface{}) {
@@ -130,7 +187,7 @@ func SetGlobalLogger(logger Logger) {
 
 // NewDevelopment creates a logger suitable for development/testing
 func NewDevelopment() Logger {
-	return New("debug", "console", "stdout")
+	return New(Config{Level: "debug", Format: "console", Output: "stdout"})
 }
 
 // NewNoop creates a noop logger that discards all log messages
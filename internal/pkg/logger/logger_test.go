@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readEntries читает JSON записи журнала построчно
+func readEntries(t *testing.T, data string) []map[string]interface{} {
+	t.Helper()
+
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(data), "\n") {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry), line)
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestNew_FileOutput(t *testing.T) {
+	// Родительские директории создаются автоматически
+	path := filepath.Join(t.TempDir(), "logs", "api", "gate.log")
+
+	log := New(Config{Level: "info", Format: "json", Output: path, MaxSizeMB: 1, MaxBackups: 2, MaxAgeDays: 1})
+	log.Info("Server started", map[string]interface{}{"port": "8080"})
+	log.With("request_id", "req-1").Warn("Slow request")
+	log.Debug("Below level")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	entries := readEntries(t, string(data))
+	require.Len(t, entries, 2)
+	assert.Equal(t, "Server started", entries[0]["message"])
+	assert.Equal(t, "info", entries[0]["level"])
+	assert.Equal(t, "8080", entries[0]["port"])
+	assert.Equal(t, "Slow request", entries[1]["message"])
+	assert.Equal(t, "req-1", entries[1]["request_id"])
+}
+
+func TestNew_FileOutputAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gate.log")
+	require.NoError(t, os.WriteFile(path, []byte(`{"level":"info","message":"Previous run"}`+"\n"), 0o644))
+
+	New(Config{Level: "info", Format: "json", Output: path}).Info("Current run")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	entries := readEntries(t, string(data))
+	require.Len(t, entries, 2)
+	assert.Equal(t, "Previous run", entries[0]["message"])
+	assert.Equal(t, "Current run", entries[1]["message"])
+}
+
+func TestNew_FileOutputFallback(t *testing.T) {
+	// Родитель "директории" журнала - обычный файл, создать ее нельзя
+	blocker := filepath.Join(t.TempDir(), "not-a-dir")
+	require.NoError(t, os.WriteFile(blocker, nil, 0o644))
+	path := filepath.Join(blocker, "gate.log")
+
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = writer
+	t.Cleanup(func() { os.Stdout = stdout })
+
+	New(Config{Level: "info", Format: "json", Output: path}).Info("Still logged")
+
+	os.Stdout = stdout
+	require.NoError(t, writer.Close())
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	entries := readEntries(t, string(data))
+	require.Len(t, entries, 2)
+	assert.Equal(t, "warn", entries[0]["level"])
+	assert.Equal(t, "Failed to open log file, falling back to stdout", entries[0]["message"])
+	assert.Equal(t, path, entries[0]["output"])
+	assert.Equal(t, "Still logged", entries[1]["message"])
+	assert.NoFileExists(t, path)
+}
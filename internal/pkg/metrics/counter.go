@@ -0,0 +1,54 @@
+package metrics
+
+import "sync"
+
+// CounterVec - набор потокобезопасных счетчиков, различающихся значением метки
+type CounterVec struct {
+	name   string
+	mu     sync.RWMutex
+	values map[string]int64
+}
+
+// NewCounterVec создает новый набор счетчиков
+func NewCounterVec(name string) *CounterVec {
+	return &CounterVec{
+		name:   name,
+		values: make(map[string]int64),
+	}
+}
+
+// Name возвращает имя набора счетчиков
+func (c *CounterVec) Name() string {
+	return c.name
+}
+
+// Inc увеличивает счетчик для метки на 1
+func (c *CounterVec) Inc(label string) {
+	c.Add(label, 1)
+}
+
+// Add увеличивает счетчик для метки на delta
+func (c *CounterVec) Add(label string, delta int64) {
+	c.mu.Lock()
+	c.values[label] += delta
+	c.mu.Unlock()
+}
+
+// Get возвращает текущее значение счетчика для метки
+func (c *CounterVec) Get(label string) int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.values[label]
+}
+
+// Snapshot возвращает копию всех счетчиков
+func (c *CounterVec) Snapshot() map[string]int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	snapshot := make(map[string]int64, len(c.values))
+	for label, value := range c.values {
+		snapshot[label] = value
+	}
+	return snapshot
+}
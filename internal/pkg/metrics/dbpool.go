@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"github.com/frontandrew/gate/internal/pkg/database"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// dbPoolCollector снимает состояние пула подключений к PostgreSQL в момент сбора метрик
+type dbPoolCollector struct {
+	stats func() database.PoolStats
+
+	maxConns         *prometheus.Desc
+	totalConns       *prometheus.Desc
+	idleConns        *prometheus.Desc
+	acquiredConns    *prometheus.Desc
+	acquires         *prometheus.Desc
+	emptyAcquires    *prometheus.Desc
+	canceledAcquires *prometheus.Desc
+	acquireDuration  *prometheus.Desc
+}
+
+func newDBPoolCollector(stats func() database.PoolStats) *dbPoolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+	}
+	return &dbPoolCollector{
+		stats:            stats,
+		maxConns:         desc("max_conns", "Максимальный размер пула подключений"),
+		totalConns:       desc("total_conns", "Открытых соединений в пуле"),
+		idleConns:        desc("idle_conns", "Свободных соединений в пуле"),
+		acquiredConns:    desc("acquired_conns", "Соединений, занятых запросами"),
+		acquires:         desc("acquires_total", "Получений соединения из пула"),
+		emptyAcquires:    desc("empty_acquires_total", "Получений, которым пришлось ждать свободное соединение"),
+		canceledAcquires: desc("canceled_acquires_total", "Получений, прерванных отменой контекста"),
+		acquireDuration:  desc("acquire_duration_seconds_total", "Суммарное время ожидания соединения"),
+	}
+}
+
+// Describe реализует prometheus.Collector
+func (c *dbPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.maxConns
+	ch <- c.totalConns
+	ch <- c.idleConns
+	ch <- c.acquiredConns
+	ch <- c.acquires
+	ch <- c.emptyAcquires
+	ch <- c.canceledAcquires
+	ch <- c.acquireDuration
+}
+
+// Collect реализует prometheus.Collector
+func (c *dbPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.stats()
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stats.MaxConns))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stats.TotalConns))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stats.IdleConns))
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(stats.AcquiredConns))
+	ch <- prometheus.MustNewConstMetric(c.acquires, prometheus.CounterValue, float64(stats.AcquireCount))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquires, prometheus.CounterValue, float64(stats.EmptyAcquireCount))
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquires, prometheus.CounterValue, float64(stats.CanceledAcquireCount))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, stats.AcquireDuration.Seconds())
+}
+
+// RegisterDBPool регистрирует метрики пула подключений к PostgreSQL (gate_db_pool_*)
+// stats вызывается при каждом сборе метрик
+func (m *Metrics) RegisterDBPool(stats func() database.PoolStats) {
+	if m == nil {
+		return
+	}
+	m.registry.MustRegister(newDBPoolCollector(stats))
+}
@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "gate"
+
+// Metrics - Prometheus метрики приложения
+// Методы безопасно вызывать на nil: метрики отключены (например, в тестах)
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests    *prometheus.CounterVec   // method, route, status
+	httpDuration    *prometheus.HistogramVec // method, route
+	accessDecisions *prometheus.CounterVec   // result, reason
+	mlRequests      *prometheus.CounterVec   // operation, result
+	mlDuration      *prometheus.HistogramVec // operation
+	cacheLookups    *prometheus.CounterVec   // cache, result
+}
+
+// New создает метрики и регистрирует их в registry
+func New(registry *prometheus.Registry) *Metrics {
+	m := &Metrics{
+		registry: registry,
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Количество HTTP запросов по маршруту и статусу ответа",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Время обработки HTTP запроса",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		accessDecisions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "access_decisions_total",
+			Help:      "Решения о доступе по результату и коду причины",
+		}, []string{"result", "reason"}),
+		mlRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "ml_requests_total",
+			Help:      "Вызовы ML сервиса по операции и результату",
+		}, []string{"operation", "result"}),
+		mlDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "ml_request_duration_seconds",
+			Help:      "Время вызова ML сервиса с учетом повторов",
+			Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		}, []string{"operation"}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_lookups_total",
+			Help:      "Обращения к кэшу по имени кэша и результату (hit/miss)",
+		}, []string{"cache", "result"}),
+	}
+
+	registry.MustRegister(
+		m.httpRequests,
+		m.httpDuration,
+		m.accessDecisions,
+		m.mlRequests,
+		m.mlDuration,
+		m.cacheLookups,
+	)
+
+	return m
+}
+
+// Handler возвращает HTTP handler для endpoint /metrics
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// ObserveHTTPRequest учитывает обработанный HTTP запрос
+// route - шаблон маршрута, а не путь, иначе ID в URL раздувают число серий
+func (m *Metrics) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	m.httpDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// ObserveAccessDecision учитывает решение о доступе с кодом причины
+func (m *Metrics) ObserveAccessDecision(granted bool, reason string) {
+	if m == nil {
+		return
+	}
+	result := "denied"
+	if granted {
+		result = "granted"
+	}
+	m.accessDecisions.WithLabelValues(result, reason).Inc()
+}
+
+// ObserveMLRequest учитывает вызов ML сервиса и его длительность
+func (m *Metrics) ObserveMLRequest(operation string, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	m.mlRequests.WithLabelValues(operation, result).Inc()
+	m.mlDuration.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+// ObserveCacheLookup учитывает попадание или промах кэша
+func (m *Metrics) ObserveCacheLookup(cache string, hit bool) {
+	if m == nil {
+		return
+	}
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.cacheLookups.WithLabelValues(cache, result).Inc()
+}
@@ -0,0 +1,136 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/frontandrew/gate/internal/pkg/redis"
+	redisv9 "github.com/redis/go-redis/v9"
+)
+
+// Config содержит параметры ограничения частоты запросов
+type Config struct {
+	Requests int           // Устойчивая скорость: столько запросов за Window
+	Burst    int           // Дополнительный запас сверх Requests для кратковременных всплесков
+	Window   time.Duration // Период, за который восстанавливаются Requests запросов
+}
+
+// tokenBucketScript списывает токен из корзины ключа атомарно для всех инстансов API
+// KEYS[1] - корзина; ARGV: емкость, миллисекунд на токен (0 - без пополнения), текущее время в мс, TTL в мс
+// Возвращает {1 - разрешено / 0 - отклонено, остаток токенов строкой (дробный)}
+var tokenBucketScript = redisv9.NewScript(`
+local capacity = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = capacity
+	ts = now
+end
+
+if interval > 0 and now > ts then
+	tokens = math.min(capacity, tokens + (now - ts) / interval)
+end
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], ARGV[4])
+return {allowed, tostring(tokens)}
+`)
+
+// Limiter ограничивает частоту запросов по ключу корзиной токенов (token bucket) в Redis
+// Корзина вмещает Requests+Burst запросов и пополняется со скоростью Requests за Window:
+// всплеск до Requests+Burst проходит сразу, дальше запросы пропускаются не чаще устойчивой скорости.
+// Корзины общие для всех инстансов API
+type Limiter struct {
+	client *redis.Client
+	prefix string
+	config Config
+
+	now func() time.Time // Источник времени; в тестах подменяется
+}
+
+// Result - результат проверки лимита
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration // Через сколько появится следующий токен (для заголовка Retry-After)
+}
+
+// NewLimiter создает новый limiter; prefix отделяет счетчики разных endpoint'ов
+func NewLimiter(client *redis.Client, prefix string, config Config) *Limiter {
+	return &Limiter{
+		client: client,
+		prefix: prefix,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Allow учитывает запрос и проверяет, укладывается ли он в лимит
+func (l *Limiter) Allow(ctx context.Context, key string) (*Result, error) {
+	capacity := l.config.Requests + l.config.Burst
+	if capacity < 0 {
+		capacity = 0
+	}
+
+	// Интервал пополнения одного токена; без Requests корзина не пополняется
+	var intervalMs float64
+	if l.config.Requests > 0 {
+		intervalMs = float64(l.config.Window.Milliseconds()) / float64(l.config.Requests)
+	}
+
+	// Корзина живет, пока не наполнится заново: дальше она не отличается от новой
+	ttl := l.config.Window
+	if full := time.Duration(math.Ceil(float64(capacity)*intervalMs)) * time.Millisecond; full > ttl {
+		ttl = full
+	}
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+
+	values, err := tokenBucketScript.Run(ctx, l.client.GetClient(), []string{l.prefix + key},
+		capacity,
+		strconv.FormatFloat(intervalMs, 'f', -1, 64),
+		l.now().UnixMilli(),
+		ttl.Milliseconds(),
+	).Slice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to take rate limit token: %w", err)
+	}
+	if len(values) != 2 {
+		return nil, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+
+	allowed, _ := values[0].(int64)
+	tokensStr, _ := values[1].(string)
+	tokens, err := strconv.ParseFloat(tokensStr, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rate limit tokens %q: %w", tokensStr, err)
+	}
+
+	result := &Result{
+		Allowed:   allowed == 1,
+		Remaining: int(tokens),
+	}
+	if tokens < 1 {
+		if intervalMs > 0 {
+			result.RetryAfter = time.Duration(math.Ceil((1-tokens)*intervalMs)) * time.Millisecond
+		} else {
+			result.RetryAfter = l.config.Window
+		}
+	}
+
+	return result, nil
+}
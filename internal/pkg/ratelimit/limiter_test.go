@@ -0,0 +1,94 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/frontandrew/gate/internal/pkg/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLimiter создает limiter поверх miniredis с управляемыми часами
+func newTestLimiter(t *testing.T, config Config) (*Limiter, *time.Time) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	host, port, err := net.SplitHostPort(mr.Addr())
+	require.NoError(t, err)
+
+	client, err := redis.NewClient(redis.Config{Host: host, Port: port})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter := NewLimiter(client, "ratelimit:test:", config)
+	limiter.now = func() time.Time { return now }
+	return limiter, &now
+}
+
+func TestLimiter_BurstThenSteadyRate(t *testing.T) {
+	// 60 запросов в минуту (токен в секунду) и запас в 10 запросов
+	limiter, now := newTestLimiter(t, Config{Requests: 60, Burst: 10, Window: time.Minute})
+	ctx := context.Background()
+
+	// Всплеск до Requests+Burst проходит сразу
+	for i := 0; i < 70; i++ {
+		result, err := limiter.Allow(ctx, "gate-1")
+		require.NoError(t, err)
+		require.True(t, result.Allowed, "request %d", i+1)
+	}
+
+	result, err := limiter.Allow(ctx, "gate-1")
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.Equal(t, time.Second, result.RetryAfter)
+
+	// Устойчивый поток вдвое быстрее скорости: проходит только каждый второй запрос
+	allowed := 0
+	for i := 0; i < 20; i++ {
+		*now = now.Add(500 * time.Millisecond)
+		result, err := limiter.Allow(ctx, "gate-1")
+		require.NoError(t, err)
+		if result.Allowed {
+			allowed++
+		}
+	}
+	assert.Equal(t, 10, allowed)
+
+	// Другой ключ не затронут
+	result, err = limiter.Allow(ctx, "gate-2")
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 69, result.Remaining)
+}
+
+func TestLimiter_NoWindowBoundaryDoubling(t *testing.T) {
+	limiter, now := newTestLimiter(t, Config{Requests: 5, Window: time.Minute})
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		result, err := limiter.Allow(ctx, "ip")
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+	}
+
+	// Через секунду корзина почти пуста: в фиксированном окне здесь открылось бы новое окно
+	*now = now.Add(time.Second)
+	result, err := limiter.Allow(ctx, "ip")
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 11*time.Second, result.RetryAfter)
+
+	// Через полное окно корзина снова полна
+	*now = now.Add(time.Minute)
+	for i := 0; i < 5; i++ {
+		result, err := limiter.Allow(ctx, "ip")
+		require.NoError(t, err)
+		assert.True(t, result.Allowed, "request %d", i+1)
+	}
+}
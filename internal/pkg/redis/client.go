@@ -57,11 +57,22 @@ func (c *Client) Set(ctx context.Context, key string, value interface{}, ttl tim
 	return c.client.Set(ctx, key, value, ttl).Err()
 }
 
+// SetNX устанавливает значение с TTL, только если ключа еще нет
+// Возвращает true, если значение было установлено
+func (c *Client) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return c.client.SetNX(ctx, key, value, ttl).Result()
+}
+
 // Get получает значение по ключу
 func (c *Client) Get(ctx context.Context, key string) (string, error) {
 	return c.client.Get(ctx, key).Result()
 }
 
+// GetDel атомарно получает значение и удаляет ключ
+func (c *Client) GetDel(ctx context.Context, key string) (string, error) {
+	return c.client.GetDel(ctx, key).Result()
+}
+
 // Del удаляет ключ
 func (c *Client) Del(ctx context.Context, keys ...string) error {
 	return c.client.Del(ctx, keys...).Err()
@@ -82,6 +93,21 @@ func (c *Client) Incr(ctx context.Context, key string) (int64, error) {
 	return c.client.Incr(ctx, key).Result()
 }
 
+// HSet устанавливает поле хеша
+func (c *Client) HSet(ctx context.Context, key, field string, value interface{}) error {
+	return c.client.HSet(ctx, key, field, value).Err()
+}
+
+// HGet получает значение поля хеша
+func (c *Client) HGet(ctx context.Context, key, field string) (string, error) {
+	return c.client.HGet(ctx, key, field).Result()
+}
+
+// HExists проверяет наличие поля хеша
+func (c *Client) HExists(ctx context.Context, key, field string) (bool, error) {
+	return c.client.HExists(ctx, key, field).Result()
+}
+
 // Close закрывает подключение
 func (c *Client) Close() error {
 	return c.client.Close()
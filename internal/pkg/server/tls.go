@@ -0,0 +1,47 @@
+package server
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+
+	"github.com/frontandrew/gate/internal/pkg/config"
+)
+
+// NewTLSConfig создает TLS конфигурацию сервера: минимум TLS 1.2 и только AEAD шифры
+// Для TLS 1.3 набор шифров не настраивается и выбирается Go автоматически
+func NewTLSConfig(cfg *config.ServerConfig) *tls.Config {
+	return &tls.Config{
+		MinVersion: cfg.TLSMinVersion(),
+		CurvePreferences: []tls.CurveID{
+			tls.X25519,
+			tls.CurveP256,
+		},
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+		},
+	}
+}
+
+// RedirectToHTTPS возвращает handler, перенаправляющий HTTP запросы на HTTPS порт
+func RedirectToHTTPS(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = h
+		}
+
+		target := "https://" + host
+		if httpsPort != "" && httpsPort != "443" {
+			target = "https://" + net.JoinHostPort(host, httpsPort)
+		}
+		target += r.URL.RequestURI()
+
+		http.Redirect(w, r, target, http.StatusMovedPermanently)
+	})
+}
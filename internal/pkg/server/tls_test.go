@@ -0,0 +1,65 @@
+package server
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/frontandrew/gate/internal/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTLSConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		minVersion string
+		expected   uint16
+	}{
+		{name: "по умолчанию TLS 1.2", minVersion: "", expected: tls.VersionTLS12},
+		{name: "явно TLS 1.3", minVersion: "1.3", expected: tls.VersionTLS13},
+		{name: "версия ниже 1.2 не допускается", minVersion: "1.0", expected: tls.VersionTLS12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.ServerConfig{
+				TLSCertFile:      "cert.pem",
+				TLSKeyFile:       "key.pem",
+				TLSMinVersionStr: tt.minVersion,
+			}
+
+			tlsConfig := NewTLSConfig(cfg)
+
+			assert.Equal(t, tt.expected, tlsConfig.MinVersion)
+			assert.NotEmpty(t, tlsConfig.CipherSuites)
+			for _, suite := range tls.InsecureCipherSuites() {
+				assert.NotContains(t, tlsConfig.CipherSuites, suite.ID)
+			}
+		})
+	}
+}
+
+func TestRedirectToHTTPS(t *testing.T) {
+	tests := []struct {
+		name      string
+		httpsPort string
+		url       string
+		expected  string
+	}{
+		{name: "стандартный порт", httpsPort: "443", url: "http://gate.local:8081/api/v1/passes?limit=10", expected: "https://gate.local/api/v1/passes?limit=10"},
+		{name: "нестандартный порт", httpsPort: "8443", url: "http://gate.local/health", expected: "https://gate.local:8443/health"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			w := httptest.NewRecorder()
+
+			RedirectToHTTPS(tt.httpsPort).ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusMovedPermanently, w.Code)
+			assert.Equal(t, tt.expected, w.Header().Get("Location"))
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Group запускает фоновые задачи с общим контекстом и при остановке дожидается их завершения
+// Задача должна вернуться после отмены ctx; начатую единицу работы (доставку, проход по БД)
+// она может довести до конца, но не брать новую
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	running map[string]int // Имена незавершенных задач - для лога при истечении таймаута
+}
+
+// NewGroup создает группу задач; отмена parent останавливает их так же, как Shutdown
+func NewGroup(parent context.Context) *Group {
+	ctx, cancel := context.WithCancel(parent)
+	return &Group{
+		ctx:     ctx,
+		cancel:  cancel,
+		running: make(map[string]int),
+	}
+}
+
+// Go запускает задачу run в отдельной goroutine; name используется в ошибке Shutdown
+func (g *Group) Go(name string, run func(ctx context.Context)) {
+	g.mu.Lock()
+	g.running[name]++
+	g.mu.Unlock()
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer g.finish(name)
+		run(g.ctx)
+	}()
+}
+
+// Shutdown отменяет контекст задач и ждет их завершения, пока не истечет ctx
+// Если задачи не успели завершиться, возвращает ошибку с их именами
+func (g *Group) Shutdown(ctx context.Context) error {
+	g.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("workers did not stop in time: %s", g.runningNames())
+	}
+}
+
+func (g *Group) finish(name string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.running[name]--
+	if g.running[name] == 0 {
+		delete(g.running, name)
+	}
+}
+
+func (g *Group) runningNames() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	names := make([]string, 0, len(g.running))
+	for name := range g.running {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroup_Shutdown(t *testing.T) {
+	t.Run("задачи видят отмену и завершаются", func(t *testing.T) {
+		group := NewGroup(context.Background())
+
+		var stopped int32
+		for _, name := range []string{"pass-expiry", "webhooks"} {
+			group.Go(name, func(ctx context.Context) {
+				<-ctx.Done()
+				atomic.AddInt32(&stopped, 1)
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		require.NoError(t, group.Shutdown(ctx))
+		assert.Equal(t, int32(2), atomic.LoadInt32(&stopped), "Shutdown возвращается только после выхода всех задач")
+	})
+
+	t.Run("начатая работа доводится до конца", func(t *testing.T) {
+		group := NewGroup(context.Background())
+		started := make(chan struct{})
+
+		var finished int32
+		group.Go("webhooks", func(ctx context.Context) {
+			close(started)
+			<-ctx.Done()
+			// Завершение текущей доставки после сигнала остановки
+			time.Sleep(20 * time.Millisecond)
+			atomic.StoreInt32(&finished, 1)
+		})
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		require.NoError(t, group.Shutdown(ctx))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&finished))
+	})
+
+	t.Run("зависшая задача не блокирует остановку дольше таймаута", func(t *testing.T) {
+		group := NewGroup(context.Background())
+		release := make(chan struct{})
+		defer close(release)
+
+		group.Go("quick", func(ctx context.Context) { <-ctx.Done() })
+		group.Go("stuck", func(ctx context.Context) { <-release })
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := group.Shutdown(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "stuck")
+		assert.NotContains(t, err.Error(), "quick", "завершившиеся задачи не упоминаются")
+	})
+
+	t.Run("без задач", func(t *testing.T) {
+		group := NewGroup(context.Background())
+		assert.NoError(t, group.Shutdown(context.Background()))
+	})
+}
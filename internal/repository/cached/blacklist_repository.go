@@ -6,70 +6,101 @@ import (
 	"time"
 
 	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/pkg/metrics"
 	"github.com/frontandrew/gate/internal/pkg/redis"
 	"github.com/frontandrew/gate/internal/repository"
 	"github.com/google/uuid"
-	redisv9 "github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
 )
 
 const (
 	blacklistCachePrefix = "blacklist:"
 	blacklistCacheTTL    = 1 * time.Hour
+
+	// blacklistWarmBatchSize - размер страницы при прогреве кэша
+	blacklistWarmBatchSize = 100
 )
 
+// blacklistCacheKey - ключ кэша номера; номер нормализуется, чтобы проверка распознанного номера
+// и инвалидация после изменения записи попадали в один ключ независимо от раскладки
+func blacklistCacheKey(licensePlate string) string {
+	return blacklistCachePrefix + domain.NormalizeLicensePlate(licensePlate)
+}
+
 // BlacklistRepository добавляет кэширование к blacklist repository
 type BlacklistRepository struct {
-	repo  repository.BlacklistRepository
-	cache *redis.Client
+	repo    repository.BlacklistRepository
+	cache   *redis.Client
+	metrics *metrics.Metrics
+	logger  logger.Logger
+
+	lookups singleflight.Group // Дедупликация запросов в БД по ключу кэша
 }
 
 // NewBlacklistRepository создает новый кэшируемый blacklist repository
-func NewBlacklistRepository(repo repository.BlacklistRepository, cache *redis.Client) *BlacklistRepository {
+func NewBlacklistRepository(repo repository.BlacklistRepository, cache *redis.Client, metrics *metrics.Metrics, logger logger.Logger) *BlacklistRepository {
 	return &BlacklistRepository{
-		repo:  repo,
-		cache: cache,
+		repo:    repo,
+		cache:   cache,
+		metrics: metrics,
+		logger:  logger,
 	}
 }
 
-// IsBlacklisted проверяет, находится ли номер в blacklist (с кэшированием)
-func (r *BlacklistRepository) IsBlacklisted(ctx context.Context, licensePlate string) (bool, string, error) {
-	// Формируем ключ кэша
-	cacheKey := blacklistCachePrefix + licensePlate
+// IsBlacklisted проверяет, находится ли номер в blacklist для зоны ворот (с кэшированием)
+func (r *BlacklistRepository) IsBlacklisted(ctx context.Context, licensePlate, zone string) (bool, string, error) {
+	// Формируем ключ кэша: хеш номера с полем на каждую зону
+	cacheKey := blacklistCacheKey(licensePlate)
+	field := zoneCacheField(zone)
 
 	// 1. Проверяем кэш
-	cached, err := r.cache.Get(ctx, cacheKey)
+	cached, err := r.cache.HGet(ctx, cacheKey, field)
 	if err == nil {
 		// Cache hit - парсим формат "0:" или "1:reason"
 		parts := strings.SplitN(cached, ":", 2)
 		if len(parts) == 2 {
 			inBlacklist := parts[0] == "1"
 			reason := parts[1]
+			r.metrics.ObserveCacheLookup("blacklist", true)
 			return inBlacklist, reason, nil
 		}
 	}
 
-	// Если ошибка не redis.Nil (ключ не найден), то это реальная ошибка
-	if err != redisv9.Nil {
-		// Логируем ошибку кэша, но продолжаем работу с БД
-		// В production здесь можно добавить метрику
-	}
+	// Промах (redis.Nil) - штатная ситуация, остальные ошибки логируем и идем в БД
+	logCacheError(r.logger, "hget", cacheKey, err)
 
 	// 2. Cache miss - идем в БД
-	inBlacklist, reason, err := r.repo.IsBlacklisted(ctx, licensePlate)
+	// Параллельные промахи по одному номеру (холодный кэш, новый номер) разделяют один запрос
+	r.metrics.ObserveCacheLookup("blacklist", false)
+	result, err, _ := r.lookups.Do(cacheKey+"|"+field, func() (interface{}, error) {
+		inBlacklist, reason, err := r.repo.IsBlacklisted(ctx, licensePlate, zone)
+		if err != nil {
+			return nil, err
+		}
+
+		// 3. Сохраняем результат в кэш (формат: "0:" или "1:reason")
+		cacheValue := "0:"
+		if inBlacklist {
+			cacheValue = "1:" + reason
+		}
+
+		// Ошибка записи в кэш не критична для ответа
+		// TTL общий для всех зон номера и отсчитывается от последнего промаха
+		if err := r.cache.HSet(ctx, cacheKey, field, cacheValue); err != nil {
+			logCacheError(r.logger, "hset", cacheKey, err)
+		} else {
+			logCacheError(r.logger, "expire", cacheKey, r.cache.Expire(ctx, cacheKey, blacklistCacheTTL))
+		}
+
+		return listLookup{listed: inBlacklist, reason: reason}, nil
+	})
 	if err != nil {
 		return false, "", err
 	}
 
-	// 3. Сохраняем результат в кэш (формат: "0:" или "1:reason")
-	cacheValue := "0:"
-	if inBlacklist {
-		cacheValue = "1:" + reason
-	}
-
-	// Игнорируем ошибку записи в кэш (не критично)
-	_ = r.cache.Set(ctx, cacheKey, cacheValue, blacklistCacheTTL)
-
-	return inBlacklist, reason, nil
+	lookup := result.(listLookup)
+	return lookup.listed, lookup.reason, nil
 }
 
 // Create добавляет запись в blacklist и инвалидирует кэш
@@ -80,22 +111,41 @@ func (r *BlacklistRepository) Create(ctx context.Context, entry *domain.Blacklis
 	}
 
 	// Инвалидируем кэш для этого номера
-	cacheKey := blacklistCachePrefix + entry.LicensePlate
-	_ = r.cache.Del(ctx, cacheKey)
+	cacheKey := blacklistCacheKey(entry.LicensePlate)
+	logCacheError(r.logger, "del", cacheKey, r.cache.Del(ctx, cacheKey))
 
 	return nil
 }
 
+// CreateBatch добавляет записи в blacklist и инвалидирует кэш созданных номеров
+func (r *BlacklistRepository) CreateBatch(ctx context.Context, entries []*domain.BlacklistEntry) ([]error, error) {
+	rowErrors, err := r.repo.CreateBatch(ctx, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	// В кэше мог остаться отрицательный результат ("0:") для только что добавленных номеров
+	for i, entry := range entries {
+		if rowErrors[i] != nil {
+			continue
+		}
+		cacheKey := blacklistCacheKey(entry.LicensePlate)
+		logCacheError(r.logger, "del", cacheKey, r.cache.Del(ctx, cacheKey))
+	}
+
+	return rowErrors, nil
+}
+
 // GetByID получает запись по ID
 func (r *BlacklistRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.BlacklistEntry, error) {
 	// Для полных данных не кэшируем - используется редко
 	return r.repo.GetByID(ctx, id)
 }
 
-// GetByLicensePlate получает запись по номеру
-func (r *BlacklistRepository) GetByLicensePlate(ctx context.Context, licensePlate string) (*domain.BlacklistEntry, error) {
+// GetByLicensePlate получает запись по номеру в зоне
+func (r *BlacklistRepository) GetByLicensePlate(ctx context.Context, licensePlate string, zone *string) (*domain.BlacklistEntry, error) {
 	// Для полных данных не кэшируем - используется редко
-	return r.repo.GetByLicensePlate(ctx, licensePlate)
+	return r.repo.GetByLicensePlate(ctx, licensePlate, zone)
 }
 
 // Update обновляет запись и инвалидирует кэш
@@ -106,8 +156,8 @@ func (r *BlacklistRepository) Update(ctx context.Context, entry *domain.Blacklis
 	}
 
 	// Инвалидируем кэш для этого номера
-	cacheKey := blacklistCachePrefix + entry.LicensePlate
-	_ = r.cache.Del(ctx, cacheKey)
+	cacheKey := blacklistCacheKey(entry.LicensePlate)
+	logCacheError(r.logger, "del", cacheKey, r.cache.Del(ctx, cacheKey))
 
 	return nil
 }
@@ -120,16 +170,21 @@ func (r *BlacklistRepository) List(ctx context.Context, limit, offset int) ([]*d
 
 // Delete удаляет запись и инвалидирует кэш
 func (r *BlacklistRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	// Номер нужен для ключа кэша, а Delete принимает только ID:
+	// без инвалидации снятый с блокировки номер отклонялся бы до истечения TTL
+	entry, err := r.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
 	// Удаляем из БД
 	if err := r.repo.Delete(ctx, id); err != nil {
 		return err
 	}
 
-	// Примечание: мы не можем точно инвалидировать кэш по license_plate,
-	// так как Delete принимает только ID. Кэш истечет через TTL (1 час).
-	// Альтернатива: можно было бы сначала получить entry, запомнить license_plate,
-	// затем удалить и инвалидировать кэш. Но это добавляет лишний запрос к БД.
-	// Поскольку Delete вызывается редко, текущий подход приемлем.
+	// Инвалидируем кэш для этого номера
+	cacheKey := blacklistCacheKey(entry.LicensePlate)
+	logCacheError(r.logger, "del", cacheKey, r.cache.Del(ctx, cacheKey))
 
 	return nil
 }
@@ -140,3 +195,55 @@ func (r *BlacklistRepository) GetExpired(ctx context.Context) ([]*domain.Blackli
 	// Кэш для GetExpired не используем, так как это административная операция
 	return r.repo.GetExpired(ctx)
 }
+
+// Warm загружает действующие записи blacklist в кэш (не более maxEntries)
+// Используется при старте приложения, чтобы первые проверки доступа не шли в БД
+// Возвращает количество закэшированных номеров
+func (r *BlacklistRepository) Warm(ctx context.Context, maxEntries int) (int, error) {
+	warmed := 0
+	offset := 0
+
+	for warmed < maxEntries {
+		entries, err := r.repo.List(ctx, blacklistWarmBatchSize, offset)
+		if err != nil {
+			return warmed, err
+		}
+
+		for _, entry := range entries {
+			// Неактивные и истекшие записи не кэшируем - IsWhitelisted/IsBlacklisted для них вернет false
+			if !entry.IsValid() {
+				continue
+			}
+
+			// Запись не должна жить в кэше дольше, чем действует сама
+			ttl := blacklistCacheTTL
+			if entry.ExpiresAt != nil {
+				if untilExpiry := time.Until(*entry.ExpiresAt); untilExpiry < ttl {
+					ttl = untilExpiry
+				}
+			}
+
+			// Запись прогревается в поле своей зоны; запись без зоны - в поле ворот без зоны,
+			// ворота зон получат ее из БД при первом промахе
+			cacheKey := blacklistCacheKey(entry.LicensePlate)
+			if err := r.cache.HSet(ctx, cacheKey, entryCacheField(entry.Zone), "1:"+entry.Reason); err != nil {
+				return warmed, err
+			}
+			if err := r.cache.Expire(ctx, cacheKey, ttl); err != nil {
+				return warmed, err
+			}
+
+			warmed++
+			if warmed >= maxEntries {
+				break
+			}
+		}
+
+		if len(entries) < blacklistWarmBatchSize {
+			break
+		}
+		offset += blacklistWarmBatchSize
+	}
+
+	return warmed, nil
+}
@@ -0,0 +1,225 @@
+package cached
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/repository/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlacklistRepository_Warm(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name         string
+		entries      []*domain.BlacklistEntry
+		maxEntries   int
+		expectWarmed int
+		cached       []string
+		notCached    []string
+	}{
+		{
+			name: "действующие записи попадают в кэш",
+			entries: []*domain.BlacklistEntry{
+				{ID: uuid.New(), LicensePlate: "А001АА777", Reason: "Угон", IsActive: true},
+				{ID: uuid.New(), LicensePlate: "В002ВВ777", Reason: "Нарушитель", IsActive: true},
+			},
+			maxEntries:   10,
+			expectWarmed: 2,
+			cached:       []string{"А001АА777", "В002ВВ777"},
+		},
+		{
+			name: "неактивные и истекшие записи пропускаются",
+			entries: []*domain.BlacklistEntry{
+				{ID: uuid.New(), LicensePlate: "А001АА777", Reason: "Угон", IsActive: true},
+				{ID: uuid.New(), LicensePlate: "С003СС777", Reason: "Отключен", IsActive: false},
+				{ID: uuid.New(), LicensePlate: "Е004ЕЕ777", Reason: "Истек", IsActive: true, ExpiresAt: &past},
+			},
+			maxEntries:   10,
+			expectWarmed: 1,
+			cached:       []string{"А001АА777"},
+			notCached:    []string{"С003СС777", "Е004ЕЕ777"},
+		},
+		{
+			name: "соблюдается лимит записей",
+			entries: []*domain.BlacklistEntry{
+				{ID: uuid.New(), LicensePlate: "А001АА777", Reason: "Угон", IsActive: true},
+				{ID: uuid.New(), LicensePlate: "В002ВВ777", Reason: "Нарушитель", IsActive: true},
+			},
+			maxEntries:   1,
+			expectWarmed: 1,
+			cached:       []string{"А001АА777"},
+			notCached:    []string{"В002ВВ777"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache, mr := newTestRedis(t)
+
+			repo := new(mocks.MockBlacklistRepository)
+			repo.On("List", context.Background(), blacklistWarmBatchSize, 0).Return(tt.entries, nil)
+
+			cachedRepo := NewBlacklistRepository(repo, cache, nil, logger.NewNoop())
+			warmed, err := cachedRepo.Warm(context.Background(), tt.maxEntries)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectWarmed, warmed)
+
+			for _, plate := range tt.cached {
+				value := mr.HGet(blacklistCacheKey(plate), globalZoneField)
+				assert.Contains(t, value, "1:")
+			}
+			for _, plate := range tt.notCached {
+				assert.False(t, mr.Exists(blacklistCacheKey(plate)))
+			}
+
+			// После прогрева проверка не должна обращаться к БД
+			for _, plate := range tt.cached {
+				ok, _, err := cachedRepo.IsBlacklisted(context.Background(), plate, "")
+				require.NoError(t, err)
+				assert.True(t, ok)
+			}
+			repo.AssertNotCalled(t, "IsBlacklisted", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestBlacklistRepository_Delete_InvalidatesCache(t *testing.T) {
+	t.Run("кэш номера удаляется вместе с записью", func(t *testing.T) {
+		cache, mr := newTestRedis(t)
+		entryID := uuid.New()
+		mr.HSet(blacklistCachePrefix+"A123BC777", globalZoneField, "1:Угон")
+		mr.HSet(blacklistCachePrefix+"B456CD777", globalZoneField, "1:Долг")
+
+		repo := new(mocks.MockBlacklistRepository)
+		repo.On("GetByID", mock.Anything, entryID).
+			Return(&domain.BlacklistEntry{ID: entryID, LicensePlate: "A123BC777", IsActive: true}, nil)
+		repo.On("Delete", mock.Anything, entryID).Return(nil)
+
+		cachedRepo := NewBlacklistRepository(repo, cache, nil, logger.NewNoop())
+		require.NoError(t, cachedRepo.Delete(context.Background(), entryID))
+
+		assert.False(t, mr.Exists(blacklistCachePrefix+"A123BC777"))
+		assert.True(t, mr.Exists(blacklistCachePrefix+"B456CD777"), "кэш других номеров не затрагивается")
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("ошибка удаления из БД сохраняет кэш", func(t *testing.T) {
+		cache, mr := newTestRedis(t)
+		entryID := uuid.New()
+		mr.HSet(blacklistCachePrefix+"A123BC777", globalZoneField, "1:Угон")
+
+		repo := new(mocks.MockBlacklistRepository)
+		repo.On("GetByID", mock.Anything, entryID).
+			Return(&domain.BlacklistEntry{ID: entryID, LicensePlate: "A123BC777", IsActive: true}, nil)
+		repo.On("Delete", mock.Anything, entryID).Return(assert.AnError)
+
+		cachedRepo := NewBlacklistRepository(repo, cache, nil, logger.NewNoop())
+		assert.ErrorIs(t, cachedRepo.Delete(context.Background(), entryID), assert.AnError)
+
+		assert.True(t, mr.Exists(blacklistCachePrefix+"A123BC777"))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("несуществующая запись", func(t *testing.T) {
+		cache, _ := newTestRedis(t)
+		entryID := uuid.New()
+
+		repo := new(mocks.MockBlacklistRepository)
+		repo.On("GetByID", mock.Anything, entryID).Return(nil, domain.ErrBlacklistEntryNotFound)
+
+		cachedRepo := NewBlacklistRepository(repo, cache, nil, logger.NewNoop())
+		assert.ErrorIs(t, cachedRepo.Delete(context.Background(), entryID), domain.ErrBlacklistEntryNotFound)
+
+		repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+// Номер латиницей и кириллицей - один номер: повторная проверка в другой раскладке идет из кэша
+func TestBlacklistRepository_IsBlacklisted_LatinCyrillic(t *testing.T) {
+	cache, _ := newTestRedis(t)
+
+	repo := new(mocks.MockBlacklistRepository)
+	repo.On("IsBlacklisted", mock.Anything, "А123ВС777", "").Return(true, "Угон", nil).Once()
+
+	cachedRepo := NewBlacklistRepository(repo, cache, nil, logger.NewNoop())
+	for _, plate := range []string{"А123ВС777", "A123BC777", "a123bc777"} {
+		inBlacklist, reason, err := cachedRepo.IsBlacklisted(context.Background(), plate, "")
+		require.NoError(t, err)
+		assert.True(t, inBlacklist, plate)
+		assert.Equal(t, "Угон", reason)
+	}
+
+	repo.AssertExpectations(t)
+}
+
+func TestBlacklistRepository_IsBlacklisted_ConcurrentMisses(t *testing.T) {
+	cache, _ := newTestRedis(t)
+	const lookups = 20
+
+	// Запрос в БД держится, пока все проверки не промахнутся мимо кэша
+	release := make(chan struct{})
+	repo := new(mocks.MockBlacklistRepository)
+	repo.On("IsBlacklisted", mock.Anything, "A123BC777", "").
+		Run(func(args mock.Arguments) { <-release }).
+		Return(true, "Угон", nil).Once()
+	repo.On("IsBlacklisted", mock.Anything, "B456CD777", "").Return(false, "", nil).Once()
+
+	cachedRepo := NewBlacklistRepository(repo, cache, nil, logger.NewNoop())
+
+	var wg sync.WaitGroup
+	for i := 0; i < lookups; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			inBlacklist, reason, err := cachedRepo.IsBlacklisted(context.Background(), "A123BC777", "")
+			assert.NoError(t, err)
+			assert.True(t, inBlacklist)
+			assert.Equal(t, "Угон", reason)
+		}()
+	}
+
+	// Другой номер не ждет зависший запрос
+	inBlacklist, _, err := cachedRepo.IsBlacklisted(context.Background(), "B456CD777", "")
+	require.NoError(t, err)
+	assert.False(t, inBlacklist)
+
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	repo.AssertNumberOfCalls(t, "IsBlacklisted", 2)
+	repo.AssertExpectations(t)
+}
+
+func TestBlacklistRepository_CreateBatch_InvalidatesCache(t *testing.T) {
+	cache, mr := newTestRedis(t)
+	// Отрицательный результат для нового номера и кэш существующей записи
+	mr.HSet(blacklistCachePrefix+"A123BC777", globalZoneField, "0:")
+	mr.HSet(blacklistCachePrefix+"B456CD777", globalZoneField, "1:Долг")
+
+	entries := []*domain.BlacklistEntry{
+		{LicensePlate: "A123BC777", Reason: "Угон", IsActive: true},
+		{LicensePlate: "B456CD777", Reason: "Повтор", IsActive: true},
+	}
+	repo := new(mocks.MockBlacklistRepository)
+	repo.On("CreateBatch", mock.Anything, entries).
+		Return([]error{nil, domain.ErrBlacklistEntryAlreadyExists}, nil)
+
+	cachedRepo := NewBlacklistRepository(repo, cache, nil, logger.NewNoop())
+	rowErrors, err := cachedRepo.CreateBatch(context.Background(), entries)
+
+	require.NoError(t, err)
+	assert.Equal(t, []error{nil, domain.ErrBlacklistEntryAlreadyExists}, rowErrors)
+	assert.False(t, mr.Exists(blacklistCachePrefix+"A123BC777"), "созданный номер не должен читаться из устаревшего кэша")
+	assert.True(t, mr.Exists(blacklistCachePrefix+"B456CD777"), "кэш дубликата не затрагивается")
+}
@@ -0,0 +1,23 @@
+package cached
+
+import (
+	"errors"
+
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	redisv9 "github.com/redis/go-redis/v9"
+)
+
+// logCacheError сообщает об ошибке Redis, после которой repository продолжает работу с БД
+// Промах кэша (redis.Nil) ошибкой не считается; недоступность Redis не должна оставаться незамеченной,
+// иначе вся нагрузка молча уходит в БД
+func logCacheError(log logger.Logger, operation, key string, err error) {
+	if err == nil || errors.Is(err, redisv9.Nil) {
+		return
+	}
+
+	log.Warn("Cache backend error, falling back to database", map[string]interface{}{
+		"operation": operation,
+		"key":       key,
+		"error":     err.Error(),
+	})
+}
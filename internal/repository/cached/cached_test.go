@@ -0,0 +1,50 @@
+package cached
+
+import (
+	"net"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/pkg/redis"
+)
+
+// newTestRedis поднимает in-memory Redis и возвращает клиент к нему
+func newTestRedis(t *testing.T) (*redis.Client, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+
+	host, port, err := net.SplitHostPort(mr.Addr())
+	if err != nil {
+		t.Fatalf("failed to parse miniredis address: %v", err)
+	}
+
+	client, err := redis.NewClient(redis.Config{Host: host, Port: port})
+	if err != nil {
+		t.Fatalf("failed to connect to miniredis: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	return client, mr
+}
+
+// recordingLogger запоминает поля предупреждений для проверок
+type recordingLogger struct {
+	logger.Logger
+	warnings []map[string]interface{}
+}
+
+func newRecordingLogger() *recordingLogger {
+	return &recordingLogger{Logger: logger.NewNoop()}
+}
+
+func (l *recordingLogger) Warn(msg string, fields ...map[string]interface{}) {
+	entry := map[string]interface{}{"msg": msg}
+	for _, f := range fields {
+		for k, v := range f {
+			entry[k] = v
+		}
+	}
+	l.warnings = append(l.warnings, entry)
+}
@@ -0,0 +1,30 @@
+package cached
+
+// listLookup - результат проверки номера по белому или черному списку,
+// разделяемый между параллельными промахами кэша
+type listLookup struct {
+	listed bool
+	reason string
+}
+
+// globalZoneField - поле хеша кэша для ворот без зоны
+// Имя зоны не может содержать "*", поэтому поле не пересекается с зонами
+const globalZoneField = "*"
+
+// zoneCacheField возвращает поле хеша кэша списка для зоны ворот
+// Результаты проверок номера по всем зонам хранятся в одном хеше:
+// удаление ключа при изменении записи сбрасывает их разом
+func zoneCacheField(zone string) string {
+	if zone == "" {
+		return globalZoneField
+	}
+	return zone
+}
+
+// entryCacheField возвращает поле хеша, в которое прогревается запись с зоной scope
+func entryCacheField(scope *string) string {
+	if scope == nil {
+		return globalZoneField
+	}
+	return *scope
+}
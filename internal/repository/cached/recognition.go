@@ -0,0 +1,68 @@
+package cached
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/frontandrew/gate/internal/infrastructure/ml"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/pkg/metrics"
+	"github.com/frontandrew/gate/internal/pkg/redis"
+)
+
+const (
+	recognitionPrefix = "recognition:"
+)
+
+// RecognitionCache кэширует результат распознавания по хешу изображения
+// Камеры повторно присылают один и тот же кадр (например, стоящий у шлагбаума автомобиль),
+// и повторное распознавание только нагружает ML сервис
+type RecognitionCache struct {
+	cache   *redis.Client
+	metrics *metrics.Metrics
+	logger  logger.Logger
+	ttl     time.Duration
+}
+
+// NewRecognitionCache создает кэш; ttl должен быть коротким - это защита от повторов, а не хранилище
+func NewRecognitionCache(cache *redis.Client, metrics *metrics.Metrics, logger logger.Logger, ttl time.Duration) *RecognitionCache {
+	return &RecognitionCache{
+		cache:   cache,
+		metrics: metrics,
+		logger:  logger,
+		ttl:     ttl,
+	}
+}
+
+// Get возвращает закэшированный результат распознавания изображения
+// Ошибка Redis или поврежденное значение трактуются как промах кэша
+func (c *RecognitionCache) Get(ctx context.Context, imageHash string) (*ml.RecognitionResult, bool) {
+	key := recognitionPrefix + imageHash
+	data, err := c.cache.Get(ctx, key)
+	if err != nil {
+		logCacheError(c.logger, "get", key, err)
+		c.metrics.ObserveCacheLookup("recognition", false)
+		return nil, false
+	}
+
+	var result ml.RecognitionResult
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		logCacheError(c.logger, "unmarshal", key, err)
+		c.metrics.ObserveCacheLookup("recognition", false)
+		return nil, false
+	}
+	c.metrics.ObserveCacheLookup("recognition", true)
+	return &result, true
+}
+
+// Set запоминает результат распознавания изображения на ttl
+func (c *RecognitionCache) Set(ctx context.Context, imageHash string, result *ml.RecognitionResult) {
+	key := recognitionPrefix + imageHash
+	data, err := json.Marshal(result)
+	if err != nil {
+		logCacheError(c.logger, "marshal", key, err)
+		return
+	}
+	logCacheError(c.logger, "set", key, c.cache.Set(ctx, key, data, c.ttl))
+}
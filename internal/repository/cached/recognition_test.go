@@ -0,0 +1,64 @@
+package cached
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/frontandrew/gate/internal/infrastructure/ml"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecognitionCache(t *testing.T) {
+	ctx := context.Background()
+	result := &ml.RecognitionResult{
+		Success:      true,
+		LicensePlate: "A123BC777",
+		Confidence:   0.95,
+		BoundingBox:  &ml.BoundingBox{X: 10, Y: 20, Width: 120, Height: 40},
+		ModelVersion: "easyocr-1.7",
+	}
+
+	t.Run("результат возвращается по хешу кадра", func(t *testing.T) {
+		client, _ := newTestRedis(t)
+		cache := NewRecognitionCache(client, nil, logger.NewNoop(), 5*time.Second)
+
+		_, ok := cache.Get(ctx, "hash-1")
+		assert.False(t, ok)
+
+		cache.Set(ctx, "hash-1", result)
+
+		cached, ok := cache.Get(ctx, "hash-1")
+		require.True(t, ok)
+		assert.Equal(t, result, cached)
+
+		_, ok = cache.Get(ctx, "hash-2")
+		assert.False(t, ok)
+	})
+
+	t.Run("запись истекает по TTL", func(t *testing.T) {
+		client, mr := newTestRedis(t)
+		cache := NewRecognitionCache(client, nil, logger.NewNoop(), 5*time.Second)
+
+		cache.Set(ctx, "hash-1", result)
+		mr.FastForward(6 * time.Second)
+
+		_, ok := cache.Get(ctx, "hash-1")
+		assert.False(t, ok)
+	})
+
+	t.Run("недоступный Redis - промах кэша", func(t *testing.T) {
+		client, mr := newTestRedis(t)
+		log := newRecordingLogger()
+		cache := NewRecognitionCache(client, nil, log, 5*time.Second)
+		mr.Close()
+
+		cache.Set(ctx, "hash-1", result)
+		_, ok := cache.Get(ctx, "hash-1")
+
+		assert.False(t, ok)
+		assert.NotEmpty(t, log.warnings)
+	})
+}
@@ -0,0 +1,73 @@
+package cached
+
+import (
+	"context"
+	"time"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/pkg/metrics"
+	"github.com/frontandrew/gate/internal/pkg/redis"
+)
+
+const (
+	unregisteredPlatePrefix = "unregistered_plate:"
+)
+
+// unregisteredPlateKey возвращает ключ хеша по нормализованному номеру
+func unregisteredPlateKey(licensePlate string) string {
+	return unregisteredPlatePrefix + domain.NormalizeLicensePlate(licensePlate)
+}
+
+// UnregisteredPlateCache кэширует решение "Vehicle not registered" по номеру и воротам
+// Повторные проезды незарегистрированных номеров (чужой транзит, ошибки камеры)
+// не доходят до БД. Все ворота номера хранятся в одном хеше, чтобы регистрация
+// автомобиля сбрасывала кэш одной командой
+type UnregisteredPlateCache struct {
+	cache   *redis.Client
+	metrics *metrics.Metrics
+	logger  logger.Logger
+	ttl     time.Duration
+}
+
+// NewUnregisteredPlateCache создает кэш; ttl должен быть коротким,
+// чтобы только что зарегистрированный автомобиль быстро начал проезжать
+func NewUnregisteredPlateCache(cache *redis.Client, metrics *metrics.Metrics, logger logger.Logger, ttl time.Duration) *UnregisteredPlateCache {
+	return &UnregisteredPlateCache{
+		cache:   cache,
+		metrics: metrics,
+		logger:  logger,
+		ttl:     ttl,
+	}
+}
+
+// IsUnregistered проверяет, закэширован ли отказ для номера на воротах
+// Ошибка Redis трактуется как промах кэша
+func (c *UnregisteredPlateCache) IsUnregistered(ctx context.Context, licensePlate, gateID string) bool {
+	key := unregisteredPlateKey(licensePlate)
+	exists, err := c.cache.HExists(ctx, key, gateID)
+	logCacheError(c.logger, "hexists", key, err)
+
+	hit := err == nil && exists
+	c.metrics.ObserveCacheLookup("unregistered_plate", hit)
+	return hit
+}
+
+// MarkUnregistered запоминает отказ для номера на воротах
+// TTL общий для всех ворот номера и отсчитывается от последнего промаха
+func (c *UnregisteredPlateCache) MarkUnregistered(ctx context.Context, licensePlate, gateID string) {
+	key := unregisteredPlateKey(licensePlate)
+	if err := c.cache.HSet(ctx, key, gateID, 1); err != nil {
+		logCacheError(c.logger, "hset", key, err)
+		return
+	}
+	logCacheError(c.logger, "expire", key, c.cache.Expire(ctx, key, c.ttl))
+}
+
+// Invalidate сбрасывает кэш номера на всех воротах
+func (c *UnregisteredPlateCache) Invalidate(ctx context.Context, licensePlate string) error {
+	key := unregisteredPlateKey(licensePlate)
+	err := c.cache.Del(ctx, key)
+	logCacheError(c.logger, "del", key, err)
+	return err
+}
@@ -0,0 +1,100 @@
+package cached
+
+import (
+	"context"
+	"time"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/pkg/redis"
+	"github.com/frontandrew/gate/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	lastLoginThrottlePrefix = "last_login:"
+)
+
+// UserRepository ограничивает частоту записи last_login_at через Redis
+// Остальные методы проксируются в исходный repository без кэширования
+type UserRepository struct {
+	repo              repository.UserRepository
+	cache             *redis.Client
+	logger            logger.Logger
+	lastLoginInterval time.Duration
+}
+
+// NewUserRepository создает user repository, обновляющий last_login_at
+// не чаще одного раза за lastLoginInterval для каждого пользователя
+func NewUserRepository(repo repository.UserRepository, cache *redis.Client, logger logger.Logger, lastLoginInterval time.Duration) *UserRepository {
+	return &UserRepository{
+		repo:              repo,
+		cache:             cache,
+		logger:            logger,
+		lastLoginInterval: lastLoginInterval,
+	}
+}
+
+// UpdateLastLogin обновляет время последнего входа, пропуская повторные записи внутри интервала
+func (r *UserRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID) error {
+	// SET NX атомарен: из параллельных входов в БД пишет только первый
+	key := lastLoginThrottlePrefix + id.String()
+	acquired, err := r.cache.SetNX(ctx, key, 1, r.lastLoginInterval)
+	if err == nil && !acquired {
+		return nil
+	}
+	logCacheError(r.logger, "setnx", key, err)
+
+	// При ошибке Redis пишем в БД как обычно - точность last_login важнее экономии
+	return r.repo.UpdateLastLogin(ctx, id)
+}
+
+// Create создает нового пользователя
+func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
+	return r.repo.Create(ctx, user)
+}
+
+// GetByID возвращает пользователя по ID
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	return r.repo.GetByID(ctx, id)
+}
+
+// GetByIDForUpdate возвращает пользователя по ID с блокировкой строки
+func (r *UserRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	return r.repo.GetByIDForUpdate(ctx, id)
+}
+
+// GetUsersByIDs возвращает пользователей по списку ID
+func (r *UserRepository) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.User, error) {
+	return r.repo.GetUsersByIDs(ctx, ids)
+}
+
+// GetByEmail возвращает пользователя по email
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return r.repo.GetByEmail(ctx, email)
+}
+
+// Update обновляет данные пользователя
+func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
+	return r.repo.Update(ctx, user)
+}
+
+// Delete удаляет пользователя
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.repo.Delete(ctx, id)
+}
+
+// DisableAccess отключает доступ пользователя
+func (r *UserRepository) DisableAccess(ctx context.Context, id, revokedBy uuid.UUID, reason string) (*domain.UserDisableResult, error) {
+	return r.repo.DisableAccess(ctx, id, revokedBy, reason)
+}
+
+// CountActiveByRoleForUpdate возвращает число активных пользователей с ролью с блокировкой строк
+func (r *UserRepository) CountActiveByRoleForUpdate(ctx context.Context, role domain.UserRole) (int, error) {
+	return r.repo.CountActiveByRoleForUpdate(ctx, role)
+}
+
+// List возвращает список пользователей с пагинацией
+func (r *UserRepository) List(ctx context.Context, limit, offset int, includeInactive bool) ([]*domain.User, error) {
+	return r.repo.List(ctx, limit, offset, includeInactive)
+}
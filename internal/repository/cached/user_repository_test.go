@@ -0,0 +1,84 @@
+package cached
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/repository/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepository_UpdateLastLogin(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("повторные входы внутри интервала дают одну запись", func(t *testing.T) {
+		client, _ := newTestRedis(t)
+		base := new(mocks.MockUserRepository)
+		userID := uuid.New()
+		base.On("UpdateLastLogin", mock.Anything, userID).Return(nil).Once()
+
+		repo := NewUserRepository(base, client, logger.NewNoop(), time.Minute)
+		for i := 0; i < 5; i++ {
+			require.NoError(t, repo.UpdateLastLogin(ctx, userID))
+		}
+
+		base.AssertNumberOfCalls(t, "UpdateLastLogin", 1)
+	})
+
+	t.Run("после интервала запись повторяется", func(t *testing.T) {
+		client, mr := newTestRedis(t)
+		base := new(mocks.MockUserRepository)
+		userID := uuid.New()
+		base.On("UpdateLastLogin", mock.Anything, userID).Return(nil)
+
+		repo := NewUserRepository(base, client, logger.NewNoop(), time.Minute)
+		require.NoError(t, repo.UpdateLastLogin(ctx, userID))
+		mr.FastForward(2 * time.Minute)
+		require.NoError(t, repo.UpdateLastLogin(ctx, userID))
+
+		base.AssertNumberOfCalls(t, "UpdateLastLogin", 2)
+	})
+
+	t.Run("разные пользователи не влияют друг на друга", func(t *testing.T) {
+		client, _ := newTestRedis(t)
+		base := new(mocks.MockUserRepository)
+		base.On("UpdateLastLogin", mock.Anything, mock.Anything).Return(nil)
+
+		repo := NewUserRepository(base, client, logger.NewNoop(), time.Minute)
+		require.NoError(t, repo.UpdateLastLogin(ctx, uuid.New()))
+		require.NoError(t, repo.UpdateLastLogin(ctx, uuid.New()))
+
+		base.AssertNumberOfCalls(t, "UpdateLastLogin", 2)
+	})
+
+	t.Run("при недоступном Redis запись идет в БД", func(t *testing.T) {
+		client, mr := newTestRedis(t)
+		base := new(mocks.MockUserRepository)
+		userID := uuid.New()
+		base.On("UpdateLastLogin", mock.Anything, userID).Return(nil)
+
+		repo := NewUserRepository(base, client, logger.NewNoop(), time.Minute)
+		mr.Close()
+		require.NoError(t, repo.UpdateLastLogin(ctx, userID))
+		require.NoError(t, repo.UpdateLastLogin(ctx, userID))
+
+		base.AssertNumberOfCalls(t, "UpdateLastLogin", 2)
+	})
+
+	t.Run("ошибка БД возвращается", func(t *testing.T) {
+		client, _ := newTestRedis(t)
+		base := new(mocks.MockUserRepository)
+		userID := uuid.New()
+		dbErr := errors.New("db down")
+		base.On("UpdateLastLogin", mock.Anything, userID).Return(dbErr)
+
+		repo := NewUserRepository(base, client, logger.NewNoop(), time.Minute)
+		assert.ErrorIs(t, repo.UpdateLastLogin(ctx, userID), dbErr)
+	})
+}
@@ -0,0 +1,105 @@
+package cached
+
+import (
+	"context"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/repository"
+	"github.com/google/uuid"
+)
+
+// VehicleRepository сбрасывает кэш незарегистрированных номеров при создании и изменении автомобилей
+// Чтение не кэшируется и проксируется в исходный repository
+type VehicleRepository struct {
+	repo         repository.VehicleRepository
+	unregistered *UnregisteredPlateCache
+}
+
+// NewVehicleRepository создает vehicle repository, инвалидирующий UnregisteredPlateCache
+func NewVehicleRepository(repo repository.VehicleRepository, unregistered *UnregisteredPlateCache) *VehicleRepository {
+	return &VehicleRepository{
+		repo:         repo,
+		unregistered: unregistered,
+	}
+}
+
+// Create создает автомобиль и сбрасывает закэшированный отказ по его номеру
+func (r *VehicleRepository) Create(ctx context.Context, vehicle *domain.Vehicle) error {
+	if err := r.repo.Create(ctx, vehicle); err != nil {
+		return err
+	}
+
+	// Ошибка инвалидации уже залогирована кэшем: запись истечет по TTL
+	_ = r.unregistered.Invalidate(ctx, vehicle.LicensePlate)
+	return nil
+}
+
+// Update обновляет автомобиль; номер мог измениться, поэтому кэш нового номера сбрасывается
+func (r *VehicleRepository) Update(ctx context.Context, vehicle *domain.Vehicle) error {
+	if err := r.repo.Update(ctx, vehicle); err != nil {
+		return err
+	}
+
+	_ = r.unregistered.Invalidate(ctx, vehicle.LicensePlate)
+	return nil
+}
+
+// GetByID возвращает автомобиль по ID
+func (r *VehicleRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Vehicle, error) {
+	return r.repo.GetByID(ctx, id)
+}
+
+// GetActiveByID возвращает активный автомобиль по ID
+func (r *VehicleRepository) GetActiveByID(ctx context.Context, id uuid.UUID) (*domain.Vehicle, error) {
+	return r.repo.GetActiveByID(ctx, id)
+}
+
+// GetByLicensePlate возвращает автомобиль по номеру
+func (r *VehicleRepository) GetByLicensePlate(ctx context.Context, licensePlate string) (*domain.Vehicle, error) {
+	return r.repo.GetByLicensePlate(ctx, licensePlate)
+}
+
+// GetVehiclesByIDs возвращает автомобили по списку ID
+func (r *VehicleRepository) GetVehiclesByIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.Vehicle, error) {
+	return r.repo.GetVehiclesByIDs(ctx, ids)
+}
+
+// GetByOwnerID возвращает автомобили владельца
+func (r *VehicleRepository) GetByOwnerID(ctx context.Context, ownerID uuid.UUID, includeInactive bool) ([]*domain.Vehicle, error) {
+	return r.repo.GetByOwnerID(ctx, ownerID, includeInactive)
+}
+
+// Delete деактивирует автомобиль
+func (r *VehicleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.repo.Delete(ctx, id)
+}
+
+// HardDelete физически удаляет автомобиль
+func (r *VehicleRepository) HardDelete(ctx context.Context, id uuid.UUID) (*domain.VehicleDeleteResult, error) {
+	return r.repo.HardDelete(ctx, id)
+}
+
+// List возвращает список автомобилей с пагинацией
+func (r *VehicleRepository) List(ctx context.Context, limit, offset int) ([]*domain.Vehicle, error) {
+	return r.repo.List(ctx, limit, offset)
+}
+
+// Search возвращает автомобили по фильтру
+func (r *VehicleRepository) Search(ctx context.Context, filter domain.VehicleFilter, limit, offset int) ([]*domain.Vehicle, error) {
+	return r.repo.Search(ctx, filter, limit, offset)
+}
+
+// Count возвращает количество автомобилей по фильтру
+func (r *VehicleRepository) Count(ctx context.Context, filter domain.VehicleFilter) (int, error) {
+	return r.repo.Count(ctx, filter)
+}
+
+// SearchByLicensePlate ищет автомобили по части номера
+func (r *VehicleRepository) SearchByLicensePlate(ctx context.Context, pattern string, limit int, includeInactive bool) ([]*domain.Vehicle, error) {
+	return r.repo.SearchByLicensePlate(ctx, pattern, limit, includeInactive)
+}
+
+// Merge объединяет дубликаты автомобилей
+func (r *VehicleRepository) Merge(ctx context.Context, sourceID, targetID uuid.UUID) (*domain.VehicleMergeResult, error) {
+	return r.repo.Merge(ctx, sourceID, targetID)
+}
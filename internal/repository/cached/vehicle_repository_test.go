@@ -0,0 +1,76 @@
+package cached
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/repository/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnregisteredPlateCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("отказ запоминается по номеру и воротам", func(t *testing.T) {
+		client, _ := newTestRedis(t)
+		cache := NewUnregisteredPlateCache(client, nil, logger.NewNoop(), 10*time.Second)
+
+		assert.False(t, cache.IsUnregistered(ctx, "A123BC777", "gate-1"))
+		cache.MarkUnregistered(ctx, "a123bc777", "gate-1")
+
+		assert.True(t, cache.IsUnregistered(ctx, "A123BC777", "gate-1"))
+		assert.False(t, cache.IsUnregistered(ctx, "A123BC777", "gate-2"))
+	})
+
+	t.Run("запись истекает по TTL", func(t *testing.T) {
+		client, mr := newTestRedis(t)
+		cache := NewUnregisteredPlateCache(client, nil, logger.NewNoop(), 10*time.Second)
+
+		cache.MarkUnregistered(ctx, "A123BC777", "gate-1")
+		mr.FastForward(11 * time.Second)
+
+		assert.False(t, cache.IsUnregistered(ctx, "A123BC777", "gate-1"))
+	})
+}
+
+func TestVehicleRepository_InvalidatesUnregisteredPlates(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("создание автомобиля сбрасывает отказы на всех воротах", func(t *testing.T) {
+		client, _ := newTestRedis(t)
+		cache := NewUnregisteredPlateCache(client, nil, logger.NewNoop(), 10*time.Second)
+		base := new(mocks.MockVehicleRepository)
+		base.On("Create", mock.Anything, mock.AnythingOfType("*domain.Vehicle")).Return(nil)
+
+		cache.MarkUnregistered(ctx, "A123BC777", "gate-1")
+		cache.MarkUnregistered(ctx, "A123BC777", "gate-2")
+		cache.MarkUnregistered(ctx, "B456CD777", "gate-1")
+
+		repo := NewVehicleRepository(base, cache)
+		require.NoError(t, repo.Create(ctx, &domain.Vehicle{ID: uuid.New(), LicensePlate: "A123BC777"}))
+
+		assert.False(t, cache.IsUnregistered(ctx, "A123BC777", "gate-1"))
+		assert.False(t, cache.IsUnregistered(ctx, "A123BC777", "gate-2"))
+		assert.True(t, cache.IsUnregistered(ctx, "B456CD777", "gate-1"))
+		base.AssertExpectations(t)
+	})
+
+	t.Run("ошибка создания не сбрасывает кэш", func(t *testing.T) {
+		client, _ := newTestRedis(t)
+		cache := NewUnregisteredPlateCache(client, nil, logger.NewNoop(), 10*time.Second)
+		base := new(mocks.MockVehicleRepository)
+		base.On("Create", mock.Anything, mock.AnythingOfType("*domain.Vehicle")).Return(domain.ErrVehicleAlreadyExists)
+
+		cache.MarkUnregistered(ctx, "A123BC777", "gate-1")
+
+		repo := NewVehicleRepository(base, cache)
+		assert.ErrorIs(t, repo.Create(ctx, &domain.Vehicle{LicensePlate: "A123BC777"}), domain.ErrVehicleAlreadyExists)
+		assert.True(t, cache.IsUnregistered(ctx, "A123BC777", "gate-1"))
+	})
+}
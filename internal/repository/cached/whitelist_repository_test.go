@@ -0,0 +1,277 @@
+package cached
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/pkg/metrics"
+	"github.com/frontandrew/gate/internal/repository/mocks"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWhitelistRepository_Warm(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name         string
+		entries      []*domain.WhitelistEntry
+		maxEntries   int
+		expectWarmed int
+		cached       []string
+		notCached    []string
+	}{
+		{
+			name: "действующие записи попадают в кэш",
+			entries: []*domain.WhitelistEntry{
+				{ID: uuid.New(), LicensePlate: "А001АА777", Reason: "Скорая помощь", IsActive: true},
+				{ID: uuid.New(), LicensePlate: "В002ВВ777", Reason: "Пожарные", IsActive: true},
+			},
+			maxEntries:   10,
+			expectWarmed: 2,
+			cached:       []string{"А001АА777", "В002ВВ777"},
+		},
+		{
+			name: "неактивные и истекшие записи пропускаются",
+			entries: []*domain.WhitelistEntry{
+				{ID: uuid.New(), LicensePlate: "А001АА777", Reason: "Скорая помощь", IsActive: true},
+				{ID: uuid.New(), LicensePlate: "С003СС777", Reason: "Отключен", IsActive: false},
+				{ID: uuid.New(), LicensePlate: "Е004ЕЕ777", Reason: "Истек", IsActive: true, ExpiresAt: &past},
+			},
+			maxEntries:   10,
+			expectWarmed: 1,
+			cached:       []string{"А001АА777"},
+			notCached:    []string{"С003СС777", "Е004ЕЕ777"},
+		},
+		{
+			name: "соблюдается лимит записей",
+			entries: []*domain.WhitelistEntry{
+				{ID: uuid.New(), LicensePlate: "А001АА777", Reason: "Скорая помощь", IsActive: true},
+				{ID: uuid.New(), LicensePlate: "В002ВВ777", Reason: "Пожарные", IsActive: true},
+			},
+			maxEntries:   1,
+			expectWarmed: 1,
+			cached:       []string{"А001АА777"},
+			notCached:    []string{"В002ВВ777"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache, mr := newTestRedis(t)
+
+			repo := new(mocks.MockWhitelistRepository)
+			repo.On("List", context.Background(), whitelistWarmBatchSize, 0).Return(tt.entries, nil)
+
+			cachedRepo := NewWhitelistRepository(repo, cache, nil, logger.NewNoop())
+			warmed, err := cachedRepo.Warm(context.Background(), tt.maxEntries)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectWarmed, warmed)
+
+			for _, plate := range tt.cached {
+				value := mr.HGet(whitelistCacheKey(plate), globalZoneField)
+				assert.Contains(t, value, "1:")
+			}
+			for _, plate := range tt.notCached {
+				assert.False(t, mr.Exists(whitelistCacheKey(plate)))
+			}
+
+			// После прогрева проверка не должна обращаться к БД
+			for _, plate := range tt.cached {
+				ok, _, err := cachedRepo.IsWhitelisted(context.Background(), plate, "")
+				require.NoError(t, err)
+				assert.True(t, ok)
+			}
+			repo.AssertNotCalled(t, "IsWhitelisted", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestWhitelistRepository_IsWhitelisted_CacheErrors(t *testing.T) {
+	t.Run("промах кэша не считается ошибкой", func(t *testing.T) {
+		cache, _ := newTestRedis(t)
+		log := newRecordingLogger()
+
+		repo := new(mocks.MockWhitelistRepository)
+		repo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(true, "Скорая помощь", nil)
+
+		cachedRepo := NewWhitelistRepository(repo, cache, nil, log)
+		inWhitelist, reason, err := cachedRepo.IsWhitelisted(context.Background(), "A123BC777", "")
+
+		require.NoError(t, err)
+		assert.True(t, inWhitelist)
+		assert.Equal(t, "Скорая помощь", reason)
+		assert.Empty(t, log.warnings)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("ошибка Redis логируется, ответ берется из БД", func(t *testing.T) {
+		cache, mr := newTestRedis(t)
+		log := newRecordingLogger()
+		mr.SetError("LOADING Redis is loading the dataset in memory")
+
+		repo := new(mocks.MockWhitelistRepository)
+		repo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(true, "Скорая помощь", nil)
+
+		cachedRepo := NewWhitelistRepository(repo, cache, nil, log)
+		inWhitelist, reason, err := cachedRepo.IsWhitelisted(context.Background(), "A123BC777", "")
+
+		require.NoError(t, err)
+		assert.True(t, inWhitelist)
+		assert.Equal(t, "Скорая помощь", reason)
+
+		// Ошибки и чтения, и записи в кэш
+		require.Len(t, log.warnings, 2)
+		assert.Equal(t, "hget", log.warnings[0]["operation"])
+		assert.Equal(t, whitelistCachePrefix+"A123BC777", log.warnings[0]["key"])
+		assert.Contains(t, log.warnings[0]["error"], "LOADING")
+		assert.Equal(t, "hset", log.warnings[1]["operation"])
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestWhitelistRepository_Delete_InvalidatesCache(t *testing.T) {
+	cache, mr := newTestRedis(t)
+	entryID := uuid.New()
+	mr.HSet(whitelistCachePrefix+"A123BC777", globalZoneField, "1:Скорая помощь")
+
+	repo := new(mocks.MockWhitelistRepository)
+	repo.On("GetByID", mock.Anything, entryID).
+		Return(&domain.WhitelistEntry{ID: entryID, LicensePlate: "A123BC777", IsActive: true}, nil)
+	repo.On("Delete", mock.Anything, entryID).Return(nil)
+
+	cachedRepo := NewWhitelistRepository(repo, cache, nil, logger.NewNoop())
+	require.NoError(t, cachedRepo.Delete(context.Background(), entryID))
+
+	assert.False(t, mr.Exists(whitelistCachePrefix+"A123BC777"))
+	repo.AssertExpectations(t)
+}
+
+// Номер латиницей и кириллицей - один номер: общий ключ кэша и общая инвалидация
+func TestWhitelistRepository_IsWhitelisted_LatinCyrillic(t *testing.T) {
+	cache, mr := newTestRedis(t)
+	entryID := uuid.New()
+
+	repo := new(mocks.MockWhitelistRepository)
+	repo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(true, "Сотрудник", nil).Once()
+	repo.On("GetByID", mock.Anything, entryID).
+		Return(&domain.WhitelistEntry{ID: entryID, LicensePlate: "A123BC777", IsActive: true}, nil)
+	repo.On("Delete", mock.Anything, entryID).Return(nil)
+
+	cachedRepo := NewWhitelistRepository(repo, cache, nil, logger.NewNoop())
+
+	inWhitelist, _, err := cachedRepo.IsWhitelisted(context.Background(), "A123BC777", "")
+	require.NoError(t, err)
+	assert.True(t, inWhitelist)
+
+	// Распознанный кириллицей номер обслуживается из кэша латинского
+	inWhitelist, reason, err := cachedRepo.IsWhitelisted(context.Background(), "А123ВС777", "")
+	require.NoError(t, err)
+	assert.True(t, inWhitelist)
+	assert.Equal(t, "Сотрудник", reason)
+
+	require.NoError(t, cachedRepo.Delete(context.Background(), entryID))
+	assert.False(t, mr.Exists(whitelistCacheKey("А123ВС777")), "удаление записи сбрасывает кэш для обеих раскладок")
+	repo.AssertExpectations(t)
+}
+
+func TestWhitelistRepository_IsWhitelisted_CacheMetrics(t *testing.T) {
+	cache, _ := newTestRedis(t)
+	registry := prometheus.NewRegistry()
+
+	repo := new(mocks.MockWhitelistRepository)
+	repo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(true, "Скорая помощь", nil).Once()
+
+	cachedRepo := NewWhitelistRepository(repo, cache, metrics.New(registry), logger.NewNoop())
+	for i := 0; i < 3; i++ {
+		inWhitelist, _, err := cachedRepo.IsWhitelisted(context.Background(), "A123BC777", "")
+		require.NoError(t, err)
+		assert.True(t, inWhitelist)
+	}
+
+	// Первое обращение - промах, остальные обслуживаются из кэша
+	expected := `
+# HELP gate_cache_lookups_total Обращения к кэшу по имени кэша и результату (hit/miss)
+# TYPE gate_cache_lookups_total counter
+gate_cache_lookups_total{cache="whitelist",result="hit"} 2
+gate_cache_lookups_total{cache="whitelist",result="miss"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "gate_cache_lookups_total"))
+	repo.AssertExpectations(t)
+}
+
+func TestWhitelistRepository_IsWhitelisted_ConcurrentMisses(t *testing.T) {
+	cache, _ := newTestRedis(t)
+	const lookups = 20
+
+	// Запрос в БД держится, пока все проверки не промахнутся мимо кэша
+	release := make(chan struct{})
+	repo := new(mocks.MockWhitelistRepository)
+	repo.On("IsWhitelisted", mock.Anything, "A123BC777", "").
+		Run(func(args mock.Arguments) { <-release }).
+		Return(false, "", nil).Once()
+
+	cachedRepo := NewWhitelistRepository(repo, cache, nil, logger.NewNoop())
+
+	var wg sync.WaitGroup
+	results := make(chan bool, lookups)
+	for i := 0; i < lookups; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			inWhitelist, _, err := cachedRepo.IsWhitelisted(context.Background(), "A123BC777", "")
+			assert.NoError(t, err)
+			results <- inWhitelist
+		}()
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(results)
+
+	for inWhitelist := range results {
+		assert.False(t, inWhitelist)
+	}
+	repo.AssertNumberOfCalls(t, "IsWhitelisted", 1)
+}
+
+func TestWhitelistRepository_IsWhitelisted_Zones(t *testing.T) {
+	cache, mr := newTestRedis(t)
+
+	repo := new(mocks.MockWhitelistRepository)
+	repo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(false, "", nil).Once()
+	repo.On("IsWhitelisted", mock.Anything, "A123BC777", "parking").Return(true, "Арендатор", nil).Once()
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	cachedRepo := NewWhitelistRepository(repo, cache, nil, logger.NewNoop())
+
+	// Результаты зон кэшируются независимо: повторные проверки не идут в БД
+	for i := 0; i < 2; i++ {
+		inWhitelist, _, err := cachedRepo.IsWhitelisted(context.Background(), "A123BC777", "")
+		require.NoError(t, err)
+		assert.False(t, inWhitelist)
+
+		inWhitelist, reason, err := cachedRepo.IsWhitelisted(context.Background(), "A123BC777", "parking")
+		require.NoError(t, err)
+		assert.True(t, inWhitelist)
+		assert.Equal(t, "Арендатор", reason)
+	}
+	assert.Equal(t, "0:", mr.HGet(whitelistCachePrefix+"A123BC777", globalZoneField))
+	assert.Equal(t, "1:Арендатор", mr.HGet(whitelistCachePrefix+"A123BC777", "parking"))
+
+	// Изменение записи сбрасывает результаты всех зон номера
+	require.NoError(t, cachedRepo.Create(context.Background(), &domain.WhitelistEntry{LicensePlate: "A123BC777"}))
+	assert.False(t, mr.Exists(whitelistCachePrefix+"A123BC777"))
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,97 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAccessLogRepository мок для repository.AccessLogRepository
+type MockAccessLogRepository struct {
+	mock.Mock
+}
+
+var _ repository.AccessLogRepository = (*MockAccessLogRepository)(nil)
+
+func (m *MockAccessLogRepository) Create(ctx context.Context, log *domain.AccessLog) error {
+	args := m.Called(ctx, log)
+	return args.Error(0)
+}
+
+func (m *MockAccessLogRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.AccessLog, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AccessLog), args.Error(1)
+}
+
+func (m *MockAccessLogRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.AccessLog, error) {
+	args := m.Called(ctx, userID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.AccessLog), args.Error(1)
+}
+
+func (m *MockAccessLogRepository) GetByVehicleID(ctx context.Context, vehicleID uuid.UUID, limit, offset int) ([]*domain.AccessLog, error) {
+	args := m.Called(ctx, vehicleID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.AccessLog), args.Error(1)
+}
+
+func (m *MockAccessLogRepository) GetByLicensePlate(ctx context.Context, licensePlate string, limit, offset int) ([]*domain.AccessLog, error) {
+	args := m.Called(ctx, licensePlate, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.AccessLog), args.Error(1)
+}
+
+func (m *MockAccessLogRepository) List(ctx context.Context, limit, offset int) ([]*domain.AccessLog, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.AccessLog), args.Error(1)
+}
+
+func (m *MockAccessLogRepository) Search(ctx context.Context, filter domain.AccessLogFilter, limit, offset int) ([]*domain.AccessLog, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.AccessLog), args.Error(1)
+}
+
+func (m *MockAccessLogRepository) Stream(ctx context.Context, filter domain.AccessLogFilter, fn func(*domain.AccessLog) error) error {
+	args := m.Called(ctx, filter, fn)
+	return args.Error(0)
+}
+
+func (m *MockAccessLogRepository) GetLatestByLicensePlate(ctx context.Context, licensePlate string) (*domain.AccessLog, error) {
+	args := m.Called(ctx, licensePlate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AccessLog), args.Error(1)
+}
+
+func (m *MockAccessLogRepository) GetStatsByPeriod(ctx context.Context, from, to time.Time) (*domain.AccessStats, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AccessStats), args.Error(1)
+}
+
+func (m *MockAccessLogRepository) Count(ctx context.Context, filter domain.AccessLogFilter) (int, error) {
+	args := m.Called(ctx, filter)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,29 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/repository"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAuditLogRepository мок для repository.AuditLogRepository
+type MockAuditLogRepository struct {
+	mock.Mock
+}
+
+var _ repository.AuditLogRepository = (*MockAuditLogRepository)(nil)
+
+func (m *MockAuditLogRepository) Create(ctx context.Context, entry *domain.AuditLog) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockAuditLogRepository) Search(ctx context.Context, filter domain.AuditLogFilter, limit, offset int) ([]*domain.AuditLog, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.AuditLog), args.Error(1)
+}
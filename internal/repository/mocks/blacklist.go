@@ -0,0 +1,77 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockBlacklistRepository мок для repository.BlacklistRepository
+type MockBlacklistRepository struct {
+	mock.Mock
+}
+
+var _ repository.BlacklistRepository = (*MockBlacklistRepository)(nil)
+
+func (m *MockBlacklistRepository) Create(ctx context.Context, entry *domain.BlacklistEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockBlacklistRepository) CreateBatch(ctx context.Context, entries []*domain.BlacklistEntry) ([]error, error) {
+	args := m.Called(ctx, entries)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]error), args.Error(1)
+}
+
+func (m *MockBlacklistRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.BlacklistEntry, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BlacklistEntry), args.Error(1)
+}
+
+func (m *MockBlacklistRepository) GetByLicensePlate(ctx context.Context, licensePlate string, zone *string) (*domain.BlacklistEntry, error) {
+	args := m.Called(ctx, licensePlate, zone)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BlacklistEntry), args.Error(1)
+}
+
+func (m *MockBlacklistRepository) IsBlacklisted(ctx context.Context, licensePlate, zone string) (bool, string, error) {
+	args := m.Called(ctx, licensePlate, zone)
+	return args.Bool(0), args.String(1), args.Error(2)
+}
+
+func (m *MockBlacklistRepository) Update(ctx context.Context, entry *domain.BlacklistEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockBlacklistRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockBlacklistRepository) List(ctx context.Context, limit, offset int) ([]*domain.BlacklistEntry, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.BlacklistEntry), args.Error(1)
+}
+
+func (m *MockBlacklistRepository) GetExpired(ctx context.Context) ([]*domain.BlacklistEntry, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.BlacklistEntry), args.Error(1)
+}
@@ -0,0 +1,122 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPassRepository мок для repository.PassRepository
+type MockPassRepository struct {
+	mock.Mock
+}
+
+var _ repository.PassRepository = (*MockPassRepository)(nil)
+
+func (m *MockPassRepository) Create(ctx context.Context, pass *domain.Pass) error {
+	args := m.Called(ctx, pass)
+	return args.Error(0)
+}
+
+func (m *MockPassRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Pass, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Pass), args.Error(1)
+}
+
+func (m *MockPassRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Pass, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Pass), args.Error(1)
+}
+
+func (m *MockPassRepository) GetActivePassesByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Pass, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Pass), args.Error(1)
+}
+
+func (m *MockPassRepository) GetActivePassesByUserAndVehicle(ctx context.Context, userID, vehicleID uuid.UUID) ([]*domain.Pass, error) {
+	args := m.Called(ctx, userID, vehicleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Pass), args.Error(1)
+}
+
+func (m *MockPassRepository) Update(ctx context.Context, pass *domain.Pass) error {
+	args := m.Called(ctx, pass)
+	return args.Error(0)
+}
+
+func (m *MockPassRepository) Revoke(ctx context.Context, id, revokedBy uuid.UUID, reason string) error {
+	args := m.Called(ctx, id, revokedBy, reason)
+	return args.Error(0)
+}
+
+func (m *MockPassRepository) GetByIDWithVehicles(ctx context.Context, id uuid.UUID) (*domain.Pass, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Pass), args.Error(1)
+}
+
+func (m *MockPassRepository) LoadVehicles(ctx context.Context, passes []*domain.Pass) error {
+	args := m.Called(ctx, passes)
+	return args.Error(0)
+}
+
+func (m *MockPassRepository) FindOverlapping(ctx context.Context, pass *domain.Pass, vehicleIDs []uuid.UUID) ([]*domain.Pass, error) {
+	args := m.Called(ctx, pass, vehicleIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Pass), args.Error(1)
+}
+
+func (m *MockPassRepository) Extend(ctx context.Context, pass *domain.Pass) error {
+	args := m.Called(ctx, pass)
+	return args.Error(0)
+}
+
+func (m *MockPassRepository) IncrementUses(ctx context.Context, passID uuid.UUID, accessLog *domain.AccessLog) error {
+	args := m.Called(ctx, passID, accessLog)
+	return args.Error(0)
+}
+
+func (m *MockPassRepository) Query(ctx context.Context, filter domain.PassFilter, limit, offset int) ([]*domain.Pass, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Pass), args.Error(1)
+}
+
+func (m *MockPassRepository) GetExpiredPasses(ctx context.Context) ([]*domain.Pass, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Pass), args.Error(1)
+}
+
+func (m *MockPassRepository) CountGuestPassesSince(ctx context.Context, issuerID uuid.UUID, since time.Time) (int, error) {
+	args := m.Called(ctx, issuerID, since)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockPassRepository) Count(ctx context.Context, filter domain.PassFilter) (int, error) {
+	args := m.Called(ctx, filter)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,56 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPassVehicleRepository мок для repository.PassVehicleRepository
+type MockPassVehicleRepository struct {
+	mock.Mock
+}
+
+var _ repository.PassVehicleRepository = (*MockPassVehicleRepository)(nil)
+
+func (m *MockPassVehicleRepository) Create(ctx context.Context, passVehicle *domain.PassVehicle) error {
+	args := m.Called(ctx, passVehicle)
+	return args.Error(0)
+}
+
+func (m *MockPassVehicleRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.PassVehicle, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PassVehicle), args.Error(1)
+}
+
+func (m *MockPassVehicleRepository) GetByPassID(ctx context.Context, passID uuid.UUID) ([]*domain.PassVehicle, error) {
+	args := m.Called(ctx, passID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.PassVehicle), args.Error(1)
+}
+
+func (m *MockPassVehicleRepository) GetByVehicleID(ctx context.Context, vehicleID uuid.UUID) ([]*domain.PassVehicle, error) {
+	args := m.Called(ctx, vehicleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.PassVehicle), args.Error(1)
+}
+
+func (m *MockPassVehicleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPassVehicleRepository) DeleteByPassAndVehicle(ctx context.Context, passID, vehicleID uuid.UUID) error {
+	args := m.Called(ctx, passID, vehicleID)
+	return args.Error(0)
+}
@@ -0,0 +1,31 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/frontandrew/gate/internal/repository"
+)
+
+// MockUnitOfWork реализация repository.UnitOfWork для тестов: передает в fn репозитории Repos
+// и считает исходы транзакций. Вызовы моков при откате не отменяются - проверяйте Committed/RolledBack
+type MockUnitOfWork struct {
+	Repos    repository.TxRepositories
+	BeginErr error // Ошибка открытия транзакции; fn не вызывается
+
+	Committed  int
+	RolledBack int
+}
+
+var _ repository.UnitOfWork = (*MockUnitOfWork)(nil)
+
+func (m *MockUnitOfWork) Do(ctx context.Context, fn func(repos repository.TxRepositories) error) error {
+	if m.BeginErr != nil {
+		return m.BeginErr
+	}
+	if err := fn(m.Repos); err != nil {
+		m.RolledBack++
+		return err
+	}
+	m.Committed++
+	return nil
+}
@@ -0,0 +1,117 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockVehicleRepository мок для repository.VehicleRepository
+type MockVehicleRepository struct {
+	mock.Mock
+}
+
+var _ repository.VehicleRepository = (*MockVehicleRepository)(nil)
+
+func (m *MockVehicleRepository) Create(ctx context.Context, vehicle *domain.Vehicle) error {
+	args := m.Called(ctx, vehicle)
+	return args.Error(0)
+}
+
+func (m *MockVehicleRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Vehicle, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Vehicle), args.Error(1)
+}
+
+func (m *MockVehicleRepository) GetByLicensePlate(ctx context.Context, licensePlate string) (*domain.Vehicle, error) {
+	args := m.Called(ctx, licensePlate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Vehicle), args.Error(1)
+}
+
+func (m *MockVehicleRepository) GetActiveByID(ctx context.Context, id uuid.UUID) (*domain.Vehicle, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Vehicle), args.Error(1)
+}
+
+func (m *MockVehicleRepository) GetVehiclesByIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.Vehicle, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Vehicle), args.Error(1)
+}
+
+func (m *MockVehicleRepository) GetByOwnerID(ctx context.Context, ownerID uuid.UUID, includeInactive bool) ([]*domain.Vehicle, error) {
+	args := m.Called(ctx, ownerID, includeInactive)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Vehicle), args.Error(1)
+}
+
+func (m *MockVehicleRepository) Update(ctx context.Context, vehicle *domain.Vehicle) error {
+	args := m.Called(ctx, vehicle)
+	return args.Error(0)
+}
+
+func (m *MockVehicleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockVehicleRepository) List(ctx context.Context, limit, offset int) ([]*domain.Vehicle, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Vehicle), args.Error(1)
+}
+
+func (m *MockVehicleRepository) Search(ctx context.Context, filter domain.VehicleFilter, limit, offset int) ([]*domain.Vehicle, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Vehicle), args.Error(1)
+}
+
+func (m *MockVehicleRepository) SearchByLicensePlate(ctx context.Context, pattern string, limit int, includeInactive bool) ([]*domain.Vehicle, error) {
+	args := m.Called(ctx, pattern, limit, includeInactive)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Vehicle), args.Error(1)
+}
+
+func (m *MockVehicleRepository) HardDelete(ctx context.Context, id uuid.UUID) (*domain.VehicleDeleteResult, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.VehicleDeleteResult), args.Error(1)
+}
+
+func (m *MockVehicleRepository) Merge(ctx context.Context, sourceID, targetID uuid.UUID) (*domain.VehicleMergeResult, error) {
+	args := m.Called(ctx, sourceID, targetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.VehicleMergeResult), args.Error(1)
+}
+
+func (m *MockVehicleRepository) Count(ctx context.Context, filter domain.VehicleFilter) (int, error) {
+	args := m.Called(ctx, filter)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,77 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWhitelistRepository мок для repository.WhitelistRepository
+type MockWhitelistRepository struct {
+	mock.Mock
+}
+
+var _ repository.WhitelistRepository = (*MockWhitelistRepository)(nil)
+
+func (m *MockWhitelistRepository) Create(ctx context.Context, entry *domain.WhitelistEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockWhitelistRepository) CreateBatch(ctx context.Context, entries []*domain.WhitelistEntry) ([]error, error) {
+	args := m.Called(ctx, entries)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]error), args.Error(1)
+}
+
+func (m *MockWhitelistRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.WhitelistEntry, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WhitelistEntry), args.Error(1)
+}
+
+func (m *MockWhitelistRepository) GetByLicensePlate(ctx context.Context, licensePlate string, zone *string) (*domain.WhitelistEntry, error) {
+	args := m.Called(ctx, licensePlate, zone)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WhitelistEntry), args.Error(1)
+}
+
+func (m *MockWhitelistRepository) IsWhitelisted(ctx context.Context, licensePlate, zone string) (bool, string, error) {
+	args := m.Called(ctx, licensePlate, zone)
+	return args.Bool(0), args.String(1), args.Error(2)
+}
+
+func (m *MockWhitelistRepository) Update(ctx context.Context, entry *domain.WhitelistEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockWhitelistRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWhitelistRepository) List(ctx context.Context, limit, offset int) ([]*domain.WhitelistEntry, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.WhitelistEntry), args.Error(1)
+}
+
+func (m *MockWhitelistRepository) GetExpired(ctx context.Context) ([]*domain.WhitelistEntry, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.WhitelistEntry), args.Error(1)
+}
@@ -13,7 +13,7 @@ import (
 )
 
 type accessLogRepository struct {
-	db *pgxpool.Pool
+	db dbtx
 }
 
 func NewAccessLogRepository(db *pgxpool.Pool) repository.AccessLogRepository {
@@ -21,16 +21,24 @@ func NewAccessLogRepository(db *pgxpool.Pool) repository.AccessLogRepository {
 }
 
 func (r *accessLogRepository) Create(ctx context.Context, log *domain.AccessLog) error {
+	return insertAccessLog(ctx, r.db, log)
+}
+
+// insertAccessLog записывает проезд; принимает пул или транзакцию
+// (учет проезда по пропуску пишет журнал в транзакции passRepository.IncrementUses)
+func insertAccessLog(ctx context.Context, db execer, log *domain.AccessLog) error {
 	query := `
 		INSERT INTO access_logs (id, user_id, vehicle_id, license_plate, image_url, recognition_confidence,
-		                        access_granted, access_reason, gate_id, direction, timestamp)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		                        access_granted, access_reason, gate_id, direction, timestamp, ml_model_version, reason_code,
+		                        observed, direction_inferred, manual, operator_id, manual_reason)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, ''), NULLIF($13, ''), $14, $15,
+		        $16, $17, NULLIF($18, ''))
 	`
 
 	log.ID = uuid.New()
 	log.Timestamp = time.Now()
 
-	_, err := r.db.Exec(ctx, query,
+	_, err := db.Exec(ctx, query,
 		log.ID,
 		log.UserID,
 		log.VehicleID,
@@ -42,6 +50,13 @@ func (r *accessLogRepository) Create(ctx context.Context, log *domain.AccessLog)
 		log.GateID,
 		log.Direction,
 		log.Timestamp,
+		log.MLModelVersion,
+		log.ReasonCode,
+		log.Observed,
+		log.DirectionInferred,
+		log.Manual,
+		log.OperatorID,
+		log.ManualReason,
 	)
 
 	return err
@@ -50,26 +65,13 @@ func (r *accessLogRepository) Create(ctx context.Context, log *domain.AccessLog)
 func (r *accessLogRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.AccessLog, error) {
 	query := `
 		SELECT id, user_id, vehicle_id, license_plate, image_url, recognition_confidence,
-		       access_granted, access_reason, gate_id, direction, timestamp
+		       access_granted, access_reason, gate_id, direction, timestamp, COALESCE(ml_model_version, ''), COALESCE(reason_code, ''),
+		       observed, direction_inferred, manual, operator_id, COALESCE(manual_reason, '')
 		FROM access_logs
 		WHERE id = $1
 	`
 
-	log := &domain.AccessLog{}
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&log.ID,
-		&log.UserID,
-		&log.VehicleID,
-		&log.LicensePlate,
-		&log.ImageURL,
-		&log.RecognitionConfidence,
-		&log.AccessGranted,
-		&log.AccessReason,
-		&log.GateID,
-		&log.Direction,
-		&log.Timestamp,
-	)
-
+	log, err := scanAccessLog(r.db.QueryRow(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrAccessLogNotFound
@@ -83,135 +85,187 @@ func (r *accessLogRepository) GetByID(ctx context.Context, id uuid.UUID) (*domai
 func (r *accessLogRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.AccessLog, error) {
 	query := `
 		SELECT id, user_id, vehicle_id, license_plate, image_url, recognition_confidence,
-		       access_granted, access_reason, gate_id, direction, timestamp
+		       access_granted, access_reason, gate_id, direction, timestamp, COALESCE(ml_model_version, ''), COALESCE(reason_code, ''),
+		       observed, direction_inferred, manual, operator_id, COALESCE(manual_reason, '')
 		FROM access_logs
 		WHERE user_id = $1
-		ORDER BY timestamp DESC
-		LIMIT $2 OFFSET $3
-	`
+		ORDER BY timestamp DESC`
 
-	rows, err := r.db.Query(ctx, query, userID, limit, offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return r.scanAccessLogs(rows)
+	return queryPage(ctx, r.db, scanAccessLog, query, limit, offset, userID)
 }
 
 func (r *accessLogRepository) GetByVehicleID(ctx context.Context, vehicleID uuid.UUID, limit, offset int) ([]*domain.AccessLog, error) {
 	query := `
 		SELECT id, user_id, vehicle_id, license_plate, image_url, recognition_confidence,
-		       access_granted, access_reason, gate_id, direction, timestamp
+		       access_granted, access_reason, gate_id, direction, timestamp, COALESCE(ml_model_version, ''), COALESCE(reason_code, ''),
+		       observed, direction_inferred, manual, operator_id, COALESCE(manual_reason, '')
 		FROM access_logs
 		WHERE vehicle_id = $1
-		ORDER BY timestamp DESC
-		LIMIT $2 OFFSET $3
-	`
-
-	rows, err := r.db.Query(ctx, query, vehicleID, limit, offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+		ORDER BY timestamp DESC`
 
-	return r.scanAccessLogs(rows)
+	return queryPage(ctx, r.db, scanAccessLog, query, limit, offset, vehicleID)
 }
 
 func (r *accessLogRepository) GetByLicensePlate(ctx context.Context, licensePlate string, limit, offset int) ([]*domain.AccessLog, error) {
 	query := `
 		SELECT id, user_id, vehicle_id, license_plate, image_url, recognition_confidence,
-		       access_granted, access_reason, gate_id, direction, timestamp
+		       access_granted, access_reason, gate_id, direction, timestamp, COALESCE(ml_model_version, ''), COALESCE(reason_code, ''),
+		       observed, direction_inferred, manual, operator_id, COALESCE(manual_reason, '')
 		FROM access_logs
 		WHERE license_plate = $1
+		ORDER BY timestamp DESC`
+
+	return queryPage(ctx, r.db, scanAccessLog, query, limit, offset, licensePlate)
+}
+
+func (r *accessLogRepository) GetLatestByLicensePlate(ctx context.Context, licensePlate string) (*domain.AccessLog, error) {
+	query := `
+		SELECT id, user_id, vehicle_id, license_plate, image_url, recognition_confidence,
+		       access_granted, access_reason, gate_id, direction, timestamp, COALESCE(ml_model_version, ''), COALESCE(reason_code, ''),
+		       observed, direction_inferred, manual, operator_id, COALESCE(manual_reason, '')
+		FROM access_logs
+		WHERE license_plate = $1 AND access_granted = true AND observed = false
 		ORDER BY timestamp DESC
-		LIMIT $2 OFFSET $3
+		LIMIT 1
 	`
 
-	rows, err := r.db.Query(ctx, query, licensePlate, limit, offset)
+	log, err := scanAccessLog(r.db.QueryRow(ctx, query, licensePlate))
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrAccessLogNotFound
+		}
 		return nil, err
 	}
-	defer rows.Close()
 
-	return r.scanAccessLogs(rows)
+	return log, nil
 }
 
 func (r *accessLogRepository) List(ctx context.Context, limit, offset int) ([]*domain.AccessLog, error) {
 	query := `
 		SELECT id, user_id, vehicle_id, license_plate, image_url, recognition_confidence,
-		       access_granted, access_reason, gate_id, direction, timestamp
+		       access_granted, access_reason, gate_id, direction, timestamp, COALESCE(ml_model_version, ''), COALESCE(reason_code, ''),
+		       observed, direction_inferred, manual, operator_id, COALESCE(manual_reason, '')
 		FROM access_logs
-		ORDER BY timestamp DESC
-		LIMIT $1 OFFSET $2
-	`
+		ORDER BY timestamp DESC`
 
-	rows, err := r.db.Query(ctx, query, limit, offset)
-	if err != nil {
-		return nil, err
+	return queryPage(ctx, r.db, scanAccessLog, query, limit, offset)
+}
+
+// accessLogFilterCondition - условие выборки по AccessLogFilter
+// Незаданные условия передаются как NULL и не ограничивают выборку
+const accessLogFilterCondition = `
+		WHERE ($1::uuid IS NULL OR user_id = $1)
+		  AND ($2::varchar IS NULL OR reason_code = $2)
+		  AND ($3::timestamp IS NULL OR timestamp >= $3)
+		  AND ($4::timestamp IS NULL OR timestamp < $4)
+		  AND ($5::uuid IS NULL OR vehicle_id = $5)
+		  AND ($6::varchar IS NULL OR gate_id = $6)
+		  AND ($7::direction_enum IS NULL OR direction = $7)
+		  AND ($8::boolean IS NULL OR access_granted = $8)`
+
+// searchAccessLogsQuery - выборка по AccessLogFilter
+const searchAccessLogsQuery = `
+		SELECT id, user_id, vehicle_id, license_plate, image_url, recognition_confidence,
+		       access_granted, access_reason, gate_id, direction, timestamp, COALESCE(ml_model_version, ''), COALESCE(reason_code, ''),
+		       observed, direction_inferred, manual, operator_id, COALESCE(manual_reason, '')
+		FROM access_logs` + accessLogFilterCondition + `
+		ORDER BY timestamp DESC`
+
+// searchArgs возвращает параметры accessLogFilterCondition
+func searchArgs(filter domain.AccessLogFilter) []any {
+	return []any{
+		filter.UserID,
+		nullIfEmpty(filter.ReasonCode),
+		filter.From,
+		filter.To,
+		filter.VehicleID,
+		nullIfEmpty(filter.GateID),
+		nullIfEmpty(string(filter.Direction)),
+		filter.AccessGranted,
+	}
+}
+
+// nullIfEmpty передает пустую строку как NULL: условие фильтра не применяется
+func nullIfEmpty(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func (r *accessLogRepository) Search(ctx context.Context, filter domain.AccessLogFilter, limit, offset int) ([]*domain.AccessLog, error) {
+	return queryPage(ctx, r.db, scanAccessLog, searchAccessLogsQuery, limit, offset, searchArgs(filter)...)
+}
+
+// Count возвращает количество логов по тому же фильтру, что и Search
+func (r *accessLogRepository) Count(ctx context.Context, filter domain.AccessLogFilter) (int, error) {
+	query := `SELECT COUNT(*) FROM access_logs` + accessLogFilterCondition
+
+	var count int
+	if err := r.db.QueryRow(ctx, query, searchArgs(filter)...).Scan(&count); err != nil {
+		return 0, err
 	}
-	defer rows.Close()
 
-	return r.scanAccessLogs(rows)
+	return count, nil
 }
 
-func (r *accessLogRepository) GetStatsByPeriod(ctx context.Context, from, to string) (map[string]interface{}, error) {
+// Stream передает логи по фильтру в fn по одному, не загружая выборку в память
+func (r *accessLogRepository) Stream(ctx context.Context, filter domain.AccessLogFilter, fn func(*domain.AccessLog) error) error {
+	return eachRow(ctx, r.db, scanAccessLog, fn, searchAccessLogsQuery, searchArgs(filter)...)
+}
+
+// GetStatsByPeriod возвращает статистику проездов за период [from, to)
+// Проверки на воротах в режиме наблюдения не учитываются
+func (r *accessLogRepository) GetStatsByPeriod(ctx context.Context, from, to time.Time) (*domain.AccessStats, error) {
 	query := `
 		SELECT
 			COUNT(*) as total_count,
-			SUM(CASE WHEN access_granted = true THEN 1 ELSE 0 END) as granted_count,
-			SUM(CASE WHEN access_granted = false THEN 1 ELSE 0 END) as denied_count,
-			AVG(recognition_confidence) as avg_confidence
+			COUNT(*) FILTER (WHERE access_granted = true) as granted_count,
+			COUNT(*) FILTER (WHERE access_granted = false) as denied_count,
+			COALESCE(AVG(recognition_confidence), 0) as avg_confidence
 		FROM access_logs
-		WHERE timestamp BETWEEN $1 AND $2
+		WHERE timestamp >= $1 AND timestamp < $2 AND observed = false
 	`
 
-	var totalCount, grantedCount, deniedCount int
-	var avgConfidence float64
-
+	stats := &domain.AccessStats{From: from, To: to}
 	err := r.db.QueryRow(ctx, query, from, to).Scan(
-		&totalCount,
-		&grantedCount,
-		&deniedCount,
-		&avgConfidence,
+		&stats.TotalCount,
+		&stats.GrantedCount,
+		&stats.DeniedCount,
+		&stats.AvgConfidence,
 	)
-
 	if err != nil {
 		return nil, err
 	}
 
-	stats := map[string]interface{}{
-		"total_count":    totalCount,
-		"granted_count":  grantedCount,
-		"denied_count":   deniedCount,
-		"avg_confidence": avgConfidence,
-	}
-
 	return stats, nil
 }
 
-func (r *accessLogRepository) scanAccessLogs(rows pgx.Rows) ([]*domain.AccessLog, error) {
-	var logs []*domain.AccessLog
-	for rows.Next() {
-		log := &domain.AccessLog{}
-		err := rows.Scan(
-			&log.ID,
-			&log.UserID,
-			&log.VehicleID,
-			&log.LicensePlate,
-			&log.ImageURL,
-			&log.RecognitionConfidence,
-			&log.AccessGranted,
-			&log.AccessReason,
-			&log.GateID,
-			&log.Direction,
-			&log.Timestamp,
-		)
-		if err != nil {
-			return nil, err
-		}
-		logs = append(logs, log)
+// scanAccessLog сканирует строку access_logs в порядке колонок SELECT выше
+func scanAccessLog(row pgx.Row) (*domain.AccessLog, error) {
+	log := &domain.AccessLog{}
+	err := row.Scan(
+		&log.ID,
+		&log.UserID,
+		&log.VehicleID,
+		&log.LicensePlate,
+		&log.ImageURL,
+		&log.RecognitionConfidence,
+		&log.AccessGranted,
+		&log.AccessReason,
+		&log.GateID,
+		&log.Direction,
+		&log.Timestamp,
+		&log.MLModelVersion,
+		&log.ReasonCode,
+		&log.Observed,
+		&log.DirectionInferred,
+		&log.Manual,
+		&log.OperatorID,
+		&log.ManualReason,
+	)
+	if err != nil {
+		return nil, err
 	}
-
-	return logs, nil
+	return log, nil
 }
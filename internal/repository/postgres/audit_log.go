@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type auditLogRepository struct {
+	db dbtx
+}
+
+func NewAuditLogRepository(db *pgxpool.Pool) repository.AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+func (r *auditLogRepository) Create(ctx context.Context, entry *domain.AuditLog) error {
+	query := `
+		INSERT INTO audit_logs (id, actor_id, action, target_type, target_id, details, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	entry.ID = uuid.New()
+	entry.CreatedAt = time.Now()
+
+	_, err := r.db.Exec(ctx, query,
+		entry.ID,
+		entry.ActorID,
+		entry.Action,
+		entry.TargetType,
+		entry.TargetID,
+		entry.Details,
+		entry.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *auditLogRepository) Search(ctx context.Context, filter domain.AuditLogFilter, limit, offset int) ([]*domain.AuditLog, error) {
+	// Незаданные условия передаются как NULL и не ограничивают выборку
+	query := `
+		SELECT id, actor_id, action, target_type, target_id, details, created_at
+		FROM audit_logs
+		WHERE ($1::varchar IS NULL OR target_type = $1)
+		  AND ($2::uuid IS NULL OR target_id = $2)
+		  AND ($3::uuid IS NULL OR actor_id = $3)
+		  AND ($4::timestamp IS NULL OR created_at >= $4)
+		  AND ($5::timestamp IS NULL OR created_at < $5)
+		ORDER BY created_at DESC`
+
+	var targetType *string
+	if filter.TargetType != "" {
+		value := string(filter.TargetType)
+		targetType = &value
+	}
+
+	return queryPage(ctx, r.db, scanAuditLog, query, limit, offset,
+		targetType, filter.TargetID, filter.ActorID, filter.From, filter.To)
+}
+
+// scanAuditLog сканирует строку audit_logs в порядке колонок SELECT выше
+func scanAuditLog(row pgx.Row) (*domain.AuditLog, error) {
+	entry := &domain.AuditLog{}
+	err := row.Scan(
+		&entry.ID,
+		&entry.ActorID,
+		&entry.Action,
+		&entry.TargetType,
+		&entry.TargetID,
+		&entry.Details,
+		&entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
@@ -13,7 +13,7 @@ import (
 )
 
 type blacklistRepository struct {
-	db *pgxpool.Pool
+	db dbtx
 }
 
 func NewBlacklistRepository(db *pgxpool.Pool) repository.BlacklistRepository {
@@ -22,8 +22,9 @@ func NewBlacklistRepository(db *pgxpool.Pool) repository.BlacklistRepository {
 
 func (r *blacklistRepository) Create(ctx context.Context, entry *domain.BlacklistEntry) error {
 	query := `
-		INSERT INTO blacklist (id, license_plate, reason, added_by, added_at, expires_at, is_active)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO blacklist (id, license_plate, reason, added_by, added_at, expires_at, is_active, zone)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (license_plate, (COALESCE(zone, ''))) DO NOTHING
 	`
 
 	entry.ID = uuid.New()
@@ -32,7 +33,7 @@ func (r *blacklistRepository) Create(ctx context.Context, entry *domain.Blacklis
 	// Нормализуем номер
 	entry.LicensePlate = domain.NormalizeLicensePlate(entry.LicensePlate)
 
-	_, err := r.db.Exec(ctx, query,
+	result, err := r.db.Exec(ctx, query,
 		entry.ID,
 		entry.LicensePlate,
 		entry.Reason,
@@ -40,14 +41,71 @@ func (r *blacklistRepository) Create(ctx context.Context, entry *domain.Blacklis
 		entry.AddedAt,
 		entry.ExpiresAt,
 		entry.IsActive,
+		entry.Zone,
 	)
 
-	return err
+	if err != nil {
+		return mapUniqueViolation(err, domain.ErrBlacklistEntryAlreadyExists)
+	}
+
+	// Номер уникален в пределах зоны (без зоны - отдельное значение), включая неактивные записи
+	if result.RowsAffected() == 0 {
+		return domain.ErrBlacklistEntryAlreadyExists
+	}
+
+	return nil
+}
+
+// CreateBatch вставляет записи в одной транзакции
+// ON CONFLICT не прерывает транзакцию, поэтому дубликаты (в том числе внутри пакета) отмечаются построчно
+func (r *blacklistRepository) CreateBatch(ctx context.Context, entries []*domain.BlacklistEntry) ([]error, error) {
+	query := `
+		INSERT INTO blacklist (id, license_plate, reason, added_by, added_at, expires_at, is_active, zone)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (license_plate, (COALESCE(zone, ''))) DO NOTHING
+	`
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	now := time.Now()
+	rowErrors := make([]error, len(entries))
+	for i, entry := range entries {
+		entry.ID = uuid.New()
+		entry.AddedAt = now
+		entry.LicensePlate = domain.NormalizeLicensePlate(entry.LicensePlate)
+
+		result, err := tx.Exec(ctx, query,
+			entry.ID,
+			entry.LicensePlate,
+			entry.Reason,
+			entry.AddedBy,
+			entry.AddedAt,
+			entry.ExpiresAt,
+			entry.IsActive,
+			entry.Zone,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if result.RowsAffected() == 0 {
+			rowErrors[i] = domain.ErrBlacklistEntryAlreadyExists
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return rowErrors, nil
 }
 
 func (r *blacklistRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.BlacklistEntry, error) {
 	query := `
-		SELECT id, license_plate, reason, added_by, added_at, expires_at, is_active
+		SELECT id, license_plate, reason, added_by, added_at, expires_at, is_active, zone
 		FROM blacklist
 		WHERE id = $1
 	`
@@ -61,6 +119,7 @@ func (r *blacklistRepository) GetByID(ctx context.Context, id uuid.UUID) (*domai
 		&entry.AddedAt,
 		&entry.ExpiresAt,
 		&entry.IsActive,
+		&entry.Zone,
 	)
 
 	if err != nil {
@@ -73,17 +132,17 @@ func (r *blacklistRepository) GetByID(ctx context.Context, id uuid.UUID) (*domai
 	return entry, nil
 }
 
-func (r *blacklistRepository) GetByLicensePlate(ctx context.Context, licensePlate string) (*domain.BlacklistEntry, error) {
+func (r *blacklistRepository) GetByLicensePlate(ctx context.Context, licensePlate string, zone *string) (*domain.BlacklistEntry, error) {
 	query := `
-		SELECT id, license_plate, reason, added_by, added_at, expires_at, is_active
+		SELECT id, license_plate, reason, added_by, added_at, expires_at, is_active, zone
 		FROM blacklist
-		WHERE license_plate = $1 AND is_active = true
+		WHERE license_plate = $1 AND COALESCE(zone, '') = COALESCE($2::varchar, '') AND is_active = true
 	`
 
 	normalizedPlate := domain.NormalizeLicensePlate(licensePlate)
 
 	entry := &domain.BlacklistEntry{}
-	err := r.db.QueryRow(ctx, query, normalizedPlate).Scan(
+	err := r.db.QueryRow(ctx, query, normalizedPlate, zone).Scan(
 		&entry.ID,
 		&entry.LicensePlate,
 		&entry.Reason,
@@ -91,6 +150,7 @@ func (r *blacklistRepository) GetByLicensePlate(ctx context.Context, licensePlat
 		&entry.AddedAt,
 		&entry.ExpiresAt,
 		&entry.IsActive,
+		&entry.Zone,
 	)
 
 	if err != nil {
@@ -104,20 +164,21 @@ func (r *blacklistRepository) GetByLicensePlate(ctx context.Context, licensePlat
 }
 
 // IsBlacklisted - КРИТИЧНЫЙ МЕТОД для проверки доступа
-func (r *blacklistRepository) IsBlacklisted(ctx context.Context, licensePlate string) (bool, string, error) {
+func (r *blacklistRepository) IsBlacklisted(ctx context.Context, licensePlate, zone string) (bool, string, error) {
 	query := `
 		SELECT reason
 		FROM blacklist
 		WHERE license_plate = $1
 		  AND is_active = true
 		  AND (expires_at IS NULL OR expires_at > NOW())
+		  AND (zone IS NULL OR zone = $2)
 		LIMIT 1
 	`
 
 	normalizedPlate := domain.NormalizeLicensePlate(licensePlate)
 
 	var reason string
-	err := r.db.QueryRow(ctx, query, normalizedPlate).Scan(&reason)
+	err := r.db.QueryRow(ctx, query, normalizedPlate, zone).Scan(&reason)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -134,7 +195,7 @@ func (r *blacklistRepository) IsBlacklisted(ctx context.Context, licensePlate st
 func (r *blacklistRepository) Update(ctx context.Context, entry *domain.BlacklistEntry) error {
 	query := `
 		UPDATE blacklist
-		SET license_plate = $2, reason = $3, expires_at = $4, is_active = $5
+		SET license_plate = $2, reason = $3, expires_at = $4, is_active = $5, zone = $6
 		WHERE id = $1
 	`
 
@@ -146,10 +207,12 @@ func (r *blacklistRepository) Update(ctx context.Context, entry *domain.Blacklis
 		entry.Reason,
 		entry.ExpiresAt,
 		entry.IsActive,
+		entry.Zone,
 	)
 
 	if err != nil {
-		return err
+		// Смена зоны на ту, где номер уже записан
+		return mapUniqueViolation(err, domain.ErrBlacklistEntryAlreadyExists)
 	}
 
 	if result.RowsAffected() == 0 {
@@ -176,7 +239,7 @@ func (r *blacklistRepository) Delete(ctx context.Context, id uuid.UUID) error {
 
 func (r *blacklistRepository) List(ctx context.Context, limit, offset int) ([]*domain.BlacklistEntry, error) {
 	query := `
-		SELECT id, license_plate, reason, added_by, added_at, expires_at, is_active
+		SELECT id, license_plate, reason, added_by, added_at, expires_at, is_active, zone
 		FROM blacklist
 		ORDER BY added_at DESC
 		LIMIT $1 OFFSET $2
@@ -193,7 +256,7 @@ func (r *blacklistRepository) List(ctx context.Context, limit, offset int) ([]*d
 
 func (r *blacklistRepository) GetExpired(ctx context.Context) ([]*domain.BlacklistEntry, error) {
 	query := `
-		SELECT id, license_plate, reason, added_by, added_at, expires_at, is_active
+		SELECT id, license_plate, reason, added_by, added_at, expires_at, is_active, zone
 		FROM blacklist
 		WHERE is_active = true
 		  AND expires_at IS NOT NULL
@@ -221,6 +284,7 @@ func (r *blacklistRepository) scanEntries(rows pgx.Rows) ([]*domain.BlacklistEnt
 			&entry.AddedAt,
 			&entry.ExpiresAt,
 			&entry.IsActive,
+			&entry.Zone,
 		)
 		if err != nil {
 			return nil, err
@@ -13,7 +13,7 @@ import (
 )
 
 type passRepository struct {
-	db *pgxpool.Pool
+	db dbtx
 }
 
 func NewPassRepository(db *pgxpool.Pool) repository.PassRepository {
@@ -21,16 +21,22 @@ func NewPassRepository(db *pgxpool.Pool) repository.PassRepository {
 }
 
 func (r *passRepository) Create(ctx context.Context, pass *domain.Pass) error {
+	return insertPass(ctx, r.db, pass)
+}
+
+// insertPass записывает пропуск; принимает пул или транзакцию
+func insertPass(ctx context.Context, db execer, pass *domain.Pass) error {
 	query := `
-		INSERT INTO passes (id, user_id, pass_type, valid_from, valid_until, is_active, created_at, created_by, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO passes (id, user_id, pass_type, valid_from, valid_until, is_active, created_at, created_by, updated_at,
+		                    allowed_time_start, allowed_time_end, allowed_weekdays, max_uses, zone, is_guest)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10::time, $11::time, $12, $13, $14, $15)
 	`
 
 	pass.ID = uuid.New()
 	pass.CreatedAt = time.Now()
 	pass.UpdatedAt = time.Now()
 
-	_, err := r.db.Exec(ctx, query,
+	_, err := db.Exec(ctx, query,
 		pass.ID,
 		pass.UserID,
 		pass.PassType,
@@ -40,6 +46,12 @@ func (r *passRepository) Create(ctx context.Context, pass *domain.Pass) error {
 		pass.CreatedAt,
 		pass.CreatedBy,
 		pass.UpdatedAt,
+		pass.AllowedTimeStart,
+		pass.AllowedTimeEnd,
+		weekdaysToInts(pass.AllowedWeekdays),
+		pass.MaxUses,
+		pass.Zone,
+		pass.IsGuest,
 	)
 
 	return err
@@ -48,27 +60,14 @@ func (r *passRepository) Create(ctx context.Context, pass *domain.Pass) error {
 func (r *passRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Pass, error) {
 	query := `
 		SELECT id, user_id, pass_type, valid_from, valid_until, is_active,
-		       revoked_at, revoked_by, revoke_reason, created_at, created_by, updated_at
+		       revoked_at, revoked_by, revoke_reason, created_at, created_by, updated_at,
+		       to_char(allowed_time_start, 'HH24:MI'), to_char(allowed_time_end, 'HH24:MI'), allowed_weekdays,
+		       max_uses, uses_count, zone, extended_at, extended_by, is_guest
 		FROM passes
 		WHERE id = $1
 	`
 
-	pass := &domain.Pass{}
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&pass.ID,
-		&pass.UserID,
-		&pass.PassType,
-		&pass.ValidFrom,
-		&pass.ValidUntil,
-		&pass.IsActive,
-		&pass.RevokedAt,
-		&pass.RevokedBy,
-		&pass.RevokeReason,
-		&pass.CreatedAt,
-		&pass.CreatedBy,
-		&pass.UpdatedAt,
-	)
-
+	pass, err := scanPass(r.db.QueryRow(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrPassNotFound
@@ -79,10 +78,80 @@ func (r *passRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Pas
 	return pass, nil
 }
 
+// GetByIDWithVehicles заполняет Vehicles автомобилями из pass_vehicles в порядке привязки
+func (r *passRepository) GetByIDWithVehicles(ctx context.Context, id uuid.UUID) (*domain.Pass, error) {
+	pass, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.LoadVehicles(ctx, []*domain.Pass{pass}); err != nil {
+		return nil, err
+	}
+
+	return pass, nil
+}
+
+// LoadVehicles одним запросом заполняет Vehicles у всех переданных пропусков
+func (r *passRepository) LoadVehicles(ctx context.Context, passes []*domain.Pass) error {
+	if len(passes) == 0 {
+		return nil
+	}
+
+	byID := make(map[uuid.UUID]*domain.Pass, len(passes))
+	ids := make([]uuid.UUID, 0, len(passes))
+	for _, pass := range passes {
+		pass.Vehicles = []*domain.Vehicle{}
+		byID[pass.ID] = pass
+		ids = append(ids, pass.ID)
+	}
+
+	query := `
+		SELECT pv.pass_id, v.id, v.owner_id, v.license_plate, v.vehicle_type, v.model, v.color, v.is_active,
+		       v.created_at, v.updated_at
+		FROM pass_vehicles pv
+		JOIN vehicles v ON v.id = pv.vehicle_id
+		WHERE pv.pass_id = ANY($1)
+		ORDER BY pv.added_at
+	`
+
+	rows, err := r.db.Query(ctx, query, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var passID uuid.UUID
+		vehicle := &domain.Vehicle{}
+		if err := rows.Scan(
+			&passID,
+			&vehicle.ID,
+			&vehicle.OwnerID,
+			&vehicle.LicensePlate,
+			&vehicle.VehicleType,
+			&vehicle.Model,
+			&vehicle.Color,
+			&vehicle.IsActive,
+			&vehicle.CreatedAt,
+			&vehicle.UpdatedAt,
+		); err != nil {
+			return err
+		}
+		if pass, ok := byID[passID]; ok {
+			pass.Vehicles = append(pass.Vehicles, vehicle)
+		}
+	}
+
+	return rows.Err()
+}
+
 func (r *passRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Pass, error) {
 	query := `
 		SELECT id, user_id, pass_type, valid_from, valid_until, is_active,
-		       revoked_at, revoked_by, revoke_reason, created_at, created_by, updated_at
+		       revoked_at, revoked_by, revoke_reason, created_at, created_by, updated_at,
+		       to_char(allowed_time_start, 'HH24:MI'), to_char(allowed_time_end, 'HH24:MI'), allowed_weekdays,
+		       max_uses, uses_count, zone, extended_at, extended_by, is_guest
 		FROM passes
 		WHERE user_id = $1
 		ORDER BY created_at DESC
@@ -100,7 +169,9 @@ func (r *passRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*
 func (r *passRepository) GetActivePassesByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Pass, error) {
 	query := `
 		SELECT id, user_id, pass_type, valid_from, valid_until, is_active,
-		       revoked_at, revoked_by, revoke_reason, created_at, created_by, updated_at
+		       revoked_at, revoked_by, revoke_reason, created_at, created_by, updated_at,
+		       to_char(allowed_time_start, 'HH24:MI'), to_char(allowed_time_end, 'HH24:MI'), allowed_weekdays,
+		       max_uses, uses_count, zone, extended_at, extended_by, is_guest
 		FROM passes
 		WHERE user_id = $1 AND is_active = true
 		ORDER BY created_at DESC
@@ -116,14 +187,17 @@ func (r *passRepository) GetActivePassesByUser(ctx context.Context, userID uuid.
 }
 
 // GetActivePassesByUserAndVehicle - КЛЮЧЕВОЙ МЕТОД для проверки доступа
-// Возвращает все активные пропуска пользователя, которые включают указанный автомобиль
+// Возвращает все активные пропуска пользователя, которые включают указанный автомобиль,
+// а также гостевые пропуска на этот автомобиль от любых жителей
 func (r *passRepository) GetActivePassesByUserAndVehicle(ctx context.Context, userID, vehicleID uuid.UUID) ([]*domain.Pass, error) {
 	query := `
 		SELECT DISTINCT p.id, p.user_id, p.pass_type, p.valid_from, p.valid_until, p.is_active,
-		       p.revoked_at, p.revoked_by, p.revoke_reason, p.created_at, p.created_by, p.updated_at
+		       p.revoked_at, p.revoked_by, p.revoke_reason, p.created_at, p.created_by, p.updated_at,
+		       to_char(p.allowed_time_start, 'HH24:MI'), to_char(p.allowed_time_end, 'HH24:MI'), p.allowed_weekdays,
+		       p.max_uses, p.uses_count, p.zone, p.extended_at, p.extended_by, p.is_guest
 		FROM passes p
 		INNER JOIN pass_vehicles pv ON p.id = pv.pass_id
-		WHERE p.user_id = $1
+		WHERE (p.user_id = $1 OR p.is_guest)
 		  AND pv.vehicle_id = $2
 		  AND p.is_active = true
 		ORDER BY p.created_at DESC
@@ -138,11 +212,39 @@ func (r *passRepository) GetActivePassesByUserAndVehicle(ctx context.Context, us
 	return r.scanPasses(rows)
 }
 
+// FindOverlapping ищет дубликаты выдаваемого пропуска: бессрочные пропуска (valid_until IS NULL)
+// пересекаются с любым сроком, пропуска без зоны совпадают только с пропусками без зоны
+func (r *passRepository) FindOverlapping(ctx context.Context, pass *domain.Pass, vehicleIDs []uuid.UUID) ([]*domain.Pass, error) {
+	query := `
+		SELECT id, user_id, pass_type, valid_from, valid_until, is_active,
+		       revoked_at, revoked_by, revoke_reason, created_at, created_by, updated_at,
+		       to_char(allowed_time_start, 'HH24:MI'), to_char(allowed_time_end, 'HH24:MI'), allowed_weekdays,
+		       max_uses, uses_count, zone, extended_at, extended_by, is_guest
+		FROM passes p
+		WHERE p.user_id = $1
+		  AND p.pass_type = $2
+		  AND p.is_active = true
+		  AND p.zone IS NOT DISTINCT FROM $3
+		  AND (p.valid_until IS NULL OR p.valid_until > $4)
+		  AND ($5::timestamp IS NULL OR p.valid_from < $5)
+		  AND EXISTS (
+			SELECT 1 FROM pass_vehicles pv
+			WHERE pv.pass_id = p.id AND pv.vehicle_id = ANY($6)
+		  )
+		ORDER BY p.created_at
+	`
+
+	return queryRows(ctx, r.db, scanPass, query,
+		pass.UserID, pass.PassType, pass.Zone, pass.ValidFrom, pass.ValidUntil, vehicleIDs)
+}
+
 func (r *passRepository) Update(ctx context.Context, pass *domain.Pass) error {
 	query := `
 		UPDATE passes
 		SET user_id = $2, pass_type = $3, valid_from = $4, valid_until = $5, is_active = $6,
-		    revoked_at = $7, revoked_by = $8, revoke_reason = $9, updated_at = $10
+		    revoked_at = $7, revoked_by = $8, revoke_reason = $9, updated_at = $10,
+		    allowed_time_start = $11::time, allowed_time_end = $12::time, allowed_weekdays = $13,
+		    max_uses = $14, zone = $15, extended_at = $16, extended_by = $17
 		WHERE id = $1
 	`
 
@@ -159,6 +261,13 @@ func (r *passRepository) Update(ctx context.Context, pass *domain.Pass) error {
 		pass.RevokedBy,
 		pass.RevokeReason,
 		pass.UpdatedAt,
+		pass.AllowedTimeStart,
+		pass.AllowedTimeEnd,
+		weekdaysToInts(pass.AllowedWeekdays),
+		pass.MaxUses,
+		pass.Zone,
+		pass.ExtendedAt,
+		pass.ExtendedBy,
 	)
 
 	if err != nil {
@@ -176,7 +285,7 @@ func (r *passRepository) Revoke(ctx context.Context, id, revokedBy uuid.UUID, re
 	query := `
 		UPDATE passes
 		SET is_active = false, revoked_at = $2, revoked_by = $3, revoke_reason = $4, updated_at = $2
-		WHERE id = $1
+		WHERE id = $1 AND is_active = true
 	`
 
 	now := time.Now()
@@ -185,35 +294,131 @@ func (r *passRepository) Revoke(ctx context.Context, id, revokedBy uuid.UUID, re
 		return err
 	}
 
-	if result.RowsAffected() == 0 {
-		return domain.ErrPassNotFound
+	if result.RowsAffected() > 0 {
+		return nil
 	}
 
-	return nil
+	// Ни одна строка не обновлена: пропуска нет или его уже отозвали (в т.ч. параллельным запросом)
+	var exists bool
+	if err := r.db.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM passes WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return domain.ErrPassAlreadyRevoked
+	}
+
+	return domain.ErrPassNotFound
 }
 
-func (r *passRepository) List(ctx context.Context, limit, offset int) ([]*domain.Pass, error) {
+// Extend записывает продленный срок действия; условие в UPDATE не дает продлить пропуск,
+// отозванный или продленный дальше параллельным запросом после проверки в сервисе
+func (r *passRepository) Extend(ctx context.Context, pass *domain.Pass) error {
 	query := `
-		SELECT id, user_id, pass_type, valid_from, valid_until, is_active,
-		       revoked_at, revoked_by, revoke_reason, created_at, created_by, updated_at
-		FROM passes
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
+		UPDATE passes
+		SET valid_until = $2, is_active = true, extended_at = $3, extended_by = $4, updated_at = $5
+		WHERE id = $1 AND revoked_at IS NULL AND (valid_until IS NULL OR valid_until < $2)
 	`
 
-	rows, err := r.db.Query(ctx, query, limit, offset)
+	result, err := r.db.Exec(ctx, query, pass.ID, pass.ValidUntil, pass.ExtendedAt, pass.ExtendedBy, pass.UpdatedAt)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer rows.Close()
 
-	return r.scanPasses(rows)
+	if result.RowsAffected() > 0 {
+		return nil
+	}
+
+	var revoked bool
+	err = r.db.QueryRow(ctx, `SELECT revoked_at IS NOT NULL FROM passes WHERE id = $1`, pass.ID).Scan(&revoked)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrPassNotFound
+		}
+		return err
+	}
+	if revoked {
+		return domain.ErrPassAlreadyRevoked
+	}
+
+	return domain.ErrInvalidDateRange
+}
+
+// IncrementUses увеличивает счетчик только пока он ниже лимита - условие в UPDATE
+// исключает перерасход при параллельных проездах; журнал пишется в той же транзакции
+func (r *passRepository) IncrementUses(ctx context.Context, passID uuid.UUID, accessLog *domain.AccessLog) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	result, err := tx.Exec(ctx, `
+		UPDATE passes
+		SET uses_count = uses_count + 1, updated_at = NOW()
+		WHERE id = $1 AND (max_uses IS NULL OR uses_count < max_uses)
+	`, passID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		var exists bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM passes WHERE id = $1)`, passID).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return domain.ErrPassUsageLimitReached
+		}
+		return domain.ErrPassNotFound
+	}
+
+	if err := insertAccessLog(ctx, tx, accessLog); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// passFilterCondition - условие выборки по PassFilter
+// Незаданные условия передаются как NULL и не ограничивают выборку; бессрочный пропуск
+// (valid_until IS NULL) действует до конца любого периода
+const passFilterCondition = `
+		WHERE ($1::uuid IS NULL OR user_id = $1)
+		  AND ($2::pass_type_enum IS NULL OR pass_type = $2)
+		  AND ($3::boolean IS NULL OR is_active = $3)
+		  AND ($4::timestamp IS NULL OR valid_until IS NULL OR valid_until >= $4)
+		  AND ($5::timestamp IS NULL OR valid_from < $5)`
+
+// passFilterArgs возвращает параметры passFilterCondition
+func passFilterArgs(filter domain.PassFilter) []any {
+	return []any{
+		filter.UserID,
+		nullIfEmpty(string(filter.PassType)),
+		filter.IsActive,
+		filter.ValidFrom,
+		filter.ValidTo,
+	}
+}
+
+// Query выбирает пропуска по PassFilter, новые первыми
+func (r *passRepository) Query(ctx context.Context, filter domain.PassFilter, limit, offset int) ([]*domain.Pass, error) {
+	query := `
+		SELECT id, user_id, pass_type, valid_from, valid_until, is_active,
+		       revoked_at, revoked_by, revoke_reason, created_at, created_by, updated_at,
+		       to_char(allowed_time_start, 'HH24:MI'), to_char(allowed_time_end, 'HH24:MI'), allowed_weekdays,
+		       max_uses, uses_count, zone, extended_at, extended_by, is_guest
+		FROM passes` + passFilterCondition + `
+		ORDER BY created_at DESC`
+
+	return queryPage(ctx, r.db, scanPass, query, limit, offset, passFilterArgs(filter)...)
 }
 
 func (r *passRepository) GetExpiredPasses(ctx context.Context) ([]*domain.Pass, error) {
 	query := `
 		SELECT id, user_id, pass_type, valid_from, valid_until, is_active,
-		       revoked_at, revoked_by, revoke_reason, created_at, created_by, updated_at
+		       revoked_at, revoked_by, revoke_reason, created_at, created_by, updated_at,
+		       to_char(allowed_time_start, 'HH24:MI'), to_char(allowed_time_end, 'HH24:MI'), allowed_weekdays,
+		       max_uses, uses_count, zone, extended_at, extended_by, is_guest
 		FROM passes
 		WHERE pass_type = 'temporary'
 		  AND is_active = true
@@ -229,30 +434,85 @@ func (r *passRepository) GetExpiredPasses(ctx context.Context) ([]*domain.Pass,
 	return r.scanPasses(rows)
 }
 
+// Count возвращает количество пропусков по тому же фильтру, что и Query
+func (r *passRepository) Count(ctx context.Context, filter domain.PassFilter) (int, error) {
+	query := `SELECT COUNT(*) FROM passes` + passFilterCondition
+
+	var count int
+	if err := r.db.QueryRow(ctx, query, passFilterArgs(filter)...).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *passRepository) CountGuestPassesSince(ctx context.Context, issuerID uuid.UUID, since time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM passes
+		WHERE is_guest
+		  AND created_by = $1
+		  AND created_at >= $2
+	`
+
+	var count int
+	if err := r.db.QueryRow(ctx, query, issuerID, since).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
 // scanPasses - вспомогательная функция для сканирования результатов запроса
 func (r *passRepository) scanPasses(rows pgx.Rows) ([]*domain.Pass, error) {
-	var passes []*domain.Pass
-	for rows.Next() {
-		pass := &domain.Pass{}
-		err := rows.Scan(
-			&pass.ID,
-			&pass.UserID,
-			&pass.PassType,
-			&pass.ValidFrom,
-			&pass.ValidUntil,
-			&pass.IsActive,
-			&pass.RevokedAt,
-			&pass.RevokedBy,
-			&pass.RevokeReason,
-			&pass.CreatedAt,
-			&pass.CreatedBy,
-			&pass.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		passes = append(passes, pass)
+	return collectRows(rows, scanPass)
+}
+
+// scanPass сканирует пропуск вместе с расписанием
+func scanPass(row pgx.Row) (*domain.Pass, error) {
+	pass := &domain.Pass{}
+	var weekdays []int16
+	err := row.Scan(
+		&pass.ID,
+		&pass.UserID,
+		&pass.PassType,
+		&pass.ValidFrom,
+		&pass.ValidUntil,
+		&pass.IsActive,
+		&pass.RevokedAt,
+		&pass.RevokedBy,
+		&pass.RevokeReason,
+		&pass.CreatedAt,
+		&pass.CreatedBy,
+		&pass.UpdatedAt,
+		&pass.AllowedTimeStart,
+		&pass.AllowedTimeEnd,
+		&weekdays,
+		&pass.MaxUses,
+		&pass.UsesCount,
+		&pass.Zone,
+		&pass.ExtendedAt,
+		&pass.ExtendedBy,
+		&pass.IsGuest,
+	)
+	if err != nil {
+		return nil, err
 	}
 
-	return passes, nil
+	for _, day := range weekdays {
+		pass.AllowedWeekdays = append(pass.AllowedWeekdays, time.Weekday(day))
+	}
+	return pass, nil
+}
+
+// weekdaysToInts переводит дни недели в SMALLINT[]; пустой список хранится как NULL
+func weekdaysToInts(days []time.Weekday) []int16 {
+	if len(days) == 0 {
+		return nil
+	}
+	result := make([]int16, len(days))
+	for i, day := range days {
+		result[i] = int16(day)
+	}
+	return result
 }
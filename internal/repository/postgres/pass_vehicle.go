@@ -13,7 +13,7 @@ import (
 )
 
 type passVehicleRepository struct {
-	db *pgxpool.Pool
+	db dbtx
 }
 
 func NewPassVehicleRepository(db *pgxpool.Pool) repository.PassVehicleRepository {
@@ -21,15 +21,22 @@ func NewPassVehicleRepository(db *pgxpool.Pool) repository.PassVehicleRepository
 }
 
 func (r *passVehicleRepository) Create(ctx context.Context, passVehicle *domain.PassVehicle) error {
+	return insertPassVehicle(ctx, r.db, passVehicle)
+}
+
+// insertPassVehicle привязывает автомобиль к пропуску; принимает пул или транзакцию
+func insertPassVehicle(ctx context.Context, db execer, passVehicle *domain.PassVehicle) error {
+	// Дубликат (pass_id, vehicle_id) не вставляется - о нем сообщаем отдельной ошибкой
 	query := `
 		INSERT INTO pass_vehicles (id, pass_id, vehicle_id, added_at, added_by)
 		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (pass_id, vehicle_id) DO NOTHING
 	`
 
 	passVehicle.ID = uuid.New()
 	passVehicle.AddedAt = time.Now()
 
-	_, err := r.db.Exec(ctx, query,
+	result, err := db.Exec(ctx, query,
 		passVehicle.ID,
 		passVehicle.PassID,
 		passVehicle.VehicleID,
@@ -41,6 +48,10 @@ func (r *passVehicleRepository) Create(ctx context.Context, passVehicle *domain.
 		return err
 	}
 
+	if result.RowsAffected() == 0 {
+		return domain.ErrPassVehicleAlreadyExists
+	}
+
 	return nil
 }
 
@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// querier - общий для pgxpool.Pool и pgx.Tx метод выборки
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// execer - общий для pgxpool.Pool и pgx.Tx метод изменения данных
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// uniqueViolationCode - SQLSTATE нарушения ограничения уникальности
+const uniqueViolationCode = "23505"
+
+// mapUniqueViolation заменяет нарушение уникальности (23505) на доменную ошибку target
+// Проверка существования в сервисе не спасает от гонки двух одновременных вставок -
+// дубликат в этом случае отсекает только ограничение в БД
+func mapUniqueViolation(err, target error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+		return target
+	}
+	return err
+}
+
+// txBeginner - источник транзакций: pgxpool.Pool или pgx.Tx (вложенная транзакция - SAVEPOINT)
+type txBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// dbtx - подключение репозитория: пул или транзакция UnitOfWork
+type dbtx interface {
+	querier
+	execer
+	txBeginner
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// scanFunc сканирует одну строку результата (pgx.Rows или pgx.Row из QueryRow)
+type scanFunc[T any] func(row pgx.Row) (T, error)
+
+// queryRows выполняет запрос и сканирует все строки результата
+func queryRows[T any](ctx context.Context, q querier, scan scanFunc[T], query string, args ...any) ([]T, error) {
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return collectRows(rows, scan)
+}
+
+// queryPage дописывает к запросу LIMIT/OFFSET следующими по номеру параметрами
+// Запрос должен сам задавать ORDER BY, иначе страницы не детерминированы
+func queryPage[T any](ctx context.Context, q querier, scan scanFunc[T], query string, limit, offset int, args ...any) ([]T, error) {
+	n := len(args)
+	paged := fmt.Sprintf("%s\n\t\tLIMIT $%d OFFSET $%d", query, n+1, n+2)
+
+	pageArgs := make([]any, 0, n+2)
+	pageArgs = append(pageArgs, args...)
+	pageArgs = append(pageArgs, limit, offset)
+
+	return queryRows(ctx, q, scan, paged, pageArgs...)
+}
+
+// collectRows сканирует строки, закрывает rows и возвращает ошибку итерации
+func collectRows[T any](rows pgx.Rows, scan scanFunc[T]) ([]T, error) {
+	defer rows.Close()
+
+	var items []T
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// eachRow выполняет запрос и передает строки в fn по одной, не накапливая результат
+// Соединение занято до конца итерации; ошибка fn прерывает выборку
+func eachRow[T any](ctx context.Context, q querier, scan scanFunc[T], fn func(T) error, query string, args ...any) error {
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,234 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRows - pgx.Rows поверх заранее заданных строк из одной колонки
+type fakeRows struct {
+	values  []string
+	pos     int
+	iterErr error
+	closed  bool
+}
+
+func (r *fakeRows) Close()                                       { r.closed = true }
+func (r *fakeRows) Err() error                                   { return r.iterErr }
+func (r *fakeRows) CommandTag() pgconn.CommandTag                { return pgconn.CommandTag{} }
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *fakeRows) Values() ([]any, error)                       { return nil, nil }
+func (r *fakeRows) RawValues() [][]byte                          { return nil }
+func (r *fakeRows) Conn() *pgx.Conn                              { return nil }
+
+func (r *fakeRows) Next() bool {
+	if r.pos >= len(r.values) {
+		return false
+	}
+	r.pos++
+	return true
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	*(dest[0].(*string)) = r.values[r.pos-1]
+	return nil
+}
+
+// fakeQuerier запоминает последний запрос и возвращает заданные rows
+type fakeQuerier struct {
+	rows  *fakeRows
+	err   error
+	query string
+	args  []any
+}
+
+func (q *fakeQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	q.query = sql
+	q.args = args
+	if q.err != nil {
+		return nil, q.err
+	}
+	return q.rows, nil
+}
+
+// fakeExecer возвращает заданную ошибку на любой Exec
+type fakeExecer struct {
+	err error
+}
+
+func (e *fakeExecer) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	if e.err != nil {
+		return pgconn.CommandTag{}, e.err
+	}
+	return pgconn.NewCommandTag("INSERT 0 1"), nil
+}
+
+// uniqueViolation - ошибка PostgreSQL о нарушении ограничения constraint
+func uniqueViolation(constraint string) error {
+	return &pgconn.PgError{Code: uniqueViolationCode, ConstraintName: constraint}
+}
+
+func scanString(row pgx.Row) (string, error) {
+	var s string
+	err := row.Scan(&s)
+	return s, err
+}
+
+func TestQueryRows(t *testing.T) {
+	tests := []struct {
+		name        string
+		querier     *fakeQuerier
+		scan        scanFunc[string]
+		expected    []string
+		expectedErr bool
+	}{
+		{
+			name:     "все строки сканируются по порядку",
+			querier:  &fakeQuerier{rows: &fakeRows{values: []string{"a", "b", "c"}}},
+			scan:     scanString,
+			expected: []string{"a", "b", "c"},
+		},
+		{
+			name:    "пустой результат",
+			querier: &fakeQuerier{rows: &fakeRows{}},
+			scan:    scanString,
+		},
+		{
+			name:        "ошибка запроса",
+			querier:     &fakeQuerier{err: errors.New("connection refused")},
+			scan:        scanString,
+			expectedErr: true,
+		},
+		{
+			name:    "ошибка сканирования прерывает выборку",
+			querier: &fakeQuerier{rows: &fakeRows{values: []string{"a", "b"}}},
+			scan: func(row pgx.Row) (string, error) {
+				return "", errors.New("cannot scan")
+			},
+			expectedErr: true,
+		},
+		{
+			name:        "ошибка итерации не теряется",
+			querier:     &fakeQuerier{rows: &fakeRows{values: []string{"a"}, iterErr: errors.New("connection reset")}},
+			scan:        scanString,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := queryRows(context.Background(), tt.querier, tt.scan, "SELECT name FROM t WHERE id = $1", 42)
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+				assert.Nil(t, items)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, items)
+			}
+
+			if tt.querier.rows != nil {
+				assert.True(t, tt.querier.rows.closed, "rows должны закрываться")
+			}
+			assert.Equal(t, []any{42}, tt.querier.args)
+		})
+	}
+}
+
+func TestQueryPage(t *testing.T) {
+	t.Run("LIMIT/OFFSET продолжают нумерацию параметров", func(t *testing.T) {
+		q := &fakeQuerier{rows: &fakeRows{values: []string{"a"}}}
+		args := make([]any, 1, 4)
+		args[0] = "x"
+
+		items, err := queryPage(context.Background(), q, scanString, "SELECT name FROM t WHERE k = $1 ORDER BY name", 10, 20, args...)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a"}, items)
+		assert.Contains(t, q.query, "LIMIT $2 OFFSET $3")
+		assert.Equal(t, []any{"x", 10, 20}, q.args)
+		assert.Len(t, args, 1, "аргументы вызывающего не изменяются")
+	})
+
+	t.Run("без фильтров пагинация занимает $1 и $2", func(t *testing.T) {
+		q := &fakeQuerier{rows: &fakeRows{}}
+
+		_, err := queryPage(context.Background(), q, scanString, "SELECT name FROM t ORDER BY name", 50, 0)
+
+		require.NoError(t, err)
+		assert.Contains(t, q.query, "LIMIT $1 OFFSET $2")
+		assert.Equal(t, []any{50, 0}, q.args)
+	})
+}
+
+func TestEachRow(t *testing.T) {
+	t.Run("строки передаются по порядку и rows закрываются", func(t *testing.T) {
+		q := &fakeQuerier{rows: &fakeRows{values: []string{"a", "b", "c"}}}
+
+		var got []string
+		err := eachRow(context.Background(), q, scanString, func(s string) error {
+			got = append(got, s)
+			return nil
+		}, "SELECT v FROM t")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b", "c"}, got)
+		assert.True(t, q.rows.closed)
+	})
+
+	t.Run("ошибка обработчика прерывает итерацию", func(t *testing.T) {
+		q := &fakeQuerier{rows: &fakeRows{values: []string{"a", "b", "c"}}}
+		stop := errors.New("client gone")
+
+		var got []string
+		err := eachRow(context.Background(), q, scanString, func(s string) error {
+			got = append(got, s)
+			if s == "b" {
+				return stop
+			}
+			return nil
+		}, "SELECT v FROM t")
+
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, []string{"a", "b"}, got)
+		assert.True(t, q.rows.closed)
+	})
+
+	t.Run("ошибка итерации возвращается", func(t *testing.T) {
+		iterErr := errors.New("connection reset")
+		q := &fakeQuerier{rows: &fakeRows{values: []string{"a"}, iterErr: iterErr}}
+
+		err := eachRow(context.Background(), q, scanString, func(string) error { return nil }, "SELECT v FROM t")
+		assert.ErrorIs(t, err, iterErr)
+	})
+}
+
+func TestMapUniqueViolation(t *testing.T) {
+	target := errors.New("already exists")
+	other := &pgconn.PgError{Code: "23503"} // foreign_key_violation
+
+	tests := []struct {
+		name     string
+		err      error
+		expected error
+	}{
+		{name: "нарушение уникальности", err: uniqueViolation("users_email_key"), expected: target},
+		{name: "обернутое нарушение уникальности", err: fmt.Errorf("exec: %w", uniqueViolation("users_email_key")), expected: target},
+		{name: "другая ошибка PostgreSQL", err: other, expected: other},
+		{name: "ErrNoRows не дубликат", err: pgx.ErrNoRows, expected: pgx.ErrNoRows},
+		{name: "нет ошибки", err: nil, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, mapUniqueViolation(tt.err, target))
+		})
+	}
+}
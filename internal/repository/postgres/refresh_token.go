@@ -2,15 +2,17 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/frontandrew/gate/internal/domain"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type refreshTokenRepository struct {
-	db *pgxpool.Pool
+	db dbtx
 }
 
 // NewRefreshTokenRepository создает новый экземпляр RefreshTokenRepository
@@ -52,24 +54,19 @@ func (r *refreshTokenRepository) GetByTokenHash(ctx context.Context, tokenHash s
 		WHERE token_hash = $1
 	`
 
-	var token domain.RefreshToken
-	err := r.db.QueryRow(ctx, query, tokenHash).Scan(
-		&token.ID,
-		&token.UserID,
-		&token.TokenHash,
-		&token.ExpiresAt,
-		&token.CreatedAt,
-		&token.RevokedAt,
-	)
-
+	token, err := scanRefreshToken(r.db.QueryRow(ctx, query, tokenHash))
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrRefreshTokenNotFound
+		}
 		return nil, fmt.Errorf("failed to get refresh token: %w", err)
 	}
 
-	return &token, nil
+	return token, nil
 }
 
 // Revoke отзывает refresh token
+// Возвращает ErrRefreshTokenNotFound, если токен не найден или уже отозван
 func (r *refreshTokenRepository) Revoke(ctx context.Context, tokenHash string) error {
 	query := `
 		UPDATE refresh_tokens
@@ -84,7 +81,7 @@ func (r *refreshTokenRepository) Revoke(ctx context.Context, tokenHash string) e
 
 	rowsAffected := result.RowsAffected()
 	if rowsAffected == 0 {
-		return fmt.Errorf("refresh token not found or already revoked")
+		return domain.ErrRefreshTokenNotFound
 	}
 
 	return nil
@@ -106,6 +103,44 @@ func (r *refreshTokenRepository) RevokeAllUserTokens(ctx context.Context, userID
 	return nil
 }
 
+// ListByUser возвращает активные сессии пользователя
+func (r *refreshTokenRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.RefreshToken, error) {
+	query := `
+		SELECT id, user_id, token_hash, expires_at, created_at, revoked_at
+		FROM refresh_tokens
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY created_at DESC
+	`
+
+	tokens, err := queryRows(ctx, r.db, scanRefreshToken, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list refresh tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// RevokeByID отзывает токен пользователя по ID
+// Возвращает ErrRefreshTokenNotFound, если токен не найден, чужой или уже отозван
+func (r *refreshTokenRepository) RevokeByID(ctx context.Context, id, userID uuid.UUID) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrRefreshTokenNotFound
+	}
+
+	return nil
+}
+
 // DeleteExpired удаляет истекшие токены
 func (r *refreshTokenRepository) DeleteExpired(ctx context.Context) error {
 	query := `
@@ -120,3 +155,19 @@ func (r *refreshTokenRepository) DeleteExpired(ctx context.Context) error {
 
 	return nil
 }
+
+func scanRefreshToken(row pgx.Row) (*domain.RefreshToken, error) {
+	token := &domain.RefreshToken{}
+	err := row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+		&token.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
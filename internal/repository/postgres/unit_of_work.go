@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/frontandrew/gate/internal/repository"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type unitOfWork struct {
+	db txBeginner
+}
+
+func NewUnitOfWork(db *pgxpool.Pool) repository.UnitOfWork {
+	return &unitOfWork{db: db}
+}
+
+// Do открывает транзакцию и передает в fn репозитории, работающие в ней
+// Собственные транзакции репозиториев (например, CreateBatch списков) внутри fn становятся SAVEPOINT
+func (u *unitOfWork) Do(ctx context.Context, fn func(repos repository.TxRepositories) error) error {
+	tx, err := u.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	// Откат после коммита ничего не делает; при ошибке или панике в fn транзакция отменяется
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := fn(newTxRepositories(tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func newTxRepositories(tx dbtx) repository.TxRepositories {
+	return repository.TxRepositories{
+		Users:         &userRepository{db: tx},
+		Vehicles:      &vehicleRepository{db: tx},
+		Passes:        &passRepository{db: tx},
+		PassVehicles:  &passVehicleRepository{db: tx},
+		AccessLogs:    &accessLogRepository{db: tx},
+		AuditLogs:     &auditLogRepository{db: tx},
+		Blacklist:     &blacklistRepository{db: tx},
+		Whitelist:     &whitelistRepository{db: tx},
+		RefreshTokens: &refreshTokenRepository{db: tx},
+	}
+}
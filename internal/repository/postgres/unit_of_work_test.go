@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePassStore - зафиксированные строки passes и pass_vehicles
+type fakePassStore struct {
+	passes       []uuid.UUID
+	passVehicles []uuid.UUID
+	failVehicle  uuid.UUID // Привязка этого автомобиля завершается ошибкой
+}
+
+func (s *fakePassStore) Begin(ctx context.Context) (pgx.Tx, error) {
+	return &fakePassTx{store: s}, nil
+}
+
+// fakePassTx копит вставки и переносит их в store только при Commit
+type fakePassTx struct {
+	pgx.Tx
+	store        *fakePassStore
+	passes       []uuid.UUID
+	passVehicles []uuid.UUID
+	done         bool
+}
+
+func (tx *fakePassTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	switch {
+	case strings.Contains(sql, "INSERT INTO passes"):
+		tx.passes = append(tx.passes, args[0].(uuid.UUID))
+	case strings.Contains(sql, "INSERT INTO pass_vehicles"):
+		vehicleID := args[2].(uuid.UUID)
+		if vehicleID == tx.store.failVehicle {
+			return pgconn.CommandTag{}, errors.New("foreign key violation")
+		}
+		tx.passVehicles = append(tx.passVehicles, vehicleID)
+	}
+	return pgconn.NewCommandTag("INSERT 0 1"), nil
+}
+
+func (tx *fakePassTx) Commit(ctx context.Context) error {
+	tx.store.passes = append(tx.store.passes, tx.passes...)
+	tx.store.passVehicles = append(tx.store.passVehicles, tx.passVehicles...)
+	tx.done = true
+	return nil
+}
+
+func (tx *fakePassTx) Rollback(ctx context.Context) error {
+	if tx.done {
+		return pgx.ErrTxClosed
+	}
+	tx.done = true
+	tx.passes, tx.passVehicles = nil, nil
+	return nil
+}
+
+func TestUnitOfWork_Do(t *testing.T) {
+	vehicleID := uuid.New()
+
+	// createPass - две операции разных репозиториев в одной транзакции
+	createPass := func(ctx context.Context, repos repository.TxRepositories, vehicleID uuid.UUID) (*domain.Pass, error) {
+		pass := &domain.Pass{UserID: uuid.New(), PassType: domain.PassTypePermanent, IsActive: true}
+		if err := repos.Passes.Create(ctx, pass); err != nil {
+			return nil, err
+		}
+		return pass, repos.PassVehicles.Create(ctx, &domain.PassVehicle{PassID: pass.ID, VehicleID: vehicleID})
+	}
+
+	t.Run("коммит при успехе", func(t *testing.T) {
+		store := &fakePassStore{}
+		uow := &unitOfWork{db: store}
+
+		var pass *domain.Pass
+		err := uow.Do(context.Background(), func(repos repository.TxRepositories) error {
+			var err error
+			pass, err = createPass(context.Background(), repos, vehicleID)
+			return err
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{pass.ID}, store.passes)
+		assert.Equal(t, []uuid.UUID{vehicleID}, store.passVehicles)
+	})
+
+	t.Run("откат при ошибке репозитория", func(t *testing.T) {
+		store := &fakePassStore{failVehicle: vehicleID}
+		uow := &unitOfWork{db: store}
+
+		err := uow.Do(context.Background(), func(repos repository.TxRepositories) error {
+			_, err := createPass(context.Background(), repos, vehicleID)
+			return err
+		})
+
+		assert.Error(t, err)
+		assert.Empty(t, store.passes, "пропуск, вставленный до ошибки, откатывается")
+		assert.Empty(t, store.passVehicles)
+	})
+
+	t.Run("откат при ошибке бизнес-логики", func(t *testing.T) {
+		store := &fakePassStore{}
+		uow := &unitOfWork{db: store}
+		errRejected := errors.New("rejected")
+
+		err := uow.Do(context.Background(), func(repos repository.TxRepositories) error {
+			if _, err := createPass(context.Background(), repos, vehicleID); err != nil {
+				return err
+			}
+			return errRejected
+		})
+
+		assert.ErrorIs(t, err, errRejected)
+		assert.Empty(t, store.passes)
+		assert.Empty(t, store.passVehicles)
+	})
+
+	t.Run("откат при панике", func(t *testing.T) {
+		store := &fakePassStore{}
+		uow := &unitOfWork{db: store}
+
+		assert.Panics(t, func() {
+			_ = uow.Do(context.Background(), func(repos repository.TxRepositories) error {
+				if _, err := createPass(context.Background(), repos, vehicleID); err != nil {
+					return err
+				}
+				panic("unexpected")
+			})
+		})
+		assert.Empty(t, store.passes)
+	})
+}
@@ -14,7 +14,7 @@ import (
 
 // userRepository - PostgreSQL реализация UserRepository
 type userRepository struct {
-	db *pgxpool.Pool
+	db dbtx
 }
 
 // NewUserRepository создает новый экземпляр userRepository
@@ -23,6 +23,11 @@ func NewUserRepository(db *pgxpool.Pool) repository.UserRepository {
 }
 
 func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
+	return insertUser(ctx, r.db, user)
+}
+
+// insertUser записывает пользователя; занятый email - ErrUserAlreadyExists
+func insertUser(ctx context.Context, db execer, user *domain.User) error {
 	query := `
 		INSERT INTO users (id, email, password_hash, full_name, phone, role, is_active, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
@@ -32,7 +37,7 @@ func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
 	user.CreatedAt = time.Now()
 	user.UpdatedAt = time.Now()
 
-	_, err := r.db.Exec(ctx, query,
+	_, err := db.Exec(ctx, query,
 		user.ID,
 		user.Email,
 		user.PasswordHash,
@@ -45,23 +50,28 @@ func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
 	)
 
 	if err != nil {
-		// Проверяем ошибку уникальности email
-		if errors.Is(err, pgx.ErrNoRows) {
-			return domain.ErrUserAlreadyExists
-		}
-		return err
+		return mapUniqueViolation(err, domain.ErrUserAlreadyExists)
 	}
 
 	return nil
 }
 
-func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
-	query := `
+const selectUserByID = `
 		SELECT id, email, password_hash, full_name, phone, role, is_active, created_at, updated_at, last_login_at
 		FROM users
 		WHERE id = $1
 	`
 
+func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	return r.getByID(ctx, selectUserByID, id)
+}
+
+// GetByIDForUpdate читает пользователя с блокировкой строки до конца транзакции
+func (r *userRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	return r.getByID(ctx, selectUserByID+" FOR UPDATE", id)
+}
+
+func (r *userRepository) getByID(ctx context.Context, query string, id uuid.UUID) (*domain.User, error) {
 	user := &domain.User{}
 	err := r.db.QueryRow(ctx, query, id).Scan(
 		&user.ID,
@@ -86,6 +96,20 @@ func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Use
 	return user, nil
 }
 
+func (r *userRepository) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.User, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT id, email, password_hash, full_name, phone, role, is_active, created_at, updated_at, last_login_at
+		FROM users
+		WHERE id = ANY($1)
+	`
+
+	return queryRows(ctx, r.db, scanUser, query, ids)
+}
+
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	query := `
 		SELECT id, email, password_hash, full_name, phone, role, is_active, created_at, updated_at, last_login_at
@@ -138,7 +162,7 @@ func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
 	)
 
 	if err != nil {
-		return err
+		return mapUniqueViolation(err, domain.ErrUserAlreadyExists)
 	}
 
 	if result.RowsAffected() == 0 {
@@ -168,15 +192,94 @@ func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
-func (r *userRepository) List(ctx context.Context, limit, offset int) ([]*domain.User, error) {
+func (r *userRepository) DisableAccess(ctx context.Context, id, revokedBy uuid.UUID, reason string) (*domain.UserDisableResult, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	now := time.Now()
+	result := &domain.UserDisableResult{UserID: id}
+
+	// Повторное отключение уже деактивированного пользователя допустимо -
+	// оно добивает то, что могло остаться активным
+	user, err := tx.Exec(ctx, `UPDATE users SET is_active = false, updated_at = $2 WHERE id = $1`, id, now)
+	if err != nil {
+		return nil, err
+	}
+	if user.RowsAffected() == 0 {
+		return nil, domain.ErrUserNotFound
+	}
+
+	vehicles, err := tx.Exec(ctx, `
+		UPDATE vehicles
+		SET is_active = false, updated_at = $2
+		WHERE owner_id = $1 AND is_active = true
+	`, id, now)
+	if err != nil {
+		return nil, err
+	}
+	result.VehiclesDeactivated = int(vehicles.RowsAffected())
+
+	passes, err := tx.Exec(ctx, `
+		UPDATE passes
+		SET is_active = false, revoked_at = $2, revoked_by = $3, revoke_reason = $4, updated_at = $2
+		WHERE user_id = $1 AND is_active = true
+	`, id, now, revokedBy, reason)
+	if err != nil {
+		return nil, err
+	}
+	result.PassesRevoked = int(passes.RowsAffected())
+
+	tokens, err := tx.Exec(ctx, `
+		UPDATE refresh_tokens
+		SET revoked_at = $2
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`, id, now)
+	if err != nil {
+		return nil, err
+	}
+	result.RefreshTokensRevoked = int(tokens.RowsAffected())
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CountActiveByRoleForUpdate считает активных пользователей с ролью, блокируя их строки до конца транзакции
+// FOR UPDATE не применяется к агрегату, поэтому строки блокируются в подзапросе
+func (r *userRepository) CountActiveByRoleForUpdate(ctx context.Context, role domain.UserRole) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM (
+			SELECT id
+			FROM users
+			WHERE role = $1 AND is_active = true
+			FOR UPDATE
+		) locked
+	`
+
+	var count int
+	if err := r.db.QueryRow(ctx, query, role).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *userRepository) List(ctx context.Context, limit, offset int, includeInactive bool) ([]*domain.User, error) {
 	query := `
 		SELECT id, email, password_hash, full_name, phone, role, is_active, created_at, updated_at, last_login_at
 		FROM users
+		WHERE $3 OR is_active = true
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
 	`
 
-	rows, err := r.db.Query(ctx, query, limit, offset)
+	rows, err := r.db.Query(ctx, query, limit, offset, includeInactive)
 	if err != nil {
 		return nil, err
 	}
@@ -225,3 +328,23 @@ func (r *userRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID) erro
 
 	return nil
 }
+
+func scanUser(row pgx.Row) (*domain.User, error) {
+	user := &domain.User{}
+	err := row.Scan(
+		&user.ID,
+		&user.Email,
+		&user.PasswordHash,
+		&user.FullName,
+		&user.Phone,
+		&user.Role,
+		&user.IsActive,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.LastLoginAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
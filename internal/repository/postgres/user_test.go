@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertUser(t *testing.T) {
+	t.Run("занятый email - ErrUserAlreadyExists", func(t *testing.T) {
+		db := &fakeExecer{err: uniqueViolation("users_email_key")}
+
+		err := insertUser(context.Background(), db, &domain.User{Email: "user@example.com"})
+
+		assert.ErrorIs(t, err, domain.ErrUserAlreadyExists)
+	})
+
+	t.Run("прочие ошибки возвращаются как есть", func(t *testing.T) {
+		dbErr := errors.New("connection refused")
+		db := &fakeExecer{err: dbErr}
+
+		err := insertUser(context.Background(), db, &domain.User{Email: "user@example.com"})
+
+		assert.ErrorIs(t, err, dbErr)
+		assert.NotErrorIs(t, err, domain.ErrUserAlreadyExists)
+	})
+
+	t.Run("успешная вставка заполняет ID", func(t *testing.T) {
+		user := &domain.User{Email: "user@example.com"}
+
+		require.NoError(t, insertUser(context.Background(), &fakeExecer{}, user))
+		assert.NotZero(t, user.ID)
+		assert.False(t, user.CreatedAt.IsZero())
+	})
+}
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/frontandrew/gate/internal/domain"
@@ -13,7 +14,7 @@ import (
 )
 
 type vehicleRepository struct {
-	db *pgxpool.Pool
+	db dbtx
 }
 
 func NewVehicleRepository(db *pgxpool.Pool) repository.VehicleRepository {
@@ -21,6 +22,11 @@ func NewVehicleRepository(db *pgxpool.Pool) repository.VehicleRepository {
 }
 
 func (r *vehicleRepository) Create(ctx context.Context, vehicle *domain.Vehicle) error {
+	return insertVehicle(ctx, r.db, vehicle)
+}
+
+// insertVehicle записывает автомобиль; занятый номер - ErrVehicleAlreadyExists
+func insertVehicle(ctx context.Context, db execer, vehicle *domain.Vehicle) error {
 	query := `
 		INSERT INTO vehicles (id, owner_id, license_plate, vehicle_type, model, color, is_active, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
@@ -33,7 +39,7 @@ func (r *vehicleRepository) Create(ctx context.Context, vehicle *domain.Vehicle)
 	// Нормализуем номер перед сохранением
 	vehicle.LicensePlate = domain.NormalizeLicensePlate(vehicle.LicensePlate)
 
-	_, err := r.db.Exec(ctx, query,
+	_, err := db.Exec(ctx, query,
 		vehicle.ID,
 		vehicle.OwnerID,
 		vehicle.LicensePlate,
@@ -46,7 +52,7 @@ func (r *vehicleRepository) Create(ctx context.Context, vehicle *domain.Vehicle)
 	)
 
 	if err != nil {
-		return err
+		return mapUniqueViolation(err, domain.ErrVehicleAlreadyExists)
 	}
 
 	return nil
@@ -59,19 +65,26 @@ func (r *vehicleRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.
 		WHERE id = $1
 	`
 
-	vehicle := &domain.Vehicle{}
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&vehicle.ID,
-		&vehicle.OwnerID,
-		&vehicle.LicensePlate,
-		&vehicle.VehicleType,
-		&vehicle.Model,
-		&vehicle.Color,
-		&vehicle.IsActive,
-		&vehicle.CreatedAt,
-		&vehicle.UpdatedAt,
-	)
+	vehicle, err := scanVehicle(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrVehicleNotFound
+		}
+		return nil, err
+	}
 
+	return vehicle, nil
+}
+
+// GetActiveByID не возвращает деактивированные (мягко удаленные) автомобили
+func (r *vehicleRepository) GetActiveByID(ctx context.Context, id uuid.UUID) (*domain.Vehicle, error) {
+	query := `
+		SELECT id, owner_id, license_plate, vehicle_type, model, color, is_active, created_at, updated_at
+		FROM vehicles
+		WHERE id = $1 AND is_active = true
+	`
+
+	vehicle, err := scanVehicle(r.db.QueryRow(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrVehicleNotFound
@@ -92,19 +105,7 @@ func (r *vehicleRepository) GetByLicensePlate(ctx context.Context, licensePlate
 	// Нормализуем номер перед поиском
 	normalizedPlate := domain.NormalizeLicensePlate(licensePlate)
 
-	vehicle := &domain.Vehicle{}
-	err := r.db.QueryRow(ctx, query, normalizedPlate).Scan(
-		&vehicle.ID,
-		&vehicle.OwnerID,
-		&vehicle.LicensePlate,
-		&vehicle.VehicleType,
-		&vehicle.Model,
-		&vehicle.Color,
-		&vehicle.IsActive,
-		&vehicle.CreatedAt,
-		&vehicle.UpdatedAt,
-	)
-
+	vehicle, err := scanVehicle(r.db.QueryRow(ctx, query, normalizedPlate))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrVehicleNotFound
@@ -115,41 +116,29 @@ func (r *vehicleRepository) GetByLicensePlate(ctx context.Context, licensePlate
 	return vehicle, nil
 }
 
-func (r *vehicleRepository) GetByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]*domain.Vehicle, error) {
+func (r *vehicleRepository) GetVehiclesByIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.Vehicle, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
 	query := `
 		SELECT id, owner_id, license_plate, vehicle_type, model, color, is_active, created_at, updated_at
 		FROM vehicles
-		WHERE owner_id = $1
-		ORDER BY created_at DESC
+		WHERE id = ANY($1)
 	`
 
-	rows, err := r.db.Query(ctx, query, ownerID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+	return queryRows(ctx, r.db, scanVehicle, query, ids)
+}
 
-	var vehicles []*domain.Vehicle
-	for rows.Next() {
-		vehicle := &domain.Vehicle{}
-		err := rows.Scan(
-			&vehicle.ID,
-			&vehicle.OwnerID,
-			&vehicle.LicensePlate,
-			&vehicle.VehicleType,
-			&vehicle.Model,
-			&vehicle.Color,
-			&vehicle.IsActive,
-			&vehicle.CreatedAt,
-			&vehicle.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		vehicles = append(vehicles, vehicle)
-	}
+func (r *vehicleRepository) GetByOwnerID(ctx context.Context, ownerID uuid.UUID, includeInactive bool) ([]*domain.Vehicle, error) {
+	query := `
+		SELECT id, owner_id, license_plate, vehicle_type, model, color, is_active, created_at, updated_at
+		FROM vehicles
+		WHERE owner_id = $1 AND ($2 OR is_active = true)
+		ORDER BY created_at DESC
+	`
 
-	return vehicles, nil
+	return queryRows(ctx, r.db, scanVehicle, query, ownerID, includeInactive)
 }
 
 func (r *vehicleRepository) Update(ctx context.Context, vehicle *domain.Vehicle) error {
@@ -174,7 +163,7 @@ func (r *vehicleRepository) Update(ctx context.Context, vehicle *domain.Vehicle)
 	)
 
 	if err != nil {
-		return err
+		return mapUniqueViolation(err, domain.ErrVehicleAlreadyExists)
 	}
 
 	if result.RowsAffected() == 0 {
@@ -204,39 +193,206 @@ func (r *vehicleRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+func (r *vehicleRepository) HardDelete(ctx context.Context, id uuid.UUID) (*domain.VehicleDeleteResult, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	result := &domain.VehicleDeleteResult{
+		VehicleID: id,
+		Hard:      true,
+	}
+
+	links, err := tx.Exec(ctx, `DELETE FROM pass_vehicles WHERE vehicle_id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	result.PassLinksRemoved = int(links.RowsAffected())
+
+	// access_logs.vehicle_id не каскадируется - обнуляем, номер в записи остается
+	logs, err := tx.Exec(ctx, `UPDATE access_logs SET vehicle_id = NULL WHERE vehicle_id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	result.AccessLogsDetached = int(logs.RowsAffected())
+
+	deleted, err := tx.Exec(ctx, `DELETE FROM vehicles WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if deleted.RowsAffected() == 0 {
+		return nil, domain.ErrVehicleNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 func (r *vehicleRepository) List(ctx context.Context, limit, offset int) ([]*domain.Vehicle, error) {
 	query := `
 		SELECT id, owner_id, license_plate, vehicle_type, model, color, is_active, created_at, updated_at
 		FROM vehicles
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
-	`
+		ORDER BY created_at DESC`
+
+	return queryPage(ctx, r.db, scanVehicle, query, limit, offset)
+}
+
+// Search выбирает автомобили по VehicleFilter; незаданные условия передаются как NULL
+func (r *vehicleRepository) Search(ctx context.Context, filter domain.VehicleFilter, limit, offset int) ([]*domain.Vehicle, error) {
+	query := `
+		SELECT id, owner_id, license_plate, vehicle_type, model, color, is_active, created_at, updated_at
+		FROM vehicles
+		WHERE ($1::uuid IS NULL OR owner_id = $1)
+		  AND ($2::boolean IS NULL OR is_active = $2)
+		ORDER BY created_at DESC`
 
-	rows, err := r.db.Query(ctx, query, limit, offset)
+	return queryPage(ctx, r.db, scanVehicle, query, limit, offset, filter.OwnerID, filter.IsActive)
+}
+
+// Count возвращает количество автомобилей по тому же фильтру, что и Search
+func (r *vehicleRepository) Count(ctx context.Context, filter domain.VehicleFilter) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM vehicles
+		WHERE ($1::uuid IS NULL OR owner_id = $1)
+		  AND ($2::boolean IS NULL OR is_active = $2)`
+
+	var count int
+	if err := r.db.QueryRow(ctx, query, filter.OwnerID, filter.IsActive).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// SearchByLicensePlate ищет номера, содержащие pattern; спецсимволы LIKE экранируются
+func (r *vehicleRepository) SearchByLicensePlate(ctx context.Context, pattern string, limit int, includeInactive bool) ([]*domain.Vehicle, error) {
+	query := `
+		SELECT id, owner_id, license_plate, vehicle_type, model, color, is_active, created_at, updated_at
+		FROM vehicles
+		WHERE license_plate ILIKE '%' || $1 || '%'
+		  AND ($3 OR is_active = true)
+		ORDER BY license_plate ILIKE $1 || '%' DESC, license_plate
+		LIMIT $2`
+
+	return queryRows(ctx, r.db, scanVehicle, query, escapeLike(pattern), limit, includeInactive)
+}
+
+// escapeLike экранирует символы шаблона LIKE, чтобы они искались буквально
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func (r *vehicleRepository) Merge(ctx context.Context, sourceID, targetID uuid.UUID) (*domain.VehicleMergeResult, error) {
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	defer func() { _ = tx.Rollback(ctx) }()
 
-	var vehicles []*domain.Vehicle
-	for rows.Next() {
-		vehicle := &domain.Vehicle{}
-		err := rows.Scan(
-			&vehicle.ID,
-			&vehicle.OwnerID,
-			&vehicle.LicensePlate,
-			&vehicle.VehicleType,
-			&vehicle.Model,
-			&vehicle.Color,
-			&vehicle.IsActive,
-			&vehicle.CreatedAt,
-			&vehicle.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
+	result := &domain.VehicleMergeResult{
+		SourceID: sourceID,
+		TargetID: targetID,
+	}
+
+	// Target блокируется до конца транзакции: от его владельца зависит, какие связи можно перенести
+	var targetOwnerID uuid.UUID
+	var targetActive bool
+	err = tx.QueryRow(ctx, `SELECT owner_id, is_active FROM vehicles WHERE id = $1 FOR UPDATE`, targetID).
+		Scan(&targetOwnerID, &targetActive)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrVehicleNotFound
 		}
-		vehicles = append(vehicles, vehicle)
+		return nil, err
+	}
+	if !targetActive {
+		return nil, domain.ErrVehicleInactive
 	}
 
-	return vehicles, nil
+	// Связи с пропусками, которые уже есть у target, удаляем - иначе нарушится unique_pass_vehicle
+	dropped, err := tx.Exec(ctx, `
+		DELETE FROM pass_vehicles src
+		WHERE src.vehicle_id = $1
+		  AND EXISTS (
+			SELECT 1 FROM pass_vehicles dst
+			WHERE dst.pass_id = src.pass_id AND dst.vehicle_id = $2
+		  )
+	`, sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	result.PassLinksDropped = int(dropped.RowsAffected())
+
+	// Переносятся связи с пропусками владельца target: обычный пропуск не может ссылаться
+	// на автомобиль другого пользователя (см. ErrPassVehicleOwnerMismatch).
+	// Гостевые пропуска действуют для автомобиля любого владельца и переносятся всегда
+	moved, err := tx.Exec(ctx, `
+		UPDATE pass_vehicles pv
+		SET vehicle_id = $2
+		FROM passes p
+		WHERE pv.vehicle_id = $1 AND p.id = pv.pass_id AND (p.user_id = $3 OR p.is_guest)
+	`, sourceID, targetID, targetOwnerID)
+	if err != nil {
+		return nil, err
+	}
+	result.PassLinksMoved = int(moved.RowsAffected())
+
+	// Оставшиеся связи source - с обычными пропусками прежнего владельца: на чужом автомобиле они бы не работали
+	revoked, err := tx.Exec(ctx, `DELETE FROM pass_vehicles WHERE vehicle_id = $1`, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	result.PassLinksRevoked = int(revoked.RowsAffected())
+
+	// Журнал проездов переходит к target вместе с владельцем, чтобы история пользователя и автомобиля совпадала
+	logs, err := tx.Exec(ctx, `UPDATE access_logs SET vehicle_id = $2, user_id = $3 WHERE vehicle_id = $1`,
+		sourceID, targetID, targetOwnerID)
+	if err != nil {
+		return nil, err
+	}
+	result.AccessLogsMoved = int(logs.RowsAffected())
+
+	deactivated, err := tx.Exec(ctx, `
+		UPDATE vehicles
+		SET is_active = false, updated_at = $2
+		WHERE id = $1
+	`, sourceID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if deactivated.RowsAffected() == 0 {
+		return nil, domain.ErrVehicleNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// scanVehicle сканирует строку vehicles в порядке колонок SELECT выше
+func scanVehicle(row pgx.Row) (*domain.Vehicle, error) {
+	vehicle := &domain.Vehicle{}
+	err := row.Scan(
+		&vehicle.ID,
+		&vehicle.OwnerID,
+		&vehicle.LicensePlate,
+		&vehicle.VehicleType,
+		&vehicle.Model,
+		&vehicle.Color,
+		&vehicle.IsActive,
+		&vehicle.CreatedAt,
+		&vehicle.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return vehicle, nil
 }
@@ -0,0 +1,328 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertVehicle(t *testing.T) {
+	t.Run("занятый номер - ErrVehicleAlreadyExists", func(t *testing.T) {
+		db := &fakeExecer{err: uniqueViolation("vehicles_license_plate_key")}
+
+		err := insertVehicle(context.Background(), db, &domain.Vehicle{LicensePlate: "A123BC777"})
+
+		assert.ErrorIs(t, err, domain.ErrVehicleAlreadyExists)
+	})
+
+	t.Run("прочие ошибки возвращаются как есть", func(t *testing.T) {
+		dbErr := errors.New("connection refused")
+		db := &fakeExecer{err: dbErr}
+
+		err := insertVehicle(context.Background(), db, &domain.Vehicle{LicensePlate: "A123BC777"})
+
+		assert.ErrorIs(t, err, dbErr)
+	})
+
+	t.Run("номер нормализуется перед вставкой", func(t *testing.T) {
+		vehicle := &domain.Vehicle{LicensePlate: " a123bc 777 "}
+
+		require.NoError(t, insertVehicle(context.Background(), &fakeExecer{}, vehicle))
+		assert.Equal(t, domain.NormalizeLicensePlate(" a123bc 777 "), vehicle.LicensePlate)
+		assert.NotZero(t, vehicle.ID)
+	})
+}
+
+// mergeState - строки таблиц, которые затрагивает Merge
+type mergeState struct {
+	vehicles   map[uuid.UUID]*mergeVehicle
+	passOwners map[uuid.UUID]uuid.UUID // passes.id -> passes.user_id
+	guest      map[uuid.UUID]bool      // passes.id -> passes.is_guest
+	links      []mergeLink             // pass_vehicles
+	logs       []mergeLog              // access_logs
+}
+
+type mergeVehicle struct {
+	ownerID  uuid.UUID
+	isActive bool
+}
+
+type mergeLink struct{ passID, vehicleID uuid.UUID }
+
+type mergeLog struct{ vehicleID, userID uuid.UUID }
+
+func (s *mergeState) clone() *mergeState {
+	c := &mergeState{
+		vehicles:   make(map[uuid.UUID]*mergeVehicle, len(s.vehicles)),
+		passOwners: s.passOwners,
+		guest:      s.guest,
+		links:      append([]mergeLink(nil), s.links...),
+		logs:       append([]mergeLog(nil), s.logs...),
+	}
+	for id, v := range s.vehicles {
+		copied := *v
+		c.vehicles[id] = &copied
+	}
+	return c
+}
+
+func (s *mergeState) hasLink(passID, vehicleID uuid.UUID) bool {
+	for _, link := range s.links {
+		if link.passID == passID && link.vehicleID == vehicleID {
+			return true
+		}
+	}
+	return false
+}
+
+// fakeMergeDB исполняет запросы Merge над mergeState; изменения видны только после Commit
+type fakeMergeDB struct {
+	dbtx
+	state *mergeState
+}
+
+func (db *fakeMergeDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	return &fakeMergeTx{db: db, state: db.state.clone()}, nil
+}
+
+type fakeMergeTx struct {
+	pgx.Tx
+	db    *fakeMergeDB
+	state *mergeState
+	done  bool
+}
+
+func (tx *fakeMergeTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	if !strings.Contains(sql, "SELECT owner_id, is_active FROM vehicles") {
+		return fakeRow{err: errors.New("unexpected query: " + sql)}
+	}
+	vehicle, ok := tx.state.vehicles[args[0].(uuid.UUID)]
+	if !ok {
+		return fakeRow{err: pgx.ErrNoRows}
+	}
+	return fakeRow{values: []any{vehicle.ownerID, vehicle.isActive}}
+}
+
+func (tx *fakeMergeTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	sourceID := args[0].(uuid.UUID)
+	affected := 0
+
+	switch {
+	case strings.Contains(sql, "DELETE FROM pass_vehicles src"):
+		targetID := args[1].(uuid.UUID)
+		tx.state.links = filterLinks(tx.state.links, func(link mergeLink) bool {
+			drop := link.vehicleID == sourceID && tx.state.hasLink(link.passID, targetID)
+			if drop {
+				affected++
+			}
+			return !drop
+		})
+	case strings.Contains(sql, "UPDATE pass_vehicles pv"):
+		targetID, ownerID := args[1].(uuid.UUID), args[2].(uuid.UUID)
+		for i, link := range tx.state.links {
+			if link.vehicleID == sourceID && (tx.state.passOwners[link.passID] == ownerID || tx.state.guest[link.passID]) {
+				tx.state.links[i].vehicleID = targetID
+				affected++
+			}
+		}
+	case strings.Contains(sql, "DELETE FROM pass_vehicles WHERE vehicle_id"):
+		tx.state.links = filterLinks(tx.state.links, func(link mergeLink) bool {
+			if link.vehicleID == sourceID {
+				affected++
+				return false
+			}
+			return true
+		})
+	case strings.Contains(sql, "UPDATE access_logs"):
+		targetID, ownerID := args[1].(uuid.UUID), args[2].(uuid.UUID)
+		for i, log := range tx.state.logs {
+			if log.vehicleID == sourceID {
+				tx.state.logs[i] = mergeLog{vehicleID: targetID, userID: ownerID}
+				affected++
+			}
+		}
+	case strings.Contains(sql, "UPDATE vehicles"):
+		if vehicle, ok := tx.state.vehicles[sourceID]; ok {
+			vehicle.isActive = false
+			affected++
+		}
+	default:
+		return pgconn.CommandTag{}, errors.New("unexpected statement: " + sql)
+	}
+
+	return pgconn.NewCommandTag("UPDATE " + strconv.Itoa(affected)), nil
+}
+
+func (tx *fakeMergeTx) Commit(ctx context.Context) error {
+	tx.db.state = tx.state
+	tx.done = true
+	return nil
+}
+
+func (tx *fakeMergeTx) Rollback(ctx context.Context) error {
+	if tx.done {
+		return pgx.ErrTxClosed
+	}
+	tx.done = true
+	return nil
+}
+
+// fakeRow - pgx.Row из QueryRow: значения колонок (uuid.UUID или bool) либо ошибка
+type fakeRow struct {
+	values []any
+	err    error
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	for i, value := range r.values {
+		switch d := dest[i].(type) {
+		case *uuid.UUID:
+			*d = value.(uuid.UUID)
+		case *bool:
+			*d = value.(bool)
+		}
+	}
+	return nil
+}
+
+func filterLinks(links []mergeLink, keep func(mergeLink) bool) []mergeLink {
+	var kept []mergeLink
+	for _, link := range links {
+		if keep(link) {
+			kept = append(kept, link)
+		}
+	}
+	return kept
+}
+
+func TestVehicleRepository_Merge(t *testing.T) {
+	ownerID, otherOwnerID := uuid.New(), uuid.New()
+	sourceID, targetID, foreignTargetID, inactiveTargetID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	ownPass, sharedPass, otherPass, guestPass := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	residentID := uuid.New() // Житель, выдавший гостевой пропуск на source
+
+	newState := func() *mergeState {
+		return &mergeState{
+			vehicles: map[uuid.UUID]*mergeVehicle{
+				sourceID:         {ownerID: ownerID, isActive: true},
+				targetID:         {ownerID: ownerID, isActive: true},
+				foreignTargetID:  {ownerID: otherOwnerID, isActive: true},
+				inactiveTargetID: {ownerID: ownerID, isActive: false},
+			},
+			passOwners: map[uuid.UUID]uuid.UUID{ownPass: ownerID, sharedPass: ownerID, otherPass: otherOwnerID, guestPass: residentID},
+			guest:      map[uuid.UUID]bool{guestPass: true},
+			links: []mergeLink{
+				{passID: ownPass, vehicleID: sourceID},
+				{passID: sharedPass, vehicleID: sourceID},
+				{passID: sharedPass, vehicleID: targetID}, // Уже есть у target
+				{passID: otherPass, vehicleID: foreignTargetID},
+			},
+			logs: []mergeLog{
+				{vehicleID: sourceID, userID: ownerID},
+				{vehicleID: sourceID, userID: ownerID},
+				{vehicleID: targetID, userID: ownerID},
+			},
+		}
+	}
+
+	t.Run("один владелец: связи и журнал переходят на target", func(t *testing.T) {
+		db := &fakeMergeDB{state: newState()}
+		repo := &vehicleRepository{db: db}
+
+		result, err := repo.Merge(context.Background(), sourceID, targetID)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, result.PassLinksMoved)
+		assert.Equal(t, 1, result.PassLinksDropped)
+		assert.Zero(t, result.PassLinksRevoked)
+		assert.Equal(t, 2, result.AccessLogsMoved)
+
+		assert.ElementsMatch(t, []mergeLink{
+			{passID: ownPass, vehicleID: targetID},
+			{passID: sharedPass, vehicleID: targetID},
+			{passID: otherPass, vehicleID: foreignTargetID},
+		}, db.state.links)
+		for _, log := range db.state.logs {
+			assert.Equal(t, targetID, log.vehicleID)
+			assert.Equal(t, ownerID, log.userID)
+		}
+		assert.False(t, db.state.vehicles[sourceID].isActive, "source деактивирован")
+		assert.True(t, db.state.vehicles[targetID].isActive)
+	})
+
+	t.Run("разные владельцы: связи прежнего владельца удаляются, журнал переходит к новому", func(t *testing.T) {
+		db := &fakeMergeDB{state: newState()}
+		repo := &vehicleRepository{db: db}
+
+		result, err := repo.Merge(context.Background(), sourceID, foreignTargetID)
+		require.NoError(t, err)
+
+		assert.Zero(t, result.PassLinksMoved)
+		assert.Equal(t, 2, result.PassLinksRevoked)
+		assert.Equal(t, 2, result.AccessLogsMoved)
+
+		// Пропуска прежнего владельца не ссылаются на чужой автомобиль
+		for _, link := range db.state.links {
+			assert.NotEqual(t, sourceID, link.vehicleID)
+			if link.vehicleID == foreignTargetID {
+				assert.Equal(t, otherOwnerID, db.state.passOwners[link.passID])
+			}
+		}
+		assert.Equal(t, []mergeLog{
+			{vehicleID: foreignTargetID, userID: otherOwnerID},
+			{vehicleID: foreignTargetID, userID: otherOwnerID},
+			{vehicleID: targetID, userID: ownerID},
+		}, db.state.logs)
+		assert.False(t, db.state.vehicles[sourceID].isActive)
+	})
+
+	t.Run("гостевой пропуск другого жителя переходит на target", func(t *testing.T) {
+		for name, target := range map[string]uuid.UUID{"один владелец": targetID, "разные владельцы": foreignTargetID} {
+			t.Run(name, func(t *testing.T) {
+				state := newState()
+				state.links = append(state.links, mergeLink{passID: guestPass, vehicleID: sourceID})
+				db := &fakeMergeDB{state: state}
+				repo := &vehicleRepository{db: db}
+
+				_, err := repo.Merge(context.Background(), sourceID, target)
+				require.NoError(t, err)
+
+				assert.True(t, db.state.hasLink(guestPass, target), "гостевой пропуск не потерян")
+				assert.False(t, db.state.hasLink(guestPass, sourceID))
+			})
+		}
+	})
+
+	t.Run("неактивный target - ничего не меняется", func(t *testing.T) {
+		state := newState()
+		db := &fakeMergeDB{state: state}
+		repo := &vehicleRepository{db: db}
+
+		_, err := repo.Merge(context.Background(), sourceID, inactiveTargetID)
+
+		assert.ErrorIs(t, err, domain.ErrVehicleInactive)
+		assert.Same(t, state, db.state, "транзакция не зафиксирована")
+		assert.True(t, db.state.vehicles[sourceID].isActive)
+	})
+
+	t.Run("target не найден", func(t *testing.T) {
+		db := &fakeMergeDB{state: newState()}
+		repo := &vehicleRepository{db: db}
+
+		_, err := repo.Merge(context.Background(), sourceID, uuid.New())
+
+		assert.ErrorIs(t, err, domain.ErrVehicleNotFound)
+	})
+}
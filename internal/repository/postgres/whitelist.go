@@ -13,7 +13,7 @@ import (
 )
 
 type whitelistRepository struct {
-	db *pgxpool.Pool
+	db dbtx
 }
 
 func NewWhitelistRepository(db *pgxpool.Pool) repository.WhitelistRepository {
@@ -22,8 +22,9 @@ func NewWhitelistRepository(db *pgxpool.Pool) repository.WhitelistRepository {
 
 func (r *whitelistRepository) Create(ctx context.Context, entry *domain.WhitelistEntry) error {
 	query := `
-		INSERT INTO whitelist (id, license_plate, reason, added_by, added_at, expires_at, is_active)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO whitelist (id, license_plate, reason, added_by, added_at, expires_at, is_active, zone)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (license_plate, (COALESCE(zone, ''))) DO NOTHING
 	`
 
 	entry.ID = uuid.New()
@@ -32,7 +33,7 @@ func (r *whitelistRepository) Create(ctx context.Context, entry *domain.Whitelis
 	// Нормализуем номер
 	entry.LicensePlate = domain.NormalizeLicensePlate(entry.LicensePlate)
 
-	_, err := r.db.Exec(ctx, query,
+	result, err := r.db.Exec(ctx, query,
 		entry.ID,
 		entry.LicensePlate,
 		entry.Reason,
@@ -40,14 +41,71 @@ func (r *whitelistRepository) Create(ctx context.Context, entry *domain.Whitelis
 		entry.AddedAt,
 		entry.ExpiresAt,
 		entry.IsActive,
+		entry.Zone,
 	)
 
-	return err
+	if err != nil {
+		return mapUniqueViolation(err, domain.ErrWhitelistEntryAlreadyExists)
+	}
+
+	// Номер уникален в пределах зоны (без зоны - отдельное значение), включая неактивные записи
+	if result.RowsAffected() == 0 {
+		return domain.ErrWhitelistEntryAlreadyExists
+	}
+
+	return nil
+}
+
+// CreateBatch вставляет записи в одной транзакции
+// ON CONFLICT не прерывает транзакцию, поэтому дубликаты (в том числе внутри пакета) отмечаются построчно
+func (r *whitelistRepository) CreateBatch(ctx context.Context, entries []*domain.WhitelistEntry) ([]error, error) {
+	query := `
+		INSERT INTO whitelist (id, license_plate, reason, added_by, added_at, expires_at, is_active, zone)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (license_plate, (COALESCE(zone, ''))) DO NOTHING
+	`
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	now := time.Now()
+	rowErrors := make([]error, len(entries))
+	for i, entry := range entries {
+		entry.ID = uuid.New()
+		entry.AddedAt = now
+		entry.LicensePlate = domain.NormalizeLicensePlate(entry.LicensePlate)
+
+		result, err := tx.Exec(ctx, query,
+			entry.ID,
+			entry.LicensePlate,
+			entry.Reason,
+			entry.AddedBy,
+			entry.AddedAt,
+			entry.ExpiresAt,
+			entry.IsActive,
+			entry.Zone,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if result.RowsAffected() == 0 {
+			rowErrors[i] = domain.ErrWhitelistEntryAlreadyExists
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return rowErrors, nil
 }
 
 func (r *whitelistRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.WhitelistEntry, error) {
 	query := `
-		SELECT id, license_plate, reason, added_by, added_at, expires_at, is_active
+		SELECT id, license_plate, reason, added_by, added_at, expires_at, is_active, zone
 		FROM whitelist
 		WHERE id = $1
 	`
@@ -61,6 +119,7 @@ func (r *whitelistRepository) GetByID(ctx context.Context, id uuid.UUID) (*domai
 		&entry.AddedAt,
 		&entry.ExpiresAt,
 		&entry.IsActive,
+		&entry.Zone,
 	)
 
 	if err != nil {
@@ -73,17 +132,17 @@ func (r *whitelistRepository) GetByID(ctx context.Context, id uuid.UUID) (*domai
 	return entry, nil
 }
 
-func (r *whitelistRepository) GetByLicensePlate(ctx context.Context, licensePlate string) (*domain.WhitelistEntry, error) {
+func (r *whitelistRepository) GetByLicensePlate(ctx context.Context, licensePlate string, zone *string) (*domain.WhitelistEntry, error) {
 	query := `
-		SELECT id, license_plate, reason, added_by, added_at, expires_at, is_active
+		SELECT id, license_plate, reason, added_by, added_at, expires_at, is_active, zone
 		FROM whitelist
-		WHERE license_plate = $1 AND is_active = true
+		WHERE license_plate = $1 AND COALESCE(zone, '') = COALESCE($2::varchar, '') AND is_active = true
 	`
 
 	normalizedPlate := domain.NormalizeLicensePlate(licensePlate)
 
 	entry := &domain.WhitelistEntry{}
-	err := r.db.QueryRow(ctx, query, normalizedPlate).Scan(
+	err := r.db.QueryRow(ctx, query, normalizedPlate, zone).Scan(
 		&entry.ID,
 		&entry.LicensePlate,
 		&entry.Reason,
@@ -91,6 +150,7 @@ func (r *whitelistRepository) GetByLicensePlate(ctx context.Context, licensePlat
 		&entry.AddedAt,
 		&entry.ExpiresAt,
 		&entry.IsActive,
+		&entry.Zone,
 	)
 
 	if err != nil {
@@ -104,20 +164,21 @@ func (r *whitelistRepository) GetByLicensePlate(ctx context.Context, licensePlat
 }
 
 // IsWhitelisted - КРИТИЧНЫЙ МЕТОД для проверки доступа (ВЫСШИЙ ПРИОРИТЕТ)
-func (r *whitelistRepository) IsWhitelisted(ctx context.Context, licensePlate string) (bool, string, error) {
+func (r *whitelistRepository) IsWhitelisted(ctx context.Context, licensePlate, zone string) (bool, string, error) {
 	query := `
 		SELECT reason
 		FROM whitelist
 		WHERE license_plate = $1
 		  AND is_active = true
 		  AND (expires_at IS NULL OR expires_at > NOW())
+		  AND (zone IS NULL OR zone = $2)
 		LIMIT 1
 	`
 
 	normalizedPlate := domain.NormalizeLicensePlate(licensePlate)
 
 	var reason string
-	err := r.db.QueryRow(ctx, query, normalizedPlate).Scan(&reason)
+	err := r.db.QueryRow(ctx, query, normalizedPlate, zone).Scan(&reason)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -134,7 +195,7 @@ func (r *whitelistRepository) IsWhitelisted(ctx context.Context, licensePlate st
 func (r *whitelistRepository) Update(ctx context.Context, entry *domain.WhitelistEntry) error {
 	query := `
 		UPDATE whitelist
-		SET license_plate = $2, reason = $3, expires_at = $4, is_active = $5
+		SET license_plate = $2, reason = $3, expires_at = $4, is_active = $5, zone = $6
 		WHERE id = $1
 	`
 
@@ -146,10 +207,12 @@ func (r *whitelistRepository) Update(ctx context.Context, entry *domain.Whitelis
 		entry.Reason,
 		entry.ExpiresAt,
 		entry.IsActive,
+		entry.Zone,
 	)
 
 	if err != nil {
-		return err
+		// Смена зоны на ту, где номер уже записан
+		return mapUniqueViolation(err, domain.ErrWhitelistEntryAlreadyExists)
 	}
 
 	if result.RowsAffected() == 0 {
@@ -176,7 +239,7 @@ func (r *whitelistRepository) Delete(ctx context.Context, id uuid.UUID) error {
 
 func (r *whitelistRepository) List(ctx context.Context, limit, offset int) ([]*domain.WhitelistEntry, error) {
 	query := `
-		SELECT id, license_plate, reason, added_by, added_at, expires_at, is_active
+		SELECT id, license_plate, reason, added_by, added_at, expires_at, is_active, zone
 		FROM whitelist
 		ORDER BY added_at DESC
 		LIMIT $1 OFFSET $2
@@ -193,7 +256,7 @@ func (r *whitelistRepository) List(ctx context.Context, limit, offset int) ([]*d
 
 func (r *whitelistRepository) GetExpired(ctx context.Context) ([]*domain.WhitelistEntry, error) {
 	query := `
-		SELECT id, license_plate, reason, added_by, added_at, expires_at, is_active
+		SELECT id, license_plate, reason, added_by, added_at, expires_at, is_active, zone
 		FROM whitelist
 		WHERE is_active = true
 		  AND expires_at IS NOT NULL
@@ -221,6 +284,7 @@ func (r *whitelistRepository) scanEntries(rows pgx.Rows) ([]*domain.WhitelistEnt
 			&entry.AddedAt,
 			&entry.ExpiresAt,
 			&entry.IsActive,
+			&entry.Zone,
 		)
 		if err != nil {
 			return nil, err
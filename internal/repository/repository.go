@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/frontandrew/gate/internal/domain"
 	"github.com/google/uuid"
@@ -15,6 +16,14 @@ type UserRepository interface {
 	// GetByID возвращает пользователя по ID
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error)
 
+	// GetByIDForUpdate возвращает пользователя по ID и блокирует его строку до конца транзакции
+	// (см. UnitOfWork): параллельные операции от имени пользователя выполняются по очереди
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.User, error)
+
+	// GetUsersByIDs возвращает пользователей с указанными ID одним запросом, в том числе деактивированных;
+	// отсутствующие ID пропускаются
+	GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.User, error)
+
 	// GetByEmail возвращает пользователя по email
 	GetByEmail(ctx context.Context, email string) (*domain.User, error)
 
@@ -24,11 +33,20 @@ type UserRepository interface {
 	// Delete удаляет пользователя (мягкое удаление - is_active = false)
 	Delete(ctx context.Context, id uuid.UUID) error
 
-	// List возвращает список пользователей с пагинацией
-	List(ctx context.Context, limit, offset int) ([]*domain.User, error)
+	// List возвращает список пользователей с пагинацией; деактивированные - только при includeInactive
+	List(ctx context.Context, limit, offset int, includeInactive bool) ([]*domain.User, error)
 
 	// UpdateLastLogin обновляет время последнего входа
 	UpdateLastLogin(ctx context.Context, id uuid.UUID) error
+
+	// CountActiveByRoleForUpdate возвращает число активных пользователей с ролью и блокирует их строки
+	// до конца транзакции (см. UnitOfWork): параллельные понижения и отключения администраторов
+	// проверяют "не последний ли это администратор" по очереди
+	CountActiveByRoleForUpdate(ctx context.Context, role domain.UserRole) (int, error)
+
+	// DisableAccess в одной транзакции деактивирует пользователя и его автомобили,
+	// отзывает активные пропуска и refresh токены
+	DisableAccess(ctx context.Context, id, revokedBy uuid.UUID, reason string) (*domain.UserDisableResult, error)
 }
 
 // VehicleRepository определяет методы для работы с автомобилями
@@ -36,14 +54,21 @@ type VehicleRepository interface {
 	// Create создает новый автомобиль
 	Create(ctx context.Context, vehicle *domain.Vehicle) error
 
-	// GetByID возвращает автомобиль по ID
+	// GetByID возвращает автомобиль по ID, в том числе деактивированный
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Vehicle, error)
 
+	// GetActiveByID возвращает активный автомобиль по ID; деактивированный - ErrVehicleNotFound
+	GetActiveByID(ctx context.Context, id uuid.UUID) (*domain.Vehicle, error)
+
+	// GetVehiclesByIDs возвращает автомобили с указанными ID одним запросом, в том числе деактивированные;
+	// отсутствующие ID пропускаются
+	GetVehiclesByIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.Vehicle, error)
+
 	// GetByLicensePlate возвращает автомобиль по номеру
 	GetByLicensePlate(ctx context.Context, licensePlate string) (*domain.Vehicle, error)
 
-	// GetByOwnerID возвращает все автомобили пользователя
-	GetByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]*domain.Vehicle, error)
+	// GetByOwnerID возвращает автомобили пользователя; деактивированные - только при includeInactive
+	GetByOwnerID(ctx context.Context, ownerID uuid.UUID, includeInactive bool) ([]*domain.Vehicle, error)
 
 	// Update обновляет данные автомобиля
 	Update(ctx context.Context, vehicle *domain.Vehicle) error
@@ -51,8 +76,27 @@ type VehicleRepository interface {
 	// Delete удаляет автомобиль (мягкое удаление - is_active = false)
 	Delete(ctx context.Context, id uuid.UUID) error
 
+	// HardDelete физически удаляет автомобиль и его связи с пропусками,
+	// в журнале проездов ссылка обнуляется (история сохраняется по номеру)
+	HardDelete(ctx context.Context, id uuid.UUID) (*domain.VehicleDeleteResult, error)
+
 	// List возвращает список автомобилей с пагинацией
 	List(ctx context.Context, limit, offset int) ([]*domain.Vehicle, error)
+
+	// Search возвращает автомобили, удовлетворяющие фильтру, с пагинацией
+	Search(ctx context.Context, filter domain.VehicleFilter, limit, offset int) ([]*domain.Vehicle, error)
+
+	// Count возвращает количество автомобилей по фильтру (пустой фильтр - все автомобили)
+	Count(ctx context.Context, filter domain.VehicleFilter) (int, error)
+
+	// SearchByLicensePlate ищет автомобили по части номера (без учета регистра);
+	// совпадения с начала номера идут первыми; деактивированные - только при includeInactive
+	SearchByLicensePlate(ctx context.Context, pattern string, limit int, includeInactive bool) ([]*domain.Vehicle, error)
+
+	// Merge переносит связи с пропусками и журнал проездов с source на target
+	// и деактивирует source (в одной транзакции). Связи с пропусками другого владельца удаляются,
+	// журнал переходит к владельцу target; неактивный target - ErrVehicleInactive
+	Merge(ctx context.Context, sourceID, targetID uuid.UUID) (*domain.VehicleMergeResult, error)
 }
 
 // PassRepository определяет методы для работы с пропусками
@@ -63,27 +107,54 @@ type PassRepository interface {
 	// GetByID возвращает пропуск по ID
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Pass, error)
 
+	// GetByIDWithVehicles возвращает пропуск вместе с привязанными автомобилями
+	GetByIDWithVehicles(ctx context.Context, id uuid.UUID) (*domain.Pass, error)
+
+	// LoadVehicles заполняет Vehicles у пропусков одним запросом (без N+1 для списков)
+	LoadVehicles(ctx context.Context, passes []*domain.Pass) error
+
 	// GetByUserID возвращает все пропуска пользователя
 	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Pass, error)
 
 	// GetActivePassesByUser возвращает все активные пропуска пользователя
 	GetActivePassesByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Pass, error)
 
-	// GetActivePassesByUserAndVehicle возвращает активные пропуска пользователя, включающие указанный автомобиль
+	// GetActivePassesByUserAndVehicle возвращает активные пропуска пользователя, включающие указанный автомобиль,
+	// и гостевые пропуска на этот автомобиль, кем бы из жителей они ни были выданы
 	// КЛЮЧЕВОЙ МЕТОД для проверки доступа
 	GetActivePassesByUserAndVehicle(ctx context.Context, userID, vehicleID uuid.UUID) ([]*domain.Pass, error)
 
+	// FindOverlapping возвращает активные пропуска того же пользователя, типа и зоны, срок которых
+	// пересекается со сроком pass и которые включают хотя бы один из vehicleIDs
+	FindOverlapping(ctx context.Context, pass *domain.Pass, vehicleIDs []uuid.UUID) ([]*domain.Pass, error)
+
 	// Update обновляет данные пропуска
 	Update(ctx context.Context, pass *domain.Pass) error
 
-	// Revoke отзывает пропуск
+	// Revoke отзывает активный пропуск; ErrPassAlreadyRevoked, если он уже отозван
 	Revoke(ctx context.Context, id, revokedBy uuid.UUID, reason string) error
 
-	// List возвращает список всех пропусков с пагинацией
-	List(ctx context.Context, limit, offset int) ([]*domain.Pass, error)
+	// Extend сохраняет продленный срок действия, отметку о продлении и снова активирует пропуск
+	// ErrPassAlreadyRevoked, если пропуск отозван; ErrInvalidDateRange, если срок уже не раньше нового
+	Extend(ctx context.Context, pass *domain.Pass) error
+
+	// IncrementUses атомарно учитывает проезд по пропуску с лимитом и записывает его в журнал
+	// в одной транзакции: при ошибке записи счетчик не меняется, повтор не посчитается дважды
+	// ErrPassUsageLimitReached, если проезды уже исчерпаны (в т.ч. параллельным проездом)
+	IncrementUses(ctx context.Context, passID uuid.UUID, accessLog *domain.AccessLog) error
+
+	// Query возвращает пропуска по фильтру с пагинацией
+	Query(ctx context.Context, filter domain.PassFilter, limit, offset int) ([]*domain.Pass, error)
+
+	// Count возвращает количество пропусков по фильтру
+	Count(ctx context.Context, filter domain.PassFilter) (int, error)
 
 	// GetExpiredPasses возвращает истекшие временные пропуска
 	GetExpiredPasses(ctx context.Context) ([]*domain.Pass, error)
+
+	// CountGuestPassesSince возвращает количество гостевых пропусков, выданных пользователем
+	// начиная с указанного момента
+	CountGuestPassesSince(ctx context.Context, issuerID uuid.UUID, since time.Time) (int, error)
 }
 
 // PassVehicleRepository определяет методы для работы со связями пропуск-автомобиль
@@ -127,8 +198,30 @@ type AccessLogRepository interface {
 	// List возвращает список всех логов с пагинацией
 	List(ctx context.Context, limit, offset int) ([]*domain.AccessLog, error)
 
-	// GetStatsByPeriod возвращает статистику проездов за период
-	GetStatsByPeriod(ctx context.Context, from, to string) (map[string]interface{}, error)
+	// Search возвращает логи, удовлетворяющие фильтру, с пагинацией
+	Search(ctx context.Context, filter domain.AccessLogFilter, limit, offset int) ([]*domain.AccessLog, error)
+
+	// Count возвращает количество логов по фильтру (пустой фильтр - все логи)
+	Count(ctx context.Context, filter domain.AccessLogFilter) (int, error)
+
+	// Stream передает логи по фильтру в fn по одному (для выгрузок); ошибка fn прерывает выборку
+	Stream(ctx context.Context, filter domain.AccessLogFilter, fn func(*domain.AccessLog) error) error
+
+	// GetStatsByPeriod возвращает статистику проездов за период [from, to)
+	GetStatsByPeriod(ctx context.Context, from, to time.Time) (*domain.AccessStats, error)
+
+	// GetLatestByLicensePlate возвращает последний разрешенный проезд по номеру
+	// (без ворот в режиме наблюдения); ErrAccessLogNotFound - проездов еще не было
+	GetLatestByLicensePlate(ctx context.Context, licensePlate string) (*domain.AccessLog, error)
+}
+
+// AuditLogRepository определяет методы для работы с журналом аудита
+type AuditLogRepository interface {
+	// Create добавляет запись в журнал аудита
+	Create(ctx context.Context, entry *domain.AuditLog) error
+
+	// Search возвращает записи, удовлетворяющие фильтру, с пагинацией
+	Search(ctx context.Context, filter domain.AuditLogFilter, limit, offset int) ([]*domain.AuditLog, error)
 }
 
 // BlacklistRepository определяет методы для работы с черным списком
@@ -136,15 +229,22 @@ type BlacklistRepository interface {
 	// Create создает новую запись в черном списке
 	Create(ctx context.Context, entry *domain.BlacklistEntry) error
 
+	// CreateBatch создает записи в одной транзакции
+	// Дубликат номера не прерывает пакет: для него в срезе (по индексу записи) возвращается
+	// ErrBlacklistEntryAlreadyExists. Вторая ошибка - сбой транзакции, ни одна запись не сохранена
+	CreateBatch(ctx context.Context, entries []*domain.BlacklistEntry) ([]error, error)
+
 	// GetByID возвращает запись по ID
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.BlacklistEntry, error)
 
-	// GetByLicensePlate возвращает запись по номеру автомобиля
-	GetByLicensePlate(ctx context.Context, licensePlate string) (*domain.BlacklistEntry, error)
+	// GetByLicensePlate возвращает активную запись по номеру автомобиля в зоне (nil - запись без зоны)
+	// Номер уникален в пределах зоны: на разные зоны заводятся разные записи
+	GetByLicensePlate(ctx context.Context, licensePlate string, zone *string) (*domain.BlacklistEntry, error)
 
-	// IsBlacklisted проверяет, находится ли номер в черном списке
+	// IsBlacklisted проверяет, находится ли номер в черном списке для ворот зоны zone
+	// Учитываются записи без зоны и записи этой зоны; пустая zone - только записи без зоны
 	// Возвращает (isBlacklisted, reason, error)
-	IsBlacklisted(ctx context.Context, licensePlate string) (bool, string, error)
+	IsBlacklisted(ctx context.Context, licensePlate, zone string) (bool, string, error)
 
 	// Update обновляет запись
 	Update(ctx context.Context, entry *domain.BlacklistEntry) error
@@ -164,15 +264,22 @@ type WhitelistRepository interface {
 	// Create создает новую запись в белом списке
 	Create(ctx context.Context, entry *domain.WhitelistEntry) error
 
+	// CreateBatch создает записи в одной транзакции
+	// Дубликат номера не прерывает пакет: для него в срезе (по индексу записи) возвращается
+	// ErrWhitelistEntryAlreadyExists. Вторая ошибка - сбой транзакции, ни одна запись не сохранена
+	CreateBatch(ctx context.Context, entries []*domain.WhitelistEntry) ([]error, error)
+
 	// GetByID возвращает запись по ID
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.WhitelistEntry, error)
 
-	// GetByLicensePlate возвращает запись по номеру автомобиля
-	GetByLicensePlate(ctx context.Context, licensePlate string) (*domain.WhitelistEntry, error)
+	// GetByLicensePlate возвращает активную запись по номеру автомобиля в зоне (nil - запись без зоны)
+	// Номер уникален в пределах зоны: на разные зоны заводятся разные записи
+	GetByLicensePlate(ctx context.Context, licensePlate string, zone *string) (*domain.WhitelistEntry, error)
 
-	// IsWhitelisted проверяет, находится ли номер в белом списке
+	// IsWhitelisted проверяет, находится ли номер в белом списке для ворот зоны zone
+	// Учитываются записи без зоны и записи этой зоны; пустая zone - только записи без зоны
 	// Возвращает (isWhitelisted, reason, error)
-	IsWhitelisted(ctx context.Context, licensePlate string) (bool, string, error)
+	IsWhitelisted(ctx context.Context, licensePlate, zone string) (bool, string, error)
 
 	// Update обновляет запись
 	Update(ctx context.Context, entry *domain.WhitelistEntry) error
@@ -192,15 +299,43 @@ type RefreshTokenRepository interface {
 	// Create сохраняет новый refresh token
 	Create(ctx context.Context, token *domain.RefreshToken) error
 
-	// GetByTokenHash возвращает refresh token по хешу
+	// GetByTokenHash возвращает refresh token по хешу (в т.ч. отозванный)
 	GetByTokenHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
 
-	// Revoke отзывает refresh token
+	// Revoke отзывает refresh token; ErrRefreshTokenNotFound, если он не найден или уже отозван
 	Revoke(ctx context.Context, tokenHash string) error
 
 	// RevokeAllUserTokens отзывает все токены пользователя
 	RevokeAllUserTokens(ctx context.Context, userID uuid.UUID) error
 
+	// ListByUser возвращает неотозванные и неистекшие токены пользователя (активные сессии), новые первыми
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.RefreshToken, error)
+
+	// RevokeByID отзывает токен пользователя по ID
+	// ErrRefreshTokenNotFound, если токен не найден, принадлежит другому пользователю или уже отозван
+	RevokeByID(ctx context.Context, id, userID uuid.UUID) error
+
 	// DeleteExpired удаляет истекшие токены
 	DeleteExpired(ctx context.Context) error
 }
+
+// TxRepositories - репозитории, выполняющие запросы в одной транзакции UnitOfWork
+// Кэширующие обертки сюда не входят: после изменения кэшируемых данных кэш сбрасывает вызывающий
+type TxRepositories struct {
+	Users         UserRepository
+	Vehicles      VehicleRepository
+	Passes        PassRepository
+	PassVehicles  PassVehicleRepository
+	AccessLogs    AccessLogRepository
+	AuditLogs     AuditLogRepository
+	Blacklist     BlacklistRepository
+	Whitelist     WhitelistRepository
+	RefreshTokens RefreshTokenRepository
+}
+
+// UnitOfWork выполняет несколько операций с репозиториями атомарно
+type UnitOfWork interface {
+	// Do выполняет fn в одной транзакции: если fn вернула nil - коммит, иначе откат и ошибка fn
+	// Репозитории действительны только внутри fn
+	Do(ctx context.Context, fn func(repos TxRepositories) error) error
+}
@@ -0,0 +1,73 @@
+package access
+
+import (
+	"strings"
+
+	"github.com/frontandrew/gate/internal/domain"
+)
+
+// ReasonCode - машиночитаемый код причины решения о доступе
+// Текст в Reason может меняться, код - стабилен и используется для метрик и фильтрации
+type ReasonCode string
+
+const (
+	ReasonRecognitionUnavailable ReasonCode = "RECOGNITION_UNAVAILABLE" // ML сервис недоступен
+	ReasonPlateNotRecognized     ReasonCode = "PLATE_NOT_RECOGNIZED"    // Номер не распознан
+	ReasonImageRejected          ReasonCode = "IMAGE_REJECTED"          // ML сервис отклонил изображение (размер, формат)
+	ReasonNeedsReview            ReasonCode = "NEEDS_REVIEW"            // Уверенность ниже MinConfidence: нужен разбор оператором
+	ReasonWhitelisted            ReasonCode = "WHITELISTED"             // Номер в белом списке
+	ReasonBlacklisted            ReasonCode = "BLACKLISTED"             // Номер в черном списке
+	ReasonWhitelistUnavailable   ReasonCode = "WHITELIST_UNAVAILABLE"   // Белый список недоступен (WhitelistFailClosed)
+	ReasonBlacklistUnavailable   ReasonCode = "BLACKLIST_UNAVAILABLE"   // Черный список недоступен (BlacklistFailClosed)
+	ReasonVehicleNotRegistered   ReasonCode = "VEHICLE_NOT_REGISTERED"  // Автомобиль не найден
+	ReasonVehicleInactive        ReasonCode = "VEHICLE_INACTIVE"        // Автомобиль деактивирован
+	ReasonOwnerNotFound          ReasonCode = "OWNER_NOT_FOUND"         // Владелец не найден
+	ReasonUserInactive           ReasonCode = "USER_INACTIVE"           // Владелец деактивирован
+	ReasonNoPass                 ReasonCode = "NO_PASS"                 // У владельца нет активных пропусков
+	ReasonPassNotForVehicle      ReasonCode = "PASS_NOT_FOR_VEHICLE"    // Пропуска владельца не включают автомобиль
+	ReasonPassExpired            ReasonCode = "PASS_EXPIRED"            // Все пропуска истекли или недействительны
+	ReasonPassOutsideHours       ReasonCode = "PASS_OUTSIDE_HOURS"      // Пропуск действует, но не в это время или день
+	ReasonPassUsageLimit         ReasonCode = "PASS_USAGE_LIMIT"        // Все разрешенные проезды по пропуску использованы
+	ReasonPassWrongZone          ReasonCode = "PASS_WRONG_ZONE"         // Пропуска владельца действуют только в других зонах
+	ReasonValidPass              ReasonCode = "VALID_PASS"              // Найден действующий пропуск
+	ReasonAntiPassback           ReasonCode = "ANTI_PASSBACK"           // Повторный проезд в том же направлении
+	ReasonAtCapacity             ReasonCode = "AT_CAPACITY"             // Зона ворот заполнена, въезд невозможен
+
+	// ReasonObservationMode возвращается воротам в режиме наблюдения;
+	// в лог записывается код вычисленного решения, поэтому в knownReasonCodes его нет
+	ReasonObservationMode ReasonCode = "OBSERVATION_MODE"
+)
+
+// knownReasonCodes содержит все коды, которые сервис записывает в лог
+var knownReasonCodes = map[ReasonCode]bool{
+	ReasonRecognitionUnavailable: true,
+	ReasonPlateNotRecognized:     true,
+	ReasonImageRejected:          true,
+	ReasonNeedsReview:            true,
+	ReasonWhitelisted:            true,
+	ReasonBlacklisted:            true,
+	ReasonWhitelistUnavailable:   true,
+	ReasonBlacklistUnavailable:   true,
+	ReasonVehicleNotRegistered:   true,
+	ReasonVehicleInactive:        true,
+	ReasonOwnerNotFound:          true,
+	ReasonUserInactive:           true,
+	ReasonNoPass:                 true,
+	ReasonPassNotForVehicle:      true,
+	ReasonPassExpired:            true,
+	ReasonPassOutsideHours:       true,
+	ReasonPassUsageLimit:         true,
+	ReasonPassWrongZone:          true,
+	ReasonValidPass:              true,
+	ReasonAntiPassback:           true,
+	ReasonAtCapacity:             true,
+}
+
+// ParseReasonCode нормализует код причины и проверяет, что он известен
+func ParseReasonCode(value string) (ReasonCode, error) {
+	code := ReasonCode(strings.ToUpper(strings.TrimSpace(value)))
+	if !knownReasonCodes[code] {
+		return "", domain.ErrInvalidReasonCode
+	}
+	return code, nil
+}
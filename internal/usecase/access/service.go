@@ -2,12 +2,19 @@ package access
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/frontandrew/gate/internal/domain"
 	"github.com/frontandrew/gate/internal/infrastructure/ml"
 	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/pkg/metrics"
 	"github.com/frontandrew/gate/internal/repository"
 	"github.com/google/uuid"
 )
@@ -16,7 +23,11 @@ import (
 type CheckAccessRequest struct {
 	ImageBase64 string `json:"image_base64" validate:"required"`
 	GateID      string `json:"gate_id" validate:"required"`
-	Direction   string `json:"direction" validate:"required,oneof=IN OUT"`
+	Direction   string `json:"direction"` // Проверяется сервисом (ParseDirection); пусто допустимо только для ворот из InferDirectionGates
+
+	// CapturedAt - время съемки кадра на устройстве; шлюзы при переподключении
+	// могут досылать буферизованные кадры. Пусто - кадр считается свежим
+	CapturedAt *time.Time `json:"captured_at,omitempty"`
 }
 
 // CheckAccessResponse - ответ на проверку доступа
@@ -28,7 +39,129 @@ type CheckAccessResponse struct {
 	User          *domain.User    `json:"user,omitempty"`
 	Pass          *domain.Pass    `json:"pass,omitempty"`
 	Reason        string          `json:"reason"`
-	Timestamp     time.Time       `json:"timestamp"`
+	ReasonCode    ReasonCode      `json:"reason_code"`
+	Observed      bool            `json:"observed,omitempty"`     // Ворота в режиме наблюдения, решение не применяется
+	Stale         bool            `json:"stale,omitempty"`        // Кадр старше MaxFrameAge: решение не записано в лог
+	Manual        bool            `json:"manual,omitempty"`       // Номер введен охранником, распознавание не выполнялось
+	NeedsReview   bool            `json:"needs_review,omitempty"` // Низкая уверенность распознавания: отказ до разбора оператором
+
+	InferredDirection domain.Direction `json:"inferred_direction,omitempty"` // Направление, выведенное из предыдущего проезда
+	Timestamp         time.Time        `json:"timestamp"`
+
+	RecognitionTimeMs int64 `json:"recognition_time_ms"` // Распознавание номера (ML сервис или кэш)
+	ValidationTimeMs  int64 `json:"validation_time_ms"`  // Решение по спискам, пропускам и истории проездов
+}
+
+// ManualAccessRequest - ручная проверка: распознавание не сработало, охранник подтвердил номер визуально
+type ManualAccessRequest struct {
+	LicensePlate string `json:"license_plate" validate:"required"`
+	GateID       string `json:"gate_id" validate:"required"`
+	Direction    string `json:"direction" validate:"required"`      // Регистр не важен, значение проверяет ParseDirection
+	Reason       string `json:"reason" validate:"required,max=500"` // Почему понадобилась ручная проверка
+}
+
+// SimulateAccessRequest - запрос на симуляцию проверки доступа без изображения
+type SimulateAccessRequest struct {
+	LicensePlate string   `json:"license_plate" validate:"required"`
+	GateID       string   `json:"gate_id"`
+	Direction    string   `json:"direction" validate:"required"` // Регистр не важен, значение проверяет ParseDirection
+	Confidence   *float64 `json:"confidence,omitempty"`          // Принудительная уверенность распознавания
+}
+
+// SimulateAccessResponse - решение и пошаговое объяснение симуляции
+type SimulateAccessResponse struct {
+	Decision *CheckAccessResponse `json:"decision"`
+	Trace    []string             `json:"trace"`
+}
+
+// OccupancyResponse - число автомобилей на территории
+type OccupancyResponse struct {
+	Total    int64            `json:"total"`
+	Capacity int              `json:"capacity,omitempty"` // Вместимость каждой зоны (0 - без ограничения)
+	Scopes   []ScopeOccupancy `json:"scopes"`
+}
+
+// ScopeOccupancy - заполненность одной зоны (или ворот без зоны)
+type ScopeOccupancy struct {
+	Scope string `json:"scope"`
+	Count int64  `json:"count"`
+	Full  bool   `json:"full"`
+}
+
+// Config содержит настройки проверки доступа
+type Config struct {
+	MinConfidence float64 // Минимальная уверенность распознавания номера
+
+	// ReviewThreshold - нижняя граница полосы "нужен разбор" (0 - отключено; должна быть меньше MinConfidence).
+	// Номер с уверенностью в [ReviewThreshold, MinConfidence) не считается нераспознанным:
+	// в доступе отказывается, а решение помечается NeedsReview для оператора
+	ReviewThreshold       float64
+	StrictDirection       bool          // Отклонять запросы с неизвестным направлением до распознавания
+	DeniedSummaryInterval time.Duration // Период сводки причин отказов в логе (0 - отключено)
+
+	Gates       []string // Известные ворота площадки (пусто - ворота не перечислены, любой gate_id допустим)
+	StrictGates bool     // Отклонять gate_id вне списка Gates (иначе - только предупреждение в логе)
+
+	ObserveOnlyGates []string // Ворота в режиме наблюдения: решение пишется в лог, шлагбаум остается закрытым
+
+	// GateZones относит ворота к зонам (gate_id -> зона): на воротах зоны действуют записи списков
+	// и пропуска этой зоны и записи без зоны. Ворота вне карты видят только записи без зоны
+	GateZones map[string]string
+
+	MaxFrameAge       time.Duration // Максимальный возраст кадра по captured_at (0 - не проверяется)
+	RejectStaleFrames bool          // Отклонять устаревшие кадры (иначе - решение помечается stale и не пишется в лог)
+
+	AntiPassback bool // Запрещать повторный въезд без выезда (и наоборот); белый список не ограничивается
+
+	// InferDirectionGates - ворота с одной петлей, не сообщающие направление:
+	// после въезда номера следующий проезд считается выездом и наоборот
+	InferDirectionGates []string
+
+	// MaxOccupancy - вместимость каждой зоны (ворот без зоны): при заполненной въезд отклоняется.
+	// 0 - без ограничения; белый список не ограничивается
+	MaxOccupancy int
+
+	// Поведение при ошибке проверки списков. По умолчанию проверка продолжается (fail-open):
+	// номер считается не найденным в списке. С флагом в доступе отказывается (fail-closed)
+	WhitelistFailClosed bool
+	BlacklistFailClosed bool // Сбой БД не должен пропустить заблокированный номер
+}
+
+// UnregisteredPlateCache кэширует отказ "Vehicle not registered" по номеру и воротам
+type UnregisteredPlateCache interface {
+	IsUnregistered(ctx context.Context, licensePlate, gateID string) bool
+	MarkUnregistered(ctx context.Context, licensePlate, gateID string)
+}
+
+// RecognitionCache кэширует успешные результаты распознавания по хешу изображения
+type RecognitionCache interface {
+	Get(ctx context.Context, imageHash string) (*ml.RecognitionResult, bool)
+	Set(ctx context.Context, imageHash string, result *ml.RecognitionResult)
+}
+
+// EventPublisher публикует решения о доступе (лента SSE для dashboard, webhook'и)
+// Ошибки публикации обрабатывает сама реализация: лента не должна влиять на проверку доступа
+type EventPublisher interface {
+	Publish(ctx context.Context, event *domain.AccessEvent)
+}
+
+// OccupancyCounter хранит число автомобилей на территории по зонам (воротам без зоны)
+// Въезд увеличивает счетчик, выезд уменьшает, но не ниже нуля
+type OccupancyCounter interface {
+	Increment(ctx context.Context, scope string) (int64, error)
+	Decrement(ctx context.Context, scope string) (int64, error)
+	Get(ctx context.Context, scope string) (int64, error)
+	All(ctx context.Context) (map[string]int64, error)
+}
+
+// EventPublishers рассылает событие всем получателям по порядку
+type EventPublishers []EventPublisher
+
+// Publish реализует EventPublisher
+func (p EventPublishers) Publish(ctx context.Context, event *domain.AccessEvent) {
+	for _, publisher := range p {
+		publisher.Publish(ctx, event)
+	}
 }
 
 // Service содержит бизнес-логику проверки доступа
@@ -40,8 +173,21 @@ type Service struct {
 	whitelistRepo repository.WhitelistRepository // ПРИОРИТЕТ 1
 	blacklistRepo repository.BlacklistRepository // ПРИОРИТЕТ 2
 	mlClient      ml.Client
+	recognitions  RecognitionCache       // nil - кэш распознавания отключен
+	unregistered  UnregisteredPlateCache // nil - кэш отказов отключен
+	events        EventPublisher         // nil - лента событий отключена
+	occupancy     OccupancyCounter       // nil - учет заполненности отключен
 	logger        logger.Logger
-	minConfidence float64
+	config        Config
+	knownGates    map[string]bool
+	observeGates  map[string]bool
+	inferGates    map[string]bool
+
+	deniedReasons *metrics.CounterVec // Количество отказов по коду причины
+	metrics       *metrics.Metrics    // nil - Prometheus метрики отключены
+
+	mlVersionMu sync.RWMutex
+	mlVersion   string // Последняя наблюдаемая версия ML модели
 }
 
 // NewService создает новый экземпляр AccessService
@@ -53,9 +199,27 @@ func NewService(
 	whitelistRepo repository.WhitelistRepository,
 	blacklistRepo repository.BlacklistRepository,
 	mlClient ml.Client,
+	recognitions RecognitionCache,
+	unregistered UnregisteredPlateCache,
+	events EventPublisher,
+	occupancy OccupancyCounter,
+	appMetrics *metrics.Metrics,
 	logger logger.Logger,
-	minConfidence float64,
+	config Config,
 ) *Service {
+	knownGates := make(map[string]bool, len(config.Gates))
+	for _, gate := range config.Gates {
+		knownGates[gate] = true
+	}
+	observeGates := make(map[string]bool, len(config.ObserveOnlyGates))
+	for _, gate := range config.ObserveOnlyGates {
+		observeGates[gate] = true
+	}
+	inferGates := make(map[string]bool, len(config.InferDirectionGates))
+	for _, gate := range config.InferDirectionGates {
+		inferGates[gate] = true
+	}
+
 	return &Service{
 		vehicleRepo:   vehicleRepo,
 		userRepo:      userRepo,
@@ -64,9 +228,66 @@ func NewService(
 		whitelistRepo: whitelistRepo,
 		blacklistRepo: blacklistRepo,
 		mlClient:      mlClient,
+		recognitions:  recognitions,
+		unregistered:  unregistered,
+		events:        events,
+		occupancy:     occupancy,
 		logger:        logger,
-		minConfidence: minConfidence,
+		config:        config,
+		knownGates:    knownGates,
+		observeGates:  observeGates,
+		inferGates:    inferGates,
+		deniedReasons: metrics.NewCounterVec("access_denied_total"),
+		metrics:       appMetrics,
+	}
+}
+
+// log возвращает logger запроса (с request_id) или logger сервиса вне HTTP запроса
+func (s *Service) log(ctx context.Context) logger.Logger {
+	return logger.FromContextOr(ctx, s.logger)
+}
+
+// checkGate проверяет gate_id по списку известных ворот
+// Если ворота не перечислены в конфигурации, допустим любой gate_id
+func (s *Service) checkGate(ctx context.Context, gateID string) error {
+	if len(s.knownGates) == 0 || s.knownGates[gateID] {
+		return nil
+	}
+
+	s.log(ctx).Warn("Access check for unknown gate", map[string]interface{}{
+		"gate_id": gateID,
+		"strict":  s.config.StrictGates,
+	})
+
+	if s.config.StrictGates {
+		return domain.ErrUnknownGate
+	}
+	return nil
+}
+
+// gateZone возвращает зону ворот (пусто - ворота не относятся ни к одной зоне)
+func (s *Service) gateZone(gateID string) string {
+	return s.config.GateZones[gateID]
+}
+
+// isStaleFrame проверяет, что кадр снят раньше допустимого окна
+func (s *Service) isStaleFrame(ctx context.Context, req *CheckAccessRequest) bool {
+	if s.config.MaxFrameAge <= 0 || req.CapturedAt == nil {
+		return false
+	}
+
+	age := time.Since(*req.CapturedAt)
+	if age <= s.config.MaxFrameAge {
+		return false
 	}
+
+	s.log(ctx).Warn("Stale frame in access check", map[string]interface{}{
+		"gate_id":     req.GateID,
+		"captured_at": req.CapturedAt,
+		"age":         age.String(),
+		"rejected":    s.config.RejectStaleFrames,
+	})
+	return true
 }
 
 // CheckAccess - КЛЮЧЕВОЙ МЕТОД системы
@@ -75,114 +296,652 @@ func NewService(
 // 2. Номер авто → [ЧЕРНЫЙ СПИСОК?] → ОТКАЗАТЬ (безусловно)
 // 3. Номер авто → Автомобиль → Владелец (Пользователь) → Активные пропуска → Решение о доступе
 func (s *Service) CheckAccess(ctx context.Context, req *CheckAccessRequest) (*CheckAccessResponse, error) {
-	s.logger.Info("Starting access check", map[string]interface{}{
+	s.log(ctx).Info("Starting access check", map[string]interface{}{
 		"gate_id":   req.GateID,
 		"direction": req.Direction,
 	})
 
+	// ШАГ 0: Проверяем направление до распознавания и обращений к БД,
+	// иначе некорректное значение обнаружится только при записи лога.
+	// Ворота без датчика направления его не присылают - оно выводится после распознавания
+	inferDirection := s.inferGates[req.GateID] && strings.TrimSpace(req.Direction) == ""
+	if !inferDirection {
+		direction, err := domain.ParseDirection(req.Direction)
+		if err != nil {
+			if s.config.StrictDirection {
+				s.log(ctx).Warn("Rejected access check with invalid direction", map[string]interface{}{
+					"gate_id":   req.GateID,
+					"direction": req.Direction,
+				})
+				return nil, domain.ErrInvalidDirection
+			}
+		} else {
+			req.Direction = string(direction)
+		}
+	}
+
+	// Опечатка в gate_id порождает "осиротевшую" статистику по несуществующим воротам
+	if err := s.checkGate(ctx, req.GateID); err != nil {
+		return nil, err
+	}
+
+	// Повторно присланный старый кадр не должен влиять на заполненность и anti-passback
+	stale := s.isStaleFrame(ctx, req)
+	if stale && s.config.RejectStaleFrames {
+		return nil, domain.ErrStaleFrame
+	}
+
 	response := &CheckAccessResponse{
 		Timestamp: time.Now(),
+		Stale:     stale,
 	}
 
 	// ШАГ 1: Распознаем номер автомобиля через ML сервис
-	recognitionResult, err := s.mlClient.RecognizePlate(ctx, req.ImageBase64, s.minConfidence)
+	recognitionStarted := time.Now()
+	recognitionResult, err := s.recognizePlate(ctx, req.ImageBase64)
+	response.RecognitionTimeMs = time.Since(recognitionStarted).Milliseconds()
+	if errors.Is(err, domain.ErrMLImageRejected) {
+		s.log(ctx).Warn("Image rejected by recognizer", map[string]interface{}{
+			"gate_id": req.GateID,
+			"error":   err.Error(),
+		})
+		response.AccessGranted = false
+		response.Reason = "Image rejected by recognizer"
+		response.ReasonCode = ReasonImageRejected
+		return s.completeCheck(ctx, response, req, nil), nil
+	}
 	if err != nil {
-		s.logger.Error("ML recognition failed", map[string]interface{}{
+		s.log(ctx).Error("ML recognition failed", map[string]interface{}{
 			"error": err.Error(),
 		})
 		response.AccessGranted = false
 		response.Reason = "Recognition service unavailable"
-		s.logAccess(ctx, response, req, nil, nil, nil)
-		return response, nil
+		response.ReasonCode = ReasonRecognitionUnavailable
+		return s.completeCheck(ctx, response, req, nil), nil
 	}
 
 	if !recognitionResult.Success {
-		s.logger.Info("License plate not recognized", map[string]interface{}{
+		s.log(ctx).Info("License plate not recognized", map[string]interface{}{
 			"error": recognitionResult.Error,
 		})
 		response.AccessGranted = false
 		response.Reason = fmt.Sprintf("License plate not recognized: %s", recognitionResult.Error)
-		s.logAccess(ctx, response, req, nil, nil, nil)
-		return response, nil
+		response.ReasonCode = ReasonPlateNotRecognized
+		return s.completeCheck(ctx, response, req, &accessDecision{modelVersion: recognitionResult.ModelVersion}), nil
 	}
 
-	response.LicensePlate = recognitionResult.LicensePlate
-	response.Confidence = recognitionResult.Confidence
+	// ML сервис может вернуть номер кириллицей: ключи кэшей и журнал используют нормализованную запись
+	plate := domain.NormalizeLicensePlate(recognitionResult.LicensePlate)
 
-	s.logger.Info("License plate recognized", map[string]interface{}{
-		"plate":      recognitionResult.LicensePlate,
+	// При включенном ReviewThreshold ML сервис возвращает и номера ниже MinConfidence
+	if s.reviewEnabled() && recognitionResult.Confidence < s.config.MinConfidence {
+		response.LicensePlate = plate
+		response.Confidence = recognitionResult.Confidence
+		s.denyLowConfidence(response)
+		if response.NeedsReview {
+			s.log(ctx).Warn("Low-confidence recognition needs review", map[string]interface{}{
+				"plate":            plate,
+				"confidence":       recognitionResult.Confidence,
+				"gate_id":          req.GateID,
+				"min_confidence":   s.config.MinConfidence,
+				"review_threshold": s.config.ReviewThreshold,
+			})
+		}
+		return s.completeCheck(ctx, response, req, &accessDecision{modelVersion: recognitionResult.ModelVersion}), nil
+	}
+
+	s.log(ctx).Info("License plate recognized", map[string]interface{}{
+		"plate":      plate,
 		"confidence": recognitionResult.Confidence,
 	})
 
+	var inferred domain.Direction
+	if inferDirection {
+		inferred = s.inferDirection(ctx, plate)
+		req.Direction = string(inferred)
+	}
+
+	// ШАГИ 2-8: решение по распознанному номеру
+	validationStarted := time.Now()
+	decided, decision, err := s.decide(ctx, plate, recognitionResult.Confidence, s.gateZone(req.GateID), req, nil)
+	if err != nil {
+		return nil, err
+	}
+	decision.modelVersion = recognitionResult.ModelVersion
+	decided.Timestamp = response.Timestamp
+	decided.Stale = stale
+	decided.InferredDirection = inferred
+
+	s.checkAntiPassback(ctx, decided, req.Direction)
+	s.checkCapacity(ctx, decided, req)
+
+	decided.RecognitionTimeMs = response.RecognitionTimeMs
+	decided.ValidationTimeMs = time.Since(validationStarted).Milliseconds()
+
+	return s.completeCheck(ctx, decided, req, decision), nil
+}
+
+// recognizePlate распознает номер через ML сервис с учетом кэша по SHA-256 изображения
+// Кэшируются только успешные распознавания: нераспознанный кадр может распознаться при повторе.
+// Версия модели отслеживается только по ответам ML сервиса: кэш хранит версию, распознавшую кадр
+func (s *Service) recognizePlate(ctx context.Context, imageBase64 string) (*ml.RecognitionResult, error) {
+	if s.recognitions == nil {
+		result, err := s.mlClient.RecognizePlate(ctx, imageBase64, s.recognitionThreshold())
+		if err == nil && result.Success {
+			s.ObserveMLVersion(result.ModelVersion)
+		}
+		return result, err
+	}
+
+	sum := sha256.Sum256([]byte(imageBase64))
+	imageHash := hex.EncodeToString(sum[:])
+
+	if cached, ok := s.recognitions.Get(ctx, imageHash); ok {
+		s.log(ctx).Debug("Recognition result served from cache", map[string]interface{}{
+			"plate": cached.LicensePlate,
+		})
+		return cached, nil
+	}
+
+	result, err := s.mlClient.RecognizePlate(ctx, imageBase64, s.recognitionThreshold())
+	if err == nil && result.Success {
+		s.ObserveMLVersion(result.ModelVersion)
+		s.recognitions.Set(ctx, imageHash, result)
+	}
+	return result, err
+}
+
+// reviewEnabled сообщает, что включена полоса "нужен разбор" между ReviewThreshold и MinConfidence
+func (s *Service) reviewEnabled() bool {
+	return s.config.ReviewThreshold > 0 && s.config.ReviewThreshold < s.config.MinConfidence
+}
+
+// recognitionThreshold возвращает порог, передаваемый ML сервису
+// Сервис отбрасывает номера ниже порога, поэтому при включенном разборе передается ReviewThreshold,
+// а MinConfidence применяется здесь
+func (s *Service) recognitionThreshold() float64 {
+	if s.reviewEnabled() {
+		return s.config.ReviewThreshold
+	}
+	return s.config.MinConfidence
+}
+
+// denyLowConfidence отказывает по номеру с уверенностью ниже MinConfidence (response.Confidence)
+// Номер в полосе разбора помечается NeedsReview, ниже нее - считается нераспознанным
+func (s *Service) denyLowConfidence(response *CheckAccessResponse) {
+	response.AccessGranted = false
+
+	if !s.reviewEnabled() || response.Confidence < s.config.ReviewThreshold {
+		response.Reason = "License plate not recognized: confidence below threshold"
+		response.ReasonCode = ReasonPlateNotRecognized
+		return
+	}
+
+	response.NeedsReview = true
+	response.Reason = "Low recognition confidence: needs operator review"
+	response.ReasonCode = ReasonNeedsReview
+}
+
+// inferDirection выводит направление для ворот без датчика: после въезда - выезд и наоборот
+// Первый проезд номера (или недоступная история) считается въездом
+func (s *Service) inferDirection(ctx context.Context, licensePlate string) domain.Direction {
+	latest, err := s.accessLogRepo.GetLatestByLicensePlate(ctx, licensePlate)
+	if err != nil {
+		if !errors.Is(err, domain.ErrAccessLogNotFound) {
+			s.log(ctx).Error("Failed to get latest access log for direction inference", map[string]interface{}{
+				"plate": licensePlate,
+				"error": err.Error(),
+			})
+		}
+		return domain.DirectionIn
+	}
+
+	if latest.Direction == domain.DirectionIn {
+		return domain.DirectionOut
+	}
+	return domain.DirectionIn
+}
+
+// checkAntiPassback отменяет разрешение, если последний разрешенный проезд
+// по номеру был в том же направлении (въезд без выезда или выезд без въезда)
+// Белый список (спецслужбы) и отказы не проверяются
+func (s *Service) checkAntiPassback(ctx context.Context, response *CheckAccessResponse, direction string) {
+	if !s.config.AntiPassback || !response.AccessGranted || response.ReasonCode == ReasonWhitelisted {
+		return
+	}
+
+	latest, err := s.accessLogRepo.GetLatestByLicensePlate(ctx, response.LicensePlate)
+	if err != nil {
+		if !errors.Is(err, domain.ErrAccessLogNotFound) {
+			// Недоступность истории не должна блокировать ворота
+			s.log(ctx).Error("Failed to get latest access log", map[string]interface{}{
+				"plate": response.LicensePlate,
+				"error": err.Error(),
+			})
+		}
+		return
+	}
+
+	if string(latest.Direction) != direction {
+		return
+	}
+
+	s.log(ctx).Info("Anti-passback violation", map[string]interface{}{
+		"plate":          response.LicensePlate,
+		"direction":      direction,
+		"last_access_at": latest.Timestamp,
+	})
+	response.AccessGranted = false
+	response.Pass = nil
+	response.Reason = "Anti-passback violation"
+	response.ReasonCode = ReasonAntiPassback
+}
+
+// occupancyScope возвращает счетчик заполненности ворот: зону, а для ворот без зоны - сами ворота
+// Въезд и выезд через разные ворота учитываются верно, только если ворота относятся к одной зоне
+func (s *Service) occupancyScope(gateID string) string {
+	if zone := s.gateZone(gateID); zone != "" {
+		return zone
+	}
+	return gateID
+}
+
+// checkCapacity отменяет разрешение на въезд, если зона ворот заполнена
+// Белый список (спецслужбы), выезды и отказы не проверяются
+func (s *Service) checkCapacity(ctx context.Context, response *CheckAccessResponse, req *CheckAccessRequest) {
+	if s.occupancy == nil || s.config.MaxOccupancy <= 0 || !response.AccessGranted ||
+		response.ReasonCode == ReasonWhitelisted || req.Direction != string(domain.DirectionIn) {
+		return
+	}
+
+	scope := s.occupancyScope(req.GateID)
+	count, err := s.occupancy.Get(ctx, scope)
+	if err != nil {
+		// Недоступность счетчика не должна блокировать ворота
+		s.log(ctx).Error("Failed to get occupancy", map[string]interface{}{
+			"scope": scope,
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if count < int64(s.config.MaxOccupancy) {
+		return
+	}
+
+	s.log(ctx).Info("Facility at capacity", map[string]interface{}{
+		"plate":     response.LicensePlate,
+		"scope":     scope,
+		"occupancy": count,
+		"capacity":  s.config.MaxOccupancy,
+	})
+	response.AccessGranted = false
+	response.Pass = nil
+	response.Reason = "Facility at capacity"
+	response.ReasonCode = ReasonAtCapacity
+}
+
+// updateOccupancy учитывает разрешенный проезд в счетчике зоны ворот
+// Ошибка счетчика только логируется: проезд уже разрешен и записан в лог
+func (s *Service) updateOccupancy(ctx context.Context, response *CheckAccessResponse, req *CheckAccessRequest) {
+	if s.occupancy == nil || !response.AccessGranted {
+		return
+	}
+
+	scope := s.occupancyScope(req.GateID)
+	if scope == "" {
+		return
+	}
+
+	var err error
+	switch domain.Direction(req.Direction) {
+	case domain.DirectionIn:
+		_, err = s.occupancy.Increment(ctx, scope)
+	case domain.DirectionOut:
+		_, err = s.occupancy.Decrement(ctx, scope)
+	default:
+		return
+	}
+
+	if err != nil {
+		s.log(ctx).Error("Failed to update occupancy", map[string]interface{}{
+			"scope":     scope,
+			"direction": req.Direction,
+			"error":     err.Error(),
+		})
+	}
+}
+
+// Occupancy возвращает текущее число автомобилей на территории по зонам
+func (s *Service) Occupancy(ctx context.Context) (*OccupancyResponse, error) {
+	if s.occupancy == nil {
+		return nil, domain.ErrOccupancyUnavailable
+	}
+
+	counts, err := s.occupancy.All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get occupancy: %w", err)
+	}
+
+	response := &OccupancyResponse{
+		Capacity: s.config.MaxOccupancy,
+		Scopes:   make([]ScopeOccupancy, 0, len(counts)),
+	}
+	for scope, count := range counts {
+		response.Total += count
+		response.Scopes = append(response.Scopes, ScopeOccupancy{
+			Scope: scope,
+			Count: count,
+			Full:  s.config.MaxOccupancy > 0 && count >= int64(s.config.MaxOccupancy),
+		})
+	}
+	sort.Slice(response.Scopes, func(i, j int) bool {
+		return response.Scopes[i].Scope < response.Scopes[j].Scope
+	})
+
+	return response, nil
+}
+
+// completeCheck записывает вычисленное решение в лог доступа
+// Для ворот в режиме наблюдения вместо решения возвращается нейтральный отказ,
+// чтобы интеграция шлагбаума не открывала ворота
+func (s *Service) completeCheck(
+	ctx context.Context,
+	response *CheckAccessResponse,
+	req *CheckAccessRequest,
+	decision *accessDecision,
+) *CheckAccessResponse {
+	if decision == nil {
+		decision = &accessDecision{}
+	}
+
+	observed := s.observeGates[req.GateID]
+	if !response.Stale {
+		s.logAccess(ctx, response, req, decision, observed)
+
+		// Учитывается итоговое решение: logAccess может заменить разрешение отказом по лимиту проездов
+		if !observed {
+			s.metrics.ObserveAccessDecision(response.AccessGranted, string(response.ReasonCode))
+		}
+
+		s.publishEvent(ctx, response, req, observed)
+
+		// Шлагбаум ворот в режиме наблюдения не открывается - автомобиль не проезжает
+		if !observed {
+			s.updateOccupancy(ctx, response, req)
+		}
+	}
+
+	if !observed {
+		return response
+	}
+
+	return &CheckAccessResponse{
+		AccessGranted: false,
+		LicensePlate:  response.LicensePlate,
+		Confidence:    response.Confidence,
+		Reason:        "Observation mode",
+		ReasonCode:    ReasonObservationMode,
+		Observed:      true,
+		Stale:         response.Stale,
+		Manual:        response.Manual,
+		NeedsReview:   response.NeedsReview,
+		Timestamp:     response.Timestamp,
+
+		InferredDirection: response.InferredDirection,
+
+		RecognitionTimeMs: response.RecognitionTimeMs,
+		ValidationTimeMs:  response.ValidationTimeMs,
+	}
+}
+
+// ManualAccess принимает решение по номеру, который охранник подтвердил визуально
+// Правила те же, что у CheckAccess, но без ML; запись в логе помечается как ручная с указанием охранника
+func (s *Service) ManualAccess(ctx context.Context, req *ManualAccessRequest, operatorID uuid.UUID) (*CheckAccessResponse, error) {
+	direction, err := domain.ParseDirection(req.Direction)
+	if err != nil {
+		return nil, err
+	}
+
+	plate := domain.NormalizeLicensePlate(req.LicensePlate)
+	if plate == "" {
+		return nil, domain.ErrInvalidLicensePlate
+	}
+
+	if err := s.checkGate(ctx, req.GateID); err != nil {
+		return nil, err
+	}
+
+	s.log(ctx).Info("Starting manual access check", map[string]interface{}{
+		"plate":       plate,
+		"gate_id":     req.GateID,
+		"direction":   direction,
+		"operator_id": operatorID,
+	})
+
+	check := &CheckAccessRequest{GateID: req.GateID, Direction: string(direction)}
+
+	// Ворота в decide не передаются: кэш отказов не используется, охранник проверяет актуальное состояние БД
+	response, decision, err := s.decide(ctx, plate, 0, s.gateZone(req.GateID), &CheckAccessRequest{Direction: check.Direction}, nil)
+	if err != nil {
+		return nil, err
+	}
+	response.Manual = true
+
+	s.checkAntiPassback(ctx, response, check.Direction)
+	s.checkCapacity(ctx, response, check)
+
+	decision.operatorID = &operatorID
+	decision.manualReason = strings.TrimSpace(req.Reason)
+
+	return s.completeCheck(ctx, response, check, decision), nil
+}
+
+// SimulateAccess прогоняет логику принятия решения для заданного номера без ML и без записи в лог
+// Используется для QA и интеграторов; счетчики отказов не изменяются
+func (s *Service) SimulateAccess(ctx context.Context, req *SimulateAccessRequest) (*SimulateAccessResponse, error) {
+	direction, err := domain.ParseDirection(req.Direction)
+	if err != nil {
+		return nil, err
+	}
+
+	plate := domain.NormalizeLicensePlate(req.LicensePlate)
+	if plate == "" {
+		return nil, domain.ErrInvalidLicensePlate
+	}
+
+	if req.GateID != "" {
+		if err := s.checkGate(ctx, req.GateID); err != nil {
+			return nil, err
+		}
+	}
+
+	trace := explainTrace{}
+	trace.add("simulation: plate %s, gate %q, direction %s", plate, req.GateID, direction)
+
+	// Принудительная уверенность ниже порога обрабатывается так же, как при распознавании:
+	// нераспознанный номер или отказ с пометкой NeedsReview
+	var confidence float64
+	if req.Confidence != nil {
+		confidence = *req.Confidence
+		if confidence < s.config.MinConfidence {
+			trace.add("recognition: confidence %.2f is below threshold %.2f", confidence, s.config.MinConfidence)
+			response := &CheckAccessResponse{
+				LicensePlate: plate,
+				Confidence:   confidence,
+				Timestamp:    time.Now(),
+			}
+			s.denyLowConfidence(response)
+			return &SimulateAccessResponse{Decision: response, Trace: trace}, nil
+		}
+	}
+
+	// Симуляция не передает ворота и не использует кэш отказов, чтобы отражать актуальное состояние БД;
+	// зона ворот при этом учитывается
+	response, _, err := s.decide(ctx, plate, confidence, s.gateZone(req.GateID), &CheckAccessRequest{Direction: string(direction)}, &trace)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SimulateAccessResponse{Decision: response, Trace: trace}, nil
+}
+
+// accessDecision - сущности, участвовавшие в принятии решения (для записи в лог)
+type accessDecision struct {
+	vehicle *domain.Vehicle
+	user    *domain.User
+	pass    *domain.Pass
+
+	// Ручная проверка: кто подтвердил номер и почему (nil - номер распознан ML)
+	operatorID   *uuid.UUID
+	manualReason string
+
+	// Версия модели, распознавшей номер (для кэшированного распознавания - из кэша);
+	// пусто - ручная проверка или распознавание не выполнено
+	modelVersion string
+}
+
+// explainTrace накапливает пошаговое объяснение решения; nil - объяснение не нужно
+type explainTrace []string
+
+func (t *explainTrace) add(format string, args ...interface{}) {
+	if t == nil {
+		return
+	}
+	*t = append(*t, fmt.Sprintf(format, args...))
+}
+
+// decide принимает решение о доступе по распознанному номеру, без ML и без записи в лог доступа
+// Порядок проверок: белый список → черный список → автомобиль → владелец → действующий пропуск.
+// Возвращает ответ с номером, уверенностью, решением и причиной, а также сущности для записи в лог.
+// zone - зона ворот: записи списков и пропуска других зон не учитываются (пусто - только записи без зоны).
+// req.GateID задает ключ кэша незарегистрированных номеров (пусто - кэш не используется)
+func (s *Service) decide(
+	ctx context.Context,
+	plate string,
+	confidence float64,
+	zone string,
+	req *CheckAccessRequest,
+	trace *explainTrace,
+) (*CheckAccessResponse, *accessDecision, error) {
+	response := &CheckAccessResponse{
+		LicensePlate: plate,
+		Confidence:   confidence,
+		Timestamp:    time.Now(),
+	}
+	decision := &accessDecision{}
+
+	if zone != "" {
+		trace.add("zone: gate belongs to zone %s", zone)
+	}
+
 	// ШАГ 2 (ПРИОРИТЕТ 1): Проверяем БЕЛЫЙ СПИСОК
 	// Если номер в белом списке - РАЗРЕШАЕМ доступ БЕЗ ДАЛЬНЕЙШИХ ПРОВЕРОК
-	isWhitelisted, whitelistReason, err := s.whitelistRepo.IsWhitelisted(ctx, recognitionResult.LicensePlate)
+	isWhitelisted, whitelistReason, err := s.whitelistRepo.IsWhitelisted(ctx, plate, zone)
 	if err != nil {
-		s.logger.Error("Failed to check whitelist", map[string]interface{}{
+		s.log(ctx).Error("Failed to check whitelist", map[string]interface{}{
 			"error": err.Error(),
 		})
+		trace.add("whitelist check failed: %v", err)
+		if s.config.WhitelistFailClosed {
+			response.AccessGranted = false
+			response.Reason = "Whitelist unavailable"
+			response.ReasonCode = ReasonWhitelistUnavailable
+			return response, decision, nil
+		}
 		// Продолжаем работу даже при ошибке whitelist (fail-open для критичных служб)
 	}
 	if isWhitelisted {
-		s.logger.Info("License plate is whitelisted", map[string]interface{}{
-			"plate":  recognitionResult.LicensePlate,
+		s.log(ctx).Info("License plate is whitelisted", map[string]interface{}{
+			"plate":  plate,
 			"reason": whitelistReason,
+			"zone":   zone,
 		})
+		trace.add("whitelist: plate %s is whitelisted (%s)", plate, whitelistReason)
 		response.AccessGranted = true
 		response.Reason = fmt.Sprintf("Whitelisted: %s", whitelistReason)
-		s.logAccess(ctx, response, req, nil, nil, nil)
-		return response, nil
+		response.ReasonCode = ReasonWhitelisted
+		return response, decision, nil
 	}
+	trace.add("whitelist: plate %s not found", plate)
 
 	// ШАГ 3 (ПРИОРИТЕТ 2): Проверяем ЧЕРНЫЙ СПИСОК
 	// Если номер в черном списке - ОТКАЗЫВАЕМ в доступе
-	isBlacklisted, blacklistReason, err := s.blacklistRepo.IsBlacklisted(ctx, recognitionResult.LicensePlate)
+	isBlacklisted, blacklistReason, err := s.blacklistRepo.IsBlacklisted(ctx, plate, zone)
 	if err != nil {
-		s.logger.Error("Failed to check blacklist", map[string]interface{}{
+		s.log(ctx).Error("Failed to check blacklist", map[string]interface{}{
 			"error": err.Error(),
 		})
+		trace.add("blacklist check failed: %v", err)
+		if s.config.BlacklistFailClosed {
+			response.AccessGranted = false
+			response.Reason = "Blacklist unavailable"
+			response.ReasonCode = ReasonBlacklistUnavailable
+			return response, decision, nil
+		}
 		// Продолжаем работу даже при ошибке blacklist
 	}
 	if isBlacklisted {
-		s.logger.Info("License plate is blacklisted", map[string]interface{}{
-			"plate":  recognitionResult.LicensePlate,
+		s.log(ctx).Info("License plate is blacklisted", map[string]interface{}{
+			"plate":  plate,
 			"reason": blacklistReason,
+			"zone":   zone,
 		})
+		trace.add("blacklist: plate %s is blacklisted (%s)", plate, blacklistReason)
 		response.AccessGranted = false
 		response.Reason = fmt.Sprintf("Blacklisted: %s", blacklistReason)
-		s.logAccess(ctx, response, req, nil, nil, nil)
-		return response, nil
+		response.ReasonCode = ReasonBlacklisted
+		return response, decision, nil
 	}
+	trace.add("blacklist: plate %s not found", plate)
 
 	// ШАГ 4 (ПРИОРИТЕТ 3): Стандартная проверка через пропуски
+	// Повторный промах по незарегистрированному номеру отвечается из кэша без обращения к БД;
+	// белый и черный списки проверены выше, поэтому их изменения применяются сразу
+	useCache := s.unregistered != nil && req.GateID != ""
+	if useCache && s.unregistered.IsUnregistered(ctx, plate, req.GateID) {
+		trace.add("vehicle: plate %s is not registered (cached)", plate)
+		response.AccessGranted = false
+		response.Reason = "Vehicle not registered"
+		response.ReasonCode = ReasonVehicleNotRegistered
+		return response, decision, nil
+	}
+
 	// Находим автомобиль в БД по номеру
-	vehicle, err := s.vehicleRepo.GetByLicensePlate(ctx, recognitionResult.LicensePlate)
+	vehicle, err := s.vehicleRepo.GetByLicensePlate(ctx, plate)
 	if err != nil {
 		if err == domain.ErrVehicleNotFound {
-			s.logger.Info("Vehicle not found in database", map[string]interface{}{
-				"plate": recognitionResult.LicensePlate,
+			s.log(ctx).Info("Vehicle not found in database", map[string]interface{}{
+				"plate": plate,
 			})
+			if useCache {
+				s.unregistered.MarkUnregistered(ctx, plate, req.GateID)
+			}
+			trace.add("vehicle: plate %s is not registered", plate)
 			response.AccessGranted = false
 			response.Reason = "Vehicle not registered"
-			s.logAccess(ctx, response, req, nil, nil, nil)
-			return response, nil
+			response.ReasonCode = ReasonVehicleNotRegistered
+			return response, decision, nil
 		}
-		s.logger.Error("Failed to get vehicle", map[string]interface{}{
+		s.log(ctx).Error("Failed to get vehicle", map[string]interface{}{
 			"error": err.Error(),
 		})
-		return nil, fmt.Errorf("failed to get vehicle: %w", err)
+		return nil, nil, fmt.Errorf("failed to get vehicle: %w", err)
 	}
+	decision.vehicle = vehicle
 
 	// Проверяем, что автомобиль активен
 	if !vehicle.IsActive {
-		s.logger.Info("Vehicle is inactive", map[string]interface{}{
+		s.log(ctx).Info("Vehicle is inactive", map[string]interface{}{
 			"vehicle_id": vehicle.ID,
 		})
+		trace.add("vehicle: %s is inactive", vehicle.ID)
 		response.AccessGranted = false
 		response.Reason = "Vehicle is inactive"
-		s.logAccess(ctx, response, req, vehicle, nil, nil)
-		return response, nil
+		response.ReasonCode = ReasonVehicleInactive
+		return response, decision, nil
 	}
+	trace.add("vehicle: %s is active", vehicle.ID)
 
 	response.Vehicle = vehicle
 
@@ -190,31 +949,35 @@ func (s *Service) CheckAccess(ctx context.Context, req *CheckAccessRequest) (*Ch
 	user, err := s.userRepo.GetByID(ctx, vehicle.OwnerID)
 	if err != nil {
 		if err == domain.ErrUserNotFound {
-			s.logger.Warn("Vehicle owner not found", map[string]interface{}{
+			s.log(ctx).Warn("Vehicle owner not found", map[string]interface{}{
 				"vehicle_id": vehicle.ID,
 				"owner_id":   vehicle.OwnerID,
 			})
+			trace.add("owner: %s not found", vehicle.OwnerID)
 			response.AccessGranted = false
 			response.Reason = "Vehicle owner not found"
-			s.logAccess(ctx, response, req, vehicle, nil, nil)
-			return response, nil
+			response.ReasonCode = ReasonOwnerNotFound
+			return response, decision, nil
 		}
-		s.logger.Error("Failed to get user", map[string]interface{}{
+		s.log(ctx).Error("Failed to get user", map[string]interface{}{
 			"error": err.Error(),
 		})
-		return nil, fmt.Errorf("failed to get user: %w", err)
+		return nil, nil, fmt.Errorf("failed to get user: %w", err)
 	}
+	decision.user = user
 
 	// Проверяем, что пользователь активен
 	if !user.IsActive {
-		s.logger.Info("User is inactive", map[string]interface{}{
+		s.log(ctx).Info("User is inactive", map[string]interface{}{
 			"user_id": user.ID,
 		})
+		trace.add("owner: %s is inactive", user.ID)
 		response.AccessGranted = false
 		response.Reason = "User account is inactive"
-		s.logAccess(ctx, response, req, vehicle, user, nil)
-		return response, nil
+		response.ReasonCode = ReasonUserInactive
+		return response, decision, nil
 	}
+	trace.add("owner: %s is active", user.ID)
 
 	response.User = user
 
@@ -222,60 +985,147 @@ func (s *Service) CheckAccess(ctx context.Context, req *CheckAccessRequest) (*Ch
 	// ВАЖНО: один пользователь может иметь несколько активных пропусков!
 	passes, err := s.passRepo.GetActivePassesByUserAndVehicle(ctx, user.ID, vehicle.ID)
 	if err != nil {
-		s.logger.Error("Failed to get user passes", map[string]interface{}{
+		s.log(ctx).Error("Failed to get user passes", map[string]interface{}{
 			"error": err.Error(),
 		})
-		return nil, fmt.Errorf("failed to get user passes: %w", err)
+		return nil, nil, fmt.Errorf("failed to get user passes: %w", err)
 	}
 
 	if len(passes) == 0 {
-		s.logger.Info("No active passes found for user and vehicle", map[string]interface{}{
-			"user_id":    user.ID,
-			"vehicle_id": vehicle.ID,
+		s.denyWithoutPass(ctx, response, user.ID, vehicle.ID, trace)
+		return response, decision, nil
+	}
+
+	// Пропуска другой зоны на этих воротах не действуют
+	zonePasses := make([]*domain.Pass, 0, len(passes))
+	for _, pass := range passes {
+		if domain.ZoneApplies(pass.Zone, zone) {
+			zonePasses = append(zonePasses, pass)
+			continue
+		}
+		trace.add("passes: %s (%s) is limited to zone %s", pass.ID, pass.PassType, *pass.Zone)
+	}
+	if len(zonePasses) == 0 {
+		s.log(ctx).Info("No pass is valid in gate zone", map[string]interface{}{
+			"user_id":      user.ID,
+			"zone":         zone,
+			"passes_count": len(passes),
 		})
+		decision.pass = passes[0]
 		response.AccessGranted = false
-		response.Reason = "No valid pass found for this vehicle"
-		s.logAccess(ctx, response, req, vehicle, user, nil)
-		return response, nil
+		response.Reason = "No pass is valid in this zone"
+		response.ReasonCode = ReasonPassWrongZone
+		return response, decision, nil
 	}
+	passes = zonePasses
 
 	// ШАГ 7: Проверяем временные ограничения для КАЖДОГО пропуска
 	// Доступ разрешается, если ХОТЯ БЫ ОДИН пропуск действителен
-	var validPass *domain.Pass
+	// Отказ по расписанию отличаем от истечения: пропуск, действующий в другие часы, не "истек"
+	now := time.Now()
+	var validPass, scheduledPass, exhaustedPass *domain.Pass
 	for _, pass := range passes {
-		if pass.IsValid() {
+		if pass.IsValidAt(now) {
 			validPass = pass
 			break
 		}
+		switch {
+		case pass.IsOutsideSchedule(now):
+			if scheduledPass == nil {
+				scheduledPass = pass
+			}
+			trace.add("passes: %s (%s) is outside allowed hours", pass.ID, pass.PassType)
+		case pass.IsUsageExhausted(now):
+			if exhaustedPass == nil {
+				exhaustedPass = pass
+			}
+			trace.add("passes: %s (%s) has no uses left", pass.ID, pass.PassType)
+		default:
+			trace.add("passes: %s (%s) is not valid now", pass.ID, pass.PassType)
+		}
+	}
+
+	if validPass == nil && scheduledPass != nil {
+		s.log(ctx).Info("Pass is outside allowed hours", map[string]interface{}{
+			"user_id": user.ID,
+			"pass_id": scheduledPass.ID,
+		})
+		decision.pass = scheduledPass
+		response.AccessGranted = false
+		response.Reason = "Outside allowed hours"
+		response.ReasonCode = ReasonPassOutsideHours
+		return response, decision, nil
+	}
+
+	if validPass == nil && exhaustedPass != nil {
+		decision.pass = exhaustedPass
+		denyUsageLimit(response)
+		return response, decision, nil
 	}
 
 	if validPass == nil {
-		s.logger.Info("All passes are expired or invalid", map[string]interface{}{
+		s.log(ctx).Info("All passes are expired or invalid", map[string]interface{}{
 			"user_id":      user.ID,
 			"passes_count": len(passes),
 		})
+		decision.pass = passes[0]
 		response.AccessGranted = false
 		response.Reason = "All passes expired or invalid"
-		s.logAccess(ctx, response, req, vehicle, user, passes[0])
-		return response, nil
+		response.ReasonCode = ReasonPassExpired
+		return response, decision, nil
 	}
 
 	// ШАГ 8: ДОСТУП РАЗРЕШЕН!
-	s.logger.Info("Access granted", map[string]interface{}{
+	s.log(ctx).Info("Access granted", map[string]interface{}{
 		"user_id":    user.ID,
 		"vehicle_id": vehicle.ID,
 		"pass_id":    validPass.ID,
 		"pass_type":  validPass.PassType,
 	})
+	trace.add("passes: %s (%s) is valid", validPass.ID, validPass.PassType)
 
+	decision.pass = validPass
 	response.AccessGranted = true
 	response.Pass = validPass
 	response.Reason = "Valid pass found"
+	response.ReasonCode = ReasonValidPass
 
-	// Записываем лог доступа
-	s.logAccess(ctx, response, req, vehicle, user, validPass)
+	return response, decision, nil
+}
 
-	return response, nil
+// denyWithoutPass отказывает в доступе, когда на автомобиль нет активных пропусков
+// Для диагностики различает "у владельца нет пропусков" и "пропуска есть, но не на этот автомобиль"
+func (s *Service) denyWithoutPass(ctx context.Context, response *CheckAccessResponse, userID, vehicleID uuid.UUID, trace *explainTrace) {
+	response.AccessGranted = false
+
+	userPasses, err := s.passRepo.GetActivePassesByUser(ctx, userID)
+	if err != nil {
+		// Уточнение причины не должно влиять на решение
+		s.log(ctx).Error("Failed to get user passes", map[string]interface{}{
+			"user_id": userID,
+			"error":   err.Error(),
+		})
+	}
+
+	if len(userPasses) > 0 {
+		s.log(ctx).Info("User has active passes, but none cover the vehicle", map[string]interface{}{
+			"user_id":      userID,
+			"vehicle_id":   vehicleID,
+			"passes_count": len(userPasses),
+		})
+		trace.add("passes: owner has %d active passes, none cover this vehicle", len(userPasses))
+		response.Reason = "No pass covers this vehicle"
+		response.ReasonCode = ReasonPassNotForVehicle
+		return
+	}
+
+	s.log(ctx).Info("No active passes found for user", map[string]interface{}{
+		"user_id":    userID,
+		"vehicle_id": vehicleID,
+	})
+	trace.add("passes: owner has no active passes")
+	response.Reason = "No valid pass found for this vehicle"
+	response.ReasonCode = ReasonNoPass
 }
 
 // logAccess записывает информацию о попытке доступа в БД
@@ -283,42 +1133,167 @@ func (s *Service) logAccess(
 	ctx context.Context,
 	response *CheckAccessResponse,
 	request *CheckAccessRequest,
-	vehicle *domain.Vehicle,
-	user *domain.User,
-	pass *domain.Pass,
+	decision *accessDecision,
+	observed bool,
 ) {
+	// Решения ворот в режиме наблюдения не попадают в метрики отказов
+	if !response.AccessGranted && !observed {
+		s.deniedReasons.Inc(string(response.ReasonCode))
+	}
+
 	accessLog := &domain.AccessLog{
 		LicensePlate:          response.LicensePlate,
 		RecognitionConfidence: response.Confidence,
 		AccessGranted:         response.AccessGranted,
 		AccessReason:          response.Reason,
+		ReasonCode:            string(response.ReasonCode),
 		GateID:                request.GateID,
 		Direction:             domain.Direction(request.Direction),
 		Timestamp:             response.Timestamp,
+		MLModelVersion:        decision.modelVersion,
+		Observed:              observed,
+		DirectionInferred:     response.InferredDirection != "",
+		Manual:                response.Manual,
+		OperatorID:            decision.operatorID,
+		ManualReason:          decision.manualReason,
 	}
 
-	if vehicle != nil {
-		accessLog.VehicleID = &vehicle.ID
+	if decision.vehicle != nil {
+		accessLog.VehicleID = &decision.vehicle.ID
 	}
 
-	if user != nil {
-		accessLog.UserID = &user.ID
+	if decision.user != nil {
+		accessLog.UserID = &decision.user.ID
 	}
 
+	pass := decision.pass
+
 	if err := accessLog.Validate(); err != nil {
-		s.logger.Error("Invalid access log data", map[string]interface{}{
+		s.log(ctx).Error("Invalid access log data", map[string]interface{}{
 			"error": err.Error(),
 		})
 		return
 	}
 
+	// Проезд по пропуску с лимитом учитывается в одной транзакции с записью в журнал
+	// Ворота в режиме наблюдения шлагбаум не открывают - проезд не расходуется
+	if response.AccessGranted && !observed && pass != nil && pass.MaxUses != nil {
+		err := s.passRepo.IncrementUses(ctx, pass.ID, accessLog)
+		switch {
+		case err == nil:
+			return
+		case errors.Is(err, domain.ErrPassUsageLimitReached):
+			// Последний проезд забрали параллельно (другие ворота) - отказываем
+			denyUsageLimit(response)
+			s.deniedReasons.Inc(string(response.ReasonCode))
+			accessLog.AccessGranted = false
+			accessLog.AccessReason = response.Reason
+			accessLog.ReasonCode = string(response.ReasonCode)
+		default:
+			// Транзакция учета откатилась вместе с записью журнала: проезд не учтен,
+			// но решение уже принято и должно остаться в журнале
+			s.log(ctx).Error("Failed to record pass use", map[string]interface{}{
+				"pass_id": pass.ID,
+				"error":   err.Error(),
+			})
+		}
+	}
+
 	if err := s.accessLogRepo.Create(ctx, accessLog); err != nil {
-		s.logger.Error("Failed to create access log", map[string]interface{}{
+		s.log(ctx).Error("Failed to create access log", map[string]interface{}{
 			"error": err.Error(),
 		})
 	}
 }
 
+// publishEvent отправляет записанное решение в ленту событий
+func (s *Service) publishEvent(ctx context.Context, response *CheckAccessResponse, req *CheckAccessRequest, observed bool) {
+	if s.events == nil {
+		return
+	}
+
+	s.events.Publish(ctx, &domain.AccessEvent{
+		LicensePlate:  response.LicensePlate,
+		AccessGranted: response.AccessGranted,
+		Reason:        response.Reason,
+		ReasonCode:    string(response.ReasonCode),
+		GateID:        req.GateID,
+		Direction:     domain.Direction(req.Direction),
+		Observed:      observed,
+		Manual:        response.Manual,
+		Timestamp:     response.Timestamp,
+	})
+}
+
+// denyUsageLimit оформляет отказ по исчерпанному лимиту проездов
+func denyUsageLimit(response *CheckAccessResponse) {
+	response.AccessGranted = false
+	response.Pass = nil
+	response.Reason = "Pass usage limit reached"
+	response.ReasonCode = ReasonPassUsageLimit
+}
+
+// ObserveMLVersion запоминает версию ML модели и предупреждает о ее смене
+// Пустая версия (старый ML сервис) игнорируется
+func (s *Service) ObserveMLVersion(version string) {
+	if version == "" {
+		return
+	}
+
+	s.mlVersionMu.Lock()
+	previous := s.mlVersion
+	s.mlVersion = version
+	s.mlVersionMu.Unlock()
+
+	if previous != "" && previous != version {
+		s.logger.Warn("ML model version changed", map[string]interface{}{
+			"previous_version": previous,
+			"current_version":  version,
+		})
+	}
+}
+
+// MLVersion возвращает последнюю наблюдаемую версию ML модели
+func (s *Service) MLVersion() string {
+	s.mlVersionMu.RLock()
+	defer s.mlVersionMu.RUnlock()
+	return s.mlVersion
+}
+
+// DeniedReasonCounts возвращает количество отказов по кодам причин с момента запуска
+func (s *Service) DeniedReasonCounts() map[string]int64 {
+	return s.deniedReasons.Snapshot()
+}
+
+// RunDeniedReasonSummary периодически пишет в лог распределение причин отказов
+// Блокируется до отмены ctx; при нулевом интервале сразу возвращается
+func (s *Service) RunDeniedReasonSummary(ctx context.Context) {
+	if s.config.DeniedSummaryInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.config.DeniedSummaryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			counts := s.deniedReasons.Snapshot()
+			if len(counts) == 0 {
+				continue
+			}
+
+			fields := make(map[string]interface{}, len(counts))
+			for reason, count := range counts {
+				fields[reason] = count
+			}
+			s.logger.Info("Denied access reasons summary", fields)
+		}
+	}
+}
+
 // GetAccessLogs возвращает историю проездов с фильтрацией и пагинацией
 func (s *Service) GetAccessLogs(ctx context.Context, userID *uuid.UUID, limit, offset int) ([]*domain.AccessLog, error) {
 	if userID != nil {
@@ -327,6 +1302,140 @@ func (s *Service) GetAccessLogs(ctx context.Context, userID *uuid.UUID, limit, o
 	return s.accessLogRepo.List(ctx, limit, offset)
 }
 
+// GetAccessLog возвращает запись журнала с пользователем и автомобилем (для разбора инцидентов)
+// Удаленные пользователь или автомобиль не считаются ошибкой: запись отдается без них
+func (s *Service) GetAccessLog(ctx context.Context, id uuid.UUID) (*domain.AccessLog, error) {
+	accessLog, err := s.accessLogRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if accessLog.UserID != nil {
+		user, err := s.userRepo.GetByID(ctx, *accessLog.UserID)
+		switch {
+		case err == nil:
+			accessLog.User = user
+		case err != domain.ErrUserNotFound:
+			return nil, fmt.Errorf("failed to get access log user: %w", err)
+		}
+	}
+
+	if accessLog.VehicleID != nil {
+		vehicle, err := s.vehicleRepo.GetByID(ctx, *accessLog.VehicleID)
+		switch {
+		case err == nil:
+			accessLog.Vehicle = vehicle
+		case err != domain.ErrVehicleNotFound:
+			return nil, fmt.Errorf("failed to get access log vehicle: %w", err)
+		}
+	}
+
+	return accessLog, nil
+}
+
+// LogInclude - связанные данные, которыми дополняются записи журнала в списках
+type LogInclude struct {
+	User    bool
+	Vehicle bool
+}
+
+// LoadRelations заполняет User и Vehicle у записей журнала: по одному запросу на каждый вид данных,
+// а не на каждую запись. Удаленные пользователи и автомобили остаются незаполненными
+func (s *Service) LoadRelations(ctx context.Context, logs []*domain.AccessLog, include LogInclude) error {
+	if include.User {
+		if ids := distinctIDs(logs, func(l *domain.AccessLog) *uuid.UUID { return l.UserID }); len(ids) > 0 {
+			users, err := s.userRepo.GetUsersByIDs(ctx, ids)
+			if err != nil {
+				return fmt.Errorf("failed to load access log users: %w", err)
+			}
+			byID := make(map[uuid.UUID]*domain.User, len(users))
+			for _, user := range users {
+				byID[user.ID] = user
+			}
+			for _, accessLog := range logs {
+				if accessLog.UserID != nil {
+					accessLog.User = byID[*accessLog.UserID]
+				}
+			}
+		}
+	}
+
+	if include.Vehicle {
+		if ids := distinctIDs(logs, func(l *domain.AccessLog) *uuid.UUID { return l.VehicleID }); len(ids) > 0 {
+			vehicles, err := s.vehicleRepo.GetVehiclesByIDs(ctx, ids)
+			if err != nil {
+				return fmt.Errorf("failed to load access log vehicles: %w", err)
+			}
+			byID := make(map[uuid.UUID]*domain.Vehicle, len(vehicles))
+			for _, vehicle := range vehicles {
+				byID[vehicle.ID] = vehicle
+			}
+			for _, accessLog := range logs {
+				if accessLog.VehicleID != nil {
+					accessLog.Vehicle = byID[*accessLog.VehicleID]
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// distinctIDs собирает уникальные непустые ID из записей журнала в порядке первого появления
+func distinctIDs(logs []*domain.AccessLog, id func(*domain.AccessLog) *uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]struct{}, len(logs))
+	ids := make([]uuid.UUID, 0, len(logs))
+	for _, accessLog := range logs {
+		value := id(accessLog)
+		if value == nil {
+			continue
+		}
+		if _, ok := seen[*value]; ok {
+			continue
+		}
+		seen[*value] = struct{}{}
+		ids = append(ids, *value)
+	}
+	return ids
+}
+
+// SearchAccessLogs возвращает историю проездов по фильтру (код причины, период)
+// Код причины должен быть предварительно проверен через ParseReasonCode
+func (s *Service) SearchAccessLogs(ctx context.Context, filter domain.AccessLogFilter, limit, offset int) ([]*domain.AccessLog, error) {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, domain.ErrInvalidDateRange
+	}
+
+	return s.accessLogRepo.Search(ctx, filter, limit, offset)
+}
+
+// CountAccessLogs возвращает количество логов по фильтру (для метаданных пагинации)
+func (s *Service) CountAccessLogs(ctx context.Context, filter domain.AccessLogFilter) (int, error) {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return 0, domain.ErrInvalidDateRange
+	}
+
+	return s.accessLogRepo.Count(ctx, filter)
+}
+
+// ExportAccessLogs передает логи по фильтру в fn по одному (потоковая выгрузка)
+func (s *Service) ExportAccessLogs(ctx context.Context, filter domain.AccessLogFilter, fn func(*domain.AccessLog) error) error {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return domain.ErrInvalidDateRange
+	}
+
+	return s.accessLogRepo.Stream(ctx, filter, fn)
+}
+
+// GetStats возвращает статистику проездов за период [from, to)
+func (s *Service) GetStats(ctx context.Context, from, to time.Time) (*domain.AccessStats, error) {
+	if !from.Before(to) {
+		return nil, domain.ErrInvalidDateRange
+	}
+
+	return s.accessLogRepo.GetStatsByPeriod(ctx, from, to)
+}
+
 // GetAccessLogsByVehicle возвращает историю проездов по автомобилю
 func (s *Service) GetAccessLogsByVehicle(ctx context.Context, vehicleID uuid.UUID, limit, offset int) ([]*domain.AccessLog, error) {
 	return s.accessLogRepo.GetByVehicleID(ctx, vehicleID, limit, offset)
@@ -0,0 +1,2434 @@
+package access
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/infrastructure/ml"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/pkg/metrics"
+	"github.com/frontandrew/gate/internal/repository/mocks"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mockMLClient мок для ml.Client
+type mockMLClient struct {
+	mock.Mock
+}
+
+func (m *mockMLClient) RecognizePlate(ctx context.Context, imageBase64 string, minConfidence float64) (*ml.RecognitionResult, error) {
+	args := m.Called(ctx, imageBase64, minConfidence)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ml.RecognitionResult), args.Error(1)
+}
+
+func (m *mockMLClient) Health(ctx context.Context) (*ml.HealthStatus, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ml.HealthStatus), args.Error(1)
+}
+
+// recordingLogger запоминает сообщения уровня Warn для проверок
+type recordingLogger struct {
+	logger.Logger
+	warnings []string
+}
+
+func (l *recordingLogger) Warn(msg string, fields ...map[string]interface{}) {
+	l.warnings = append(l.warnings, msg)
+}
+
+type serviceMocks struct {
+	vehicleRepo   *mocks.MockVehicleRepository
+	userRepo      *mocks.MockUserRepository
+	passRepo      *mocks.MockPassRepository
+	accessLogRepo *mocks.MockAccessLogRepository
+	whitelistRepo *mocks.MockWhitelistRepository
+	blacklistRepo *mocks.MockBlacklistRepository
+	mlClient      *mockMLClient
+}
+
+func newTestService(config Config) (*Service, *serviceMocks) {
+	m := &serviceMocks{
+		vehicleRepo:   new(mocks.MockVehicleRepository),
+		userRepo:      new(mocks.MockUserRepository),
+		passRepo:      new(mocks.MockPassRepository),
+		accessLogRepo: new(mocks.MockAccessLogRepository),
+		whitelistRepo: new(mocks.MockWhitelistRepository),
+		blacklistRepo: new(mocks.MockBlacklistRepository),
+		mlClient:      new(mockMLClient),
+	}
+
+	svc := NewService(
+		m.vehicleRepo,
+		m.userRepo,
+		m.passRepo,
+		m.accessLogRepo,
+		m.whitelistRepo,
+		m.blacklistRepo,
+		m.mlClient,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		logger.NewNoop(),
+		config,
+	)
+	return svc, m
+}
+
+func (m *serviceMocks) assertExpectations(t *testing.T) {
+	m.vehicleRepo.AssertExpectations(t)
+	m.userRepo.AssertExpectations(t)
+	m.passRepo.AssertExpectations(t)
+	m.accessLogRepo.AssertExpectations(t)
+	m.whitelistRepo.AssertExpectations(t)
+	m.blacklistRepo.AssertExpectations(t)
+	m.mlClient.AssertExpectations(t)
+}
+
+func TestService_CheckAccess_Direction(t *testing.T) {
+	notRecognized := &ml.RecognitionResult{Success: false, Error: "no plate"}
+
+	tests := []struct {
+		name        string
+		config      Config
+		direction   string
+		mockSetup   func(*serviceMocks)
+		expectedErr error
+		check       func(*testing.T, *CheckAccessRequest, *serviceMocks)
+	}{
+		{
+			name:        "строгий режим отклоняет неизвестное направление до распознавания",
+			config:      Config{MinConfidence: 0.7, StrictDirection: true},
+			direction:   "SIDEWAYS",
+			mockSetup:   func(m *serviceMocks) {},
+			expectedErr: domain.ErrInvalidDirection,
+			check: func(t *testing.T, req *CheckAccessRequest, m *serviceMocks) {
+				m.mlClient.AssertNotCalled(t, "RecognizePlate", mock.Anything, mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name:      "направление нормализуется перед проверкой",
+			config:    Config{MinConfidence: 0.7, StrictDirection: true},
+			direction: " in ",
+			mockSetup: func(m *serviceMocks) {
+				m.mlClient.On("RecognizePlate", mock.Anything, "image", 0.7).Return(notRecognized, nil)
+			},
+			check: func(t *testing.T, req *CheckAccessRequest, m *serviceMocks) {
+				assert.Equal(t, string(domain.DirectionIn), req.Direction)
+			},
+		},
+		{
+			name:      "нестрогий режим не прерывает проверку",
+			config:    Config{MinConfidence: 0.7, StrictDirection: false},
+			direction: "SIDEWAYS",
+			mockSetup: func(m *serviceMocks) {
+				m.mlClient.On("RecognizePlate", mock.Anything, "image", 0.7).Return(notRecognized, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestService(tt.config)
+			tt.mockSetup(m)
+
+			req := &CheckAccessRequest{ImageBase64: "image", GateID: "gate-1", Direction: tt.direction}
+			resp, err := svc.CheckAccess(context.Background(), req)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, resp)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, resp)
+				assert.False(t, resp.AccessGranted)
+			}
+
+			if tt.check != nil {
+				tt.check(t, req, m)
+			}
+
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestService_CheckAccess_Gate(t *testing.T) {
+	notRecognized := &ml.RecognitionResult{Success: false, Error: "no plate"}
+	gates := []string{"north-1", "south-1"}
+
+	tests := []struct {
+		name        string
+		config      Config
+		gateID      string
+		expectedErr error
+	}{
+		{
+			name:   "известные ворота принимаются в строгом режиме",
+			config: Config{MinConfidence: 0.7, Gates: gates, StrictGates: true},
+			gateID: "north-1",
+		},
+		{
+			name:        "неизвестные ворота отклоняются в строгом режиме",
+			config:      Config{MinConfidence: 0.7, Gates: gates, StrictGates: true},
+			gateID:      "nort-1",
+			expectedErr: domain.ErrUnknownGate,
+		},
+		{
+			name:   "нестрогий режим пропускает неизвестные ворота",
+			config: Config{MinConfidence: 0.7, Gates: gates, StrictGates: false},
+			gateID: "nort-1",
+		},
+		{
+			name:   "ворота не перечислены - любой gate_id допустим",
+			config: Config{MinConfidence: 0.7, StrictGates: true},
+			gateID: "anything",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestService(tt.config)
+			if tt.expectedErr == nil {
+				m.mlClient.On("RecognizePlate", mock.Anything, "image", 0.7).Return(notRecognized, nil)
+			}
+
+			req := &CheckAccessRequest{ImageBase64: "image", GateID: tt.gateID, Direction: "IN"}
+			resp, err := svc.CheckAccess(context.Background(), req)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, resp)
+				m.mlClient.AssertNotCalled(t, "RecognizePlate", mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, resp)
+			}
+
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestService_CheckAccess_RequestLogger(t *testing.T) {
+	svc, _ := newTestService(Config{MinConfidence: 0.7, Gates: []string{"north-1"}, StrictGates: true})
+	serviceLog := &recordingLogger{Logger: logger.NewNoop()}
+	svc.logger = serviceLog
+
+	// Logger запроса (с request_id) кладет в контекст HTTP middleware
+	requestLog := &recordingLogger{Logger: logger.NewNoop()}
+	ctx := logger.WithContext(context.Background(), requestLog)
+
+	_, err := svc.CheckAccess(ctx, &CheckAccessRequest{ImageBase64: "image", GateID: "nort-1", Direction: "IN"})
+	require.ErrorIs(t, err, domain.ErrUnknownGate)
+
+	assert.Equal(t, []string{"Access check for unknown gate"}, requestLog.warnings)
+	assert.Empty(t, serviceLog.warnings, "при logger'е в контексте logger сервиса не используется")
+}
+
+func TestService_CheckAccess_DeniedReasonCounters(t *testing.T) {
+	svc, m := newTestService(Config{MinConfidence: 0.7, StrictDirection: true})
+
+	m.mlClient.On("RecognizePlate", mock.Anything, "image", 0.7).
+		Return(&ml.RecognitionResult{Success: true, LicensePlate: "A123BC777", Confidence: 95}, nil)
+	m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+	m.blacklistRepo.On("IsBlacklisted", mock.Anything, "A123BC777", "").Return(true, "stolen", nil)
+	m.accessLogRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.AccessLog")).Return(nil)
+
+	req := &CheckAccessRequest{ImageBase64: "image", GateID: "gate-1", Direction: "IN"}
+	for i := 0; i < 2; i++ {
+		resp, err := svc.CheckAccess(context.Background(), req)
+		require.NoError(t, err)
+		assert.False(t, resp.AccessGranted)
+		assert.Equal(t, ReasonBlacklisted, resp.ReasonCode)
+	}
+
+	counts := svc.DeniedReasonCounts()
+	assert.Equal(t, int64(2), counts[string(ReasonBlacklisted)])
+	assert.Zero(t, counts[string(ReasonNoPass)])
+
+	m.assertExpectations(t)
+}
+
+func TestService_SimulateAccess(t *testing.T) {
+	ownerID := uuid.New()
+	vehicle := &domain.Vehicle{ID: uuid.New(), OwnerID: ownerID, LicensePlate: "A123BC777", IsActive: true}
+	owner := &domain.User{ID: ownerID, Role: domain.RoleUser, IsActive: true}
+	validPass := &domain.Pass{
+		ID:        uuid.New(),
+		UserID:    ownerID,
+		PassType:  domain.PassTypePermanent,
+		ValidFrom: time.Now().Add(-time.Hour),
+		IsActive:  true,
+	}
+
+	tests := []struct {
+		name           string
+		req            *SimulateAccessRequest
+		mockSetup      func(*serviceMocks)
+		expectedGrant  bool
+		expectedReason ReasonCode
+	}{
+		{
+			name: "номер в белом списке",
+			req:  &SimulateAccessRequest{LicensePlate: "a123bc777", GateID: "gate-1", Direction: "IN"},
+			mockSetup: func(m *serviceMocks) {
+				m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(true, "ambulance", nil)
+			},
+			expectedGrant:  true,
+			expectedReason: ReasonWhitelisted,
+		},
+		{
+			name: "номер в черном списке",
+			req:  &SimulateAccessRequest{LicensePlate: "A123BC777", GateID: "gate-1", Direction: "IN"},
+			mockSetup: func(m *serviceMocks) {
+				m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+				m.blacklistRepo.On("IsBlacklisted", mock.Anything, "A123BC777", "").Return(true, "stolen", nil)
+			},
+			expectedGrant:  false,
+			expectedReason: ReasonBlacklisted,
+		},
+		{
+			name: "действующий пропуск",
+			req:  &SimulateAccessRequest{LicensePlate: "A123BC777", GateID: "gate-1", Direction: "OUT"},
+			mockSetup: func(m *serviceMocks) {
+				m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+				m.blacklistRepo.On("IsBlacklisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+				m.vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(vehicle, nil)
+				m.userRepo.On("GetByID", mock.Anything, ownerID).Return(owner, nil)
+				m.passRepo.On("GetActivePassesByUserAndVehicle", mock.Anything, ownerID, vehicle.ID).
+					Return([]*domain.Pass{validPass}, nil)
+			},
+			expectedGrant:  true,
+			expectedReason: ReasonValidPass,
+		},
+		{
+			name: "нет пропусков",
+			req:  &SimulateAccessRequest{LicensePlate: "A123BC777", GateID: "gate-1", Direction: "IN"},
+			mockSetup: func(m *serviceMocks) {
+				m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+				m.blacklistRepo.On("IsBlacklisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+				m.vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(vehicle, nil)
+				m.userRepo.On("GetByID", mock.Anything, ownerID).Return(owner, nil)
+				m.passRepo.On("GetActivePassesByUserAndVehicle", mock.Anything, ownerID, vehicle.ID).
+					Return([]*domain.Pass{}, nil)
+				m.passRepo.On("GetActivePassesByUser", mock.Anything, ownerID).Return([]*domain.Pass{}, nil)
+			},
+			expectedGrant:  false,
+			expectedReason: ReasonNoPass,
+		},
+		{
+			name: "принудительная уверенность ниже порога",
+			req: func() *SimulateAccessRequest {
+				confidence := 0.5
+				return &SimulateAccessRequest{LicensePlate: "A123BC777", Direction: "IN", Confidence: &confidence}
+			}(),
+			mockSetup:      func(m *serviceMocks) {},
+			expectedGrant:  false,
+			expectedReason: ReasonPlateNotRecognized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestService(Config{MinConfidence: 0.7, StrictDirection: true})
+			tt.mockSetup(m)
+
+			result, err := svc.SimulateAccess(context.Background(), tt.req)
+			require.NoError(t, err)
+			require.NotNil(t, result.Decision)
+
+			assert.Equal(t, tt.expectedGrant, result.Decision.AccessGranted)
+			assert.Equal(t, tt.expectedReason, result.Decision.ReasonCode)
+			assert.NotEmpty(t, result.Trace)
+
+			// Симуляция не распознает изображение, не пишет в лог и не влияет на счетчики
+			m.mlClient.AssertNotCalled(t, "RecognizePlate", mock.Anything, mock.Anything, mock.Anything)
+			m.accessLogRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			assert.Empty(t, svc.DeniedReasonCounts())
+
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestService_Decide(t *testing.T) {
+	ownerID := uuid.New()
+	vehicle := &domain.Vehicle{ID: uuid.New(), OwnerID: ownerID, LicensePlate: "A123BC777", IsActive: true}
+	inactiveVehicle := &domain.Vehicle{ID: vehicle.ID, OwnerID: ownerID, LicensePlate: "A123BC777", IsActive: false}
+	owner := &domain.User{ID: ownerID, Role: domain.RoleUser, IsActive: true}
+	inactiveOwner := &domain.User{ID: ownerID, Role: domain.RoleUser, IsActive: false}
+
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	start := now.Add(2 * time.Hour).Format("15:04")
+	end := now.Add(3 * time.Hour).Format("15:04")
+	oneUse := 1
+
+	validPass := &domain.Pass{ID: uuid.New(), UserID: ownerID, PassType: domain.PassTypePermanent, ValidFrom: past, IsActive: true}
+	expiredPass := &domain.Pass{
+		ID: uuid.New(), UserID: ownerID, PassType: domain.PassTypeTemporary,
+		ValidFrom: now.Add(-48 * time.Hour), ValidUntil: &past, IsActive: true,
+	}
+	outsideHoursPass := &domain.Pass{
+		ID: uuid.New(), UserID: ownerID, PassType: domain.PassTypePermanent, ValidFrom: past, IsActive: true,
+		AllowedTimeStart: &start, AllowedTimeEnd: &end,
+	}
+	exhaustedPass := &domain.Pass{
+		ID: uuid.New(), UserID: ownerID, PassType: domain.PassTypeTemporary, ValidFrom: past, IsActive: true,
+		MaxUses: &oneUse, UsesCount: 1,
+	}
+
+	// lists настраивает ответы белого и черного списков (номер в них не найден)
+	lists := func(m *serviceMocks) {
+		m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+		m.blacklistRepo.On("IsBlacklisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+	}
+	// owned настраивает найденный активный автомобиль с активным владельцем и его пропуска
+	owned := func(passes ...*domain.Pass) func(m *serviceMocks) {
+		return func(m *serviceMocks) {
+			lists(m)
+			m.vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(vehicle, nil)
+			m.userRepo.On("GetByID", mock.Anything, ownerID).Return(owner, nil)
+			m.passRepo.On("GetActivePassesByUserAndVehicle", mock.Anything, ownerID, vehicle.ID).Return(passes, nil)
+		}
+	}
+
+	tests := []struct {
+		name           string
+		unregistered   fakeUnregisteredCache
+		mockSetup      func(*serviceMocks)
+		expectedGrant  bool
+		expectedReason ReasonCode
+		expectedPass   *domain.Pass // Пропуск, записываемый в лог
+		expectedErr    bool
+	}{
+		{
+			name: "белый список разрешает без дальнейших проверок",
+			mockSetup: func(m *serviceMocks) {
+				m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(true, "ambulance", nil)
+			},
+			expectedGrant:  true,
+			expectedReason: ReasonWhitelisted,
+		},
+		{
+			name: "черный список отказывает",
+			mockSetup: func(m *serviceMocks) {
+				m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+				m.blacklistRepo.On("IsBlacklisted", mock.Anything, "A123BC777", "").Return(true, "stolen", nil)
+			},
+			expectedReason: ReasonBlacklisted,
+		},
+		{
+			name: "ошибка белого списка не прерывает проверку",
+			mockSetup: func(m *serviceMocks) {
+				m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(false, "", errors.New("db down"))
+				m.blacklistRepo.On("IsBlacklisted", mock.Anything, "A123BC777", "").Return(true, "stolen", nil)
+			},
+			expectedReason: ReasonBlacklisted,
+		},
+		{
+			name:           "незарегистрированный номер из кэша",
+			unregistered:   fakeUnregisteredCache{"A123BC777|gate-1": true},
+			mockSetup:      lists,
+			expectedReason: ReasonVehicleNotRegistered,
+		},
+		{
+			name: "автомобиль не зарегистрирован",
+			mockSetup: func(m *serviceMocks) {
+				lists(m)
+				m.vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(nil, domain.ErrVehicleNotFound)
+			},
+			expectedReason: ReasonVehicleNotRegistered,
+		},
+		{
+			name: "ошибка поиска автомобиля",
+			mockSetup: func(m *serviceMocks) {
+				lists(m)
+				m.vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(nil, errors.New("db down"))
+			},
+			expectedErr: true,
+		},
+		{
+			name: "автомобиль деактивирован",
+			mockSetup: func(m *serviceMocks) {
+				lists(m)
+				m.vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(inactiveVehicle, nil)
+			},
+			expectedReason: ReasonVehicleInactive,
+		},
+		{
+			name: "владелец не найден",
+			mockSetup: func(m *serviceMocks) {
+				lists(m)
+				m.vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(vehicle, nil)
+				m.userRepo.On("GetByID", mock.Anything, ownerID).Return(nil, domain.ErrUserNotFound)
+			},
+			expectedReason: ReasonOwnerNotFound,
+		},
+		{
+			name: "владелец деактивирован",
+			mockSetup: func(m *serviceMocks) {
+				lists(m)
+				m.vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(vehicle, nil)
+				m.userRepo.On("GetByID", mock.Anything, ownerID).Return(inactiveOwner, nil)
+			},
+			expectedReason: ReasonUserInactive,
+		},
+		{
+			name: "у владельца нет пропусков",
+			mockSetup: func(m *serviceMocks) {
+				owned()(m)
+				m.passRepo.On("GetActivePassesByUser", mock.Anything, ownerID).Return([]*domain.Pass{}, nil)
+			},
+			expectedReason: ReasonNoPass,
+		},
+		{
+			name: "пропуска владельца не на этот автомобиль",
+			mockSetup: func(m *serviceMocks) {
+				owned()(m)
+				m.passRepo.On("GetActivePassesByUser", mock.Anything, ownerID).Return([]*domain.Pass{validPass}, nil)
+			},
+			expectedReason: ReasonPassNotForVehicle,
+		},
+		{
+			name:           "пропуск вне разрешенных часов",
+			mockSetup:      owned(outsideHoursPass),
+			expectedReason: ReasonPassOutsideHours,
+			expectedPass:   outsideHoursPass,
+		},
+		{
+			name:           "проезды по пропуску исчерпаны",
+			mockSetup:      owned(exhaustedPass),
+			expectedReason: ReasonPassUsageLimit,
+			expectedPass:   exhaustedPass,
+		},
+		{
+			name:           "пропуск истек",
+			mockSetup:      owned(expiredPass),
+			expectedReason: ReasonPassExpired,
+			expectedPass:   expiredPass,
+		},
+		{
+			name:           "действующий пропуск среди недействительных",
+			mockSetup:      owned(expiredPass, validPass),
+			expectedGrant:  true,
+			expectedReason: ReasonValidPass,
+			expectedPass:   validPass,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestService(Config{MinConfidence: 0.7})
+			if tt.unregistered != nil {
+				svc.unregistered = tt.unregistered
+			}
+			tt.mockSetup(m)
+
+			response, decision, err := svc.decide(context.Background(), "A123BC777", 95, "",
+				&CheckAccessRequest{GateID: "gate-1", Direction: "IN"}, nil)
+
+			if tt.expectedErr {
+				require.Error(t, err)
+				m.assertExpectations(t)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, "A123BC777", response.LicensePlate)
+			assert.Equal(t, float64(95), response.Confidence)
+			assert.Equal(t, tt.expectedGrant, response.AccessGranted)
+			assert.Equal(t, tt.expectedReason, response.ReasonCode)
+			assert.NotEmpty(t, response.Reason)
+			assert.Equal(t, tt.expectedPass, decision.pass)
+			if tt.expectedGrant && tt.expectedReason == ReasonValidPass {
+				assert.Equal(t, validPass, response.Pass)
+				assert.Equal(t, vehicle, response.Vehicle)
+				assert.Equal(t, owner, response.User)
+			}
+
+			// decide только принимает решение: распознавание и запись в лог - забота вызывающего
+			m.mlClient.AssertNotCalled(t, "RecognizePlate", mock.Anything, mock.Anything, mock.Anything)
+			m.accessLogRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestService_Decide_ListErrors(t *testing.T) {
+	ownerID := uuid.New()
+	vehicle := &domain.Vehicle{ID: uuid.New(), OwnerID: ownerID, LicensePlate: "A123BC777", IsActive: true}
+	owner := &domain.User{ID: ownerID, Role: domain.RoleUser, IsActive: true}
+	validPass := &domain.Pass{ID: uuid.New(), UserID: ownerID, PassType: domain.PassTypePermanent, ValidFrom: time.Now().Add(-time.Hour), IsActive: true}
+	dbErr := errors.New("db down")
+
+	// registered настраивает зарегистрированный автомобиль с действующим пропуском
+	registered := func(m *serviceMocks) {
+		m.vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(vehicle, nil)
+		m.userRepo.On("GetByID", mock.Anything, ownerID).Return(owner, nil)
+		m.passRepo.On("GetActivePassesByUserAndVehicle", mock.Anything, ownerID, vehicle.ID).Return([]*domain.Pass{validPass}, nil)
+	}
+
+	tests := []struct {
+		name           string
+		config         Config
+		mockSetup      func(*serviceMocks)
+		expectedGrant  bool
+		expectedReason ReasonCode
+	}{
+		{
+			name: "ошибка черного списка, fail-open: проверка продолжается",
+			mockSetup: func(m *serviceMocks) {
+				m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+				m.blacklistRepo.On("IsBlacklisted", mock.Anything, "A123BC777", "").Return(false, "", dbErr)
+				registered(m)
+			},
+			expectedGrant:  true,
+			expectedReason: ReasonValidPass,
+		},
+		{
+			name:   "ошибка черного списка, fail-closed: отказ",
+			config: Config{BlacklistFailClosed: true},
+			mockSetup: func(m *serviceMocks) {
+				m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+				m.blacklistRepo.On("IsBlacklisted", mock.Anything, "A123BC777", "").Return(false, "", dbErr)
+			},
+			expectedReason: ReasonBlacklistUnavailable,
+		},
+		{
+			name:   "ошибка белого списка, fail-open: проверка продолжается",
+			config: Config{BlacklistFailClosed: true},
+			mockSetup: func(m *serviceMocks) {
+				m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(false, "", dbErr)
+				m.blacklistRepo.On("IsBlacklisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+				registered(m)
+			},
+			expectedGrant:  true,
+			expectedReason: ReasonValidPass,
+		},
+		{
+			name:   "ошибка белого списка, fail-closed: отказ без дальнейших проверок",
+			config: Config{WhitelistFailClosed: true},
+			mockSetup: func(m *serviceMocks) {
+				m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(false, "", dbErr)
+			},
+			expectedReason: ReasonWhitelistUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.MinConfidence = 0.7
+			svc, m := newTestService(tt.config)
+			tt.mockSetup(m)
+
+			response, _, err := svc.decide(context.Background(), "A123BC777", 95, "",
+				&CheckAccessRequest{GateID: "gate-1", Direction: "IN"}, nil)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedGrant, response.AccessGranted)
+			assert.Equal(t, tt.expectedReason, response.ReasonCode)
+			if tt.expectedReason == ReasonBlacklistUnavailable {
+				assert.Equal(t, "Blacklist unavailable", response.Reason)
+			}
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestService_ManualAccess(t *testing.T) {
+	ownerID := uuid.New()
+	guardID := uuid.New()
+	vehicle := &domain.Vehicle{ID: uuid.New(), OwnerID: ownerID, LicensePlate: "A123BC777", IsActive: true}
+	owner := &domain.User{ID: ownerID, Role: domain.RoleUser, IsActive: true}
+	validPass := &domain.Pass{
+		ID:        uuid.New(),
+		UserID:    ownerID,
+		PassType:  domain.PassTypePermanent,
+		ValidFrom: time.Now().Add(-time.Hour),
+		IsActive:  true,
+	}
+
+	tests := []struct {
+		name           string
+		mockSetup      func(*serviceMocks)
+		expectedGrant  bool
+		expectedReason ReasonCode
+	}{
+		{
+			name: "действующий пропуск",
+			mockSetup: func(m *serviceMocks) {
+				m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+				m.blacklistRepo.On("IsBlacklisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+				m.vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(vehicle, nil)
+				m.userRepo.On("GetByID", mock.Anything, ownerID).Return(owner, nil)
+				m.passRepo.On("GetActivePassesByUserAndVehicle", mock.Anything, ownerID, vehicle.ID).
+					Return([]*domain.Pass{validPass}, nil)
+			},
+			expectedGrant:  true,
+			expectedReason: ReasonValidPass,
+		},
+		{
+			name: "номер в черном списке",
+			mockSetup: func(m *serviceMocks) {
+				m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+				m.blacklistRepo.On("IsBlacklisted", mock.Anything, "A123BC777", "").Return(true, "stolen", nil)
+			},
+			expectedGrant:  false,
+			expectedReason: ReasonBlacklisted,
+		},
+		{
+			name: "незарегистрированный номер",
+			mockSetup: func(m *serviceMocks) {
+				m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+				m.blacklistRepo.On("IsBlacklisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+				m.vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(nil, domain.ErrVehicleNotFound)
+			},
+			expectedGrant:  false,
+			expectedReason: ReasonVehicleNotRegistered,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestService(Config{MinConfidence: 0.7, StrictDirection: true})
+			tt.mockSetup(m)
+
+			var logged *domain.AccessLog
+			m.accessLogRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.AccessLog")).
+				Run(func(args mock.Arguments) { logged = args.Get(1).(*domain.AccessLog) }).
+				Return(nil)
+
+			resp, err := svc.ManualAccess(context.Background(), &ManualAccessRequest{
+				LicensePlate: "a123bc777",
+				GateID:       "gate-1",
+				Direction:    "in",
+				Reason:       " Камера залеплена снегом ",
+			}, guardID)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedGrant, resp.AccessGranted)
+			assert.Equal(t, tt.expectedReason, resp.ReasonCode)
+			assert.True(t, resp.Manual)
+
+			// Решение принято без распознавания и записано как ручное от имени охранника
+			m.mlClient.AssertNotCalled(t, "RecognizePlate", mock.Anything, mock.Anything, mock.Anything)
+			require.NotNil(t, logged)
+			assert.True(t, logged.Manual)
+			require.NotNil(t, logged.OperatorID)
+			assert.Equal(t, guardID, *logged.OperatorID)
+			assert.Equal(t, "Камера залеплена снегом", logged.ManualReason)
+			assert.Equal(t, "A123BC777", logged.LicensePlate)
+			assert.Equal(t, domain.DirectionIn, logged.Direction)
+			assert.Equal(t, tt.expectedGrant, logged.AccessGranted)
+
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestService_ManualAccess_InvalidRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         *ManualAccessRequest
+		expectedErr error
+	}{
+		{
+			name:        "неизвестное направление",
+			req:         &ManualAccessRequest{LicensePlate: "A123BC777", GateID: "gate-1", Direction: "SIDEWAYS", Reason: "r"},
+			expectedErr: domain.ErrInvalidDirection,
+		},
+		{
+			name:        "пустой номер",
+			req:         &ManualAccessRequest{LicensePlate: "   ", GateID: "gate-1", Direction: "IN", Reason: "r"},
+			expectedErr: domain.ErrInvalidLicensePlate,
+		},
+		{
+			name:        "неизвестные ворота",
+			req:         &ManualAccessRequest{LicensePlate: "A123BC777", GateID: "nort-1", Direction: "IN", Reason: "r"},
+			expectedErr: domain.ErrUnknownGate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestService(Config{Gates: []string{"gate-1"}, StrictGates: true})
+
+			_, err := svc.ManualAccess(context.Background(), tt.req, uuid.New())
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			m.accessLogRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestService_CheckAccess_MLModelVersion(t *testing.T) {
+	svc, m := newTestService(Config{MinConfidence: 0.7, StrictDirection: true})
+	log := &recordingLogger{Logger: logger.NewNoop()}
+	svc.logger = log
+
+	m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(true, "ambulance", nil)
+	m.mlClient.On("RecognizePlate", mock.Anything, "image", 0.7).
+		Return(&ml.RecognitionResult{Success: true, LicensePlate: "A123BC777", Confidence: 95, ModelVersion: "v1"}, nil).Once()
+	m.mlClient.On("RecognizePlate", mock.Anything, "image", 0.7).
+		Return(&ml.RecognitionResult{Success: true, LicensePlate: "A123BC777", Confidence: 95, ModelVersion: "v2"}, nil).Once()
+
+	var versions []string
+	m.accessLogRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.AccessLog")).
+		Run(func(args mock.Arguments) {
+			versions = append(versions, args.Get(1).(*domain.AccessLog).MLModelVersion)
+		}).
+		Return(nil)
+
+	req := &CheckAccessRequest{ImageBase64: "image", GateID: "gate-1", Direction: "IN"}
+
+	_, err := svc.CheckAccess(context.Background(), req)
+	require.NoError(t, err)
+	assert.Empty(t, log.warnings, "первая наблюдаемая версия не считается сменой")
+
+	_, err = svc.CheckAccess(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"v1", "v2"}, versions)
+	assert.Contains(t, log.warnings, "ML model version changed")
+	assert.Equal(t, "v2", svc.MLVersion())
+
+	m.assertExpectations(t)
+}
+
+// mapRecognitionCache - кэш распознавания в памяти
+type mapRecognitionCache map[string]*ml.RecognitionResult
+
+func (c mapRecognitionCache) Get(_ context.Context, imageHash string) (*ml.RecognitionResult, bool) {
+	result, ok := c[imageHash]
+	return result, ok
+}
+
+func (c mapRecognitionCache) Set(_ context.Context, imageHash string, result *ml.RecognitionResult) {
+	c[imageHash] = result
+}
+
+// В журнал пишется версия модели, распознавшей именно этот кадр, а не последняя наблюдаемая
+func TestService_MLModelVersion_CachedAndManual(t *testing.T) {
+	svc, m := newTestService(Config{MinConfidence: 0.7, StrictDirection: true})
+	svc.recognitions = mapRecognitionCache{}
+
+	m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(true, "ambulance", nil)
+	m.mlClient.On("RecognizePlate", mock.Anything, "first", 0.7).
+		Return(&ml.RecognitionResult{Success: true, LicensePlate: "A123BC777", Confidence: 95, ModelVersion: "v1"}, nil).Once()
+	m.mlClient.On("RecognizePlate", mock.Anything, "second", 0.7).
+		Return(&ml.RecognitionResult{Success: true, LicensePlate: "A123BC777", Confidence: 95, ModelVersion: "v2"}, nil).Once()
+
+	var versions []string
+	m.accessLogRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.AccessLog")).
+		Run(func(args mock.Arguments) {
+			versions = append(versions, args.Get(1).(*domain.AccessLog).MLModelVersion)
+		}).
+		Return(nil)
+
+	for _, image := range []string{"first", "second", "first"} {
+		_, err := svc.CheckAccess(context.Background(), &CheckAccessRequest{ImageBase64: image, GateID: "gate-1", Direction: "IN"})
+		require.NoError(t, err)
+	}
+
+	_, err := svc.ManualAccess(context.Background(), &ManualAccessRequest{
+		LicensePlate: "A123BC777",
+		GateID:       "gate-1",
+		Direction:    "IN",
+		Reason:       "Грязный номер",
+	}, uuid.New())
+	require.NoError(t, err)
+
+	// Повтор первого кадра взят из кэша, ручная проверка модель не использует
+	assert.Equal(t, []string{"v1", "v2", "v1", ""}, versions)
+	assert.Equal(t, "v2", svc.MLVersion())
+	m.assertExpectations(t)
+}
+
+// ML сервис вернул номер кириллицей, а в списках он записан латиницей: проверка должна совпасть
+func TestService_CheckAccess_CyrillicRecognition(t *testing.T) {
+	svc, m := newTestService(Config{MinConfidence: 0.7})
+
+	m.mlClient.On("RecognizePlate", mock.Anything, "image", 0.7).
+		Return(&ml.RecognitionResult{Success: true, LicensePlate: "А123ВС777", Confidence: 95}, nil)
+	m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(true, "ambulance", nil)
+
+	var logged *domain.AccessLog
+	m.accessLogRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.AccessLog")).
+		Run(func(args mock.Arguments) { logged = args.Get(1).(*domain.AccessLog) }).
+		Return(nil)
+
+	response, err := svc.CheckAccess(context.Background(), &CheckAccessRequest{ImageBase64: "image", GateID: "gate-1", Direction: "IN"})
+
+	require.NoError(t, err)
+	assert.True(t, response.AccessGranted)
+	assert.Equal(t, "A123BC777", response.LicensePlate)
+	require.NotNil(t, logged)
+	assert.Equal(t, "A123BC777", logged.LicensePlate, "журнал ищется по номеру - запись в той же раскладке, что и списки")
+	m.assertExpectations(t)
+}
+
+func TestService_CheckAccess_ObserveOnlyGate(t *testing.T) {
+	tests := []struct {
+		name        string
+		whitelisted bool
+		blacklisted bool
+		loggedGrant bool
+		loggedCode  ReasonCode
+	}{
+		{
+			name:        "разрешение записывается в лог, но не возвращается",
+			whitelisted: true,
+			loggedGrant: true,
+			loggedCode:  ReasonWhitelisted,
+		},
+		{
+			name:        "отказ записывается в лог без учета в метриках",
+			blacklisted: true,
+			loggedGrant: false,
+			loggedCode:  ReasonBlacklisted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestService(Config{MinConfidence: 0.7, ObserveOnlyGates: []string{"gate-new"}})
+
+			m.mlClient.On("RecognizePlate", mock.Anything, "image", 0.7).
+				Return(&ml.RecognitionResult{Success: true, LicensePlate: "A123BC777", Confidence: 95}, nil)
+			m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(tt.whitelisted, "", nil)
+			if !tt.whitelisted {
+				m.blacklistRepo.On("IsBlacklisted", mock.Anything, "A123BC777", "").Return(tt.blacklisted, "stolen", nil)
+			}
+
+			var logged *domain.AccessLog
+			m.accessLogRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.AccessLog")).
+				Run(func(args mock.Arguments) {
+					logged = args.Get(1).(*domain.AccessLog)
+				}).
+				Return(nil)
+
+			resp, err := svc.CheckAccess(context.Background(), &CheckAccessRequest{ImageBase64: "image", GateID: "gate-new", Direction: "IN"})
+			require.NoError(t, err)
+
+			assert.False(t, resp.AccessGranted)
+			assert.True(t, resp.Observed)
+			assert.Equal(t, ReasonObservationMode, resp.ReasonCode)
+			assert.Equal(t, "Observation mode", resp.Reason)
+			assert.Equal(t, "A123BC777", resp.LicensePlate)
+
+			require.NotNil(t, logged)
+			assert.True(t, logged.Observed)
+			assert.Equal(t, tt.loggedGrant, logged.AccessGranted)
+			assert.Equal(t, string(tt.loggedCode), logged.ReasonCode)
+
+			assert.Empty(t, svc.DeniedReasonCounts())
+
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestService_CheckAccess_ImageRejected(t *testing.T) {
+	svc, m := newTestService(Config{MinConfidence: 0.7})
+
+	m.mlClient.On("RecognizePlate", mock.Anything, "image", 0.7).
+		Return(nil, fmt.Errorf("%w: ML service returned status 413", domain.ErrMLImageRejected))
+
+	resp, err := svc.CheckAccess(context.Background(), &CheckAccessRequest{ImageBase64: "image", GateID: "gate-1", Direction: "IN"})
+	require.NoError(t, err)
+
+	assert.False(t, resp.AccessGranted)
+	assert.Equal(t, ReasonImageRejected, resp.ReasonCode)
+	assert.Equal(t, "Image rejected by recognizer", resp.Reason)
+
+	m.assertExpectations(t)
+}
+
+func TestService_CheckAccess_LowConfidenceReview(t *testing.T) {
+	tests := []struct {
+		name           string
+		confidence     float64
+		mockSetup      func(*serviceMocks)
+		expectedGrant  bool
+		expectedReason ReasonCode
+		expectedReview bool
+	}{
+		{
+			name:       "уверенность не ниже MinConfidence - обычное решение",
+			confidence: 0.9,
+			mockSetup: func(m *serviceMocks) {
+				m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(true, "ambulance", nil)
+			},
+			expectedGrant:  true,
+			expectedReason: ReasonWhitelisted,
+		},
+		{
+			name:           "между порогами - отказ с пометкой на разбор",
+			confidence:     0.55,
+			mockSetup:      func(m *serviceMocks) {},
+			expectedReason: ReasonNeedsReview,
+			expectedReview: true,
+		},
+		{
+			name:           "ниже ReviewThreshold - номер не распознан",
+			confidence:     0.3,
+			mockSetup:      func(m *serviceMocks) {},
+			expectedReason: ReasonPlateNotRecognized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestService(Config{MinConfidence: 0.7, ReviewThreshold: 0.4})
+			tt.mockSetup(m)
+
+			// ML сервису передается нижний порог, чтобы он не отбросил номера из полосы разбора
+			m.mlClient.On("RecognizePlate", mock.Anything, "image", 0.4).
+				Return(&ml.RecognitionResult{Success: true, LicensePlate: "A123BC777", Confidence: tt.confidence}, nil)
+
+			var logged *domain.AccessLog
+			m.accessLogRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.AccessLog")).
+				Run(func(args mock.Arguments) { logged = args.Get(1).(*domain.AccessLog) }).
+				Return(nil)
+
+			resp, err := svc.CheckAccess(context.Background(), &CheckAccessRequest{ImageBase64: "image", GateID: "gate-1", Direction: "IN"})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedGrant, resp.AccessGranted)
+			assert.Equal(t, tt.expectedReason, resp.ReasonCode)
+			assert.Equal(t, tt.expectedReview, resp.NeedsReview)
+			assert.Equal(t, "A123BC777", resp.LicensePlate)
+
+			// Решение записано в журнал: операторы находят проезды на разбор по reason_code
+			require.NotNil(t, logged)
+			assert.Equal(t, string(tt.expectedReason), logged.ReasonCode)
+			assert.Equal(t, tt.confidence, logged.RecognitionConfidence)
+
+			if tt.expectedReason != ReasonWhitelisted {
+				m.whitelistRepo.AssertNotCalled(t, "IsWhitelisted", mock.Anything, mock.Anything, mock.Anything)
+			}
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestService_CheckAccess_LowConfidenceReviewDisabled(t *testing.T) {
+	// Без ReviewThreshold порог MinConfidence применяет ML сервис, как и раньше
+	svc, m := newTestService(Config{MinConfidence: 0.7})
+
+	m.mlClient.On("RecognizePlate", mock.Anything, "image", 0.7).
+		Return(&ml.RecognitionResult{Success: false, Error: "Confidence 0.55 below threshold 0.7"}, nil)
+	m.accessLogRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.AccessLog")).Return(nil).Maybe()
+
+	resp, err := svc.CheckAccess(context.Background(), &CheckAccessRequest{ImageBase64: "image", GateID: "gate-1", Direction: "IN"})
+	require.NoError(t, err)
+
+	assert.False(t, resp.AccessGranted)
+	assert.Equal(t, ReasonPlateNotRecognized, resp.ReasonCode)
+	assert.False(t, resp.NeedsReview)
+	m.assertExpectations(t)
+}
+
+func TestService_SimulateAccess_LowConfidenceReview(t *testing.T) {
+	svc, _ := newTestService(Config{MinConfidence: 0.7, ReviewThreshold: 0.4})
+
+	confidence := 0.5
+	result, err := svc.SimulateAccess(context.Background(), &SimulateAccessRequest{
+		LicensePlate: "A123BC777",
+		Direction:    "IN",
+		Confidence:   &confidence,
+	})
+	require.NoError(t, err)
+
+	assert.False(t, result.Decision.AccessGranted)
+	assert.True(t, result.Decision.NeedsReview)
+	assert.Equal(t, ReasonNeedsReview, result.Decision.ReasonCode)
+}
+
+func TestService_CheckAccess_Timings(t *testing.T) {
+	ownerID := uuid.New()
+	vehicle := &domain.Vehicle{ID: uuid.New(), OwnerID: ownerID, LicensePlate: "A123BC777", IsActive: true}
+	owner := &domain.User{ID: ownerID, Role: domain.RoleUser, IsActive: true}
+	validPass := &domain.Pass{
+		ID:        uuid.New(),
+		UserID:    ownerID,
+		PassType:  domain.PassTypePermanent,
+		ValidFrom: time.Now().Add(-time.Hour),
+		IsActive:  true,
+	}
+
+	svc, m := newTestService(Config{MinConfidence: 0.7})
+
+	// Задержки моков задают нижнюю границу измеренных длительностей
+	m.mlClient.On("RecognizePlate", mock.Anything, "image", 0.7).
+		After(5*time.Millisecond).
+		Return(&ml.RecognitionResult{Success: true, LicensePlate: "A123BC777", Confidence: 95}, nil)
+	m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+	m.blacklistRepo.On("IsBlacklisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+	m.vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(vehicle, nil)
+	m.userRepo.On("GetByID", mock.Anything, ownerID).Return(owner, nil)
+	m.passRepo.On("GetActivePassesByUserAndVehicle", mock.Anything, ownerID, vehicle.ID).
+		After(5*time.Millisecond).
+		Return([]*domain.Pass{validPass}, nil)
+	m.accessLogRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.AccessLog")).Return(nil)
+
+	resp, err := svc.CheckAccess(context.Background(), &CheckAccessRequest{ImageBase64: "image", GateID: "gate-1", Direction: "IN"})
+	require.NoError(t, err)
+
+	assert.True(t, resp.AccessGranted)
+	assert.GreaterOrEqual(t, resp.RecognitionTimeMs, int64(5))
+	assert.GreaterOrEqual(t, resp.ValidationTimeMs, int64(5))
+
+	body, err := json.Marshal(resp)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"recognition_time_ms":`)
+	assert.Contains(t, string(body), `"validation_time_ms":`)
+
+	m.assertExpectations(t)
+}
+
+func TestService_CheckAccess_StaleFrame(t *testing.T) {
+	fresh := time.Now().Add(-5 * time.Second)
+	stale := time.Now().Add(-10 * time.Minute)
+
+	tests := []struct {
+		name        string
+		config      Config
+		capturedAt  *time.Time
+		expectedErr error
+		expectStale bool
+		expectLog   bool
+	}{
+		{
+			name:       "свежий кадр обрабатывается",
+			config:     Config{MinConfidence: 0.7, MaxFrameAge: time.Minute, RejectStaleFrames: true},
+			capturedAt: &fresh,
+			expectLog:  true,
+		},
+		{
+			name:      "без captured_at кадр считается свежим",
+			config:    Config{MinConfidence: 0.7, MaxFrameAge: time.Minute, RejectStaleFrames: true},
+			expectLog: true,
+		},
+		{
+			name:        "устаревший кадр отклоняется",
+			config:      Config{MinConfidence: 0.7, MaxFrameAge: time.Minute, RejectStaleFrames: true},
+			capturedAt:  &stale,
+			expectedErr: domain.ErrStaleFrame,
+		},
+		{
+			name:        "устаревший кадр помечается и не пишется в лог",
+			config:      Config{MinConfidence: 0.7, MaxFrameAge: time.Minute},
+			capturedAt:  &stale,
+			expectStale: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestService(tt.config)
+
+			if tt.expectedErr == nil {
+				m.mlClient.On("RecognizePlate", mock.Anything, "image", 0.7).
+					Return(&ml.RecognitionResult{Success: true, LicensePlate: "A123BC777", Confidence: 95}, nil)
+				m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(true, "emergency", nil)
+			}
+			if tt.expectLog {
+				m.accessLogRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.AccessLog")).Return(nil)
+			}
+
+			resp, err := svc.CheckAccess(context.Background(), &CheckAccessRequest{
+				ImageBase64: "image",
+				GateID:      "gate-1",
+				Direction:   "IN",
+				CapturedAt:  tt.capturedAt,
+			})
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, resp)
+				m.mlClient.AssertNotCalled(t, "RecognizePlate", mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				require.NoError(t, err)
+				assert.True(t, resp.AccessGranted)
+				assert.Equal(t, tt.expectStale, resp.Stale)
+			}
+			if !tt.expectLog {
+				m.accessLogRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			}
+
+			m.assertExpectations(t)
+		})
+	}
+}
+
+// fakeUnregisteredCache - in-memory кэш отказов по номеру и воротам
+type fakeUnregisteredCache map[string]bool
+
+func (c fakeUnregisteredCache) IsUnregistered(ctx context.Context, licensePlate, gateID string) bool {
+	return c[licensePlate+"|"+gateID]
+}
+
+func (c fakeUnregisteredCache) MarkUnregistered(ctx context.Context, licensePlate, gateID string) {
+	c[licensePlate+"|"+gateID] = true
+}
+
+func TestService_CheckAccess_UnregisteredPlateCache(t *testing.T) {
+	m := &serviceMocks{
+		vehicleRepo:   new(mocks.MockVehicleRepository),
+		userRepo:      new(mocks.MockUserRepository),
+		passRepo:      new(mocks.MockPassRepository),
+		accessLogRepo: new(mocks.MockAccessLogRepository),
+		whitelistRepo: new(mocks.MockWhitelistRepository),
+		blacklistRepo: new(mocks.MockBlacklistRepository),
+		mlClient:      new(mockMLClient),
+	}
+	cache := fakeUnregisteredCache{}
+	svc := NewService(m.vehicleRepo, m.userRepo, m.passRepo, m.accessLogRepo, m.whitelistRepo, m.blacklistRepo,
+		m.mlClient, nil, cache, nil, nil, nil, logger.NewNoop(), Config{MinConfidence: 0.7})
+
+	m.mlClient.On("RecognizePlate", mock.Anything, "image", 0.7).
+		Return(&ml.RecognitionResult{Success: true, LicensePlate: "A123BC777", Confidence: 95}, nil)
+	m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+	m.blacklistRepo.On("IsBlacklisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+	m.accessLogRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.AccessLog")).Return(nil)
+	m.vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(nil, domain.ErrVehicleNotFound).Once()
+
+	// Повторные проезды на тех же воротах отвечаются из кэша
+	for i := 0; i < 3; i++ {
+		resp, err := svc.CheckAccess(context.Background(), &CheckAccessRequest{ImageBase64: "image", GateID: "gate-1", Direction: "IN"})
+		require.NoError(t, err)
+		assert.False(t, resp.AccessGranted)
+		assert.Equal(t, ReasonVehicleNotRegistered, resp.ReasonCode)
+	}
+	m.vehicleRepo.AssertNumberOfCalls(t, "GetByLicensePlate", 1)
+
+	// После регистрации автомобиля (инвалидация кэша) номер снова ищется в БД
+	delete(cache, "A123BC777|gate-1")
+	vehicle := &domain.Vehicle{ID: uuid.New(), OwnerID: uuid.New(), LicensePlate: "A123BC777", IsActive: false}
+	m.vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(vehicle, nil).Once()
+
+	resp, err := svc.CheckAccess(context.Background(), &CheckAccessRequest{ImageBase64: "image", GateID: "gate-1", Direction: "IN"})
+	require.NoError(t, err)
+	assert.Equal(t, ReasonVehicleInactive, resp.ReasonCode)
+	m.vehicleRepo.AssertNumberOfCalls(t, "GetByLicensePlate", 2)
+	m.assertExpectations(t)
+}
+
+// fakeRecognitionCache - in-memory кэш распознавания по хешу изображения
+type fakeRecognitionCache map[string]*ml.RecognitionResult
+
+func (c fakeRecognitionCache) Get(ctx context.Context, imageHash string) (*ml.RecognitionResult, bool) {
+	result, ok := c[imageHash]
+	return result, ok
+}
+
+func (c fakeRecognitionCache) Set(ctx context.Context, imageHash string, result *ml.RecognitionResult) {
+	c[imageHash] = result
+}
+
+func TestService_CheckAccess_RecognitionCache(t *testing.T) {
+	newService := func() (*Service, *serviceMocks, fakeRecognitionCache) {
+		m := &serviceMocks{
+			vehicleRepo:   new(mocks.MockVehicleRepository),
+			userRepo:      new(mocks.MockUserRepository),
+			passRepo:      new(mocks.MockPassRepository),
+			accessLogRepo: new(mocks.MockAccessLogRepository),
+			whitelistRepo: new(mocks.MockWhitelistRepository),
+			blacklistRepo: new(mocks.MockBlacklistRepository),
+			mlClient:      new(mockMLClient),
+		}
+		cache := fakeRecognitionCache{}
+		svc := NewService(m.vehicleRepo, m.userRepo, m.passRepo, m.accessLogRepo, m.whitelistRepo, m.blacklistRepo,
+			m.mlClient, cache, nil, nil, nil, nil, logger.NewNoop(), Config{MinConfidence: 0.7})
+		return svc, m, cache
+	}
+
+	t.Run("повторный кадр не отправляется в ML сервис", func(t *testing.T) {
+		svc, m, cache := newService()
+
+		m.mlClient.On("RecognizePlate", mock.Anything, "image", 0.7).
+			Return(&ml.RecognitionResult{Success: true, LicensePlate: "A123BC777", Confidence: 95}, nil).Once()
+		m.mlClient.On("RecognizePlate", mock.Anything, "other-image", 0.7).
+			Return(&ml.RecognitionResult{Success: true, LicensePlate: "B456CD777", Confidence: 90}, nil).Once()
+		m.whitelistRepo.On("IsWhitelisted", mock.Anything, mock.Anything, "").Return(true, "Скорая помощь", nil)
+		m.accessLogRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.AccessLog")).Return(nil)
+
+		for i := 0; i < 2; i++ {
+			resp, err := svc.CheckAccess(context.Background(), &CheckAccessRequest{ImageBase64: "image", GateID: "gate-1", Direction: "IN"})
+			require.NoError(t, err)
+			assert.Equal(t, "A123BC777", resp.LicensePlate)
+			assert.True(t, resp.AccessGranted)
+		}
+		m.mlClient.AssertNumberOfCalls(t, "RecognizePlate", 1)
+		assert.Len(t, cache, 1)
+
+		// Другой кадр распознается заново
+		resp, err := svc.CheckAccess(context.Background(), &CheckAccessRequest{ImageBase64: "other-image", GateID: "gate-1", Direction: "IN"})
+		require.NoError(t, err)
+		assert.Equal(t, "B456CD777", resp.LicensePlate)
+		m.mlClient.AssertNumberOfCalls(t, "RecognizePlate", 2)
+		m.assertExpectations(t)
+	})
+
+	t.Run("нераспознанный кадр не кэшируется", func(t *testing.T) {
+		svc, m, cache := newService()
+
+		m.mlClient.On("RecognizePlate", mock.Anything, "image", 0.7).
+			Return(&ml.RecognitionResult{Success: false, Error: "no plate"}, nil)
+		m.accessLogRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.AccessLog")).Return(nil)
+
+		for i := 0; i < 2; i++ {
+			resp, err := svc.CheckAccess(context.Background(), &CheckAccessRequest{ImageBase64: "image", GateID: "gate-1", Direction: "IN"})
+			require.NoError(t, err)
+			assert.Equal(t, ReasonPlateNotRecognized, resp.ReasonCode)
+		}
+		m.mlClient.AssertNumberOfCalls(t, "RecognizePlate", 2)
+		assert.Empty(t, cache)
+	})
+}
+
+func TestService_GetAccessLog(t *testing.T) {
+	logID := uuid.New()
+	userID := uuid.New()
+	vehicleID := uuid.New()
+	user := &domain.User{ID: userID, FullName: "Иван Иванов"}
+	vehicle := &domain.Vehicle{ID: vehicleID, LicensePlate: "A123BC777"}
+
+	newLog := func() *domain.AccessLog {
+		return &domain.AccessLog{ID: logID, UserID: &userID, VehicleID: &vehicleID, LicensePlate: "A123BC777", ImageURL: "frames/1.jpg"}
+	}
+
+	tests := []struct {
+		name        string
+		mockSetup   func(*serviceMocks)
+		expectedErr error
+		check       func(*testing.T, *domain.AccessLog)
+	}{
+		{
+			name: "запись дополняется пользователем и автомобилем",
+			mockSetup: func(m *serviceMocks) {
+				m.accessLogRepo.On("GetByID", mock.Anything, logID).Return(newLog(), nil)
+				m.userRepo.On("GetByID", mock.Anything, userID).Return(user, nil)
+				m.vehicleRepo.On("GetByID", mock.Anything, vehicleID).Return(vehicle, nil)
+			},
+			check: func(t *testing.T, log *domain.AccessLog) {
+				assert.Equal(t, "frames/1.jpg", log.ImageURL)
+				assert.Equal(t, user, log.User)
+				assert.Equal(t, vehicle, log.Vehicle)
+			},
+		},
+		{
+			name: "удаленный автомобиль не мешает ответу",
+			mockSetup: func(m *serviceMocks) {
+				m.accessLogRepo.On("GetByID", mock.Anything, logID).Return(newLog(), nil)
+				m.userRepo.On("GetByID", mock.Anything, userID).Return(user, nil)
+				m.vehicleRepo.On("GetByID", mock.Anything, vehicleID).Return(nil, domain.ErrVehicleNotFound)
+			},
+			check: func(t *testing.T, log *domain.AccessLog) {
+				assert.Equal(t, user, log.User)
+				assert.Nil(t, log.Vehicle)
+			},
+		},
+		{
+			name: "номер без владельца - связанных данных нет",
+			mockSetup: func(m *serviceMocks) {
+				m.accessLogRepo.On("GetByID", mock.Anything, logID).Return(&domain.AccessLog{ID: logID, LicensePlate: "B456CE777"}, nil)
+			},
+			check: func(t *testing.T, log *domain.AccessLog) {
+				assert.Nil(t, log.User)
+				assert.Nil(t, log.Vehicle)
+			},
+		},
+		{
+			name: "запись не найдена",
+			mockSetup: func(m *serviceMocks) {
+				m.accessLogRepo.On("GetByID", mock.Anything, logID).Return(nil, domain.ErrAccessLogNotFound)
+			},
+			expectedErr: domain.ErrAccessLogNotFound,
+		},
+		{
+			name: "ошибка загрузки пользователя",
+			mockSetup: func(m *serviceMocks) {
+				m.accessLogRepo.On("GetByID", mock.Anything, logID).Return(newLog(), nil)
+				m.userRepo.On("GetByID", mock.Anything, userID).Return(nil, assert.AnError)
+			},
+			expectedErr: assert.AnError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestService(Config{})
+			tt.mockSetup(m)
+
+			log, err := svc.GetAccessLog(context.Background(), logID)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, log)
+			} else {
+				require.NoError(t, err)
+				tt.check(t, log)
+			}
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestService_LoadRelations(t *testing.T) {
+	userA, userB := uuid.New(), uuid.New()
+	vehicleA := uuid.New()
+	deletedVehicle := uuid.New()
+
+	newLogs := func() []*domain.AccessLog {
+		return []*domain.AccessLog{
+			{UserID: &userA, VehicleID: &vehicleA, LicensePlate: "A123BC777"},
+			{UserID: &userA, VehicleID: &vehicleA, LicensePlate: "A123BC777"},
+			{UserID: &userB, VehicleID: &deletedVehicle, LicensePlate: "B456CE777"},
+			{LicensePlate: "X999XX99"}, // Незарегистрированный номер
+		}
+	}
+
+	t.Run("уникальные ID загружаются одним запросом на вид данных", func(t *testing.T) {
+		svc, m := newTestService(Config{})
+		m.userRepo.On("GetUsersByIDs", mock.Anything, []uuid.UUID{userA, userB}).
+			Return([]*domain.User{{ID: userA, FullName: "A"}, {ID: userB, FullName: "B"}}, nil).Once()
+		// Удаленный автомобиль не возвращается репозиторием
+		m.vehicleRepo.On("GetVehiclesByIDs", mock.Anything, []uuid.UUID{vehicleA, deletedVehicle}).
+			Return([]*domain.Vehicle{{ID: vehicleA, LicensePlate: "A123BC777"}}, nil).Once()
+
+		logs := newLogs()
+		require.NoError(t, svc.LoadRelations(context.Background(), logs, LogInclude{User: true, Vehicle: true}))
+
+		assert.Equal(t, "A", logs[0].User.FullName)
+		assert.Equal(t, "A", logs[1].User.FullName)
+		assert.Equal(t, "B", logs[2].User.FullName)
+		assert.Equal(t, vehicleA, logs[0].Vehicle.ID)
+		assert.Nil(t, logs[2].Vehicle)
+		assert.Nil(t, logs[3].User)
+		assert.Nil(t, logs[3].Vehicle)
+		m.assertExpectations(t)
+	})
+
+	t.Run("только запрошенные данные", func(t *testing.T) {
+		svc, m := newTestService(Config{})
+		m.vehicleRepo.On("GetVehiclesByIDs", mock.Anything, mock.Anything).
+			Return([]*domain.Vehicle{{ID: vehicleA}}, nil).Once()
+
+		logs := newLogs()
+		require.NoError(t, svc.LoadRelations(context.Background(), logs, LogInclude{Vehicle: true}))
+
+		assert.NotNil(t, logs[0].Vehicle)
+		assert.Nil(t, logs[0].User)
+		m.userRepo.AssertNotCalled(t, "GetUsersByIDs", mock.Anything, mock.Anything)
+		m.assertExpectations(t)
+	})
+
+	t.Run("без include запросов нет", func(t *testing.T) {
+		svc, m := newTestService(Config{})
+
+		logs := newLogs()
+		require.NoError(t, svc.LoadRelations(context.Background(), logs, LogInclude{}))
+
+		assert.Nil(t, logs[0].User)
+		assert.Nil(t, logs[0].Vehicle)
+		m.assertExpectations(t)
+	})
+
+	t.Run("ошибка загрузки", func(t *testing.T) {
+		svc, m := newTestService(Config{})
+		m.userRepo.On("GetUsersByIDs", mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+		err := svc.LoadRelations(context.Background(), newLogs(), LogInclude{User: true})
+
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestService_GetStats(t *testing.T) {
+	from := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	t.Run("пустой период возвращает нулевую статистику", func(t *testing.T) {
+		svc, m := newTestService(Config{})
+		m.accessLogRepo.On("GetStatsByPeriod", mock.Anything, from, to).Return(&domain.AccessStats{From: from, To: to}, nil)
+
+		stats, err := svc.GetStats(context.Background(), from, to)
+		require.NoError(t, err)
+		assert.Zero(t, stats.TotalCount)
+		assert.Zero(t, stats.AvgConfidence)
+		m.assertExpectations(t)
+	})
+
+	t.Run("from не раньше to", func(t *testing.T) {
+		svc, m := newTestService(Config{})
+
+		_, err := svc.GetStats(context.Background(), to, from)
+		assert.ErrorIs(t, err, domain.ErrInvalidDateRange)
+
+		_, err = svc.GetStats(context.Background(), from, from)
+		assert.ErrorIs(t, err, domain.ErrInvalidDateRange)
+		m.accessLogRepo.AssertNotCalled(t, "GetStatsByPeriod", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_CheckAccess_AntiPassback(t *testing.T) {
+	ownerID := uuid.New()
+	vehicle := &domain.Vehicle{ID: uuid.New(), OwnerID: ownerID, LicensePlate: "A123BC777", IsActive: true}
+	owner := &domain.User{ID: ownerID, Role: domain.RoleUser, IsActive: true}
+	validPass := &domain.Pass{
+		ID:        uuid.New(),
+		UserID:    ownerID,
+		PassType:  domain.PassTypePermanent,
+		ValidFrom: time.Now().Add(-time.Hour),
+		IsActive:  true,
+	}
+	lastEntry := &domain.AccessLog{
+		LicensePlate:  "A123BC777",
+		AccessGranted: true,
+		Direction:     domain.DirectionIn,
+		Timestamp:     time.Now().Add(-time.Hour),
+	}
+
+	tests := []struct {
+		name           string
+		direction      string
+		whitelisted    bool
+		latest         *domain.AccessLog
+		latestErr      error
+		expectedGrant  bool
+		expectedReason ReasonCode
+	}{
+		{
+			name:           "повторный въезд без выезда",
+			direction:      "IN",
+			latest:         lastEntry,
+			expectedGrant:  false,
+			expectedReason: ReasonAntiPassback,
+		},
+		{
+			name:           "выезд после въезда",
+			direction:      "OUT",
+			latest:         lastEntry,
+			expectedGrant:  true,
+			expectedReason: ReasonValidPass,
+		},
+		{
+			name:           "первый проезд",
+			direction:      "IN",
+			latestErr:      domain.ErrAccessLogNotFound,
+			expectedGrant:  true,
+			expectedReason: ReasonValidPass,
+		},
+		{
+			name:           "белый список не ограничивается",
+			direction:      "IN",
+			whitelisted:    true,
+			expectedGrant:  true,
+			expectedReason: ReasonWhitelisted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestService(Config{MinConfidence: 0.7, AntiPassback: true})
+
+			m.mlClient.On("RecognizePlate", mock.Anything, "image", 0.7).
+				Return(&ml.RecognitionResult{Success: true, LicensePlate: "A123BC777", Confidence: 95}, nil)
+			m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(tt.whitelisted, "ambulance", nil)
+			if !tt.whitelisted {
+				m.blacklistRepo.On("IsBlacklisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+				m.vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(vehicle, nil)
+				m.userRepo.On("GetByID", mock.Anything, ownerID).Return(owner, nil)
+				m.passRepo.On("GetActivePassesByUserAndVehicle", mock.Anything, ownerID, vehicle.ID).
+					Return([]*domain.Pass{validPass}, nil)
+				m.accessLogRepo.On("GetLatestByLicensePlate", mock.Anything, "A123BC777").Return(tt.latest, tt.latestErr)
+			}
+
+			var logged *domain.AccessLog
+			m.accessLogRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.AccessLog")).
+				Run(func(args mock.Arguments) {
+					logged = args.Get(1).(*domain.AccessLog)
+				}).
+				Return(nil)
+
+			resp, err := svc.CheckAccess(context.Background(), &CheckAccessRequest{ImageBase64: "image", GateID: "gate-1", Direction: tt.direction})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedGrant, resp.AccessGranted)
+			assert.Equal(t, tt.expectedReason, resp.ReasonCode)
+			if tt.expectedReason == ReasonAntiPassback {
+				assert.Equal(t, "Anti-passback violation", resp.Reason)
+				assert.Nil(t, resp.Pass)
+			}
+
+			require.NotNil(t, logged)
+			assert.Equal(t, tt.expectedGrant, logged.AccessGranted)
+			assert.Equal(t, string(tt.expectedReason), logged.ReasonCode)
+
+			if tt.whitelisted {
+				m.accessLogRepo.AssertNotCalled(t, "GetLatestByLicensePlate", mock.Anything, mock.Anything)
+			}
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestService_CheckAccess_InferDirection(t *testing.T) {
+	tests := []struct {
+		name              string
+		latest            *domain.AccessLog
+		latestErr         error
+		expectedDirection domain.Direction
+	}{
+		{
+			name:              "первый проезд считается въездом",
+			latestErr:         domain.ErrAccessLogNotFound,
+			expectedDirection: domain.DirectionIn,
+		},
+		{
+			name:              "после въезда - выезд",
+			latest:            &domain.AccessLog{Direction: domain.DirectionIn},
+			expectedDirection: domain.DirectionOut,
+		},
+		{
+			name:              "после выезда - въезд",
+			latest:            &domain.AccessLog{Direction: domain.DirectionOut},
+			expectedDirection: domain.DirectionIn,
+		},
+		{
+			name:              "ошибка истории - въезд",
+			latestErr:         errors.New("db down"),
+			expectedDirection: domain.DirectionIn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestService(Config{MinConfidence: 0.7, InferDirectionGates: []string{"gate-loop"}})
+			m.mlClient.On("RecognizePlate", mock.Anything, "image", 0.7).
+				Return(&ml.RecognitionResult{Success: true, LicensePlate: "A123BC777", Confidence: 95}, nil)
+			m.accessLogRepo.On("GetLatestByLicensePlate", mock.Anything, "A123BC777").Return(tt.latest, tt.latestErr)
+			m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(true, "ambulance", nil)
+
+			var logged *domain.AccessLog
+			m.accessLogRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.AccessLog")).
+				Run(func(args mock.Arguments) {
+					logged = args.Get(1).(*domain.AccessLog)
+				}).
+				Return(nil)
+
+			resp, err := svc.CheckAccess(context.Background(), &CheckAccessRequest{ImageBase64: "image", GateID: "gate-loop"})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedDirection, resp.InferredDirection)
+			require.NotNil(t, logged)
+			assert.Equal(t, tt.expectedDirection, logged.Direction)
+			assert.True(t, logged.DirectionInferred)
+			m.assertExpectations(t)
+		})
+	}
+
+	t.Run("явное направление не переопределяется", func(t *testing.T) {
+		svc, m := newTestService(Config{MinConfidence: 0.7, InferDirectionGates: []string{"gate-loop"}})
+		m.mlClient.On("RecognizePlate", mock.Anything, "image", 0.7).
+			Return(&ml.RecognitionResult{Success: true, LicensePlate: "A123BC777", Confidence: 95}, nil)
+		m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(true, "ambulance", nil)
+
+		var logged *domain.AccessLog
+		m.accessLogRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.AccessLog")).
+			Run(func(args mock.Arguments) {
+				logged = args.Get(1).(*domain.AccessLog)
+			}).
+			Return(nil)
+
+		resp, err := svc.CheckAccess(context.Background(), &CheckAccessRequest{ImageBase64: "image", GateID: "gate-loop", Direction: "OUT"})
+		require.NoError(t, err)
+
+		assert.Empty(t, resp.InferredDirection)
+		require.NotNil(t, logged)
+		assert.Equal(t, domain.DirectionOut, logged.Direction)
+		assert.False(t, logged.DirectionInferred)
+		m.accessLogRepo.AssertNotCalled(t, "GetLatestByLicensePlate", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_CheckAccess_PassSchedule(t *testing.T) {
+	ownerID := uuid.New()
+	vehicle := &domain.Vehicle{ID: uuid.New(), OwnerID: ownerID, LicensePlate: "A123BC777", IsActive: true}
+	owner := &domain.User{ID: ownerID, Role: domain.RoleUser, IsActive: true}
+
+	// Окно, заведомо не включающее текущий момент
+	now := time.Now()
+	start := now.Add(2 * time.Hour).Format("15:04")
+	end := now.Add(3 * time.Hour).Format("15:04")
+	past := now.Add(-time.Hour)
+
+	outsideHours := &domain.Pass{
+		ID:               uuid.New(),
+		UserID:           ownerID,
+		PassType:         domain.PassTypePermanent,
+		ValidFrom:        now.Add(-24 * time.Hour),
+		IsActive:         true,
+		AllowedTimeStart: &start,
+		AllowedTimeEnd:   &end,
+	}
+	otherWeekday := &domain.Pass{
+		ID:              uuid.New(),
+		UserID:          ownerID,
+		PassType:        domain.PassTypePermanent,
+		ValidFrom:       now.Add(-24 * time.Hour),
+		IsActive:        true,
+		AllowedWeekdays: []time.Weekday{(now.Weekday() + 1) % 7},
+	}
+	expired := &domain.Pass{
+		ID:         uuid.New(),
+		UserID:     ownerID,
+		PassType:   domain.PassTypeTemporary,
+		ValidFrom:  now.Add(-48 * time.Hour),
+		ValidUntil: &past,
+		IsActive:   true,
+	}
+	today := &domain.Pass{
+		ID:              uuid.New(),
+		UserID:          ownerID,
+		PassType:        domain.PassTypePermanent,
+		ValidFrom:       now.Add(-24 * time.Hour),
+		IsActive:        true,
+		AllowedWeekdays: []time.Weekday{now.Weekday()},
+	}
+
+	tests := []struct {
+		name           string
+		passes         []*domain.Pass
+		expectedGrant  bool
+		expectedReason ReasonCode
+	}{
+		{
+			name:           "вне разрешенных часов",
+			passes:         []*domain.Pass{outsideHours},
+			expectedReason: ReasonPassOutsideHours,
+		},
+		{
+			name:           "не разрешенный день недели",
+			passes:         []*domain.Pass{otherWeekday},
+			expectedReason: ReasonPassOutsideHours,
+		},
+		{
+			name:           "истекший пропуск остается PASS_EXPIRED",
+			passes:         []*domain.Pass{expired},
+			expectedReason: ReasonPassExpired,
+		},
+		{
+			name:           "расписание приоритетнее истечения другого пропуска",
+			passes:         []*domain.Pass{expired, outsideHours},
+			expectedReason: ReasonPassOutsideHours,
+		},
+		{
+			name:           "разрешенный день недели",
+			passes:         []*domain.Pass{otherWeekday, today},
+			expectedGrant:  true,
+			expectedReason: ReasonValidPass,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestService(Config{MinConfidence: 0.7})
+			m.mlClient.On("RecognizePlate", mock.Anything, "image", 0.7).
+				Return(&ml.RecognitionResult{Success: true, LicensePlate: "A123BC777", Confidence: 95}, nil)
+			m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+			m.blacklistRepo.On("IsBlacklisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+			m.vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(vehicle, nil)
+			m.userRepo.On("GetByID", mock.Anything, ownerID).Return(owner, nil)
+			m.passRepo.On("GetActivePassesByUserAndVehicle", mock.Anything, ownerID, vehicle.ID).Return(tt.passes, nil)
+			m.accessLogRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.AccessLog")).Return(nil)
+
+			resp, err := svc.CheckAccess(context.Background(), &CheckAccessRequest{ImageBase64: "image", GateID: "gate-1", Direction: "IN"})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedGrant, resp.AccessGranted)
+			assert.Equal(t, tt.expectedReason, resp.ReasonCode)
+			if tt.expectedReason == ReasonPassOutsideHours {
+				assert.Equal(t, "Outside allowed hours", resp.Reason)
+			}
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestService_CheckAccess_PassUsageLimit(t *testing.T) {
+	ownerID := uuid.New()
+	vehicle := &domain.Vehicle{ID: uuid.New(), OwnerID: ownerID, LicensePlate: "A123BC777", IsActive: true}
+	owner := &domain.User{ID: ownerID, Role: domain.RoleUser, IsActive: true}
+	maxUses := 3
+
+	tests := []struct {
+		name            string
+		usesCount       int
+		gateID          string
+		incrementErr    error
+		expectIncrement bool
+		expectedGrant   bool
+		expectedReason  ReasonCode
+	}{
+		{
+			name:            "последний разрешенный проезд",
+			usesCount:       2,
+			expectIncrement: true,
+			expectedGrant:   true,
+			expectedReason:  ReasonValidPass,
+		},
+		{
+			name:           "первый проезд сверх лимита",
+			usesCount:      3,
+			expectedReason: ReasonPassUsageLimit,
+		},
+		{
+			name:            "последний проезд забран параллельно",
+			usesCount:       2,
+			incrementErr:    domain.ErrPassUsageLimitReached,
+			expectIncrement: true,
+			expectedReason:  ReasonPassUsageLimit,
+		},
+		{
+			name:            "ошибка учета проезда - решение все равно записано в журнал",
+			usesCount:       2,
+			incrementErr:    errors.New("connection reset"),
+			expectIncrement: true,
+			expectedGrant:   true,
+			expectedReason:  ReasonValidPass,
+		},
+		{
+			name:           "ворота в режиме наблюдения не расходуют проезд",
+			usesCount:      2,
+			gateID:         "gate-observe",
+			expectedGrant:  false,
+			expectedReason: ReasonObservationMode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateID := tt.gateID
+			if gateID == "" {
+				gateID = "gate-1"
+			}
+			pass := &domain.Pass{
+				ID:        uuid.New(),
+				UserID:    ownerID,
+				PassType:  domain.PassTypePermanent,
+				ValidFrom: time.Now().Add(-time.Hour),
+				IsActive:  true,
+				MaxUses:   &maxUses,
+				UsesCount: tt.usesCount,
+			}
+
+			svc, m := newTestService(Config{MinConfidence: 0.7, ObserveOnlyGates: []string{"gate-observe"}})
+			m.mlClient.On("RecognizePlate", mock.Anything, "image", 0.7).
+				Return(&ml.RecognitionResult{Success: true, LicensePlate: "A123BC777", Confidence: 95}, nil)
+			m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+			m.blacklistRepo.On("IsBlacklisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+			m.vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(vehicle, nil)
+			m.userRepo.On("GetByID", mock.Anything, ownerID).Return(owner, nil)
+			m.passRepo.On("GetActivePassesByUserAndVehicle", mock.Anything, ownerID, vehicle.ID).
+				Return([]*domain.Pass{pass}, nil)
+
+			var logged *domain.AccessLog
+			captureLog := func(args mock.Arguments) {
+				logged = args.Get(len(args) - 1).(*domain.AccessLog)
+			}
+			if tt.expectIncrement {
+				m.passRepo.On("IncrementUses", mock.Anything, pass.ID, mock.AnythingOfType("*domain.AccessLog")).
+					Run(captureLog).Return(tt.incrementErr)
+			}
+			if !tt.expectIncrement || tt.incrementErr != nil {
+				m.accessLogRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.AccessLog")).
+					Run(captureLog).Return(nil)
+			}
+
+			resp, err := svc.CheckAccess(context.Background(), &CheckAccessRequest{ImageBase64: "image", GateID: gateID, Direction: "IN"})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedGrant, resp.AccessGranted)
+			assert.Equal(t, tt.expectedReason, resp.ReasonCode)
+			require.NotNil(t, logged)
+			if tt.expectIncrement {
+				assert.Equal(t, tt.expectedGrant, logged.AccessGranted)
+			}
+			if tt.expectedReason == ReasonPassUsageLimit {
+				assert.Equal(t, "Pass usage limit reached", resp.Reason)
+				assert.False(t, logged.AccessGranted)
+				assert.Equal(t, string(ReasonPassUsageLimit), logged.ReasonCode)
+				assert.Equal(t, int64(1), svc.DeniedReasonCounts()[string(ReasonPassUsageLimit)])
+			}
+			if !tt.expectIncrement {
+				m.passRepo.AssertNotCalled(t, "IncrementUses", mock.Anything, mock.Anything, mock.Anything)
+			}
+			m.assertExpectations(t)
+		})
+	}
+}
+
+// zonedWhitelist отвечает на IsWhitelisted по записям с учетом зон, как запрос к БД
+type zonedWhitelist struct {
+	*mocks.MockWhitelistRepository
+	entries []*domain.WhitelistEntry
+}
+
+func (w *zonedWhitelist) IsWhitelisted(ctx context.Context, licensePlate, zone string) (bool, string, error) {
+	for _, entry := range w.entries {
+		if entry.LicensePlate == licensePlate && entry.IsValid() && domain.ZoneApplies(entry.Zone, zone) {
+			return true, entry.Reason, nil
+		}
+	}
+	return false, "", nil
+}
+
+func TestService_CheckAccess_ZoneWhitelist(t *testing.T) {
+	parking := "parking"
+	whitelist := &zonedWhitelist{entries: []*domain.WhitelistEntry{
+		{LicensePlate: "A123BC777", Reason: "Арендатор парковки", IsActive: true, Zone: &parking},
+		{LicensePlate: "B456CD777", Reason: "Скорая помощь", IsActive: true},
+	}}
+	config := Config{
+		MinConfidence: 0.7,
+		GateZones:     map[string]string{"north": "parking", "office-1": "office"},
+	}
+
+	tests := []struct {
+		name        string
+		plate       string
+		gateID      string
+		expectGrant bool
+	}{
+		{name: "запись зоны действует на воротах зоны", plate: "A123BC777", gateID: "north", expectGrant: true},
+		{name: "запись зоны не действует на воротах другой зоны", plate: "A123BC777", gateID: "office-1"},
+		{name: "запись зоны не действует на воротах без зоны", plate: "A123BC777", gateID: "gate-1"},
+		{name: "запись без зоны действует на воротах зоны", plate: "B456CD777", gateID: "office-1", expectGrant: true},
+		{name: "запись без зоны действует на воротах без зоны", plate: "B456CD777", gateID: "gate-1", expectGrant: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestService(config)
+			svc.whitelistRepo = whitelist
+			m.mlClient.On("RecognizePlate", mock.Anything, "image", 0.7).
+				Return(&ml.RecognitionResult{Success: true, LicensePlate: tt.plate, Confidence: 95}, nil)
+			if !tt.expectGrant {
+				m.blacklistRepo.On("IsBlacklisted", mock.Anything, tt.plate, svc.gateZone(tt.gateID)).Return(false, "", nil)
+				m.vehicleRepo.On("GetByLicensePlate", mock.Anything, tt.plate).Return(nil, domain.ErrVehicleNotFound)
+			}
+			m.accessLogRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.AccessLog")).Return(nil)
+
+			resp, err := svc.CheckAccess(context.Background(), &CheckAccessRequest{ImageBase64: "image", GateID: tt.gateID, Direction: "IN"})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectGrant, resp.AccessGranted)
+			if tt.expectGrant {
+				assert.Equal(t, ReasonWhitelisted, resp.ReasonCode)
+			} else {
+				assert.Equal(t, ReasonVehicleNotRegistered, resp.ReasonCode)
+			}
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestService_CheckAccess_ZonePasses(t *testing.T) {
+	ownerID := uuid.New()
+	vehicle := &domain.Vehicle{ID: uuid.New(), OwnerID: ownerID, LicensePlate: "A123BC777", IsActive: true}
+	owner := &domain.User{ID: ownerID, Role: domain.RoleUser, IsActive: true}
+	parking, office := "parking", "office"
+	newPass := func(zone *string) *domain.Pass {
+		return &domain.Pass{ID: uuid.New(), UserID: ownerID, PassType: domain.PassTypePermanent,
+			ValidFrom: time.Now().Add(-time.Hour), IsActive: true, Zone: zone}
+	}
+
+	tests := []struct {
+		name           string
+		gateID         string
+		passes         []*domain.Pass
+		expectedGrant  bool
+		expectedReason ReasonCode
+	}{
+		{
+			name:           "пропуск зоны на воротах зоны",
+			gateID:         "north",
+			passes:         []*domain.Pass{newPass(&parking)},
+			expectedGrant:  true,
+			expectedReason: ReasonValidPass,
+		},
+		{
+			name:           "пропуск другой зоны",
+			gateID:         "north",
+			passes:         []*domain.Pass{newPass(&office)},
+			expectedReason: ReasonPassWrongZone,
+		},
+		{
+			name:           "пропуск зоны на воротах без зоны",
+			gateID:         "gate-1",
+			passes:         []*domain.Pass{newPass(&parking)},
+			expectedReason: ReasonPassWrongZone,
+		},
+		{
+			name:           "пропуск без зоны действует везде",
+			gateID:         "north",
+			passes:         []*domain.Pass{newPass(&office), newPass(nil)},
+			expectedGrant:  true,
+			expectedReason: ReasonValidPass,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestService(Config{MinConfidence: 0.7, GateZones: map[string]string{"north": "parking"}})
+			zone := svc.gateZone(tt.gateID)
+			m.mlClient.On("RecognizePlate", mock.Anything, "image", 0.7).
+				Return(&ml.RecognitionResult{Success: true, LicensePlate: "A123BC777", Confidence: 95}, nil)
+			m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", zone).Return(false, "", nil)
+			m.blacklistRepo.On("IsBlacklisted", mock.Anything, "A123BC777", zone).Return(false, "", nil)
+			m.vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(vehicle, nil)
+			m.userRepo.On("GetByID", mock.Anything, ownerID).Return(owner, nil)
+			m.passRepo.On("GetActivePassesByUserAndVehicle", mock.Anything, ownerID, vehicle.ID).Return(tt.passes, nil)
+			m.accessLogRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.AccessLog")).Return(nil)
+
+			resp, err := svc.CheckAccess(context.Background(), &CheckAccessRequest{ImageBase64: "image", GateID: tt.gateID, Direction: "IN"})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedGrant, resp.AccessGranted)
+			assert.Equal(t, tt.expectedReason, resp.ReasonCode)
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestService_CheckAccess_NoPassReason(t *testing.T) {
+	ownerID := uuid.New()
+	vehicle := &domain.Vehicle{ID: uuid.New(), OwnerID: ownerID, LicensePlate: "A123BC777", IsActive: true}
+	owner := &domain.User{ID: ownerID, Role: domain.RoleUser, IsActive: true}
+	otherVehiclePass := &domain.Pass{
+		ID:        uuid.New(),
+		UserID:    ownerID,
+		PassType:  domain.PassTypePermanent,
+		ValidFrom: time.Now().Add(-time.Hour),
+		IsActive:  true,
+	}
+
+	tests := []struct {
+		name           string
+		userPasses     []*domain.Pass
+		expectedReason ReasonCode
+	}{
+		{
+			name:           "у владельца нет пропусков",
+			userPasses:     []*domain.Pass{},
+			expectedReason: ReasonNoPass,
+		},
+		{
+			name:           "пропуска владельца не включают автомобиль",
+			userPasses:     []*domain.Pass{otherVehiclePass},
+			expectedReason: ReasonPassNotForVehicle,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestService(Config{MinConfidence: 0.7})
+
+			m.mlClient.On("RecognizePlate", mock.Anything, "image", 0.7).
+				Return(&ml.RecognitionResult{Success: true, LicensePlate: "A123BC777", Confidence: 95}, nil)
+			m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+			m.blacklistRepo.On("IsBlacklisted", mock.Anything, "A123BC777", "").Return(false, "", nil)
+			m.vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(vehicle, nil)
+			m.userRepo.On("GetByID", mock.Anything, ownerID).Return(owner, nil)
+			m.passRepo.On("GetActivePassesByUserAndVehicle", mock.Anything, ownerID, vehicle.ID).
+				Return([]*domain.Pass{}, nil)
+			m.passRepo.On("GetActivePassesByUser", mock.Anything, ownerID).Return(tt.userPasses, nil)
+
+			var logged *domain.AccessLog
+			m.accessLogRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.AccessLog")).
+				Run(func(args mock.Arguments) {
+					logged = args.Get(1).(*domain.AccessLog)
+				}).
+				Return(nil)
+
+			resp, err := svc.CheckAccess(context.Background(), &CheckAccessRequest{ImageBase64: "image", GateID: "gate-1", Direction: "IN"})
+			require.NoError(t, err)
+
+			assert.False(t, resp.AccessGranted)
+			assert.Equal(t, tt.expectedReason, resp.ReasonCode)
+			require.NotNil(t, logged)
+			assert.Equal(t, string(tt.expectedReason), logged.ReasonCode)
+			assert.Equal(t, int64(1), svc.DeniedReasonCounts()[string(tt.expectedReason)])
+
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestService_CheckAccess_DecisionMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m := &serviceMocks{
+		vehicleRepo:   new(mocks.MockVehicleRepository),
+		userRepo:      new(mocks.MockUserRepository),
+		passRepo:      new(mocks.MockPassRepository),
+		accessLogRepo: new(mocks.MockAccessLogRepository),
+		whitelistRepo: new(mocks.MockWhitelistRepository),
+		blacklistRepo: new(mocks.MockBlacklistRepository),
+		mlClient:      new(mockMLClient),
+	}
+	svc := NewService(m.vehicleRepo, m.userRepo, m.passRepo, m.accessLogRepo, m.whitelistRepo, m.blacklistRepo,
+		m.mlClient, nil, nil, nil, nil, metrics.New(registry), logger.NewNoop(),
+		Config{MinConfidence: 0.7, ObserveOnlyGates: []string{"gate-observe"}})
+
+	m.mlClient.On("RecognizePlate", mock.Anything, "whitelisted", 0.7).
+		Return(&ml.RecognitionResult{Success: true, LicensePlate: "A001AA777", Confidence: 95}, nil)
+	m.mlClient.On("RecognizePlate", mock.Anything, "blacklisted", 0.7).
+		Return(&ml.RecognitionResult{Success: true, LicensePlate: "B002BB777", Confidence: 95}, nil)
+	m.mlClient.On("RecognizePlate", mock.Anything, "unreadable", 0.7).
+		Return(&ml.RecognitionResult{Success: false, Error: "no plate"}, nil)
+	m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A001AA777", "").Return(true, "Скорая помощь", nil)
+	m.whitelistRepo.On("IsWhitelisted", mock.Anything, "B002BB777", "").Return(false, "", nil)
+	m.blacklistRepo.On("IsBlacklisted", mock.Anything, "B002BB777", "").Return(true, "stolen", nil)
+	m.accessLogRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.AccessLog")).Return(nil)
+
+	checks := []struct{ image, gate string }{
+		{"whitelisted", "gate-1"},
+		{"whitelisted", "gate-1"},
+		{"blacklisted", "gate-1"},
+		{"unreadable", "gate-1"},
+		{"blacklisted", "gate-observe"}, // Режим наблюдения в метрики не попадает
+	}
+	for _, c := range checks {
+		_, err := svc.CheckAccess(context.Background(), &CheckAccessRequest{ImageBase64: c.image, GateID: c.gate, Direction: "IN"})
+		require.NoError(t, err)
+	}
+
+	expected := `
+# HELP gate_access_decisions_total Решения о доступе по результату и коду причины
+# TYPE gate_access_decisions_total counter
+gate_access_decisions_total{reason="BLACKLISTED",result="denied"} 1
+gate_access_decisions_total{reason="PLATE_NOT_RECOGNIZED",result="denied"} 1
+gate_access_decisions_total{reason="WHITELISTED",result="granted"} 2
+`
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "gate_access_decisions_total"))
+	m.assertExpectations(t)
+}
+
+func TestService_CountAccessLogs(t *testing.T) {
+	vehicleID := uuid.New()
+	from := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	t.Run("фильтр передается в репозиторий", func(t *testing.T) {
+		svc, m := newTestService(Config{})
+		filter := domain.AccessLogFilter{VehicleID: &vehicleID, From: &from, To: &to}
+		m.accessLogRepo.On("Count", mock.Anything, filter).Return(3, nil)
+
+		total, err := svc.CountAccessLogs(context.Background(), filter)
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, total)
+		m.accessLogRepo.AssertExpectations(t)
+	})
+
+	t.Run("from позже to", func(t *testing.T) {
+		svc, m := newTestService(Config{})
+
+		_, err := svc.CountAccessLogs(context.Background(), domain.AccessLogFilter{From: &to, To: &from})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidDateRange)
+		m.accessLogRepo.AssertNotCalled(t, "Count", mock.Anything, mock.Anything)
+	})
+}
+
+// recordingPublisher запоминает опубликованные события
+type recordingPublisher struct {
+	events []*domain.AccessEvent
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event *domain.AccessEvent) {
+	p.events = append(p.events, event)
+}
+
+func TestService_CheckAccess_PublishesEvents(t *testing.T) {
+	m := &serviceMocks{
+		vehicleRepo:   new(mocks.MockVehicleRepository),
+		userRepo:      new(mocks.MockUserRepository),
+		passRepo:      new(mocks.MockPassRepository),
+		accessLogRepo: new(mocks.MockAccessLogRepository),
+		whitelistRepo: new(mocks.MockWhitelistRepository),
+		blacklistRepo: new(mocks.MockBlacklistRepository),
+		mlClient:      new(mockMLClient),
+	}
+	publisher := &recordingPublisher{}
+	svc := NewService(m.vehicleRepo, m.userRepo, m.passRepo, m.accessLogRepo, m.whitelistRepo, m.blacklistRepo,
+		m.mlClient, nil, nil, publisher, nil, nil, logger.NewNoop(),
+		Config{MinConfidence: 0.7, ObserveOnlyGates: []string{"gate-observe"}, MaxFrameAge: time.Minute})
+
+	m.mlClient.On("RecognizePlate", mock.Anything, "whitelisted", 0.7).
+		Return(&ml.RecognitionResult{Success: true, LicensePlate: "A001AA777", Confidence: 95}, nil)
+	m.mlClient.On("RecognizePlate", mock.Anything, "blacklisted", 0.7).
+		Return(&ml.RecognitionResult{Success: true, LicensePlate: "B002BB777", Confidence: 95}, nil)
+	m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A001AA777", "").Return(true, "Скорая помощь", nil)
+	m.whitelistRepo.On("IsWhitelisted", mock.Anything, "B002BB777", "").Return(false, "", nil)
+	m.blacklistRepo.On("IsBlacklisted", mock.Anything, "B002BB777", "").Return(true, "stolen", nil)
+	m.accessLogRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.AccessLog")).Return(nil)
+
+	staleCapture := time.Now().Add(-time.Hour)
+	checks := []*CheckAccessRequest{
+		{ImageBase64: "whitelisted", GateID: "gate-1", Direction: "in"},
+		{ImageBase64: "blacklisted", GateID: "gate-observe", Direction: "OUT"},
+		// Устаревший кадр не пишется в журнал и не публикуется
+		{ImageBase64: "whitelisted", GateID: "gate-1", Direction: "IN", CapturedAt: &staleCapture},
+	}
+	for _, req := range checks {
+		_, err := svc.CheckAccess(context.Background(), req)
+		require.NoError(t, err)
+	}
+
+	require.Len(t, publisher.events, 2)
+
+	granted := publisher.events[0]
+	assert.Equal(t, "A001AA777", granted.LicensePlate)
+	assert.True(t, granted.AccessGranted)
+	assert.Equal(t, string(ReasonWhitelisted), granted.ReasonCode)
+	assert.Equal(t, "gate-1", granted.GateID)
+	assert.Equal(t, domain.DirectionIn, granted.Direction)
+	assert.False(t, granted.Timestamp.IsZero())
+
+	observed := publisher.events[1]
+	assert.Equal(t, "B002BB777", observed.LicensePlate)
+	assert.False(t, observed.AccessGranted)
+	assert.Equal(t, string(ReasonBlacklisted), observed.ReasonCode)
+	assert.True(t, observed.Observed)
+	assert.Equal(t, domain.DirectionOut, observed.Direction)
+}
+
+func TestEventPublishers_Publish(t *testing.T) {
+	first, second := &recordingPublisher{}, &recordingPublisher{}
+	event := &domain.AccessEvent{LicensePlate: "A001AA777", AccessGranted: true}
+
+	EventPublishers{first, second}.Publish(context.Background(), event)
+	EventPublishers(nil).Publish(context.Background(), event)
+
+	assert.Equal(t, []*domain.AccessEvent{event}, first.events)
+	assert.Equal(t, []*domain.AccessEvent{event}, second.events)
+}
+
+// fakeOccupancy - in-memory счетчик заполненности по зонам
+type fakeOccupancy map[string]int64
+
+func (c fakeOccupancy) Increment(ctx context.Context, scope string) (int64, error) {
+	c[scope]++
+	return c[scope], nil
+}
+
+func (c fakeOccupancy) Decrement(ctx context.Context, scope string) (int64, error) {
+	if c[scope] > 0 {
+		c[scope]--
+	}
+	return c[scope], nil
+}
+
+func (c fakeOccupancy) Get(ctx context.Context, scope string) (int64, error) {
+	return c[scope], nil
+}
+
+func (c fakeOccupancy) All(ctx context.Context) (map[string]int64, error) {
+	return c, nil
+}
+
+func TestService_CheckAccess_Occupancy(t *testing.T) {
+	ownerID := uuid.New()
+	vehicle := &domain.Vehicle{ID: uuid.New(), OwnerID: ownerID, LicensePlate: "A123BC777", IsActive: true}
+	owner := &domain.User{ID: ownerID, Role: domain.RoleUser, IsActive: true}
+	validPass := &domain.Pass{ID: uuid.New(), UserID: ownerID, PassType: domain.PassTypePermanent,
+		ValidFrom: time.Now().Add(-time.Hour), IsActive: true}
+
+	tests := []struct {
+		name           string
+		gateID         string
+		direction      string
+		whitelisted    bool
+		blacklisted    bool
+		counts         fakeOccupancy
+		expectedGrant  bool
+		expectedReason ReasonCode
+		expectedCounts fakeOccupancy
+	}{
+		{
+			name:           "въезд увеличивает счетчик зоны",
+			gateID:         "north",
+			direction:      "IN",
+			counts:         fakeOccupancy{"parking": 1},
+			expectedGrant:  true,
+			expectedReason: ReasonValidPass,
+			expectedCounts: fakeOccupancy{"parking": 2},
+		},
+		{
+			name:           "выезд уменьшает счетчик зоны",
+			gateID:         "north",
+			direction:      "OUT",
+			counts:         fakeOccupancy{"parking": 1},
+			expectedGrant:  true,
+			expectedReason: ReasonValidPass,
+			expectedCounts: fakeOccupancy{"parking": 0},
+		},
+		{
+			name:           "выезд не опускает счетчик ниже нуля",
+			gateID:         "north",
+			direction:      "OUT",
+			counts:         fakeOccupancy{},
+			expectedGrant:  true,
+			expectedReason: ReasonValidPass,
+			expectedCounts: fakeOccupancy{},
+		},
+		{
+			name:           "ворота без зоны считаются отдельно",
+			gateID:         "gate-1",
+			direction:      "IN",
+			counts:         fakeOccupancy{"parking": 3},
+			expectedGrant:  true,
+			expectedReason: ReasonValidPass,
+			expectedCounts: fakeOccupancy{"parking": 3, "gate-1": 1},
+		},
+		{
+			name:           "въезд в заполненную зону",
+			gateID:         "north",
+			direction:      "IN",
+			counts:         fakeOccupancy{"parking": 3},
+			expectedReason: ReasonAtCapacity,
+			expectedCounts: fakeOccupancy{"parking": 3},
+		},
+		{
+			name:           "выезд из заполненной зоны",
+			gateID:         "north",
+			direction:      "OUT",
+			counts:         fakeOccupancy{"parking": 3},
+			expectedGrant:  true,
+			expectedReason: ReasonValidPass,
+			expectedCounts: fakeOccupancy{"parking": 2},
+		},
+		{
+			name:           "белый список не ограничивается вместимостью",
+			gateID:         "north",
+			direction:      "IN",
+			whitelisted:    true,
+			counts:         fakeOccupancy{"parking": 3},
+			expectedGrant:  true,
+			expectedReason: ReasonWhitelisted,
+			expectedCounts: fakeOccupancy{"parking": 4},
+		},
+		{
+			name:           "отказ не меняет счетчик",
+			gateID:         "north",
+			direction:      "IN",
+			blacklisted:    true,
+			counts:         fakeOccupancy{"parking": 1},
+			expectedReason: ReasonBlacklisted,
+			expectedCounts: fakeOccupancy{"parking": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestService(Config{
+				MinConfidence: 0.7,
+				GateZones:     map[string]string{"north": "parking"},
+				MaxOccupancy:  3,
+			})
+			svc.occupancy = tt.counts
+			zone := svc.gateZone(tt.gateID)
+
+			m.mlClient.On("RecognizePlate", mock.Anything, "image", 0.7).
+				Return(&ml.RecognitionResult{Success: true, LicensePlate: "A123BC777", Confidence: 95}, nil)
+			m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", zone).Return(tt.whitelisted, "ambulance", nil)
+			if !tt.whitelisted {
+				m.blacklistRepo.On("IsBlacklisted", mock.Anything, "A123BC777", zone).Return(tt.blacklisted, "stolen", nil)
+			}
+			if !tt.whitelisted && !tt.blacklisted {
+				m.vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(vehicle, nil)
+				m.userRepo.On("GetByID", mock.Anything, ownerID).Return(owner, nil)
+				m.passRepo.On("GetActivePassesByUserAndVehicle", mock.Anything, ownerID, vehicle.ID).
+					Return([]*domain.Pass{validPass}, nil)
+			}
+			m.accessLogRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.AccessLog")).Return(nil)
+
+			resp, err := svc.CheckAccess(context.Background(), &CheckAccessRequest{ImageBase64: "image", GateID: tt.gateID, Direction: tt.direction})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedGrant, resp.AccessGranted)
+			assert.Equal(t, tt.expectedReason, resp.ReasonCode)
+			if tt.expectedReason == ReasonAtCapacity {
+				assert.Equal(t, "Facility at capacity", resp.Reason)
+				assert.Nil(t, resp.Pass)
+			}
+			assert.Equal(t, tt.expectedCounts, tt.counts)
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestService_CheckAccess_OccupancyObserveOnlyGate(t *testing.T) {
+	svc, m := newTestService(Config{MinConfidence: 0.7, ObserveOnlyGates: []string{"gate-1"}})
+	counts := fakeOccupancy{}
+	svc.occupancy = counts
+
+	m.mlClient.On("RecognizePlate", mock.Anything, "image", 0.7).
+		Return(&ml.RecognitionResult{Success: true, LicensePlate: "A123BC777", Confidence: 95}, nil)
+	m.whitelistRepo.On("IsWhitelisted", mock.Anything, "A123BC777", "").Return(true, "ambulance", nil)
+	m.accessLogRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.AccessLog")).Return(nil)
+
+	_, err := svc.CheckAccess(context.Background(), &CheckAccessRequest{ImageBase64: "image", GateID: "gate-1", Direction: "IN"})
+	require.NoError(t, err)
+
+	assert.Empty(t, counts)
+	m.assertExpectations(t)
+}
+
+func TestService_Occupancy(t *testing.T) {
+	t.Run("по зонам", func(t *testing.T) {
+		svc, _ := newTestService(Config{MaxOccupancy: 3})
+		svc.occupancy = fakeOccupancy{"parking": 3, "gate-1": 1}
+
+		resp, err := svc.Occupancy(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(4), resp.Total)
+		assert.Equal(t, 3, resp.Capacity)
+		assert.Equal(t, []ScopeOccupancy{
+			{Scope: "gate-1", Count: 1},
+			{Scope: "parking", Count: 3, Full: true},
+		}, resp.Scopes)
+	})
+
+	t.Run("учет отключен", func(t *testing.T) {
+		svc, _ := newTestService(Config{})
+
+		_, err := svc.Occupancy(context.Background())
+		assert.ErrorIs(t, err, domain.ErrOccupancyUnavailable)
+	})
+}
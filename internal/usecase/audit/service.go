@@ -0,0 +1,69 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/repository"
+	"github.com/google/uuid"
+)
+
+// Service ведет журнал аудита действий над объектами системы
+type Service struct {
+	auditRepo repository.AuditLogRepository
+	logger    logger.Logger
+}
+
+// NewService создает новый экземпляр AuditService
+func NewService(auditRepo repository.AuditLogRepository, logger logger.Logger) *Service {
+	return &Service{
+		auditRepo: auditRepo,
+		logger:    logger,
+	}
+}
+
+// Record записывает действие в журнал аудита
+// Ошибка записи не прерывает основное действие - она только логируется
+func (s *Service) Record(ctx context.Context, actorID *uuid.UUID, action string, targetType domain.AuditTargetType, targetID *uuid.UUID, details map[string]interface{}) {
+	entry := &domain.AuditLog{
+		ActorID:    actorID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Details:    details,
+	}
+
+	if err := entry.Validate(); err != nil {
+		s.logger.Error("Invalid audit log entry", map[string]interface{}{
+			"action":      action,
+			"target_type": targetType,
+			"error":       err.Error(),
+		})
+		return
+	}
+
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		s.logger.Error("Failed to write audit log", map[string]interface{}{
+			"action":      action,
+			"target_type": targetType,
+			"target_id":   targetID,
+			"error":       err.Error(),
+		})
+	}
+}
+
+// SearchAuditLogs возвращает записи журнала аудита по фильтру
+func (s *Service) SearchAuditLogs(ctx context.Context, filter domain.AuditLogFilter, limit, offset int) ([]*domain.AuditLog, error) {
+	if filter.TargetType != "" {
+		if _, err := domain.ParseAuditTargetType(string(filter.TargetType)); err != nil {
+			return nil, err
+		}
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, domain.ErrInvalidDateRange
+	}
+
+	return s.auditRepo.Search(ctx, filter, limit, offset)
+}
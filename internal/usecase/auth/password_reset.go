@@ -0,0 +1,200 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/hash"
+	"github.com/frontandrew/gate/internal/pkg/jwt"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/google/uuid"
+	redisv9 "github.com/redis/go-redis/v9"
+)
+
+// Префиксы ключей сброса пароля в Redis
+// По хешу токена хранится ID пользователя, по ID пользователя - хеш его последнего токена
+const (
+	passwordResetPrefix     = "password_reset:"
+	passwordResetUserPrefix = "password_reset_user:"
+)
+
+// ForgotPasswordRequest - запрос на сброс пароля
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordRequest - установка нового пароля по токену сброса
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,min=8"`
+}
+
+// Notifier доставляет пользователю токен сброса пароля (например, по email)
+type Notifier interface {
+	SendPasswordReset(ctx context.Context, user *domain.User, token string) error
+}
+
+// LogNotifier - заглушка Notifier: только пишет в лог факт отправки (без самого токена)
+type LogNotifier struct {
+	logger logger.Logger
+}
+
+// NewLogNotifier создает новый экземпляр LogNotifier
+func NewLogNotifier(logger logger.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+// SendPasswordReset пишет в лог, кому был бы отправлен токен
+func (n *LogNotifier) SendPasswordReset(ctx context.Context, user *domain.User, token string) error {
+	n.logger.Info("Password reset notification", map[string]interface{}{
+		"user_id": user.ID,
+		"email":   user.Email,
+	})
+	return nil
+}
+
+// ForgotPassword выдает одноразовый токен сброса пароля и отправляет его пользователю
+// Для несуществующего или неактивного email ошибка не возвращается, чтобы не раскрывать,
+// зарегистрирован ли адрес
+func (s *Service) ForgotPassword(ctx context.Context, req *ForgotPasswordRequest) error {
+	if s.redisClient == nil {
+		return domain.ErrPasswordResetUnavailable
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			s.logger.Info("Password reset requested for unknown email", map[string]interface{}{
+				"email": req.Email,
+			})
+			return nil
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if !user.IsActive {
+		s.logger.Warn("Password reset requested for inactive user", map[string]interface{}{
+			"user_id": user.ID,
+		})
+		return nil
+	}
+
+	token, err := generateResetToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate reset token: %w", err)
+	}
+	tokenHash := jwt.HashToken(token)
+
+	// Действует только последний выданный токен
+	userKey := passwordResetUserPrefix + user.ID.String()
+	if previous, err := s.redisClient.Get(ctx, userKey); err == nil {
+		if err := s.redisClient.Del(ctx, passwordResetPrefix+previous); err != nil {
+			return fmt.Errorf("failed to revoke previous reset token: %w", err)
+		}
+	} else if err != redisv9.Nil {
+		return fmt.Errorf("failed to get previous reset token: %w", err)
+	}
+
+	if err := s.redisClient.Set(ctx, passwordResetPrefix+tokenHash, user.ID.String(), s.config.PasswordResetTTL); err != nil {
+		return fmt.Errorf("failed to store reset token: %w", err)
+	}
+	if err := s.redisClient.Set(ctx, userKey, tokenHash, s.config.PasswordResetTTL); err != nil {
+		return fmt.Errorf("failed to store reset token: %w", err)
+	}
+
+	if err := s.notifier.SendPasswordReset(ctx, user, token); err != nil {
+		s.logger.Error("Failed to send password reset", map[string]interface{}{
+			"user_id": user.ID,
+			"error":   err.Error(),
+		})
+		return fmt.Errorf("failed to send password reset: %w", err)
+	}
+
+	s.logger.Info("Password reset token issued", map[string]interface{}{
+		"user_id": user.ID,
+	})
+
+	return nil
+}
+
+// ResetPassword устанавливает новый пароль по токену сброса
+// Токен одноразовый: он удаляется при первом предъявлении. Все сессии пользователя завершаются
+func (s *Service) ResetPassword(ctx context.Context, req *ResetPasswordRequest) error {
+	if s.redisClient == nil {
+		return domain.ErrPasswordResetUnavailable
+	}
+
+	// Слабый пароль проверяем до погашения токена, чтобы его можно было отправить повторно
+	if utf8.RuneCountInString(req.NewPassword) < domain.MinPasswordLength {
+		return domain.ErrWeakPassword
+	}
+
+	value, err := s.redisClient.GetDel(ctx, passwordResetPrefix+jwt.HashToken(req.Token))
+	if err != nil {
+		if err == redisv9.Nil {
+			return domain.ErrInvalidResetToken
+		}
+		return fmt.Errorf("failed to get reset token: %w", err)
+	}
+
+	userID, err := uuid.Parse(value)
+	if err != nil {
+		return domain.ErrInvalidResetToken
+	}
+
+	if err := s.redisClient.Del(ctx, passwordResetUserPrefix+userID.String()); err != nil {
+		s.logger.Error("Failed to delete password reset user key", map[string]interface{}{
+			"user_id": userID,
+			"error":   err.Error(),
+		})
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return domain.ErrInvalidResetToken
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if !user.IsActive {
+		return domain.ErrUserInactive
+	}
+
+	passwordHash, err := hash.HashPassword(req.NewPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user.PasswordHash = passwordHash
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	if err := s.refreshTokenRepo.RevokeAllUserTokens(ctx, userID); err != nil {
+		s.logger.Error("Failed to revoke user tokens after password reset", map[string]interface{}{
+			"user_id": userID,
+			"error":   err.Error(),
+		})
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	s.logger.Info("Password reset successfully", map[string]interface{}{
+		"user_id": userID,
+	})
+
+	return nil
+}
+
+// generateResetToken возвращает случайный токен сброса (256 бит в hex)
+func generateResetToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/hash"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/repository/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// captureNotifier запоминает последний отправленный токен сброса
+type captureNotifier struct {
+	token string
+	sent  int
+}
+
+func (n *captureNotifier) SendPasswordReset(ctx context.Context, user *domain.User, token string) error {
+	n.token = token
+	n.sent++
+	return nil
+}
+
+func TestService_PasswordReset(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Email: "test@example.com", Role: domain.RoleUser, IsActive: true}
+	config := Config{PasswordResetTTL: 30 * time.Minute}
+
+	type env struct {
+		svc              *Service
+		userRepo         *mocks.MockUserRepository
+		refreshTokenRepo *mocks.MockRefreshTokenRepository
+		notifier         *captureNotifier
+	}
+
+	newEnv := func(t *testing.T) (*env, func(time.Duration)) {
+		client, mr := newTestRedis(t)
+		e := &env{
+			userRepo:         new(mocks.MockUserRepository),
+			refreshTokenRepo: new(mocks.MockRefreshTokenRepository),
+			notifier:         &captureNotifier{},
+		}
+		e.svc = NewService(e.userRepo, e.refreshTokenRepo, nil, client, e.notifier, logger.NewNoop(), config)
+		e.userRepo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+		return e, mr.FastForward
+	}
+
+	expectReset := func(e *env) {
+		e.userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil).Once()
+		e.userRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+			return hash.CheckPassword(u.PasswordHash, "new-password")
+		})).Return(nil).Once()
+		e.refreshTokenRepo.On("RevokeAllUserTokens", mock.Anything, user.ID).Return(nil).Once()
+	}
+
+	t.Run("валидный токен меняет пароль и завершает сессии", func(t *testing.T) {
+		e, _ := newEnv(t)
+		expectReset(e)
+
+		require.NoError(t, e.svc.ForgotPassword(context.Background(), &ForgotPasswordRequest{Email: user.Email}))
+		require.Equal(t, 1, e.notifier.sent)
+
+		err := e.svc.ResetPassword(context.Background(), &ResetPasswordRequest{Token: e.notifier.token, NewPassword: "new-password"})
+		require.NoError(t, err)
+		e.userRepo.AssertExpectations(t)
+		e.refreshTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("истекший токен отклоняется", func(t *testing.T) {
+		e, fastForward := newEnv(t)
+
+		require.NoError(t, e.svc.ForgotPassword(context.Background(), &ForgotPasswordRequest{Email: user.Email}))
+		fastForward(config.PasswordResetTTL + time.Second)
+
+		err := e.svc.ResetPassword(context.Background(), &ResetPasswordRequest{Token: e.notifier.token, NewPassword: "new-password"})
+		assert.ErrorIs(t, err, domain.ErrInvalidResetToken)
+		e.userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("повторное использование токена отклоняется", func(t *testing.T) {
+		e, _ := newEnv(t)
+		expectReset(e)
+
+		require.NoError(t, e.svc.ForgotPassword(context.Background(), &ForgotPasswordRequest{Email: user.Email}))
+		req := &ResetPasswordRequest{Token: e.notifier.token, NewPassword: "new-password"}
+		require.NoError(t, e.svc.ResetPassword(context.Background(), req))
+
+		err := e.svc.ResetPassword(context.Background(), req)
+		assert.ErrorIs(t, err, domain.ErrInvalidResetToken)
+		e.userRepo.AssertNumberOfCalls(t, "Update", 1)
+	})
+
+	t.Run("новый запрос отменяет предыдущий токен", func(t *testing.T) {
+		e, _ := newEnv(t)
+
+		require.NoError(t, e.svc.ForgotPassword(context.Background(), &ForgotPasswordRequest{Email: user.Email}))
+		first := e.notifier.token
+		require.NoError(t, e.svc.ForgotPassword(context.Background(), &ForgotPasswordRequest{Email: user.Email}))
+
+		err := e.svc.ResetPassword(context.Background(), &ResetPasswordRequest{Token: first, NewPassword: "new-password"})
+		assert.ErrorIs(t, err, domain.ErrInvalidResetToken)
+	})
+
+	t.Run("неизвестный email не раскрывается", func(t *testing.T) {
+		e, _ := newEnv(t)
+		e.userRepo.On("GetByEmail", mock.Anything, "unknown@example.com").Return(nil, domain.ErrUserNotFound)
+
+		err := e.svc.ForgotPassword(context.Background(), &ForgotPasswordRequest{Email: "unknown@example.com"})
+		assert.NoError(t, err)
+		assert.Zero(t, e.notifier.sent)
+	})
+
+	t.Run("слабый пароль не гасит токен", func(t *testing.T) {
+		e, _ := newEnv(t)
+		expectReset(e)
+
+		require.NoError(t, e.svc.ForgotPassword(context.Background(), &ForgotPasswordRequest{Email: user.Email}))
+
+		err := e.svc.ResetPassword(context.Background(), &ResetPasswordRequest{Token: e.notifier.token, NewPassword: "short"})
+		assert.ErrorIs(t, err, domain.ErrWeakPassword)
+
+		err = e.svc.ResetPassword(context.Background(), &ResetPasswordRequest{Token: e.notifier.token, NewPassword: "new-password"})
+		assert.NoError(t, err)
+	})
+}
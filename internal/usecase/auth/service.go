@@ -3,14 +3,19 @@ package auth
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/frontandrew/gate/internal/domain"
 	"github.com/frontandrew/gate/internal/pkg/hash"
 	"github.com/frontandrew/gate/internal/pkg/jwt"
 	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/pkg/redis"
 	"github.com/frontandrew/gate/internal/repository"
 	"github.com/google/uuid"
+	redisv9 "github.com/redis/go-redis/v9"
 )
 
 // RegisterRequest - запрос на регистрацию
@@ -36,12 +41,31 @@ type LoginResponse struct {
 	ExpiresAt    string       `json:"expires_at"`
 }
 
+// Config содержит настройки аутентификации и регистрации
+type Config struct {
+	PhoneDefaultRegion string // Регион для номеров без кода страны (ISO 3166-1, например RU)
+	SMSEnabled         bool   // Телефон используется для SMS: некорректный номер отклоняется
+
+	DisableRegistration bool // Самостоятельная регистрация закрыта (пользователей заводит администратор)
+
+	MaxLoginAttempts int           // Неудачных попыток входа до блокировки (0 - без ограничения)
+	LockoutDuration  time.Duration // Окно подсчета неудачных попыток и длительность блокировки
+
+	PasswordResetTTL time.Duration // Срок действия токена сброса пароля
+}
+
+// loginFailPrefix - префикс ключей счетчиков неудачных входов в Redis
+const loginFailPrefix = "login_fail:"
+
 // Service содержит бизнес-логику аутентификации
 type Service struct {
 	userRepo         repository.UserRepository
 	refreshTokenRepo repository.RefreshTokenRepository
 	tokenService     *jwt.TokenService
+	redisClient      *redis.Client // nil - счетчик неудачных входов и сброс пароля отключены
+	notifier         Notifier
 	logger           logger.Logger
+	config           Config
 }
 
 // NewService создает новый экземпляр AuthService
@@ -49,13 +73,19 @@ func NewService(
 	userRepo repository.UserRepository,
 	refreshTokenRepo repository.RefreshTokenRepository,
 	tokenService *jwt.TokenService,
+	redisClient *redis.Client,
+	notifier Notifier,
 	logger logger.Logger,
+	config Config,
 ) *Service {
 	return &Service{
 		userRepo:         userRepo,
 		refreshTokenRepo: refreshTokenRepo,
 		tokenService:     tokenService,
+		redisClient:      redisClient,
+		notifier:         notifier,
 		logger:           logger,
+		config:           config,
 	}
 }
 
@@ -65,6 +95,10 @@ func (s *Service) Register(ctx context.Context, req *RegisterRequest) (*domain.U
 		"email": req.Email,
 	})
 
+	if s.config.DisableRegistration {
+		return nil, domain.ErrRegistrationClosed
+	}
+
 	// Проверяем, что пользователь с таким email еще не существует
 	existingUser, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil && err != domain.ErrUserNotFound {
@@ -104,8 +138,16 @@ func (s *Service) Register(ctx context.Context, req *RegisterRequest) (*domain.U
 		return nil, err
 	}
 
+	// Телефон храним в E.164; для SMS некорректный номер недопустим
+	if err := user.NormalizePhone(s.config.PhoneDefaultRegion, s.config.SMSEnabled); err != nil {
+		return nil, err
+	}
+
 	// Сохраняем в БД
 	if err := s.userRepo.Create(ctx, user); err != nil {
+		if err == domain.ErrUserAlreadyExists {
+			return nil, err
+		}
 		s.logger.Error("Failed to create user", map[string]interface{}{
 			"error": err.Error(),
 		})
@@ -129,6 +171,14 @@ func (s *Service) Login(ctx context.Context, req *LoginRequest) (*LoginResponse,
 		"email": req.Email,
 	})
 
+	// Защита от перебора: после MaxLoginAttempts неудач вход блокируется на LockoutDuration
+	if s.isLoginLocked(ctx, req.Email) {
+		s.logger.Warn("Login failed: account locked", map[string]interface{}{
+			"email": req.Email,
+		})
+		return nil, domain.ErrAccountLocked
+	}
+
 	// Находим пользователя по email
 	user, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
@@ -136,6 +186,7 @@ func (s *Service) Login(ctx context.Context, req *LoginRequest) (*LoginResponse,
 			s.logger.Warn("Login failed: user not found", map[string]interface{}{
 				"email": req.Email,
 			})
+			s.recordLoginFailure(ctx, req.Email)
 			return nil, domain.ErrInvalidCredentials
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
@@ -154,9 +205,12 @@ func (s *Service) Login(ctx context.Context, req *LoginRequest) (*LoginResponse,
 		s.logger.Warn("Login failed: invalid password", map[string]interface{}{
 			"user_id": user.ID,
 		})
+		s.recordLoginFailure(ctx, req.Email)
 		return nil, domain.ErrInvalidCredentials
 	}
 
+	s.resetLoginFailures(ctx, req.Email)
+
 	// Генерируем JWT токены
 	tokenPair, err := s.tokenService.GenerateTokenPair(user)
 	if err != nil {
@@ -173,20 +227,9 @@ func (s *Service) Login(ctx context.Context, req *LoginRequest) (*LoginResponse,
 		})
 	}
 
-	// Сохраняем refresh token в БД
-	refreshTokenHash := jwt.HashToken(tokenPair.RefreshToken)
-	refreshTokenModel := &domain.RefreshToken{
-		UserID:    user.ID,
-		TokenHash: refreshTokenHash,
-		ExpiresAt: tokenPair.ExpiresAt.Add(7 * 24 * time.Hour), // Refresh token живет 7 дней
-		CreatedAt: time.Now(),
-	}
-
-	if err := s.refreshTokenRepo.Create(ctx, refreshTokenModel); err != nil {
-		s.logger.Error("Failed to save refresh token", map[string]interface{}{
-			"error": err.Error(),
-		})
-		// Не возвращаем ошибку, так как токены уже сгенерированы
+	// Сохраняем refresh token в БД: без записи его нельзя будет обменять
+	if err := s.storeRefreshToken(ctx, user.ID, tokenPair); err != nil {
+		return nil, err
 	}
 
 	s.logger.Info("User logged in successfully", map[string]interface{}{
@@ -204,6 +247,88 @@ func (s *Service) Login(ctx context.Context, req *LoginRequest) (*LoginResponse,
 	}, nil
 }
 
+// loginFailKey возвращает ключ счетчика неудачных входов (email без учета регистра)
+func loginFailKey(email string) string {
+	return loginFailPrefix + strings.ToLower(strings.TrimSpace(email))
+}
+
+// loginAttemptsEnabled проверяет, включен ли счетчик неудачных входов
+func (s *Service) loginAttemptsEnabled() bool {
+	return s.redisClient != nil && s.config.MaxLoginAttempts > 0
+}
+
+// isLoginLocked проверяет, исчерпан ли лимит неудачных входов
+// При недоступности Redis вход не блокируется
+func (s *Service) isLoginLocked(ctx context.Context, email string) bool {
+	if !s.loginAttemptsEnabled() {
+		return false
+	}
+
+	value, err := s.redisClient.Get(ctx, loginFailKey(email))
+	if err != nil {
+		if err != redisv9.Nil {
+			s.logger.Error("Failed to read login failure counter", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+		return false
+	}
+
+	failures, err := strconv.Atoi(value)
+	if err != nil {
+		return false
+	}
+
+	return failures >= s.config.MaxLoginAttempts
+}
+
+// recordLoginFailure увеличивает счетчик неудачных входов
+// TTL выставляется при первой неудаче и продлевается при достижении лимита,
+// чтобы блокировка длилась полный LockoutDuration
+func (s *Service) recordLoginFailure(ctx context.Context, email string) {
+	if !s.loginAttemptsEnabled() {
+		return
+	}
+
+	key := loginFailKey(email)
+	failures, err := s.redisClient.Incr(ctx, key)
+	if err != nil {
+		s.logger.Error("Failed to increment login failure counter", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if failures == 1 || failures == int64(s.config.MaxLoginAttempts) {
+		if err := s.redisClient.Expire(ctx, key, s.config.LockoutDuration); err != nil {
+			s.logger.Error("Failed to set login failure window", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	if failures == int64(s.config.MaxLoginAttempts) {
+		s.logger.Warn("Account locked after failed login attempts", map[string]interface{}{
+			"email":    email,
+			"failures": failures,
+			"duration": s.config.LockoutDuration.String(),
+		})
+	}
+}
+
+// resetLoginFailures сбрасывает счетчик после успешного входа
+func (s *Service) resetLoginFailures(ctx context.Context, email string) {
+	if !s.loginAttemptsEnabled() {
+		return
+	}
+
+	if err := s.redisClient.Del(ctx, loginFailKey(email)); err != nil {
+		s.logger.Error("Failed to reset login failure counter", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
+
 // GetUserByID возвращает пользователя по ID
 func (s *Service) GetUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
 	user, err := s.userRepo.GetByID(ctx, id)
@@ -227,21 +352,40 @@ type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" validate:"required"`
 }
 
-// RefreshToken обновляет access token используя refresh token
+// RefreshToken обменивает refresh token на новую пару токенов
+// Старый токен отзывается (ротация). Повторное предъявление уже отозванного токена
+// считается утечкой: отзываются все токены пользователя
 func (s *Service) RefreshToken(ctx context.Context, req *RefreshTokenRequest) (*LoginResponse, error) {
 	s.logger.Info("Token refresh attempt")
 
-	// Извлекаем claims из refresh token
-	claims, err := s.tokenService.ExtractClaims(req.RefreshToken)
-	if err != nil {
+	// Проверяем подпись refresh token
+	if _, err := s.tokenService.ExtractClaims(req.RefreshToken); err != nil {
 		s.logger.Warn("Failed to extract claims from refresh token", map[string]interface{}{
 			"error": err.Error(),
 		})
 		return nil, domain.ErrInvalidToken
 	}
 
+	// Ищем токен в БД по хешу
+	tokenHash := jwt.HashToken(req.RefreshToken)
+	stored, err := s.refreshTokenRepo.GetByTokenHash(ctx, tokenHash)
+	if err != nil {
+		if err == domain.ErrRefreshTokenNotFound {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	if stored.RevokedAt != nil {
+		return nil, s.handleTokenReuse(ctx, stored.UserID)
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, domain.ErrTokenExpired
+	}
+
 	// Получаем актуальные данные пользователя
-	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	user, err := s.userRepo.GetByID(ctx, stored.UserID)
 	if err != nil {
 		if err == domain.ErrUserNotFound {
 			return nil, domain.ErrUserNotFound
@@ -257,6 +401,14 @@ func (s *Service) RefreshToken(ctx context.Context, req *RefreshTokenRequest) (*
 		return nil, domain.ErrUserInactive
 	}
 
+	// Отзываем старый токен; если его успел отозвать параллельный запрос - это повторное использование
+	if err := s.refreshTokenRepo.Revoke(ctx, tokenHash); err != nil {
+		if err == domain.ErrRefreshTokenNotFound {
+			return nil, s.handleTokenReuse(ctx, stored.UserID)
+		}
+		return nil, fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+
 	// Генерируем новую пару токенов
 	tokenPair, err := s.tokenService.GenerateTokenPair(user)
 	if err != nil {
@@ -266,20 +418,8 @@ func (s *Service) RefreshToken(ctx context.Context, req *RefreshTokenRequest) (*
 		return nil, fmt.Errorf("failed to generate tokens: %w", err)
 	}
 
-	// Сохраняем новый refresh token в БД
-	refreshTokenHash := jwt.HashToken(tokenPair.RefreshToken)
-	refreshTokenModel := &domain.RefreshToken{
-		UserID:    user.ID,
-		TokenHash: refreshTokenHash,
-		ExpiresAt: tokenPair.ExpiresAt.Add(7 * 24 * time.Hour), // Refresh token живет 7 дней
-		CreatedAt: time.Now(),
-	}
-
-	if err := s.refreshTokenRepo.Create(ctx, refreshTokenModel); err != nil {
-		s.logger.Error("Failed to save refresh token", map[string]interface{}{
-			"error": err.Error(),
-		})
-		// Не возвращаем ошибку, так как токены уже сгенерированы
+	if err := s.storeRefreshToken(ctx, user.ID, tokenPair); err != nil {
+		return nil, err
 	}
 
 	s.logger.Info("Token refreshed successfully", map[string]interface{}{
@@ -297,9 +437,47 @@ func (s *Service) RefreshToken(ctx context.Context, req *RefreshTokenRequest) (*
 	}, nil
 }
 
+// handleTokenReuse отзывает все токены пользователя при повторном использовании refresh token
+// Клиенту возвращается обычная ошибка невалидного токена (401)
+func (s *Service) handleTokenReuse(ctx context.Context, userID uuid.UUID) error {
+	s.logger.Warn("Revoked refresh token reused, revoking all user tokens", map[string]interface{}{
+		"user_id": userID,
+	})
+
+	if err := s.refreshTokenRepo.RevokeAllUserTokens(ctx, userID); err != nil {
+		s.logger.Error("Failed to revoke user tokens", map[string]interface{}{
+			"user_id": userID,
+			"error":   err.Error(),
+		})
+	}
+
+	return domain.ErrInvalidToken
+}
+
+// storeRefreshToken сохраняет хеш refresh token из пары
+func (s *Service) storeRefreshToken(ctx context.Context, userID uuid.UUID, tokenPair *jwt.TokenPair) error {
+	refreshToken := &domain.RefreshToken{
+		UserID:    userID,
+		TokenHash: jwt.HashToken(tokenPair.RefreshToken),
+		ExpiresAt: tokenPair.RefreshExpiresAt,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.refreshTokenRepo.Create(ctx, refreshToken); err != nil {
+		s.logger.Error("Failed to save refresh token", map[string]interface{}{
+			"user_id": userID,
+			"error":   err.Error(),
+		})
+		return fmt.Errorf("failed to save refresh token: %w", err)
+	}
+
+	return nil
+}
+
 // LogoutRequest - запрос на выход
 type LogoutRequest struct {
 	RefreshToken string `json:"refresh_token" validate:"required"`
+	AccessToken  string `json:"-"` // Из заголовка Authorization, если передан: отзывается до истечения
 }
 
 // Logout отзывает refresh token и завершает сессию
@@ -317,6 +495,222 @@ func (s *Service) Logout(ctx context.Context, req *LogoutRequest) error {
 		return domain.ErrInvalidToken
 	}
 
+	// Access token без отзыва действовал бы до истечения срока
+	if req.AccessToken != "" {
+		if claims, err := s.tokenService.ValidateToken(req.AccessToken); err == nil {
+			if err := s.RevokeAccessToken(ctx, claims.ID, time.Until(claims.ExpiresAt.Time)); err != nil {
+				s.logger.Error("Failed to revoke access token on logout", map[string]interface{}{
+					"user_id": claims.UserID,
+					"error":   err.Error(),
+				})
+			}
+		}
+	}
+
 	s.logger.Info("User logged out successfully")
 	return nil
 }
+
+// ChangePasswordRequest - запрос на смену пароля
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,min=8"`
+}
+
+// ChangePassword меняет пароль пользователя после проверки текущего
+// Все refresh токены отзываются: остальные сессии должны войти заново
+func (s *Service) ChangePassword(ctx context.Context, userID uuid.UUID, req *ChangePasswordRequest) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return domain.ErrUserNotFound
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if !hash.CheckPassword(user.PasswordHash, req.OldPassword) {
+		s.logger.Warn("Password change failed: invalid old password", map[string]interface{}{
+			"user_id": userID,
+		})
+		return domain.ErrInvalidPassword
+	}
+
+	if utf8.RuneCountInString(req.NewPassword) < domain.MinPasswordLength {
+		return domain.ErrWeakPassword
+	}
+
+	passwordHash, err := hash.HashPassword(req.NewPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user.PasswordHash = passwordHash
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		s.logger.Error("Failed to update password", map[string]interface{}{
+			"user_id": userID,
+			"error":   err.Error(),
+		})
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	if err := s.refreshTokenRepo.RevokeAllUserTokens(ctx, userID); err != nil {
+		s.logger.Error("Failed to revoke user tokens after password change", map[string]interface{}{
+			"user_id": userID,
+			"error":   err.Error(),
+		})
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	s.logger.Info("Password changed successfully", map[string]interface{}{
+		"user_id": userID,
+	})
+
+	return nil
+}
+
+// sessionIdentifierLength - сколько символов хеша refresh token показывается клиенту
+const sessionIdentifierLength = 8
+
+// Session - активная сессия (refresh token) пользователя для списка устройств
+// Хеш токена целиком не отдается: Identifier - лишь его начало, чтобы отличать сессии
+type Session struct {
+	ID         uuid.UUID `json:"id"`
+	Identifier string    `json:"identifier"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// ListSessions возвращает активные сессии пользователя
+func (s *Service) ListSessions(ctx context.Context, userID uuid.UUID) ([]*Session, error) {
+	tokens, err := s.refreshTokenRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	sessions := make([]*Session, 0, len(tokens))
+	for _, token := range tokens {
+		identifier := token.TokenHash
+		if len(identifier) > sessionIdentifierLength {
+			identifier = identifier[:sessionIdentifierLength]
+		}
+
+		sessions = append(sessions, &Session{
+			ID:         token.ID,
+			Identifier: identifier,
+			CreatedAt:  token.CreatedAt,
+			ExpiresAt:  token.ExpiresAt,
+		})
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession завершает одну сессию пользователя
+// ErrRefreshTokenNotFound, если сессии нет среди активных сессий пользователя
+func (s *Service) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	if err := s.refreshTokenRepo.RevokeByID(ctx, sessionID, userID); err != nil {
+		if err == domain.ErrRefreshTokenNotFound {
+			return err
+		}
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	s.logger.Info("Session revoked", map[string]interface{}{
+		"user_id":    userID,
+		"session_id": sessionID,
+	})
+
+	return nil
+}
+
+// RevokeAllSessions завершает все сессии пользователя ("выйти на всех устройствах")
+// Выданные access токены действуют до истечения своего срока
+func (s *Service) RevokeAllSessions(ctx context.Context, userID uuid.UUID) error {
+	if err := s.refreshTokenRepo.RevokeAllUserTokens(ctx, userID); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	s.logger.Info("All user sessions revoked", map[string]interface{}{
+		"user_id": userID,
+	})
+
+	return nil
+}
+
+// Префиксы ключей отозванных access токенов в Redis
+const (
+	accessDenylistPrefix    = "access_denylist:"     // jti отдельного токена
+	accessRevokedUserPrefix = "access_revoked_user:" // Время, до которого отозваны все токены пользователя
+)
+
+// RevokeAccessToken вносит access токен в denylist до истечения его срока
+// Без Redis отзыв не поддерживается и вызов ничего не делает
+func (s *Service) RevokeAccessToken(ctx context.Context, jti string, ttl time.Duration) error {
+	if s.redisClient == nil || jti == "" || ttl <= 0 {
+		return nil
+	}
+
+	if err := s.redisClient.Set(ctx, accessDenylistPrefix+jti, "1", ttl); err != nil {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeUserAccessTokens отзывает все уже выданные access токены пользователя
+// (например, при отключении доступа администратором): токены, выданные не позже
+// текущего момента, отклоняются, пока не истек бы самый поздний из них
+func (s *Service) RevokeUserAccessTokens(ctx context.Context, userID uuid.UUID) error {
+	if s.redisClient == nil {
+		return nil
+	}
+
+	now := time.Now().Unix()
+	if err := s.redisClient.Set(ctx, accessRevokedUserPrefix+userID.String(), now, s.tokenService.AccessExpiry()); err != nil {
+		return fmt.Errorf("failed to revoke user access tokens: %w", err)
+	}
+
+	s.logger.Info("User access tokens revoked", map[string]interface{}{
+		"user_id": userID,
+	})
+
+	return nil
+}
+
+// IsAccessTokenRevoked проверяет, отозван ли access токен (по jti или вместе со всеми токенами пользователя)
+// При недоступности Redis токен не отклоняется
+func (s *Service) IsAccessTokenRevoked(ctx context.Context, claims *jwt.Claims) bool {
+	if s.redisClient == nil {
+		return false
+	}
+
+	if claims.ID != "" {
+		exists, err := s.redisClient.Exists(ctx, accessDenylistPrefix+claims.ID)
+		if err != nil {
+			s.logger.Error("Failed to check access token denylist", map[string]interface{}{
+				"error": err.Error(),
+			})
+			return false
+		}
+		if exists > 0 {
+			return true
+		}
+	}
+
+	value, err := s.redisClient.Get(ctx, accessRevokedUserPrefix+claims.UserID.String())
+	if err != nil {
+		if err != redisv9.Nil {
+			s.logger.Error("Failed to check user access token revocation", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+		return false
+	}
+
+	revokedAt, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || claims.IssuedAt == nil {
+		return false
+	}
+
+	return claims.IssuedAt.Unix() <= revokedAt
+}
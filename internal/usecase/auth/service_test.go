@@ -0,0 +1,557 @@
+package auth
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/hash"
+	"github.com/frontandrew/gate/internal/pkg/jwt"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/pkg/redis"
+	"github.com/frontandrew/gate/internal/repository/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Register_Phone(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        Config
+		phone         string
+		expectedPhone string
+		expectedErr   error
+	}{
+		{
+			name:          "номер в E.164 сохраняется как есть",
+			config:        Config{PhoneDefaultRegion: "RU", SMSEnabled: true},
+			phone:         "+79991234567",
+			expectedPhone: "+79991234567",
+		},
+		{
+			name:          "локальный формат нормализуется по региону",
+			config:        Config{PhoneDefaultRegion: "RU", SMSEnabled: true},
+			phone:         "8 (999) 123-45-67",
+			expectedPhone: "+79991234567",
+		},
+		{
+			name:        "некорректный номер отклоняется при включенных SMS",
+			config:      Config{PhoneDefaultRegion: "RU", SMSEnabled: true},
+			phone:       "12-34",
+			expectedErr: domain.ErrInvalidPhone,
+		},
+		{
+			name:          "без SMS некорректный номер сохраняется как введен",
+			config:        Config{PhoneDefaultRegion: "RU"},
+			phone:         "12-34",
+			expectedPhone: "12-34",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(mocks.MockUserRepository)
+			userRepo.On("GetByEmail", mock.Anything, "test@example.com").Return(nil, domain.ErrUserNotFound)
+			if tt.expectedErr == nil {
+				userRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil)
+			}
+
+			svc := NewService(userRepo, nil, nil, nil, nil, logger.NewNoop(), tt.config)
+			user, err := svc.Register(context.Background(), &RegisterRequest{
+				Email:    "test@example.com",
+				Password: "password123",
+				FullName: "Test User",
+				Phone:    tt.phone,
+			})
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, user)
+				userRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedPhone, user.Phone)
+			}
+
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// Пользователь с тем же email создан между проверкой и вставкой: репозиторий
+// сообщает о нарушении уникальности, и ошибка не оборачивается (обработчик отвечает 409)
+func TestService_Register_ConcurrentDuplicate(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	userRepo.On("GetByEmail", mock.Anything, "test@example.com").Return(nil, domain.ErrUserNotFound)
+	userRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.User")).Return(domain.ErrUserAlreadyExists)
+
+	svc := NewService(userRepo, nil, nil, nil, nil, logger.NewNoop(), Config{})
+	user, err := svc.Register(context.Background(), &RegisterRequest{
+		Email:    "test@example.com",
+		Password: "password123",
+		FullName: "Test User",
+	})
+
+	assert.Equal(t, domain.ErrUserAlreadyExists, err)
+	assert.Nil(t, user)
+	userRepo.AssertExpectations(t)
+}
+
+func newTokenTestService() (*Service, *mocks.MockUserRepository, *mocks.MockRefreshTokenRepository, *jwt.TokenService) {
+	userRepo := new(mocks.MockUserRepository)
+	refreshTokenRepo := new(mocks.MockRefreshTokenRepository)
+	tokenService := jwt.NewTokenService("test-secret", time.Hour, 24*time.Hour)
+
+	svc := NewService(userRepo, refreshTokenRepo, tokenService, nil, nil, logger.NewNoop(), Config{})
+	return svc, userRepo, refreshTokenRepo, tokenService
+}
+
+func TestService_Login_StoresRefreshToken(t *testing.T) {
+	svc, userRepo, refreshTokenRepo, _ := newTokenTestService()
+
+	passwordHash, err := hash.HashPassword("password123")
+	require.NoError(t, err)
+	user := &domain.User{ID: uuid.New(), Email: "test@example.com", PasswordHash: passwordHash, Role: domain.RoleUser, IsActive: true}
+
+	var stored *domain.RefreshToken
+	userRepo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+	userRepo.On("UpdateLastLogin", mock.Anything, user.ID).Return(nil)
+	refreshTokenRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.RefreshToken")).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*domain.RefreshToken)
+	}).Return(nil)
+
+	resp, err := svc.Login(context.Background(), &LoginRequest{Email: user.Email, Password: "password123"})
+	require.NoError(t, err)
+
+	require.NotNil(t, stored)
+	assert.Equal(t, user.ID, stored.UserID)
+	assert.Equal(t, jwt.HashToken(resp.RefreshToken), stored.TokenHash)
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), stored.ExpiresAt, time.Minute)
+	userRepo.AssertExpectations(t)
+	refreshTokenRepo.AssertExpectations(t)
+}
+
+func TestService_RefreshToken(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Email: "test@example.com", Role: domain.RoleUser, IsActive: true}
+	revokedAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name        string
+		mockSetup   func(*mocks.MockUserRepository, *mocks.MockRefreshTokenRepository, string)
+		expectedErr error
+	}{
+		{
+			name: "действующий токен ротируется",
+			mockSetup: func(userRepo *mocks.MockUserRepository, tokenRepo *mocks.MockRefreshTokenRepository, tokenHash string) {
+				tokenRepo.On("GetByTokenHash", mock.Anything, tokenHash).Return(&domain.RefreshToken{
+					UserID: user.ID, TokenHash: tokenHash, ExpiresAt: time.Now().Add(time.Hour),
+				}, nil)
+				userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+				tokenRepo.On("Revoke", mock.Anything, tokenHash).Return(nil)
+				tokenRepo.On("Create", mock.Anything, mock.MatchedBy(func(rt *domain.RefreshToken) bool {
+					return rt.UserID == user.ID && rt.TokenHash != tokenHash
+				})).Return(nil)
+			},
+		},
+		{
+			name: "неизвестный токен отклоняется",
+			mockSetup: func(userRepo *mocks.MockUserRepository, tokenRepo *mocks.MockRefreshTokenRepository, tokenHash string) {
+				tokenRepo.On("GetByTokenHash", mock.Anything, tokenHash).Return(nil, domain.ErrRefreshTokenNotFound)
+			},
+			expectedErr: domain.ErrInvalidToken,
+		},
+		{
+			name: "истекший токен отклоняется",
+			mockSetup: func(userRepo *mocks.MockUserRepository, tokenRepo *mocks.MockRefreshTokenRepository, tokenHash string) {
+				tokenRepo.On("GetByTokenHash", mock.Anything, tokenHash).Return(&domain.RefreshToken{
+					UserID: user.ID, TokenHash: tokenHash, ExpiresAt: time.Now().Add(-time.Hour),
+				}, nil)
+			},
+			expectedErr: domain.ErrTokenExpired,
+		},
+		{
+			name: "повторное использование отозванного токена отзывает все сессии",
+			mockSetup: func(userRepo *mocks.MockUserRepository, tokenRepo *mocks.MockRefreshTokenRepository, tokenHash string) {
+				tokenRepo.On("GetByTokenHash", mock.Anything, tokenHash).Return(&domain.RefreshToken{
+					UserID: user.ID, TokenHash: tokenHash, ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt,
+				}, nil)
+				tokenRepo.On("RevokeAllUserTokens", mock.Anything, user.ID).Return(nil)
+			},
+			expectedErr: domain.ErrInvalidToken,
+		},
+		{
+			name: "токен отозван параллельным обменом",
+			mockSetup: func(userRepo *mocks.MockUserRepository, tokenRepo *mocks.MockRefreshTokenRepository, tokenHash string) {
+				tokenRepo.On("GetByTokenHash", mock.Anything, tokenHash).Return(&domain.RefreshToken{
+					UserID: user.ID, TokenHash: tokenHash, ExpiresAt: time.Now().Add(time.Hour),
+				}, nil)
+				userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+				tokenRepo.On("Revoke", mock.Anything, tokenHash).Return(domain.ErrRefreshTokenNotFound)
+				tokenRepo.On("RevokeAllUserTokens", mock.Anything, user.ID).Return(nil)
+			},
+			expectedErr: domain.ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, userRepo, refreshTokenRepo, tokenService := newTokenTestService()
+
+			pair, err := tokenService.GenerateTokenPair(user)
+			require.NoError(t, err)
+			tt.mockSetup(userRepo, refreshTokenRepo, jwt.HashToken(pair.RefreshToken))
+
+			resp, err := svc.RefreshToken(context.Background(), &RefreshTokenRequest{RefreshToken: pair.RefreshToken})
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, resp)
+				refreshTokenRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			} else {
+				require.NoError(t, err)
+				assert.NotEqual(t, pair.RefreshToken, resp.RefreshToken)
+			}
+
+			userRepo.AssertExpectations(t)
+			refreshTokenRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_RefreshToken_ReuseAfterRotation(t *testing.T) {
+	svc, userRepo, refreshTokenRepo, tokenService := newTokenTestService()
+	user := &domain.User{ID: uuid.New(), Email: "test@example.com", Role: domain.RoleUser, IsActive: true}
+
+	pair, err := tokenService.GenerateTokenPair(user)
+	require.NoError(t, err)
+	tokenHash := jwt.HashToken(pair.RefreshToken)
+	revokedAt := time.Now()
+
+	// Первый обмен проходит, после чего токен в БД отозван
+	refreshTokenRepo.On("GetByTokenHash", mock.Anything, tokenHash).Return(&domain.RefreshToken{
+		UserID: user.ID, TokenHash: tokenHash, ExpiresAt: time.Now().Add(time.Hour),
+	}, nil).Once()
+	refreshTokenRepo.On("GetByTokenHash", mock.Anything, tokenHash).Return(&domain.RefreshToken{
+		UserID: user.ID, TokenHash: tokenHash, ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt,
+	}, nil).Once()
+	userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil).Once()
+	refreshTokenRepo.On("Revoke", mock.Anything, tokenHash).Return(nil).Once()
+	refreshTokenRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.RefreshToken")).Return(nil).Once()
+	refreshTokenRepo.On("RevokeAllUserTokens", mock.Anything, user.ID).Return(nil).Once()
+
+	first, err := svc.RefreshToken(context.Background(), &RefreshTokenRequest{RefreshToken: pair.RefreshToken})
+	require.NoError(t, err)
+	require.NotEqual(t, pair.RefreshToken, first.RefreshToken)
+
+	second, err := svc.RefreshToken(context.Background(), &RefreshTokenRequest{RefreshToken: pair.RefreshToken})
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+	assert.Nil(t, second)
+
+	userRepo.AssertExpectations(t)
+	refreshTokenRepo.AssertExpectations(t)
+}
+
+func TestService_RefreshToken_Concurrent(t *testing.T) {
+	svc, userRepo, refreshTokenRepo, tokenService := newTokenTestService()
+	user := &domain.User{ID: uuid.New(), Email: "test@example.com", Role: domain.RoleUser, IsActive: true}
+
+	pair, err := tokenService.GenerateTokenPair(user)
+	require.NoError(t, err)
+	tokenHash := jwt.HashToken(pair.RefreshToken)
+
+	// Оба запроса видят действующий токен, но отозвать его удается только одному
+	refreshTokenRepo.On("GetByTokenHash", mock.Anything, tokenHash).Return(&domain.RefreshToken{
+		UserID: user.ID, TokenHash: tokenHash, ExpiresAt: time.Now().Add(time.Hour),
+	}, nil).Twice()
+	userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil).Twice()
+	refreshTokenRepo.On("Revoke", mock.Anything, tokenHash).Return(nil).Once()
+	refreshTokenRepo.On("Revoke", mock.Anything, tokenHash).Return(domain.ErrRefreshTokenNotFound).Once()
+	refreshTokenRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.RefreshToken")).Return(nil).Once()
+	refreshTokenRepo.On("RevokeAllUserTokens", mock.Anything, user.ID).Return(nil).Once()
+
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := svc.RefreshToken(context.Background(), &RefreshTokenRequest{RefreshToken: pair.RefreshToken})
+			errs <- err
+		}()
+	}
+
+	var succeeded, rejected int
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err == nil {
+			succeeded++
+		} else {
+			assert.ErrorIs(t, err, domain.ErrInvalidToken)
+			rejected++
+		}
+	}
+
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, 1, rejected)
+	userRepo.AssertExpectations(t)
+	refreshTokenRepo.AssertExpectations(t)
+}
+
+func TestService_Logout(t *testing.T) {
+	svc, _, refreshTokenRepo, _ := newTokenTestService()
+	refreshTokenRepo.On("Revoke", mock.Anything, jwt.HashToken("refresh-token")).Return(nil)
+
+	require.NoError(t, svc.Logout(context.Background(), &LogoutRequest{RefreshToken: "refresh-token"}))
+	refreshTokenRepo.AssertExpectations(t)
+}
+
+func TestService_ListSessions(t *testing.T) {
+	svc, _, refreshTokenRepo, _ := newTokenTestService()
+	userID := uuid.New()
+	token := &domain.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		TokenHash: jwt.HashToken("refresh-token"),
+		CreatedAt: time.Now().Add(-time.Hour),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	refreshTokenRepo.On("ListByUser", mock.Anything, userID).Return([]*domain.RefreshToken{token}, nil)
+
+	sessions, err := svc.ListSessions(context.Background(), userID)
+
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, token.ID, sessions[0].ID)
+	assert.Equal(t, token.CreatedAt, sessions[0].CreatedAt)
+	// Наружу уходит только начало хеша
+	assert.Equal(t, token.TokenHash[:sessionIdentifierLength], sessions[0].Identifier)
+	refreshTokenRepo.AssertExpectations(t)
+}
+
+func TestService_RevokeSession(t *testing.T) {
+	userID := uuid.New()
+	sessionID := uuid.New()
+
+	t.Run("сессия отозвана", func(t *testing.T) {
+		svc, _, refreshTokenRepo, _ := newTokenTestService()
+		refreshTokenRepo.On("RevokeByID", mock.Anything, sessionID, userID).Return(nil)
+
+		require.NoError(t, svc.RevokeSession(context.Background(), userID, sessionID))
+		refreshTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("чужая или уже отозванная сессия", func(t *testing.T) {
+		svc, _, refreshTokenRepo, _ := newTokenTestService()
+		refreshTokenRepo.On("RevokeByID", mock.Anything, sessionID, userID).Return(domain.ErrRefreshTokenNotFound)
+
+		err := svc.RevokeSession(context.Background(), userID, sessionID)
+		assert.ErrorIs(t, err, domain.ErrRefreshTokenNotFound)
+	})
+}
+
+func TestService_RevokeAllSessions(t *testing.T) {
+	svc, _, refreshTokenRepo, _ := newTokenTestService()
+	userID := uuid.New()
+	refreshTokenRepo.On("RevokeAllUserTokens", mock.Anything, userID).Return(nil)
+
+	require.NoError(t, svc.RevokeAllSessions(context.Background(), userID))
+	refreshTokenRepo.AssertExpectations(t)
+}
+
+func TestService_ChangePassword(t *testing.T) {
+	passwordHash, err := hash.HashPassword("old-password")
+	require.NoError(t, err)
+	userID := uuid.New()
+
+	tests := []struct {
+		name        string
+		req         *ChangePasswordRequest
+		mockSetup   func(*mocks.MockUserRepository, *mocks.MockRefreshTokenRepository)
+		expectedErr error
+	}{
+		{
+			name: "пароль изменен, сессии отозваны",
+			req:  &ChangePasswordRequest{OldPassword: "old-password", NewPassword: "new-password"},
+			mockSetup: func(userRepo *mocks.MockUserRepository, refreshTokenRepo *mocks.MockRefreshTokenRepository) {
+				userRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+					return hash.CheckPassword(u.PasswordHash, "new-password")
+				})).Return(nil)
+				refreshTokenRepo.On("RevokeAllUserTokens", mock.Anything, userID).Return(nil)
+			},
+		},
+		{
+			name:        "неверный текущий пароль",
+			req:         &ChangePasswordRequest{OldPassword: "wrong-password", NewPassword: "new-password"},
+			expectedErr: domain.ErrInvalidPassword,
+		},
+		{
+			name:        "слишком короткий новый пароль",
+			req:         &ChangePasswordRequest{OldPassword: "old-password", NewPassword: "short"},
+			expectedErr: domain.ErrWeakPassword,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, userRepo, refreshTokenRepo, _ := newTokenTestService()
+			userRepo.On("GetByID", mock.Anything, userID).
+				Return(&domain.User{ID: userID, PasswordHash: passwordHash, Role: domain.RoleUser, IsActive: true}, nil)
+			if tt.mockSetup != nil {
+				tt.mockSetup(userRepo, refreshTokenRepo)
+			}
+
+			err := svc.ChangePassword(context.Background(), userID, tt.req)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+				refreshTokenRepo.AssertNotCalled(t, "RevokeAllUserTokens", mock.Anything, mock.Anything)
+			} else {
+				require.NoError(t, err)
+			}
+			userRepo.AssertExpectations(t)
+			refreshTokenRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_AccessTokenDenylist(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Email: "test@example.com", Role: domain.RoleUser, IsActive: true}
+
+	newService := func(t *testing.T) (*Service, *mocks.MockRefreshTokenRepository, *jwt.TokenService) {
+		client, _ := newTestRedis(t)
+		refreshTokenRepo := new(mocks.MockRefreshTokenRepository)
+		tokenService := jwt.NewTokenService("test-secret", time.Hour, 24*time.Hour)
+		return NewService(new(mocks.MockUserRepository), refreshTokenRepo, tokenService, client, nil, logger.NewNoop(), Config{}), refreshTokenRepo, tokenService
+	}
+
+	issue := func(t *testing.T, tokenService *jwt.TokenService) (string, *jwt.Claims) {
+		pair, err := tokenService.GenerateTokenPair(user)
+		require.NoError(t, err)
+		claims, err := tokenService.ValidateToken(pair.AccessToken)
+		require.NoError(t, err)
+		return pair.AccessToken, claims
+	}
+
+	t.Run("logout отзывает переданный access токен", func(t *testing.T) {
+		svc, refreshTokenRepo, tokenService := newService(t)
+		accessToken, claims := issue(t, tokenService)
+		_, other := issue(t, tokenService)
+		refreshTokenRepo.On("Revoke", mock.Anything, jwt.HashToken("refresh-token")).Return(nil)
+
+		require.NoError(t, svc.Logout(context.Background(), &LogoutRequest{RefreshToken: "refresh-token", AccessToken: accessToken}))
+
+		assert.True(t, svc.IsAccessTokenRevoked(context.Background(), claims))
+		assert.False(t, svc.IsAccessTokenRevoked(context.Background(), other))
+	})
+
+	t.Run("отключение пользователя отзывает все выданные токены", func(t *testing.T) {
+		svc, _, tokenService := newService(t)
+		_, first := issue(t, tokenService)
+		_, second := issue(t, tokenService)
+
+		require.NoError(t, svc.RevokeUserAccessTokens(context.Background(), user.ID))
+
+		assert.True(t, svc.IsAccessTokenRevoked(context.Background(), first))
+		assert.True(t, svc.IsAccessTokenRevoked(context.Background(), second))
+
+		_, foreign := issue(t, tokenService)
+		foreign.UserID = uuid.New()
+		assert.False(t, svc.IsAccessTokenRevoked(context.Background(), foreign))
+	})
+}
+
+func TestService_Register_Closed(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	svc := NewService(userRepo, nil, nil, nil, nil, logger.NewNoop(), Config{DisableRegistration: true})
+
+	user, err := svc.Register(context.Background(), &RegisterRequest{
+		Email:    "test@example.com",
+		Password: "password123",
+		FullName: "Test User",
+	})
+
+	assert.ErrorIs(t, err, domain.ErrRegistrationClosed)
+	assert.Nil(t, user)
+	userRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// newTestRedis поднимает in-memory Redis и возвращает клиент к нему
+func newTestRedis(t *testing.T) (*redis.Client, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+
+	host, port, err := net.SplitHostPort(mr.Addr())
+	if err != nil {
+		t.Fatalf("failed to parse miniredis address: %v", err)
+	}
+
+	client, err := redis.NewClient(redis.Config{Host: host, Port: port})
+	if err != nil {
+		t.Fatalf("failed to connect to miniredis: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	return client, mr
+}
+
+func TestService_Login_Lockout(t *testing.T) {
+	passwordHash, err := hash.HashPassword("password123")
+	require.NoError(t, err)
+	user := &domain.User{ID: uuid.New(), Email: "test@example.com", PasswordHash: passwordHash, Role: domain.RoleUser, IsActive: true}
+	config := Config{MaxLoginAttempts: 3, LockoutDuration: 15 * time.Minute}
+
+	newService := func(t *testing.T) (*Service, *miniredis.Miniredis) {
+		client, mr := newTestRedis(t)
+		userRepo := new(mocks.MockUserRepository)
+		refreshTokenRepo := new(mocks.MockRefreshTokenRepository)
+		tokenService := jwt.NewTokenService("test-secret", time.Hour, 24*time.Hour)
+
+		// Login очищает PasswordHash у возвращенного пользователя - восстанавливаем перед каждым вызовом
+		userRepo.On("GetByEmail", mock.Anything, user.Email).Run(func(mock.Arguments) {
+			user.PasswordHash = passwordHash
+		}).Return(user, nil)
+		userRepo.On("UpdateLastLogin", mock.Anything, user.ID).Return(nil).Maybe()
+		refreshTokenRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+		return NewService(userRepo, refreshTokenRepo, tokenService, client, nil, logger.NewNoop(), config), mr
+	}
+
+	t.Run("блокировка после превышения лимита", func(t *testing.T) {
+		svc, mr := newService(t)
+
+		for i := 0; i < config.MaxLoginAttempts; i++ {
+			_, err := svc.Login(context.Background(), &LoginRequest{Email: user.Email, Password: "wrong"})
+			assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+		}
+
+		// Даже верный пароль не принимается до истечения блокировки
+		_, err := svc.Login(context.Background(), &LoginRequest{Email: user.Email, Password: "password123"})
+		assert.ErrorIs(t, err, domain.ErrAccountLocked)
+		assert.Equal(t, config.LockoutDuration, mr.TTL(loginFailKey(user.Email)))
+
+		mr.FastForward(config.LockoutDuration)
+		_, err = svc.Login(context.Background(), &LoginRequest{Email: user.Email, Password: "password123"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("успешный вход сбрасывает счетчик", func(t *testing.T) {
+		svc, mr := newService(t)
+
+		for i := 0; i < config.MaxLoginAttempts-1; i++ {
+			_, err := svc.Login(context.Background(), &LoginRequest{Email: user.Email, Password: "wrong"})
+			assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+		}
+
+		_, err := svc.Login(context.Background(), &LoginRequest{Email: user.Email, Password: "password123"})
+		require.NoError(t, err)
+		assert.False(t, mr.Exists(loginFailKey(user.Email)))
+
+		// После сброса лимит снова отсчитывается с нуля
+		_, err = svc.Login(context.Background(), &LoginRequest{Email: user.Email, Password: "wrong"})
+		assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+	})
+}
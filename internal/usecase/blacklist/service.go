@@ -0,0 +1,296 @@
+package blacklist
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/repository"
+	"github.com/google/uuid"
+)
+
+// CreateEntryRequest - запрос на добавление номера в черный список
+type CreateEntryRequest struct {
+	LicensePlate string     `json:"license_plate" validate:"required"`
+	Reason       string     `json:"reason" validate:"required"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	Zone         *string    `json:"zone,omitempty"` // Зона ворот, где действует запись; пусто - на всех воротах
+	AddedBy      uuid.UUID  `json:"-"`              // Заполняется из claims
+}
+
+// UpdateEntryRequest - запрос на изменение записи черного списка
+// Номер не меняется: для другого номера запись удаляется и создается заново
+type UpdateEntryRequest struct {
+	Reason      *string    `json:"reason,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	ClearExpiry bool       `json:"clear_expiry,omitempty"` // Сделать блокировку бессрочной
+	IsActive    *bool      `json:"is_active,omitempty"`
+	Zone        *string    `json:"zone,omitempty"` // Новая зона записи; "" - действует на всех воротах
+	UpdatedBy   uuid.UUID  `json:"-"`              // Заполняется из claims
+}
+
+// MaxBulkEntries - наибольшее число строк в одном пакетном добавлении
+const MaxBulkEntries = 1000
+
+// maxReasonLength - длина колонки reason в таблице
+const maxReasonLength = 500
+
+// BulkEntryResult - итог добавления одной строки пакета
+type BulkEntryResult struct {
+	Row          int                    `json:"row"` // Номер строки в запросе, начиная с 1
+	LicensePlate string                 `json:"license_plate"`
+	Success      bool                   `json:"success"`
+	Entry        *domain.BlacklistEntry `json:"entry,omitempty"`
+	Error        string                 `json:"error,omitempty"`
+}
+
+// BulkResult - итог пакетного добавления
+type BulkResult struct {
+	Created int               `json:"created"`
+	Failed  int               `json:"failed"`
+	Results []BulkEntryResult `json:"results"`
+}
+
+// AuditRecorder записывает действия в журнал аудита
+type AuditRecorder interface {
+	Record(ctx context.Context, actorID *uuid.UUID, action string, targetType domain.AuditTargetType, targetID *uuid.UUID, details map[string]interface{})
+}
+
+// Config содержит настройки сервиса черного списка
+type Config struct {
+	PlateCountry string // Страна формата номеров (ISO 3166-1 alpha-2, пусто - без проверки формата)
+}
+
+// Service содержит бизнес-логику управления черным списком
+// Каждое изменение пишется в журнал аудита: блокировка номера влияет на доступ
+type Service struct {
+	blacklistRepo repository.BlacklistRepository
+	audit         AuditRecorder
+	logger        logger.Logger
+	config        Config
+}
+
+// NewService создает новый экземпляр BlacklistService
+func NewService(
+	blacklistRepo repository.BlacklistRepository,
+	audit AuditRecorder,
+	logger logger.Logger,
+	config Config,
+) *Service {
+	return &Service{
+		blacklistRepo: blacklistRepo,
+		audit:         audit,
+		logger:        logger,
+		config:        config,
+	}
+}
+
+// CreateEntry добавляет номер в черный список
+func (s *Service) CreateEntry(ctx context.Context, req *CreateEntryRequest) (*domain.BlacklistEntry, error) {
+	s.logger.Info("Adding plate to blacklist", map[string]interface{}{
+		"license_plate": req.LicensePlate,
+		"added_by":      req.AddedBy,
+	})
+
+	entry := &domain.BlacklistEntry{
+		LicensePlate: req.LicensePlate,
+		Reason:       req.Reason,
+		AddedBy:      req.AddedBy,
+		AddedAt:      time.Now(),
+		ExpiresAt:    req.ExpiresAt,
+		Zone:         req.Zone,
+		IsActive:     true,
+	}
+
+	if err := entry.Validate(s.config.PlateCountry); err != nil {
+		return nil, err
+	}
+
+	if err := s.blacklistRepo.Create(ctx, entry); err != nil {
+		if err == domain.ErrBlacklistEntryAlreadyExists {
+			return nil, err
+		}
+		s.logger.Error("Failed to create blacklist entry", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, fmt.Errorf("failed to create blacklist entry: %w", err)
+	}
+
+	s.audit.Record(ctx, &req.AddedBy, "create", domain.AuditTargetBlacklist, &entry.ID, entryDetails(entry))
+
+	return entry, nil
+}
+
+// CreateEntries добавляет пакет номеров в черный список одной транзакцией
+// Невалидные строки и дубликаты не прерывают пакет: итог возвращается по каждой строке
+func (s *Service) CreateEntries(ctx context.Context, reqs []CreateEntryRequest, addedBy uuid.UUID) (*BulkResult, error) {
+	result := &BulkResult{Results: make([]BulkEntryResult, len(reqs))}
+
+	entries := make([]*domain.BlacklistEntry, 0, len(reqs))
+	rows := make([]int, 0, len(reqs)) // Индекс строки запроса для каждой записи пакета
+	for i, req := range reqs {
+		result.Results[i] = BulkEntryResult{Row: i + 1, LicensePlate: req.LicensePlate}
+
+		entry := &domain.BlacklistEntry{
+			LicensePlate: req.LicensePlate,
+			Reason:       req.Reason,
+			AddedBy:      addedBy,
+			ExpiresAt:    req.ExpiresAt,
+			Zone:         req.Zone,
+			IsActive:     true,
+		}
+		if err := s.validateBulkEntry(entry); err != nil {
+			result.Results[i].Error = err.Error()
+			continue
+		}
+
+		result.Results[i].LicensePlate = entry.LicensePlate
+		entries = append(entries, entry)
+		rows = append(rows, i)
+	}
+
+	if len(entries) > 0 {
+		rowErrors, err := s.blacklistRepo.CreateBatch(ctx, entries)
+		if err != nil {
+			s.logger.Error("Failed to create blacklist entries", map[string]interface{}{
+				"entries": len(entries),
+				"error":   err.Error(),
+			})
+			return nil, fmt.Errorf("failed to create blacklist entries: %w", err)
+		}
+
+		for j, entry := range entries {
+			i := rows[j]
+			if rowErrors[j] != nil {
+				result.Results[i].Error = rowErrors[j].Error()
+				continue
+			}
+
+			result.Results[i].Success = true
+			result.Results[i].Entry = entry
+			result.Created++
+
+			s.audit.Record(ctx, &addedBy, "create", domain.AuditTargetBlacklist, &entry.ID, entryDetails(entry))
+		}
+	}
+
+	result.Failed = len(reqs) - result.Created
+
+	s.logger.Info("Blacklist bulk import finished", map[string]interface{}{
+		"added_by": addedBy,
+		"created":  result.Created,
+		"failed":   result.Failed,
+	})
+
+	return result, nil
+}
+
+// ListEntries возвращает записи черного списка с пагинацией
+func (s *Service) ListEntries(ctx context.Context, limit, offset int) ([]*domain.BlacklistEntry, error) {
+	return s.blacklistRepo.List(ctx, limit, offset)
+}
+
+// GetEntry возвращает запись черного списка по ID
+func (s *Service) GetEntry(ctx context.Context, id uuid.UUID) (*domain.BlacklistEntry, error) {
+	return s.blacklistRepo.GetByID(ctx, id)
+}
+
+// UpdateEntry изменяет причину, срок действия или активность записи
+func (s *Service) UpdateEntry(ctx context.Context, id uuid.UUID, req *UpdateEntryRequest) (*domain.BlacklistEntry, error) {
+	entry, err := s.blacklistRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Reason != nil {
+		entry.Reason = *req.Reason
+	}
+	if req.ClearExpiry {
+		entry.ExpiresAt = nil
+	} else if req.ExpiresAt != nil {
+		entry.ExpiresAt = req.ExpiresAt
+	}
+	if req.IsActive != nil {
+		entry.IsActive = *req.IsActive
+	}
+	if req.Zone != nil {
+		entry.Zone = req.Zone
+	}
+
+	// Номер при обновлении не меняется, поэтому формат страны здесь не проверяется:
+	// иначе записи со старыми или иностранными номерами нельзя было бы даже деактивировать
+	if err := entry.Validate(""); err != nil {
+		return nil, err
+	}
+
+	if err := s.blacklistRepo.Update(ctx, entry); err != nil {
+		if err == domain.ErrBlacklistEntryNotFound {
+			return nil, err
+		}
+		s.logger.Error("Failed to update blacklist entry", map[string]interface{}{
+			"entry_id": id,
+			"error":    err.Error(),
+		})
+		return nil, fmt.Errorf("failed to update blacklist entry: %w", err)
+	}
+
+	s.audit.Record(ctx, &req.UpdatedBy, "update", domain.AuditTargetBlacklist, &entry.ID, entryDetails(entry))
+
+	return entry, nil
+}
+
+// DeleteEntry удаляет запись из черного списка
+func (s *Service) DeleteEntry(ctx context.Context, id, deletedBy uuid.UUID) error {
+	entry, err := s.blacklistRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.blacklistRepo.Delete(ctx, id); err != nil {
+		if err == domain.ErrBlacklistEntryNotFound {
+			return err
+		}
+		s.logger.Error("Failed to delete blacklist entry", map[string]interface{}{
+			"entry_id": id,
+			"error":    err.Error(),
+		})
+		return fmt.Errorf("failed to delete blacklist entry: %w", err)
+	}
+
+	s.audit.Record(ctx, &deletedBy, "delete", domain.AuditTargetBlacklist, &id, map[string]interface{}{
+		"license_plate": entry.LicensePlate,
+	})
+
+	return nil
+}
+
+// entryDetails - состояние записи для журнала аудита
+func entryDetails(entry *domain.BlacklistEntry) map[string]interface{} {
+	details := map[string]interface{}{
+		"license_plate": entry.LicensePlate,
+		"reason":        entry.Reason,
+		"is_active":     entry.IsActive,
+	}
+	if entry.ExpiresAt != nil {
+		details["expires_at"] = entry.ExpiresAt.Format(time.RFC3339)
+	}
+	if entry.Zone != nil {
+		details["zone"] = *entry.Zone
+	}
+	return details
+}
+
+// validateBulkEntry проверяет строку пакета заранее: номер, не проходящий ограничения таблицы,
+// прервал бы всю транзакцию
+func (s *Service) validateBulkEntry(entry *domain.BlacklistEntry) error {
+	if err := entry.Validate(s.config.PlateCountry); err != nil {
+		return err
+	}
+	if utf8.RuneCountInString(entry.Reason) > maxReasonLength {
+		return domain.ErrInvalidBlacklistData
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package blacklist
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/repository/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAuditRecorder struct {
+	mock.Mock
+}
+
+func (m *mockAuditRecorder) Record(ctx context.Context, actorID *uuid.UUID, action string, targetType domain.AuditTargetType, targetID *uuid.UUID, details map[string]interface{}) {
+	m.Called(ctx, actorID, action, targetType, targetID, details)
+}
+
+func TestService_CreateEntry(t *testing.T) {
+	addedBy := uuid.New()
+	moscow := time.FixedZone("MSK", 3*60*60)
+	future := time.Now().Add(24 * time.Hour).In(moscow)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name        string
+		expiresAt   *time.Time
+		createErr   error
+		expectedErr error
+	}{
+		{
+			name:      "запись создана, действие записано в аудит",
+			expiresAt: &future,
+		},
+		{
+			name:        "номер уже в черном списке",
+			createErr:   domain.ErrBlacklistEntryAlreadyExists,
+			expectedErr: domain.ErrBlacklistEntryAlreadyExists,
+		},
+		{
+			name:        "прошедший срок отклоняется",
+			expiresAt:   &past,
+			expectedErr: domain.ErrExpiryInPast,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blacklistRepo := new(mocks.MockBlacklistRepository)
+			audit := new(mockAuditRecorder)
+			if tt.expectedErr != domain.ErrExpiryInPast {
+				blacklistRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.BlacklistEntry")).Return(tt.createErr)
+			}
+			if tt.expectedErr == nil {
+				audit.On("Record", mock.Anything, &addedBy, "create", domain.AuditTargetBlacklist, mock.Anything,
+					mock.MatchedBy(func(details map[string]interface{}) bool {
+						return details["license_plate"] == "A123BC777" && details["expires_at"] != nil
+					})).Return()
+			}
+
+			svc := NewService(blacklistRepo, audit, logger.NewNoop(), Config{})
+			entry, err := svc.CreateEntry(context.Background(), &CreateEntryRequest{
+				LicensePlate: "a123 bc777",
+				Reason:       "Угнан",
+				ExpiresAt:    tt.expiresAt,
+				AddedBy:      addedBy,
+			})
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, entry)
+				audit.AssertNotCalled(t, "Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "A123BC777", entry.LicensePlate)
+				assert.Equal(t, addedBy, entry.AddedBy)
+				require.NotNil(t, entry.ExpiresAt)
+				assert.Equal(t, time.UTC, entry.ExpiresAt.Location())
+			}
+			blacklistRepo.AssertExpectations(t)
+			audit.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_DeleteEntry(t *testing.T) {
+	entryID := uuid.New()
+	deletedBy := uuid.New()
+
+	blacklistRepo := new(mocks.MockBlacklistRepository)
+	blacklistRepo.On("GetByID", mock.Anything, entryID).
+		Return(&domain.BlacklistEntry{ID: entryID, LicensePlate: "A123BC777", IsActive: true}, nil)
+	blacklistRepo.On("Delete", mock.Anything, entryID).Return(nil)
+
+	audit := new(mockAuditRecorder)
+	audit.On("Record", mock.Anything, &deletedBy, "delete", domain.AuditTargetBlacklist, &entryID,
+		map[string]interface{}{"license_plate": "A123BC777"}).Return()
+
+	svc := NewService(blacklistRepo, audit, logger.NewNoop(), Config{})
+	require.NoError(t, svc.DeleteEntry(context.Background(), entryID, deletedBy))
+
+	blacklistRepo.AssertExpectations(t)
+	audit.AssertExpectations(t)
+}
+
+func TestService_UpdateEntry_LegacyPlate(t *testing.T) {
+	entryID := uuid.New()
+	updatedBy := uuid.New()
+	inactive := false
+
+	blacklistRepo := new(mocks.MockBlacklistRepository)
+	blacklistRepo.On("GetByID", mock.Anything, entryID).
+		Return(&domain.BlacklistEntry{ID: entryID, LicensePlate: "B456CD777", Reason: "Нарушитель", AddedBy: updatedBy, IsActive: true}, nil)
+	blacklistRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.BlacklistEntry")).Return(nil)
+
+	audit := new(mockAuditRecorder)
+	audit.On("Record", mock.Anything, &updatedBy, "update", domain.AuditTargetBlacklist, &entryID, mock.Anything).Return()
+
+	// Номер не соответствует формату RU, но при обновлении он не меняется
+	svc := NewService(blacklistRepo, audit, logger.NewNoop(), Config{PlateCountry: "RU"})
+	entry, err := svc.UpdateEntry(context.Background(), entryID, &UpdateEntryRequest{IsActive: &inactive, UpdatedBy: updatedBy})
+
+	require.NoError(t, err)
+	assert.False(t, entry.IsActive)
+	blacklistRepo.AssertExpectations(t)
+	audit.AssertExpectations(t)
+}
+
+func TestService_CreateEntries(t *testing.T) {
+	addedBy := uuid.New()
+	past := time.Now().Add(-time.Hour)
+
+	blacklistRepo := new(mocks.MockBlacklistRepository)
+	audit := new(mockAuditRecorder)
+
+	// В пакет попадают только прошедшие проверку строки, номера уже нормализованы
+	blacklistRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(entries []*domain.BlacklistEntry) bool {
+		return len(entries) == 3 &&
+			entries[0].LicensePlate == "A123BC777" &&
+			entries[1].LicensePlate == "B456CE777" &&
+			entries[2].LicensePlate == "A123BC777"
+	})).Return([]error{nil, domain.ErrBlacklistEntryAlreadyExists, domain.ErrBlacklistEntryAlreadyExists}, nil)
+	audit.On("Record", mock.Anything, &addedBy, "create", domain.AuditTargetBlacklist, mock.Anything, mock.Anything).Return().Once()
+
+	svc := NewService(blacklistRepo, audit, logger.NewNoop(), Config{})
+	result, err := svc.CreateEntries(context.Background(), []CreateEntryRequest{
+		{LicensePlate: "a123 bc777", Reason: "Угнан"},
+		{LicensePlate: "B456CE777", Reason: "Уже заблокирован"},
+		{LicensePlate: "C789-KM", Reason: "Недопустимые символы"},
+		{LicensePlate: "E001OO777", Reason: ""},
+		{LicensePlate: "K002PP777", Reason: "Истекший срок", ExpiresAt: &past},
+		{LicensePlate: "A123BC777", Reason: "Повтор в пакете"},
+	}, addedBy)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 5, result.Failed)
+	require.Len(t, result.Results, 6)
+
+	assert.True(t, result.Results[0].Success)
+	assert.Equal(t, "A123BC777", result.Results[0].LicensePlate)
+	require.NotNil(t, result.Results[0].Entry)
+
+	expectedErrors := []error{
+		nil,
+		domain.ErrBlacklistEntryAlreadyExists,
+		domain.ErrInvalidLicensePlate,
+		domain.ErrInvalidBlacklistData,
+		domain.ErrExpiryInPast,
+		domain.ErrBlacklistEntryAlreadyExists,
+	}
+	for i, expected := range expectedErrors {
+		assert.Equal(t, i+1, result.Results[i].Row)
+		if expected == nil {
+			assert.Empty(t, result.Results[i].Error)
+			continue
+		}
+		assert.False(t, result.Results[i].Success, "строка %d", i+1)
+		assert.Nil(t, result.Results[i].Entry, "строка %d", i+1)
+		assert.Equal(t, expected.Error(), result.Results[i].Error, "строка %d", i+1)
+	}
+
+	audit.AssertExpectations(t)
+}
+
+func TestService_CreateEntries_BatchFailure(t *testing.T) {
+	blacklistRepo := new(mocks.MockBlacklistRepository)
+	audit := new(mockAuditRecorder)
+	blacklistRepo.On("CreateBatch", mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+	svc := NewService(blacklistRepo, audit, logger.NewNoop(), Config{})
+	result, err := svc.CreateEntries(context.Background(), []CreateEntryRequest{
+		{LicensePlate: "A123BC777", Reason: "Угнан"},
+	}, uuid.New())
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, result)
+	audit.AssertNotCalled(t, "Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_CreateEntries_AllInvalid(t *testing.T) {
+	blacklistRepo := new(mocks.MockBlacklistRepository)
+
+	svc := NewService(blacklistRepo, new(mockAuditRecorder), logger.NewNoop(), Config{})
+	result, err := svc.CreateEntries(context.Background(), []CreateEntryRequest{
+		{LicensePlate: "", Reason: "Пустой номер"},
+	}, uuid.New())
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Created)
+	assert.Equal(t, 1, result.Failed)
+	blacklistRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,229 @@
+package lists
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/repository"
+	"github.com/google/uuid"
+)
+
+// exportBatchSize - размер страницы при чтении списков для экспорта
+const exportBatchSize = 100
+
+// Entry - запись белого или черного списка в переносимом формате (без ID и автора)
+type Entry struct {
+	LicensePlate string     `json:"license_plate"`
+	Reason       string     `json:"reason"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	Zone         *string    `json:"zone,omitempty"`
+}
+
+// Snapshot - выгрузка действующих записей обоих списков
+type Snapshot struct {
+	ExportedAt time.Time `json:"exported_at"`
+	Whitelist  []Entry   `json:"whitelist"`
+	Blacklist  []Entry   `json:"blacklist"`
+}
+
+// ImportStats - итог импорта одного списка
+type ImportStats struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+}
+
+// ImportResult - итог импорта выгрузки
+type ImportResult struct {
+	Whitelist ImportStats `json:"whitelist"`
+	Blacklist ImportStats `json:"blacklist"`
+}
+
+// Config содержит настройки сервиса списков
+type Config struct {
+	PlateCountry string // Страна формата номеров (ISO 3166-1 alpha-2, пусто - без проверки формата)
+}
+
+// Service выгружает и восстанавливает белый и черный списки
+// (резервное копирование, перенос между окружениями)
+type Service struct {
+	whitelistRepo repository.WhitelistRepository
+	blacklistRepo repository.BlacklistRepository
+	logger        logger.Logger
+	config        Config
+}
+
+// NewService создает новый экземпляр сервиса списков
+// Репозитории должны быть кэшируемыми, чтобы импорт инвалидировал кэш проверок доступа
+func NewService(
+	whitelistRepo repository.WhitelistRepository,
+	blacklistRepo repository.BlacklistRepository,
+	logger logger.Logger,
+	config Config,
+) *Service {
+	return &Service{
+		whitelistRepo: whitelistRepo,
+		blacklistRepo: blacklistRepo,
+		logger:        logger,
+		config:        config,
+	}
+}
+
+// Export возвращает действующие (активные и не истекшие) записи обоих списков
+func (s *Service) Export(ctx context.Context) (*Snapshot, error) {
+	snapshot := &Snapshot{
+		ExportedAt: time.Now(),
+		Whitelist:  []Entry{},
+		Blacklist:  []Entry{},
+	}
+
+	for offset := 0; ; offset += exportBatchSize {
+		entries, err := s.whitelistRepo.List(ctx, exportBatchSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list whitelist: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.IsValid() {
+				snapshot.Whitelist = append(snapshot.Whitelist, Entry{
+					LicensePlate: entry.LicensePlate,
+					Reason:       entry.Reason,
+					ExpiresAt:    entry.ExpiresAt,
+					Zone:         entry.Zone,
+				})
+			}
+		}
+		if len(entries) < exportBatchSize {
+			break
+		}
+	}
+
+	for offset := 0; ; offset += exportBatchSize {
+		entries, err := s.blacklistRepo.List(ctx, exportBatchSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list blacklist: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.IsValid() {
+				snapshot.Blacklist = append(snapshot.Blacklist, Entry{
+					LicensePlate: entry.LicensePlate,
+					Reason:       entry.Reason,
+					ExpiresAt:    entry.ExpiresAt,
+					Zone:         entry.Zone,
+				})
+			}
+		}
+		if len(entries) < exportBatchSize {
+			break
+		}
+	}
+
+	return snapshot, nil
+}
+
+// Import восстанавливает записи из выгрузки
+// Идемпотентен: совпадающие записи не меняются, отличающиеся - обновляются по номеру и зоне.
+// Записи, отсутствующие в выгрузке, не удаляются. Выгрузка проверяется целиком до записи в БД
+func (s *Service) Import(ctx context.Context, snapshot *Snapshot, addedBy uuid.UUID) (*ImportResult, error) {
+	whitelist := make([]*domain.WhitelistEntry, 0, len(snapshot.Whitelist))
+	for i, item := range snapshot.Whitelist {
+		entry := &domain.WhitelistEntry{
+			LicensePlate: item.LicensePlate,
+			Reason:       item.Reason,
+			AddedBy:      addedBy,
+			ExpiresAt:    item.ExpiresAt,
+			Zone:         item.Zone,
+			IsActive:     true,
+		}
+		if err := entry.Validate(s.config.PlateCountry); err != nil {
+			return nil, fmt.Errorf("whitelist[%d]: %w", i, err)
+		}
+		whitelist = append(whitelist, entry)
+	}
+
+	blacklist := make([]*domain.BlacklistEntry, 0, len(snapshot.Blacklist))
+	for i, item := range snapshot.Blacklist {
+		entry := &domain.BlacklistEntry{
+			LicensePlate: item.LicensePlate,
+			Reason:       item.Reason,
+			AddedBy:      addedBy,
+			ExpiresAt:    item.ExpiresAt,
+			Zone:         item.Zone,
+			IsActive:     true,
+		}
+		if err := entry.Validate(s.config.PlateCountry); err != nil {
+			return nil, fmt.Errorf("blacklist[%d]: %w", i, err)
+		}
+		blacklist = append(blacklist, entry)
+	}
+
+	result := &ImportResult{}
+
+	for _, entry := range whitelist {
+		existing, err := s.whitelistRepo.GetByLicensePlate(ctx, entry.LicensePlate, entry.Zone)
+		switch {
+		case err == domain.ErrWhitelistEntryNotFound:
+			entry.AddedAt = time.Now()
+			if err := s.whitelistRepo.Create(ctx, entry); err != nil {
+				return result, fmt.Errorf("failed to import whitelist entry %s: %w", entry.LicensePlate, err)
+			}
+			result.Whitelist.Created++
+		case err != nil:
+			return result, fmt.Errorf("failed to check whitelist entry %s: %w", entry.LicensePlate, err)
+		case existing.Reason == entry.Reason && sameExpiry(existing.ExpiresAt, entry.ExpiresAt):
+			result.Whitelist.Unchanged++
+		default:
+			existing.Reason = entry.Reason
+			existing.ExpiresAt = entry.ExpiresAt
+			if err := s.whitelistRepo.Update(ctx, existing); err != nil {
+				return result, fmt.Errorf("failed to update whitelist entry %s: %w", entry.LicensePlate, err)
+			}
+			result.Whitelist.Updated++
+		}
+	}
+
+	for _, entry := range blacklist {
+		existing, err := s.blacklistRepo.GetByLicensePlate(ctx, entry.LicensePlate, entry.Zone)
+		switch {
+		case err == domain.ErrBlacklistEntryNotFound:
+			entry.AddedAt = time.Now()
+			if err := s.blacklistRepo.Create(ctx, entry); err != nil {
+				return result, fmt.Errorf("failed to import blacklist entry %s: %w", entry.LicensePlate, err)
+			}
+			result.Blacklist.Created++
+		case err != nil:
+			return result, fmt.Errorf("failed to check blacklist entry %s: %w", entry.LicensePlate, err)
+		case existing.Reason == entry.Reason && sameExpiry(existing.ExpiresAt, entry.ExpiresAt):
+			result.Blacklist.Unchanged++
+		default:
+			existing.Reason = entry.Reason
+			existing.ExpiresAt = entry.ExpiresAt
+			if err := s.blacklistRepo.Update(ctx, existing); err != nil {
+				return result, fmt.Errorf("failed to update blacklist entry %s: %w", entry.LicensePlate, err)
+			}
+			result.Blacklist.Updated++
+		}
+	}
+
+	s.logger.Info("Lists imported", map[string]interface{}{
+		"imported_by":         addedBy,
+		"whitelist_created":   result.Whitelist.Created,
+		"whitelist_updated":   result.Whitelist.Updated,
+		"whitelist_unchanged": result.Whitelist.Unchanged,
+		"blacklist_created":   result.Blacklist.Created,
+		"blacklist_updated":   result.Blacklist.Updated,
+		"blacklist_unchanged": result.Blacklist.Unchanged,
+	})
+
+	return result, nil
+}
+
+// sameExpiry сравнивает сроки действия (nil - бессрочно)
+func sameExpiry(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,195 @@
+package lists
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/repository/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type serviceMocks struct {
+	whitelistRepo *mocks.MockWhitelistRepository
+	blacklistRepo *mocks.MockBlacklistRepository
+}
+
+func newTestService() (*Service, *serviceMocks) {
+	m := &serviceMocks{
+		whitelistRepo: new(mocks.MockWhitelistRepository),
+		blacklistRepo: new(mocks.MockBlacklistRepository),
+	}
+
+	svc := NewService(m.whitelistRepo, m.blacklistRepo, logger.NewNoop(), Config{})
+	return svc, m
+}
+
+func (m *serviceMocks) assertExpectations(t *testing.T) {
+	m.whitelistRepo.AssertExpectations(t)
+	m.blacklistRepo.AssertExpectations(t)
+}
+
+func TestService_ExportImportRoundTrip(t *testing.T) {
+	adminID := uuid.New()
+	future := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	past := time.Now().Add(-time.Hour)
+
+	sourceWhitelist := []*domain.WhitelistEntry{
+		{ID: uuid.New(), LicensePlate: "A123BC777", Reason: "Скорая помощь", AddedBy: adminID, IsActive: true},
+		{ID: uuid.New(), LicensePlate: "B456CD777", Reason: "Подрядчик", AddedBy: adminID, ExpiresAt: &future, IsActive: true},
+		{ID: uuid.New(), LicensePlate: "C789EF777", Reason: "Истекла", AddedBy: adminID, ExpiresAt: &past, IsActive: true},
+	}
+	sourceBlacklist := []*domain.BlacklistEntry{
+		{ID: uuid.New(), LicensePlate: "E001KX777", Reason: "Нарушение", AddedBy: adminID, IsActive: true},
+		{ID: uuid.New(), LicensePlate: "E002KX777", Reason: "Снята", AddedBy: adminID, IsActive: false},
+	}
+
+	// Экспорт из исходного окружения
+	source, sm := newTestService()
+	sm.whitelistRepo.On("List", mock.Anything, exportBatchSize, 0).Return(sourceWhitelist, nil)
+	sm.blacklistRepo.On("List", mock.Anything, exportBatchSize, 0).Return(sourceBlacklist, nil)
+
+	snapshot, err := source.Export(context.Background())
+	require.NoError(t, err)
+	require.Len(t, snapshot.Whitelist, 2, "истекшие записи не выгружаются")
+	require.Len(t, snapshot.Blacklist, 1, "неактивные записи не выгружаются")
+	sm.assertExpectations(t)
+
+	// Выгрузка передается как JSON
+	payload, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+	var restored Snapshot
+	require.NoError(t, json.Unmarshal(payload, &restored))
+
+	// Импорт в пустое окружение
+	target, tm := newTestService()
+	var createdWhitelist []*domain.WhitelistEntry
+	var createdBlacklist []*domain.BlacklistEntry
+	tm.whitelistRepo.On("GetByLicensePlate", mock.Anything, mock.Anything, mock.Anything).Return(nil, domain.ErrWhitelistEntryNotFound)
+	tm.blacklistRepo.On("GetByLicensePlate", mock.Anything, mock.Anything, mock.Anything).Return(nil, domain.ErrBlacklistEntryNotFound)
+	tm.whitelistRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.WhitelistEntry")).Run(func(args mock.Arguments) {
+		createdWhitelist = append(createdWhitelist, args.Get(1).(*domain.WhitelistEntry))
+	}).Return(nil)
+	tm.blacklistRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.BlacklistEntry")).Run(func(args mock.Arguments) {
+		createdBlacklist = append(createdBlacklist, args.Get(1).(*domain.BlacklistEntry))
+	}).Return(nil)
+
+	result, err := target.Import(context.Background(), &restored, adminID)
+	require.NoError(t, err)
+	assert.Equal(t, ImportStats{Created: 2}, result.Whitelist)
+	assert.Equal(t, ImportStats{Created: 1}, result.Blacklist)
+	tm.assertExpectations(t)
+
+	require.Len(t, createdWhitelist, 2)
+	for i, entry := range createdWhitelist {
+		assert.Equal(t, sourceWhitelist[i].LicensePlate, entry.LicensePlate)
+		assert.Equal(t, sourceWhitelist[i].Reason, entry.Reason)
+		assert.True(t, sameExpiry(sourceWhitelist[i].ExpiresAt, entry.ExpiresAt))
+		assert.True(t, entry.IsActive)
+		assert.Equal(t, adminID, entry.AddedBy)
+	}
+	require.Len(t, createdBlacklist, 1)
+	assert.Equal(t, "E001KX777", createdBlacklist[0].LicensePlate)
+	assert.Equal(t, "Нарушение", createdBlacklist[0].Reason)
+
+	// Повторный импорт той же выгрузки ничего не меняет
+	again, am := newTestService()
+	for _, entry := range createdWhitelist {
+		am.whitelistRepo.On("GetByLicensePlate", mock.Anything, entry.LicensePlate, entry.Zone).Return(entry, nil)
+	}
+	for _, entry := range createdBlacklist {
+		am.blacklistRepo.On("GetByLicensePlate", mock.Anything, entry.LicensePlate, entry.Zone).Return(entry, nil)
+	}
+
+	result, err = again.Import(context.Background(), &restored, adminID)
+	require.NoError(t, err)
+	assert.Equal(t, ImportStats{Unchanged: 2}, result.Whitelist)
+	assert.Equal(t, ImportStats{Unchanged: 1}, result.Blacklist)
+	am.whitelistRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	am.whitelistRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	am.blacklistRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	am.blacklistRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	am.assertExpectations(t)
+}
+
+func TestService_Import(t *testing.T) {
+	adminID := uuid.New()
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name        string
+		snapshot    *Snapshot
+		mockSetup   func(*serviceMocks)
+		expectedErr error
+		expected    *ImportResult
+	}{
+		{
+			name:     "обновление причины существующей записи",
+			snapshot: &Snapshot{Blacklist: []Entry{{LicensePlate: "e001kx777", Reason: "Новая причина"}}},
+			mockSetup: func(m *serviceMocks) {
+				m.blacklistRepo.On("GetByLicensePlate", mock.Anything, "E001KX777", mock.Anything).Return(&domain.BlacklistEntry{
+					ID: uuid.New(), LicensePlate: "E001KX777", Reason: "Старая причина", AddedBy: uuid.New(), IsActive: true,
+				}, nil)
+				m.blacklistRepo.On("Update", mock.Anything, mock.MatchedBy(func(e *domain.BlacklistEntry) bool {
+					return e.Reason == "Новая причина"
+				})).Return(nil)
+			},
+			expected: &ImportResult{Blacklist: ImportStats{Updated: 1}},
+		},
+		{
+			name:     "запись той же зоны не найдена - номер в другой зоне добавляется отдельной записью",
+			snapshot: &Snapshot{Whitelist: []Entry{{LicensePlate: "A123BC777", Reason: "Скорая помощь", Zone: strPtr("north")}}},
+			mockSetup: func(m *serviceMocks) {
+				m.whitelistRepo.On("GetByLicensePlate", mock.Anything, "A123BC777", strPtr("north")).
+					Return(nil, domain.ErrWhitelistEntryNotFound)
+				m.whitelistRepo.On("Create", mock.Anything, mock.MatchedBy(func(e *domain.WhitelistEntry) bool {
+					return e.Zone != nil && *e.Zone == "north"
+				})).Return(nil)
+			},
+			expected: &ImportResult{Whitelist: ImportStats{Created: 1}},
+		},
+		{
+			name: "некорректная запись отклоняет импорт целиком",
+			snapshot: &Snapshot{
+				Whitelist: []Entry{{LicensePlate: "A123BC777", Reason: "Скорая помощь"}},
+				Blacklist: []Entry{{LicensePlate: "E001KX777"}},
+			},
+			mockSetup:   func(m *serviceMocks) {},
+			expectedErr: domain.ErrInvalidBlacklistData,
+		},
+		{
+			name: "истекшая запись черного списка отклоняется",
+			snapshot: &Snapshot{
+				Blacklist: []Entry{{LicensePlate: "E001KX777", Reason: "Угон", ExpiresAt: &past}},
+			},
+			mockSetup:   func(m *serviceMocks) {},
+			expectedErr: domain.ErrExpiryInPast,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestService()
+			tt.mockSetup(m)
+
+			result, err := svc.Import(context.Background(), tt.snapshot, adminID)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				m.whitelistRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func strPtr(s string) *string { return &s }
@@ -2,6 +2,7 @@ package pass
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -15,10 +16,48 @@ import (
 type CreatePassRequest struct {
 	UserID     uuid.UUID       `json:"user_id" validate:"required"`
 	PassType   domain.PassType `json:"pass_type" validate:"required"`
-	ValidFrom  time.Time       `json:"valid_from" validate:"required"`
+	ValidFrom  time.Time       `json:"valid_from"` // Пусто - с текущего момента (если не включен строгий режим)
 	ValidUntil *time.Time      `json:"valid_until,omitempty"`
 	VehicleIDs []uuid.UUID     `json:"vehicle_ids" validate:"required,min=1"`
 	CreatedBy  uuid.UUID       `json:"created_by" validate:"required"`
+
+	// Расписание: окно "HH:MM"-"HH:MM" и дни недели (0 - воскресенье); пусто - круглосуточно
+	AllowedTimeStart *string        `json:"allowed_time_start,omitempty"`
+	AllowedTimeEnd   *string        `json:"allowed_time_end,omitempty"`
+	AllowedWeekdays  []time.Weekday `json:"allowed_weekdays,omitempty"`
+
+	MaxUses *int `json:"max_uses,omitempty"` // Лимит проездов (1 - разовый пропуск); пусто - без ограничения
+
+	Zone *string `json:"zone,omitempty"` // Зона действия пропуска; пусто - на всех воротах
+}
+
+// CreateGuestPassRequest - запрос жителя на гостевой пропуск для посетителя
+type CreateGuestPassRequest struct {
+	UserID       uuid.UUID          `json:"-"` // Устанавливается из JWT
+	LicensePlate string             `json:"license_plate" validate:"required"`
+	VehicleType  domain.VehicleType `json:"vehicle_type,omitempty"`
+	ValidUntil   *time.Time         `json:"valid_until,omitempty"` // По умолчанию - now + GuestPassDuration
+}
+
+// Config содержит настройки PassService
+type Config struct {
+	GuestDailyLimit   int
+	GuestPassDuration time.Duration
+
+	RejectDuplicateVehicleLinks bool // Возвращать ошибку при повторной привязке автомобиля к пропуску
+
+	// RejectOverlappingPasses отклоняет выдачу пропуска, дублирующего действующий (ErrOverlappingPass);
+	// иначе вместо нового пропуска возвращается уже выданный
+	RejectOverlappingPasses bool
+
+	ExpiryInterval time.Duration // Период деактивации истекших временных пропусков (0 - отключено)
+
+	PlateCountry string // Страна формата номеров гостевых автомобилей (ISO 3166-1 alpha-2, пусто - без проверки)
+}
+
+// UnregisteredPlateInvalidator сбрасывает закэшированный отказ "Vehicle not registered" по номеру
+type UnregisteredPlateInvalidator interface {
+	Invalidate(ctx context.Context, licensePlate string) error
 }
 
 // Service содержит бизнес-логику работы с пропусками
@@ -27,7 +66,10 @@ type Service struct {
 	passVehicleRepo repository.PassVehicleRepository
 	userRepo        repository.UserRepository
 	vehicleRepo     repository.VehicleRepository
+	uow             repository.UnitOfWork
+	unregistered    UnregisteredPlateInvalidator // nil - кэш отказов отключен
 	logger          logger.Logger
+	config          Config
 }
 
 // NewService создает новый экземпляр PassService
@@ -36,14 +78,20 @@ func NewService(
 	passVehicleRepo repository.PassVehicleRepository,
 	userRepo repository.UserRepository,
 	vehicleRepo repository.VehicleRepository,
+	uow repository.UnitOfWork,
+	unregistered UnregisteredPlateInvalidator,
 	logger logger.Logger,
+	config Config,
 ) *Service {
 	return &Service{
 		passRepo:        passRepo,
 		passVehicleRepo: passVehicleRepo,
 		userRepo:        userRepo,
 		vehicleRepo:     vehicleRepo,
+		uow:             uow,
+		unregistered:    unregistered,
 		logger:          logger,
+		config:          config,
 	}
 }
 
@@ -54,92 +102,309 @@ func (s *Service) CreatePass(ctx context.Context, req *CreatePassRequest) (*doma
 		"pass_type": req.PassType,
 	})
 
-	// Проверяем, что пользователь существует
-	user, err := s.userRepo.GetByID(ctx, req.UserID)
-	if err != nil {
-		if err == domain.ErrUserNotFound {
-			return nil, domain.ErrUserNotFound
+	// Проверки, поиск пересечений и вставка пропуска с автомобилями идут в одной транзакции:
+	// при ошибке любого шага в БД ничего не остается
+	var pass *domain.Pass
+	created := false
+	err := s.uow.Do(ctx, func(repos repository.TxRepositories) error {
+		// Проверяем, что пользователь существует
+		user, err := repos.Users.GetByID(ctx, req.UserID)
+		if err != nil {
+			if err == domain.ErrUserNotFound {
+				return domain.ErrUserNotFound
+			}
+			return fmt.Errorf("failed to get user: %w", err)
 		}
-		return nil, fmt.Errorf("failed to get user: %w", err)
-	}
 
-	if !user.IsActive {
-		return nil, domain.ErrUserInactive
-	}
+		if !user.IsActive {
+			return domain.ErrUserInactive
+		}
 
-	// Проверяем, что все указанные автомобили существуют и принадлежат пользователю
-	for _, vehicleID := range req.VehicleIDs {
-		vehicle, err := s.vehicleRepo.GetByID(ctx, vehicleID)
-		if err != nil {
-			if err == domain.ErrVehicleNotFound {
-				return nil, fmt.Errorf("vehicle %s not found", vehicleID)
+		// Проверяем, что все указанные автомобили существуют и принадлежат пользователю
+		for _, vehicleID := range req.VehicleIDs {
+			vehicle, err := repos.Vehicles.GetByID(ctx, vehicleID)
+			if err != nil {
+				if err == domain.ErrVehicleNotFound {
+					return fmt.Errorf("vehicle %s: %w", vehicleID, domain.ErrVehicleNotFound)
+				}
+				return fmt.Errorf("failed to get vehicle: %w", err)
+			}
+
+			if vehicle.OwnerID != req.UserID {
+				return fmt.Errorf("vehicle %s, user %s: %w", vehicleID, req.UserID, domain.ErrPassVehicleOwnerMismatch)
+			}
+
+			if !vehicle.IsActive {
+				return fmt.Errorf("vehicle %s: %w", vehicleID, domain.ErrVehicleInactive)
 			}
-			return nil, fmt.Errorf("failed to get vehicle: %w", err)
 		}
 
-		if vehicle.OwnerID != req.UserID {
-			return nil, fmt.Errorf("vehicle %s does not belong to user %s", vehicleID, req.UserID)
+		// Создаем пропуск
+		candidate := &domain.Pass{
+			UserID:     req.UserID,
+			PassType:   req.PassType,
+			ValidFrom:  req.ValidFrom,
+			ValidUntil: req.ValidUntil,
+			IsActive:   true,
+			CreatedBy:  &req.CreatedBy,
+
+			AllowedTimeStart: req.AllowedTimeStart,
+			AllowedTimeEnd:   req.AllowedTimeEnd,
+			AllowedWeekdays:  req.AllowedWeekdays,
+			MaxUses:          req.MaxUses,
+			Zone:             req.Zone,
 		}
 
-		if !vehicle.IsActive {
-			return nil, fmt.Errorf("vehicle %s is inactive", vehicleID)
+		// Валидируем данные
+		if err := candidate.Validate(); err != nil {
+			return err
 		}
-	}
 
-	// Создаем пропуск
-	pass := &domain.Pass{
-		UserID:     req.UserID,
-		PassType:   req.PassType,
-		ValidFrom:  req.ValidFrom,
-		ValidUntil: req.ValidUntil,
-		IsActive:   true,
-		CreatedBy:  &req.CreatedBy,
-	}
+		// Действующий пропуск того же типа на те же автомобили и период уже выдан
+		// Проверка идет в той же транзакции, но без блокировки параллельные запросы все еще могут создать дубликат
+		overlapping, err := repos.Passes.FindOverlapping(ctx, candidate, req.VehicleIDs)
+		if err != nil {
+			return fmt.Errorf("failed to check overlapping passes: %w", err)
+		}
+		// Перепроверяем пересечение сроков, не полагаясь только на запрос
+		for _, existing := range overlapping {
+			if !existing.Overlaps(candidate) {
+				continue
+			}
+			s.logger.Warn("Overlapping pass already exists", map[string]interface{}{
+				"user_id":          req.UserID,
+				"existing_pass_id": existing.ID,
+			})
+			if s.config.RejectOverlappingPasses {
+				return domain.ErrOverlappingPass
+			}
+			pass = existing
+			return nil
+		}
 
-	// Валидируем данные
-	if err := pass.Validate(); err != nil {
+		// Сохраняем пропуск вместе с автомобилями: при ошибке привязки транзакция откатывается
+		if err := insertPassWithVehicles(ctx, repos, candidate, req.VehicleIDs); err != nil {
+			s.logger.Error("Failed to create pass", map[string]interface{}{
+				"error": err.Error(),
+			})
+			return fmt.Errorf("failed to create pass: %w", err)
+		}
+
+		pass, created = candidate, true
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
+	if !created {
+		return pass, nil
+	}
 
-	// Сохраняем пропуск в БД
-	if err := s.passRepo.Create(ctx, pass); err != nil {
-		s.logger.Error("Failed to create pass", map[string]interface{}{
-			"error": err.Error(),
-		})
-		return nil, fmt.Errorf("failed to create pass: %w", err)
+	s.logger.Info("Pass created successfully", map[string]interface{}{
+		"pass_id":        pass.ID,
+		"vehicles_count": len(req.VehicleIDs),
+	})
+
+	return pass, nil
+}
+
+// insertPassWithVehicles сохраняет пропуск и привязывает к нему автомобили репозиториями транзакции
+func insertPassWithVehicles(ctx context.Context, repos repository.TxRepositories, pass *domain.Pass, vehicleIDs []uuid.UUID) error {
+	if err := repos.Passes.Create(ctx, pass); err != nil {
+		return err
 	}
 
-	// Привязываем автомобили к пропуску
-	for _, vehicleID := range req.VehicleIDs {
+	for _, vehicleID := range vehicleIDs {
 		passVehicle := &domain.PassVehicle{
 			PassID:    pass.ID,
 			VehicleID: vehicleID,
-			AddedBy:   &req.CreatedBy,
+			AddedBy:   pass.CreatedBy,
+		}
+
+		if err := repos.PassVehicles.Create(ctx, passVehicle); err != nil {
+			if errors.Is(err, domain.ErrPassVehicleAlreadyExists) {
+				// Повтор vehicle_id в запросе - связь уже создана
+				continue
+			}
+			return fmt.Errorf("%w: vehicle %s: %v", domain.ErrPassVehicleLinkFailed, vehicleID, err)
+		}
+	}
+
+	return nil
+}
+
+// CreateGuestPass выдает временный гостевой пропуск от имени жителя
+// Номер гостя регистрируется как автомобиль, если его еще нет в системе; уже известный номер
+// привязывается к пропуску, за кем бы он ни был записан: гостевой пропуск действует для автомобиля
+// независимо от его владельца (см. GetActivePassesByUserAndVehicle)
+func (s *Service) CreateGuestPass(ctx context.Context, req *CreateGuestPassRequest) (*domain.Pass, error) {
+	s.logger.Info("Creating guest pass", map[string]interface{}{
+		"user_id":       req.UserID,
+		"license_plate": req.LicensePlate,
+	})
+
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	// Срок действия: по умолчанию максимальный, но не дольше него
+	maxValidUntil := now.Add(s.config.GuestPassDuration)
+	validUntil := maxValidUntil
+	if req.ValidUntil != nil {
+		if req.ValidUntil.After(maxValidUntil) || !req.ValidUntil.After(now) {
+			return nil, domain.ErrInvalidDateRange
+		}
+		validUntil = *req.ValidUntil
+	}
+
+	// Проверка лимита и вставка идут в одной транзакции под блокировкой строки жителя:
+	// параллельные запросы одного жителя выполняются по очереди и не превышают лимит
+	var pass *domain.Pass
+	err := s.uow.Do(ctx, func(repos repository.TxRepositories) error {
+		user, err := repos.Users.GetByIDForUpdate(ctx, req.UserID)
+		if err != nil {
+			if err == domain.ErrUserNotFound {
+				return domain.ErrUserNotFound
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+
+		if !user.IsActive {
+			return domain.ErrUserInactive
+		}
+
+		// Проверяем суточный лимит гостевых пропусков
+		issuedToday, err := repos.Passes.CountGuestPassesSince(ctx, user.ID, startOfDay)
+		if err != nil {
+			return fmt.Errorf("failed to count guest passes: %w", err)
+		}
+
+		if issuedToday >= s.config.GuestDailyLimit {
+			s.logger.Warn("Guest pass daily limit exceeded", map[string]interface{}{
+				"user_id": user.ID,
+				"limit":   s.config.GuestDailyLimit,
+			})
+			return domain.ErrGuestPassLimitExceeded
 		}
 
-		if err := s.passVehicleRepo.Create(ctx, passVehicle); err != nil {
-			s.logger.Error("Failed to add vehicle to pass", map[string]interface{}{
-				"pass_id":    pass.ID,
-				"vehicle_id": vehicleID,
+		guestVehicle, err := s.getOrCreateGuestVehicle(ctx, repos.Vehicles, user.ID, req)
+		if err != nil {
+			return err
+		}
+
+		candidate := &domain.Pass{
+			UserID:     user.ID,
+			PassType:   domain.PassTypeTemporary,
+			ValidFrom:  now,
+			ValidUntil: &validUntil,
+			IsActive:   true,
+			CreatedBy:  &user.ID,
+			IsGuest:    true,
+		}
+
+		if err := candidate.Validate(); err != nil {
+			return err
+		}
+
+		if err := insertPassWithVehicles(ctx, repos, candidate, []uuid.UUID{guestVehicle.ID}); err != nil {
+			s.logger.Error("Failed to create guest pass", map[string]interface{}{
+				"vehicle_id": guestVehicle.ID,
 				"error":      err.Error(),
 			})
-			// Продолжаем, даже если не удалось добавить один автомобиль
+			return fmt.Errorf("failed to create pass: %w", err)
 		}
+
+		candidate.Vehicles = []*domain.Vehicle{guestVehicle}
+		pass = candidate
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	s.logger.Info("Pass created successfully", map[string]interface{}{
-		"pass_id":        pass.ID,
-		"vehicles_count": len(req.VehicleIDs),
+	// Автомобиль гостя создается в транзакции мимо кэширующего repository, поэтому отказ,
+	// закэшированный до выдачи пропуска, сбрасывается здесь - уже после коммита.
+	// Ошибка инвалидации залогирована кэшем: запись истечет по TTL
+	if s.unregistered != nil {
+		_ = s.unregistered.Invalidate(ctx, pass.Vehicles[0].LicensePlate)
+	}
+
+	s.logger.Info("Guest pass created successfully", map[string]interface{}{
+		"pass_id":    pass.ID,
+		"vehicle_id": pass.Vehicles[0].ID,
 	})
 
 	return pass, nil
 }
 
+// getOrCreateGuestVehicle находит автомобиль гостя по номеру или регистрирует его
+// с минимальным набором данных на пригласившего жителя
+func (s *Service) getOrCreateGuestVehicle(ctx context.Context, vehicles repository.VehicleRepository, ownerID uuid.UUID, req *CreateGuestPassRequest) (*domain.Vehicle, error) {
+	existing, err := vehicles.GetByLicensePlate(ctx, req.LicensePlate)
+	if err != nil && err != domain.ErrVehicleNotFound {
+		return nil, fmt.Errorf("failed to get vehicle: %w", err)
+	}
+
+	if existing != nil {
+		if !existing.IsActive {
+			return nil, domain.ErrVehicleInactive
+		}
+		return existing, nil
+	}
+
+	vehicleType := req.VehicleType
+	if vehicleType == "" {
+		vehicleType = domain.VehicleTypeCar
+	}
+
+	guestVehicle := &domain.Vehicle{
+		OwnerID:      ownerID,
+		LicensePlate: req.LicensePlate,
+		VehicleType:  vehicleType,
+		IsActive:     true,
+	}
+
+	if err := guestVehicle.Validate(s.config.PlateCountry); err != nil {
+		return nil, err
+	}
+
+	if err := vehicles.Create(ctx, guestVehicle); err != nil {
+		s.logger.Error("Failed to create guest vehicle", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, fmt.Errorf("failed to create vehicle: %w", err)
+	}
+
+	return guestVehicle, nil
+}
+
 // GetPassByID возвращает пропуск по ID
 func (s *Service) GetPassByID(ctx context.Context, id uuid.UUID) (*domain.Pass, error) {
 	return s.passRepo.GetByID(ctx, id)
 }
 
+// GetPassWithVehicles возвращает пропуск по ID вместе с привязанными автомобилями
+func (s *Service) GetPassWithVehicles(ctx context.Context, id uuid.UUID) (*domain.Pass, error) {
+	return s.passRepo.GetByIDWithVehicles(ctx, id)
+}
+
+// LoadVehicles заполняет Vehicles у пропусков; для списков выполняется одним запросом
+func (s *Service) LoadVehicles(ctx context.Context, passes []*domain.Pass) error {
+	if err := s.passRepo.LoadVehicles(ctx, passes); err != nil {
+		return fmt.Errorf("failed to load pass vehicles: %w", err)
+	}
+	return nil
+}
+
+// ListPasses возвращает пропуска по фильтру с пагинацией (для админов и охраны)
+func (s *Service) ListPasses(ctx context.Context, filter domain.PassFilter, limit, offset int) ([]*domain.Pass, error) {
+	return s.passRepo.Query(ctx, filter, limit, offset)
+}
+
+// CountPasses возвращает количество пропусков по фильтру (для метаданных пагинации)
+func (s *Service) CountPasses(ctx context.Context, filter domain.PassFilter) (int, error) {
+	return s.passRepo.Count(ctx, filter)
+}
+
 // GetPassesByUser возвращает все пропуска пользователя
 func (s *Service) GetPassesByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Pass, error) {
 	return s.passRepo.GetByUserID(ctx, userID)
@@ -169,8 +434,11 @@ func (s *Service) RevokePass(ctx context.Context, passID, revokedBy uuid.UUID, r
 		return domain.ErrPassAlreadyRevoked
 	}
 
-	// Отзываем пропуск
+	// Отзываем пропуск; параллельный отзыв между проверкой и обновлением отсекается в репозитории
 	if err := s.passRepo.Revoke(ctx, passID, revokedBy, reason); err != nil {
+		if err == domain.ErrPassAlreadyRevoked || err == domain.ErrPassNotFound {
+			return err
+		}
 		s.logger.Error("Failed to revoke pass", map[string]interface{}{
 			"error": err.Error(),
 		})
@@ -184,6 +452,104 @@ func (s *Service) RevokePass(ctx context.Context, passID, revokedBy uuid.UUID, r
 	return nil
 }
 
+// ExtendPass продлевает временный пропуск до validUntil вместо отзыва и выдачи нового
+// Постоянные пропуска не продлеваются (ErrPassNotExtendable), отозванные - ErrPassAlreadyRevoked;
+// новый срок должен быть позже текущего и в будущем (ErrInvalidDateRange)
+func (s *Service) ExtendPass(ctx context.Context, passID, extendedBy uuid.UUID, validUntil time.Time) (*domain.Pass, error) {
+	s.logger.Info("Extending pass", map[string]interface{}{
+		"pass_id":     passID,
+		"extended_by": extendedBy,
+		"valid_until": validUntil,
+	})
+
+	pass, err := s.passRepo.GetByID(ctx, passID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pass.Extend(validUntil, extendedBy, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.passRepo.Extend(ctx, pass); err != nil {
+		switch err {
+		case domain.ErrPassNotFound, domain.ErrPassAlreadyRevoked, domain.ErrInvalidDateRange:
+			return nil, err
+		}
+		s.logger.Error("Failed to extend pass", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, fmt.Errorf("failed to extend pass: %w", err)
+	}
+
+	s.logger.Info("Pass extended successfully", map[string]interface{}{
+		"pass_id": passID,
+	})
+
+	return pass, nil
+}
+
+// ExpirePasses деактивирует временные пропуска с истекшим valid_until
+// Ошибка обновления одного пропуска не прерывает обработку остальных
+// Возвращает количество деактивированных пропусков
+func (s *Service) ExpirePasses(ctx context.Context) (int, error) {
+	passes, err := s.passRepo.GetExpiredPasses(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get expired passes: %w", err)
+	}
+
+	expired := 0
+	for _, pass := range passes {
+		// Постоянные пропуска не истекают; перепроверяем, не полагаясь только на запрос
+		if !pass.IsExpired() {
+			continue
+		}
+
+		pass.IsActive = false
+		if err := s.passRepo.Update(ctx, pass); err != nil {
+			s.logger.Error("Failed to deactivate expired pass", map[string]interface{}{
+				"pass_id": pass.ID,
+				"error":   err.Error(),
+			})
+			continue
+		}
+		expired++
+	}
+
+	if expired > 0 {
+		s.logger.Info("Expired passes deactivated", map[string]interface{}{
+			"count": expired,
+		})
+	}
+
+	return expired, nil
+}
+
+// RunExpiryWorker периодически деактивирует истекшие пропуска
+// Блокируется до отмены ctx; при нулевом интервале сразу возвращается
+func (s *Service) RunExpiryWorker(ctx context.Context) {
+	if s.config.ExpiryInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.config.ExpiryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Начатый проход доводится до конца и при остановке, иначе каждое обновление завершится ошибкой отмены
+			if _, err := s.ExpirePasses(context.WithoutCancel(ctx)); err != nil {
+				s.logger.Error("Pass expiry run failed", map[string]interface{}{
+					"error": err.Error(),
+				})
+			}
+		}
+	}
+}
+
 // AddVehicleToPass добавляет автомобиль к пропуску
 func (s *Service) AddVehicleToPass(ctx context.Context, passID, vehicleID, addedBy uuid.UUID) error {
 	// Проверяем, что пропуск существует
@@ -199,7 +565,11 @@ func (s *Service) AddVehicleToPass(ctx context.Context, passID, vehicleID, added
 	}
 
 	if vehicle.OwnerID != pass.UserID {
-		return fmt.Errorf("vehicle does not belong to pass owner")
+		return domain.ErrPassVehicleOwnerMismatch
+	}
+
+	if !vehicle.IsActive {
+		return domain.ErrVehicleInactive
 	}
 
 	// Создаем связь
@@ -209,7 +579,15 @@ func (s *Service) AddVehicleToPass(ctx context.Context, passID, vehicleID, added
 		AddedBy:   &addedBy,
 	}
 
-	return s.passVehicleRepo.Create(ctx, passVehicle)
+	if err := s.passVehicleRepo.Create(ctx, passVehicle); err != nil {
+		// В нестрогом режиме повторная привязка идемпотентна
+		if err == domain.ErrPassVehicleAlreadyExists && !s.config.RejectDuplicateVehicleLinks {
+			return nil
+		}
+		return err
+	}
+
+	return nil
 }
 
 // RemoveVehicleFromPass удаляет автомобиль из пропуска
@@ -0,0 +1,857 @@
+package pass
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/pkg/redis"
+	"github.com/frontandrew/gate/internal/repository"
+	"github.com/frontandrew/gate/internal/repository/cached"
+	"github.com/frontandrew/gate/internal/repository/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type serviceMocks struct {
+	passRepo        *mocks.MockPassRepository
+	passVehicleRepo *mocks.MockPassVehicleRepository
+	userRepo        *mocks.MockUserRepository
+	vehicleRepo     *mocks.MockVehicleRepository
+	uow             *mocks.MockUnitOfWork
+}
+
+func newTestService(config Config) (*Service, *serviceMocks) {
+	m := &serviceMocks{
+		passRepo:        new(mocks.MockPassRepository),
+		passVehicleRepo: new(mocks.MockPassVehicleRepository),
+		userRepo:        new(mocks.MockUserRepository),
+		vehicleRepo:     new(mocks.MockVehicleRepository),
+	}
+	// Репозитории транзакции - те же моки: сервис получает их из UnitOfWork
+	m.uow = &mocks.MockUnitOfWork{Repos: repository.TxRepositories{
+		Users:        m.userRepo,
+		Vehicles:     m.vehicleRepo,
+		Passes:       m.passRepo,
+		PassVehicles: m.passVehicleRepo,
+	}}
+
+	svc := NewService(m.passRepo, m.passVehicleRepo, m.userRepo, m.vehicleRepo, m.uow, nil, logger.NewNoop(), config)
+	return svc, m
+}
+
+func (m *serviceMocks) assertExpectations(t *testing.T) {
+	m.passRepo.AssertExpectations(t)
+	m.passVehicleRepo.AssertExpectations(t)
+	m.userRepo.AssertExpectations(t)
+	m.vehicleRepo.AssertExpectations(t)
+}
+
+func TestService_CreatePass(t *testing.T) {
+	userID := uuid.New()
+	adminID := uuid.New()
+	vehicleIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	owner := &domain.User{ID: userID, Role: domain.RoleUser, IsActive: true}
+
+	tests := []struct {
+		name              string
+		linkErr           error // Ошибка привязки второго автомобиля
+		expectedErr       error
+		expectedCommits   int
+		expectedRollbacks int
+	}{
+		{
+			name:            "пропуск и автомобили сохраняются одной транзакцией",
+			expectedCommits: 1,
+		},
+		{
+			name:            "повтор автомобиля в запросе не прерывает создание",
+			linkErr:         domain.ErrPassVehicleAlreadyExists,
+			expectedCommits: 1,
+		},
+		{
+			name:              "ошибка привязки автомобиля - транзакция откатывается",
+			linkErr:           errors.New("connection reset"),
+			expectedErr:       domain.ErrPassVehicleLinkFailed,
+			expectedRollbacks: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestService(Config{})
+
+			m.userRepo.On("GetByID", mock.Anything, userID).Return(owner, nil)
+			for _, id := range vehicleIDs {
+				m.vehicleRepo.On("GetByID", mock.Anything, id).
+					Return(&domain.Vehicle{ID: id, OwnerID: userID, IsActive: true}, nil)
+			}
+			m.passRepo.On("FindOverlapping", mock.Anything, mock.AnythingOfType("*domain.Pass"), vehicleIDs).Return(nil, nil)
+			m.passRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Pass")).Return(nil)
+			m.passVehicleRepo.On("Create", mock.Anything, mock.MatchedBy(func(pv *domain.PassVehicle) bool {
+				return pv.VehicleID == vehicleIDs[0]
+			})).Return(nil)
+			m.passVehicleRepo.On("Create", mock.Anything, mock.MatchedBy(func(pv *domain.PassVehicle) bool {
+				return pv.VehicleID == vehicleIDs[1]
+			})).Return(tt.linkErr)
+
+			p, err := svc.CreatePass(context.Background(), &CreatePassRequest{
+				UserID:     userID,
+				PassType:   domain.PassTypePermanent,
+				VehicleIDs: vehicleIDs,
+				CreatedBy:  adminID,
+			})
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Contains(t, err.Error(), vehicleIDs[1].String())
+				assert.Nil(t, p)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+			}
+
+			assert.Equal(t, tt.expectedCommits, m.uow.Committed)
+			assert.Equal(t, tt.expectedRollbacks, m.uow.RolledBack)
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestService_CreatePass_VehicleChecks(t *testing.T) {
+	userID := uuid.New()
+	vehicleID := uuid.New()
+
+	tests := []struct {
+		name        string
+		vehicle     *domain.Vehicle
+		vehicleErr  error
+		expectedErr error
+	}{
+		{
+			name:        "автомобиль не найден",
+			vehicleErr:  domain.ErrVehicleNotFound,
+			expectedErr: domain.ErrVehicleNotFound,
+		},
+		{
+			name:        "автомобиль другого владельца",
+			vehicle:     &domain.Vehicle{ID: vehicleID, OwnerID: uuid.New(), IsActive: true},
+			expectedErr: domain.ErrPassVehicleOwnerMismatch,
+		},
+		{
+			name:        "автомобиль деактивирован",
+			vehicle:     &domain.Vehicle{ID: vehicleID, OwnerID: userID, IsActive: false},
+			expectedErr: domain.ErrVehicleInactive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestService(Config{})
+
+			m.userRepo.On("GetByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleUser, IsActive: true}, nil)
+			m.vehicleRepo.On("GetByID", mock.Anything, vehicleID).Return(tt.vehicle, tt.vehicleErr)
+
+			p, err := svc.CreatePass(context.Background(), &CreatePassRequest{
+				UserID:     userID,
+				PassType:   domain.PassTypePermanent,
+				VehicleIDs: []uuid.UUID{vehicleID},
+				CreatedBy:  uuid.New(),
+			})
+
+			// Доменная ошибка сохраняется в цепочке: handler отвечает 4xx, а не 500
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Contains(t, err.Error(), vehicleID.String())
+			assert.Nil(t, p)
+			assert.Equal(t, 1, m.uow.RolledBack)
+			m.passRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestService_CreatePass_Rollback(t *testing.T) {
+	userID := uuid.New()
+	vehicleID := uuid.New()
+	req := &CreatePassRequest{
+		UserID:     userID,
+		PassType:   domain.PassTypePermanent,
+		VehicleIDs: []uuid.UUID{vehicleID},
+		CreatedBy:  uuid.New(),
+	}
+
+	t.Run("транзакция не открылась", func(t *testing.T) {
+		svc, m := newTestService(Config{})
+		m.uow.BeginErr = errors.New("connection refused")
+
+		p, err := svc.CreatePass(context.Background(), req)
+
+		assert.ErrorIs(t, err, m.uow.BeginErr)
+		assert.Nil(t, p)
+		m.assertExpectations(t)
+	})
+
+	t.Run("ошибка сохранения пропуска", func(t *testing.T) {
+		svc, m := newTestService(Config{})
+		m.userRepo.On("GetByID", mock.Anything, userID).Return(&domain.User{ID: userID, IsActive: true}, nil)
+		m.vehicleRepo.On("GetByID", mock.Anything, vehicleID).
+			Return(&domain.Vehicle{ID: vehicleID, OwnerID: userID, IsActive: true}, nil)
+		m.passRepo.On("FindOverlapping", mock.Anything, mock.AnythingOfType("*domain.Pass"), req.VehicleIDs).Return(nil, nil)
+		m.passRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Pass")).Return(errors.New("connection reset"))
+
+		p, err := svc.CreatePass(context.Background(), req)
+
+		assert.Error(t, err)
+		assert.Nil(t, p)
+		assert.Equal(t, 0, m.uow.Committed)
+		assert.Equal(t, 1, m.uow.RolledBack)
+		m.passVehicleRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		m.assertExpectations(t)
+	})
+
+	t.Run("неактивный пользователь - откат без записи", func(t *testing.T) {
+		svc, m := newTestService(Config{})
+		m.userRepo.On("GetByID", mock.Anything, userID).Return(&domain.User{ID: userID, IsActive: false}, nil)
+
+		_, err := svc.CreatePass(context.Background(), req)
+
+		assert.ErrorIs(t, err, domain.ErrUserInactive)
+		assert.Equal(t, 1, m.uow.RolledBack)
+		m.passRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		m.assertExpectations(t)
+	})
+}
+
+func TestService_CreatePass_Overlapping(t *testing.T) {
+	userID := uuid.New()
+	adminID := uuid.New()
+	vehicleIDs := []uuid.UUID{uuid.New()}
+	owner := &domain.User{ID: userID, Role: domain.RoleUser, IsActive: true}
+
+	day := func(n int) time.Time { return time.Date(2030, 1, n, 0, 0, 0, 0, time.UTC) }
+	existingPass := func(from, until time.Time) *domain.Pass {
+		return &domain.Pass{ID: uuid.New(), UserID: userID, PassType: domain.PassTypeTemporary,
+			ValidFrom: from, ValidUntil: &until, IsActive: true}
+	}
+	overlapping := existingPass(day(5), day(15))
+
+	tests := []struct {
+		name         string
+		reject       bool
+		candidates   []*domain.Pass
+		expectCreate bool
+		expectedPass *domain.Pass
+		expectedErr  error
+	}{
+		{
+			name:         "нет действующих пропусков",
+			reject:       true,
+			expectCreate: true,
+		},
+		{
+			name:        "пересекающийся срок отклоняется",
+			reject:      true,
+			candidates:  []*domain.Pass{overlapping},
+			expectedErr: domain.ErrOverlappingPass,
+		},
+		{
+			name:         "пересекающийся срок возвращает существующий пропуск",
+			candidates:   []*domain.Pass{overlapping},
+			expectedPass: overlapping,
+		},
+		{
+			name:         "смежный срок не пересекается",
+			reject:       true,
+			candidates:   []*domain.Pass{existingPass(day(1), day(10))},
+			expectCreate: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestService(Config{RejectOverlappingPasses: tt.reject})
+
+			m.userRepo.On("GetByID", mock.Anything, userID).Return(owner, nil)
+			m.vehicleRepo.On("GetByID", mock.Anything, vehicleIDs[0]).
+				Return(&domain.Vehicle{ID: vehicleIDs[0], OwnerID: userID, IsActive: true}, nil)
+			m.passRepo.On("FindOverlapping", mock.Anything, mock.AnythingOfType("*domain.Pass"), vehicleIDs).Return(tt.candidates, nil)
+			if tt.expectCreate {
+				m.passRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Pass")).Return(nil)
+				m.passVehicleRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.PassVehicle")).Return(nil)
+			}
+
+			validUntil := day(20)
+			p, err := svc.CreatePass(context.Background(), &CreatePassRequest{
+				UserID:     userID,
+				PassType:   domain.PassTypeTemporary,
+				ValidFrom:  day(10),
+				ValidUntil: &validUntil,
+				VehicleIDs: vehicleIDs,
+				CreatedBy:  adminID,
+			})
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, p)
+			} else {
+				require.NoError(t, err)
+				if tt.expectedPass != nil {
+					assert.Same(t, tt.expectedPass, p)
+				}
+			}
+			if !tt.expectCreate {
+				m.passRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			}
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestService_CreateGuestPass(t *testing.T) {
+	config := Config{GuestDailyLimit: 3, GuestPassDuration: 24 * time.Hour}
+	userID := uuid.New()
+	resident := &domain.User{ID: userID, Role: domain.RoleUser, IsActive: true}
+
+	tests := []struct {
+		name        string
+		req         *CreateGuestPassRequest
+		mockSetup   func(*serviceMocks)
+		expectedErr error
+		check       func(*testing.T, *domain.Pass, *serviceMocks)
+	}{
+		{
+			name: "успешное создание с регистрацией нового автомобиля",
+			req:  &CreateGuestPassRequest{UserID: userID, LicensePlate: "A123BC777"},
+			mockSetup: func(m *serviceMocks) {
+				m.userRepo.On("GetByIDForUpdate", mock.Anything, userID).Return(resident, nil)
+				m.passRepo.On("CountGuestPassesSince", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(0, nil)
+				m.vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(nil, domain.ErrVehicleNotFound)
+				m.vehicleRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Vehicle")).Return(nil)
+				m.passRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Pass")).Return(nil)
+				m.passVehicleRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.PassVehicle")).Return(nil)
+			},
+			check: func(t *testing.T, p *domain.Pass, m *serviceMocks) {
+				assert.Equal(t, domain.PassTypeTemporary, p.PassType)
+				assert.True(t, p.IsGuest)
+				assert.Equal(t, userID, p.UserID)
+				require.NotNil(t, p.CreatedBy)
+				assert.Equal(t, userID, *p.CreatedBy)
+				require.NotNil(t, p.ValidUntil)
+				assert.WithinDuration(t, time.Now().Add(config.GuestPassDuration), *p.ValidUntil, time.Minute)
+				require.Len(t, p.Vehicles, 1)
+				assert.Equal(t, userID, p.Vehicles[0].OwnerID)
+				assert.Equal(t, domain.VehicleTypeCar, p.Vehicles[0].VehicleType)
+				assert.Equal(t, 1, m.uow.Committed)
+			},
+		},
+		{
+			name: "превышен суточный лимит",
+			req:  &CreateGuestPassRequest{UserID: userID, LicensePlate: "A123BC777"},
+			mockSetup: func(m *serviceMocks) {
+				m.userRepo.On("GetByIDForUpdate", mock.Anything, userID).Return(resident, nil)
+				m.passRepo.On("CountGuestPassesSince", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(config.GuestDailyLimit, nil)
+			},
+			expectedErr: domain.ErrGuestPassLimitExceeded,
+			check: func(t *testing.T, p *domain.Pass, m *serviceMocks) {
+				m.passRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				assert.Equal(t, 1, m.uow.RolledBack)
+			},
+		},
+		{
+			name: "повторное использование уже зарегистрированного номера",
+			req:  &CreateGuestPassRequest{UserID: userID, LicensePlate: "A123BC777"},
+			mockSetup: func(m *serviceMocks) {
+				existing := &domain.Vehicle{
+					ID:           uuid.New(),
+					OwnerID:      userID,
+					LicensePlate: "A123BC777",
+					VehicleType:  domain.VehicleTypeCar,
+					IsActive:     true,
+				}
+				m.userRepo.On("GetByIDForUpdate", mock.Anything, userID).Return(resident, nil)
+				m.passRepo.On("CountGuestPassesSince", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(1, nil)
+				m.vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(existing, nil)
+				m.passRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Pass")).Return(nil)
+				m.passVehicleRepo.On("Create", mock.Anything, mock.MatchedBy(func(pv *domain.PassVehicle) bool {
+					return pv.VehicleID == existing.ID
+				})).Return(nil)
+			},
+			check: func(t *testing.T, p *domain.Pass, m *serviceMocks) {
+				m.vehicleRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				require.Len(t, p.Vehicles, 1)
+			},
+		},
+		{
+			name: "номер записан за другим жителем",
+			req:  &CreateGuestPassRequest{UserID: userID, LicensePlate: "A123BC777"},
+			mockSetup: func(m *serviceMocks) {
+				otherVehicle := &domain.Vehicle{
+					ID:           uuid.New(),
+					OwnerID:      uuid.New(),
+					LicensePlate: "A123BC777",
+					VehicleType:  domain.VehicleTypeCar,
+					IsActive:     true,
+				}
+				m.userRepo.On("GetByIDForUpdate", mock.Anything, userID).Return(resident, nil)
+				m.passRepo.On("CountGuestPassesSince", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(0, nil)
+				m.vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(otherVehicle, nil)
+				m.passRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Pass")).Return(nil)
+				m.passVehicleRepo.On("Create", mock.Anything, mock.MatchedBy(func(pv *domain.PassVehicle) bool {
+					return pv.VehicleID == otherVehicle.ID
+				})).Return(nil)
+			},
+			check: func(t *testing.T, p *domain.Pass, m *serviceMocks) {
+				// Пропуск выдан жителю, автомобиль остается за прежним владельцем
+				assert.Equal(t, userID, p.UserID)
+				assert.True(t, p.IsGuest)
+				require.Len(t, p.Vehicles, 1)
+				assert.NotEqual(t, userID, p.Vehicles[0].OwnerID)
+				m.vehicleRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name: "ошибка привязки откатывает транзакцию",
+			req:  &CreateGuestPassRequest{UserID: userID, LicensePlate: "A123BC777"},
+			mockSetup: func(m *serviceMocks) {
+				m.userRepo.On("GetByIDForUpdate", mock.Anything, userID).Return(resident, nil)
+				m.passRepo.On("CountGuestPassesSince", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(0, nil)
+				m.vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(nil, domain.ErrVehicleNotFound)
+				m.vehicleRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Vehicle")).Return(nil)
+				m.passRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Pass")).Return(nil)
+				m.passVehicleRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.PassVehicle")).Return(errors.New("connection reset"))
+			},
+			expectedErr: domain.ErrPassVehicleLinkFailed,
+			check: func(t *testing.T, p *domain.Pass, m *serviceMocks) {
+				assert.Equal(t, 0, m.uow.Committed)
+				assert.Equal(t, 1, m.uow.RolledBack)
+			},
+		},
+		{
+			name: "срок действия больше допустимого",
+			req: func() *CreateGuestPassRequest {
+				validUntil := time.Now().Add(48 * time.Hour)
+				return &CreateGuestPassRequest{UserID: userID, LicensePlate: "A123BC777", ValidUntil: &validUntil}
+			}(),
+			mockSetup:   func(m *serviceMocks) {},
+			expectedErr: domain.ErrInvalidDateRange,
+			check: func(t *testing.T, p *domain.Pass, m *serviceMocks) {
+				m.userRepo.AssertNotCalled(t, "GetByIDForUpdate", mock.Anything, mock.Anything)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestService(config)
+			tt.mockSetup(m)
+
+			p, err := svc.CreateGuestPass(context.Background(), tt.req)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, p)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+			}
+
+			if tt.check != nil {
+				tt.check(t, p, m)
+			}
+
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestService_CreateGuestPass_InvalidatesUnregisteredPlate(t *testing.T) {
+	mr := miniredis.RunT(t)
+	host, port, err := net.SplitHostPort(mr.Addr())
+	require.NoError(t, err)
+	client, err := redis.NewClient(redis.Config{Host: host, Port: port})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	ctx := context.Background()
+	unregistered := cached.NewUnregisteredPlateCache(client, nil, logger.NewNoop(), time.Minute)
+
+	// Гостя только что не пустили: отказ закэширован
+	unregistered.MarkUnregistered(ctx, "A123BC777", "gate-1")
+	require.True(t, unregistered.IsUnregistered(ctx, "A123BC777", "gate-1"))
+
+	svc, m := newTestService(Config{GuestDailyLimit: 3, GuestPassDuration: 24 * time.Hour})
+	svc.unregistered = unregistered
+
+	userID := uuid.New()
+	m.userRepo.On("GetByIDForUpdate", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleUser, IsActive: true}, nil)
+	m.passRepo.On("CountGuestPassesSince", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(0, nil)
+	m.vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(nil, domain.ErrVehicleNotFound)
+	m.vehicleRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Vehicle")).Return(nil)
+	m.passRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Pass")).Return(nil)
+	m.passVehicleRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.PassVehicle")).Return(nil)
+
+	_, err = svc.CreateGuestPass(ctx, &CreateGuestPassRequest{UserID: userID, LicensePlate: "A123BC777"})
+	require.NoError(t, err)
+
+	// Сразу после выдачи пропуска номер проверяется заново, а не по закэшированному отказу
+	assert.False(t, unregistered.IsUnregistered(ctx, "A123BC777", "gate-1"))
+	m.assertExpectations(t)
+}
+
+func TestService_AddVehicleToPass_Duplicate(t *testing.T) {
+	ownerID := uuid.New()
+	passID := uuid.New()
+	vehicleID := uuid.New()
+	addedBy := uuid.New()
+
+	tests := []struct {
+		name        string
+		config      Config
+		expectedErr error
+	}{
+		{
+			name:        "строгий режим отклоняет повторную привязку",
+			config:      Config{RejectDuplicateVehicleLinks: true},
+			expectedErr: domain.ErrPassVehicleAlreadyExists,
+		},
+		{
+			name:   "нестрогий режим считает повторную привязку успешной",
+			config: Config{RejectDuplicateVehicleLinks: false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestService(tt.config)
+			m.passRepo.On("GetByID", mock.Anything, passID).Return(&domain.Pass{ID: passID, UserID: ownerID}, nil)
+			m.vehicleRepo.On("GetByID", mock.Anything, vehicleID).Return(&domain.Vehicle{ID: vehicleID, OwnerID: ownerID, IsActive: true}, nil)
+			m.passVehicleRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.PassVehicle")).Return(domain.ErrPassVehicleAlreadyExists)
+
+			err := svc.AddVehicleToPass(context.Background(), passID, vehicleID, addedBy)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestService_AddVehicleToPass(t *testing.T) {
+	ownerID := uuid.New()
+	passID := uuid.New()
+	vehicleID := uuid.New()
+	addedBy := uuid.New()
+
+	tests := []struct {
+		name        string
+		vehicle     *domain.Vehicle
+		vehicleErr  error
+		expectLink  bool
+		expectedErr error
+	}{
+		{
+			name:       "автомобиль владельца пропуска",
+			vehicle:    &domain.Vehicle{ID: vehicleID, OwnerID: ownerID, IsActive: true},
+			expectLink: true,
+		},
+		{
+			name:        "автомобиль другого пользователя",
+			vehicle:     &domain.Vehicle{ID: vehicleID, OwnerID: uuid.New(), IsActive: true},
+			expectedErr: domain.ErrPassVehicleOwnerMismatch,
+		},
+		{
+			name:        "неактивный автомобиль",
+			vehicle:     &domain.Vehicle{ID: vehicleID, OwnerID: ownerID},
+			expectedErr: domain.ErrVehicleInactive,
+		},
+		{
+			name:        "автомобиль не найден",
+			vehicleErr:  domain.ErrVehicleNotFound,
+			expectedErr: domain.ErrVehicleNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestService(Config{RejectDuplicateVehicleLinks: true})
+			m.passRepo.On("GetByID", mock.Anything, passID).Return(&domain.Pass{ID: passID, UserID: ownerID}, nil)
+			m.vehicleRepo.On("GetByID", mock.Anything, vehicleID).Return(tt.vehicle, tt.vehicleErr)
+			if tt.expectLink {
+				m.passVehicleRepo.On("Create", mock.Anything, mock.MatchedBy(func(pv *domain.PassVehicle) bool {
+					return pv.PassID == passID && pv.VehicleID == vehicleID && *pv.AddedBy == addedBy
+				})).Return(nil)
+			}
+
+			err := svc.AddVehicleToPass(context.Background(), passID, vehicleID, addedBy)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				m.passVehicleRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+			}
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestService_LoadVehicles(t *testing.T) {
+	svc, m := newTestService(Config{})
+	first := &domain.Pass{ID: uuid.New()}
+	second := &domain.Pass{ID: uuid.New()}
+	passes := []*domain.Pass{first, second}
+	vehicle := &domain.Vehicle{ID: uuid.New(), LicensePlate: "A123BC777"}
+
+	// Автомобили всех пропусков загружаются одним вызовом репозитория
+	m.passRepo.On("LoadVehicles", mock.Anything, passes).
+		Run(func(args mock.Arguments) {
+			for _, p := range args.Get(1).([]*domain.Pass) {
+				p.Vehicles = []*domain.Vehicle{}
+			}
+			first.Vehicles = append(first.Vehicles, vehicle)
+		}).
+		Return(nil).Once()
+
+	require.NoError(t, svc.LoadVehicles(context.Background(), passes))
+
+	assert.Equal(t, []*domain.Vehicle{vehicle}, first.Vehicles)
+	assert.Empty(t, second.Vehicles)
+	m.assertExpectations(t)
+}
+
+func TestService_ListPasses(t *testing.T) {
+	userID := uuid.New()
+	active := true
+
+	tests := []struct {
+		name   string
+		filter domain.PassFilter
+		passes []*domain.Pass
+	}{
+		{
+			name:   "только активные",
+			filter: domain.PassFilter{IsActive: &active},
+			passes: []*domain.Pass{{ID: uuid.New(), UserID: uuid.New(), IsActive: true}},
+		},
+		{
+			name:   "пропуска пользователя",
+			filter: domain.PassFilter{UserID: &userID},
+			passes: []*domain.Pass{{ID: uuid.New(), UserID: userID}, {ID: uuid.New(), UserID: userID, IsActive: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestService(Config{})
+			m.passRepo.On("Query", mock.Anything, tt.filter, 20, 40).Return(tt.passes, nil)
+			m.passRepo.On("Count", mock.Anything, tt.filter).Return(len(tt.passes)+40, nil)
+
+			passes, err := svc.ListPasses(context.Background(), tt.filter, 20, 40)
+			require.NoError(t, err)
+			assert.Equal(t, tt.passes, passes)
+
+			total, err := svc.CountPasses(context.Background(), tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.passes)+40, total)
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestService_RevokePass_ConcurrentRevoke(t *testing.T) {
+	passID := uuid.New()
+	firstGuard := uuid.New()
+	secondGuard := uuid.New()
+
+	svc, m := newTestService(Config{})
+
+	// Оба охранника видят активный пропуск, но обновит его только первый
+	m.passRepo.On("GetByID", mock.Anything, passID).Return(&domain.Pass{ID: passID, IsActive: true}, nil).Twice()
+	m.passRepo.On("Revoke", mock.Anything, passID, firstGuard, "lost").Return(nil).Once()
+	m.passRepo.On("Revoke", mock.Anything, passID, secondGuard, "lost").Return(domain.ErrPassAlreadyRevoked).Once()
+
+	errs := make(chan error, 2)
+	for _, guard := range []uuid.UUID{firstGuard, secondGuard} {
+		go func(guard uuid.UUID) {
+			errs <- svc.RevokePass(context.Background(), passID, guard, "lost")
+		}(guard)
+	}
+
+	var succeeded, alreadyRevoked int
+	for i := 0; i < 2; i++ {
+		err := <-errs
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.Is(err, domain.ErrPassAlreadyRevoked):
+			alreadyRevoked++
+		default:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, 1, alreadyRevoked)
+	m.assertExpectations(t)
+}
+
+func TestService_ExpirePasses(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	expiredPass := &domain.Pass{ID: uuid.New(), PassType: domain.PassTypeTemporary, ValidUntil: &past, IsActive: true}
+	failingPass := &domain.Pass{ID: uuid.New(), PassType: domain.PassTypeTemporary, ValidUntil: &past, IsActive: true}
+	permanentPass := &domain.Pass{ID: uuid.New(), PassType: domain.PassTypePermanent, IsActive: true}
+	currentPass := &domain.Pass{ID: uuid.New(), PassType: domain.PassTypeTemporary, ValidUntil: &future, IsActive: true}
+
+	svc, m := newTestService(Config{})
+	m.passRepo.On("GetExpiredPasses", mock.Anything).
+		Return([]*domain.Pass{expiredPass, failingPass, permanentPass, currentPass}, nil)
+	m.passRepo.On("Update", mock.Anything, expiredPass).Return(nil)
+	m.passRepo.On("Update", mock.Anything, failingPass).Return(errors.New("db error"))
+
+	expired, err := svc.ExpirePasses(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, expired)
+	assert.False(t, expiredPass.IsActive)
+	assert.True(t, permanentPass.IsActive)
+	assert.True(t, currentPass.IsActive)
+	m.passRepo.AssertNotCalled(t, "Update", mock.Anything, permanentPass)
+	m.passRepo.AssertNotCalled(t, "Update", mock.Anything, currentPass)
+	m.assertExpectations(t)
+}
+
+// Остановка worker'а: отмена ctx завершает цикл, а начатый проход доводится до конца
+func TestService_RunExpiryWorker_Shutdown(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	expiredPass := &domain.Pass{ID: uuid.New(), PassType: domain.PassTypeTemporary, ValidUntil: &past, IsActive: true}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	svc, m := newTestService(Config{ExpiryInterval: 10 * time.Millisecond})
+	// Сигнал остановки приходит посреди прохода
+	m.passRepo.On("GetExpiredPasses", mock.Anything).
+		Run(func(mock.Arguments) { cancel() }).
+		Return([]*domain.Pass{expiredPass}, nil).Once()
+	notCanceled := mock.MatchedBy(func(ctx context.Context) bool { return ctx.Err() == nil })
+	m.passRepo.On("Update", notCanceled, expiredPass).Return(nil).Once()
+
+	done := make(chan struct{})
+	go func() {
+		svc.RunExpiryWorker(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("worker не завершился после отмены контекста")
+	}
+
+	assert.False(t, expiredPass.IsActive, "начатый проход завершается несмотря на отмену")
+	m.assertExpectations(t)
+}
+
+func TestService_ExtendPass(t *testing.T) {
+	passID := uuid.New()
+	adminID := uuid.New()
+	now := time.Now()
+	current := now.Add(time.Hour)
+	expired := now.Add(-time.Hour)
+	revokedAt := now.Add(-time.Minute)
+
+	tests := []struct {
+		name        string
+		pass        *domain.Pass
+		validUntil  time.Time
+		repoErr     error
+		expectSave  bool
+		expectedErr error
+	}{
+		{
+			name:       "продление временного пропуска",
+			pass:       &domain.Pass{ID: passID, PassType: domain.PassTypeTemporary, ValidUntil: &current, IsActive: true},
+			validUntil: now.Add(48 * time.Hour),
+			expectSave: true,
+		},
+		{
+			name:       "истекший пропуск снова активен",
+			pass:       &domain.Pass{ID: passID, PassType: domain.PassTypeTemporary, ValidUntil: &expired, IsActive: false},
+			validUntil: now.Add(24 * time.Hour),
+			expectSave: true,
+		},
+		{
+			name:        "срок в прошлом",
+			pass:        &domain.Pass{ID: passID, PassType: domain.PassTypeTemporary, ValidUntil: &expired, IsActive: false},
+			validUntil:  now.Add(-time.Minute),
+			expectedErr: domain.ErrInvalidDateRange,
+		},
+		{
+			name:        "срок раньше текущего",
+			pass:        &domain.Pass{ID: passID, PassType: domain.PassTypeTemporary, ValidUntil: &current, IsActive: true},
+			validUntil:  now.Add(30 * time.Minute),
+			expectedErr: domain.ErrInvalidDateRange,
+		},
+		{
+			name: "отозванный пропуск",
+			pass: &domain.Pass{ID: passID, PassType: domain.PassTypeTemporary, ValidUntil: &current,
+				IsActive: false, RevokedAt: &revokedAt},
+			validUntil:  now.Add(48 * time.Hour),
+			expectedErr: domain.ErrPassAlreadyRevoked,
+		},
+		{
+			name:        "постоянный пропуск",
+			pass:        &domain.Pass{ID: passID, PassType: domain.PassTypePermanent, IsActive: true},
+			validUntil:  now.Add(48 * time.Hour),
+			expectedErr: domain.ErrPassNotExtendable,
+		},
+		{
+			name:        "отозван параллельным запросом",
+			pass:        &domain.Pass{ID: passID, PassType: domain.PassTypeTemporary, ValidUntil: &current, IsActive: true},
+			validUntil:  now.Add(48 * time.Hour),
+			repoErr:     domain.ErrPassAlreadyRevoked,
+			expectSave:  true,
+			expectedErr: domain.ErrPassAlreadyRevoked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestService(Config{})
+			m.passRepo.On("GetByID", mock.Anything, passID).Return(tt.pass, nil)
+			if tt.expectSave {
+				m.passRepo.On("Extend", mock.Anything, tt.pass).Return(tt.repoErr)
+			}
+
+			extended, err := svc.ExtendPass(context.Background(), passID, adminID, tt.validUntil)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, extended)
+				if !tt.expectSave {
+					m.passRepo.AssertNotCalled(t, "Extend", mock.Anything, mock.Anything)
+				}
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, extended.ValidUntil)
+				assert.True(t, extended.ValidUntil.Equal(tt.validUntil))
+				assert.True(t, extended.IsActive)
+				assert.Equal(t, &adminID, extended.ExtendedBy)
+				assert.NotNil(t, extended.ExtendedAt)
+			}
+			m.assertExpectations(t)
+		})
+	}
+}
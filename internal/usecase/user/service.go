@@ -0,0 +1,200 @@
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/repository"
+	"github.com/google/uuid"
+)
+
+// disableAccessReason - причина отзыва пропусков при отключении доступа
+const disableAccessReason = "User access disabled"
+
+// UpdateUserRequest - изменение роли и активности пользователя администратором
+type UpdateUserRequest struct {
+	Role     *domain.UserRole `json:"role,omitempty"`
+	IsActive *bool            `json:"is_active,omitempty"`
+}
+
+// AccessTokenRevoker отзывает уже выданные access токены пользователя
+type AccessTokenRevoker interface {
+	RevokeUserAccessTokens(ctx context.Context, userID uuid.UUID) error
+}
+
+// Service содержит бизнес-логику администрирования пользователей
+type Service struct {
+	userRepo     repository.UserRepository
+	uow          repository.UnitOfWork
+	tokenRevoker AccessTokenRevoker
+	logger       logger.Logger
+}
+
+// NewService создает новый экземпляр UserService
+func NewService(userRepo repository.UserRepository, uow repository.UnitOfWork, tokenRevoker AccessTokenRevoker, logger logger.Logger) *Service {
+	return &Service{
+		userRepo:     userRepo,
+		uow:          uow,
+		tokenRevoker: tokenRevoker,
+		logger:       logger,
+	}
+}
+
+// DisableAccess отключает пользователю весь доступ одним действием (offboarding):
+// деактивирует пользователя и его автомобили, отзывает пропуска и сессии
+// Изменения применяются атомарно - частично отключенный пользователь не остается
+// Последнего активного администратора отключить нельзя (ErrLastAdmin)
+func (s *Service) DisableAccess(ctx context.Context, userID, disabledBy uuid.UUID) (*domain.UserDisableResult, error) {
+	s.logger.Info("Disabling user access", map[string]interface{}{
+		"user_id":     userID,
+		"disabled_by": disabledBy,
+	})
+
+	// Проверка "не последний администратор" и отключение идут в одной транзакции
+	// под блокировкой строк администраторов (см. ensureNotLastAdmin)
+	var result *domain.UserDisableResult
+	err := s.uow.Do(ctx, func(repos repository.TxRepositories) error {
+		user, err := repos.Users.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		// В том числе администратор не может отключить сам себя, если он последний
+		if user.IsAdmin() && user.IsActive {
+			if err := ensureNotLastAdmin(ctx, repos.Users); err != nil {
+				return err
+			}
+		}
+
+		result, err = repos.Users.DisableAccess(ctx, userID, disabledBy, disableAccessReason)
+		if err != nil {
+			if err == domain.ErrUserNotFound {
+				return err
+			}
+			s.logger.Error("Failed to disable user access", map[string]interface{}{
+				"user_id": userID,
+				"error":   err.Error(),
+			})
+			return fmt.Errorf("failed to disable user access: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Refresh токены уже отозваны в БД; access токены отклоняются через denylist.
+	// Ошибка не откатывает отключение: токены в любом случае истекут
+	if err := s.tokenRevoker.RevokeUserAccessTokens(ctx, userID); err != nil {
+		s.logger.Error("Failed to revoke user access tokens", map[string]interface{}{
+			"user_id": userID,
+			"error":   err.Error(),
+		})
+	}
+
+	s.logger.Info("User access disabled", map[string]interface{}{
+		"user_id":                result.UserID,
+		"vehicles_deactivated":   result.VehiclesDeactivated,
+		"passes_revoked":         result.PassesRevoked,
+		"refresh_tokens_revoked": result.RefreshTokensRevoked,
+	})
+
+	return result, nil
+}
+
+// ensureNotLastAdmin возвращает ErrLastAdmin, если активный администратор остался один
+// Вызывается в транзакции перед понижением или отключением активного администратора: строки администраторов
+// блокируются до ее конца, поэтому параллельное изменение двух последних администраторов не оставит ни одного
+func ensureNotLastAdmin(ctx context.Context, users repository.UserRepository) error {
+	admins, err := users.CountActiveByRoleForUpdate(ctx, domain.RoleAdmin)
+	if err != nil {
+		return fmt.Errorf("failed to count admins: %w", err)
+	}
+	if admins <= 1 {
+		return domain.ErrLastAdmin
+	}
+	return nil
+}
+
+// ListUsers возвращает пользователей с пагинацией (деактивированных - только при includeInactive)
+func (s *Service) ListUsers(ctx context.Context, limit, offset int, includeInactive bool) ([]*domain.User, error) {
+	return s.userRepo.List(ctx, limit, offset, includeInactive)
+}
+
+// GetUser возвращает пользователя по ID
+func (s *Service) GetUser(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	return s.userRepo.GetByID(ctx, id)
+}
+
+// UpdateUser меняет роль и/или активность пользователя
+// Последнего активного администратора нельзя понизить или деактивировать
+func (s *Service) UpdateUser(ctx context.Context, id uuid.UUID, req *UpdateUserRequest) (*domain.User, error) {
+	if req.Role != nil && !req.Role.IsValid() {
+		return nil, domain.ErrInvalidRole
+	}
+
+	// Проверка "не последний администратор" и изменение идут в одной транзакции (см. ensureNotLastAdmin)
+	var user *domain.User
+	revokeTokens := false
+	err := s.uow.Do(ctx, func(repos repository.TxRepositories) error {
+		var err error
+		user, err = repos.Users.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		newRole := user.Role
+		if req.Role != nil {
+			newRole = *req.Role
+		}
+		newActive := user.IsActive
+		if req.IsActive != nil {
+			newActive = *req.IsActive
+		}
+
+		if user.IsAdmin() && user.IsActive && (newRole != domain.RoleAdmin || !newActive) {
+			if err := ensureNotLastAdmin(ctx, repos.Users); err != nil {
+				return err
+			}
+		}
+
+		// Права в выданных токенах берутся из claims: при их сужении токены отзываются
+		revokeTokens = newRole != user.Role || (user.IsActive && !newActive)
+
+		user.Role = newRole
+		user.IsActive = newActive
+
+		if err := repos.Users.Update(ctx, user); err != nil {
+			if err == domain.ErrUserNotFound {
+				return err
+			}
+			s.logger.Error("Failed to update user", map[string]interface{}{
+				"user_id": id,
+				"error":   err.Error(),
+			})
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if revokeTokens {
+		if err := s.tokenRevoker.RevokeUserAccessTokens(ctx, id); err != nil {
+			s.logger.Error("Failed to revoke user access tokens", map[string]interface{}{
+				"user_id": id,
+				"error":   err.Error(),
+			})
+		}
+	}
+
+	s.logger.Info("User updated", map[string]interface{}{
+		"user_id":   id,
+		"role":      user.Role,
+		"is_active": user.IsActive,
+	})
+
+	return user, nil
+}
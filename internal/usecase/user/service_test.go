@@ -0,0 +1,209 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/repository"
+	"github.com/frontandrew/gate/internal/repository/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mockTokenRevoker мок для AccessTokenRevoker
+type mockTokenRevoker struct {
+	mock.Mock
+}
+
+func (m *mockTokenRevoker) RevokeUserAccessTokens(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func TestService_DisableAccess(t *testing.T) {
+	userID := uuid.New()
+	adminID := uuid.New()
+
+	tests := []struct {
+		name        string
+		mockSetup   func(*mocks.MockUserRepository, *mockTokenRevoker)
+		expectedErr error
+		check       func(*testing.T, *domain.UserDisableResult)
+	}{
+		{
+			name: "пользователь, автомобили, пропуска и сессии отключены",
+			mockSetup: func(m *mocks.MockUserRepository, r *mockTokenRevoker) {
+				m.On("GetByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleUser, IsActive: true}, nil)
+				m.On("DisableAccess", mock.Anything, userID, adminID, disableAccessReason).Return(&domain.UserDisableResult{
+					UserID:               userID,
+					VehiclesDeactivated:  2,
+					PassesRevoked:        3,
+					RefreshTokensRevoked: 1,
+				}, nil)
+				// Уже выданные access токены отзываются сразу
+				r.On("RevokeUserAccessTokens", mock.Anything, userID).Return(nil)
+			},
+			check: func(t *testing.T, result *domain.UserDisableResult) {
+				assert.Equal(t, userID, result.UserID)
+				assert.Equal(t, 2, result.VehiclesDeactivated)
+				assert.Equal(t, 3, result.PassesRevoked)
+				assert.Equal(t, 1, result.RefreshTokensRevoked)
+			},
+		},
+		{
+			name: "пользователь не найден",
+			mockSetup: func(m *mocks.MockUserRepository, r *mockTokenRevoker) {
+				m.On("GetByID", mock.Anything, userID).Return(nil, domain.ErrUserNotFound)
+			},
+			expectedErr: domain.ErrUserNotFound,
+		},
+		{
+			name: "последний активный администратор не отключается",
+			mockSetup: func(m *mocks.MockUserRepository, r *mockTokenRevoker) {
+				m.On("GetByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleAdmin, IsActive: true}, nil)
+				m.On("CountActiveByRoleForUpdate", mock.Anything, domain.RoleAdmin).Return(1, nil)
+			},
+			expectedErr: domain.ErrLastAdmin,
+		},
+		{
+			name: "администратор отключается, если есть другие",
+			mockSetup: func(m *mocks.MockUserRepository, r *mockTokenRevoker) {
+				m.On("GetByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: domain.RoleAdmin, IsActive: true}, nil)
+				m.On("CountActiveByRoleForUpdate", mock.Anything, domain.RoleAdmin).Return(2, nil)
+				m.On("DisableAccess", mock.Anything, userID, adminID, disableAccessReason).Return(&domain.UserDisableResult{UserID: userID}, nil)
+				r.On("RevokeUserAccessTokens", mock.Anything, userID).Return(nil)
+			},
+			check: func(t *testing.T, result *domain.UserDisableResult) {
+				assert.Equal(t, userID, result.UserID)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(mocks.MockUserRepository)
+			tokenRevoker := new(mockTokenRevoker)
+			tt.mockSetup(userRepo, tokenRevoker)
+
+			uow := &mocks.MockUnitOfWork{Repos: repository.TxRepositories{Users: userRepo}}
+			svc := NewService(userRepo, uow, tokenRevoker, logger.NewNoop())
+			result, err := svc.DisableAccess(context.Background(), userID, adminID)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, result)
+				assert.Zero(t, uow.Committed)
+			} else {
+				require.NoError(t, err)
+				tt.check(t, result)
+				// Проверка последнего администратора и отключение - одна транзакция
+				assert.Equal(t, 1, uow.Committed)
+			}
+
+			userRepo.AssertExpectations(t)
+			tokenRevoker.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_UpdateUser(t *testing.T) {
+	userID := uuid.New()
+	roleUser := domain.RoleUser
+	roleGuard := domain.RoleGuard
+	roleInvalid := domain.UserRole("superuser")
+	inactive := false
+
+	newUser := func(role domain.UserRole) *domain.User {
+		return &domain.User{ID: userID, Email: "user@test.com", FullName: "Test", Role: role, IsActive: true}
+	}
+
+	tests := []struct {
+		name        string
+		req         *UpdateUserRequest
+		mockSetup   func(*mocks.MockUserRepository, *mockTokenRevoker)
+		expectedErr error
+		check       func(*testing.T, *domain.User)
+	}{
+		{
+			name: "пользователь повышен до охранника, токены отозваны",
+			req:  &UpdateUserRequest{Role: &roleGuard},
+			mockSetup: func(m *mocks.MockUserRepository, r *mockTokenRevoker) {
+				m.On("GetByID", mock.Anything, userID).Return(newUser(domain.RoleUser), nil)
+				m.On("Update", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+					return u.Role == domain.RoleGuard && u.IsActive
+				})).Return(nil)
+				r.On("RevokeUserAccessTokens", mock.Anything, userID).Return(nil)
+			},
+			check: func(t *testing.T, u *domain.User) {
+				assert.Equal(t, domain.RoleGuard, u.Role)
+			},
+		},
+		{
+			name:        "неизвестная роль",
+			req:         &UpdateUserRequest{Role: &roleInvalid},
+			mockSetup:   func(m *mocks.MockUserRepository, r *mockTokenRevoker) {},
+			expectedErr: domain.ErrInvalidRole,
+		},
+		{
+			name: "понижение последнего админа запрещено",
+			req:  &UpdateUserRequest{Role: &roleUser},
+			mockSetup: func(m *mocks.MockUserRepository, r *mockTokenRevoker) {
+				m.On("GetByID", mock.Anything, userID).Return(newUser(domain.RoleAdmin), nil)
+				m.On("CountActiveByRoleForUpdate", mock.Anything, domain.RoleAdmin).Return(1, nil)
+			},
+			expectedErr: domain.ErrLastAdmin,
+		},
+		{
+			name: "деактивация последнего админа запрещена",
+			req:  &UpdateUserRequest{IsActive: &inactive},
+			mockSetup: func(m *mocks.MockUserRepository, r *mockTokenRevoker) {
+				m.On("GetByID", mock.Anything, userID).Return(newUser(domain.RoleAdmin), nil)
+				m.On("CountActiveByRoleForUpdate", mock.Anything, domain.RoleAdmin).Return(1, nil)
+			},
+			expectedErr: domain.ErrLastAdmin,
+		},
+		{
+			name: "админа можно понизить, если есть другие",
+			req:  &UpdateUserRequest{Role: &roleUser},
+			mockSetup: func(m *mocks.MockUserRepository, r *mockTokenRevoker) {
+				m.On("GetByID", mock.Anything, userID).Return(newUser(domain.RoleAdmin), nil)
+				m.On("CountActiveByRoleForUpdate", mock.Anything, domain.RoleAdmin).Return(2, nil)
+				m.On("Update", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil)
+				r.On("RevokeUserAccessTokens", mock.Anything, userID).Return(nil)
+			},
+			check: func(t *testing.T, u *domain.User) {
+				assert.Equal(t, domain.RoleUser, u.Role)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(mocks.MockUserRepository)
+			tokenRevoker := new(mockTokenRevoker)
+			tt.mockSetup(userRepo, tokenRevoker)
+
+			uow := &mocks.MockUnitOfWork{Repos: repository.TxRepositories{Users: userRepo}}
+			svc := NewService(userRepo, uow, tokenRevoker, logger.NewNoop())
+			u, err := svc.UpdateUser(context.Background(), userID, tt.req)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, u)
+				userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+				assert.Zero(t, uow.Committed)
+			} else {
+				require.NoError(t, err)
+				tt.check(t, u)
+				assert.Equal(t, 1, uow.Committed)
+			}
+
+			userRepo.AssertExpectations(t)
+			tokenRevoker.AssertExpectations(t)
+		})
+	}
+}
@@ -19,11 +19,24 @@ type CreateVehicleRequest struct {
 	Color        string             `json:"color,omitempty"`
 }
 
+// MergeVehiclesRequest - запрос на объединение дубликатов автомобилей
+type MergeVehiclesRequest struct {
+	SourceID        uuid.UUID `json:"source_id" validate:"required"`
+	TargetID        uuid.UUID `json:"target_id" validate:"required"`
+	AllowCrossOwner bool      `json:"allow_cross_owner"` // Разрешить объединение автомобилей разных владельцев
+}
+
+// Config содержит настройки сервиса автомобилей
+type Config struct {
+	PlateCountry string // Страна формата номеров (ISO 3166-1 alpha-2, пусто - без проверки формата)
+}
+
 // Service содержит бизнес-логику работы с автомобилями
 type Service struct {
 	vehicleRepo repository.VehicleRepository
 	userRepo    repository.UserRepository
 	logger      logger.Logger
+	config      Config
 }
 
 // NewService создает новый экземпляр VehicleService
@@ -31,11 +44,13 @@ func NewService(
 	vehicleRepo repository.VehicleRepository,
 	userRepo repository.UserRepository,
 	logger logger.Logger,
+	config Config,
 ) *Service {
 	return &Service{
 		vehicleRepo: vehicleRepo,
 		userRepo:    userRepo,
 		logger:      logger,
+		config:      config,
 	}
 }
 
@@ -83,12 +98,15 @@ func (s *Service) CreateVehicle(ctx context.Context, req *CreateVehicleRequest)
 	}
 
 	// Валидируем данные
-	if err := vehicle.Validate(); err != nil {
+	if err := vehicle.Validate(s.config.PlateCountry); err != nil {
 		return nil, err
 	}
 
 	// Сохраняем в БД
 	if err := s.vehicleRepo.Create(ctx, vehicle); err != nil {
+		if err == domain.ErrVehicleAlreadyExists {
+			return nil, err
+		}
 		s.logger.Error("Failed to create vehicle", map[string]interface{}{
 			"error": err.Error(),
 		})
@@ -103,13 +121,44 @@ func (s *Service) CreateVehicle(ctx context.Context, req *CreateVehicleRequest)
 }
 
 // GetVehicleByID возвращает автомобиль по ID
-func (s *Service) GetVehicleByID(ctx context.Context, id uuid.UUID) (*domain.Vehicle, error) {
-	return s.vehicleRepo.GetByID(ctx, id)
+// Деактивированный автомобиль возвращается только при includeInactive, иначе - ErrVehicleNotFound
+func (s *Service) GetVehicleByID(ctx context.Context, id uuid.UUID, includeInactive bool) (*domain.Vehicle, error) {
+	if includeInactive {
+		return s.vehicleRepo.GetByID(ctx, id)
+	}
+	return s.vehicleRepo.GetActiveByID(ctx, id)
+}
+
+// GetVehiclesByOwner возвращает автомобили пользователя (деактивированные - только при includeInactive)
+func (s *Service) GetVehiclesByOwner(ctx context.Context, ownerID uuid.UUID, includeInactive bool) ([]*domain.Vehicle, error) {
+	return s.vehicleRepo.GetByOwnerID(ctx, ownerID, includeInactive)
+}
+
+// ListVehicles возвращает автомобили по фильтру с пагинацией (обзор автопарка для админов)
+func (s *Service) ListVehicles(ctx context.Context, filter domain.VehicleFilter, limit, offset int) ([]*domain.Vehicle, error) {
+	if filter.IsEmpty() {
+		return s.vehicleRepo.List(ctx, limit, offset)
+	}
+	return s.vehicleRepo.Search(ctx, filter, limit, offset)
 }
 
-// GetVehiclesByOwner возвращает все автомобили пользователя
-func (s *Service) GetVehiclesByOwner(ctx context.Context, ownerID uuid.UUID) ([]*domain.Vehicle, error) {
-	return s.vehicleRepo.GetByOwnerID(ctx, ownerID)
+// CountVehicles возвращает количество автомобилей по фильтру (для метаданных пагинации)
+func (s *Service) CountVehicles(ctx context.Context, filter domain.VehicleFilter) (int, error) {
+	return s.vehicleRepo.Count(ctx, filter)
+}
+
+// MinPlateSearchLength - минимальная длина части номера для поиска:
+// более короткий запрос совпадает с большей частью автопарка
+const MinPlateSearchLength = 3
+
+// SearchVehiclesByPlate ищет автомобили по части номера (оператор знает номер не полностью)
+// Деактивированные автомобили попадают в выдачу только при includeInactive
+func (s *Service) SearchVehiclesByPlate(ctx context.Context, plate string, limit int, includeInactive bool) ([]*domain.Vehicle, error) {
+	pattern := domain.NormalizeLicensePlate(plate)
+	if len([]rune(pattern)) < MinPlateSearchLength {
+		return nil, domain.ErrPlateQueryTooShort
+	}
+	return s.vehicleRepo.SearchByLicensePlate(ctx, pattern, limit, includeInactive)
 }
 
 // GetVehicleByLicensePlate возвращает автомобиль по номеру
@@ -118,16 +167,103 @@ func (s *Service) GetVehicleByLicensePlate(ctx context.Context, licensePlate str
 }
 
 // UpdateVehicle обновляет данные автомобиля
+// Формат страны проверяется, только если меняется номер: автомобили с иностранными
+// или старыми номерами можно обновлять и деактивировать
 func (s *Service) UpdateVehicle(ctx context.Context, vehicle *domain.Vehicle) error {
+	existing, err := s.vehicleRepo.GetByID(ctx, vehicle.ID)
+	if err != nil {
+		return err
+	}
+
+	plateCountry := s.config.PlateCountry
+	if domain.NormalizeLicensePlate(vehicle.LicensePlate) == existing.LicensePlate {
+		plateCountry = ""
+	}
+
 	// Валидируем данные
-	if err := vehicle.Validate(); err != nil {
+	if err := vehicle.Validate(plateCountry); err != nil {
 		return err
 	}
 
 	return s.vehicleRepo.Update(ctx, vehicle)
 }
 
-// DeleteVehicle удаляет автомобиль (мягкое удаление)
-func (s *Service) DeleteVehicle(ctx context.Context, id uuid.UUID) error {
-	return s.vehicleRepo.Delete(ctx, id)
+// DeleteVehicle удаляет автомобиль: по умолчанию мягко (is_active = false),
+// при hard - физически, вместе со связями с пропусками
+func (s *Service) DeleteVehicle(ctx context.Context, id uuid.UUID, hard bool) (*domain.VehicleDeleteResult, error) {
+	if !hard {
+		if err := s.vehicleRepo.Delete(ctx, id); err != nil {
+			return nil, err
+		}
+		return &domain.VehicleDeleteResult{VehicleID: id}, nil
+	}
+
+	result, err := s.vehicleRepo.HardDelete(ctx, id)
+	if err != nil {
+		if err == domain.ErrVehicleNotFound {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to hard delete vehicle: %w", err)
+	}
+
+	s.logger.Info("Vehicle hard deleted", map[string]interface{}{
+		"vehicle_id":           id,
+		"pass_links_removed":   result.PassLinksRemoved,
+		"access_logs_detached": result.AccessLogsDetached,
+	})
+
+	return result, nil
+}
+
+// MergeVehicles объединяет дубликаты: связи с пропусками и журнал проездов
+// переходят на target, source деактивируется
+// При объединении разных владельцев (AllowCrossOwner) пропуска прежнего владельца на target не переходят
+func (s *Service) MergeVehicles(ctx context.Context, req *MergeVehiclesRequest) (*domain.VehicleMergeResult, error) {
+	s.logger.Info("Merging vehicles", map[string]interface{}{
+		"source_id": req.SourceID,
+		"target_id": req.TargetID,
+	})
+
+	if req.SourceID == uuid.Nil || req.TargetID == uuid.Nil || req.SourceID == req.TargetID {
+		return nil, domain.ErrInvalidVehicleData
+	}
+
+	source, err := s.vehicleRepo.GetByID(ctx, req.SourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := s.vehicleRepo.GetByID(ctx, req.TargetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !target.IsActive {
+		return nil, domain.ErrVehicleInactive
+	}
+
+	if source.OwnerID != target.OwnerID && !req.AllowCrossOwner {
+		return nil, domain.ErrVehicleOwnerMismatch
+	}
+
+	result, err := s.vehicleRepo.Merge(ctx, source.ID, target.ID)
+	if err != nil {
+		s.logger.Error("Failed to merge vehicles", map[string]interface{}{
+			"source_id": source.ID,
+			"target_id": target.ID,
+			"error":     err.Error(),
+		})
+		return nil, fmt.Errorf("failed to merge vehicles: %w", err)
+	}
+
+	s.logger.Info("Vehicles merged successfully", map[string]interface{}{
+		"source_id":          result.SourceID,
+		"target_id":          result.TargetID,
+		"pass_links_moved":   result.PassLinksMoved,
+		"pass_links_dropped": result.PassLinksDropped,
+		"pass_links_revoked": result.PassLinksRevoked,
+		"access_logs_moved":  result.AccessLogsMoved,
+	})
+
+	return result, nil
 }
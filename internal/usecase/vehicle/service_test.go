@@ -0,0 +1,448 @@
+package vehicle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/repository/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_MergeVehicles(t *testing.T) {
+	ownerID := uuid.New()
+	source := &domain.Vehicle{ID: uuid.New(), OwnerID: ownerID, LicensePlate: "A123BC77", IsActive: true}
+	target := &domain.Vehicle{ID: uuid.New(), OwnerID: ownerID, LicensePlate: "A123BC777", IsActive: true}
+	foreignTarget := &domain.Vehicle{ID: uuid.New(), OwnerID: uuid.New(), LicensePlate: "B456CD777", IsActive: true}
+	inactiveTarget := &domain.Vehicle{ID: uuid.New(), OwnerID: ownerID, LicensePlate: "C789EK777", IsActive: false}
+
+	tests := []struct {
+		name        string
+		req         *MergeVehiclesRequest
+		mockSetup   func(*mocks.MockVehicleRepository)
+		expectedErr error
+		check       func(*testing.T, *domain.VehicleMergeResult, *mocks.MockVehicleRepository)
+	}{
+		{
+			name: "связи и журнал переносятся, source деактивируется",
+			req:  &MergeVehiclesRequest{SourceID: source.ID, TargetID: target.ID},
+			mockSetup: func(m *mocks.MockVehicleRepository) {
+				m.On("GetByID", mock.Anything, source.ID).Return(source, nil)
+				m.On("GetByID", mock.Anything, target.ID).Return(target, nil)
+				m.On("Merge", mock.Anything, source.ID, target.ID).Return(&domain.VehicleMergeResult{
+					SourceID:        source.ID,
+					TargetID:        target.ID,
+					PassLinksMoved:  2,
+					AccessLogsMoved: 5,
+				}, nil)
+			},
+			check: func(t *testing.T, result *domain.VehicleMergeResult, m *mocks.MockVehicleRepository) {
+				assert.Equal(t, 2, result.PassLinksMoved)
+				assert.Equal(t, 5, result.AccessLogsMoved)
+			},
+		},
+		{
+			name: "разные владельцы без флага",
+			req:  &MergeVehiclesRequest{SourceID: source.ID, TargetID: foreignTarget.ID},
+			mockSetup: func(m *mocks.MockVehicleRepository) {
+				m.On("GetByID", mock.Anything, source.ID).Return(source, nil)
+				m.On("GetByID", mock.Anything, foreignTarget.ID).Return(foreignTarget, nil)
+			},
+			expectedErr: domain.ErrVehicleOwnerMismatch,
+			check: func(t *testing.T, result *domain.VehicleMergeResult, m *mocks.MockVehicleRepository) {
+				m.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name: "разные владельцы с флагом",
+			req:  &MergeVehiclesRequest{SourceID: source.ID, TargetID: foreignTarget.ID, AllowCrossOwner: true},
+			mockSetup: func(m *mocks.MockVehicleRepository) {
+				m.On("GetByID", mock.Anything, source.ID).Return(source, nil)
+				m.On("GetByID", mock.Anything, foreignTarget.ID).Return(foreignTarget, nil)
+				m.On("Merge", mock.Anything, source.ID, foreignTarget.ID).Return(&domain.VehicleMergeResult{
+					SourceID: source.ID,
+					TargetID: foreignTarget.ID,
+				}, nil)
+			},
+		},
+		{
+			name: "неактивный target",
+			req:  &MergeVehiclesRequest{SourceID: source.ID, TargetID: inactiveTarget.ID},
+			mockSetup: func(m *mocks.MockVehicleRepository) {
+				m.On("GetByID", mock.Anything, source.ID).Return(source, nil)
+				m.On("GetByID", mock.Anything, inactiveTarget.ID).Return(inactiveTarget, nil)
+			},
+			expectedErr: domain.ErrVehicleInactive,
+			check: func(t *testing.T, result *domain.VehicleMergeResult, m *mocks.MockVehicleRepository) {
+				m.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name: "source не найден",
+			req:  &MergeVehiclesRequest{SourceID: source.ID, TargetID: target.ID},
+			mockSetup: func(m *mocks.MockVehicleRepository) {
+				m.On("GetByID", mock.Anything, source.ID).Return(nil, domain.ErrVehicleNotFound)
+			},
+			expectedErr: domain.ErrVehicleNotFound,
+		},
+		{
+			name:        "объединение с самим собой",
+			req:         &MergeVehiclesRequest{SourceID: source.ID, TargetID: source.ID},
+			mockSetup:   func(m *mocks.MockVehicleRepository) {},
+			expectedErr: domain.ErrInvalidVehicleData,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vehicleRepo := new(mocks.MockVehicleRepository)
+			userRepo := new(mocks.MockUserRepository)
+			tt.mockSetup(vehicleRepo)
+
+			svc := NewService(vehicleRepo, userRepo, logger.NewNoop(), Config{})
+			result, err := svc.MergeVehicles(context.Background(), tt.req)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, result)
+			}
+
+			if tt.check != nil {
+				tt.check(t, result, vehicleRepo)
+			}
+
+			vehicleRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_DeleteVehicle(t *testing.T) {
+	vehicleID := uuid.New()
+
+	tests := []struct {
+		name        string
+		hard        bool
+		mockSetup   func(*mocks.MockVehicleRepository)
+		expectedErr error
+		check       func(*testing.T, *domain.VehicleDeleteResult, *mocks.MockVehicleRepository)
+	}{
+		{
+			name: "мягкое удаление по умолчанию",
+			mockSetup: func(m *mocks.MockVehicleRepository) {
+				m.On("Delete", mock.Anything, vehicleID).Return(nil)
+			},
+			check: func(t *testing.T, result *domain.VehicleDeleteResult, m *mocks.MockVehicleRepository) {
+				assert.False(t, result.Hard)
+				m.AssertNotCalled(t, "HardDelete", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name: "физическое удаление убирает связи с пропусками",
+			hard: true,
+			mockSetup: func(m *mocks.MockVehicleRepository) {
+				m.On("HardDelete", mock.Anything, vehicleID).Return(&domain.VehicleDeleteResult{
+					VehicleID:          vehicleID,
+					Hard:               true,
+					PassLinksRemoved:   2,
+					AccessLogsDetached: 7,
+				}, nil)
+			},
+			check: func(t *testing.T, result *domain.VehicleDeleteResult, m *mocks.MockVehicleRepository) {
+				assert.True(t, result.Hard)
+				assert.Equal(t, 2, result.PassLinksRemoved)
+				assert.Equal(t, 7, result.AccessLogsDetached)
+				m.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name: "физическое удаление несуществующего автомобиля",
+			hard: true,
+			mockSetup: func(m *mocks.MockVehicleRepository) {
+				m.On("HardDelete", mock.Anything, vehicleID).Return(nil, domain.ErrVehicleNotFound)
+			},
+			expectedErr: domain.ErrVehicleNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vehicleRepo := new(mocks.MockVehicleRepository)
+			userRepo := new(mocks.MockUserRepository)
+			tt.mockSetup(vehicleRepo)
+
+			svc := NewService(vehicleRepo, userRepo, logger.NewNoop(), Config{})
+			result, err := svc.DeleteVehicle(context.Background(), vehicleID, tt.hard)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, result)
+			}
+
+			if tt.check != nil {
+				tt.check(t, result, vehicleRepo)
+			}
+
+			vehicleRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// Номер занят между проверкой и вставкой: ErrVehicleAlreadyExists не оборачивается (обработчик отвечает 409)
+func TestService_CreateVehicle_ConcurrentDuplicate(t *testing.T) {
+	ownerID := uuid.New()
+	userRepo := new(mocks.MockUserRepository)
+	userRepo.On("GetByID", mock.Anything, ownerID).Return(&domain.User{ID: ownerID, IsActive: true}, nil)
+	vehicleRepo := new(mocks.MockVehicleRepository)
+	vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(nil, domain.ErrVehicleNotFound)
+	vehicleRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Vehicle")).Return(domain.ErrVehicleAlreadyExists)
+
+	svc := NewService(vehicleRepo, userRepo, logger.NewNoop(), Config{})
+	result, err := svc.CreateVehicle(context.Background(), &CreateVehicleRequest{
+		OwnerID:      ownerID,
+		LicensePlate: "A123BC777",
+		VehicleType:  domain.VehicleTypeCar,
+	})
+
+	assert.Equal(t, domain.ErrVehicleAlreadyExists, err)
+	assert.Nil(t, result)
+	vehicleRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
+
+// Формат номера проверяется по стране из конфигурации; номер сохраняется латиницей
+func TestService_CreateVehicle_PlateCountry(t *testing.T) {
+	ownerID := uuid.New()
+	newService := func() (*Service, *mocks.MockVehicleRepository) {
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetByID", mock.Anything, ownerID).Return(&domain.User{ID: ownerID, IsActive: true}, nil)
+		vehicleRepo := new(mocks.MockVehicleRepository)
+		vehicleRepo.On("GetByLicensePlate", mock.Anything, mock.Anything).Return(nil, domain.ErrVehicleNotFound)
+		return NewService(vehicleRepo, userRepo, logger.NewNoop(), Config{PlateCountry: "RU"}), vehicleRepo
+	}
+
+	t.Run("номер кириллицей", func(t *testing.T) {
+		svc, vehicleRepo := newService()
+		vehicleRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Vehicle")).Return(nil)
+
+		result, err := svc.CreateVehicle(context.Background(), &CreateVehicleRequest{
+			OwnerID:      ownerID,
+			LicensePlate: "А123ВС777",
+			VehicleType:  domain.VehicleTypeCar,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "A123BC777", result.LicensePlate)
+	})
+
+	t.Run("номер не по формату страны", func(t *testing.T) {
+		svc, vehicleRepo := newService()
+
+		result, err := svc.CreateVehicle(context.Background(), &CreateVehicleRequest{
+			OwnerID:      ownerID,
+			LicensePlate: "B456CD777",
+			VehicleType:  domain.VehicleTypeCar,
+		})
+
+		assert.Equal(t, domain.ErrInvalidLicensePlate, err)
+		assert.Nil(t, result)
+		vehicleRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_UpdateVehicle_PlateCountry(t *testing.T) {
+	vehicleID := uuid.New()
+	ownerID := uuid.New()
+	newService := func() (*Service, *mocks.MockVehicleRepository) {
+		vehicleRepo := new(mocks.MockVehicleRepository)
+		vehicleRepo.On("GetByID", mock.Anything, vehicleID).Return(&domain.Vehicle{
+			ID:           vehicleID,
+			OwnerID:      ownerID,
+			LicensePlate: "B456CD777",
+			VehicleType:  domain.VehicleTypeCar,
+			IsActive:     true,
+		}, nil)
+		return NewService(vehicleRepo, new(mocks.MockUserRepository), logger.NewNoop(), Config{PlateCountry: "RU"}), vehicleRepo
+	}
+
+	t.Run("старый номер без изменений", func(t *testing.T) {
+		svc, vehicleRepo := newService()
+		vehicleRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Vehicle")).Return(nil)
+
+		err := svc.UpdateVehicle(context.Background(), &domain.Vehicle{
+			ID:           vehicleID,
+			OwnerID:      ownerID,
+			LicensePlate: "B456CD777",
+			VehicleType:  domain.VehicleTypeCar,
+			Color:        "Синий",
+		})
+
+		require.NoError(t, err)
+		vehicleRepo.AssertExpectations(t)
+	})
+
+	t.Run("новый номер не по формату страны", func(t *testing.T) {
+		svc, vehicleRepo := newService()
+
+		err := svc.UpdateVehicle(context.Background(), &domain.Vehicle{
+			ID:           vehicleID,
+			OwnerID:      ownerID,
+			LicensePlate: "C789EF777",
+			VehicleType:  domain.VehicleTypeCar,
+		})
+
+		assert.Equal(t, domain.ErrInvalidLicensePlate, err)
+		vehicleRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_GetVehicleByID(t *testing.T) {
+	vehicleID := uuid.New()
+	inactive := &domain.Vehicle{ID: vehicleID, LicensePlate: "A123BC777", IsActive: false}
+
+	t.Run("по умолчанию деактивированный автомобиль не найден", func(t *testing.T) {
+		vehicleRepo := new(mocks.MockVehicleRepository)
+		vehicleRepo.On("GetActiveByID", mock.Anything, vehicleID).Return(nil, domain.ErrVehicleNotFound)
+
+		svc := NewService(vehicleRepo, new(mocks.MockUserRepository), logger.NewNoop(), Config{})
+		result, err := svc.GetVehicleByID(context.Background(), vehicleID, false)
+
+		assert.ErrorIs(t, err, domain.ErrVehicleNotFound)
+		assert.Nil(t, result)
+		vehicleRepo.AssertExpectations(t)
+		vehicleRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("includeInactive возвращает деактивированный автомобиль", func(t *testing.T) {
+		vehicleRepo := new(mocks.MockVehicleRepository)
+		vehicleRepo.On("GetByID", mock.Anything, vehicleID).Return(inactive, nil)
+
+		svc := NewService(vehicleRepo, new(mocks.MockUserRepository), logger.NewNoop(), Config{})
+		result, err := svc.GetVehicleByID(context.Background(), vehicleID, true)
+
+		require.NoError(t, err)
+		assert.Equal(t, inactive, result)
+		vehicleRepo.AssertExpectations(t)
+		vehicleRepo.AssertNotCalled(t, "GetActiveByID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_ListVehicles(t *testing.T) {
+	ownerID := uuid.New()
+	active := true
+	vehicles := []*domain.Vehicle{{ID: uuid.New(), OwnerID: ownerID, LicensePlate: "A123BC777", IsActive: true}}
+
+	tests := []struct {
+		name      string
+		filter    domain.VehicleFilter
+		mockSetup func(*mocks.MockVehicleRepository)
+	}{
+		{
+			name:   "без фильтров используется List",
+			filter: domain.VehicleFilter{},
+			mockSetup: func(m *mocks.MockVehicleRepository) {
+				m.On("List", mock.Anything, 50, 0).Return(vehicles, nil)
+			},
+		},
+		{
+			name:   "фильтр по владельцу и активности используется Search",
+			filter: domain.VehicleFilter{OwnerID: &ownerID, IsActive: &active},
+			mockSetup: func(m *mocks.MockVehicleRepository) {
+				m.On("Search", mock.Anything, domain.VehicleFilter{OwnerID: &ownerID, IsActive: &active}, 50, 0).Return(vehicles, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vehicleRepo := new(mocks.MockVehicleRepository)
+			tt.mockSetup(vehicleRepo)
+
+			svc := NewService(vehicleRepo, new(mocks.MockUserRepository), logger.NewNoop(), Config{})
+			result, err := svc.ListVehicles(context.Background(), tt.filter, 50, 0)
+
+			require.NoError(t, err)
+			assert.Equal(t, vehicles, result)
+			vehicleRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_SearchVehiclesByPlate(t *testing.T) {
+	vehicles := []*domain.Vehicle{{ID: uuid.New(), LicensePlate: "A123BC777", IsActive: true}}
+
+	tests := []struct {
+		name            string
+		plate           string
+		includeInactive bool
+		mockSetup       func(*mocks.MockVehicleRepository)
+		expected        []*domain.Vehicle
+		expectedErr     error
+	}{
+		{
+			name:  "часть номера нормализуется",
+			plate: " 123 bc ",
+			mockSetup: func(m *mocks.MockVehicleRepository) {
+				m.On("SearchByLicensePlate", mock.Anything, "123BC", 20, false).Return(vehicles, nil)
+			},
+			expected: vehicles,
+		},
+		{
+			name:            "с деактивированными автомобилями",
+			plate:           "123",
+			includeInactive: true,
+			mockSetup: func(m *mocks.MockVehicleRepository) {
+				m.On("SearchByLicensePlate", mock.Anything, "123", 20, true).Return(vehicles, nil)
+			},
+			expected: vehicles,
+		},
+		{
+			name:  "нет совпадений",
+			plate: "999",
+			mockSetup: func(m *mocks.MockVehicleRepository) {
+				m.On("SearchByLicensePlate", mock.Anything, "999", 20, false).Return([]*domain.Vehicle{}, nil)
+			},
+			expected: []*domain.Vehicle{},
+		},
+		{
+			name:        "пустой запрос",
+			plate:       "   ",
+			mockSetup:   func(m *mocks.MockVehicleRepository) {},
+			expectedErr: domain.ErrPlateQueryTooShort,
+		},
+		{
+			name:        "слишком короткий запрос",
+			plate:       "a1",
+			mockSetup:   func(m *mocks.MockVehicleRepository) {},
+			expectedErr: domain.ErrPlateQueryTooShort,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vehicleRepo := new(mocks.MockVehicleRepository)
+			tt.mockSetup(vehicleRepo)
+
+			svc := NewService(vehicleRepo, new(mocks.MockUserRepository), logger.NewNoop(), Config{})
+			result, err := svc.SearchVehiclesByPlate(context.Background(), tt.plate, 20, tt.includeInactive)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				vehicleRepo.AssertNotCalled(t, "SearchByLicensePlate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+			vehicleRepo.AssertExpectations(t)
+		})
+	}
+}
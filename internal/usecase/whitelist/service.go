@@ -0,0 +1,339 @@
+package whitelist
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/repository"
+	"github.com/google/uuid"
+)
+
+// CreateEntryRequest - запрос на добавление номера в белый список
+type CreateEntryRequest struct {
+	LicensePlate string     `json:"license_plate" validate:"required"`
+	Reason       string     `json:"reason" validate:"required"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	Zone         *string    `json:"zone,omitempty"`     // Зона ворот, где действует запись; пусто - на всех воротах
+	OwnerID      *uuid.UUID `json:"owner_id,omitempty"` // Владелец автомобиля-заглушки (иначе системный аккаунт)
+	AddedBy      uuid.UUID  `json:"-"`                  // Заполняется из claims
+}
+
+// UpdateEntryRequest - запрос на изменение записи белого списка
+// Номер не меняется: для другого номера запись удаляется и создается заново
+type UpdateEntryRequest struct {
+	Reason      *string    `json:"reason,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	ClearExpiry bool       `json:"clear_expiry,omitempty"` // Сделать запись бессрочной
+	IsActive    *bool      `json:"is_active,omitempty"`
+	Zone        *string    `json:"zone,omitempty"` // Новая зона записи; "" - действует на всех воротах
+}
+
+// MaxBulkEntries - наибольшее число строк в одном пакетном добавлении
+const MaxBulkEntries = 1000
+
+// maxReasonLength - длина колонки reason в таблице
+const maxReasonLength = 500
+
+// BulkEntryResult - итог добавления одной строки пакета
+type BulkEntryResult struct {
+	Row          int                    `json:"row"` // Номер строки в запросе, начиная с 1
+	LicensePlate string                 `json:"license_plate"`
+	Success      bool                   `json:"success"`
+	Entry        *domain.WhitelistEntry `json:"entry,omitempty"`
+	Error        string                 `json:"error,omitempty"`
+}
+
+// BulkResult - итог пакетного добавления
+type BulkResult struct {
+	Created int               `json:"created"`
+	Failed  int               `json:"failed"`
+	Results []BulkEntryResult `json:"results"`
+}
+
+// Config содержит настройки работы с белым списком
+type Config struct {
+	AutoCreateVehicle  bool      // Создавать автомобиль-заглушку для незарегистрированного номера
+	PlaceholderOwnerID uuid.UUID // Системный аккаунт-владелец заглушек по умолчанию
+
+	PlateCountry string // Страна формата номеров (ISO 3166-1 alpha-2, пусто - без проверки формата)
+}
+
+// Service содержит бизнес-логику управления белым списком
+type Service struct {
+	whitelistRepo repository.WhitelistRepository
+	vehicleRepo   repository.VehicleRepository
+	logger        logger.Logger
+	config        Config
+}
+
+// NewService создает новый экземпляр WhitelistService
+func NewService(
+	whitelistRepo repository.WhitelistRepository,
+	vehicleRepo repository.VehicleRepository,
+	logger logger.Logger,
+	config Config,
+) *Service {
+	return &Service{
+		whitelistRepo: whitelistRepo,
+		vehicleRepo:   vehicleRepo,
+		logger:        logger,
+		config:        config,
+	}
+}
+
+// CreateEntry добавляет номер в белый список
+func (s *Service) CreateEntry(ctx context.Context, req *CreateEntryRequest) (*domain.WhitelistEntry, error) {
+	s.logger.Info("Adding plate to whitelist", map[string]interface{}{
+		"license_plate": req.LicensePlate,
+		"added_by":      req.AddedBy,
+	})
+
+	entry := &domain.WhitelistEntry{
+		LicensePlate: req.LicensePlate,
+		Reason:       req.Reason,
+		AddedBy:      req.AddedBy,
+		AddedAt:      time.Now(),
+		ExpiresAt:    req.ExpiresAt,
+		Zone:         req.Zone,
+		IsActive:     true,
+	}
+
+	if err := entry.Validate(s.config.PlateCountry); err != nil {
+		return nil, err
+	}
+
+	if err := s.whitelistRepo.Create(ctx, entry); err != nil {
+		if err == domain.ErrWhitelistEntryAlreadyExists {
+			return nil, err
+		}
+		s.logger.Error("Failed to create whitelist entry", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, fmt.Errorf("failed to create whitelist entry: %w", err)
+	}
+
+	if s.config.AutoCreateVehicle {
+		ownerID := s.config.PlaceholderOwnerID
+		if req.OwnerID != nil {
+			ownerID = *req.OwnerID
+		}
+		s.ensurePlaceholderVehicle(ctx, entry.LicensePlate, ownerID)
+	}
+
+	return entry, nil
+}
+
+// CreateEntries добавляет пакет номеров в белый список одной транзакцией
+// Невалидные строки и дубликаты не прерывают пакет: итог возвращается по каждой строке
+func (s *Service) CreateEntries(ctx context.Context, reqs []CreateEntryRequest, addedBy uuid.UUID) (*BulkResult, error) {
+	result := &BulkResult{Results: make([]BulkEntryResult, len(reqs))}
+
+	entries := make([]*domain.WhitelistEntry, 0, len(reqs))
+	rows := make([]int, 0, len(reqs)) // Индекс строки запроса для каждой записи пакета
+	for i, req := range reqs {
+		result.Results[i] = BulkEntryResult{Row: i + 1, LicensePlate: req.LicensePlate}
+
+		entry := &domain.WhitelistEntry{
+			LicensePlate: req.LicensePlate,
+			Reason:       req.Reason,
+			AddedBy:      addedBy,
+			ExpiresAt:    req.ExpiresAt,
+			Zone:         req.Zone,
+			IsActive:     true,
+		}
+		if err := s.validateBulkEntry(entry); err != nil {
+			result.Results[i].Error = err.Error()
+			continue
+		}
+
+		result.Results[i].LicensePlate = entry.LicensePlate
+		entries = append(entries, entry)
+		rows = append(rows, i)
+	}
+
+	if len(entries) > 0 {
+		rowErrors, err := s.whitelistRepo.CreateBatch(ctx, entries)
+		if err != nil {
+			s.logger.Error("Failed to create whitelist entries", map[string]interface{}{
+				"entries": len(entries),
+				"error":   err.Error(),
+			})
+			return nil, fmt.Errorf("failed to create whitelist entries: %w", err)
+		}
+
+		for j, entry := range entries {
+			i := rows[j]
+			if rowErrors[j] != nil {
+				result.Results[i].Error = rowErrors[j].Error()
+				continue
+			}
+
+			result.Results[i].Success = true
+			result.Results[i].Entry = entry
+			result.Created++
+
+			if s.config.AutoCreateVehicle {
+				ownerID := s.config.PlaceholderOwnerID
+				if reqs[i].OwnerID != nil {
+					ownerID = *reqs[i].OwnerID
+				}
+				s.ensurePlaceholderVehicle(ctx, entry.LicensePlate, ownerID)
+			}
+		}
+	}
+
+	result.Failed = len(reqs) - result.Created
+
+	s.logger.Info("Whitelist bulk import finished", map[string]interface{}{
+		"added_by": addedBy,
+		"created":  result.Created,
+		"failed":   result.Failed,
+	})
+
+	return result, nil
+}
+
+// ListEntries возвращает записи белого списка с пагинацией
+func (s *Service) ListEntries(ctx context.Context, limit, offset int) ([]*domain.WhitelistEntry, error) {
+	return s.whitelistRepo.List(ctx, limit, offset)
+}
+
+// GetEntry возвращает запись белого списка по ID
+func (s *Service) GetEntry(ctx context.Context, id uuid.UUID) (*domain.WhitelistEntry, error) {
+	return s.whitelistRepo.GetByID(ctx, id)
+}
+
+// UpdateEntry изменяет причину, срок действия или активность записи
+func (s *Service) UpdateEntry(ctx context.Context, id uuid.UUID, req *UpdateEntryRequest) (*domain.WhitelistEntry, error) {
+	entry, err := s.whitelistRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Reason != nil {
+		entry.Reason = *req.Reason
+	}
+	if req.ClearExpiry {
+		entry.ExpiresAt = nil
+	} else if req.ExpiresAt != nil {
+		entry.ExpiresAt = req.ExpiresAt
+	}
+	if req.IsActive != nil {
+		entry.IsActive = *req.IsActive
+	}
+	if req.Zone != nil {
+		entry.Zone = req.Zone
+	}
+
+	// Номер при обновлении не меняется, поэтому формат страны здесь не проверяется:
+	// иначе записи со старыми или иностранными номерами нельзя было бы даже деактивировать
+	if err := entry.Validate(""); err != nil {
+		return nil, err
+	}
+
+	if err := s.whitelistRepo.Update(ctx, entry); err != nil {
+		if err == domain.ErrWhitelistEntryNotFound {
+			return nil, err
+		}
+		s.logger.Error("Failed to update whitelist entry", map[string]interface{}{
+			"entry_id": id,
+			"error":    err.Error(),
+		})
+		return nil, fmt.Errorf("failed to update whitelist entry: %w", err)
+	}
+
+	s.logger.Info("Whitelist entry updated", map[string]interface{}{
+		"entry_id":      id,
+		"license_plate": entry.LicensePlate,
+	})
+
+	return entry, nil
+}
+
+// DeleteEntry удаляет запись из белого списка
+func (s *Service) DeleteEntry(ctx context.Context, id uuid.UUID) error {
+	if err := s.whitelistRepo.Delete(ctx, id); err != nil {
+		if err == domain.ErrWhitelistEntryNotFound {
+			return err
+		}
+		s.logger.Error("Failed to delete whitelist entry", map[string]interface{}{
+			"entry_id": id,
+			"error":    err.Error(),
+		})
+		return fmt.Errorf("failed to delete whitelist entry: %w", err)
+	}
+
+	s.logger.Info("Whitelist entry deleted", map[string]interface{}{
+		"entry_id": id,
+	})
+
+	return nil
+}
+
+// ensurePlaceholderVehicle создает минимальную запись автомобиля, чтобы отчеты по проездам
+// связывались с владельцем. Ошибки не прерывают добавление в белый список
+func (s *Service) ensurePlaceholderVehicle(ctx context.Context, licensePlate string, ownerID uuid.UUID) {
+	if ownerID == uuid.Nil {
+		s.logger.Warn("Placeholder vehicle owner is not configured", map[string]interface{}{
+			"license_plate": licensePlate,
+		})
+		return
+	}
+
+	_, err := s.vehicleRepo.GetByLicensePlate(ctx, licensePlate)
+	if err == nil {
+		return
+	}
+	if err != domain.ErrVehicleNotFound {
+		s.logger.Error("Failed to check vehicle for whitelist entry", map[string]interface{}{
+			"license_plate": licensePlate,
+			"error":         err.Error(),
+		})
+		return
+	}
+
+	placeholder := &domain.Vehicle{
+		OwnerID:      ownerID,
+		LicensePlate: licensePlate,
+		VehicleType:  domain.VehicleTypeOther,
+		IsActive:     true,
+	}
+
+	if err := placeholder.Validate(s.config.PlateCountry); err != nil {
+		s.logger.Warn("Cannot create placeholder vehicle", map[string]interface{}{
+			"license_plate": licensePlate,
+			"error":         err.Error(),
+		})
+		return
+	}
+
+	if err := s.vehicleRepo.Create(ctx, placeholder); err != nil {
+		s.logger.Error("Failed to create placeholder vehicle", map[string]interface{}{
+			"license_plate": licensePlate,
+			"error":         err.Error(),
+		})
+		return
+	}
+
+	s.logger.Info("Placeholder vehicle created for whitelisted plate", map[string]interface{}{
+		"vehicle_id":    placeholder.ID,
+		"license_plate": licensePlate,
+		"owner_id":      ownerID,
+	})
+}
+
+// validateBulkEntry проверяет строку пакета заранее: номер, не проходящий ограничения таблицы,
+// прервал бы всю транзакцию
+func (s *Service) validateBulkEntry(entry *domain.WhitelistEntry) error {
+	if err := entry.Validate(s.config.PlateCountry); err != nil {
+		return err
+	}
+	if utf8.RuneCountInString(entry.Reason) > maxReasonLength {
+		return domain.ErrInvalidWhitelistData
+	}
+	return nil
+}
@@ -0,0 +1,292 @@
+package whitelist
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/frontandrew/gate/internal/domain"
+	"github.com/frontandrew/gate/internal/pkg/logger"
+	"github.com/frontandrew/gate/internal/repository/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_CreateEntry_PlaceholderVehicle(t *testing.T) {
+	adminID := uuid.New()
+	systemOwnerID := uuid.New()
+
+	tests := []struct {
+		name      string
+		config    Config
+		mockSetup func(*mocks.MockVehicleRepository)
+		check     func(*testing.T, *mocks.MockVehicleRepository)
+	}{
+		{
+			name:   "флаг включен - создается заглушка на системный аккаунт",
+			config: Config{AutoCreateVehicle: true, PlaceholderOwnerID: systemOwnerID},
+			mockSetup: func(m *mocks.MockVehicleRepository) {
+				m.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(nil, domain.ErrVehicleNotFound)
+				m.On("Create", mock.Anything, mock.MatchedBy(func(v *domain.Vehicle) bool {
+					return v.OwnerID == systemOwnerID && v.LicensePlate == "A123BC777" && v.IsActive
+				})).Return(nil)
+			},
+		},
+		{
+			name:   "флаг включен, автомобиль уже зарегистрирован",
+			config: Config{AutoCreateVehicle: true, PlaceholderOwnerID: systemOwnerID},
+			mockSetup: func(m *mocks.MockVehicleRepository) {
+				m.On("GetByLicensePlate", mock.Anything, "A123BC777").
+					Return(&domain.Vehicle{ID: uuid.New(), LicensePlate: "A123BC777"}, nil)
+			},
+			check: func(t *testing.T, m *mocks.MockVehicleRepository) {
+				m.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name:      "флаг выключен - автомобиль не создается",
+			config:    Config{AutoCreateVehicle: false, PlaceholderOwnerID: systemOwnerID},
+			mockSetup: func(m *mocks.MockVehicleRepository) {},
+			check: func(t *testing.T, m *mocks.MockVehicleRepository) {
+				m.AssertNotCalled(t, "GetByLicensePlate", mock.Anything, mock.Anything)
+				m.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			whitelistRepo := new(mocks.MockWhitelistRepository)
+			vehicleRepo := new(mocks.MockVehicleRepository)
+			whitelistRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.WhitelistEntry")).Return(nil)
+			tt.mockSetup(vehicleRepo)
+
+			svc := NewService(whitelistRepo, vehicleRepo, logger.NewNoop(), tt.config)
+			entry, err := svc.CreateEntry(context.Background(), &CreateEntryRequest{
+				LicensePlate: "a123 bc777",
+				Reason:       "Скорая помощь",
+				AddedBy:      adminID,
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, "A123BC777", entry.LicensePlate)
+			assert.True(t, entry.IsActive)
+
+			if tt.check != nil {
+				tt.check(t, vehicleRepo)
+			}
+
+			whitelistRepo.AssertExpectations(t)
+			vehicleRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_CreateEntry_ExpiresAt(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	future := time.Now().Add(24 * time.Hour).In(moscow)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name        string
+		expiresAt   *time.Time
+		expectedErr error
+		check       func(*testing.T, *domain.WhitelistEntry)
+	}{
+		{
+			name:      "будущий срок сохраняется в UTC",
+			expiresAt: &future,
+			check: func(t *testing.T, entry *domain.WhitelistEntry) {
+				require.NotNil(t, entry.ExpiresAt)
+				assert.Equal(t, time.UTC, entry.ExpiresAt.Location())
+				assert.True(t, entry.ExpiresAt.Equal(future))
+			},
+		},
+		{
+			name:        "прошедший срок отклоняется",
+			expiresAt:   &past,
+			expectedErr: domain.ErrExpiryInPast,
+		},
+		{
+			name: "без срока - бессрочная запись",
+			check: func(t *testing.T, entry *domain.WhitelistEntry) {
+				assert.Nil(t, entry.ExpiresAt)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			whitelistRepo := new(mocks.MockWhitelistRepository)
+			if tt.expectedErr == nil {
+				whitelistRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.WhitelistEntry")).Return(nil)
+			}
+
+			svc := NewService(whitelistRepo, new(mocks.MockVehicleRepository), logger.NewNoop(), Config{})
+			entry, err := svc.CreateEntry(context.Background(), &CreateEntryRequest{
+				LicensePlate: "A123BC777",
+				Reason:       "Скорая помощь",
+				ExpiresAt:    tt.expiresAt,
+				AddedBy:      uuid.New(),
+			})
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, entry)
+			} else {
+				require.NoError(t, err)
+				tt.check(t, entry)
+			}
+
+			whitelistRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// Дубликат номера не оборачивается, чтобы обработчик ответил 409, а не 500
+func TestService_CreateEntry_Duplicate(t *testing.T) {
+	whitelistRepo := new(mocks.MockWhitelistRepository)
+	whitelistRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.WhitelistEntry")).Return(domain.ErrWhitelistEntryAlreadyExists)
+
+	svc := NewService(whitelistRepo, new(mocks.MockVehicleRepository), logger.NewNoop(), Config{})
+	entry, err := svc.CreateEntry(context.Background(), &CreateEntryRequest{
+		LicensePlate: "A123BC777",
+		Reason:       "Скорая помощь",
+		AddedBy:      uuid.New(),
+	})
+
+	assert.Equal(t, domain.ErrWhitelistEntryAlreadyExists, err)
+	assert.Nil(t, entry)
+	whitelistRepo.AssertExpectations(t)
+}
+
+func TestService_UpdateEntry(t *testing.T) {
+	entryID := uuid.New()
+	addedBy := uuid.New()
+	future := time.Now().Add(24 * time.Hour)
+	past := time.Now().Add(-time.Hour)
+	inactive := false
+	reason := "Пожарные"
+
+	tests := []struct {
+		name        string
+		req         *UpdateEntryRequest
+		expectedErr error
+		check       func(*testing.T, *domain.WhitelistEntry)
+	}{
+		{
+			name: "меняются только переданные поля",
+			req:  &UpdateEntryRequest{Reason: &reason, IsActive: &inactive},
+			check: func(t *testing.T, entry *domain.WhitelistEntry) {
+				assert.Equal(t, "Пожарные", entry.Reason)
+				assert.False(t, entry.IsActive)
+				assert.Equal(t, addedBy, entry.AddedBy)
+				require.NotNil(t, entry.ExpiresAt)
+			},
+		},
+		{
+			name: "clear_expiry делает запись бессрочной",
+			req:  &UpdateEntryRequest{ClearExpiry: true},
+			check: func(t *testing.T, entry *domain.WhitelistEntry) {
+				assert.Nil(t, entry.ExpiresAt)
+			},
+		},
+		{
+			name:        "прошедший срок отклоняется",
+			req:         &UpdateEntryRequest{ExpiresAt: &past},
+			expectedErr: domain.ErrExpiryInPast,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expiresAt := future
+			whitelistRepo := new(mocks.MockWhitelistRepository)
+			whitelistRepo.On("GetByID", mock.Anything, entryID).Return(&domain.WhitelistEntry{
+				ID:           entryID,
+				LicensePlate: "A123BC777",
+				Reason:       "Скорая помощь",
+				AddedBy:      addedBy,
+				ExpiresAt:    &expiresAt,
+				IsActive:     true,
+			}, nil)
+			if tt.expectedErr == nil {
+				whitelistRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.WhitelistEntry")).Return(nil)
+			}
+
+			svc := NewService(whitelistRepo, new(mocks.MockVehicleRepository), logger.NewNoop(), Config{})
+			entry, err := svc.UpdateEntry(context.Background(), entryID, tt.req)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				whitelistRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, entry)
+			whitelistRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_UpdateEntry_LegacyPlate(t *testing.T) {
+	entryID := uuid.New()
+	inactive := false
+
+	whitelistRepo := new(mocks.MockWhitelistRepository)
+	whitelistRepo.On("GetByID", mock.Anything, entryID).
+		Return(&domain.WhitelistEntry{ID: entryID, LicensePlate: "B456CD777", Reason: "Скорая помощь", AddedBy: uuid.New(), IsActive: true}, nil)
+	whitelistRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.WhitelistEntry")).Return(nil)
+
+	// Номер не соответствует формату RU, но при обновлении он не меняется
+	svc := NewService(whitelistRepo, new(mocks.MockVehicleRepository), logger.NewNoop(), Config{PlateCountry: "RU"})
+	entry, err := svc.UpdateEntry(context.Background(), entryID, &UpdateEntryRequest{IsActive: &inactive})
+
+	require.NoError(t, err)
+	assert.False(t, entry.IsActive)
+	whitelistRepo.AssertExpectations(t)
+}
+
+func TestService_CreateEntries(t *testing.T) {
+	adminID := uuid.New()
+	systemOwnerID := uuid.New()
+	past := time.Now().Add(-time.Hour)
+
+	whitelistRepo := new(mocks.MockWhitelistRepository)
+	vehicleRepo := new(mocks.MockVehicleRepository)
+
+	whitelistRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(entries []*domain.WhitelistEntry) bool {
+		return len(entries) == 2 &&
+			entries[0].LicensePlate == "A123BC777" && entries[0].AddedBy == adminID &&
+			entries[1].LicensePlate == "B456CE777"
+	})).Return([]error{nil, domain.ErrWhitelistEntryAlreadyExists}, nil)
+	// Заглушка создается только для добавленного номера
+	vehicleRepo.On("GetByLicensePlate", mock.Anything, "A123BC777").Return(nil, domain.ErrVehicleNotFound)
+	vehicleRepo.On("Create", mock.Anything, mock.MatchedBy(func(v *domain.Vehicle) bool {
+		return v.LicensePlate == "A123BC777" && v.OwnerID == systemOwnerID
+	})).Return(nil)
+
+	svc := NewService(whitelistRepo, vehicleRepo, logger.NewNoop(), Config{AutoCreateVehicle: true, PlaceholderOwnerID: systemOwnerID})
+	result, err := svc.CreateEntries(context.Background(), []CreateEntryRequest{
+		{LicensePlate: "a123 bc777", Reason: "Подрядчик"},
+		{LicensePlate: "B456CE777", Reason: "Уже в списке"},
+		{LicensePlate: "C789KM777", Reason: "Истекший срок", ExpiresAt: &past},
+		{LicensePlate: "ОЧЕНЬДЛИННЫЙНОМЕР1234567", Reason: "Слишком длинный"},
+	}, adminID)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 3, result.Failed)
+	require.Len(t, result.Results, 4)
+
+	assert.True(t, result.Results[0].Success)
+	require.NotNil(t, result.Results[0].Entry)
+	assert.Equal(t, "A123BC777", result.Results[0].Entry.LicensePlate)
+	assert.Equal(t, domain.ErrWhitelistEntryAlreadyExists.Error(), result.Results[1].Error)
+	assert.Equal(t, domain.ErrExpiryInPast.Error(), result.Results[2].Error)
+	assert.Equal(t, domain.ErrInvalidLicensePlate.Error(), result.Results[3].Error)
+
+	vehicleRepo.AssertNumberOfCalls(t, "Create", 1)
+}
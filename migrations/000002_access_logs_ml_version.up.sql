@@ -0,0 +1,4 @@
+-- Версия ML модели, принявшей решение, для корреляции после обновления модели
+ALTER TABLE access_logs ADD COLUMN IF NOT EXISTS ml_model_version VARCHAR(100);
+
+COMMENT ON COLUMN access_logs.ml_model_version IS 'Версия ML модели/сервиса, распознавшей номер';
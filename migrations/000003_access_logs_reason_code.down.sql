@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_access_logs_reason_code_timestamp;
+ALTER TABLE access_logs DROP COLUMN IF EXISTS reason_code;
@@ -0,0 +1,6 @@
+-- Машиночитаемый код причины решения для фильтрации и дашбордов
+ALTER TABLE access_logs ADD COLUMN IF NOT EXISTS reason_code VARCHAR(50);
+
+CREATE INDEX IF NOT EXISTS idx_access_logs_reason_code_timestamp ON access_logs(reason_code, timestamp DESC);
+
+COMMENT ON COLUMN access_logs.reason_code IS 'Код причины решения (WHITELISTED, BLACKLISTED, NO_PASS, ...)';
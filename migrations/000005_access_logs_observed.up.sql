@@ -0,0 +1,4 @@
+-- Решения ворот в режиме наблюдения: вычислены и записаны, но шлагбаум не управлялся
+ALTER TABLE access_logs ADD COLUMN IF NOT EXISTS observed BOOLEAN NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN access_logs.observed IS 'Ворота в режиме наблюдения: решение не передавалось шлагбауму';
@@ -0,0 +1 @@
+ALTER TABLE access_logs DROP COLUMN IF EXISTS direction_inferred;
@@ -0,0 +1,4 @@
+-- Ворота с одной петлей не сообщают направление: оно выводится из предыдущего проезда номера
+ALTER TABLE access_logs ADD COLUMN IF NOT EXISTS direction_inferred BOOLEAN NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN access_logs.direction_inferred IS 'Направление выведено из предыдущего разрешенного проезда, а не получено от ворот';
@@ -0,0 +1,5 @@
+ALTER TABLE passes DROP CONSTRAINT IF EXISTS passes_allowed_weekdays_check;
+ALTER TABLE passes DROP CONSTRAINT IF EXISTS passes_allowed_time_check;
+ALTER TABLE passes DROP COLUMN IF EXISTS allowed_weekdays;
+ALTER TABLE passes DROP COLUMN IF EXISTS allowed_time_end;
+ALTER TABLE passes DROP COLUMN IF EXISTS allowed_time_start;
@@ -0,0 +1,13 @@
+-- Расписание действия пропуска: окно времени суток и дни недели
+ALTER TABLE passes ADD COLUMN IF NOT EXISTS allowed_time_start TIME;
+ALTER TABLE passes ADD COLUMN IF NOT EXISTS allowed_time_end TIME;
+ALTER TABLE passes ADD COLUMN IF NOT EXISTS allowed_weekdays SMALLINT[];
+
+ALTER TABLE passes ADD CONSTRAINT passes_allowed_time_check
+    CHECK ((allowed_time_start IS NULL) = (allowed_time_end IS NULL));
+ALTER TABLE passes ADD CONSTRAINT passes_allowed_weekdays_check
+    CHECK (allowed_weekdays IS NULL OR allowed_weekdays <@ ARRAY[0, 1, 2, 3, 4, 5, 6]::SMALLINT[]);
+
+COMMENT ON COLUMN passes.allowed_time_start IS 'Начало разрешенного окна времени суток (NULL - круглосуточно); начало позже конца - окно через полночь';
+COMMENT ON COLUMN passes.allowed_time_end IS 'Конец разрешенного окна времени суток (не включительно)';
+COMMENT ON COLUMN passes.allowed_weekdays IS 'Разрешенные дни недели: 0 - воскресенье, 6 - суббота (NULL - любой день)';
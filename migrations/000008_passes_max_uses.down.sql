@@ -0,0 +1,3 @@
+ALTER TABLE passes DROP CONSTRAINT IF EXISTS passes_max_uses_check;
+ALTER TABLE passes DROP COLUMN IF EXISTS uses_count;
+ALTER TABLE passes DROP COLUMN IF EXISTS max_uses;
@@ -0,0 +1,8 @@
+-- Пропуска с ограниченным числом проездов (разовые)
+ALTER TABLE passes ADD COLUMN IF NOT EXISTS max_uses INTEGER;
+ALTER TABLE passes ADD COLUMN IF NOT EXISTS uses_count INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE passes ADD CONSTRAINT passes_max_uses_check CHECK (max_uses IS NULL OR max_uses > 0);
+
+COMMENT ON COLUMN passes.max_uses IS 'Лимит разрешенных проездов (NULL - без ограничения, 1 - разовый пропуск)';
+COMMENT ON COLUMN passes.uses_count IS 'Количество учтенных разрешенных проездов';
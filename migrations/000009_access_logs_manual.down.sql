@@ -0,0 +1,3 @@
+ALTER TABLE access_logs DROP COLUMN IF EXISTS manual_reason;
+ALTER TABLE access_logs DROP COLUMN IF EXISTS operator_id;
+ALTER TABLE access_logs DROP COLUMN IF EXISTS manual;
@@ -0,0 +1,8 @@
+-- Ручные решения охраны: номер подтвержден визуально, распознавание не выполнялось
+ALTER TABLE access_logs ADD COLUMN IF NOT EXISTS manual BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE access_logs ADD COLUMN IF NOT EXISTS operator_id UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE access_logs ADD COLUMN IF NOT EXISTS manual_reason TEXT;
+
+COMMENT ON COLUMN access_logs.manual IS 'Номер введен охранником вручную, без распознавания';
+COMMENT ON COLUMN access_logs.operator_id IS 'Охранник или администратор, выполнивший ручную проверку';
+COMMENT ON COLUMN access_logs.manual_reason IS 'Причина ручной проверки со слов охранника';
@@ -0,0 +1,24 @@
+-- Без зон номер снова уникален в таблице: из записей одного номера остается запись без зоны,
+-- а если ее нет - самая ранняя
+DELETE FROM whitelist w
+USING whitelist d
+WHERE w.license_plate = d.license_plate
+  AND w.id <> d.id
+  AND w.zone IS NOT NULL
+  AND (d.zone IS NULL OR d.added_at < w.added_at OR (d.added_at = w.added_at AND d.id < w.id));
+
+DELETE FROM blacklist b
+USING blacklist d
+WHERE b.license_plate = d.license_plate
+  AND b.id <> d.id
+  AND b.zone IS NOT NULL
+  AND (d.zone IS NULL OR d.added_at < b.added_at OR (d.added_at = b.added_at AND d.id < b.id));
+
+DROP INDEX IF EXISTS idx_blacklist_license_plate_zone;
+DROP INDEX IF EXISTS idx_whitelist_license_plate_zone;
+ALTER TABLE blacklist ADD CONSTRAINT blacklist_license_plate_key UNIQUE (license_plate);
+ALTER TABLE whitelist ADD CONSTRAINT whitelist_license_plate_key UNIQUE (license_plate);
+
+ALTER TABLE passes DROP COLUMN IF EXISTS zone;
+ALTER TABLE blacklist DROP COLUMN IF EXISTS zone;
+ALTER TABLE whitelist DROP COLUMN IF EXISTS zone;
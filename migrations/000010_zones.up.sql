@@ -0,0 +1,16 @@
+-- Зоны площадки: записи списков и пропуска могут действовать только на воротах одной зоны
+-- NULL - запись действует на всех воротах (как до появления зон)
+ALTER TABLE whitelist ADD COLUMN IF NOT EXISTS zone VARCHAR(64);
+ALTER TABLE blacklist ADD COLUMN IF NOT EXISTS zone VARCHAR(64);
+ALTER TABLE passes ADD COLUMN IF NOT EXISTS zone VARCHAR(64);
+
+-- Номер уникален в пределах зоны: один номер может быть в списке для разных зон
+-- (запись без зоны - отдельное значение). ON CONFLICT в репозиториях ссылается на эти индексы
+ALTER TABLE whitelist DROP CONSTRAINT IF EXISTS whitelist_license_plate_key;
+ALTER TABLE blacklist DROP CONSTRAINT IF EXISTS blacklist_license_plate_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_whitelist_license_plate_zone ON whitelist (license_plate, (COALESCE(zone, '')));
+CREATE UNIQUE INDEX IF NOT EXISTS idx_blacklist_license_plate_zone ON blacklist (license_plate, (COALESCE(zone, '')));
+
+COMMENT ON COLUMN whitelist.zone IS 'Зона ворот, где действует запись (NULL - все ворота)';
+COMMENT ON COLUMN blacklist.zone IS 'Зона ворот, где действует запись (NULL - все ворота)';
+COMMENT ON COLUMN passes.zone IS 'Зона ворот, где действует пропуск (NULL - все ворота)';
@@ -0,0 +1,2 @@
+ALTER TABLE passes DROP COLUMN IF EXISTS extended_by;
+ALTER TABLE passes DROP COLUMN IF EXISTS extended_at;
@@ -0,0 +1,6 @@
+-- Продление временного пропуска: кто и когда последним продлил срок действия
+ALTER TABLE passes ADD COLUMN IF NOT EXISTS extended_at TIMESTAMP;
+ALTER TABLE passes ADD COLUMN IF NOT EXISTS extended_by UUID REFERENCES users(id);
+
+COMMENT ON COLUMN passes.extended_at IS 'Время последнего продления valid_until (NULL - не продлевался)';
+COMMENT ON COLUMN passes.extended_by IS 'Пользователь, последним продливший пропуск';
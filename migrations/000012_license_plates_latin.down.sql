@@ -0,0 +1,2 @@
+-- Исходная раскладка номеров не сохранялась: латинская запись остается, откатывать нечего
+SELECT 1;
@@ -0,0 +1,49 @@
+-- Номера хранятся латиницей: кириллические буквы, совпадающие по начертанию, заменяются латинскими
+-- Если после замены номер совпадает с уже сохраненным (в списках - в той же зоне), миграция прерывается со списком таких номеров:
+-- пропущенные строки стали бы недоступны для поиска по нормализованному номеру. Дубликаты объединяются
+-- вручную (POST /vehicles/merge для автомобилей, удаление лишних записей списков), затем миграция повторяется
+DO $$
+DECLARE
+    conflicts text;
+BEGIN
+    SELECT string_agg(format('%s %s', t.tbl, t.plates), '; ' ORDER BY t.tbl, t.plates)
+    INTO conflicts
+    FROM (
+        SELECT 'vehicles' AS tbl, string_agg(license_plate, ', ' ORDER BY license_plate) AS plates
+        FROM vehicles
+        GROUP BY translate(license_plate, 'АВЕКМНОРСТУХ', 'ABEKMHOPCTYX')
+        HAVING count(*) > 1
+        UNION ALL
+        SELECT 'whitelist', string_agg(license_plate, ', ' ORDER BY license_plate)
+        FROM whitelist
+        GROUP BY translate(license_plate, 'АВЕКМНОРСТУХ', 'ABEKMHOPCTYX'), COALESCE(zone, '')
+        HAVING count(*) > 1
+        UNION ALL
+        SELECT 'blacklist', string_agg(license_plate, ', ' ORDER BY license_plate)
+        FROM blacklist
+        GROUP BY translate(license_plate, 'АВЕКМНОРСТУХ', 'ABEKMHOPCTYX'), COALESCE(zone, '')
+        HAVING count(*) > 1
+    ) t;
+
+    IF conflicts IS NOT NULL THEN
+        RAISE EXCEPTION 'license plates duplicate each other after Cyrillic to Latin conversion: %', conflicts
+            USING HINT = 'merge duplicate vehicles via POST /vehicles/merge and remove duplicate whitelist/blacklist entries, then rerun the migration';
+    END IF;
+END $$;
+
+UPDATE vehicles
+SET license_plate = translate(license_plate, 'АВЕКМНОРСТУХ', 'ABEKMHOPCTYX')
+WHERE license_plate ~ '[АВЕКМНОРСТУХ]';
+
+UPDATE whitelist
+SET license_plate = translate(license_plate, 'АВЕКМНОРСТУХ', 'ABEKMHOPCTYX')
+WHERE license_plate ~ '[АВЕКМНОРСТУХ]';
+
+UPDATE blacklist
+SET license_plate = translate(license_plate, 'АВЕКМНОРСТУХ', 'ABEKMHOPCTYX')
+WHERE license_plate ~ '[АВЕКМНОРСТУХ]';
+
+-- Журнал проездов ищется по номеру (anti-passback, история): приводится к той же записи
+UPDATE access_logs
+SET license_plate = translate(license_plate, 'АВЕКМНОРСТУХ', 'ABEKMHOPCTYX')
+WHERE license_plate ~ '[АВЕКМНОРСТУХ]';
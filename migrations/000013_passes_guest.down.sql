@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_passes_guest_created_by;
+ALTER TABLE passes DROP COLUMN IF EXISTS is_guest;
@@ -0,0 +1,8 @@
+-- Гостевые пропуска отмечаются явно: суточный лимит жителя считает только их,
+-- а проверка доступа принимает их для автомобиля гостя независимо от его владельца
+-- Ранее выданные гостевые пропуска не отличить от обычных временных, они остаются is_guest = false
+ALTER TABLE passes ADD COLUMN IF NOT EXISTS is_guest BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS idx_passes_guest_created_by ON passes(created_by, created_at) WHERE is_guest;
+
+COMMENT ON COLUMN passes.is_guest IS 'Гостевой пропуск, выданный жителем посетителю';